@@ -0,0 +1,260 @@
+package via
+
+import (
+	"bytes"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/go-via/via/h"
+)
+
+// a11yFinding is one accessibility issue found in a rendered page.
+type a11yFinding struct {
+	issue string // e.g. "img without alt"
+	elem  string // #id, or the opening tag when the element has none
+}
+
+// auditPage renders body and logs and records each a11y finding once per
+// route, so a page rendered a thousand times warns once. WithA11yAudit
+// only; a render error is ignored — the real render reports it.
+func (a *App) auditPage(ctx *Ctx, body h.H) {
+	var buf bytes.Buffer
+	if body == nil || body.Render(&buf) != nil {
+		return
+	}
+	for _, f := range auditA11y(buf.String()) {
+		key := ctx.desc.route + "\x00" + f.issue + "\x00" + f.elem
+		a.a11yMu.Lock()
+		_, seen := a.a11ySeen[key]
+		if !seen {
+			if a.a11ySeen == nil {
+				a.a11ySeen = map[string]struct{}{}
+			}
+			a.a11ySeen[key] = struct{}{}
+			a.a11yFound = append(a.a11yFound, f.issue+" on "+ctx.desc.route+": "+f.elem)
+		}
+		a.a11yMu.Unlock()
+		if !seen {
+			a.logWarn(ctx, "a11y: %s on %s: %s", f.issue, ctx.desc.route, f.elem)
+		}
+	}
+}
+
+// A11yFindings returns what WithA11yAudit has found so far, one entry per
+// route and finding in the order first seen, e.g. "img without alt on /:
+// #hero". Tests assert on it, or through vt.AssertAccessible; empty with
+// the audit off.
+func (a *App) A11yFindings() []string {
+	a.a11yMu.Lock()
+	defer a.a11yMu.Unlock()
+	return slices.Clone(a.a11yFound)
+}
+
+// lowInfoLinkText is link text that says nothing out of context — screen
+// reader users often navigate by a list of links alone.
+var lowInfoLinkText = map[string]bool{
+	"click here": true, "here": true, "click": true, "more": true,
+	"read more": true, "learn more": true, "link": true, "this": true,
+}
+
+// voidTags never have children or a closing tag.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// a11yFrame is an open element while auditing.
+type a11yFrame struct {
+	tag   string
+	attrs map[string]string
+	open  string          // opening tag source, for the report
+	text  strings.Builder // descendant text, for button/a names
+	named bool            // a descendant img with alt text names it
+}
+
+// auditA11y walks doc with scanHTML and reports images without alt text,
+// buttons and links without an accessible name, low-information link
+// text, and form controls without a label. It is a lint, not a validator: it knows
+// nothing of CSS, so visually hidden text still counts as a name.
+func auditA11y(doc string) []a11yFinding {
+	var (
+		out      []a11yFinding
+		stack    []*a11yFrame
+		controls []*a11yFrame // unlabelled-so-far inputs/selects/textareas
+		labelFor = map[string]bool{}
+	)
+	report := func(f *a11yFrame, issue string) {
+		out = append(out, a11yFinding{issue: issue, elem: elemRef(f)})
+	}
+	inside := func(tag string) bool {
+		for _, f := range stack {
+			if f.tag == tag {
+				return true
+			}
+		}
+		return false
+	}
+	closeFrame := func(f *a11yFrame) {
+		if hasName(f.attrs) || f.named {
+			return
+		}
+		text := strings.ToLower(strings.Join(strings.Fields(html.UnescapeString(f.text.String())), " "))
+		switch {
+		case text == "" && f.tag == "button":
+			report(f, "button without accessible name")
+		case text == "" && f.tag == "a":
+			report(f, "link without accessible name")
+		case f.tag == "a" && lowInfoLinkText[text]:
+			report(f, "link text "+`"`+text+`"`+" says nothing out of context")
+		}
+	}
+
+	scanHTML(doc, func(t htmlToken) {
+		switch t.kind {
+		case tokenText:
+			for _, f := range stack {
+				if f.tag == "button" || f.tag == "a" {
+					f.text.WriteString(t.raw)
+				}
+			}
+			return
+		case tokenClose:
+			for n := len(stack) - 1; n >= 0; n-- {
+				if stack[n].tag == t.tag {
+					for _, f := range stack[n:] {
+						closeFrame(f)
+					}
+					stack = stack[:n]
+					break
+				}
+			}
+			return
+		}
+		f := &a11yFrame{tag: t.tag, attrs: t.attrs, open: t.raw}
+		switch t.tag {
+		case "img":
+			alt, ok := t.attrs["alt"]
+			if !ok {
+				report(f, "img without alt")
+			} else if strings.TrimSpace(alt) != "" {
+				for _, p := range stack {
+					p.named = true
+				}
+			}
+		case "label":
+			if id := t.attrs["for"]; id != "" {
+				labelFor[id] = true
+			}
+		case "input", "select", "textarea":
+			switch strings.ToLower(t.attrs["type"]) {
+			case "hidden", "submit", "button", "reset", "image":
+			default:
+				if !hasName(t.attrs) && !inside("label") {
+					controls = append(controls, f)
+				}
+			}
+		}
+		if !t.leaf {
+			stack = append(stack, f)
+		}
+	})
+	for _, f := range stack {
+		closeFrame(f)
+	}
+	for _, c := range controls {
+		if id := c.attrs["id"]; id == "" || !labelFor[id] {
+			report(c, c.tag+" without label")
+		}
+	}
+	return out
+}
+
+// hasName reports whether attrs name the element without its content.
+func hasName(attrs map[string]string) bool {
+	for _, k := range [...]string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(attrs[k]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// elemRef identifies an element in a finding: #id when it has one, else
+// its opening tag, shortened.
+func elemRef(f *a11yFrame) string {
+	if id := f.attrs["id"]; id != "" {
+		return "#" + id
+	}
+	if len(f.open) > 80 {
+		return f.open[:77] + "..."
+	}
+	return f.open
+}
+
+// tagEnd returns the index of the '>' closing the tag opened at i,
+// skipping any '>' inside a quoted attribute value; -1 if unterminated.
+func tagEnd(doc string, i int) int {
+	var quote byte
+	for j := i + 1; j < len(doc); j++ {
+		switch c := doc[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j
+		}
+	}
+	return -1
+}
+
+// parseTag splits an opening tag into its lowercased name and attributes
+// (values unescaped; a bare attribute maps to "").
+func parseTag(raw string) (string, map[string]string) {
+	s := strings.TrimSuffix(strings.TrimSuffix(raw[1:], ">"), "/")
+	n := strings.IndexAny(s, " \t\n\r\f")
+	if n < 0 {
+		return strings.ToLower(s), map[string]string{}
+	}
+	tag := strings.ToLower(s[:n])
+	attrs := map[string]string{}
+	s = s[n:]
+	for {
+		s = strings.TrimLeft(s, " \t\n\r\f")
+		if s == "" {
+			return tag, attrs
+		}
+		end := strings.IndexAny(s, " \t\n\r\f=")
+		if end < 0 {
+			attrs[strings.ToLower(s)] = ""
+			return tag, attrs
+		}
+		name := strings.ToLower(s[:end])
+		s = strings.TrimLeft(s[end:], " \t\n\r\f")
+		if !strings.HasPrefix(s, "=") {
+			attrs[name] = ""
+			continue
+		}
+		s = strings.TrimLeft(s[1:], " \t\n\r\f")
+		var val string
+		if s != "" && (s[0] == '"' || s[0] == '\'') {
+			q := strings.IndexByte(s[1:], s[0])
+			if q < 0 {
+				val, s = s[1:], ""
+			} else {
+				val, s = s[1:q+1], s[q+2:]
+			}
+		} else {
+			v := strings.IndexAny(s, " \t\n\r\f")
+			if v < 0 {
+				v = len(s)
+			}
+			val, s = s[:v], s[v:]
+		}
+		attrs[name] = html.UnescapeString(val)
+	}
+}
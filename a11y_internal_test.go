@@ -0,0 +1,45 @@
+package via
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditA11y_flagsCommonMistakes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		html string
+		want []a11yFinding
+	}{
+		{"img without alt", `<img id="logo" src="/l.png">`,
+			[]a11yFinding{{"img without alt", "#logo"}}},
+		{"decorative img with empty alt", `<img src="/l.png" alt="">`, nil},
+		{"icon-only button", `<button id="close"><svg></svg></button>`,
+			[]a11yFinding{{"button without accessible name", "#close"}}},
+		{"button named by aria-label", `<button aria-label="Close"><svg></svg></button>`, nil},
+		{"button named by img alt", `<button><img src="x" alt="Close"></button>`, nil},
+		{"button named by nested text", `<button><span> Save </span></button>`, nil},
+		{"low-information link", `<a href="/docs">Click  <b>here</b></a>`,
+			[]a11yFinding{{`link text "click here" says nothing out of context`, `<a href="/docs">`}}},
+		{"empty link", `<a id="x" href="/"></a>`,
+			[]a11yFinding{{"link without accessible name", "#x"}}},
+		{"unlabelled input", `<input id="q" type="text">`,
+			[]a11yFinding{{"input without label", "#q"}}},
+		{"label for", `<label for="q">Search</label><input id="q">`, nil},
+		{"label for after the control", `<input id="q"><label for="q">Search</label>`, nil},
+		{"wrapping label", `<label>Search <input></label>`, nil},
+		{"hidden input", `<input type="hidden" name="csrf">`, nil},
+		{"unlabelled textarea", `<textarea name="body"></textarea>`,
+			[]a11yFinding{{"textarea without label", `<textarea name="body">`}}},
+		{"markup in script ignored", `<script>var s = "<img src=x>";</script>`, nil},
+		{"quoted gt in attribute", `<button data-on:click="a>b&amp;&amp;go()">Go</button>`, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, auditA11y(tc.html))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package via_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+type a11yPage struct{}
+
+func (p *a11yPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.Img(h.ID("hero"), h.Src("/hero.png")),
+		h.Img(h.Src("/ok.png"), h.Alt("Our team")),
+	)
+}
+
+func a11yWarnings(logger *captureLogger) []string {
+	var out []string
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogWarn && strings.HasPrefix(r.msg, "a11y: ") {
+			out = append(out, r.msg)
+		}
+	}
+	return out
+}
+
+func TestWithA11yAudit_logsEachFindingOncePerRoute(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithA11yAudit())
+	via.Mount[a11yPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/")
+
+	assert.Equal(t, []string{"a11y: img without alt on /: #hero"}, a11yWarnings(logger))
+}
+
+func TestA11yAudit_isOffByDefault(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug)
+	via.Mount[a11yPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+
+	assert.Empty(t, a11yWarnings(logger))
+}
+
+func TestA11yFindings_recordsEachFindingOncePerRoute(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithA11yAudit())
+	via.Mount[a11yPage](app, "/")
+	via.Mount[a11yPage](app, "/team")
+	server := vt.Serve(t, app)
+
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/team")
+
+	assert.Equal(t, []string{
+		"img without alt on /: #hero",
+		"img without alt on /team: #hero",
+	}, app.A11yFindings())
+}
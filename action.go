@@ -159,6 +159,9 @@ func (a *App) handleAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	slot := &d.actionSlots[slotIdx]
+	if !a.checkActionSignature(w, ctx, id, sigs) {
+		return
+	}
 
 	// Wrap the dispatch in the descriptor's group middleware so a
 	// requireAuth (or any group-level guard) checks the request before
@@ -193,9 +196,23 @@ func runAction(a *App, ctx *Ctx, slotIdx int, slot *actionSlot,
 	// about. Recorded in seconds for prom/otel convention.
 	started := time.Now()
 	m := a.metricsOrNoop()
+	// The audit hook records the actor as of the action's start, so read
+	// the user before the handler can sign in or out.
+	var (
+		auditUser string
+		actionErr error
+		panicked  bool
+	)
+	if a.cfg.auditHook != nil {
+		auditUser = ctx.User()
+	}
 	defer func() {
-		m.Histogram("via.action.latency", time.Since(started).Seconds(), "method", slot.name)
-		m.Counter("via.action.total", "method", slot.name)
+		labels := ctx.metricLabels("method", slot.name)
+		m.Histogram("via.action.latency", time.Since(started).Seconds(), labels...)
+		m.Counter("via.action.total", labels...)
+		if a.cfg.auditHook != nil {
+			a.audit(ctx, slot.name, auditUser, started, actionErr, panicked)
+		}
 	}()
 	// Serialize per-tab so parallel POSTs to the same ctx don't race
 	// on State writes, dirty bits, or Writer/Request assignment.
@@ -214,11 +231,14 @@ func runAction(a *App, ctx *Ctx, slotIdx int, slot *actionSlot,
 	ctx.mu.Lock()
 	ctx.w = w
 	ctx.r = r
+	ctx.args = r.URL.Query()
+	ctx.clientHeader, ctx.clientAddr = r.Header, r.RemoteAddr // kept for Client
 	ctx.mu.Unlock()
 	defer func() {
 		ctx.mu.Lock()
 		ctx.w = nil
 		ctx.r = nil
+		ctx.args = nil
 		ctx.mu.Unlock()
 	}()
 	// Every handler entry starts loud — Silent doesn't leak between
@@ -230,8 +250,12 @@ func runAction(a *App, ctx *Ctx, slotIdx int, slot *actionSlot,
 	// *before* the recover defer so the recover runs first (defers are
 	// LIFO) and turns the panic back into a normal return. If the
 	// handler ended in silent mode, drop any accumulated dirty bits so
-	// they don't leak into a subsequent loud action's flush.
+	// they don't leak into a subsequent loud action's flush. Optimistic
+	// signals the handler left alone revert first, so the revert rides the
+	// same flush as the handler's own patches.
+	var optimistic []optimisticSnap
 	defer func() {
+		restoreOptimistic(ctx, optimistic)
 		if ctx.silent.Load() {
 			ctx.discardDirty()
 			return
@@ -250,13 +274,16 @@ func runAction(a *App, ctx *Ctx, slotIdx int, slot *actionSlot,
 		if !ok {
 			err = fmt.Errorf("panic: %v", rec)
 		}
+		actionErr, panicked = err, true
 		a.dispatchActionError(ctx, err, true)
 	}()
 
 	ctx.lastSignals = sigs
+	optimistic = snapshotOptimistic(ctx, r)
 	if err := injectSignals(ctx, sigs); err != nil {
 		// Strict decode rejected a client value — surface the error and skip
 		// the handler so corrupt input never reaches it.
+		actionErr = err
 		a.dispatchActionError(ctx, err, false)
 		return
 	}
@@ -266,11 +293,31 @@ func runAction(a *App, ctx *Ctx, slotIdx int, slot *actionSlot,
 		defer form.RemoveAll()
 	}
 
+	a.observeAction(ctx, slot.name)
 	if err := ctx.actionFns[slotIdx](ctx); err != nil {
+		actionErr = err
 		a.dispatchActionError(ctx, err, false)
 	}
 }
 
+// runBeacon runs fn for a runtime beacon posted on ctx's behalf the way
+// handleAction runs an action: its signature checked, behind the route's
+// group middleware, and audited under the beacon's pseudo-action name. fn
+// recovers its own panics.
+func (a *App) runBeacon(w http.ResponseWriter, r *http.Request, ctx *Ctx, action, tok string, fn func()) {
+	if !a.checkBeaconSignature(w, ctx, action, tok) {
+		return
+	}
+	dispatch := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.cfg.auditHook != nil {
+			started, user := time.Now(), ctx.User()
+			defer func() { a.audit(ctx, action, user, started, nil, false) }()
+		}
+		fn()
+	})
+	applyMiddleware(ctx.desc.groupMW, dispatch).ServeHTTP(w, requestWithRoute(r, ctx.desc.route))
+}
+
 func (a *App) dispatchActionError(ctx *Ctx, err error, fromPanic bool) {
 	if a.cfg.actionErrorHandler != nil {
 		a.cfg.actionErrorHandler(ctx, err)
@@ -284,7 +331,8 @@ func (a *App) dispatchActionError(ctx *Ctx, err error, fromPanic bool) {
 }
 
 // injectSignals applies signals from a request body into the bound *C's
-// Signal[T] fields by wire key.
+// Signal[T] fields by wire key. A child's key ("Card.step") is matched
+// flat or, as the browser sends it, nested under the child's object.
 func injectSignals(ctx *Ctx, sigs map[string]any) error {
 	strict := ctx.app != nil && ctx.app.cfg.strictDecode
 	for slot, ref := range ctx.signalRefs {
@@ -292,7 +340,11 @@ func injectSignals(ctx *Ctx, sigs map[string]any) error {
 		if s.kind != kindSignal {
 			continue
 		}
-		if v, ok := sigs[s.wireKey]; ok {
+		v, ok := sigs[s.wireKey]
+		if !ok && strings.IndexByte(s.wireKey, '.') >= 0 {
+			v, ok = nestedSignal(sigs, s.wireKey)
+		}
+		if ok {
 			// decodeRaw still applies a best-effort value; the returned error is
 			// surfaced only under WithStrictDecode, where a lossy decode must
 			// reject the action rather than act on corrupt input.
@@ -303,3 +355,20 @@ func injectSignals(ctx *Ctx, sigs map[string]any) error {
 	}
 	return nil
 }
+
+// nestedSignal resolves a dotted wire key through the nested objects a
+// Datastar client posts for it.
+func nestedSignal(sigs map[string]any, key string) (any, bool) {
+	m := sigs
+	for {
+		head, rest, more := strings.Cut(key, ".")
+		v, ok := m[head]
+		if !ok || !more {
+			return v, ok
+		}
+		if m, ok = v.(map[string]any); !ok {
+			return nil, false
+		}
+		key = rest
+	}
+}
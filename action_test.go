@@ -492,3 +492,120 @@ func TestSilentActionStillShipsExplicitPatch(t *testing.T) {
 	assert.Contains(t, body, "PATCH-A",
 		"explicit pushes survive SyncOff even though the auto render is suppressed")
 }
+
+type eventArgPage struct {
+	Deleted via.StateTab[string]
+}
+
+func (p *eventArgPage) Delete(ctx *via.Ctx) {
+	p.Deleted.Write(ctx, "deleted:"+ctx.EventArg("id"))
+}
+
+func (p *eventArgPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID("out"), h.Text(p.Deleted.Read(ctx)),
+		h.Each([]string{"a", "b"}, func(id string) h.H {
+			return h.Button(on.Click(p.Delete, on.Arg("id", id)))
+		}))
+}
+
+func TestEventArg_deliversBoundArgumentToAction(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[eventArgPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, http.StatusOK, tc.Action("Delete").WithArg("id", "b").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "deleted:b")
+}
+
+func TestEventArg_isEmptyWithoutBinding(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[eventArgPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Delete").Fire()
+	body := vt.AwaitFrame(t, frames, 2*time.Second, "deleted:")
+	assert.NotContains(t, body, "deleted:b")
+}
+
+type sortablePage struct {
+	Order via.StateTab[[]string]
+}
+
+func (p *sortablePage) OnInit(ctx *via.Ctx) error {
+	p.Order.Write(ctx, []string{"a", "b", "c"})
+	return nil
+}
+
+func (p *sortablePage) Reorder(ctx *via.Ctx) {
+	p.Order.Write(ctx, ctx.EventArgs(on.SortOrderArg))
+}
+
+func (p *sortablePage) View(ctx *via.CtxR) h.H {
+	return h.Ul(h.ID("list"), on.Sortable(p.Reorder),
+		h.Each(p.Order.Read(ctx), func(id string) h.H {
+			return h.Li(on.SortItem(id), h.Text("item-"+id))
+		}))
+}
+
+func TestEventArgs_deliversSortableOrderToAction(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[sortablePage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, http.StatusOK, tc.Action("Reorder").
+		WithArg(on.SortOrderArg, "c").WithArg(on.SortOrderArg, "a").WithArg(on.SortOrderArg, "b").Fire())
+	body := vt.AwaitFrame(t, frames, 2*time.Second, "item-c")
+	assert.Regexp(t, `item-c.*item-a.*item-b`, body, "the re-render follows the order the action received")
+}
+
+type noteCard struct {
+	Draft via.Signal[string] `via:"draft"`
+}
+
+func (c *noteCard) View(ctx *via.CtxR) h.H { return h.Input(c.Draft.Bind()) }
+
+type notePage struct {
+	Card  *noteCard
+	Saved via.StateTab[string]
+}
+
+func (p *notePage) Save(ctx *via.Ctx) {
+	p.Saved.Write(ctx, "saved:"+p.Card.Draft.Read(ctx))
+}
+
+func (p *notePage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID("out"), h.Text(p.Saved.Read(ctx)), p.Card.View(ctx))
+}
+
+func TestAction_readsAChildSignalPostedNested(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[notePage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	// The browser posts a child's "Card.draft" as {"Card":{"draft":…}}.
+	tc.Action("Save").WithSignal("Card", map[string]any{"draft": "nested"}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "saved:nested")
+
+	tc.Action("Save").WithSignal("Card.draft", "flat").Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "saved:flat")
+}
@@ -0,0 +1,238 @@
+package via
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sigSignalKey carries a tab's action signatures, one per signed action:
+// {"Delete": "<expiry>.<mac>", ...}. Datastar sends every signal with each
+// @post, so the signature rides along without changing the action URL.
+const sigSignalKey = "via_sig"
+
+// The runtime's own beacons run on a tab's behalf like actions, under
+// these names: signed, audited and behind the route's group middleware.
+// Lower-case, so no action method can claim one.
+const (
+	popStateAction = "popstate" // history navigation
+	lazyAction     = "lazy"     // a Lazy region scrolled into view
+	evalAction     = "eval"     // a Ctx.Eval reply
+)
+
+// defaultActionSigTTL is ActionSigning.TTL when left zero.
+const defaultActionSigTTL = 15 * time.Minute
+
+// ActionSigning configures [WithActionSigning].
+type ActionSigning struct {
+	// TTL is how long a signature stays valid. A connected tab is re-issued
+	// fresh signatures over its SSE stream well before they expire, so
+	// only a captured request outlives it. Default 15 minutes; must be at
+	// least twice the SSE heartbeat.
+	TTL time.Duration
+
+	// Key is the HMAC-SHA256 key, at least 16 bytes. Every pod serving the
+	// app must share it; when nil a random per-process key is used, which
+	// is only right for a single pod.
+	Key []byte
+
+	// Actions names the action methods to sign. Empty signs every action
+	// and the runtime's beacons, which are named "popstate", "lazy" and
+	// "eval" here.
+	Actions []string
+}
+
+// WithActionSigning requires each action POST to carry an HMAC signature
+// over the tab id, the action name and an expiry. A request replayed after
+// the expiry, aimed at another tab, or guessed is refused with 403
+// (counted as via.action.signature) before the action or its group
+// middleware runs. Signatures are issued with the page and refreshed over
+// the SSE stream, so nothing changes for a live tab.
+//
+// Panics at New on a Key shorter than 16 bytes or a TTL under twice the
+// SSE heartbeat.
+func WithActionSigning(s ActionSigning) Option {
+	return func(c *config) { c.actionSigning = &s }
+}
+
+func (s *ActionSigning) validate(heartbeat time.Duration) {
+	if s == nil {
+		return
+	}
+	if s.Key != nil && len(s.Key) < 16 {
+		panic(fmt.Sprintf("via.WithActionSigning: Key must be at least 16 bytes, got %d", len(s.Key)))
+	}
+	if heartbeat <= 0 {
+		heartbeat = keepaliveFloor
+	}
+	if s.TTL != 0 && s.TTL < 2*heartbeat {
+		panic(fmt.Sprintf("via.WithActionSigning: TTL %v is under twice the SSE heartbeat (%v); "+
+			"tabs could not be re-signed before their signatures expire", s.TTL, heartbeat))
+	}
+}
+
+// actionSigner is the resolved ActionSigning an App signs and verifies with.
+type actionSigner struct {
+	ttl  time.Duration
+	key  []byte
+	only map[string]bool // nil: every action
+}
+
+func newActionSigner(s *ActionSigning) *actionSigner {
+	if s == nil {
+		return nil
+	}
+	sg := &actionSigner{ttl: s.TTL, key: s.Key}
+	if sg.ttl == 0 {
+		sg.ttl = defaultActionSigTTL
+	}
+	if sg.key == nil {
+		sg.key = make([]byte, 32)
+		_, _ = rand.Read(sg.key)
+	}
+	if len(s.Actions) > 0 {
+		sg.only = make(map[string]bool, len(s.Actions))
+		for _, a := range s.Actions {
+			sg.only[a] = true
+		}
+	}
+	return sg
+}
+
+func (sg *actionSigner) covers(action string) bool {
+	return sg != nil && (sg.only == nil || sg.only[action])
+}
+
+func (sg *actionSigner) mac(tabID, action string, exp int64) string {
+	m := hmac.New(sha256.New, sg.key)
+	m.Write([]byte(tabID))
+	m.Write([]byte{0})
+	m.Write([]byte(action))
+	m.Write([]byte{0})
+	m.Write(strconv.AppendInt(nil, exp, 10))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil)[:16])
+}
+
+// tokens signs every covered action of ctx's composition, and the
+// beacons the page may send, until now+ttl, recording the expiry on ctx so
+// the SSE loop knows when to re-issue. nil when nothing is signed.
+func (sg *actionSigner) tokens(ctx *Ctx, now time.Time) map[string]string {
+	if sg == nil {
+		return nil
+	}
+	exp := now.Add(sg.ttl).Unix()
+	var out map[string]string
+	sign := func(action string) {
+		if !sg.covers(action) {
+			return
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[action] = sg.token(ctx.id, action, exp)
+	}
+	for _, s := range ctx.desc.actionSlots {
+		sign(s.name)
+	}
+	sign(popStateAction)
+	sign(lazyAction)
+	if out != nil {
+		ctx.sigExp.Store(exp)
+	}
+	return out
+}
+
+// token is the signature for action on tabID valid until exp.
+func (sg *actionSigner) token(tabID, action string, exp int64) string {
+	return strconv.FormatInt(exp, 10) + "." + sg.mac(tabID, action, exp)
+}
+
+// verify checks the signature sigs carries for action on tabID. reason is
+// "missing", "invalid" or "expired" when it fails.
+func (sg *actionSigner) verify(tabID, action string, sigs map[string]any, now time.Time) (reason string, ok bool) {
+	var tok string
+	switch v := sigs[sigSignalKey].(type) {
+	case map[string]any:
+		tok, _ = v[action].(string)
+	case string:
+		// A multipart body flattens signals to strings.
+		var m map[string]string
+		if json.Unmarshal([]byte(v), &m) == nil {
+			tok = m[action]
+		}
+	}
+	return sg.check(tabID, action, tok, now)
+}
+
+// check validates tok as the signature for action on tabID.
+func (sg *actionSigner) check(tabID, action, tok string, now time.Time) (reason string, ok bool) {
+	if tok == "" {
+		return "missing", false
+	}
+	expStr, mac, found := strings.Cut(tok, ".")
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if !found || err != nil || !hmac.Equal([]byte(mac), []byte(sg.mac(tabID, action, exp))) {
+		return "invalid", false
+	}
+	if now.Unix() > exp {
+		return "expired", false
+	}
+	return "", true
+}
+
+// checkActionSignature answers 403 and reports false when action requires
+// a signature the request doesn't validly carry.
+func (a *App) checkActionSignature(w http.ResponseWriter, ctx *Ctx, action string, sigs map[string]any) bool {
+	if !a.signer.covers(action) {
+		return true
+	}
+	reason, ok := a.signer.verify(ctx.id, action, sigs, a.now())
+	if ok {
+		return true
+	}
+	a.refuseSignature(w, ctx, action, reason)
+	return false
+}
+
+// checkBeaconSignature is checkActionSignature for a runtime beacon, whose
+// text body carries the signature token itself.
+func (a *App) checkBeaconSignature(w http.ResponseWriter, ctx *Ctx, action, tok string) bool {
+	if !a.signer.covers(action) {
+		return true
+	}
+	reason, ok := a.signer.check(ctx.id, action, tok, a.now())
+	if ok {
+		return true
+	}
+	a.refuseSignature(w, ctx, action, reason)
+	return false
+}
+
+func (a *App) refuseSignature(w http.ResponseWriter, ctx *Ctx, action, reason string) {
+	a.metricsOrNoop().Counter("via.action.signature", "reason", reason)
+	a.logWarn(ctx, "action %s refused: %s signature", action, reason)
+	http.Error(w, "action signature "+reason, http.StatusForbidden)
+}
+
+// refreshActionSigs pushes fresh signatures once the tab's current ones
+// are past half their lifetime. Run on SSE connect and every keepalive,
+// so a connected tab never holds an expired signature.
+func (a *App) refreshActionSigs(ctx *Ctx) {
+	if a.signer == nil {
+		return
+	}
+	now := a.now()
+	if exp := ctx.sigExp.Load(); exp != 0 && now.Add(a.signer.ttl/2).Unix() < exp {
+		return
+	}
+	if toks := a.signer.tokens(ctx, now); toks != nil {
+		ctx.patch.Signal(sigSignalKey, toks)
+	}
+}
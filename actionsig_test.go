@@ -0,0 +1,124 @@
+package via_test
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ledgerPage struct {
+	Balance via.StateTabNum[int]
+}
+
+func (p *ledgerPage) Withdraw(ctx *via.Ctx) error {
+	return p.Balance.Update(ctx, func(n int) (int, error) { return n - 10, nil })
+}
+
+func (p *ledgerPage) Refresh(ctx *via.Ctx) {}
+
+func (p *ledgerPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.P(h.Textf("balance %d", p.Balance.Read(ctx))),
+		h.Button(h.Text("Withdraw"), on.Click(p.Withdraw)),
+		h.Button(h.Text("Refresh"), on.Click(p.Refresh)))
+}
+
+var sigKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestActionSigning_liveTabsActNormally(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithActionSigning(via.ActionSigning{Key: sigKey}))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	assert.Contains(t, tc.HTML(), "via_sig")
+	assert.Equal(t, http.StatusOK, tc.Action("Withdraw").Fire())
+	assert.Equal(t, http.StatusOK, tc.Action("Refresh").Fire())
+}
+
+func TestActionSigning_refusesMissingForeignAndExpired(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	clk := vt.NewClock(time.Unix(1_700_000_000, 0))
+	app := via.New(via.WithClock(clk), via.WithMetrics(m),
+		via.WithActionSigning(via.ActionSigning{Key: sigKey, TTL: 10 * time.Minute}))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	assert.Equal(t, http.StatusForbidden,
+		tc.Action("Withdraw").WithSignal("via_sig", nil).Fire(), "no signature")
+
+	// A signature is bound to its tab: another tab's can't be replayed here.
+	other := tc.Fork("/")
+	assert.Equal(t, http.StatusForbidden,
+		tc.Action("Withdraw").WithSignal("via_sig", sigOf(t, other)).Fire(), "another tab's signature")
+
+	// ...and to its expiry.
+	clk.Advance(11 * time.Minute)
+	assert.Equal(t, http.StatusForbidden, tc.Action("Withdraw").Fire(), "expired")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.action.signature:reason,missing")
+	assert.Contains(t, m.counters, "via.action.signature:reason,invalid")
+	assert.Contains(t, m.counters, "via.action.signature:reason,expired")
+}
+
+// sigOf returns the via_sig signal c's page was rendered with.
+func sigOf(t *testing.T, c *vt.Client) any {
+	t.Helper()
+	m := regexp.MustCompile(`data-signals="([^"]*)"`).FindStringSubmatch(c.HTML())
+	require.NotNil(t, m)
+	var sigs map[string]any
+	require.NoError(t, json.Unmarshal([]byte(html.UnescapeString(m[1])), &sigs))
+	require.NotNil(t, sigs["via_sig"])
+	return sigs["via_sig"]
+}
+
+func TestActionSigning_onlyListedActions(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithActionSigning(via.ActionSigning{Key: sigKey, Actions: []string{"Withdraw"}}))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	assert.Equal(t, http.StatusOK, tc.Action("Refresh").WithSignal("via_sig", nil).Fire())
+	assert.Equal(t, http.StatusForbidden, tc.Action("Withdraw").WithSignal("via_sig", nil).Fire())
+}
+
+func TestActionSigning_reconnectAfterExpiryGetsFreshSignatures(t *testing.T) {
+	t.Parallel()
+	clk := vt.NewClock(time.Unix(1_700_000_000, 0))
+	app := via.New(via.WithClock(clk),
+		via.WithActionSigning(via.ActionSigning{Key: sigKey, TTL: time.Hour}))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	clk.Advance(2 * time.Hour)
+	frames, cancel := tc.SSE()
+	defer cancel()
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "via_sig")
+	assert.Contains(t, frame, "Withdraw")
+}
+
+func TestWithActionSigning_validates(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, "via.WithActionSigning: Key must be at least 16 bytes, got 3", func() {
+		via.New(via.WithActionSigning(via.ActionSigning{Key: []byte("abc")}))
+	})
+	assert.Panics(t, func() {
+		via.New(via.WithActionSigning(via.ActionSigning{TTL: 30 * time.Second}))
+	}, "a TTL the keepalive can't refresh in time")
+}
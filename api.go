@@ -0,0 +1,164 @@
+package via
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// apiBodyLimit caps the request body Decode will read.
+const apiBodyLimit = 1 << 20
+
+// Request is the in-flight call to an [App.API] endpoint: the embedded
+// *http.Request (PathValue, URL, Header, Context …) plus the session and
+// response bits a handler needs.
+type Request struct {
+	*http.Request
+	app    *App
+	w      http.ResponseWriter
+	status int
+}
+
+// Session returns the caller's via session — the same one their pages
+// and actions see, so sess.Get works on a *Request too. Writes through it
+// don't re-render open tabs; see [RequestSession].
+func (r *Request) Session() *Session {
+	return &Session{data: r.app.sessionFromRequest(r.Request), app: r.app}
+}
+
+// User returns the user the caller's session signed in as, or "". See
+// [Ctx.SignIn].
+func (r *Request) User() string {
+	if sess := r.app.sessionFromRequest(r.Request); sess != nil {
+		if in := sess.auth.Load(); in != nil {
+			return in.user
+		}
+	}
+	return ""
+}
+
+// ResponseHeader returns the response header map, for Location,
+// Cache-Control and the like. Content-Type is set to application/json for
+// you. The request's own headers stay on the embedded r.Header.
+func (r *Request) ResponseHeader() http.Header { return r.w.Header() }
+
+// SetStatus overrides the success status (200, or 204 for a nil result),
+// e.g. 201 after a create.
+func (r *Request) SetStatus(code int) { r.status = code }
+
+// Decode reads the JSON request body into v. Unknown fields and bodies over
+// 1 MiB are rejected. The error is an [APIError] with status 400, so a
+// handler can return it as is.
+func (r *Request) Decode(v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(r.w, r.Body, apiBodyLimit))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return APIErrorf(http.StatusBadRequest, "empty request body")
+		}
+		return APIErrorf(http.StatusBadRequest, "invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+// APIError is an error an API handler returns to choose the response
+// status and the message the client sees.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string { return e.Message }
+
+// APIErrorf returns an *APIError with status and a formatted message.
+//
+//	return nil, via.APIErrorf(http.StatusNotFound, "no todo %q", id)
+func APIErrorf(status int, format string, args ...any) error {
+	return &APIError{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// API registers a JSON endpoint on the app's mux. fn's result is encoded
+// as the response body — 200, or 204 when it is nil. A returned error is
+// encoded as {"error": "..."}: an [APIError] keeps its status and
+// message, ErrStaleVersion maps to 409, and anything else is logged and
+// answered with a bare 500 so internals don't leak.
+//
+//	app.API("GET /api/todos", func(r *via.Request) (any, error) {
+//	    return store.List(r.Context(), r.User())
+//	})
+func (a *App) API(pattern string, fn func(*Request) (any, error)) {
+	a.claimRoute(pattern, "API")
+	a.mux.Handle(pattern, a.apiHandler(fn))
+}
+
+// API registers a JSON endpoint under the group prefix, wrapped in the
+// group's middleware chain. Same pattern shape as [Group.HandleFunc] and
+// same semantics as [App.API].
+func (g *Group) API(pattern string, fn func(*Request) (any, error)) {
+	g.handle(pattern, g.app.apiHandler(fn), "API")
+}
+
+func (a *App) apiHandler(fn func(*Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w, done := a.pageCompress.wrap(w, r)
+		defer done()
+		req := &Request{Request: r, app: a, w: w}
+		v, err := callAPI(fn, req)
+		if err != nil {
+			a.writeAPIError(w, r, err)
+			return
+		}
+		status := req.status
+		if v == nil {
+			if status == 0 {
+				status = http.StatusNoContent
+			}
+			w.WriteHeader(status)
+			return
+		}
+		body, err := json.Marshal(v)
+		if err != nil {
+			a.writeAPIError(w, r, fmt.Errorf("encode response: %w", err))
+			return
+		}
+		if status == 0 {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, body)
+	})
+}
+
+// callAPI runs fn, turning a panic into an error so it is answered (and
+// logged) like any other 500, as actions do.
+func callAPI(fn func(*Request) (any, error), req *Request) (v any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return fn(req)
+}
+
+func (a *App) writeAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg := http.StatusInternalServerError, "internal server error"
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		status, msg = apiErr.Status, apiErr.Message
+	case errors.Is(err, ErrStaleVersion):
+		status, msg = http.StatusConflict, err.Error()
+	default:
+		a.logErr(nil, "API %s %s: %v", r.Method, r.URL.Path, err)
+	}
+	a.metricsOrNoop().Counter("via.api.error", "status", fmt.Sprint(status))
+	body, _ := json.Marshal(map[string]string{"error": msg})
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -0,0 +1,199 @@
+package via_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/sess"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type apiTodo struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type apiCart struct{ Items int }
+
+type apiLoginPage struct{}
+
+func (p *apiLoginPage) Login(ctx *via.Ctx) error {
+	ctx.SignIn("alice")
+	sess.Put(ctx, apiCart{Items: 3})
+	return nil
+}
+
+func (p *apiLoginPage) View(ctx *via.CtxR) h.H { return h.P(h.Text("login")) }
+
+func doJSON(t *testing.T, c *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if len(b) > 0 {
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestAPI_encodesResultsAndMapsErrors(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	todos := map[string]apiTodo{"1": {ID: "1", Title: "milk"}}
+	app.API("GET /api/todos/{id}", func(r *via.Request) (any, error) {
+		td, ok := todos[r.PathValue("id")]
+		if !ok {
+			return nil, via.APIErrorf(http.StatusNotFound, "no todo %s", r.PathValue("id"))
+		}
+		return td, nil
+	})
+	app.API("POST /api/todos", func(r *via.Request) (any, error) {
+		var td apiTodo
+		if err := r.Decode(&td); err != nil {
+			return nil, err
+		}
+		r.SetStatus(http.StatusCreated)
+		r.ResponseHeader().Set("Location", "/api/todos/"+td.ID)
+		return td, nil
+	})
+	app.API("DELETE /api/todos/{id}", func(r *via.Request) (any, error) { return nil, nil })
+	app.API("GET /api/boom", func(r *via.Request) (any, error) {
+		return nil, errors.New("db password is hunter2")
+	})
+	app.API("GET /api/stale", func(r *via.Request) (any, error) { return nil, via.ErrStaleVersion })
+
+	c := server.Client()
+	status, body := doJSON(t, c, "GET", server.URL+"/api/todos/1", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"id":"1","title":"milk"}`, body)
+
+	status, body = doJSON(t, c, "GET", server.URL+"/api/todos/9", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"error":"no todo 9"}`, body)
+
+	status, body = doJSON(t, c, "POST", server.URL+"/api/todos", `{"id":"2","title":"eggs"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.JSONEq(t, `{"id":"2","title":"eggs"}`, body)
+
+	status, body = doJSON(t, c, "POST", server.URL+"/api/todos", `{"nope":true}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "invalid JSON body")
+
+	status, body = doJSON(t, c, "DELETE", server.URL+"/api/todos/1", "")
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, body)
+
+	status, body = doJSON(t, c, "GET", server.URL+"/api/boom", "")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.NotContains(t, body, "hunter2", "internal errors don't reach the client")
+
+	status, _ = doJSON(t, c, "GET", server.URL+"/api/stale", "")
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestAPI_headerIsTheRequestsAndResponseHeaderTheResponses(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	app.API("GET /api/echo", func(r *via.Request) (any, error) {
+		r.ResponseHeader().Set("X-Echo", r.Header.Get("X-Probe"))
+		return nil, nil
+	})
+
+	req, err := http.NewRequest("GET", server.URL+"/api/echo", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Probe", "ping")
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "ping", resp.Header.Get("X-Echo"))
+}
+
+func TestAPI_recoversPanics(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	app.API("GET /api/panic", func(r *via.Request) (any, error) { panic("oops") })
+
+	status, body := doJSON(t, server.Client(), "GET", server.URL+"/api/panic", "")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.JSONEq(t, `{"error":"internal server error"}`, body)
+}
+
+func TestAPI_seesTheSessionPagesUse(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithInsecureCookies())
+	server := vt.Serve(t, app)
+	via.Mount[apiLoginPage](app, "/")
+	app.API("GET /api/me", func(r *via.Request) (any, error) {
+		cart, _ := sess.Get[apiCart](r)
+		return map[string]any{"user": r.User(), "items": cart.Items}, nil
+	})
+
+	httpc := jarClient(t)
+	status, _ := doJSON(t, httpc, "GET", server.URL+"/api/me", "")
+	require.Equal(t, http.StatusOK, status)
+
+	resp, err := httpc.Get(server.URL + "/")
+	require.NoError(t, err)
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	tabID := regexp.MustCompile(`/_[0-9a-f]{64}`).FindString(string(page))
+	require.NotEmpty(t, tabID)
+	ar, err := httpc.Post(server.URL+"/_action/Login", "application/json",
+		strings.NewReader(`{"via_tab":"`+tabID+`"}`))
+	require.NoError(t, err)
+	ar.Body.Close()
+	require.Equal(t, http.StatusOK, ar.StatusCode)
+
+	_, body := doJSON(t, httpc, "GET", server.URL+"/api/me", "")
+	var me struct {
+		User  string `json:"user"`
+		Items int    `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &me))
+	assert.Equal(t, "alice", me.User)
+	assert.Equal(t, 3, me.Items)
+}
+
+func TestGroupAPI_runsGroupMiddleware(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	g := app.Group("/api")
+	g.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	g.API("/ping", func(r *via.Request) (any, error) { return "pong", nil })
+
+	resp, err := server.Client().Get(server.URL + "/api/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ := http.NewRequest("GET", server.URL+"/api/ping", nil)
+	req.Header.Set("Authorization", "Bearer x")
+	resp, err = server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "\"pong\"\n", string(b))
+}
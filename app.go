@@ -40,6 +40,16 @@ type App struct {
 	routesMu sync.Mutex
 	serverMu sync.Mutex // guards a.server while Start binds and Shutdown reads
 
+	// a11yFound is each WithA11yAudit finding once, in the order first
+	// seen, for A11yFindings; a11ySeen indexes it by route and finding.
+	a11yFound []string
+	a11ySeen  map[string]struct{}
+	a11yMu    sync.Mutex
+	dupIDSeen sync.Map // route+id/key already logged by the duplicate id/bind dev check
+
+	pageCompress *pageCompressor // WithCompression applied to page documents
+	signer       *actionSigner   // WithActionSigning; nil when off
+
 	// appSignals holds plugin-registered, app-wide initial signal values.
 	// They are injected into <meta data-signals> on every page render but
 	// don't have a server-side reactive handle — clients drive them.
@@ -48,10 +58,12 @@ type App struct {
 
 	// valStates holds the L1 cache + decode closure for each value-shaped
 	// StateApp key. The backplane Store cell `val:<key>` is the source of
-	// truth; valCell.l1 is a per-pod cache reconciled to it. Populated at the
-	// first bindApp for a key.
+	// truth; valCell.cur is a per-pod cache reconciled to it. Populated at the
+	// first bindApp for a key. valIndex is a read-only copy republished on
+	// each registration, so Read looks cells up without valStatesMu.
 	valStates     map[string]*valCell
 	valStatesMu   sync.Mutex
+	valIndex      atomic.Pointer[map[string]*valCell]
 	valTailerOnce sync.Once // starts the one changes-feed tailer per App
 
 	// sessDecoders holds the typed (Store bytes → T) decoder for each
@@ -60,6 +72,12 @@ type App struct {
 	sessDecoders   map[string]func([]byte) (any, error)
 	sessDecodersMu sync.Mutex
 
+	// appStateKeys is every StateApp key WithAppStateStore snapshots:
+	// those restored at New plus each one registered since. appStateMu
+	// also keeps a periodic save and the Shutdown save from overlapping.
+	appStateKeys map[string]struct{}
+	appStateMu   sync.Mutex
+
 	// backplane backs StateAppEvents and (later) clustered StateApp/StateSess.
 	// Resolved at New: a nil config backplane becomes InMemory(), so the
 	// runtime always drives one Backplane code path. Drained on Shutdown.
@@ -77,8 +95,7 @@ type App struct {
 	consumersByKey map[string][]*consumerState
 	consumersMu    sync.Mutex
 
-	contextRegistry   map[string]*Ctx
-	contextRegistryMu sync.RWMutex
+	contexts ctxRegistry // live tabs by id; see registry.go
 
 	sessions   map[string]*session
 	sessionsMu sync.RWMutex
@@ -113,6 +130,13 @@ type App struct {
 	backplaneCtx    context.Context
 	backplaneCancel context.CancelFunc
 
+	// hooks are the plugins' lifecycle hooks; see pluginhooks.go.
+	hooks pluginHooks
+	// pluginsByName indexes named plugins for App.Plugin; see plugins.go.
+	pluginsByName map[string]Plugin
+	// datastarSrc is the content-hashed URL pages load Datastar from.
+	datastarSrc string
+
 	middlewareMu sync.Mutex
 	middleware   []Middleware
 
@@ -123,6 +147,15 @@ type App struct {
 
 // ServeHTTP makes *App an http.Handler.
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(a.cfg.trustedProxies) > 0 {
+		r = a.applyForwarded(r)
+	}
+	if a.cfg.basePath != "" {
+		r = a.stripBasePath(r)
+	}
+	if a.cfg.metrics != nil {
+		a.cfg.metrics.Counter("via.request.total", "endpoint", string(a.Endpoint(r)))
+	}
 	if a.serveHealth(w, r) {
 		return
 	}
@@ -289,25 +322,18 @@ func (a *App) registerDescriptor(d *cmpDescriptor) {
 // registry write. Returns false if the cap is set and already met —
 // the caller must respond with 503 instead of registering. Separate
 // "live count" check + register opens a TOCTOU race under heavy
-// concurrent page loads; this fuses both steps under a single Lock.
+// concurrent page loads; ctxRegistry.tryAdd claims the slot first.
 func (a *App) tryRegisterCtx(ctx *Ctx, limit int) bool {
-	a.contextRegistryMu.Lock()
-	if limit > 0 && len(a.contextRegistry) >= limit {
-		a.contextRegistryMu.Unlock()
+	live, ok := a.contexts.tryAdd(ctx, limit)
+	if !ok {
 		return false
 	}
-	a.contextRegistry[ctx.id] = ctx
-	live := len(a.contextRegistry)
-	a.contextRegistryMu.Unlock()
 	a.metricsOrNoop().Gauge("via.ctx.live", float64(live))
 	return true
 }
 
 func (a *App) unregisterCtx(id string) {
-	a.contextRegistryMu.Lock()
-	delete(a.contextRegistry, id)
-	live := len(a.contextRegistry)
-	a.contextRegistryMu.Unlock()
+	live := a.contexts.remove(id)
 	a.metricsOrNoop().Gauge("via.ctx.live", float64(live))
 }
 
@@ -316,10 +342,7 @@ func (a *App) unregisterCtx(id string) {
 // disposal). Comma-ok shape so callers don't allocate an error wrapper
 // just to throw it away — every caller maps a miss to a 404 directly.
 func (a *App) getCtx(id string) (*Ctx, bool) {
-	a.contextRegistryMu.RLock()
-	defer a.contextRegistryMu.RUnlock()
-	ctx, ok := a.contextRegistry[id]
-	return ctx, ok
+	return a.contexts.get(id)
 }
 
 func (a *App) emit(level LogLevel, ctx *Ctx, format string, args ...any) {
@@ -335,7 +358,7 @@ func (a *App) emit(level LogLevel, ctx *Ctx, format string, args ...any) {
 		logger = defaultLogger{}
 	}
 	if ctx != nil {
-		logger.Log(level, msg, tabSignalKey, ctx.id)
+		logger.Log(level, msg, append([]any{tabSignalKey, ctx.id}, ctx.labelPairs()...)...)
 	} else {
 		logger.Log(level, msg)
 	}
@@ -382,13 +405,13 @@ func New(opts ...Option) *App {
 	backplaneCtx, backplaneCancel := context.WithCancel(context.Background())
 	a := &App{
 		mux:             mux,
-		contextRegistry: make(map[string]*Ctx),
 		sessions:        make(map[string]*session),
 		appSignals:      make(map[string]any),
 		routes:          make(map[string]string),
 		logs:            make(map[string]*logState),
 		valStates:       make(map[string]*valCell),
 		sessDecoders:    make(map[string]func([]byte) (any, error)),
+		appStateKeys:    make(map[string]struct{}),
 		backplaneDone:   make(chan struct{}),
 		backplaneCtx:    backplaneCtx,
 		backplaneCancel: backplaneCancel,
@@ -403,6 +426,7 @@ func New(opts ...Option) *App {
 			snapshotInterval:  64,
 			sseHeartbeat:      25 * time.Second,
 			sseWriteTimeout:   10 * time.Second,
+			sseReplayBytes:    defaultReplayBytes,
 			maxRequestBody:    1 << 20,
 			maxUploadSize:     32 << 20,
 			// Secure-by-default: the deployment surface (internal tools,
@@ -413,17 +437,16 @@ func New(opts ...Option) *App {
 			// footgun and the cost amortizes to ~zero (once per descriptor).
 			// WithoutDevChecks opts out.
 			devChecks: true,
+			clock:     realClock{},
 		},
 	}
 	for _, opt := range opts {
 		opt(&a.cfg)
 	}
 	a.cfg.validate()
-	for _, plugin := range a.cfg.plugins {
-		if plugin != nil {
-			plugin.Register(a)
-		}
-	}
+	a.pageCompress = newPageCompressor(a.cfg.compression)
+	a.signer = newActionSigner(a.cfg.actionSigning)
+	a.registerPlugins(a.cfg.plugins)
 
 	// A nil backplane resolves to the in-process default, so the Backplane
 	// interface is exercised on every single-pod run (no nil-special-case path).
@@ -439,13 +462,29 @@ func New(opts ...Option) *App {
 		a.startBroadcastTailer()
 	}
 
-	a.mux.HandleFunc("GET /_datastar.js", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/javascript")
-		_, _ = w.Write(datastarJS)
-	})
+	if a.cfg.appState != nil {
+		a.restoreAppState()
+	}
+
+	// Pages load the runtime from its content-hashed URL; the fixed
+	// /_datastar.js stays for pages rendered by older builds and for
+	// hand-written documents.
+	js := datastarJS
+	if a.cfg.datastarJS != nil {
+		js = a.cfg.datastarJS
+	}
+	runtime := newAsset("datastar.js", js)
+	dsPath := assetURLPath("datastar.js", js)
+	a.datastarSrc = a.cfg.basePath + dsPath
+	a.mux.HandleFunc("GET "+dsPath, runtime.serveImmutable)
+	a.mux.HandleFunc("GET /_datastar.js", runtime.serveRevalidated)
 	a.mux.HandleFunc("GET /_sse", a.handleSSE)
 	a.mux.HandleFunc("POST /_action/{id}", a.handleAction)
 	a.mux.HandleFunc("POST /_sse/close", a.handleSSEClose)
+	a.mux.HandleFunc("POST /_sse/visibility", a.handleVisibility)
+	a.mux.HandleFunc("POST /_sse/lazy", a.handleLazy)
+	a.mux.HandleFunc("POST /_sse/eval", a.handleEval)
+	a.mux.HandleFunc("POST /_sse/popstate", a.handlePopState)
 
 	a.rebuildChain()
 	a.handler = a.withSession()
@@ -453,19 +492,26 @@ func New(opts ...Option) *App {
 	// The context-TTL sweep only reaps stream-less ctxs: a connected stream
 	// is kept alive by Ctx.connected regardless of the TTL, so a short TTL
 	// can no longer kill a live tab and needs no guard against the heartbeat.
-	if a.cfg.sessionTTL > 0 || a.cfg.contextTTL > 0 || a.cfg.reconcileInterval > 0 {
+	if a.cfg.sessionTTL > 0 || a.cfg.contextTTL > 0 || a.cfg.reconcileInterval > 0 || a.cfg.appState != nil {
 		a.stopSweep = make(chan struct{})
 		if a.cfg.sessionTTL > 0 {
 			a.bgWG.Add(1)
-			go a.runSweep(a.cfg.sessionTTL/2, time.Millisecond, a.removeExpiredSessions)
+			go a.runSweep(a.Clock(), a.cfg.sessionTTL/2, time.Millisecond, a.removeExpiredSessions)
 		}
 		if a.cfg.contextTTL > 0 {
 			a.bgWG.Add(1)
-			go a.runSweep(a.cfg.contextTTL/2, time.Second, a.removeExpiredContexts)
+			go a.runSweep(a.Clock(), a.cfg.contextTTL/2, time.Second, a.removeExpiredContexts)
 		}
 		if a.cfg.reconcileInterval > 0 {
 			a.bgWG.Add(1)
-			go a.runSweep(a.cfg.reconcileInterval, a.cfg.reconcileInterval, a.reconcileValues)
+			// Reconcile is backplane convergence, not TTL: it stays on real
+			// time so a fake clock can't stall it.
+			go a.runSweep(realClock{}, a.cfg.reconcileInterval, a.cfg.reconcileInterval, a.reconcileValues)
+		}
+		if a.cfg.appState != nil {
+			a.bgWG.Add(1)
+			go a.runSweep(a.Clock(), a.cfg.appState.interval, a.cfg.appState.interval,
+				func() { a.saveAppState(a.backplaneCtx) })
 		}
 	}
 
@@ -481,7 +527,7 @@ func (a *App) withSession() http.Handler {
 		_, pattern := a.mux.Handler(r)
 		matched := pattern != ""
 
-		if matched {
+		if matched && !a.isCrawler(r) {
 			if a.getOrCreateSession(w, r) == nil {
 				a.logWarn(nil, "max sessions reached (%d); rejecting request", a.cfg.maxSessions)
 				http.Error(w, "server is at capacity", http.StatusServiceUnavailable)
@@ -0,0 +1,179 @@
+package via
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultAppStateInterval is how often WithAppStateStore snapshots when no
+// interval is given.
+const defaultAppStateInterval = 30 * time.Second
+
+// AppStateStore keeps a snapshot of the app's StateApp values — counters,
+// feature flags, caches — somewhere that outlives the process: a file, a
+// Redis key, a SQL row. Install it with [WithAppStateStore]. Values are
+// the handles' JSON encodings, keyed by wire key.
+type AppStateStore interface {
+	// LoadAppState returns the last snapshot saved, or an empty map when
+	// there is none yet.
+	LoadAppState(ctx context.Context) (map[string]json.RawMessage, error)
+	// SaveAppState replaces the snapshot with state.
+	SaveAppState(ctx context.Context, state map[string]json.RawMessage) error
+}
+
+// WithAppStateStore snapshots every StateApp value into s every interval
+// (30s when zero) and once more on Shutdown, and restores the last
+// snapshot when the App is created, so a deploy or restart doesn't wipe
+// app-wide state.
+//
+// Restoring only fills keys the backplane has never seen, so a clustered
+// backplane that already holds newer values wins; with one, a shared
+// durable backend usually makes this option unnecessary. StateAppEvents
+// logs aren't snapshotted. Panics at New on a nil store or a negative
+// interval.
+//
+//	app := via.New(via.WithAppStateStore(via.AppStateFile("state.json"), 0))
+func WithAppStateStore(s AppStateStore, interval time.Duration) Option {
+	return func(c *config) { c.appState = &appStateConfig{store: s, interval: interval} }
+}
+
+// appStateConfig is what WithAppStateStore set; nil when it wasn't used.
+type appStateConfig struct {
+	store    AppStateStore
+	interval time.Duration
+}
+
+// validate panics on a nil store or a negative interval and applies the
+// default interval; called from config.validate at New.
+func (s *appStateConfig) validate() {
+	if s == nil {
+		return
+	}
+	if s.store == nil {
+		panic("via.WithAppStateStore: nil AppStateStore")
+	}
+	if s.interval < 0 {
+		panic(fmt.Sprintf("via.WithAppStateStore: interval must be >= 0, got %v", s.interval))
+	}
+	if s.interval == 0 {
+		s.interval = defaultAppStateInterval
+	}
+}
+
+// restoreAppState seeds the backplane's value cells from the store's last
+// snapshot. Runs once from New, before any Mount, so each handle's cell
+// picks its value up as it registers.
+func (a *App) restoreAppState() {
+	state, err := a.cfg.appState.store.LoadAppState(a.backplaneCtx)
+	if err != nil {
+		a.logErr(nil, "via: restoring app state: %v", err)
+		return
+	}
+	a.appStateMu.Lock()
+	defer a.appStateMu.Unlock()
+	for key, data := range state {
+		a.appStateKeys[key] = struct{}{}
+		rev, err := a.backplane.CAS(a.backplaneCtx, valKey(key), 0, data)
+		if errors.Is(err, ErrCASConflict) {
+			continue // the backplane already holds a value
+		}
+		if err != nil {
+			a.logWarn(nil, "via: restoring app state %q: %v", key, err)
+			continue
+		}
+		// The hint lets the changes tailer pull the cell into L1 once the
+		// key's handle registers it.
+		if hint, mErr := json.Marshal(change{Key: key, Rev: rev}); mErr == nil {
+			_, _ = a.backplane.Append(a.backplaneCtx, changesKey, hint)
+		}
+	}
+}
+
+// saveAppState snapshots every StateApp value into the store: the keys
+// mounted in this process plus any restored but not mounted yet, so a
+// value outlives a release that doesn't render it.
+func (a *App) saveAppState(ctx context.Context) {
+	a.appStateMu.Lock()
+	defer a.appStateMu.Unlock()
+	a.valStatesMu.Lock()
+	for k := range a.valStates {
+		a.appStateKeys[k] = struct{}{}
+	}
+	a.valStatesMu.Unlock()
+
+	state := make(map[string]json.RawMessage, len(a.appStateKeys))
+	for key := range a.appStateKeys {
+		data, _, ok, err := a.backplane.LoadSnapshot(ctx, valKey(key))
+		if err != nil {
+			a.logWarn(nil, "via: snapshotting app state %q: %v", key, err)
+			return // a partial snapshot would drop the key on the next boot
+		}
+		if ok {
+			state[key] = data
+		}
+	}
+	if err := a.cfg.appState.store.SaveAppState(ctx, state); err != nil {
+		a.logErr(nil, "via: saving app state: %v", err)
+	}
+}
+
+// AppStateFile is an [AppStateStore] that keeps the snapshot as a JSON
+// object in the file at path. Saves write a temporary file beside it and
+// rename it into place, so a crash mid-save leaves the last snapshot
+// intact.
+func AppStateFile(path string) AppStateStore { return &appStateFile{path: path} }
+
+type appStateFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *appStateFile) LoadAppState(context.Context) (map[string]json.RawMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]json.RawMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := map[string]json.RawMessage{}
+	if strings.TrimSpace(string(b)) == "" {
+		return state, nil
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (f *appStateFile) SaveAppState(_ context.Context, state map[string]json.RawMessage) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
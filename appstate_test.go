@@ -0,0 +1,149 @@
+package via_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flagsPage struct {
+	Hits via.StateAppNum[int] `via:"hits"`
+	Beta via.StateApp[bool]   `via:"beta"`
+}
+
+func (p *flagsPage) Hit(ctx *via.Ctx) error {
+	p.Hits.Op(ctx).Inc()
+	return nil
+}
+
+func (p *flagsPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Textf("hits=%d beta=%v", p.Hits.Read(ctx), p.Beta.Read(ctx)))
+}
+
+// memAppState is an AppStateStore in memory.
+type memAppState struct {
+	mu    sync.Mutex
+	state map[string]json.RawMessage
+}
+
+func (m *memAppState) LoadAppState(context.Context) (map[string]json.RawMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := map[string]json.RawMessage{}
+	for k, v := range m.state {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (m *memAppState) SaveAppState(_ context.Context, state map[string]json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = state
+	return nil
+}
+
+func (m *memAppState) get(key string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return string(m.state[key])
+}
+
+func TestAppStateStore_survivesARestart(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := via.New(via.WithAppStateStore(via.AppStateFile(path), time.Hour))
+	server := vt.Serve(t, first)
+	via.Mount[flagsPage](first, "/")
+	tc := vt.NewClient(t, server, "/")
+	for range 3 {
+		require.Equal(t, 200, tc.Action("Hit").Fire())
+	}
+	require.NoError(t, first.Shutdown(context.Background()))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hits":3}`, string(b), "Shutdown saves a last snapshot")
+
+	second := via.New(via.WithAppStateStore(via.AppStateFile(path), time.Hour))
+	server = vt.Serve(t, second)
+	via.Mount[flagsPage](second, "/")
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Contains(c, vt.NewClient(t, server, "/").HTML(), "hits=3 beta=false")
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestAppStateStore_restoreKeepsValuesTheBackplaneHolds(t *testing.T) {
+	t.Parallel()
+	bp := via.InMemory()
+	_, err := bp.CAS(context.Background(), "val:hits", 0, []byte("10"))
+	require.NoError(t, err)
+	store := &memAppState{state: map[string]json.RawMessage{
+		"hits": json.RawMessage("2"),
+		"beta": json.RawMessage("true"),
+	}}
+
+	// The seeded cell carries no change hint, so the reconcile sweep is
+	// what pulls it into the pod.
+	app := via.New(via.WithBackplane(bp), via.WithReconcileInterval(10*time.Millisecond),
+		via.WithAppStateStore(store, time.Hour))
+	server := vt.Serve(t, app)
+	via.Mount[flagsPage](app, "/")
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Contains(c, vt.NewClient(t, server, "/").HTML(), "hits=10 beta=true")
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestAppStateStore_snapshotsEveryInterval(t *testing.T) {
+	t.Parallel()
+	clk := vt.NewClock(time.Unix(0, 0))
+	store := &memAppState{}
+	app := via.New(via.WithClock(clk), via.WithAppStateStore(store, time.Minute))
+	server := vt.Serve(t, app)
+	via.Mount[flagsPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Hit").Fire())
+
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool { return store.get("hits") == "1" }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestAppStateFile_roundTripsAndToleratesAMissingFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "state.json")
+	f := via.AppStateFile(path)
+
+	state, err := f.LoadAppState(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, state)
+
+	require.NoError(t, f.SaveAppState(context.Background(), map[string]json.RawMessage{"beta": json.RawMessage("true")}))
+	state, err = f.LoadAppState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "true", string(state["beta"]))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestWithAppStateStore_panicsAtNewOnNilStoreOrNegativeInterval(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, "via.WithAppStateStore: nil AppStateStore", func() {
+		via.New(via.WithAppStateStore(nil, 0))
+	})
+	assert.PanicsWithValue(t, "via.WithAppStateStore: interval must be >= 0, got -1s", func() {
+		via.New(via.WithAppStateStore(via.AppStateFile(filepath.Join(t.TempDir(), "s.json")), -time.Second))
+	})
+}
@@ -3,23 +3,58 @@ package via
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"sync"
+	"sync/atomic"
 )
 
 // valCell is the per-pod L1 cache for one value-shaped StateApp key. The
-// backplane Store cell valKey(key) is the source of truth; l1 holds the live
-// decoded value so reads stay zero-serialization. Exactly two writers touch
-// l1/l1Rev under mu: the local Update (sync, on its own pod) and the
-// changes-feed tailer (for writes from peer pods). decode turns a Store
-// snapshot's bytes back into the typed value (captured from the typed handle at
-// bindApp, since the App itself is type-erased).
+// backplane Store cell valKey(key) is the source of truth; cur holds the live
+// decoded value and its revision so reads stay zero-serialization. Exactly two
+// writers publish to cur, serialised by mu: the local Update (sync, on its own
+// pod) and the changes-feed tailer (for writes from peer pods). Readers never
+// take mu — a render of every tab on a hot dashboard key is one atomic load.
+// decode turns a Store snapshot's bytes back into the typed value (captured
+// from the typed handle at bindApp, since the App itself is type-erased).
 type valCell struct {
-	mu     sync.RWMutex
-	l1     any
-	l1Rev  Rev
+	mu     sync.Mutex
+	cur    atomic.Pointer[valSnap]
 	decode func([]byte) (any, error)
 }
 
+// valSnap is one published L1 version. Immutable once stored, so a reader
+// always sees a value paired with the revision it was written at.
+type valSnap struct {
+	v   any
+	rev Rev
+}
+
+// load returns the L1 value and its revision; nil, 0 before the first write.
+func (vc *valCell) load() (any, Rev) {
+	if s := vc.cur.Load(); s != nil {
+		return s.v, s.rev
+	}
+	return nil, 0
+}
+
+// rev is the L1 revision; 0 before the first write.
+func (vc *valCell) rev() Rev {
+	_, r := vc.load()
+	return r
+}
+
+// set publishes v at rev. Callers hold mu and have checked rev advances.
+func (vc *valCell) set(v any, rev Rev) { vc.cur.Store(&valSnap{v: v, rev: rev}) }
+
+// advance publishes v at rev unless L1 is already at or past it.
+func (vc *valCell) advance(v any, rev Rev) {
+	vc.mu.Lock()
+	if rev > vc.rev() {
+		vc.set(v, rev)
+	}
+	vc.mu.Unlock()
+}
+
 // changesKey is the shared EventLog feed carrying value-less Change hints; every
 // pod tails it and re-pulls the named Store cell to HEAD.
 const changesKey = "via.changes"
@@ -48,32 +83,39 @@ func (a *App) registerValCell(key string, decode func([]byte) (any, error)) {
 	a.valStatesMu.Lock()
 	if a.valStates[key] == nil {
 		a.valStates[key] = &valCell{decode: decode}
+		// Republish the read index. Keys register once per process, so
+		// the copy is paid at startup, not per read.
+		idx := maps.Clone(a.valStates)
+		a.valIndex.Store(&idx)
 	}
 	a.valStatesMu.Unlock()
 
 	a.valTailerOnce.Do(func() { a.startChangesTailer() })
 }
 
+// valCellFor looks key up in the copy-on-write index without locking; a
+// miss falls back to the registry itself.
 func (a *App) valCellFor(key string) *valCell {
+	if idx := a.valIndex.Load(); idx != nil {
+		if vc := (*idx)[key]; vc != nil {
+			return vc
+		}
+	}
 	a.valStatesMu.Lock()
 	defer a.valStatesMu.Unlock()
 	return a.valStates[key]
 }
 
 // valProjection returns the cached value for key, or ok=false if no cell is
-// registered. Read hits this — never the backplane — so it stays O(1) and
-// allocation-free.
+// registered. Read hits this — never the backplane — so it stays O(1),
+// allocation-free and lock-free.
 func (a *App) valProjection(key string) (any, bool) {
 	vc := a.valCellFor(key)
 	if vc == nil {
 		return nil, false
 	}
-	vc.mu.RLock()
-	defer vc.mu.RUnlock()
-	if vc.l1 == nil {
-		return nil, false
-	}
-	return vc.l1, true
+	v, _ := vc.load()
+	return v, v != nil
 }
 
 // reconcileValues re-pulls every registered value key to the Store HEAD. Run
@@ -168,10 +210,9 @@ func (a *App) reconcileKey(key string) {
 	}
 	vc.mu.Lock()
 	changed := false
-	if ok && storeRev > vc.l1Rev {
+	if ok && storeRev > vc.rev() {
 		if v, err := vc.decode(data); err == nil {
-			vc.l1 = v
-			vc.l1Rev = storeRev
+			vc.set(v, storeRev)
 			changed = true
 		}
 	}
@@ -270,21 +311,20 @@ func (a *App) applyChangeL1(c change) bool {
 	}
 	vc.mu.Lock()
 	defer vc.mu.Unlock()
-	if c.Rev <= vc.l1Rev {
+	if c.Rev <= vc.rev() {
 		return false
 	}
 	data, storeRev, ok, err := a.backplane.LoadSnapshot(a.backplaneCtx, valKey(c.Key))
 	if err != nil {
 		a.logWarn(nil, "via: backplane LoadSnapshot failed applying change for key %q: %v", c.Key, err)
 	}
-	if !ok || storeRev < c.Rev || storeRev <= vc.l1Rev {
+	if !ok || storeRev < c.Rev || storeRev <= vc.rev() {
 		return false
 	}
 	v, err := vc.decode(data)
 	if err != nil {
 		return false
 	}
-	vc.l1 = v
-	vc.l1Rev = storeRev
+	vc.set(v, storeRev)
 	return true
 }
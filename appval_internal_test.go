@@ -56,30 +56,30 @@ func TestApplyChangeDropsStaleReadsAndNeverRegressesL1(t *testing.T) {
 	// past — drop and wait.
 	stub.data, stub.rev, stub.ok = mustJSON(70), 3, true
 	app.applyChange(change{Key: "k", Rev: 5})
-	if vc.l1 != nil {
-		t.Fatalf("stale read (storeRev 3 < hint rev 5) must not apply; l1 = %v", vc.l1)
+	if vc.cur.Load() != nil {
+		t.Fatalf("stale read (storeRev 3 < hint rev 5) must not apply; l1 = %v", vc.cur.Load())
 	}
 
 	// The Store catches up to rev 5 → now the re-pull applies.
 	stub.data, stub.rev, stub.ok = mustJSON(77), 5, true
 	app.applyChange(change{Key: "k", Rev: 5})
-	if vc.l1 != 77 || vc.l1Rev != 5 {
-		t.Fatalf("caught-up read must apply; l1=%v l1Rev=%d, want 77/5", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 77 || rev != 5 {
+		t.Fatalf("caught-up read must apply; l1=%v l1Rev=%d, want 77/5", v, rev)
 	}
 
 	// An older, redelivered change (rev 3) must be ignored — L1 is monotone.
 	stub.data, stub.rev, stub.ok = mustJSON(33), 3, true
 	app.applyChange(change{Key: "k", Rev: 3})
-	if vc.l1 != 77 || vc.l1Rev != 5 {
-		t.Fatalf("older change must not regress L1; l1=%v l1Rev=%d, want 77/5", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 77 || rev != 5 {
+		t.Fatalf("older change must not regress L1; l1=%v l1Rev=%d, want 77/5", v, rev)
 	}
 
 	// A poison Store snapshot (undecodable) must leave the last good value
 	// intact rather than corrupt or panic the projection.
 	stub.data, stub.rev, stub.ok = []byte("not-an-int"), 9, true
 	app.applyChange(change{Key: "k", Rev: 9})
-	if vc.l1 != 77 || vc.l1Rev != 5 {
-		t.Fatalf("undecodable snapshot must keep the last good value; l1=%v l1Rev=%d, want 77/5", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 77 || rev != 5 {
+		t.Fatalf("undecodable snapshot must keep the last good value; l1=%v l1Rev=%d, want 77/5", v, rev)
 	}
 }
 
@@ -126,24 +126,64 @@ func TestReconcileKeyAdvancesOnlyForwardAndSurvivesPoison(t *testing.T) {
 	// Store ahead of L1 → advance.
 	stub.data, stub.rev, stub.ok = mustJSON(42), 4, true
 	app.reconcileKey("k")
-	if vc.l1 != 42 || vc.l1Rev != 4 {
-		t.Fatalf("reconcile must advance to Store HEAD; l1=%v l1Rev=%d, want 42/4", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 42 || rev != 4 {
+		t.Fatalf("reconcile must advance to Store HEAD; l1=%v l1Rev=%d, want 42/4", v, rev)
 	}
 
 	// Store not ahead (same rev) → no-op, no regression.
 	stub.data, stub.rev, stub.ok = mustJSON(999), 4, true
 	app.reconcileKey("k")
-	if vc.l1 != 42 || vc.l1Rev != 4 {
-		t.Fatalf("reconcile at an unchanged rev must be a no-op; l1=%v l1Rev=%d, want 42/4", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 42 || rev != 4 {
+		t.Fatalf("reconcile at an unchanged rev must be a no-op; l1=%v l1Rev=%d, want 42/4", v, rev)
 	}
 
 	// Poison snapshot at a higher rev → keep the last good value.
 	stub.data, stub.rev, stub.ok = []byte("nope"), 7, true
 	app.reconcileKey("k")
-	if vc.l1 != 42 || vc.l1Rev != 4 {
-		t.Fatalf("reconcile must survive a poison snapshot; l1=%v l1Rev=%d, want 42/4", vc.l1, vc.l1Rev)
+	if v, rev := vc.load(); v != 42 || rev != 4 {
+		t.Fatalf("reconcile must survive a poison snapshot; l1=%v l1Rev=%d, want 42/4", v, rev)
 	}
 
 	// Absent cell → no panic.
 	app.reconcileKey("missing")
 }
+
+// Reads of a hot key must never observe a value paired with another
+// write's revision, however writers interleave.
+func TestValCell_readsPairValueWithItsRevision(t *testing.T) {
+	vc := intCell()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 2000; i++ {
+			vc.advance(i, Rev(i))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if v, rev := vc.load(); v != nil && v.(int) != int(rev) {
+			t.Fatalf("value %v read with revision %d", v, rev)
+		}
+	}
+}
+
+// BenchmarkValProjectionParallel is the read side of a popular dashboard:
+// every tab's render reading one StateApp key at once.
+func BenchmarkValProjectionParallel(b *testing.B) {
+	app := &App{valStates: map[string]*valCell{}}
+	app.valTailerOnce.Do(func() {}) // no backplane to tail
+	app.registerValCell("k", intCell().decode)
+	app.valCellFor("k").advance(1, 1)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, ok := app.valProjection("k"); !ok {
+				b.Fatal("missing")
+			}
+		}
+	})
+}
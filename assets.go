@@ -0,0 +1,114 @@
+package via
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// assetPathPrefix is where RegisterAsset serves content-hashed files.
+const assetPathPrefix = "/via/assets/"
+
+// asset is one registered file, hashed and precompressed once so a
+// request only picks the encoding.
+type asset struct {
+	contentType string
+	body, gz    []byte
+	etag        string
+}
+
+// RegisterAsset serves body at a content-hashed URL and returns that URL,
+// base path included, ready for a src or href:
+//
+//	src := app.RegisterAsset("mylib/widget.js", widgetJS)
+//	app.AppendToFoot(h.Script(h.Type("module"), h.Src(src)))
+//
+// The hash changes whenever body does, so the response is cached as
+// immutable and a deploy that changes the file changes its URL. The
+// Content-Type comes from name's extension, and gzip is negotiated.
+// Registering the same name and body again returns the same URL. Like
+// [App.HandleFunc] it is for boot: call it from a plugin's Register or
+// before the server starts. Panics on a name that isn't a clean
+// relative slash path.
+func (a *App) RegisterAsset(name string, body []byte) string {
+	if name == "" || path.Clean(name) != name || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "../") {
+		panic(fmt.Sprintf("via.RegisterAsset: bad asset name %q", name))
+	}
+	urlPath := assetURLPath(name, body)
+	a.routesMu.Lock()
+	_, dup := a.routes["GET "+urlPath]
+	a.routesMu.Unlock()
+	if !dup {
+		a.HandleFunc("GET "+urlPath, newAsset(name, body).serveImmutable)
+	}
+	return a.cfg.basePath + urlPath
+}
+
+// assetURLPath is the content-hashed path body is served at as name.
+func assetURLPath(name string, body []byte) string {
+	return assetPathPrefix + assetHash(body) + "/" + name
+}
+
+func assetHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:8])
+}
+
+// newAsset prepares body for serving as name.
+func newAsset(name string, body []byte) *asset {
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &asset{contentType: ct, body: body, gz: gzipBytes(body), etag: `"` + assetHash(body) + `"`}
+}
+
+func (as *asset) serveImmutable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	as.serve(w, r)
+}
+
+// serveRevalidated serves as at a fixed URL: cached, but checked with
+// the server on each use.
+func (as *asset) serveRevalidated(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-cache")
+	as.serve(w, r)
+}
+
+func (as *asset) serve(w http.ResponseWriter, r *http.Request) {
+	hdr := w.Header()
+	hdr.Set("Content-Type", as.contentType)
+	hdr.Set("Vary", "Accept-Encoding")
+	gz := strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+	// The gzip representation gets its own ETag so a cache can't answer
+	// one encoding's revalidation with the other's body.
+	etag := as.etag
+	if gz {
+		etag = strings.TrimSuffix(etag, `"`) + `-gz"`
+	}
+	hdr.Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if gz {
+		hdr.Set("Content-Encoding", "gzip")
+		_, _ = w.Write(as.gz)
+		return
+	}
+	_, _ = w.Write(as.body)
+}
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(b)
+	_ = zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,94 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var datastarSrc = regexp.MustCompile(`src="(/via/assets/[0-9a-f]{16}/datastar\.js)"`)
+
+func fetch(t *testing.T, url string, hdr map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	// A bare transport, so gzip isn't negotiated and decoded behind our back.
+	resp, err := (&http.Transport{DisableCompression: true}).RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestDatastar_servedFromAHashedImmutableURL(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[pluginHostPage](app, "/")
+	server := vt.Serve(t, app)
+
+	m := datastarSrc.FindStringSubmatch(vt.NewClient(t, server, "/").HTML())
+	require.NotNil(t, m, "pages must load Datastar from its hashed URL")
+
+	resp, body := fetch(t, server.URL+m[1], nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=31536000, immutable", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.NotEmpty(t, body)
+
+	resp, _ = fetch(t, server.URL+m[1], map[string]string{"Accept-Encoding": "gzip"})
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	resp, _ = fetch(t, server.URL+m[1], map[string]string{
+		"Accept-Encoding": "gzip", "If-None-Match": resp.Header.Get("ETag"),
+	})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, legacy := fetch(t, server.URL+"/_datastar.js", nil)
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"), "the fixed URL must revalidate")
+	assert.Equal(t, body, legacy)
+}
+
+func TestWithDatastarJS_servesTheCustomBuild(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithDatastarJS([]byte("/* custom datastar */")))
+	via.Mount[pluginHostPage](app, "/")
+	server := vt.Serve(t, app)
+
+	m := datastarSrc.FindStringSubmatch(vt.NewClient(t, server, "/").HTML())
+	require.NotNil(t, m)
+	_, body := fetch(t, server.URL+m[1], nil)
+	assert.Equal(t, "/* custom datastar */", body)
+	_, body = fetch(t, server.URL+"/_datastar.js", nil)
+	assert.Equal(t, "/* custom datastar */", body)
+}
+
+func TestRegisterAsset_servesContentHashedFiles(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"))
+	server := vt.Serve(t, app)
+
+	src := app.RegisterAsset("widgets/chart.css", []byte("p{color:red}"))
+	assert.Regexp(t, `^/app/via/assets/[0-9a-f]{16}/widgets/chart\.css$`, src)
+	assert.Equal(t, src, app.RegisterAsset("widgets/chart.css", []byte("p{color:red}")),
+		"the same file registers once")
+	assert.NotEqual(t, src, app.RegisterAsset("widgets/chart.css", []byte("p{color:blue}")),
+		"new content, new URL")
+
+	resp, body := fetch(t, server.URL+src, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/css; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "p{color:red}", body)
+
+	for _, bad := range []string{"", "/abs.js", "../up.js", "a//b.js"} {
+		assert.Panics(t, func() { app.RegisterAsset(bad, nil) }, bad)
+	}
+}
@@ -0,0 +1,71 @@
+package via
+
+import "time"
+
+// AuditOutcome is how an audited action ended.
+type AuditOutcome string
+
+const (
+	// AuditOK: the handler returned without error.
+	AuditOK AuditOutcome = "ok"
+	// AuditError: the handler returned an error, or its input was rejected
+	// before it ran (WithStrictDecode).
+	AuditError AuditOutcome = "error"
+	// AuditPanic: the handler panicked.
+	AuditPanic AuditOutcome = "panic"
+)
+
+// AuditEvent records one action execution for [WithAuditHook].
+type AuditEvent struct {
+	Time   time.Time // when the action POST was received
+	TabID  string    // tab id, as Ctx.ID
+	Route  string    // mounted pattern of the tab's composition
+	Action string    // action method name
+	// SessionID is the same opaque session digest as ContextInfo.SessionID,
+	// never the session cookie. Empty for a tab without a session.
+	SessionID string
+	// User is who the session was signed in as when the action began, so
+	// a sign-in action is attributed to "" and a sign-out to the user
+	// leaving. Empty when signed out.
+	User     string
+	Duration time.Duration // POST receipt to handler return, as via.action.latency
+	Outcome  AuditOutcome
+	Err      error // the handler's error or the recovered panic; nil on AuditOK
+}
+
+// WithAuditHook calls fn once for every action that runs, after its
+// handler returns — with who ran what, on which tab, for how long, and
+// how it ended. Requests refused before the handler is reached (an
+// unknown tab, a failed WithActionSigning check, a group guard such as
+// RequireAuth) don't run an action and aren't reported here.
+//
+// fn runs synchronously on the action's goroutine, so the action's
+// response waits for it: hand events to a buffered writer rather than
+// doing slow I/O inline.
+func WithAuditHook(fn func(AuditEvent)) Option {
+	return func(c *config) { c.auditHook = fn }
+}
+
+// audit reports one action execution to the configured hook.
+func (a *App) audit(ctx *Ctx, action, user string, started time.Time, err error, panicked bool) {
+	ev := AuditEvent{
+		Time:     started,
+		TabID:    ctx.id,
+		Route:    ctx.desc.route,
+		Action:   action,
+		User:     user,
+		Duration: time.Since(started),
+		Outcome:  AuditOK,
+		Err:      err,
+	}
+	if sess := ctx.session.Load(); sess != nil {
+		ev.SessionID = sessionDigest(sess.id)
+	}
+	switch {
+	case panicked:
+		ev.Outcome = AuditPanic
+	case err != nil:
+		ev.Outcome = AuditError
+	}
+	a.cfg.auditHook(ev)
+}
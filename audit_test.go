@@ -0,0 +1,104 @@
+package via_test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditedPage struct{}
+
+func (p *auditedPage) Save(ctx *via.Ctx)       {}
+func (p *auditedPage) Fail(ctx *via.Ctx) error { return errors.New("nope") }
+func (p *auditedPage) Crash(ctx *via.Ctx)      { panic("boom") }
+func (p *auditedPage) In(ctx *via.Ctx)         { ctx.SignIn("alice") }
+func (p *auditedPage) View(ctx *via.CtxR) h.H  { return h.Div() }
+
+// auditLog collects the events WithAuditHook reports.
+type auditLog struct {
+	mu     sync.Mutex
+	events []via.AuditEvent
+}
+
+func (l *auditLog) record(ev via.AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, ev)
+}
+
+func (l *auditLog) last(t *testing.T) via.AuditEvent {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	require.NotEmpty(t, l.events)
+	return l.events[len(l.events)-1]
+}
+
+func auditedClient(t *testing.T) (*vt.Client, *auditLog) {
+	t.Helper()
+	log := &auditLog{}
+	app := via.New(via.WithAuditHook(log.record))
+	server := vt.Serve(t, app)
+	via.Mount[auditedPage](app, "/audited")
+	return vt.NewClient(t, server, "/audited"), log
+}
+
+func TestAuditHook_recordsEachAction(t *testing.T) {
+	t.Parallel()
+	tc, log := auditedClient(t)
+
+	before := time.Now()
+	require.Equal(t, http.StatusOK, tc.Action("Save").Fire())
+	ev := log.last(t)
+	assert.Equal(t, "Save", ev.Action)
+	assert.Equal(t, tc.TabID(), ev.TabID)
+	assert.Equal(t, "/audited", ev.Route)
+	assert.NotEmpty(t, ev.SessionID)
+	assert.Equal(t, via.AuditOK, ev.Outcome)
+	assert.NoError(t, ev.Err)
+	assert.False(t, ev.Time.Before(before))
+	assert.Positive(t, ev.Duration)
+}
+
+func TestAuditHook_recordsErrorsAndPanics(t *testing.T) {
+	t.Parallel()
+	tc, log := auditedClient(t)
+
+	tc.Action("Fail").Fire()
+	ev := log.last(t)
+	assert.Equal(t, via.AuditError, ev.Outcome)
+	assert.EqualError(t, ev.Err, "nope")
+
+	tc.Action("Crash").Fire()
+	ev = log.last(t)
+	assert.Equal(t, via.AuditPanic, ev.Outcome)
+	assert.EqualError(t, ev.Err, "panic: boom")
+}
+
+func TestAuditHook_attributesTheActionToTheUserWhoRanIt(t *testing.T) {
+	t.Parallel()
+	tc, log := auditedClient(t)
+
+	tc.Action("In").Fire()
+	assert.Empty(t, log.last(t).User, "the sign-in ran signed out")
+	tc.Action("Save").Fire()
+	assert.Equal(t, "alice", log.last(t).User)
+}
+
+func TestAuditHook_skipsRequestsThatRunNoAction(t *testing.T) {
+	t.Parallel()
+	tc, log := auditedClient(t)
+
+	assert.Equal(t, http.StatusNotFound, tc.Action("Missing").Fire())
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	assert.Empty(t, log.events)
+}
@@ -0,0 +1,69 @@
+package via
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// cleanBasePath normalises a WithBasePath value to "" or "/seg[/seg…]"
+// with no trailing slash, so it can be prepended to any "/_…" path.
+func cleanBasePath(p string) string {
+	if p == "" {
+		return ""
+	}
+	if !strings.HasPrefix(p, "/") || strings.ContainsAny(p, "?#") {
+		panic(fmt.Sprintf("via.WithBasePath: must be a path starting with \"/\", got %q", p))
+	}
+	return strings.TrimRight(p, "/")
+}
+
+// BasePath returns the prefix set by [WithBasePath] ("" when none), for
+// plugins and handlers building absolute URLs into the app.
+func (a *App) BasePath() string { return a.cfg.basePath }
+
+// trimBasePath returns p relative to the base path, and false when p
+// doesn't sit under it.
+func (a *App) trimBasePath(p string) (string, bool) {
+	base := a.cfg.basePath
+	if base == "" || (p != base && !strings.HasPrefix(p, base+"/")) {
+		return p, false
+	}
+	if p = p[len(base):]; p == "" {
+		p = "/"
+	}
+	return p, true
+}
+
+// stripBasePath removes the base path from r's URL so routes match as
+// registered. A request without the prefix — one a proxy already stripped
+// — passes through untouched. Mirrors http.StripPrefix's shallow copy.
+func (a *App) stripBasePath(r *http.Request) *http.Request {
+	p, ok := a.trimBasePath(r.URL.Path)
+	if !ok {
+		return r
+	}
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = p
+	r2.URL.RawPath = ""
+	return r2
+}
+
+// basePathInit returns the data-init expression that prefixes action
+// POSTs with base. Action URLs are baked as "/_action/<method>" by the
+// on package, which renders without an App (and caches the result), so
+// they are rewritten in the browser instead: Datastar resolves every
+// @post against document.baseURI and hands fetch the absolute URL, which
+// is matched here by path. Installed once per document, ahead of the SSE
+// connect.
+func basePathInit(base string) string {
+	return `(()=>{if(window.__viaBase!=null)return;var B=window.__viaBase='` + template.JSEscapeString(base) + `',f=window.fetch;` +
+		`window.fetch=function(u,o){if(typeof u==='string'){var x=new URL(u,document.baseURI);` +
+		`if(x.origin===location.origin&&x.pathname.startsWith('/_action/')){x.pathname=B+x.pathname;u=x.href}}` +
+		`return f.call(this,u,o)}})()`
+}
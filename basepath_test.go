@@ -0,0 +1,88 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasePath_prefixesGeneratedURLs(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app/"))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/")
+	assert.Equal(t, "/app", app.BasePath())
+
+	body := getBody(t, server, "/app/")
+	assert.Regexp(t, `src="/app/via/assets/[0-9a-f]{16}/datastar\.js"`, body)
+	assert.Contains(t, body, "@get(&#39;/app/_sse&#39;)")
+	assert.Contains(t, body, "sendBeacon(&#39;/app/_sse/close&#39;")
+	assert.Contains(t, body, "window.__viaBase=")
+}
+
+func TestBasePath_servesPrefixedAndStrippedRequests(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"))
+	server := vt.Serve(t, app)
+	via.Mount[ledgerPage](app, "/ledger")
+
+	// A proxy that forwards the prefix...
+	httpc := jarClient(t)
+	resp, err := httpc.Get(server.URL + "/app/ledger")
+	require.NoError(t, err)
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	tab := vt.TabIDFromHTML(string(page))
+	require.NotEmpty(t, tab)
+	resp, err = httpc.Post(server.URL+"/app/_action/Withdraw", "application/json",
+		strings.NewReader(`{"via_tab":"`+tab+`"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = httpc.Get(server.URL + "/app/_datastar.js")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// ...and one that strips it both reach the same routes.
+	stripped := vt.NewClient(t, server, "/ledger")
+	assert.Equal(t, http.StatusOK, stripped.Action("Withdraw").Fire())
+}
+
+func TestWithBasePath_validates(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, `via.WithBasePath: must be a path starting with "/", got "app"`, func() {
+		via.New(via.WithBasePath("app"))
+	})
+	assert.Empty(t, via.New(via.WithBasePath("/")).BasePath())
+}
+
+type prefixedAssetPage struct{}
+
+func (p *prefixedAssetPage) View(ctx *via.CtxR) h.H {
+	return h.Link(h.Rel("stylesheet"), h.Href("/app/assets/site.css"))
+}
+
+func TestBasePath_exportRootsAssetsAtTheBase(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"))
+	via.Mount[prefixedAssetPage](app, "/home")
+	app.HandleStatic("/assets/", fstest.MapFS{"site.css": {Data: []byte("body{}")}})
+
+	dir := t.TempDir()
+	require.NoError(t, app.Export(dir, "/home"))
+	css, err := os.ReadFile(filepath.Join(dir, "assets", "site.css"))
+	require.NoError(t, err)
+	assert.Equal(t, "body{}", string(css))
+}
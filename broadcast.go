@@ -3,6 +3,7 @@ package via
 import (
 	"context"
 	"encoding/json"
+	"slices"
 )
 
 // broadcastKey is the shared EventLog feed carrying broadcast payloads across
@@ -12,8 +13,9 @@ import (
 const broadcastKey = "via.broadcast"
 
 const (
-	bcScript  = "script"
-	bcSignals = "signals"
+	bcScript     = "script"
+	bcSignals    = "signals"
+	bcInvalidate = "invalidate"
 )
 
 // broadcastRecord is one cross-pod broadcast, carried whole on the feed.
@@ -21,6 +23,10 @@ type broadcastRecord struct {
 	Kind    string         `json:"kind"`
 	Script  string         `json:"script,omitempty"`
 	Signals map[string]any `json:"signals,omitempty"`
+	Cursor  string         `json:"cursor,omitempty"`
+	// Where narrows a script/signals broadcast to tabs carrying one label;
+	// nil reaches every tab. See App.BroadcastWhere.
+	Where *labelMatch `json:"where,omitempty"`
 }
 
 // Broadcast queues a JavaScript snippet on every currently-live tab's
@@ -94,6 +100,59 @@ func (a *App) BroadcastSignals(values map[string]any) int {
 	return a.dispatchBroadcast(broadcastRecord{Kind: bcSignals, Signals: values})
 }
 
+// BroadcastFunc runs fn on every live tab whose [ContextInfo] satisfies
+// match — the general form of [App.BroadcastWhere] for admin messages,
+// cache invalidation, or logging one user out everywhere:
+//
+//	app.BroadcastFunc(
+//	    func(c via.ContextInfo) bool { return c.Labels["user"] == uid },
+//	    func(ctx *via.Ctx) { ctx.Redirect("/login") },
+//	)
+//
+// Each fn runs on its own goroutine under the tab's action lock, like a
+// Stream tick: its writes don't race a concurrent action and are flushed
+// to the browser when it returns, and calling BroadcastFunc from inside an
+// action cannot deadlock on the caller's own tab. match runs synchronously
+// on a snapshot of the registry, so it must not block.
+//
+// Unlike the rest of the Broadcast family, BroadcastFunc is always
+// pod-local — fn cannot travel over a backplane. Returns the number of
+// tabs matched; a nil match or fn is a no-op.
+func (a *App) BroadcastFunc(match func(ContextInfo) bool, fn func(*Ctx)) int {
+	if match == nil || fn == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range a.snapshotContexts() {
+		if !match(c.info()) {
+			continue
+		}
+		n++
+		go runBroadcastFunc(c, fn)
+	}
+	return n
+}
+
+// runBroadcastFunc is streamTick's exclusivity for a BroadcastFunc
+// callback; a tab disposed since the snapshot is skipped.
+func runBroadcastFunc(ctx *Ctx, fn func(*Ctx)) {
+	ctx.actionMu.Lock()
+	defer ctx.actionMu.Unlock()
+	if ctx.Disposed() {
+		return
+	}
+	ctx.silent.Store(false)
+	defer func() {
+		if ctx.silent.Load() {
+			ctx.discardDirty()
+			return
+		}
+		flushDirty(ctx)
+	}()
+	defer recoverLog(ctx, "BroadcastFunc callback")
+	fn(ctx)
+}
+
 // dispatchBroadcast routes one record: when clustered it Appends to the shared
 // feed and lets the tailer apply on EVERY pod (including this one — append-only,
 // never also applied directly, so the originating pod sees it exactly once);
@@ -105,16 +164,25 @@ func (a *App) dispatchBroadcast(rec broadcastRecord) int {
 				a.logWarn(nil, "via: backplane Append failed dispatching broadcast: %v", err)
 			}
 		}
-		return len(a.snapshotContexts())
+		return len(a.broadcastTargets(rec.Where))
 	}
 	return a.applyBroadcast(rec)
 }
 
+// broadcastTargets snapshots the live tabs a broadcast reaches on this pod.
+func (a *App) broadcastTargets(where *labelMatch) []*Ctx {
+	ctxs := a.snapshotContexts()
+	if where == nil {
+		return ctxs
+	}
+	return slices.DeleteFunc(ctxs, func(c *Ctx) bool { return !where.matches(c) })
+}
+
 // applyBroadcast pushes one record onto every live tab's patch queue on THIS
 // pod. It is the single apply path — used directly in single-pod mode and by
 // the cross-pod tailer — so the two never diverge. Returns the tab count.
 func (a *App) applyBroadcast(rec broadcastRecord) int {
-	ctxs := a.snapshotContexts()
+	ctxs := a.broadcastTargets(rec.Where)
 	switch rec.Kind {
 	case bcScript:
 		for _, c := range ctxs {
@@ -124,6 +192,8 @@ func (a *App) applyBroadcast(rec broadcastRecord) int {
 		for _, c := range ctxs {
 			c.patch.Signals(rec.Signals)
 		}
+	case bcInvalidate:
+		a.broadcastRender(nil, nil, cursorKey(rec.Cursor))
 	}
 	return len(ctxs)
 }
@@ -177,16 +247,10 @@ func (a *App) broadcastRender(skip *Ctx, sess *session, key string) {
 	}
 }
 
-// snapshotContexts copies every live *Ctx into a slice under the
-// registry RLock, so callers can iterate without holding the lock —
-// the per-Ctx work (enqueueScript, Patch.Signals) takes its own locks
-// and we don't want the registry lock to gate that.
+// snapshotContexts copies every live *Ctx into a slice, so callers can
+// iterate without holding a registry lock — the per-Ctx work
+// (enqueueScript, Patch.Signals) takes its own locks and we don't want the
+// registry to gate that.
 func (a *App) snapshotContexts() []*Ctx {
-	a.contextRegistryMu.RLock()
-	ctxs := make([]*Ctx, 0, len(a.contextRegistry))
-	for _, c := range a.contextRegistry {
-		ctxs = append(ctxs, c)
-	}
-	a.contextRegistryMu.RUnlock()
-	return ctxs
+	return a.contexts.all()
 }
@@ -303,3 +303,49 @@ func TestBroadcast_staysPodLocalWithoutASharedBackplane(t *testing.T) {
 	assert.NotContains(t, got, msg,
 		"a broadcast must not reach an unrelated App when no backplane is shared")
 }
+
+func TestBroadcastFunc_runsOnlyOnMatchingTabs(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[labelPage](app, "/")
+	via.Mount[broadcastPage](app, "/other")
+
+	acme := vt.NewClient(t, server, "/?tenant=acme")
+	acmeFrames, cancelAcme := acme.SSEReady()
+	defer cancelAcme()
+	globex := vt.NewClient(t, server, "/?tenant=globex")
+	globexFrames, cancelGlobex := globex.SSEReady()
+	defer cancelGlobex()
+	otherFrames, cancelOther := openSSEStreams(t, server, "/other", 1)
+	defer cancelOther()
+
+	var seen []via.ContextInfo
+	n := app.BroadcastFunc(
+		func(c via.ContextInfo) bool {
+			seen = append(seen, c)
+			return c.Route == "/" && c.Labels["tenant"] == "acme"
+		},
+		func(ctx *via.Ctx) { ctx.Notify("signed out elsewhere") },
+	)
+
+	assert.Equal(t, 1, n)
+	assert.Len(t, seen, 3, "match sees every live tab")
+	vt.AwaitFrame(t, acmeFrames, 2*time.Second, "signed out elsewhere")
+	assert.NotContains(t, drainFor(globexFrames, 200*time.Millisecond), "signed out elsewhere")
+	assert.NotContains(t, drainFor(otherFrames[0], 50*time.Millisecond), "signed out elsewhere")
+}
+
+func TestBroadcastFunc_nilArgumentsAreNoOp(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[broadcastPage](app, "/")
+	_, cancel := openSSEStreams(t, server, "/", 1)
+	defer cancel()
+
+	assert.Zero(t, app.BroadcastFunc(nil, func(*via.Ctx) {}))
+	assert.Zero(t, app.BroadcastFunc(func(via.ContextInfo) bool { return true }, nil))
+}
@@ -66,6 +66,18 @@ echo "== CI: Run go vet =="
 go vet ./...
 echo "OK: go vet passed"
 
+echo "== CI: Check embedded scripts =="
+# Scripts a package embeds and splices into markup (on/sortable.js) are
+# kept as .js files so a syntax error fails here, not in a user's browser.
+if command -v node >/dev/null 2>&1; then
+  for f in on/*.js; do
+    node --check "$f"
+  done
+  echo "OK: embedded scripts parse"
+else
+  echo "SKIP: node not installed"
+fi
+
 echo "== CI: golangci-lint =="
 if ! command -v golangci-lint >/dev/null 2>&1; then
   go install "github.com/golangci/golangci-lint/v2/cmd/golangci-lint@${GOLANGCI_VERSION}"
@@ -143,6 +155,18 @@ echo "OK: public API compatible with committed baselines"
 echo "== CI: Run tests =="
 go test -race ./... 2>&1 | grep -v '\[no test files\]'
 
+echo "== CI: SQLite backplane tests (viasqlite) =="
+# viasqlite is its own module so its test-only SQLite driver (cgo) stays
+# out of the core's dependency graph; the conformance suite must run
+# against a real database, never skip.
+(cd viasqlite && go test -race ./...)
+
+echo "== CI: Example smoke tests =="
+# Boots every app under internal/examples in-process, loads its pages and
+# fires each action they bind (internal/exampletest). Behind a build tag so
+# the plain `go test ./...` stays fast.
+go test -race -tags examples ./internal/examples/... 2>&1 | grep -v '\[no test files\]'
+
 echo "== CI: Allocation gates =="
 # Bench output looks like:
 #   BenchmarkCounterRender-20    1000   95012 ns/op   29200 B/op   206 allocs/op
@@ -0,0 +1,60 @@
+package via
+
+import "net/http"
+
+// ClientInfo is what the browser behind a tab sent with its latest
+// request — the page load, then each action — as returned by
+// [Ctx.Client]. Unlike [Ctx.Request] it outlives the request, so a View
+// re-rendered by a broadcast or a Stream callback can still adapt to the
+// user agent or look at the address.
+type ClientInfo struct {
+	// IP is the client's address: the one a trusted proxy reported
+	// (WithTrustedProxies), else the peer's. See [ClientIP].
+	IP             string
+	RemoteAddr     string // as the request arrived, after proxy rewriting
+	UserAgent      string
+	AcceptLanguage string
+	// Header is a copy of the request headers, without Cookie: read
+	// cookies with Ctx.Cookie while a request is in flight.
+	Header http.Header
+}
+
+// Client returns what the tab's browser sent with its latest page load or
+// action; the zero ClientInfo for a tab that hasn't had one.
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H {
+//	    if strings.Contains(ctx.Client().UserAgent, "Mobile") {
+//	        return p.compact(ctx)
+//	    }
+//	    return p.full(ctx)
+//	}
+//
+// Everything in it comes from the client, so treat it as input: don't
+// authorize on the User-Agent. Safe from any goroutine.
+func (ctx *Ctx) Client() ClientInfo {
+	if ctx == nil {
+		return ClientInfo{}
+	}
+	ctx.mu.Lock()
+	header, addr := ctx.clientHeader, ctx.clientAddr
+	ctx.mu.Unlock()
+	if header == nil {
+		return ClientInfo{}
+	}
+	header = header.Clone()
+	header.Del("Cookie")
+	ip := ""
+	if a, ok := remoteIP(addr); ok {
+		ip = a.String()
+	}
+	return ClientInfo{
+		IP:             ip,
+		RemoteAddr:     addr,
+		UserAgent:      header.Get("User-Agent"),
+		AcceptLanguage: header.Get("Accept-Language"),
+		Header:         header,
+	}
+}
+
+// Client returns the tab's client metadata. See [Ctx.Client].
+func (r *CtxR) Client() ClientInfo { return r.rctx().Client() }
@@ -0,0 +1,77 @@
+package via_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientPage struct {
+	Hits via.StateAppNum[int]
+}
+
+func (p *clientPage) Hit(ctx *via.Ctx) error {
+	p.Hits.Op(ctx).Inc()
+	return nil
+}
+
+func (p *clientPage) View(ctx *via.CtxR) h.H {
+	c := ctx.Client()
+	return h.Div(
+		h.P(h.ID("ua"), h.Textf("ua=%s", c.UserAgent)),
+		h.P(h.ID("ip"), h.Textf("ip=%s", c.IP)),
+		h.P(h.ID("lang"), h.Textf("lang=%s", c.AcceptLanguage)),
+		h.P(h.ID("cookie"), h.Textf("cookie=%q", c.Header.Get("Cookie"))),
+		h.P(h.Textf("hits=%d", p.Hits.Read(ctx))),
+		h.Button(h.Text("hit"), on.Click(p.Hit)),
+	)
+}
+
+func TestCtx_Client_seesThePageLoad(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[clientPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	assert.Contains(t, tc.HTML(), "ua=Go-http-client/1.1")
+	assert.Contains(t, tc.HTML(), "ip=127.0.0.1")
+	assert.Contains(t, tc.HTML(), `cookie=&#34;&#34;`, "the session cookie must not be handed out")
+}
+
+func TestCtx_Client_followsEachActionIntoLaterRenders(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[clientPage](app, "/")
+	srv := vt.Serve(t, app)
+	tc := vt.NewClient(t, srv, "/")
+	other := vt.NewClient(t, srv, "/")
+
+	require.Equal(t, 200, tc.Action("Hit").
+		WithHeader("User-Agent", "alpha/1.0").
+		WithHeader("Accept-Language", "de-DE").
+		Fire())
+	tc.WaitForText("ua=alpha/1.0", 2*time.Second)
+
+	// The other tab's action re-renders this one by broadcast, with no
+	// request of its own in flight: it still sees its last action's.
+	require.Equal(t, 200, other.Action("Hit").WithHeader("User-Agent", "beta/2.0").Fire())
+	got := tc.WaitForText("hits=2", 2*time.Second)
+	assert.NotContains(t, got, "beta/2.0")
+	assert.Contains(t, got, "lang=de-DE")
+}
+
+func TestCtx_Client_reportsTheForwardedClientBehindATrustedProxy(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithTrustedProxies("127.0.0.1"))
+	via.Mount[clientPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	require.Equal(t, 200, tc.Action("Hit").WithHeader("X-Forwarded-For", "203.0.113.9").Fire())
+	tc.WaitForText("ip=203.0.113.9", 2*time.Second)
+}
@@ -0,0 +1,43 @@
+package via
+
+import "time"
+
+// Clock is the source of time behind Stream tickers and the session and
+// tab idle-TTL sweeps. The default reads the wall clock; tests swap in a
+// fake with [WithClock] (vt.NewClock) to drive tickers and expiry without
+// sleeping. Network deadlines, heartbeats and action latency metrics
+// always use real time.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) ClockTicker
+}
+
+// ClockTicker is the subset of *time.Ticker a [Clock] hands out.
+type ClockTicker interface {
+	Chan() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// realClock is the default Clock: the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) ClockTicker { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) Chan() <-chan time.Time { return t.C }
+
+// Clock returns the app's Clock — the one [WithClock] set, or the wall
+// clock — for plugins that keep deadlines of their own.
+func (a *App) Clock() Clock {
+	if a == nil || a.cfg.clock == nil {
+		return realClock{}
+	}
+	return a.cfg.clock
+}
+
+// now reads the app's clock.
+func (a *App) now() time.Time { return a.Clock().Now() }
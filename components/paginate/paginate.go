@@ -0,0 +1,190 @@
+// Package paginate keeps a list's page in Via state and renders the
+// controls that move it: numbered pager buttons for paged lists, and an
+// infinite-scroll sentinel that loads the next page as it comes into
+// view.
+//
+// A Pager is a child composition. Forward one action to [Pager.Handle]
+// and render with that action:
+//
+//	type Orders struct {
+//	    Pages *paginate.Pager
+//	}
+//
+//	func (p *Orders) OnInit(ctx *via.Ctx) error { p.Pages.SetSize(25); return nil }
+//
+//	func (p *Orders) Paginate(ctx *via.Ctx) error { return p.Pages.Handle(ctx) }
+//
+//	func (p *Orders) View(ctx *via.CtxR) h.H {
+//	    orders, total := store.Orders(p.Pages.Offset(ctx), p.Pages.Size())
+//	    return h.Div(
+//	        h.Each(orders, orderRow),
+//	        p.Pages.View(ctx, total, p.Paginate),
+//	    )
+//	}
+//
+// For infinite scroll, render the rows loaded so far — the first
+// [Pager.Loaded] — and end the list with [Pager.More]; each time the
+// sentinel scrolls into view the next page is added.
+package paginate
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+)
+
+// DefaultSize is the page size until SetSize is called.
+const DefaultSize = 20
+
+// Action arguments the controls post to the forwarding action.
+const (
+	argOp   = "paginate"
+	argPage = "page"
+)
+
+// Pager is the current page of one list. The zero value, as the
+// framework allocates it, pages by DefaultSize.
+type Pager struct {
+	// Page is the zero-based current page; for infinite scroll, the last
+	// page loaded.
+	Page via.StateTab[int]
+	// Changed reports the new page each time Handle moves it, e.g. to
+	// fetch and append the next batch in an infinite-scroll list.
+	Changed via.Emitter[int]
+
+	size int
+}
+
+// SetSize sets the rows per page. Call it from the parent's OnInit.
+func (p *Pager) SetSize(n int) {
+	if n <= 0 {
+		panic("paginate.SetSize: size must be positive")
+	}
+	p.size = n
+}
+
+// Size is the rows per page.
+func (p *Pager) Size() int {
+	if p.size == 0 {
+		return DefaultSize
+	}
+	return p.size
+}
+
+// Pages is the number of pages total rows fill; at least 1, so an empty
+// list still shows "page 1 of 1".
+func (p *Pager) Pages(total int) int {
+	return max(1, (total+p.Size()-1)/p.Size())
+}
+
+// Offset is the index of the current page's first row — the OFFSET of a
+// paged query.
+func (p *Pager) Offset(ctx *via.CtxR) int { return p.Page.Read(ctx) * p.Size() }
+
+// Loaded is the number of rows an infinite-scroll list shows: every page
+// up to and including the current one — the LIMIT of a query from the
+// top.
+func (p *Pager) Loaded(ctx *via.CtxR) int { return (p.Page.Read(ctx) + 1) * p.Size() }
+
+// Slice returns the current page of items, for a list held in memory.
+// A page past the end yields the last page.
+func Slice[T any](ctx *via.CtxR, p *Pager, items []T) []T {
+	page := min(p.Page.Read(ctx), p.Pages(len(items))-1)
+	start := page * p.Size()
+	return items[start:min(start+p.Size(), len(items))]
+}
+
+// Reset moves back to the first page, e.g. when a filter changes which
+// rows the list holds. It doesn't emit Changed.
+func (p *Pager) Reset(ctx *via.Ctx) { p.Page.Write(ctx, 0) }
+
+// Handle moves the page for the control that fired the forwarding
+// action, then emits Changed. It returns an error for a request no
+// rendered control sends.
+func (p *Pager) Handle(ctx *via.Ctx) error {
+	page := p.Page.Read(ctx)
+	switch ctx.EventArg(argOp) {
+	case "page":
+		n, err := strconv.Atoi(ctx.EventArg(argPage))
+		if err != nil || n < 0 {
+			return errors.New("paginate: bad page")
+		}
+		page = n
+	case "more":
+		n, err := strconv.Atoi(ctx.EventArg(argPage))
+		if err != nil || n < 0 {
+			return errors.New("paginate: bad page")
+		}
+		if n <= page {
+			return nil // a repeat fire for a page already loaded
+		}
+		page = n
+	default:
+		return errors.New("paginate: unknown event " + strconv.Quote(ctx.EventArg(argOp)))
+	}
+	p.Page.Write(ctx, page)
+	p.Changed.Emit(ctx, page)
+	return nil
+}
+
+// View renders the pager controls for a list of total rows: previous and
+// next buttons around the page numbers, the first, last and two either
+// side of the current page, with gaps elided. It renders nothing when
+// everything fits on one page. event is the parent's action that
+// forwards to Handle.
+func (p *Pager) View(ctx *via.CtxR, total int, event func(*via.Ctx) error) h.H {
+	pages := p.Pages(total)
+	if pages <= 1 {
+		return nil
+	}
+	page := min(p.Page.Read(ctx), pages-1)
+	items := make([]h.H, 0, 9)
+	items = append(items, p.button("Previous", page-1, page == 0, event))
+	last := -1
+	for i := range pages {
+		if i != 0 && i != pages-1 && (i < page-2 || i > page+2) {
+			continue
+		}
+		if last >= 0 && i > last+1 {
+			items = append(items, h.Span(h.Text("…")))
+		}
+		last = i
+		if i == page {
+			items = append(items, h.Button(h.Type("button"), h.Aria("current", "page"), h.Text(strconv.Itoa(i+1))))
+			continue
+		}
+		items = append(items, p.button(strconv.Itoa(i+1), i, false, event))
+	}
+	items = append(items, p.button("Next", page+1, page >= pages-1, event))
+	return h.Nav(append([]h.H{h.Class("via-pager"), h.Aria("label", "Pages")}, items...)...)
+}
+
+func (p *Pager) button(label string, page int, disabled bool, event func(*via.Ctx) error) h.H {
+	if disabled {
+		return h.Button(h.Type("button"), h.Text(label), h.Disabled())
+	}
+	return h.Button(h.Type("button"), h.Text(label),
+		on.Click(event, on.Arg(argOp, "page"), on.Arg(argPage, page)))
+}
+
+// More renders the infinite-scroll sentinel: an element that fires event
+// when it scrolls into view, adding the next page. Place it after the
+// rows. It renders nothing once all total rows are loaded, so the last
+// page stops the scroll. content, if any, shows inside it, e.g. a
+// "Loading…" line.
+func (p *Pager) More(ctx *via.CtxR, total int, event func(*via.Ctx) error, content ...h.H) h.H {
+	if p.Loaded(ctx) >= total {
+		return nil
+	}
+	// The page to load rides along: the binding changes with every page,
+	// so datastar re-arms the observer, and it fires again at once if the
+	// sentinel is still on screen after the new rows land.
+	attrs := []h.H{
+		h.Class("via-more"),
+		on.Intersect(event, on.Arg(argOp, "more"), on.Arg(argPage, p.Page.Read(ctx)+1)),
+	}
+	return h.Div(append(attrs, content...)...)
+}
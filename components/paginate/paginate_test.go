@@ -0,0 +1,126 @@
+package paginate_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/components/paginate"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var items = func() []string {
+	out := make([]string, 23)
+	for i := range out {
+		out[i] = "item-" + strconv.Itoa(i)
+	}
+	return out
+}()
+
+type listPage struct {
+	Pages   *paginate.Pager
+	Fetched via.StateTab[int]
+}
+
+func (p *listPage) OnInit(ctx *via.Ctx) error {
+	p.Pages.SetSize(2)
+	p.Pages.Changed.On(func(ctx *via.Ctx, page int) { p.Fetched.Write(ctx, page) })
+	return nil
+}
+
+func (p *listPage) Paginate(ctx *via.Ctx) error { return p.Pages.Handle(ctx) }
+
+func (p *listPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.Each(paginate.Slice(ctx, p.Pages, items), func(s string) h.H { return h.P(h.Text(s + ";")) }),
+		p.Pages.View(ctx, len(items), p.Paginate),
+		h.Span(h.Textf("fetched=%d", p.Fetched.Read(ctx))),
+	)
+}
+
+func newListClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[listPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+func TestPager_rendersFirstPageWithElidedControls(t *testing.T) {
+	t.Parallel()
+	tc, _ := newListClient(t)
+
+	html := tc.HTML()
+	assert.Contains(t, html, "item-0;")
+	assert.Contains(t, html, "item-1;")
+	assert.NotContains(t, html, "item-2;")
+	assert.Contains(t, html, `<button type="button" disabled>Previous</button>`)
+	assert.Contains(t, html, `aria-current="page">1</button>`)
+	assert.Contains(t, html, ">3</button><span>…</span>")
+	assert.Contains(t, html, ">12</button>", "the last page is always offered")
+}
+
+func TestPager_handleMovesPageAndEmitsChanged(t *testing.T) {
+	t.Parallel()
+	tc, frames := newListClient(t)
+
+	require.Equal(t, 200, tc.Action("Paginate").WithArg("paginate", "page").WithArg("page", "11").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "item-22;", "fetched=11")
+	assert.NotContains(t, frame, "item-21;", "the last page holds one row")
+	assert.Contains(t, frame, `<button type="button" disabled>Next</button>`)
+}
+
+func TestPager_rejectsABadPage(t *testing.T) {
+	t.Parallel()
+	tc, frames := newListClient(t)
+
+	require.Equal(t, 200, tc.Action("Paginate").WithArg("paginate", "page").WithArg("page", "-1").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "paginate: bad page")
+}
+
+type feedPage struct {
+	Pages *paginate.Pager
+}
+
+func (p *feedPage) OnInit(ctx *via.Ctx) error { p.Pages.SetSize(10); return nil }
+
+func (p *feedPage) More(ctx *via.Ctx) error { return p.Pages.Handle(ctx) }
+
+func (p *feedPage) View(ctx *via.CtxR) h.H {
+	shown := items[:min(p.Pages.Loaded(ctx), len(items))]
+	return h.Div(
+		h.Each(shown, func(s string) h.H { return h.P(h.Text(s + ";")) }),
+		p.Pages.More(ctx, len(items), p.More, h.Text("Loading…")),
+	)
+}
+
+func TestPager_moreLoadsNextPageUntilTheEnd(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[feedPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	html := tc.HTML()
+	assert.Contains(t, html, "item-9;")
+	assert.NotContains(t, html, "item-10;")
+	assert.Contains(t, html, `data-on-intersect="@post(&#39;/_action/More?paginate=more&amp;page=1&#39;)"`)
+
+	require.Equal(t, 200, tc.Action("More").WithArg("paginate", "more").WithArg("page", "1").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "item-19;", "page=2")
+
+	// A repeat fire for a page already loaded doesn't skip ahead.
+	require.Equal(t, 200, tc.Action("More").WithArg("paginate", "more").WithArg("page", "1").Fire())
+	require.Equal(t, 200, tc.Action("More").WithArg("paginate", "more").WithArg("page", "2").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "item-22;")
+	assert.NotContains(t, frame, "Loading…", "the sentinel goes once every row is loaded")
+}
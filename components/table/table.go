@@ -0,0 +1,415 @@
+// Package table is a server-driven data table: sortable columns, paging,
+// a filter box, per-row actions and row selection, with the table's
+// state held in Via state and every interaction handled by one action.
+//
+// A Table is a child composition. Configure it in the parent's OnInit,
+// forward one action to [Table.Handle], and render it with that action:
+//
+//	type Users struct {
+//	    Grid *table.Table[User]
+//	}
+//
+//	func (p *Users) OnInit(ctx *via.Ctx) error {
+//	    p.Grid.Configure(table.Config[User]{
+//	        Columns: []table.Column[User]{
+//	            {Key: "name", Title: "Name", Cell: func(u User) h.H { return h.Text(u.Name) },
+//	                Compare: func(a, b User) int { return strings.Compare(a.Name, b.Name) }},
+//	            {Key: "email", Title: "Email", Cell: func(u User) h.H { return h.Text(u.Email) }},
+//	        },
+//	        Key:     func(u User) string { return u.ID },
+//	        All:     store.List,
+//	        Match:   func(u User, f string) bool { return strings.Contains(u.Name, f) },
+//	        Actions: []table.RowAction{{Name: "delete", Label: "Delete"}},
+//	    })
+//	    p.Grid.Row.On(func(ctx *via.Ctx, ev table.RowEvent) { store.Delete(ev.Key) })
+//	    return nil
+//	}
+//
+//	func (p *Users) GridEvent(ctx *via.Ctx) error { return p.Grid.Handle(ctx) }
+//
+//	func (p *Users) View(ctx *via.CtxR) h.H { return p.Grid.View(ctx, p.GridEvent) }
+//
+// All suits rows already in memory. For a database, set Source instead:
+// it is asked for one page at a time, so the query sorts, filters and
+// pages in SQL rather than loading every row.
+package table
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+)
+
+// DefaultPageSize is the page size when Config.PageSize is zero.
+const DefaultPageSize = 20
+
+// Column is one column of a table.
+type Column[R any] struct {
+	// Key names the column in Query.Sort. Required for a sortable column.
+	Key   string
+	Title string
+	// Cell renders the column's cell for one row.
+	Cell func(R) h.H
+	// Compare orders two rows by this column, and makes the column
+	// sortable when the table serves rows from Config.All.
+	Compare func(a, b R) int
+	// Sortable makes the header clickable for a Config.Source that sorts
+	// by Query.Sort itself.
+	Sortable bool
+}
+
+func (c Column[R]) sortable() bool { return c.Key != "" && (c.Sortable || c.Compare != nil) }
+
+// Query is the view of the data a table asks its Source for.
+type Query struct {
+	Sort   string // key of the column to sort by; "" keeps the source's order
+	Desc   bool
+	Filter string // the filter box's text, trimmed
+	Page   int    // zero-based page index
+	Size   int    // rows per page
+}
+
+// Offset is the index of the page's first row.
+func (q Query) Offset() int { return q.Page * q.Size }
+
+// Source returns one page of rows for q, and the number of rows matching
+// q.Filter across all pages.
+type Source[R any] func(q Query) (rows []R, total int, err error)
+
+// RowAction is a button rendered in every row. Clicking it emits a
+// [RowEvent] on Table.Row.
+type RowAction struct {
+	Name  string // reported as RowEvent.Action
+	Label string
+}
+
+// RowEvent reports a click on a row action.
+type RowEvent struct {
+	Action string // RowAction.Name
+	Key    string // Config.Key of the row
+}
+
+// Config is a table's setup, passed to [Table.Configure].
+type Config[R any] struct {
+	Columns []Column[R]
+	// Key identifies a row for row actions and selection. Required.
+	Key func(R) string
+	// Source serves the table one page at a time. Set it, or All.
+	Source Source[R]
+	// All returns every row, for data that already lives in memory; the
+	// table filters with Match, sorts with the columns' Compare and pages
+	// itself. It is called on every render, so changes show up on the
+	// next one.
+	All   func() []R
+	Match func(r R, filter string) bool
+	// Filterable shows the filter box for a Source that filters by
+	// Query.Filter itself. With All it shows whenever Match is set.
+	Filterable bool
+	// PageSize is the number of rows per page; DefaultPageSize if zero.
+	PageSize int
+	Actions  []RowAction
+	// Selectable adds a checkbox column; the checked keys are in
+	// Table.Selected.
+	Selectable bool
+	// Empty is shown when no row matches; "No rows." if nil.
+	Empty h.H
+}
+
+// Table is a data table child composition. Its exported handles are the
+// table's per-tab state: read them, but change them through Handle.
+type Table[R any] struct {
+	// State is the sort, filter and page being shown.
+	State via.StateTab[Query]
+	// Selected holds the keys of the checked rows, in the order checked.
+	Selected via.StateTab[[]string]
+	// Filter is the filter box's text as typed.
+	Filter via.Signal[string] `via:"filter"`
+	// Row reports row action clicks.
+	Row via.Emitter[RowEvent]
+
+	cfg Config[R]
+}
+
+// Action arguments the table's controls post to the forwarding action.
+const (
+	argOp     = "table"
+	argColumn = "column"
+	argPage   = "page"
+	argKey    = "key"
+	argAction = "action"
+)
+
+// Configure sets the table up. Call it from the parent's OnInit; it
+// panics unless Key and exactly one of Source and All are set, since no
+// table renders without them.
+func (t *Table[R]) Configure(cfg Config[R]) {
+	if cfg.Key == nil {
+		panic("table.Configure: Config.Key is required")
+	}
+	if (cfg.Source == nil) == (cfg.All == nil) {
+		panic("table.Configure: set exactly one of Config.Source and Config.All")
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = DefaultPageSize
+	}
+	t.cfg = cfg
+}
+
+// load fetches the page q asks for.
+func (t *Table[R]) load(q Query) ([]R, int, error) {
+	if t.cfg.Key == nil {
+		panic("table: not configured; call Configure from the parent's OnInit")
+	}
+	q.Size = t.cfg.PageSize
+	if t.cfg.Source != nil {
+		return t.cfg.Source(q)
+	}
+	rows := t.cfg.All()
+	if t.cfg.Match != nil && q.Filter != "" {
+		kept := make([]R, 0, len(rows))
+		for _, r := range rows {
+			if t.cfg.Match(r, q.Filter) {
+				kept = append(kept, r)
+			}
+		}
+		rows = kept
+	}
+	if i := slices.IndexFunc(t.cfg.Columns, func(c Column[R]) bool {
+		return c.Key == q.Sort && c.Compare != nil
+	}); i >= 0 {
+		compare := t.cfg.Columns[i].Compare
+		rows = slices.Clone(rows)
+		slices.SortStableFunc(rows, func(a, b R) int {
+			if q.Desc {
+				return compare(b, a)
+			}
+			return compare(a, b)
+		})
+	}
+	total := len(rows)
+	start := min(q.Offset(), total)
+	return rows[start:min(start+q.Size, total)], total, nil
+}
+
+// shown fetches the page View renders for q: the last page when rows went
+// away under the one q asks for.
+func (t *Table[R]) shown(q Query) ([]R, int, error) {
+	rows, total, err := t.load(q)
+	if pages := pageCount(total, t.cfg.PageSize); err == nil && q.Page >= pages && q.Page > 0 {
+		q.Page = pages - 1
+		rows, total, err = t.load(q)
+	}
+	return rows, total, err
+}
+
+// checkShown returns an error unless key names a row on the page q shows;
+// a checkbox or row button can only have been clicked there.
+func (t *Table[R]) checkShown(q Query, key string) error {
+	rows, _, err := t.shown(q)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(rows, func(r R) bool { return t.cfg.Key(r) == key }) {
+		return errors.New("table: no row " + strconv.Quote(key) + " on the page")
+	}
+	return nil
+}
+
+// Handle applies the control that fired the forwarding action: a sort
+// header, a pager button, the filter box, a selection checkbox, or a row
+// action. It returns an error for a request no rendered control sends.
+func (t *Table[R]) Handle(ctx *via.Ctx) error {
+	q := t.State.Read(ctx)
+	switch ctx.EventArg(argOp) {
+	case "sort":
+		col := ctx.EventArg(argColumn)
+		if !slices.ContainsFunc(t.cfg.Columns, func(c Column[R]) bool { return c.Key == col && c.sortable() }) {
+			return errors.New("table: unknown sort column " + strconv.Quote(col))
+		}
+		q.Desc = q.Sort == col && !q.Desc
+		q.Sort, q.Page = col, 0
+	case "page":
+		n, err := strconv.Atoi(ctx.EventArg(argPage))
+		if err != nil || n < 0 {
+			return errors.New("table: bad page")
+		}
+		q.Page = n
+	case "filter":
+		q.Filter, q.Page = strings.TrimSpace(t.Filter.Read(ctx)), 0
+	case "select":
+		key := ctx.EventArg(argKey)
+		if err := t.checkShown(q, key); err != nil {
+			return err
+		}
+		t.Selected.Write(ctx, toggle(t.Selected.Read(ctx), key))
+		return nil
+	case "selectpage":
+		rows, _, err := t.shown(q)
+		if err != nil {
+			return err
+		}
+		sel := t.Selected.Read(ctx)
+		all := true
+		for _, r := range rows {
+			all = all && slices.Contains(sel, t.cfg.Key(r))
+		}
+		for _, r := range rows {
+			key := t.cfg.Key(r)
+			if has := slices.Contains(sel, key); has == all {
+				sel = toggle(sel, key)
+			}
+		}
+		t.Selected.Write(ctx, sel)
+		return nil
+	case "row":
+		name, key := ctx.EventArg(argAction), ctx.EventArg(argKey)
+		if !slices.ContainsFunc(t.cfg.Actions, func(a RowAction) bool { return a.Name == name }) {
+			return errors.New("table: unknown row action " + strconv.Quote(name))
+		}
+		if err := t.checkShown(q, key); err != nil {
+			return err
+		}
+		t.Row.Emit(ctx, RowEvent{Action: name, Key: key})
+		return nil
+	default:
+		return errors.New("table: unknown event " + strconv.Quote(ctx.EventArg(argOp)))
+	}
+	t.State.Write(ctx, q)
+	return nil
+}
+
+// toggle adds key to keys, or removes it if present, without changing
+// the slice it was given.
+func toggle(keys []string, key string) []string {
+	if i := slices.Index(keys, key); i >= 0 {
+		return slices.Delete(slices.Clone(keys), i, i+1)
+	}
+	return append(slices.Clone(keys), key)
+}
+
+// View renders the table. event is the parent's action that forwards to
+// [Table.Handle]; every control in the table fires it.
+func (t *Table[R]) View(ctx *via.CtxR, event func(*via.Ctx) error) h.H {
+	q := t.State.Read(ctx)
+	rows, total, err := t.shown(q)
+	if pages := pageCount(total, t.cfg.PageSize); err == nil && q.Page >= pages && q.Page > 0 {
+		// Rows went away under the page being shown: shown fell back to
+		// the last one, so the pager must too.
+		q.Page = pages - 1
+	}
+	sel := t.Selected.Read(ctx)
+
+	head := make([]h.H, 0, len(t.cfg.Columns)+2)
+	if t.cfg.Selectable {
+		all := len(rows) > 0
+		for _, r := range rows {
+			all = all && slices.Contains(sel, t.cfg.Key(r))
+		}
+		head = append(head, h.Th(h.Input(h.Type("checkbox"), h.Aria("label", "Select page"),
+			h.If(all, h.Checked()), on.Change(event, on.Arg(argOp, "selectpage")))))
+	}
+	for _, c := range t.cfg.Columns {
+		head = append(head, t.header(c, q, event))
+	}
+	if len(t.cfg.Actions) > 0 {
+		head = append(head, h.Th(h.Aria("label", "Actions")))
+	}
+
+	var body h.H
+	switch {
+	case err != nil:
+		body = t.message(h.Text("Couldn't load rows."))
+	case len(rows) == 0:
+		empty := t.cfg.Empty
+		if empty == nil {
+			empty = h.Text("No rows.")
+		}
+		body = t.message(empty)
+	default:
+		body = h.Each(rows, func(r R) h.H { return t.row(r, sel, event) })
+	}
+
+	var filter h.H
+	if t.cfg.Match != nil || (t.cfg.Source != nil && t.cfg.Filterable) {
+		filter = h.Input(h.Type("search"), h.Placeholder("Filter…"), h.Aria("label", "Filter"),
+			t.Filter.Bind(), on.Input(event, on.Debounce("300ms"), on.Arg(argOp, "filter")))
+	}
+	return h.Div(h.Class("via-table"),
+		filter,
+		h.Table(h.THead(h.Tr(head...)), h.TBody(body)),
+		t.pager(q.Page, pageCount(total, t.cfg.PageSize), event),
+	)
+}
+
+// header renders a column heading, as a sort toggle when it can sort.
+func (t *Table[R]) header(c Column[R], q Query, event func(*via.Ctx) error) h.H {
+	if !c.sortable() {
+		return h.Th(h.Text(c.Title))
+	}
+	sort, mark := "none", ""
+	if q.Sort == c.Key {
+		sort, mark = "ascending", " ▲"
+		if q.Desc {
+			sort, mark = "descending", " ▼"
+		}
+	}
+	return h.Th(h.Aria("sort", sort),
+		h.Button(h.Type("button"), h.Text(c.Title+mark),
+			on.Click(event, on.Arg(argOp, "sort"), on.Arg(argColumn, c.Key))))
+}
+
+func (t *Table[R]) row(r R, sel []string, event func(*via.Ctx) error) h.H {
+	key := t.cfg.Key(r)
+	cells := make([]h.H, 0, len(t.cfg.Columns)+2)
+	if t.cfg.Selectable {
+		cells = append(cells, h.Td(h.Input(h.Type("checkbox"), h.Aria("label", "Select row"),
+			h.If(slices.Contains(sel, key), h.Checked()),
+			on.Change(event, on.Arg(argOp, "select"), on.Arg(argKey, key)))))
+	}
+	for _, c := range t.cfg.Columns {
+		var cell h.H
+		if c.Cell != nil {
+			cell = c.Cell(r)
+		}
+		cells = append(cells, h.Td(cell))
+	}
+	if len(t.cfg.Actions) > 0 {
+		cells = append(cells, h.Td(h.Each(t.cfg.Actions, func(a RowAction) h.H {
+			return h.Button(h.Type("button"), h.Text(a.Label),
+				on.Click(event, on.Arg(argOp, "row"), on.Arg(argAction, a.Name), on.Arg(argKey, key)))
+		})))
+	}
+	return h.Tr(cells...)
+}
+
+// message renders a full-width body row.
+func (t *Table[R]) message(content h.H) h.H {
+	span := len(t.cfg.Columns)
+	if t.cfg.Selectable {
+		span++
+	}
+	if len(t.cfg.Actions) > 0 {
+		span++
+	}
+	return h.Tr(h.Td(h.ColSpan(strconv.Itoa(span)), content))
+}
+
+func (t *Table[R]) pager(page, pages int, event func(*via.Ctx) error) h.H {
+	if pages <= 1 {
+		return nil
+	}
+	return h.Nav(h.Aria("label", "Pages"),
+		h.Button(h.Type("button"), h.Text("Previous"), h.If(page == 0, h.Disabled()),
+			on.Click(event, on.Arg(argOp, "page"), on.Arg(argPage, page-1))),
+		h.Span(h.Textf(" Page %d of %d ", page+1, pages)),
+		h.Button(h.Type("button"), h.Text("Next"), h.If(page >= pages-1, h.Disabled()),
+			on.Click(event, on.Arg(argOp, "page"), on.Arg(argPage, page+1))),
+	)
+}
+
+func pageCount(total, size int) int { return (total + size - 1) / size }
@@ -0,0 +1,221 @@
+package table_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/components/table"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fruit struct {
+	ID    string
+	Name  string
+	Price int
+}
+
+var fruits = []fruit{
+	{"1", "cherry", 4}, {"2", "apple", 2}, {"3", "banana", 1},
+	{"4", "damson", 6}, {"5", "elderberry", 9},
+}
+
+type fruitPage struct {
+	Grid    *table.Table[fruit]
+	Deleted via.StateTab[string]
+}
+
+func (p *fruitPage) OnInit(ctx *via.Ctx) error {
+	p.Grid.Configure(table.Config[fruit]{
+		Columns: []table.Column[fruit]{
+			{Key: "name", Title: "Name", Cell: func(f fruit) h.H { return h.Text(f.Name) },
+				Compare: func(a, b fruit) int { return strings.Compare(a.Name, b.Name) }},
+			{Key: "price", Title: "Price", Cell: func(f fruit) h.H { return h.Text("$" + strconv.Itoa(f.Price)) }},
+		},
+		Key:        func(f fruit) string { return f.ID },
+		All:        func() []fruit { return fruits },
+		Match:      func(f fruit, q string) bool { return strings.Contains(f.Name, q) },
+		PageSize:   2,
+		Actions:    []table.RowAction{{Name: "delete", Label: "Delete"}},
+		Selectable: true,
+	})
+	p.Grid.Row.On(func(ctx *via.Ctx, ev table.RowEvent) {
+		p.Deleted.Write(ctx, ev.Action+":"+ev.Key)
+	})
+	return nil
+}
+
+func (p *fruitPage) GridEvent(ctx *via.Ctx) error { return p.Grid.Handle(ctx) }
+
+func (p *fruitPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Grid.View(ctx, p.GridEvent), h.P(h.Text("deleted="+p.Deleted.Read(ctx))))
+}
+
+func newFruitClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[fruitPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+func TestTable_rendersFirstPageInSourceOrder(t *testing.T) {
+	t.Parallel()
+	tc, _ := newFruitClient(t)
+
+	html := tc.HTML()
+	assert.Contains(t, html, "cherry")
+	assert.Contains(t, html, "apple")
+	assert.NotContains(t, html, "banana", "PageSize is 2")
+	assert.Contains(t, html, "Page 1 of 3")
+	assert.Contains(t, html, `aria-sort="none"`, "Name has a Compare, so it sorts")
+	assert.Contains(t, html, `<th>Price</th>`, "Price has no Compare, so it doesn't")
+	assert.Contains(t, html, `type="search"`, "Match enables the filter box")
+}
+
+func TestTable_sortHeaderTogglesDirection(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "sort").WithArg("column", "name").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, `aria-sort="ascending"`, "apple", "banana")
+	assert.NotContains(t, frame, "cherry")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "sort").WithArg("column", "name").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `aria-sort="descending"`, "elderberry", "damson")
+}
+
+func TestTable_rejectsSortOnAColumnThatCannotSort(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "sort").WithArg("column", "price").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "unknown sort column")
+}
+
+func TestTable_pagerMovesBetweenPages(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "page").WithArg("page", "2").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "Page 3 of 3", "elderberry")
+	assert.NotContains(t, frame, "cherry")
+}
+
+func TestTable_filterNarrowsRowsAndResetsPage(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "page").WithArg("page", "1").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "Page 2 of 3")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "filter").
+		WithSignal("Grid.filter", " rr ").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "cherry", "elderberry")
+	assert.NotContains(t, frame, "apple")
+	assert.NotContains(t, frame, "Page ", "two matches fit on one page")
+}
+
+func TestTable_selectionTogglesRowsAndPage(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "select").WithArg("key", "2").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, `aria-label="Select row" checked`)
+	assert.Equal(t, 1, strings.Count(frame, " checked"))
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "selectpage").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `aria-label="Select page" checked`)
+}
+
+func TestTable_rowActionEmitsRowEvent(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "row").
+		WithArg("action", "delete").WithArg("key", "2").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "deleted=delete:2")
+}
+
+func TestTable_rejectsRowEventsNoRenderedControlSends(t *testing.T) {
+	t.Parallel()
+	tc, frames := newFruitClient(t)
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "row").
+		WithArg("action", "archive").WithArg("key", "2").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "unknown row action")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "row").
+		WithArg("action", "delete").WithArg("key", "3").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "on the page")
+	assert.NotContains(t, frame, "deleted=delete", "row 3 is on page 2, not the one shown")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "select").WithArg("key", "9").Fire())
+	frame = vt.AwaitFrame(t, frames, 2*time.Second, "on the page")
+	assert.NotContains(t, frame, `aria-label="Select row" checked`)
+}
+
+type pagedPage struct {
+	Grid *table.Table[fruit]
+
+	asked table.Query
+}
+
+func (p *pagedPage) OnInit(ctx *via.Ctx) error {
+	p.Grid.Configure(table.Config[fruit]{
+		Columns: []table.Column[fruit]{
+			{Key: "price", Title: "Price", Sortable: true, Cell: func(f fruit) h.H { return h.Text(f.Name) }},
+		},
+		Key: func(f fruit) string { return f.ID },
+		Source: func(q table.Query) ([]fruit, int, error) {
+			p.asked = q
+			if q.Filter == "boom" {
+				return nil, 0, errors.New("db down")
+			}
+			return fruits[:1], 40, nil
+		},
+		Filterable: true,
+	})
+	return nil
+}
+
+func (p *pagedPage) GridEvent(ctx *via.Ctx) error { return p.Grid.Handle(ctx) }
+
+func (p *pagedPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Grid.View(ctx, p.GridEvent), h.P(h.Textf("asked=%+v", p.asked)))
+}
+
+func TestTable_sourceReceivesTheQuery(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[pagedPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	assert.Contains(t, tc.HTML(), "Page 1 of 2", "total comes from the Source")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "sort").WithArg("column", "price").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "asked={Sort:price Desc:false Filter: Page:0 Size:20}")
+
+	require.Equal(t, 200, tc.Action("GridEvent").WithArg("table", "filter").WithSignal("Grid.filter", "boom").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "Couldn&#39;t load rows.")
+}
+
+func TestConfigure_panicsWithoutARowSource(t *testing.T) {
+	t.Parallel()
+	var grid table.Table[fruit]
+	assert.PanicsWithValue(t, "table.Configure: set exactly one of Config.Source and Config.All", func() {
+		grid.Configure(table.Config[fruit]{Key: func(f fruit) string { return f.ID }})
+	})
+}
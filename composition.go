@@ -61,11 +61,24 @@ type Connector interface {
 // Ctx is torn down — page unload, ctx-TTL sweep, or app shutdown.
 // Release resources, close goroutines, persist final state. Runs
 // under the per-Ctx action mutex so it observes a composition that
-// isn't being mutated by a concurrent handler.
+// isn't being mutated by a concurrent handler. Only the root's runs on
+// its own; a child's runs through [Ctx.Dispose].
 type Disposer interface {
 	OnDispose(ctx *Ctx)
 }
 
+// CacheableShell is the optional marker for a page whose first render is
+// the same for every visitor — no per-user data in OnInit or View. Its
+// document is then served as a shell with an ETag: a repeat visit
+// revalidates and gets a 304 instead of the HTML, and the tab itself
+// (OnInit, session-bound state, the tab id) is set up by the SSE
+// handshake that follows, the same way a stale tab is re-bootstrapped.
+// OnInit therefore runs twice on a fresh load: once for the shell, once
+// for the tab.
+type CacheableShell interface {
+	CacheableShell()
+}
+
 // Mountable is the target of [Mount]. Implemented by *App (mounts at
 // route on the app) and *Group (mounts under the group's prefix with
 // the group's middleware applied to page render, action POST, and SSE
@@ -155,6 +168,7 @@ func buildDescriptor[C any]() *cmpDescriptor {
 		connectIdx:   -1,
 		disposeIdx:   -1,
 		bind:         &bindGuard{},
+		shell:        ptrTyp.Implements(reflect.TypeFor[CacheableShell]()),
 	}
 
 	walkStruct(desc, typ, nil, "")
@@ -178,6 +192,9 @@ func buildDescriptor[C any]() *cmpDescriptor {
 	for _, s := range desc.scopeSlots {
 		checkWireKey(s.wireKey)
 	}
+	for _, s := range desc.formSlots {
+		checkWireKey(s.wireKey)
+	}
 
 	for i := range ptrTyp.NumMethod() {
 		m := ptrTyp.Method(i)
@@ -68,6 +68,23 @@ func TestMount_panicShowsActualAndExpectedLifecycleSignature(t *testing.T) {
 	via.Mount[badOnInitPage](app, "/")
 }
 
+type ownDisposeChild struct{ N via.StateTabNum[int] }
+
+func (c *ownDisposeChild) OnDispose() error { return nil } // the child's own shape, not via's hook
+
+func (c *ownDisposeChild) View(ctx *via.CtxR) h.H { return h.Div() }
+
+type ownDisposeChildPage struct{ Child *ownDisposeChild }
+
+func (p *ownDisposeChildPage) View(ctx *via.CtxR) h.H { return p.Child.View(ctx) }
+
+func TestMount_acceptsAChildOnDisposeOfAnyShape(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	assert.NotPanics(t, func() { via.Mount[ownDisposeChildPage](app, "/") },
+		"the runtime never calls a child's OnDispose, so its signature is the child's business")
+}
+
 type badViewReturnPage struct{}
 
 func (p *badViewReturnPage) View(ctx *via.CtxR) int { return 0 } // wrong return type
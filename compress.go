@@ -0,0 +1,262 @@
+package via
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// CompressionAlgorithm names a Content-Encoding via can apply.
+type CompressionAlgorithm string
+
+const (
+	CompressBrotli CompressionAlgorithm = "br"
+	CompressGzip   CompressionAlgorithm = "gzip"
+)
+
+// Compression configures how via compresses what it writes: the page
+// document served on a Mount GET and the SSE stream that follows. The
+// zero value is the default — Brotli at level 5 for both. A client whose
+// Accept-Encoding doesn't list the algorithm gets the bytes uncompressed.
+type Compression struct {
+	Algorithm CompressionAlgorithm // "" means CompressBrotli
+	// Level is the algorithm's level: 1–11 for Brotli, 1–9 for gzip, or
+	// [CompressLevelFastest]. 0 picks the default (Brotli 5, gzip 6).
+	Level int
+	// Off disables compression entirely, e.g. behind a proxy that already
+	// compresses, or where CPU matters more than bandwidth.
+	Off bool
+	// SkipTypes lists Content-Type prefixes a page response is passed
+	// through uncompressed for — payloads already compressed, which only
+	// grow (and cost CPU) when compressed again. A response that already
+	// carries a Content-Encoding is always passed through. nil means
+	// [DefaultCompressionSkipTypes].
+	SkipTypes []string
+}
+
+// CompressLevelFastest, as Compression.Level, picks the algorithm's
+// fastest level — Brotli 0, gzip 1 — which a literal 0 can't, since 0
+// means the default.
+const CompressLevelFastest = -1
+
+// DefaultCompressionSkipTypes is the SkipTypes used when none is set:
+// media and archive formats whose bytes are already compressed.
+var DefaultCompressionSkipTypes = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/zstd", "application/x-7z-compressed", "application/pdf",
+}
+
+// WithCompression sets the compression for page responses and the SSE
+// stream. Default: Brotli level 5, already-compressed types skipped.
+func WithCompression(c Compression) Option {
+	return func(cfg *config) { cfg.compression = c }
+}
+
+func (c Compression) algorithm() CompressionAlgorithm {
+	if c.Algorithm == "" {
+		return CompressBrotli
+	}
+	return c.Algorithm
+}
+
+func (c Compression) level() int {
+	gz := c.algorithm() == CompressGzip
+	switch {
+	case c.Level == CompressLevelFastest && gz:
+		return gzip.BestSpeed
+	case c.Level == CompressLevelFastest:
+		return brotli.BestSpeed
+	case c.Level != 0:
+		return c.Level
+	case gz:
+		return gzip.DefaultCompression
+	}
+	return sseLevel
+}
+
+func (c Compression) validate() {
+	switch c.algorithm() {
+	case CompressBrotli:
+		if c.Level < CompressLevelFastest || c.Level > brotli.BestCompression {
+			panic(fmt.Sprintf("via.WithCompression: Brotli level must be 1–11 or CompressLevelFastest, got %d", c.Level))
+		}
+	case CompressGzip:
+		if c.Level < CompressLevelFastest || c.Level > gzip.BestCompression {
+			panic(fmt.Sprintf("via.WithCompression: gzip level must be 1–9 or CompressLevelFastest, got %d", c.Level))
+		}
+	default:
+		panic(fmt.Sprintf("via.WithCompression: unknown algorithm %q", c.Algorithm))
+	}
+}
+
+// sseOptions returns the datastar options that apply the configured
+// compression to an SSE stream.
+func (c Compression) sseOptions() []datastar.SSEOption {
+	if c.Off {
+		return nil
+	}
+	var opt datastar.CompressionOption
+	if c.algorithm() == CompressGzip {
+		opt = datastar.WithGzip(datastar.WithGzipLevel(c.level()))
+	} else {
+		opt = datastar.WithBrotli(datastar.WithBrotliLevel(c.level()))
+	}
+	return []datastar.SSEOption{datastar.WithCompression(opt)}
+}
+
+// accepts reports whether the request's Accept-Encoding lists enc with a
+// non-zero quality.
+func accepts(r *http.Request, enc CompressionAlgorithm) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), string(enc)) {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		v, err := strconv.ParseFloat(q, 64)
+		return err == nil && v > 0
+	}
+	return false
+}
+
+// pageCompressor hands out compressing writers for page responses,
+// pooling the encoders: a Brotli writer carries hundreds of KB of state
+// that a per-request allocation would churn.
+type pageCompressor struct {
+	c    Compression
+	pool sync.Pool
+}
+
+func newPageCompressor(c Compression) *pageCompressor {
+	pc := &pageCompressor{c: c}
+	level := c.level()
+	if c.algorithm() == CompressGzip {
+		pc.pool.New = func() any {
+			zw, _ := gzip.NewWriterLevel(nil, level)
+			return zw
+		}
+	} else {
+		pc.pool.New = func() any { return brotli.NewWriterLevel(nil, level) }
+	}
+	return pc
+}
+
+// resettableWriter is the shape gzip.Writer and brotli.Writer share.
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+// wrap returns the writer a page response is rendered into and the func
+// that must run once the response is complete. When the client doesn't
+// accept the algorithm (or compression is off) w is returned as is.
+func (pc *pageCompressor) wrap(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, func()) {
+	if pc == nil || pc.c.Off || r.Method == http.MethodHead {
+		return w, func() {}
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !accepts(r, pc.c.algorithm()) {
+		return w, func() {}
+	}
+	cw := &compressWriter{ResponseWriter: w, pc: pc}
+	return cw, cw.close
+}
+
+// compressWriter compresses a page response. It decides on the first
+// WriteHeader/Write, once the handler's headers are final: a response
+// that is already encoded, has an empty body status, or has a skipped
+// Content-Type passes through untouched.
+type compressWriter struct {
+	http.ResponseWriter
+	pc      *pageCompressor
+	enc     resettableWriter
+	decided bool
+}
+
+func (cw *compressWriter) decide(status int) {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+	hdr := cw.Header()
+	if hdr.Get("Content-Encoding") != "" || status == http.StatusNoContent ||
+		status == http.StatusNotModified || status < http.StatusOK {
+		return
+	}
+	ct := hdr.Get("Content-Type")
+	if ct == "" {
+		// net/http would sniff the compressed bytes; the page document is
+		// HTML, so say so before the encoding hides it.
+		ct = "text/html; charset=utf-8"
+		hdr.Set("Content-Type", ct)
+	}
+	skip := cw.pc.c.SkipTypes
+	if skip == nil {
+		skip = DefaultCompressionSkipTypes
+	}
+	for _, p := range skip {
+		if strings.HasPrefix(ct, p) {
+			return
+		}
+	}
+	hdr.Set("Content-Encoding", string(cw.pc.c.algorithm()))
+	hdr.Del("Content-Length")
+	cw.enc = cw.pc.pool.Get().(resettableWriter)
+	cw.enc.Reset(cw.ResponseWriter)
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	cw.decide(status)
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	cw.decide(http.StatusOK)
+	if cw.enc == nil {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.enc.Write(b)
+}
+
+func (cw *compressWriter) close() {
+	if cw.enc == nil {
+		return
+	}
+	_ = cw.enc.Close()
+	cw.enc.Reset(nil)
+	cw.pc.pool.Put(cw.enc)
+	cw.enc = nil
+}
+
+// Flush pushes buffered compressed bytes through to the client.
+func (cw *compressWriter) Flush() {
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack passes through so a handler that takes over the connection
+// still can; the compressor is bypassed from then on.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	cw.decided = true
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter { return cw.ResponseWriter }
@@ -0,0 +1,18 @@
+package via
+
+import (
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression_levelResolvesDefaultsAndFastest(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, sseLevel, Compression{}.level())
+	assert.Equal(t, 0, Compression{Level: CompressLevelFastest}.level(), "Brotli's level 0 must be selectable")
+	assert.Equal(t, 11, Compression{Level: 11}.level())
+	assert.Equal(t, gzip.DefaultCompression, Compression{Algorithm: CompressGzip}.level())
+	assert.Equal(t, gzip.BestSpeed, Compression{Algorithm: CompressGzip, Level: CompressLevelFastest}.level())
+}
@@ -0,0 +1,170 @@
+package via_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pressPage struct{}
+
+func (p *pressPage) View(ctx *via.CtxR) h.H { return h.P(h.Text("compressible compressible")) }
+
+// logoPage answers its GET with an image from OnInit, the shape of a
+// payload that is already compressed.
+type logoPage struct{}
+
+func (p *logoPage) OnInit(ctx *via.Ctx) error {
+	w := ctx.Writer()
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte("\x89PNG fake"))
+	return err
+}
+
+func (p *logoPage) View(ctx *via.CtxR) h.H { return nil }
+
+func getPage(app *via.App, path, acceptEncoding string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompression_pageIsBrotliByDefault(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[pressPage](app, "/")
+
+	rec := getPage(app, "/", "gzip, deflate, br")
+	require.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+	body, err := io.ReadAll(brotli.NewReader(rec.Body))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "compressible compressible")
+}
+
+func TestCompression_pageIsPlainWhenNotAccepted(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[pressPage](app, "/")
+
+	for _, ae := range []string{"", "gzip", "br;q=0"} {
+		rec := getPage(app, "/", ae)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"), "Accept-Encoding %q", ae)
+		assert.Contains(t, rec.Body.String(), "compressible compressible")
+	}
+}
+
+func TestCompression_gzipAlgorithm(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithCompression(via.Compression{Algorithm: via.CompressGzip, Level: 9}))
+	via.Mount[pressPage](app, "/")
+
+	rec := getPage(app, "/", "gzip")
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "compressible compressible")
+}
+
+func TestCompression_offLeavesPagesPlain(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithCompression(via.Compression{Off: true}))
+	via.Mount[pressPage](app, "/")
+
+	rec := getPage(app, "/", "br, gzip")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Contains(t, rec.Body.String(), "compressible compressible")
+}
+
+func TestCompression_skipsAlreadyCompressedTypes(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[logoPage](app, "/logo")
+
+	rec := getPage(app, "/logo", "br")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "\x89PNG fake")
+}
+
+func TestCompression_skipTypesOverride(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithCompression(via.Compression{SkipTypes: []string{"text/html"}}))
+	via.Mount[pressPage](app, "/")
+
+	rec := getPage(app, "/", "br")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Contains(t, rec.Body.String(), "compressible compressible")
+
+	app2 := via.New(via.WithCompression(via.Compression{SkipTypes: []string{}}))
+	via.Mount[logoPage](app2, "/logo")
+	rec = getPage(app2, "/logo", "br")
+	assert.Equal(t, "br", rec.Header().Get("Content-Encoding"), "an empty list compresses everything")
+}
+
+func TestCompression_appliesToSSE(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		c    via.Compression
+		want string
+	}{
+		{"gzip", via.Compression{Algorithm: via.CompressGzip}, "gzip"},
+		{"off", via.Compression{Off: true}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			app := via.New(via.WithCompression(tc.c))
+			server := vt.Serve(t, app)
+			via.Mount[pressPage](app, "/")
+
+			jar, _ := cookiejar.New(nil)
+			c := &http.Client{Jar: jar}
+			resp, err := c.Get(server.URL + "/")
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			m := brotliTabRE.FindStringSubmatch(string(body))
+			require.Len(t, m, 2, "tab id in page")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			sseURL := server.URL + "/_sse?datastar=" + url.QueryEscape(`{"via_tab":"`+m[1]+`"}`)
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, sseURL, nil)
+			req.Header.Set("Accept-Encoding", "gzip, br")
+			sresp, err := c.Do(req)
+			require.NoError(t, err)
+			defer sresp.Body.Close()
+			assert.Equal(t, tc.want, sresp.Header.Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestWithCompression_rejectsBadConfig(t *testing.T) {
+	t.Parallel()
+	assert.Panics(t, func() { via.New(via.WithCompression(via.Compression{Level: 12})) })
+	assert.Panics(t, func() { via.New(via.WithCompression(via.Compression{Algorithm: via.CompressGzip, Level: 10})) })
+	assert.Panics(t, func() { via.New(via.WithCompression(via.Compression{Algorithm: "lz4"})) })
+	assert.Panics(t, func() { via.New(via.WithCompression(via.Compression{Level: -2})) })
+}
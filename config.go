@@ -3,6 +3,7 @@ package via
 import (
 	"fmt"
 	"net/http"
+	"net/netip"
 	"time"
 )
 
@@ -18,11 +19,16 @@ const (
 
 type config struct {
 	addr               string
+	basePath           string
+	trustedProxyCIDRs  []string
+	trustedProxies     []netip.Prefix
+	internalPaths      []string
 	title              string
 	lang               string
 	description        string
 	logLevel           LogLevel
 	plugins            []Plugin
+	datastarJS         []byte
 	shutdownTimeout    time.Duration
 	sessionTTL         time.Duration
 	contextTTL         time.Duration
@@ -32,9 +38,19 @@ type config struct {
 	keyStore           KeyStore
 	sseHeartbeat       time.Duration
 	sseWriteTimeout    time.Duration
+	sseMaxFrameGap     time.Duration
+	sseReplayBytes     int
+	compression        Compression
+	actionSigning      *ActionSigning
+	crawler            func(*http.Request) bool
+	stateSnapshots     bool
+	appState           *appStateConfig
+	signInAlert        func(*Ctx, SessionInfo)
+	userStores         []namedUserStore
 	secureCookies      bool
 	cookieSecuritySet  bool
 	cookieName         string
+	sessionKeys        [][]byte
 	httpServerHook     func(*http.Server)
 	readHeaderTimeout  time.Duration
 	readTimeout        time.Duration
@@ -48,13 +64,20 @@ type config struct {
 	noReconnect        bool
 	verboseErrors      bool
 	devChecks          bool
+	markupChecks       bool
+	a11yAudit          bool
+	prettyHTML         bool
 	strictDecode       bool
+	viewTransitions    bool
 	actionErrorHandler func(*Ctx, error)
+	auditHook          func(AuditEvent)
 	logger             Logger
 	notFoundHandler    http.Handler
 	tooLargeHandler    http.Handler
 	metrics            Metrics
+	metricLabels       []string
 	backplane          Backplane
+	clock              Clock
 }
 
 // Option configures a via App.
@@ -65,6 +88,12 @@ type Option func(*config)
 // negatives are rejected: 0 is a meaningful value (unlimited/default) for the
 // size and context caps, and a 0 shutdown timeout is a deliberate force-kill.
 func (c *config) validate() {
+	c.compression.validate()
+	c.actionSigning.validate(c.sseHeartbeat)
+	c.appState.validate()
+	c.basePath = cleanBasePath(c.basePath)
+	c.trustedProxies = parseTrustedProxies(c.trustedProxyCIDRs)
+	validateSessionKeys(c.sessionKeys)
 	if c.shutdownTimeout < 0 {
 		panic(fmt.Sprintf("via.WithShutdownTimeout: must be >= 0, got %v", c.shutdownTimeout))
 	}
@@ -77,6 +106,9 @@ func (c *config) validate() {
 	if c.maxContexts < 0 {
 		panic(fmt.Sprintf("via.WithMaxContexts: must be >= 0, got %d", c.maxContexts))
 	}
+	if c.sseReplayBytes < 0 {
+		panic(fmt.Sprintf("via.WithSSEReplayBytes: must be >= 0, got %d", c.sseReplayBytes))
+	}
 	if c.maxSessions < 0 {
 		panic(fmt.Sprintf("via.WithMaxSessions: must be >= 0, got %d", c.maxSessions))
 	}
@@ -85,6 +117,14 @@ func (c *config) validate() {
 // WithAddr sets the HTTP listen address.
 func WithAddr(addr string) Option { return func(c *config) { c.addr = addr } }
 
+// WithBasePath serves the app under a path prefix such as "/app", for
+// mounting behind a reverse proxy. Every URL Via generates — the Datastar
+// runtime, the SSE stream, action POSTs, beacons and plugin assets — is
+// prefixed with it. Requests are accepted with or without the prefix, so
+// it works whether or not the proxy strips it. Panics at New unless the
+// path starts with "/".
+func WithBasePath(p string) Option { return func(c *config) { c.basePath = p } }
+
 // WithTitle sets the rendered <title> on every page.
 func WithTitle(title string) Option { return func(c *config) { c.title = title } }
 
@@ -188,6 +228,17 @@ func WithSSEWriteTimeout(d time.Duration) Option {
 	return func(c *config) { c.sseWriteTimeout = d }
 }
 
+// WithSSEAdaptiveRate turns on pacing for slow clients, capped at max.
+// Each connection times its frame writes — a write blocks once the peer
+// stops reading — and a client that falls behind gets frames spaced out
+// in proportion, its pending patches coalescing into fewer, fresher
+// frames; fast clients keep the full rate. max bounds the gap between two
+// frames; 1 second is a good start. Off by default, and a value <= 0
+// turns it off again: every wake ships as it comes.
+func WithSSEAdaptiveRate(max time.Duration) Option {
+	return func(c *config) { c.sseMaxFrameGap = max }
+}
+
 // WithSecureCookies marks the session cookie Secure. This is the default;
 // the option remains for explicit intent and conflicts with
 // [WithInsecureCookies].
@@ -227,11 +278,21 @@ func WithSessionCookieName(name string) Option {
 	return func(c *config) { c.cookieName = name }
 }
 
-// WithPlugins registers plugins. They run Register at New time.
+// WithPlugins registers plugins. They run Register at New time, in the
+// order given except that a [DependentPlugin] runs after the plugins it
+// requires. A [NamedPlugin] is registered under its name.
 func WithPlugins(plugins ...Plugin) Option {
 	return func(c *config) { c.plugins = append(c.plugins, plugins...) }
 }
 
+// WithDatastarJS serves js — a custom or patched Datastar build — in
+// place of the embedded runtime. It is served like the embedded one, at
+// a content-hashed, immutably cached URL. js must be a Datastar build
+// the via client code works with; a nil js keeps the embedded runtime.
+func WithDatastarJS(js []byte) Option {
+	return func(c *config) { c.datastarJS = js }
+}
+
 // WithHTTPServer hands the user the *http.Server before listening so
 // non-default fields (TLSConfig, ConnState, …) can be set.
 func WithHTTPServer(hook func(*http.Server)) Option {
@@ -283,6 +344,38 @@ func WithMaxContexts(n int) Option { return func(c *config) { c.maxContexts = n
 // (expected peak users × 2).
 func WithMaxSessions(n int) Option { return func(c *config) { c.maxSessions = n } }
 
+// WithCrawlerRender serves bots plain HTML. A page GET that detect flags
+// runs the composition as usual — OnInit, then View — but the tab is never
+// registered, no session is minted, and the document carries no SSE
+// bootstrap, so a crawl costs one render instead of a live tab that idles
+// until the TTL sweep. OnDispose runs once the response is written. A nil
+// detect uses [IsCrawler].
+//
+// Actions are unavailable on such a page: there is no tab to post them to.
+// Anything the page shows only after a client-side interaction won't be
+// indexed — render the content you want crawled in the initial view.
+func WithCrawlerRender(detect func(*http.Request) bool) Option {
+	return func(c *config) {
+		if detect == nil {
+			detect = IsCrawler
+		}
+		c.crawler = detect
+	}
+}
+
+// WithStateSnapshots lets a page GET that asks for JSON (Accept:
+// application/json, and not text/html) get the page's state instead of its
+// HTML: every Signal, StateTab, StateSess, StateApp and StateAppEvents
+// field, by wire key, as the composition holds them once OnInit ran for
+// this request. Handy for integration tests, debugging and reusing a
+// page's data from a script. Like a crawler render, the tab is never
+// registered and OnDispose runs once the response is written.
+//
+// Off by default: StateTab and StateSess fields are server-side state the
+// HTML never shows, so enable it only where every page's state is fine
+// for its visitor to read.
+func WithStateSnapshots() Option { return func(c *config) { c.stateSnapshots = true } }
+
 // WithoutHealthEndpoints disables via's built-in GET /livez, /healthz, and
 // /readyz probes. By default they are served before the session and middleware
 // chain (so a frequent probe never mints a session or logs a request): /livez
@@ -309,17 +402,61 @@ func WithoutSSEReconnect() Option { return func(c *config) { c.noReconnect = tru
 // EXPERIMENTAL: a diagnostic knob; its name or default may change before 1.0.
 func WithVerboseErrors() Option { return func(c *config) { c.verboseErrors = true } }
 
-// WithoutDevChecks disables via's by-default runtime binding check. That check
+// WithoutDevChecks disables via's by-default runtime dev checks. The binding check
 // runs once per composition descriptor (the cost amortizes to ~zero across
 // renders): after OnInit it verifies no bound state handle was orphaned by
 // reassigning a child composition (p.Child = &T{...}), which silently
 // orphans the runtime's by-address binding and leaves the page rendering once
-// then going dead. It's on by default because that footgun is silent and
-// expensive to debug; opt out only if it ever false-positives in your build.
+// then going dead. It's on by default because the footgun is silent and
+// expensive to debug; opt out in production, or if the check ever
+// false-positives in your build.
 //
 // EXPERIMENTAL: a diagnostic knob; its name or default may change before 1.0.
 func WithoutDevChecks() Option { return func(c *config) { c.devChecks = false } }
 
+// WithMarkupChecks scans every rendered page and SSE patch for element ids
+// used more than once — a patch only reaches the first — for signal keys
+// two-way bound by more than one element, and for elements nested where
+// HTML forbids it (a <div> in a <p>, a <button> in an <a>), which the
+// browser silently re-parents. Each warns once per route and id, key or
+// pair (see Ctx.ScopedID and Ctx.ScopedSignal). Off by default: the
+// scan re-parses the HTML of every render, so enable it in development, not
+// production.
+//
+// EXPERIMENTAL: a diagnostic knob; its name or default may change before 1.0.
+func WithMarkupChecks() Option { return func(c *config) { c.markupChecks = true } }
+
+// WithClock replaces the wall clock behind Stream tickers and the session
+// and tab idle-TTL sweeps — inject vt.NewClock in tests to advance time
+// instead of sleeping. Nil keeps the default.
+func WithClock(c Clock) Option {
+	return func(cfg *config) {
+		if c != nil {
+			cfg.clock = c
+		}
+	}
+}
+
+// WithA11yAudit lints every rendered page for common accessibility
+// mistakes — images without alt text, buttons and links without an
+// accessible name, "click here" link text, form controls without a label —
+// and logs each finding once per route at warn level, naming the element
+// by id where it has one. Findings are also kept for [App.A11yFindings],
+// so a test can fail on them. Off by default: the audit re-renders the view,
+// so enable it in development, not production.
+//
+// EXPERIMENTAL: a diagnostic knob; its name or default may change before 1.0.
+func WithA11yAudit() Option { return func(c *config) { c.a11yAudit = true } }
+
+// WithPrettyHTML serves page documents indented, one block-level element
+// per line (see h.Pretty), so view-source and a failing test's dump are
+// readable. Inline content is left as is, so pages lay out the same. SSE
+// patches stay compact. Off by default: the extra whitespace costs bytes on
+// every page, so enable it in development, not production.
+//
+// EXPERIMENTAL: a diagnostic knob; its name or default may change before 1.0.
+func WithPrettyHTML() Option { return func(c *config) { c.prettyHTML = true } }
+
 // WithStrictDecode rejects a client signal value that cannot be represented in
 // its Signal[T] type — a number that overflows the target int/uint/float width,
 // or a value whose JSON shape doesn't match the field — instead of silently
@@ -369,6 +506,14 @@ func WithRequestTooLarge(h http.Handler) Option {
 // purely additive. See the [Metrics] godoc for the event catalogue.
 func WithMetrics(m Metrics) Option { return func(c *config) { c.metrics = m } }
 
+// WithMetricLabels adds the named tab labels (see [Ctx.SetLabel]) to the
+// per-action metrics, so latency can be sliced by tenant or experiment
+// variant. Each distinct value is a new series in most backends: name
+// low-cardinality labels only — a tenant, never a user id.
+func WithMetricLabels(keys ...string) Option {
+	return func(c *config) { c.metricLabels = append(c.metricLabels, keys...) }
+}
+
 // WithBackplane wires the state backplane that makes app/session-scoped
 // reactive state survive restarts and span a cluster. The default (no option,
 // or a nil b) resolves internally to [InMemory], so the Backplane interface is
@@ -0,0 +1,255 @@
+// Package consent records a visitor's cookie and tracking consent and
+// gates the code that depends on it.
+//
+// A choice is stored in the session (so every tab of the visitor sees it
+// at once) and in a long-lived cookie (so it outlives the session). Until
+// the visitor decides, every category but [Necessary] is denied.
+//
+//	func (p *Page) Accept(ctx *via.Ctx) error { consent.AcceptAll(ctx); return nil }
+//	func (p *Page) Reject(ctx *via.Ctx) error { consent.RejectAll(ctx); return nil }
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H {
+//	    return h.Div(
+//	        consent.Banner(ctx, consent.BannerProps{
+//	            Accept: on.Click(p.Accept),
+//	            Reject: on.Click(p.Reject),
+//	        }),
+//	        consent.Script(ctx, consent.Analytics, "https://stats.example.com/a.js"),
+//	    )
+//	}
+//
+// App code asks directly:
+//
+//	if consent.Allowed(ctx, consent.Analytics) { track(ctx) }
+package consent
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/sess"
+)
+
+// Common categories. Any lowercase name made of letters, digits, '-' and
+// '_' works; these are the ones most banners offer.
+const (
+	Necessary   = "necessary" // always allowed; the site doesn't work without it
+	Preferences = "preferences"
+	Analytics   = "analytics"
+	Marketing   = "marketing"
+)
+
+// CookieName is the cookie that carries the choice across sessions.
+const CookieName = "via_consent"
+
+// cookieMaxAge keeps the choice for about a year, after which the
+// visitor is asked again.
+const cookieMaxAge = 365 * 24 * 60 * 60
+
+// rejected is the cookie value for "decided, nothing granted".
+const rejected = "-"
+
+// Choices is a visitor's decision: the categories granted. A category
+// not in the set is denied.
+type Choices struct {
+	Granted   []string  // sorted, without Necessary
+	DecidedAt time.Time // when the visitor chose; zero if restored from the cookie
+}
+
+// Allows reports whether c grants category. Necessary is always allowed.
+func (c Choices) Allows(category string) bool {
+	if category == Necessary {
+		return true
+	}
+	_, found := slices.BinarySearch(c.Granted, category)
+	return found
+}
+
+// Get returns the visitor's choices, and false if they haven't decided
+// yet. src may be any [sess.Source].
+func Get[S sess.Source](src S) (Choices, bool) {
+	if r, ok := sess.Get[record](src); ok {
+		return r.choices, r.decided
+	}
+	return parseCookie(cookie(src))
+}
+
+// record is the session copy. A withdrawn choice is kept as an undecided
+// record rather than cleared, so the cookie still riding on the same
+// request can't bring it back.
+type record struct {
+	choices Choices
+	decided bool
+}
+
+// Decided reports whether the visitor has made a choice.
+func Decided[S sess.Source](src S) bool {
+	_, ok := Get(src)
+	return ok
+}
+
+// Allowed reports whether the visitor granted category. Before they
+// decide only [Necessary] is allowed.
+func Allowed[S sess.Source](src S, category string) bool {
+	c, _ := Get(src)
+	return c.Allows(category)
+}
+
+// Set records the visitor's choice: granted categories are allowed,
+// everything else denied. Call it from an action; the session copy
+// re-renders the page (so gated scripts load and the banner goes away)
+// and the cookie keeps the choice after the session ends.
+func Set(ctx *via.Ctx, granted ...string) {
+	if ctx == nil {
+		return
+	}
+	c := Choices{DecidedAt: time.Now()}
+	for _, g := range granted {
+		if g != Necessary && validCategory(g) && !slices.Contains(c.Granted, g) {
+			c.Granted = append(c.Granted, g)
+		}
+	}
+	slices.Sort(c.Granted)
+	sess.Put(ctx, record{choices: c, decided: true})
+	ctx.SetCookie(&http.Cookie{
+		Name:     CookieName,
+		Value:    formatCookie(c),
+		Path:     "/",
+		MaxAge:   cookieMaxAge,
+		HttpOnly: true,
+		Secure:   via.Scheme(ctx.Request()) == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// AcceptAll grants every category (Preferences, Analytics, Marketing and
+// any extra ones given).
+func AcceptAll(ctx *via.Ctx, extra ...string) {
+	Set(ctx, append([]string{Preferences, Analytics, Marketing}, extra...)...)
+}
+
+// RejectAll denies every category but Necessary.
+func RejectAll(ctx *via.Ctx) { Set(ctx) }
+
+// Withdraw forgets the choice so the banner shows again, as consent
+// rules require withdrawing to be as easy as giving.
+func Withdraw(ctx *via.Ctx) {
+	if ctx == nil {
+		return
+	}
+	sess.Put(ctx, record{})
+	ctx.DelCookie(CookieName)
+}
+
+// BannerProps configures [Banner]. Accept and Reject are the event
+// attributes of the two buttons, typically on.Click of actions that call
+// [AcceptAll] and [RejectAll].
+type BannerProps struct {
+	Message   string // defaults to a short cookie notice
+	PolicyURL string // when set, a "Privacy policy" link follows the message
+	Accept    h.H
+	Reject    h.H
+}
+
+// Banner renders the consent banner while the visitor hasn't decided and
+// nothing once they have.
+func Banner(ctx *via.CtxR, p BannerProps) h.H {
+	if Decided(ctx) {
+		return nil
+	}
+	msg := p.Message
+	if msg == "" {
+		msg = "We use cookies for analytics and marketing only with your consent."
+	}
+	var policy h.H
+	if p.PolicyURL != "" {
+		policy = h.A(h.Href(p.PolicyURL), h.Text("Privacy policy"))
+	}
+	return h.Aside(h.ID("via-consent"), h.Role("dialog"), h.AriaLabel("Cookie consent"),
+		h.P(h.Text(msg), h.Text(" "), policy),
+		h.Button(h.Type("button"), h.Text("Reject"), p.Reject),
+		h.Button(h.Type("button"), h.Text("Accept"), p.Accept),
+	)
+}
+
+// Script loads a third-party script only once the visitor allowed
+// category, and renders nothing before. Render it from View: when the
+// choice is made the page re-renders and the script is added and run
+// then, with no reload.
+func Script(ctx *via.CtxR, category, src string, attrs ...h.H) h.H {
+	if !Allowed(ctx, category) {
+		return nil
+	}
+	return h.Script(append([]h.H{h.Src(src)}, attrs...)...)
+}
+
+// Gate renders node only when the visitor allowed category — for
+// embeds (maps, videos, chat widgets) that set their own cookies.
+func Gate(ctx *via.CtxR, category string, node h.H) h.H {
+	if !Allowed(ctx, category) {
+		return nil
+	}
+	return node
+}
+
+func cookie[S sess.Source](src S) string {
+	switch v := any(src).(type) {
+	case *via.Ctx:
+		return v.Cookie(CookieName)
+	case *via.CtxR:
+		return v.Cookie(CookieName)
+	case *http.Request:
+		if c, err := v.Cookie(CookieName); err == nil {
+			return c.Value
+		}
+	case *via.Request:
+		if c, err := v.Cookie(CookieName); err == nil {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func formatCookie(c Choices) string {
+	if len(c.Granted) == 0 {
+		return rejected
+	}
+	return strings.Join(c.Granted, ".")
+}
+
+func parseCookie(v string) (Choices, bool) {
+	if v == "" {
+		return Choices{}, false
+	}
+	if v == rejected {
+		return Choices{}, true
+	}
+	var c Choices
+	for _, g := range strings.Split(v, ".") {
+		if g == Necessary || !validCategory(g) {
+			continue
+		}
+		if !slices.Contains(c.Granted, g) {
+			c.Granted = append(c.Granted, g)
+		}
+	}
+	slices.Sort(c.Granted)
+	return c, true
+}
+
+func validCategory(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
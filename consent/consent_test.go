@@ -0,0 +1,215 @@
+package consent_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/consent"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shopPage struct{}
+
+func (p *shopPage) Accept(ctx *via.Ctx) error   { consent.AcceptAll(ctx); return nil }
+func (p *shopPage) Reject(ctx *via.Ctx) error   { consent.RejectAll(ctx); return nil }
+func (p *shopPage) Withdraw(ctx *via.Ctx) error { consent.Withdraw(ctx); return nil }
+func (p *shopPage) Stats(ctx *via.Ctx) error {
+	consent.Set(ctx, consent.Analytics)
+	return nil
+}
+
+func (p *shopPage) View(ctx *via.CtxR) h.H {
+	tracking := "tracking off"
+	if consent.Allowed(ctx, consent.Analytics) {
+		tracking = "tracking on"
+	}
+	return h.Main(
+		consent.Banner(ctx, consent.BannerProps{
+			PolicyURL: "/privacy",
+			Accept:    on.Click(p.Accept),
+			Reject:    on.Click(p.Reject),
+		}),
+		h.P(h.Text(tracking)),
+		consent.Script(ctx, consent.Analytics, "https://stats.example.com/a.js", h.Attr("defer")),
+		consent.Gate(ctx, consent.Marketing, h.Div(h.ID("ads"))),
+	)
+}
+
+func TestBanner_showsUntilDecided(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	body := tc.HTML()
+	assert.Contains(t, body, `id="via-consent"`)
+	assert.Contains(t, body, `href="/privacy"`)
+	assert.Contains(t, body, "tracking off")
+	assert.NotContains(t, body, "stats.example.com")
+	assert.NotContains(t, body, `id="ads"`)
+}
+
+func TestAcceptAll_loadsGatedScriptsLiveAndInEveryTab(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Accept").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "tracking on")
+	assert.Contains(t, frame, `src="https://stats.example.com/a.js"`)
+	assert.NotContains(t, frame, `id="via-consent"`, "banner goes once decided")
+
+	body := tc.Fork("/").HTML()
+	assert.Contains(t, body, "tracking on")
+	assert.Contains(t, body, `id="ads"`)
+}
+
+func TestSet_grantsOnlyWhatWasChosen(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Stats").Fire())
+	body := tc.Reload()
+	assert.Contains(t, body, "tracking on")
+	assert.NotContains(t, body, `id="ads"`)
+	assert.NotContains(t, body, `id="via-consent"`)
+}
+
+func TestRejectAll_hidesBannerAndKeepsTrackingOff(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Reject").Fire())
+	body := tc.Reload()
+	assert.NotContains(t, body, `id="via-consent"`)
+	assert.Contains(t, body, "tracking off")
+}
+
+func TestWithdraw_asksAgain(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+	require.Equal(t, 200, tc.Action("Accept").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "tracking on")
+	require.Equal(t, 200, tc.Action("Withdraw").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="via-consent"`)
+
+	body := tc.Reload()
+	assert.Contains(t, body, `id="via-consent"`)
+	assert.Contains(t, body, "tracking off")
+}
+
+// acceptCookie fires Accept on a fresh tab of an app built with opts and
+// returns the consent cookie it set.
+func acceptCookie(t *testing.T, header http.Header, opts ...via.Option) *http.Cookie {
+	t.Helper()
+	app := via.New(opts...)
+	var setCookies atomic.Pointer[[]string]
+	app.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		next.ServeHTTP(w, r)
+		if strings.HasPrefix(r.URL.Path, "/_action/") {
+			v := w.Header().Values("Set-Cookie")
+			setCookies.Store(&v)
+		}
+	})
+	server := vt.Serve(t, app)
+	via.Mount[shopPage](app, "/")
+
+	call := vt.NewClient(t, server, "/").Action("Accept")
+	for k := range header {
+		call.WithHeader(k, header.Get(k))
+	}
+	require.Equal(t, 200, call.Fire())
+	require.NotNil(t, setCookies.Load())
+	for _, line := range *setCookies.Load() {
+		if c, err := http.ParseSetCookie(line); err == nil && c.Name == consent.CookieName {
+			return c
+		}
+	}
+	require.FailNow(t, "no consent cookie set")
+	return nil
+}
+
+func TestAccept_setsLongLivedCookie(t *testing.T) {
+	t.Parallel()
+	got := acceptCookie(t, nil)
+	assert.Equal(t, "analytics.marketing.preferences", got.Value)
+	assert.Greater(t, got.MaxAge, 30*24*60*60)
+	assert.False(t, got.Secure)
+}
+
+func TestAccept_trustsForwardedProtoOnlyFromTrustedProxies(t *testing.T) {
+	t.Parallel()
+	https := http.Header{"X-Forwarded-Proto": {"https"}}
+
+	assert.False(t, acceptCookie(t, https).Secure, "an untrusted client must not mark the cookie Secure")
+	assert.True(t, acceptCookie(t, https, via.WithTrustedProxies("127.0.0.1/32", "::1/128")).Secure)
+}
+
+func TestAllowed_restoresChoiceFromCookieInFreshSession(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[shopPage](app, "/")
+
+	get := func(cookie string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: consent.CookieName, Value: cookie})
+		}
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		b, _ := io.ReadAll(rec.Body)
+		return string(b)
+	}
+
+	body := get("analytics")
+	assert.Contains(t, body, "tracking on")
+	assert.NotContains(t, body, `id="ads"`)
+	assert.NotContains(t, body, `id="via-consent"`)
+
+	body = get("-")
+	assert.Contains(t, body, "tracking off")
+	assert.NotContains(t, body, `id="via-consent"`)
+
+	body = get("bogus<script>")
+	assert.Contains(t, body, "tracking off", "unknown categories grant nothing")
+}
+
+func TestAllowed_fromMiddlewareRequest(t *testing.T) {
+	t.Parallel()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, consent.Allowed(req, consent.Necessary))
+	assert.False(t, consent.Allowed(req, consent.Analytics))
+	assert.False(t, consent.Decided(req))
+
+	req.AddCookie(&http.Cookie{Name: consent.CookieName, Value: "analytics"})
+	assert.True(t, consent.Allowed(req, consent.Analytics))
+	assert.False(t, consent.Allowed(req, consent.Marketing))
+}
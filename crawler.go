@@ -0,0 +1,46 @@
+package via
+
+import (
+	"net/http"
+	"strings"
+)
+
+// crawlerTokens are lowercase User-Agent fragments of the search and
+// link-preview bots IsCrawler recognises.
+var crawlerTokens = []string{
+	"googlebot", "google-inspectiontool", "bingbot", "yandexbot", "baiduspider",
+	"duckduckbot", "slurp", "applebot", "petalbot", "seznambot",
+	"facebookexternalhit", "twitterbot", "linkedinbot", "slackbot",
+	"discordbot", "telegrambot", "whatsapp", "embedly",
+	"ahrefsbot", "semrushbot", "crawler", "spider",
+}
+
+// IsCrawler reports whether r looks like a search or link-preview bot: its
+// User-Agent names a known crawler, or it carries an X-Prerender header, as
+// prerender services send. It is the default detector of
+// [WithCrawlerRender]; wrap it to add your own signals.
+func IsCrawler(r *http.Request) bool {
+	if r.Header.Get("X-Prerender") != "" {
+		return true
+	}
+	ua := strings.ToLower(r.UserAgent())
+	if ua == "" {
+		return false
+	}
+	for _, tok := range crawlerTokens {
+		if strings.Contains(ua, tok) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCrawler reports whether the page GET r should take the crawler path.
+// An in-process render decides for itself: Export always does, RenderPage
+// never — its callers want the live document.
+func (a *App) isCrawler(r *http.Request) bool {
+	if pr, ok := r.Context().Value(pageRenderKey{}).(*pageRender); ok {
+		return pr.static
+	}
+	return a.cfg.crawler != nil && a.cfg.crawler(r)
+}
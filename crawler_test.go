@@ -0,0 +1,108 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type articlePage struct {
+	Slug string `path:"slug"`
+}
+
+// articleDisposed counts articlePage disposals. Only the sequential test
+// reads it; the parallel ones run after it returns.
+var articleDisposed atomic.Int32
+
+func (p *articlePage) OnDispose(ctx *via.Ctx) { articleDisposed.Add(1) }
+
+func (p *articlePage) View(ctx *via.CtxR) h.H {
+	return h.Article(h.H1(h.Text("Post " + p.Slug)))
+}
+
+func getWithUA(t *testing.T, url, ua string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", ua)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCrawlerRender_servesStaticHTMLWithoutATab(t *testing.T) {
+	app := via.New(via.WithCrawlerRender(nil))
+	server := vt.Serve(t, app)
+	via.Mount[articlePage](app, "/posts/{slug}")
+
+	before := articleDisposed.Load()
+	resp, body := getWithUA(t, server.URL+"/posts/hello",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "<h1>Post hello</h1>")
+	assert.NotContains(t, body, "/_sse", "no SSE bootstrap for a crawler")
+	assert.NotContains(t, body, "data-signals")
+	assert.Empty(t, resp.Cookies(), "a crawl must not mint a session")
+	assert.Empty(t, app.Contexts(), "a crawl must not register a tab")
+	assert.Equal(t, before+1, articleDisposed.Load(), "OnDispose runs once the page is written")
+}
+
+func TestCrawlerRender_browsersStillGetTheLivePage(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithCrawlerRender(nil))
+	server := vt.Serve(t, app)
+	via.Mount[articlePage](app, "/posts/{slug}")
+
+	resp, body := getWithUA(t, server.URL+"/posts/hello",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/126.0 Safari/537.36")
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "/_sse")
+	assert.NotEmpty(t, resp.Cookies())
+	assert.Len(t, app.Contexts(), 1)
+}
+
+func TestCrawlerRender_usesCustomDetector(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithCrawlerRender(func(r *http.Request) bool {
+		return r.URL.Query().Get("static") == "1"
+	}))
+	server := vt.Serve(t, app)
+	via.Mount[articlePage](app, "/posts/{slug}")
+
+	_, body := getWithUA(t, server.URL+"/posts/x?static=1", "Googlebot")
+	assert.NotContains(t, body, "/_sse")
+	_, body = getWithUA(t, server.URL+"/posts/x", "Googlebot")
+	assert.Contains(t, body, "/_sse", "the custom detector replaces IsCrawler")
+}
+
+func TestIsCrawler_matchesBotsAndPrerenderHeader(t *testing.T) {
+	t.Parallel()
+
+	for ua, want := range map[string]bool{
+		"Mozilla/5.0 (compatible; bingbot/2.0)":                 true,
+		"facebookexternalhit/1.1":                               true,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0) Safari/604.1": false,
+		"": false,
+		"Mozilla/5.0 (compatible; YandexBot/3.0; +http://ya.ru)": true,
+	} {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("User-Agent", ua)
+		assert.Equal(t, want, via.IsCrawler(r), ua)
+	}
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Prerender", "1")
+	assert.True(t, via.IsCrawler(r))
+}
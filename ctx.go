@@ -2,10 +2,11 @@ package via
 
 import (
 	"net/http"
+	"net/url"
 	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/go-via/via/h"
 )
@@ -14,6 +15,7 @@ import (
 // for the lifetime of the SSE stream, passed to View/OnInit/Action methods.
 type Ctx struct {
 	id           string // tab id, generated per page request
+	idAttr       h.H    // h.ID(id), built once: every render wraps the view in it
 	app          *App
 	desc         *cmpDescriptor
 	cmpReflect   reflect.Value // reflect.ValueOf(<bound *C>), boxed once at request entry
@@ -41,11 +43,23 @@ type Ctx struct {
 	// which is itself proof the tab is alive — the TTL sweep skips such a
 	// Ctx, so lastAccess governs only stream-less ctxs.
 	connected atomic.Int32
+	// connectedAt is the UnixNano the latest SSE stream opened (0 before
+	// the first), surfaced through ContextInfo.
+	connectedAt atomic.Int64
 	// everConnected latches once the first SSE stream opens. Unlike
 	// connected it never resets, so runSSEStream can tell a reconnect
 	// (resync the view — the client may have drifted during the gap)
 	// from the first connect (the page document already carries the view).
 	everConnected atomic.Bool
+	// replay numbers the events sent to the tab and keeps the newest for
+	// a reconnect to resume from (WithSSEReplayBytes).
+	replay replayRing
+	// frameGap is the adaptive pacing interval (nanoseconds) the live
+	// stream currently holds between frames; 0 at full rate.
+	frameGap atomic.Int64
+	// sigExp is the unix expiry of the action signatures last issued to
+	// the tab (WithActionSigning); 0 before the first.
+	sigExp atomic.Int64
 
 	// lastSignals holds the most recent signals payload from an action
 	// POST so via.DecodeForm can read keys that aren't tracked by typed
@@ -60,11 +74,37 @@ type Ctx struct {
 	// by queue.mu, like the queue it shadows.
 	pushedSignals map[string]any
 
+	flashes []FlashMessage // taken off the session by the page render; see Flash
+
+	static bool // rendered for a crawler (WithCrawlerRender): never registered, no SSE bootstrap
+
 	cspNonce string // lazily generated per-request CSP nonce
 	docNonce string // page document's CSP nonce, captured at render for the push path
 
 	connectOnce sync.Once // guards OnConnect dispatch
 
+	tickers []*Ticker         // Streams on this tab not yet stopped, for Tickers; guarded by mu
+	jsFns   map[string]string // RegisterJS sources by name, as last shipped; guarded by mu
+
+	// evals holds the reply channel of each Ctx.Eval awaiting the
+	// browser, by eval id; guarded by mu.
+	evals   map[string]chan evalReply
+	evalSeq atomic.Uint64
+
+	// locale is the tab's formatting locale once SetLocale or Locale
+	// fixed it; nil before.
+	locale atomic.Pointer[h.Locale]
+
+	// popFn is the OnPopState handler, guarded by mu; popOnce installs
+	// the browser's popstate listener.
+	popFn   func(*Ctx, *url.URL)
+	popOnce sync.Once
+
+	// hidden mirrors the client's document.visibilityState, reported once
+	// a ticker opts in with PauseWhileHidden (visOnce installs the listener).
+	hidden  atomic.Bool
+	visOnce sync.Once
+
 	// actionMu serializes action handlers per-Ctx. Without it, two POSTs
 	// for the same tab arriving concurrently race on State writes,
 	// dirty bits, and Writer/Request assignment.
@@ -77,6 +117,18 @@ type Ctx struct {
 	rendering     bool
 	inflightReads map[string]struct{}
 	lastReads     map[string]struct{}
+	spareReads    map[string]struct{}      // the set before lastReads, recycled by beginRender
+	regions       map[string]func() h.H    // CtxR.Region sub-views of the last full render, by name; guarded by readsMu
+	regionSyncs   []string                 // SyncRegion names awaiting syncRegions; guarded by readsMu
+	lazyShown     map[string]bool          // CtxR.Lazy regions the browser has scrolled to; guarded by readsMu
+	portals       []h.H                    // CtxR.Portal content of the current render; guarded by readsMu
+	portalUsed    bool                     // a render has used CtxR.Portal, so re-renders patch the host; guarded by readsMu
+	formGuards    map[*FormGuard]formIssue // FormGuard.Fields issues, by guard; guarded by readsMu
+
+	// labelsMu guards labels (SetLabel); read by loggers, metrics and
+	// label-targeted broadcasts from any goroutine.
+	labelsMu sync.RWMutex
+	labels   map[string]string
 
 	// Typed dispatch funcs, bound once at newCtx by extracting each
 	// reflect-discovered method as a method value (`cmpVal.Method(i).
@@ -90,12 +142,21 @@ type Ctx struct {
 	initFn    func(*Ctx) error
 	connectFn func(*Ctx) error
 	disposeFn func(*Ctx)
-	actionFns []func(*Ctx) error // indexed by descriptor actionSlot index
+	// persistFlush saves each persisted StateTab's pending write; run
+	// after the OnDispose hooks. Guarded by mu.
+	persistFlush []func()
+	actionFns    []func(*Ctx) error // indexed by descriptor actionSlot index
+
+	mu sync.Mutex // guards w / r / args / client* / tickers / jsFns and disposed flag
 
-	mu sync.Mutex // guards w / r and disposed flag
+	w    http.ResponseWriter
+	r    *http.Request
+	args url.Values // on.Arg values of the in-flight action; nil outside one
 
-	w http.ResponseWriter
-	r *http.Request
+	// clientHeader and clientAddr are the latest page load's or action's
+	// headers and RemoteAddr, kept past the request for Client.
+	clientHeader http.Header
+	clientAddr   string
 }
 
 // CtxR is the read-only render context passed to View(ctx *CtxR) h.H.
@@ -243,6 +304,33 @@ func (ctx *Ctx) Request() *http.Request {
 	return ctx.r
 }
 
+// EventArg returns the argument name bound to the triggering element with
+// on.Arg, or "" if the element bound none (or the caller isn't on the
+// action goroutine). It lets one action serve every row of a list without
+// a signal per element:
+//
+//	h.Each(items, func(it Item) h.H {
+//	    return h.Button(h.Text("Delete"), on.Click(p.Delete, on.Arg("id", it.ID)))
+//	})
+//
+//	func (p *Page) Delete(ctx *via.Ctx) { db.Delete(ctx.EventArg("id")) }
+//
+// The value arrives from the client like any request input: validate it
+// before trusting it.
+func (ctx *Ctx) EventArg(name string) string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.args.Get(name)
+}
+
+// EventArgs returns every value bound to name, in order — on.Sortable's
+// new item order arrives this way. nil when there are none.
+func (ctx *Ctx) EventArgs(name string) []string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return slices.Clone(ctx.args[name])
+}
+
 // Patch returns the imperative client-push handle for this request —
 // the escape hatch for pushing a signal value to a key not bound to a
 // typed Signal[T], or morphing an arbitrary element fragment into the
@@ -313,7 +401,7 @@ func (ctx *Ctx) DelCookie(name string) {
 }
 
 func (ctx *Ctx) touch() {
-	ctx.lastAccess.Store(time.Now().UnixNano())
+	ctx.lastAccess.Store(ctx.app.now().UnixNano())
 }
 
 // markSignalDirty records that slot needs a signal patch on the next
@@ -419,7 +507,15 @@ func (ctx *Ctx) markStateDirty() {
 func (ctx *Ctx) beginRender() {
 	ctx.readsMu.Lock()
 	ctx.rendering = true
-	ctx.inflightReads = make(map[string]struct{})
+	clear(ctx.regions)
+	clear(ctx.portals)
+	ctx.portals = ctx.portals[:0]
+	if ctx.spareReads != nil {
+		clear(ctx.spareReads)
+		ctx.inflightReads, ctx.spareReads = ctx.spareReads, nil
+	} else {
+		ctx.inflightReads = make(map[string]struct{})
+	}
 	ctx.readsMu.Unlock()
 }
 
@@ -428,6 +524,7 @@ func (ctx *Ctx) beginRender() {
 func (ctx *Ctx) endRender() {
 	ctx.readsMu.Lock()
 	ctx.rendering = false
+	ctx.spareReads = ctx.lastReads
 	ctx.lastReads = ctx.inflightReads
 	ctx.inflightReads = nil
 	ctx.readsMu.Unlock()
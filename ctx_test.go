@@ -301,6 +301,102 @@ func TestDone_channelClosedInsideOnDispose(t *testing.T) {
 		"ctx.Done() must be a closed channel by the time OnDispose runs")
 }
 
+// disposeProbe rides in on the page request's context, so each test
+// counts the disposals of its own page's chart.
+type disposeProbe struct {
+	forward  bool // the root's OnDispose disposes a chart still shown
+	disposed atomic.Int32
+}
+
+type disposeProbeKey struct{}
+
+type disposeChart struct {
+	N     via.StateTabNum[int]
+	probe *disposeProbe
+}
+
+func (c *disposeChart) OnDispose(ctx *via.Ctx) { c.probe.disposed.Add(1) }
+
+func (c *disposeChart) View(ctx *via.CtxR) h.H { return h.Div() }
+
+type disposeBroken struct{ N via.StateTabNum[int] }
+
+func (c *disposeBroken) OnDispose(ctx *via.Ctx) { panic("cleanup failed") }
+
+func (c *disposeBroken) View(ctx *via.CtxR) h.H { return h.Div() }
+
+type disposeTabsPage struct {
+	Chart  *disposeChart
+	Broken *disposeBroken
+	Tab    via.StateTabStr
+}
+
+func (p *disposeTabsPage) OnInit(ctx *via.Ctx) error {
+	p.Chart.probe = ctx.Request().Context().Value(disposeProbeKey{}).(*disposeProbe)
+	return nil
+}
+
+func (p *disposeTabsPage) ShowTable(ctx *via.Ctx) {
+	ctx.Dispose(p.Broken)
+	ctx.Dispose(p.Chart)
+	p.Tab.Write(ctx, "table")
+}
+
+func (p *disposeTabsPage) OnDispose(ctx *via.Ctx) {
+	if p.Chart.probe.forward && p.Tab.Read(ctx) != "table" {
+		ctx.Dispose(p.Chart)
+	}
+}
+
+func (p *disposeTabsPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Text("tab=" + p.Tab.Read(ctx)))
+}
+
+func newDisposeApp(t *testing.T, probe *disposeProbe) (*via.App, *vt.Client) {
+	t.Helper()
+	app := via.New()
+	app.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), disposeProbeKey{}, probe)))
+	})
+	via.Mount[disposeTabsPage](app, "/")
+	return app, vt.NewClient(t, vt.Serve(t, app), "/")
+}
+
+func TestDispose_runsTheChildsHookFromAnAction(t *testing.T) {
+	t.Parallel()
+
+	probe := &disposeProbe{forward: true}
+	app, tc := newDisposeApp(t, probe)
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, http.StatusOK, tc.Action("ShowTable").Fire(),
+		"a panicking child's OnDispose is logged, not fatal to the action")
+	vt.AwaitFrame(t, frames, 2*time.Second, "tab=table")
+	assert.EqualValues(t, 1, probe.disposed.Load())
+
+	// Hidden and already disposed: the root doesn't dispose it again.
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.EqualValues(t, 1, probe.disposed.Load())
+}
+
+func TestOnDispose_leavesChildrenToTheRoot(t *testing.T) {
+	t.Parallel()
+
+	for _, forward := range []bool{false, true} {
+		probe := &disposeProbe{forward: forward}
+		app, _ := newDisposeApp(t, probe)
+		require.NoError(t, app.Shutdown(context.Background()))
+
+		want := 0
+		if forward {
+			want = 1
+		}
+		assert.EqualValues(t, want, probe.disposed.Load(),
+			"a child is disposed once when the root forwards it, and never by the runtime itself (forward=%v)", forward)
+	}
+}
+
 var disposedFlagSeenInsideOnDispose atomic.Bool
 
 type disposedSelfCheck struct{}
@@ -0,0 +1,103 @@
+package via
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// cursorKeyPrefix namespaces external-data cursors in the render read set so
+// a cursor named like a state wire key ("todos") can never alias it.
+const cursorKeyPrefix = "cursor:"
+
+func cursorKey(cursor string) string { return cursorKeyPrefix + cursor }
+
+// ChangeSource is the integration point for an external store that can push
+// change notifications — Postgres LISTEN/NOTIFY, SQLite update hooks, a CDC
+// stream. Watch blocks, calling notify with the cursor of every query whose
+// result changed, until ctx is cancelled (App shutdown). A cursor is any
+// string both sides agree on: a table name, "orders:open", a query hash.
+//
+// A non-nil return while the app is running is treated as a dropped
+// connection: the runtime logs it and calls Watch again with jittered
+// backoff, so an implementation need not reconnect on its own. A Watch that
+// delivered a notification, or ran for 30s, was a healthy connection: its
+// drop restarts the backoff from the shortest wait.
+type ChangeSource interface {
+	Watch(ctx context.Context, notify func(cursor string)) error
+}
+
+// Subscribe marks the view being rendered as depending on cursor: a later
+// [App.Invalidate] of the same cursor (or a [ChangeSource] notification for
+// it) re-renders this tab. Like a StateApp Read, the subscription is the
+// render's read set — a render that no longer calls Subscribe drops it.
+//
+//	func (p *Orders) View(ctx *via.CtxR) h.H {
+//	    ctx.Subscribe("orders")
+//	    return h.Ul(h.Each(db.OpenOrders(), orderRow))
+//	}
+func (r *CtxR) Subscribe(cursor string) {
+	if r == nil || r.ctx == nil || cursor == "" {
+		return
+	}
+	r.ctx.trackRead(cursorKey(cursor))
+}
+
+// Invalidate re-renders every live tab whose last render subscribed to
+// cursor. Call it after a write to external data a view reads directly:
+//
+//	db.InsertOrder(o)
+//	app.Invalidate("orders")
+//
+// When a backplane is wired the invalidation rides the shared broadcast feed
+// and reaches every pod; otherwise it stays pod-local. Empty cursor is a
+// no-op.
+func (a *App) Invalidate(cursor string) {
+	if cursor == "" {
+		return
+	}
+	a.dispatchBroadcast(broadcastRecord{Kind: bcInvalidate, Cursor: cursor})
+}
+
+// watchHealthyAfter is how long a Watch call must run, without delivering
+// anything, to count as a healthy connection whose drop resets the backoff.
+const watchHealthyAfter = 30 * time.Second
+
+// WatchChanges runs src for the lifetime of the app, re-rendering the tabs
+// subscribed to each cursor it reports. Notifications apply to THIS pod only:
+// a store notification such as LISTEN/NOTIFY already reaches every pod that
+// watches it, so fanning it out again over the backplane would re-render
+// each tab once per pod. Stops on Shutdown. nil src is a no-op.
+func (a *App) WatchChanges(src ChangeSource) {
+	if src == nil {
+		return
+	}
+	a.bgWG.Add(1)
+	go func() {
+		defer a.bgWG.Done()
+		var delivered atomic.Bool
+		notify := func(cursor string) {
+			delivered.Store(true)
+			if cursor != "" {
+				a.applyBroadcast(broadcastRecord{Kind: bcInvalidate, Cursor: cursor})
+			}
+		}
+		for attempt := 1; ; attempt++ {
+			delivered.Store(false)
+			began := time.Now()
+			err := src.Watch(a.backplaneCtx, notify)
+			if a.shuttingDown() || a.backplaneCtx.Err() != nil {
+				return
+			}
+			if delivered.Load() || time.Since(began) >= watchHealthyAfter {
+				// The connection was up: this drop starts a fresh backoff
+				// rather than extending the last outage's.
+				attempt = 1
+			}
+			a.logWarn(nil, "via: change source stopped (attempt %d), restarting: %v", attempt, err)
+			if !a.tailerSleep(attempt) {
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,162 @@
+package via_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// externalRows stands in for a database table the view reads directly.
+type externalRows struct {
+	mu   sync.Mutex
+	rows []string
+}
+
+func (e *externalRows) add(row string) {
+	e.mu.Lock()
+	e.rows = append(e.rows, row)
+	e.mu.Unlock()
+}
+
+func (e *externalRows) snapshot() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.rows...)
+}
+
+var cursorRows = map[string]*externalRows{}
+var cursorRowsMu sync.Mutex
+
+func rowsFor(id string) *externalRows {
+	cursorRowsMu.Lock()
+	defer cursorRowsMu.Unlock()
+	r, ok := cursorRows[id]
+	if !ok {
+		r = &externalRows{}
+		cursorRows[id] = r
+	}
+	return r
+}
+
+type cursorPage struct {
+	Table string `query:"table"`
+}
+
+func (p *cursorPage) View(ctx *via.CtxR) h.H {
+	ctx.Subscribe(p.Table)
+	return h.Ul(h.ID("rows"), h.Each(rowsFor(p.Table).snapshot(), func(r string) h.H {
+		return h.Li(h.Text(r))
+	}))
+}
+
+type chanSource chan string
+
+func (s chanSource) Watch(ctx context.Context, notify func(string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c := <-s:
+			notify(c)
+		}
+	}
+}
+
+func TestInvalidate_rerendersSubscribedTabs(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[cursorPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/?table=invalidate")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	rowsFor("invalidate").add("row-one")
+	app.Invalidate("invalidate")
+
+	vt.AwaitFrame(t, frames, 2*time.Second, "<li>row-one</li>")
+}
+
+func TestInvalidate_skipsTabsSubscribedToOtherCursors(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[cursorPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/?table=skip-a")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	rowsFor("skip-a").add("row-a")
+	app.Invalidate("skip-b")
+
+	assert.NotContains(t, drainFor(frames, 200*time.Millisecond), "row-a")
+}
+
+func TestWatchChanges_rerendersOnSourceNotification(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[cursorPage](app, "/")
+	src := make(chanSource, 1)
+	app.WatchChanges(src)
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+
+	tc := vt.NewClient(t, server, "/?table=watch")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	rowsFor("watch").add("from-source")
+	src <- "watch"
+
+	vt.AwaitFrame(t, frames, 2*time.Second, "<li>from-source</li>")
+}
+
+// flakySource fails its first three Watch calls at once, then delivers a
+// notification before failing a fourth time, then holds until shutdown.
+type flakySource struct{ calls atomic.Int32 }
+
+func (s *flakySource) Watch(ctx context.Context, notify func(string)) error {
+	switch s.calls.Add(1) {
+	case 1, 2, 3:
+		return errors.New("connection refused")
+	case 4:
+		notify("flaky")
+		return errors.New("connection reset")
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWatchChanges_resetsTheBackoffAfterAHealthyWatch(t *testing.T) {
+	t.Parallel()
+
+	app, _, logger := newLoggedApp(t, via.LogWarn)
+	src := &flakySource{}
+	app.WatchChanges(src)
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+
+	require.Eventually(t, func() bool { return src.calls.Load() == 5 }, 5*time.Second, 5*time.Millisecond)
+	var attempts []string
+	for _, r := range logger.snapshot() {
+		if strings.Contains(r.msg, "change source stopped") {
+			attempts = append(attempts, r.msg[strings.Index(r.msg, "(")+1:strings.Index(r.msg, ")")])
+		}
+	}
+	assert.Equal(t, []string{"attempt 1", "attempt 2", "attempt 3", "attempt 1"}, attempts,
+		"a Watch that delivered must restart the backoff")
+}
@@ -48,6 +48,21 @@ type kindedSlot struct {
 	kind      reflect.Kind
 }
 
+// childSlot is one nested child composition, for ScopedID/ScopedSignal:
+// the field to compare an owner pointer against, and the prefixes it stamps.
+type childSlot struct {
+	fieldPath  []int
+	wirePrefix string // "Tab.Chart", as its signals' wire keys
+	idPrefix   string // "Tab-Chart" for the wire prefix "Tab.Chart"
+}
+
+// formSlot is one via.Form[T] field and the wire key its signals nest
+// under.
+type formSlot struct {
+	fieldPath []int
+	wireKey   string
+}
+
 type actionSlot struct {
 	name        string
 	methodIndex int
@@ -62,12 +77,15 @@ type cmpDescriptor struct {
 	paramSlots   []kindedSlot
 	querySlots   []kindedSlot
 	fileSlots    []fileSlot
+	formSlots    []formSlot
+	childSlots   []childSlot
 	actionSlots  []actionSlot
 	actionByName map[string]int
-	viewIdx      int // method index of View on *C
-	initIdx      int // method index of OnInit or -1
-	connectIdx   int // method index of OnConnect or -1
-	disposeIdx   int // method index of OnDispose or -1
+	viewIdx      int  // method index of View on *C
+	initIdx      int  // method index of OnInit or -1
+	connectIdx   int  // method index of OnConnect or -1
+	disposeIdx   int  // method index of OnDispose or -1
+	shell        bool // *C implements CacheableShell
 
 	groupMW []Middleware // middleware from the owning Group, if any
 
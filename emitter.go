@@ -0,0 +1,39 @@
+package via
+
+// Emitter is a typed event a child composition publishes to whichever
+// parent holds it, so a reusable child (a picker, an editor) reports what
+// happened without knowing the parent's state shape:
+//
+//	type Picker struct {
+//	    Picked via.Emitter[string]
+//	}
+//
+//	func (c *Picker) Pick(ctx *via.Ctx, item string) { c.Picked.Emit(ctx, item) }
+//
+//	func (p *Page) OnInit(ctx *via.Ctx) error {
+//	    p.Picker.Picked.On(func(ctx *via.Ctx, item string) { p.Chosen.Write(ctx, item) })
+//	    return nil
+//	}
+//
+// The composition tree belongs to one tab, so handlers registered in
+// OnInit hear only that tab's child. Emit runs them in order, on the
+// caller's goroutine; an Emitter no one listens to is a no-op. Register
+// handlers from OnInit only — On is not safe to call concurrently with
+// Emit.
+type Emitter[T any] struct {
+	handlers []func(*Ctx, T)
+}
+
+// On adds fn to the handlers Emit calls.
+func (e *Emitter[T]) On(fn func(*Ctx, T)) {
+	if fn != nil {
+		e.handlers = append(e.handlers, fn)
+	}
+}
+
+// Emit calls every handler registered with On, in registration order.
+func (e *Emitter[T]) Emit(ctx *Ctx, v T) {
+	for _, fn := range e.handlers {
+		fn(ctx, v)
+	}
+}
@@ -0,0 +1,73 @@
+package via_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+type colorPicker struct {
+	Picked via.Emitter[string]
+}
+
+func (c *colorPicker) Pick(ctx *via.Ctx, color string) { c.Picked.Emit(ctx, color) }
+
+func (c *colorPicker) View(ctx *via.CtxR, onPick func(color string) h.H) h.H {
+	return h.Div(h.Each([]string{"red", "blue"}, func(color string) h.H {
+		return h.Button(h.Text(color), onPick(color))
+	}))
+}
+
+type pickerPage struct {
+	Left, Right *colorPicker
+	Chosen      via.StateTab[string]
+}
+
+func (p *pickerPage) OnInit(ctx *via.Ctx) error {
+	p.Left.Picked.On(func(ctx *via.Ctx, color string) { p.Chosen.Write(ctx, "left:"+color) })
+	p.Right.Picked.On(func(ctx *via.Ctx, color string) { p.Chosen.Write(ctx, "right:"+color) })
+	return nil
+}
+
+func (p *pickerPage) PickLeft(ctx *via.Ctx)  { p.Left.Pick(ctx, ctx.EventArg("color")) }
+func (p *pickerPage) PickRight(ctx *via.Ctx) { p.Right.Pick(ctx, ctx.EventArg("color")) }
+
+func (p *pickerPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.ID("chosen"), h.Text("chosen="+p.Chosen.Read(ctx))),
+		p.Left.View(ctx, func(c string) h.H { return on.Click(p.PickLeft, on.Arg("color", c)) }),
+		p.Right.View(ctx, func(c string) h.H { return on.Click(p.PickRight, on.Arg("color", c)) }),
+	)
+}
+
+func TestEmitter_deliversAChildEventToItsParent(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[pickerPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("PickRight").WithArg("color", "blue").Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "chosen=right:blue")
+	tc.Action("PickLeft").WithArg("color", "red").Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "chosen=left:red")
+}
+
+func TestEmitter_withoutListenersIsANoOp(t *testing.T) {
+	t.Parallel()
+	var e via.Emitter[int]
+	assert.NotPanics(t, func() { e.Emit(nil, 1) })
+
+	var got []int
+	e.On(func(_ *via.Ctx, v int) { got = append(got, v) })
+	e.On(func(_ *via.Ctx, v int) { got = append(got, v*10) })
+	e.Emit(nil, 2)
+	assert.Equal(t, []int{2, 20}, got, "handlers run in registration order")
+}
@@ -0,0 +1,60 @@
+package via
+
+import (
+	"net/http"
+	"strings"
+)
+
+// EndpointKind classifies a request for access logs and metrics, so
+// dashboards and log streams can keep the framework's own traffic — a
+// stream per open tab, the runtime script, probes — apart from the pages
+// people visit. See [App.Endpoint].
+type EndpointKind string
+
+const (
+	// EndpointPage is everything the app itself serves: pages, API
+	// endpoints and its own handlers.
+	EndpointPage EndpointKind = "page"
+	// EndpointAction is an action POST.
+	EndpointAction EndpointKind = "action"
+	// EndpointInternal is framework plumbing: the SSE stream and its
+	// beacons, the Datastar runtime, plugin assets, the health probes,
+	// and any path added with [WithInternalPaths].
+	EndpointInternal EndpointKind = "internal"
+)
+
+// internalPaths are the framework's own endpoints; a trailing "/" matches
+// the whole subtree.
+var internalPaths = []string{
+	"/_sse", "/_sse/", "/_datastar.js", "/_plugins/", "/via/assets/",
+	"/livez", "/readyz", "/healthz",
+}
+
+// WithInternalPaths marks more of the app's own paths as
+// [EndpointInternal] — a Prometheus scrape endpoint, favicon.ico,
+// robots.txt. A path ending in "/" covers everything under it.
+func WithInternalPaths(paths ...string) Option {
+	return func(c *config) { c.internalPaths = append(c.internalPaths, paths...) }
+}
+
+// Endpoint reports which kind of endpoint r is for. Inside middleware r's
+// path is already relative to [WithBasePath].
+func (a *App) Endpoint(r *http.Request) EndpointKind {
+	p := r.URL.Path
+	if strings.HasPrefix(p, "/_action/") {
+		return EndpointAction
+	}
+	if matchesPath(internalPaths, p) || matchesPath(a.cfg.internalPaths, p) {
+		return EndpointInternal
+	}
+	return EndpointPage
+}
+
+func matchesPath(paths []string, p string) bool {
+	for _, m := range paths {
+		if p == m || (strings.HasSuffix(m, "/") && strings.HasPrefix(p, m)) {
+			return true
+		}
+	}
+	return false
+}
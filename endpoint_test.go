@@ -0,0 +1,45 @@
+package via_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoint_classifiesFrameworkTraffic(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithInternalPaths("/metrics", "/static/"))
+
+	for path, want := range map[string]via.EndpointKind{
+		"/":                   via.EndpointPage,
+		"/_sse":               via.EndpointInternal,
+		"/_sse/close":         via.EndpointInternal,
+		"/_datastar.js":       via.EndpointInternal,
+		"/via/assets/x/y.css": via.EndpointInternal,
+		"/readyz":             via.EndpointInternal,
+		"/metrics":            via.EndpointInternal,
+		"/static/logo.svg":    via.EndpointInternal,
+		"/metrics/extra":      via.EndpointPage,
+		"/_action/Save":       via.EndpointAction,
+		"/_ssefake":           via.EndpointPage,
+	} {
+		assert.Equal(t, want, app.Endpoint(httptest.NewRequest(http.MethodGet, path, nil)), path)
+	}
+}
+
+func TestEndpoint_countsRequestsByKind(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	app := via.New(via.WithMetrics(m), via.WithBasePath("/app"))
+
+	for _, p := range []string{"/app/_datastar.js", "/livez", "/app/missing"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.request.total:endpoint,internal")
+	assert.Contains(t, m.counters, "via.request.total:endpoint,page")
+}
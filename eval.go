@@ -0,0 +1,123 @@
+package via
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// defaultEvalTimeout bounds a Ctx.Eval given no timeout.
+const defaultEvalTimeout = 5 * time.Second
+
+// ErrEvalTimeout is returned by [Ctx.Eval] when the browser doesn't reply
+// in time — the tab has no live stream, or the expression never settled.
+var ErrEvalTimeout = errors.New("via: Eval timed out waiting for the browser")
+
+// errEvalDisposed is returned by Ctx.Eval when the tab goes away first.
+var errEvalDisposed = errors.New("via: Eval: tab disposed")
+
+// evalReply is the browser's answer to one Ctx.Eval: the JSON-encoded
+// value, or the message of what the expression threw.
+type evalReply struct {
+	V   json.RawMessage `json:"v"`
+	Err *string         `json:"e"`
+}
+
+// Eval evaluates the JavaScript expression expr in the browser and returns
+// its value, JSON-encoded — for what only the client knows: an element's
+// size, the clipboard, a third-party widget's state. A promise is awaited,
+// and undefined comes back as null:
+//
+//	raw, err := ctx.Eval("document.getElementById('chart').getBoundingClientRect()", time.Second)
+//	var box struct{ Width, Height float64 }
+//	err = json.Unmarshal(raw, &box)
+//
+// Eval blocks until the reply arrives, the tab goes away, or timeout
+// passes (5s when zero), returning [ErrEvalTimeout] in that last case. An
+// expression that throws, or whose value doesn't JSON-encode, returns an
+// error carrying the message. The request ships at once, even from inside
+// an action, so it sees the page as it was before the action's patches.
+// The browser's reply is checked like an action: behind the route's group
+// middleware, signed and audited as "eval". expr is code: never build it
+// from user input — pass data in with [Ctx.ExecScriptf] instead.
+func (ctx *Ctx) Eval(expr string, timeout time.Duration) (json.RawMessage, error) {
+	if ctx == nil || ctx.queue == nil {
+		return nil, errEvalDisposed
+	}
+	if timeout <= 0 {
+		timeout = defaultEvalTimeout
+	}
+	id := strconv.FormatUint(ctx.evalSeq.Add(1), 10)
+	ch := make(chan evalReply, 1)
+	ctx.mu.Lock()
+	if ctx.disposed {
+		ctx.mu.Unlock()
+		return nil, errEvalDisposed
+	}
+	if ctx.evals == nil {
+		ctx.evals = map[string]chan evalReply{}
+	}
+	ctx.evals[id] = ch
+	ctx.mu.Unlock()
+	defer func() {
+		ctx.mu.Lock()
+		delete(ctx.evals, id)
+		ctx.mu.Unlock()
+	}()
+
+	var base string
+	key := ctx.id + " " + id
+	if a := ctx.app; a != nil {
+		base = a.cfg.basePath
+		if a.signer.covers(evalAction) {
+			// Signed for as long as the reply may take, however long that
+			// outlives the tab's other signatures.
+			key += " " + a.signer.token(ctx.id, evalAction, a.now().Add(max(a.signer.ttl, timeout)).Unix())
+		}
+	}
+	q := ctx.queue
+	q.mu.Lock()
+	q.evals.WriteString("(async(u,k)=>{let r;try{r=JSON.stringify({v:await(" + expr + ")})}" +
+		"catch(e){r=JSON.stringify({e:String(e&&e.message||e)})}" +
+		"fetch(u,{method:'POST',body:k+' '+r})})(" +
+		jsString(base+"/_sse/eval") + "," + jsString(key) + ");")
+	q.mu.Unlock()
+	// Bypass the action-scoped wake hold, as Progress.Set does: the
+	// drain ships evals alongside signals while an action is in flight.
+	q.signal()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case rep := <-ch:
+		if rep.Err != nil {
+			return nil, errors.New("via: Eval: " + *rep.Err)
+		}
+		if len(rep.V) == 0 {
+			return json.RawMessage("null"), nil
+		}
+		return rep.V, nil
+	case <-t.C:
+		return nil, ErrEvalTimeout
+	case <-ctx.doneChan:
+		return nil, errEvalDisposed
+	}
+}
+
+// answerEval hands the browser's reply to the Eval waiting on id. A reply
+// nobody waits for any more — it timed out — is dropped.
+func (ctx *Ctx) answerEval(id, body string) {
+	var rep evalReply
+	if err := json.Unmarshal([]byte(body), &rep); err != nil {
+		msg := "malformed reply"
+		rep = evalReply{Err: &msg}
+	}
+	ctx.mu.Lock()
+	ch := ctx.evals[id]
+	delete(ctx.evals, id)
+	ctx.mu.Unlock()
+	if ch != nil {
+		ch <- rep
+	}
+}
@@ -0,0 +1,125 @@
+package via_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type measurePage struct {
+	Width via.StateTab[int]
+	Note  via.StateTabStr
+}
+
+func (p *measurePage) Measure(ctx *via.Ctx) error {
+	raw, err := ctx.Eval("document.body.clientWidth", 2*time.Second)
+	if err != nil {
+		return err
+	}
+	var w int
+	if err := json.Unmarshal(raw, &w); err != nil {
+		return err
+	}
+	p.Width.Write(ctx, w)
+	return nil
+}
+
+func (p *measurePage) Quick(ctx *via.Ctx) error {
+	_, err := ctx.Eval("new Promise(() => {})", 20*time.Millisecond)
+	if errors.Is(err, via.ErrEvalTimeout) {
+		p.Note.Write(ctx, "timed out")
+	}
+	return nil
+}
+
+func (p *measurePage) Broken(ctx *via.Ctx) error {
+	_, err := ctx.Eval("widget.state()", 2*time.Second)
+	p.Note.Write(ctx, err.Error())
+	return nil
+}
+
+func (p *measurePage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Textf("width=%d note=%s", p.Width.Read(ctx), p.Note.Read(ctx)))
+}
+
+func TestEval_returnsTheBrowsersAnswerToTheAction(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[measurePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	fired := make(chan int, 1)
+	go func() { fired <- tc.Action("Measure").Fire() }()
+
+	// The request ships while the action is still waiting on it.
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "/_sse/eval", "document.body.clientWidth")
+	require.Equal(t, 200, tc.AnswerEval(frame, 640))
+	require.Equal(t, 200, <-fired)
+	vt.AwaitFrame(t, frames, 2*time.Second, "width=640")
+}
+
+func TestEval_signedReplyIsAnsweredAndAudited(t *testing.T) {
+	t.Parallel()
+	log := &auditLog{}
+	app := via.New(via.WithAuditHook(log.record),
+		via.WithActionSigning(via.ActionSigning{Key: sigKey}))
+	via.Mount[measurePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	fired := make(chan int, 1)
+	go func() { fired <- tc.Action("Measure").Fire() }()
+
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "/_sse/eval")
+	require.Equal(t, 200, tc.AnswerEval(frame, 480))
+	require.Equal(t, 200, <-fired)
+	vt.AwaitFrame(t, frames, 2*time.Second, "width=480")
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	var actions []string
+	for _, ev := range log.events {
+		actions = append(actions, ev.Action)
+	}
+	assert.Contains(t, actions, "eval")
+}
+
+func TestEval_timesOutWithoutAReply(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[measurePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Quick").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "note=timed out")
+}
+
+func TestEval_returnsWhatTheExpressionThrew(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[measurePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	fired := make(chan int, 1)
+	go func() { fired <- tc.Action("Broken").Fire() }()
+
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "widget.state()")
+	require.Equal(t, 200, tc.FailEval(frame, "widget is not defined"))
+	require.Equal(t, 200, <-fired)
+	got := vt.AwaitFrame(t, frames, 2*time.Second, "note=via: Eval: widget is not defined")
+	assert.NotContains(t, got, "width=640")
+}
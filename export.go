@@ -0,0 +1,128 @@
+package via
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Export writes each target page to dir as a static HTML file, plus the
+// same-origin assets those pages reference — for the docs, marketing and
+// legal sections of an app that don't need to be live. A target is a path
+// with optional query, like RenderPage's; path parameters and query fields
+// are how a page gets its per-route data:
+//
+//	err := app.Export("public", "/", "/pricing", "/blog/launch?lang=en")
+//
+// Pages render the way [WithCrawlerRender] serves bots — OnInit and View,
+// no session, no registered tab, no SSE bootstrap — with RenderPage's
+// deterministic ids, so an unchanged app exports byte-identical files.
+// "/" becomes index.html and "/pricing" pricing/index.html, so any static
+// host serves the export under the original URLs; the query plays no part
+// in the file name, so two targets that differ only in their query are an
+// error rather than one silently overwriting the other. Assets are the src
+// and href targets under the app's own routes (plugin stylesheets,
+// HandleStatic files) and keep their paths.
+// Actions don't work in an exported page: there is no server behind it.
+//
+// Export stops at the first target that fails to render or write; files
+// already written stay in place.
+func (a *App) Export(dir string, targets ...string) error {
+	pages := make(map[string]string, len(targets)) // cleaned path → target
+	assets := map[string]bool{}
+	for _, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			return fmt.Errorf("via: Export %q: %w", target, err)
+		}
+		p := path.Clean("/" + u.Path)
+		if prev, ok := pages[p]; ok {
+			return fmt.Errorf("via: Export: %q and %q both export to %s", prev, target, pageFile(u.Path))
+		}
+		pages[p] = target
+		doc, err := a.renderTarget(target, exportSeed, true)
+		if err != nil {
+			return fmt.Errorf("via: Export: %w", err)
+		}
+		if err := writeExportFile(dir, pageFile(u.Path), []byte(doc)); err != nil {
+			return fmt.Errorf("via: Export %q: %w", target, err)
+		}
+		walkTags(doc, func(_ string, attrs map[string]string) {
+			for _, name := range []string{"src", "href"} {
+				if p, ok := localAsset(attrs[name]); ok {
+					// Under WithBasePath the export root is the base.
+					p, _ = a.trimBasePath(p)
+					assets[p] = true
+				}
+			}
+		})
+	}
+	for p := range assets {
+		if _, ok := pages[p]; ok {
+			continue
+		}
+		body, ok := a.fetchAsset(p)
+		if !ok {
+			continue // a link to a page that wasn't exported, or a 404
+		}
+		if err := writeExportFile(dir, filepath.FromSlash(strings.TrimPrefix(p, "/")), body); err != nil {
+			return fmt.Errorf("via: Export asset %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// exportSeed seeds Export's renders. The NUL keeps it out of reach of a
+// RenderPage caller's seed, whose renders would otherwise share the
+// export's session.
+const exportSeed = "\x00via:export"
+
+// pageFile maps a URL path to its file under the export root.
+func pageFile(urlPath string) string {
+	p := strings.Trim(path.Clean("/"+urlPath), "/")
+	return filepath.Join(filepath.FromSlash(p), "index.html")
+}
+
+// localAsset returns the cleaned path of ref when it names a resource on
+// this app: root-relative, not protocol-relative, not an in-app anchor.
+func localAsset(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "//") {
+		return "", false
+	}
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	p := path.Clean(ref)
+	return p, p != "/"
+}
+
+// fetchAsset GETs p through the app's handler chain. Only non-HTML 200s
+// count: an HTML answer is a page (or a catch-all), not an asset.
+func (a *App) fetchAsset(p string) ([]byte, bool) {
+	// Marked as a static render so the session layer mints nothing.
+	rc := context.WithValue(context.Background(), pageRenderKey{}, &pageRender{static: true})
+	r, err := http.NewRequestWithContext(rc, http.MethodGet, p, nil)
+	if err != nil {
+		return nil, false
+	}
+	r.RemoteAddr = "127.0.0.1:0"
+	rec := &pageRecorder{header: http.Header{}}
+	a.ServeHTTP(rec, r)
+	if rec.status != http.StatusOK || strings.HasPrefix(rec.header.Get("Content-Type"), "text/html") {
+		return nil, false
+	}
+	return rec.body.Bytes(), true
+}
+
+func writeExportFile(root, name string, body []byte) error {
+	full := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(full, body, 0o644)
+}
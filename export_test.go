@@ -0,0 +1,94 @@
+package via_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type brochurePage struct {
+	Lang string `query:"lang"`
+}
+
+func (p *brochurePage) View(ctx *via.CtxR) h.H {
+	return h.Main(
+		h.Link(h.Rel("stylesheet"), h.Href("/assets/site.css?v=2")),
+		h.H1(h.Text("Hello "+p.Lang)),
+		h.A(h.Href("/posts/launch"), h.Text("launch")),
+		h.A(h.Href("https://example.com/x.css"), h.Text("elsewhere")),
+	)
+}
+
+func TestExport_writesStaticPagesAndTheirAssets(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[brochurePage](app, "/home")
+	via.Mount[articlePage](app, "/posts/{slug}")
+	app.HandleStatic("/assets/", fstest.MapFS{"site.css": {Data: []byte("body{}")}})
+
+	dir := t.TempDir()
+	require.NoError(t, app.Export(dir, "/home?lang=en", "/posts/launch"))
+
+	home, err := os.ReadFile(filepath.Join(dir, "home", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(home), "<h1>Hello en</h1>")
+	assert.NotContains(t, string(home), "/_sse", "an exported page carries no SSE bootstrap")
+
+	post, err := os.ReadFile(filepath.Join(dir, "posts", "launch", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(post), "<h1>Post launch</h1>")
+
+	css, err := os.ReadFile(filepath.Join(dir, "assets", "site.css"))
+	require.NoError(t, err)
+	assert.Equal(t, "body{}", string(css))
+
+	assert.Empty(t, app.Contexts(), "exporting registers no tabs")
+}
+
+func TestExport_isDeterministic(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[brochurePage](app, "/")
+
+	a, b := t.TempDir(), t.TempDir()
+	require.NoError(t, app.Export(a, "/"))
+	require.NoError(t, app.Export(b, "/"))
+	first, _ := os.ReadFile(filepath.Join(a, "index.html"))
+	second, _ := os.ReadFile(filepath.Join(b, "index.html"))
+	assert.Equal(t, string(first), string(second))
+}
+
+func TestExport_failsOnAPageThatDoesNotRender(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[brochurePage](app, "/home")
+
+	err := app.Export(t.TempDir(), "/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestExport_refusesTargetsThatShareAFile(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[brochurePage](app, "/home")
+
+	dir := t.TempDir()
+	err := app.Export(dir, "/home?lang=en", "/home/?lang=fr")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"/home?lang=en" and "/home/?lang=fr" both export to`)
+
+	home, err := os.ReadFile(filepath.Join(dir, "home", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(home), "<h1>Hello en</h1>", "the first target's file is left alone")
+}
@@ -0,0 +1,84 @@
+package via
+
+// flashKey is the session key pending flash messages live under.
+const flashKey = "via.flash"
+
+// maxFlashes bounds the messages a session holds between page loads;
+// past it the oldest are dropped.
+const maxFlashes = 16
+
+// FlashLevel is a flash message's severity. Its string form doubles as a
+// CSS class or an alert variant in the view.
+type FlashLevel string
+
+const (
+	FlashInfo    FlashLevel = "info"
+	FlashSuccess FlashLevel = "success"
+	FlashWarning FlashLevel = "warning"
+	FlashError   FlashLevel = "error"
+)
+
+// FlashMessage is one notice queued with [Flash].
+type FlashMessage struct {
+	Level FlashLevel
+	Text  string
+}
+
+// Flash queues a one-time notice on ctx's session for the next page the
+// browser loads — "Welcome back" after a sign-in, "Saved" after a form
+// redirects. The next page render takes every pending message off the
+// session and exposes it through [CtxR.Flashes] for that tab's lifetime;
+// a later load sees none. Messages survive [Session.Rotate], so a flash
+// queued just before a login rotation still shows.
+//
+//	func (p *Login) Submit(ctx *via.Ctx) error {
+//	    ...
+//	    via.Flash(ctx, via.FlashSuccess, "Signed in")
+//	    ctx.Redirect("/")
+//	    return nil
+//	}
+func Flash(ctx *Ctx, level FlashLevel, msg string) {
+	if ctx == nil || msg == "" {
+		return
+	}
+	s := ctx.session.Load()
+	if s == nil {
+		return
+	}
+	// Straight to the store: a pending flash is for the next page, so it
+	// must not re-render the tabs already open on the session.
+	_, _ = s.data.Update(flashKey, func(old any) (any, error) {
+		msgs, _ := old.([]FlashMessage)
+		msgs = append(msgs, FlashMessage{Level: level, Text: msg})
+		if len(msgs) > maxFlashes {
+			msgs = msgs[len(msgs)-maxFlashes:]
+		}
+		return msgs, nil
+	})
+}
+
+// Flashes returns the messages queued with [Flash] that this tab's page
+// load consumed, oldest first, or nil.
+func (r *CtxR) Flashes() []FlashMessage {
+	if c := r.rctx(); c != nil {
+		return c.flashes
+	}
+	return nil
+}
+
+// takeFlashes moves the session's pending flashes onto a tab being
+// rendered as a page. Runs before the tab is registered, so the field is
+// never written once other goroutines can see the ctx.
+func (ctx *Ctx) takeFlashes() {
+	s := ctx.session.Load()
+	if s == nil {
+		return
+	}
+	// Under the key's lock, so a Flash racing the take lands either in
+	// this page or in the next one — never in neither.
+	m := s.data.lockFor(flashKey)
+	m.Lock()
+	v, _ := s.data.values.LoadAndDelete(flashKey)
+	m.Unlock()
+	ctx.flashes, _ = v.([]FlashMessage)
+}
@@ -0,0 +1,76 @@
+package via_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flashPage struct{}
+
+func (p *flashPage) Save(ctx *via.Ctx) {
+	via.Flash(ctx, via.FlashSuccess, "Saved")
+	via.Flash(ctx, via.FlashWarning, "Quota almost full")
+}
+
+func (p *flashPage) SignIn(ctx *via.Ctx) {
+	via.Flash(ctx, via.FlashInfo, "Welcome back")
+	ctx.Session().Rotate()
+}
+
+func (p *flashPage) View(ctx *via.CtxR) h.H {
+	var items []h.H
+	for _, f := range ctx.Flashes() {
+		items = append(items, h.Li(h.Class("flash-"+string(f.Level)), h.Text(f.Text)))
+	}
+	return h.Ul(h.ID("flashes"), h.Fragment(items...))
+}
+
+func TestFlash_showsOnTheNextPageLoadOnly(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[flashPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, http.StatusOK, tc.Action("Save").Fire())
+	assert.NotContains(t, tc.HTML(), "Saved")
+
+	body := tc.Reload()
+	assert.Contains(t, body, `<li class="flash-success">Saved</li>`)
+	assert.Contains(t, body, `<li class="flash-warning">Quota almost full</li>`)
+	assert.Less(t, strings.Index(body, "Saved"), strings.Index(body, "Quota"), "oldest first")
+
+	assert.NotContains(t, tc.Reload(), "Saved", "a flash is consumed by the page that showed it")
+}
+
+func TestFlash_isPerSession(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[flashPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	other := vt.NewClient(t, server, "/")
+	require.Equal(t, http.StatusOK, tc.Action("Save").Fire())
+
+	assert.NotContains(t, other.Reload(), "Saved")
+	assert.Contains(t, tc.Reload(), "Saved")
+}
+
+func TestFlash_survivesSessionRotation(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[flashPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, http.StatusOK, tc.Action("SignIn").Fire())
+	assert.Contains(t, tc.Reload(), "Welcome back")
+}
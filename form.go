@@ -3,6 +3,7 @@ package via
 import (
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // DecodeForm parses the request body's signal payload into a typed struct.
@@ -72,7 +73,8 @@ func DecodeForm[T any](ctx *Ctx, dst *T) {
 		if !f.IsExported() {
 			continue
 		}
-		key := f.Tag.Get("form")
+		// Options after the key belong to via.Form.
+		key, _, _ := strings.Cut(f.Tag.Get("form"), ",")
 		if key == "" {
 			key = lowerFirst(f.Name)
 		}
@@ -94,7 +96,9 @@ func formatScalar(v any) string {
 		}
 		return "false"
 	case float64:
-		return strconv.FormatFloat(x, 'g', -1, 64)
+		// 'f', not 'g': a JSON number such as 1000000 must read back as
+		// an integer, not "1e+06".
+		return strconv.FormatFloat(x, 'f', -1, 64)
 	case int:
 		return strconv.Itoa(x)
 	case int64:
@@ -0,0 +1,198 @@
+package via
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"time"
+
+	"github.com/go-via/via/h"
+)
+
+// Signal keys the guard's fields ride on. Plain (not "_"-prefixed) so
+// Datastar sends them with every @post.
+const (
+	formHoneypotKey  = "via_fg_hp"
+	formChallengeKey = "via_fg_c"
+	formSolutionKey  = "via_fg_n"
+)
+
+// FormGuard screens submissions of a public form — contact, signup,
+// comments — for spam bots, without an external service. Render its
+// Fields inside the form and call Check first thing in the submit action:
+//
+//	var contactGuard = &via.FormGuard{MinFillTime: 3 * time.Second, ProofOfWork: 16}
+//
+//	func (p *Contact) View(ctx *via.CtxR) h.H {
+//	    return h.Form(contactGuard.Fields(ctx), p.Email.Bind(), ...)
+//	}
+//	func (p *Contact) Send(ctx *via.Ctx) error {
+//	    if contactGuard.Check(ctx) != nil {
+//	        return nil // don't tell the bot
+//	    }
+//	    ...
+//	}
+//
+// A honeypot input, hidden from people and assistive tech, is always
+// checked. The other checks are opt-in. A rejection is counted as
+// via.form.rejected with the failed check as its reason label.
+//
+// One guard per page; a single guard value may be shared by every page
+// that uses it.
+type FormGuard struct {
+	// MinFillTime rejects a submission made sooner than this after the
+	// form was rendered. People take seconds to fill a form; scripts
+	// post immediately. Zero disables the check.
+	MinFillTime time.Duration
+
+	// ProofOfWork makes the browser find a nonce whose SHA-256 with a
+	// per-tab challenge has this many leading zero bits before the form
+	// can pass (16 costs a browser well under a second; each extra bit
+	// doubles it). A fresh challenge is issued after every accepted
+	// submission. The solver needs crypto.subtle, so pages must be
+	// served over https or from localhost. Zero disables the check;
+	// more than 32 panics.
+	ProofOfWork int
+
+	// Verify runs last, after the built-in checks pass — the place to
+	// call a CAPTCHA service such as Turnstile with a token the form
+	// posted as a signal. A non-nil error rejects the submission.
+	Verify func(ctx *Ctx) error
+}
+
+// formIssue is what a tab's render of a guard's fields issued.
+type formIssue struct {
+	at        time.Time
+	challenge string
+}
+
+// Fields renders the guard's hidden inputs. Place it inside the form.
+func (g *FormGuard) Fields(ctx *CtxR) h.H {
+	if g.ProofOfWork < 0 || g.ProofOfWork > 32 {
+		panic(fmt.Sprintf("via.FormGuard: ProofOfWork must be 0..32 bits, got %d", g.ProofOfWork))
+	}
+	c := ctx.rctx()
+	if c == nil {
+		return nil
+	}
+	iss := c.issuedForm(g)
+
+	seed, _ := json.Marshal(map[string]string{formHoneypotKey: "", formSolutionKey: ""})
+	// Off-screen rather than display:none, which bots know to skip.
+	// data-style applies it through the CSSOM, so a strict CSP without
+	// 'unsafe-inline' styles still hides it.
+	nodes := []h.H{
+		h.Data("signals__ifmissing", string(seed)),
+		h.Data("style", "{position:'absolute',left:'-10000px',width:'1px',height:'1px',overflow:'hidden'}"),
+		h.Aria("hidden", "true"),
+		h.Label(h.Text("Leave this field empty"),
+			h.Input(h.Type("text"), h.Name("website"), h.TabIndexNum(-1), h.AutoComplete("off"),
+				h.Data("bind", formHoneypotKey))),
+	}
+	if g.ProofOfWork > 0 {
+		challenge, _ := json.Marshal(map[string]string{formChallengeKey: iss.challenge})
+		nodes = append(nodes,
+			h.Data("signals", string(challenge)),
+			h.Data("effect", powSolver(g.ProofOfWork)))
+	}
+	return h.Div(nodes...)
+}
+
+// powSolver is the data-effect that searches for the proof-of-work nonce.
+// It re-runs whenever the challenge signal changes.
+func powSolver(zeroBits int) string {
+	return `(async(c,b)=>{if(!c)return;$` + formSolutionKey + `='';let e=new TextEncoder();` +
+		`for(let n=0;;n++){let d=new Uint8Array(await crypto.subtle.digest('SHA-256',e.encode(c+':'+n))),z=0,i=0;` +
+		`for(;i<d.length&&d[i]===0;i++)z+=8;if(i<d.length)z+=Math.clz32(d[i])-24;` +
+		`if(z>=b){$` + formSolutionKey + `=String(n);return}}})($` + formChallengeKey + `,` + strconv.Itoa(zeroBits) + `)`
+}
+
+// Check reports whether the in-flight action's submission passes the
+// guard, returning an error naming the failed check when it doesn't.
+// Call it from the action the guarded form posts to.
+func (g *FormGuard) Check(ctx *Ctx) error {
+	if ctx == nil {
+		return errors.New("via: form submission rejected: no context")
+	}
+	sigs := ctx.lastSignals
+	if hp := formatScalar(sigs[formHoneypotKey]); hp != "" {
+		return g.reject(ctx, "honeypot")
+	}
+	ctx.readsMu.Lock()
+	iss, ok := ctx.formGuards[g]
+	ctx.readsMu.Unlock()
+	if !ok {
+		// The fields were never rendered on this tab.
+		return g.reject(ctx, "unissued")
+	}
+	if g.MinFillTime > 0 && ctx.app.now().Sub(iss.at) < g.MinFillTime {
+		return g.reject(ctx, "too_fast")
+	}
+	if g.ProofOfWork > 0 && !powValid(iss.challenge, formatScalar(sigs[formSolutionKey]), g.ProofOfWork) {
+		return g.reject(ctx, "pow")
+	}
+	if g.Verify != nil {
+		if err := g.Verify(ctx); err != nil {
+			ctx.app.metricsOrNoop().Counter("via.form.rejected", "reason", "verify")
+			return fmt.Errorf("via: form submission rejected: %v", err)
+		}
+	}
+	// Accepted: a proof or a fill time only pays for one submission.
+	fresh := ctx.reissueForm(g)
+	if g.ProofOfWork > 0 {
+		ctx.patch.Signal(formChallengeKey, fresh.challenge)
+	}
+	return nil
+}
+
+func (g *FormGuard) reject(ctx *Ctx, reason string) error {
+	ctx.app.metricsOrNoop().Counter("via.form.rejected", "reason", reason)
+	return fmt.Errorf("via: form submission rejected: %s", reason)
+}
+
+// issuedForm returns what this tab was issued for g, issuing it on the
+// first render so later re-renders keep the original fill-time start.
+func (ctx *Ctx) issuedForm(g *FormGuard) formIssue {
+	ctx.readsMu.Lock()
+	iss, ok := ctx.formGuards[g]
+	ctx.readsMu.Unlock()
+	if ok {
+		return iss
+	}
+	return ctx.reissueForm(g)
+}
+
+func (ctx *Ctx) reissueForm(g *FormGuard) formIssue {
+	iss := formIssue{at: ctx.app.now(), challenge: genSecureID()}
+	ctx.readsMu.Lock()
+	if ctx.formGuards == nil {
+		ctx.formGuards = make(map[*FormGuard]formIssue)
+	}
+	ctx.formGuards[g] = iss
+	ctx.readsMu.Unlock()
+	return iss
+}
+
+// powValid reports whether SHA-256(challenge ":" nonce) starts with at
+// least zeroBits zero bits.
+func powValid(challenge, nonce string, zeroBits int) bool {
+	if nonce == "" {
+		return false
+	}
+	if _, err := strconv.ParseUint(nonce, 10, 64); err != nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			n += bits.LeadingZeros8(b)
+			break
+		}
+		n += 8
+	}
+	return n >= zeroBits
+}
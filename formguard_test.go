@@ -0,0 +1,136 @@
+package via_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"html"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var contactGuard = &via.FormGuard{MinFillTime: 2 * time.Second, ProofOfWork: 8}
+
+type contactPage struct{}
+
+func (p *contactPage) Send(ctx *via.Ctx) error { return contactGuard.Check(ctx) }
+
+func (p *contactPage) View(ctx *via.CtxR) h.H {
+	return h.Form(contactGuard.Fields(ctx), h.Button(h.Text("Send"), on.Click(p.Send)))
+}
+
+var signupGuard = &via.FormGuard{Verify: func(ctx *via.Ctx) error { return errors.New("captcha failed") }}
+
+type signupPage struct{}
+
+func (p *signupPage) Join(ctx *via.Ctx) error { return signupGuard.Check(ctx) }
+
+func (p *signupPage) View(ctx *via.CtxR) h.H {
+	return h.Form(signupGuard.Fields(ctx), h.Button(h.Text("Join"), on.Click(p.Join)))
+}
+
+// guardedApp serves page at "/" and returns the errors its actions return.
+func guardedApp[C any](t *testing.T, opts ...via.Option) (*vt.Client, *vt.Clock, *captureMetrics, *[]error) {
+	t.Helper()
+	m := &captureMetrics{}
+	clk := vt.NewClock(time.Unix(1_700_000_000, 0))
+	var errs []error
+	app := via.New(append(opts, via.WithClock(clk), via.WithMetrics(m),
+		via.WithActionErrorHandler(func(_ *via.Ctx, err error) { errs = append(errs, err) }))...)
+	server := vt.Serve(t, app)
+	via.Mount[C](app, "/")
+	return vt.NewClient(t, server, "/"), clk, m, &errs
+}
+
+var challengeRE = regexp.MustCompile(`"via_fg_c":"([0-9a-f]+)"`)
+
+// solve finds the proof-of-work nonce for the challenge on c's page.
+func solve(t *testing.T, c *vt.Client, zeroBits int) string {
+	t.Helper()
+	m := challengeRE.FindStringSubmatch(html.UnescapeString(c.HTML()))
+	require.NotNil(t, m, "no challenge on the page")
+	for n := 0; ; n++ {
+		nonce := strconv.Itoa(n)
+		sum := sha256.Sum256([]byte(m[1] + ":" + nonce))
+		z := 0
+		for _, b := range sum {
+			z += bits.LeadingZeros8(b)
+			if b != 0 {
+				break
+			}
+		}
+		if z >= zeroBits {
+			return nonce
+		}
+	}
+}
+
+func TestFormGuard_acceptsAPatientSolvedSubmissionOnce(t *testing.T) {
+	t.Parallel()
+	tc, clk, m, errs := guardedApp[contactPage](t)
+	assert.Contains(t, tc.HTML(), `name="website"`)
+
+	nonce := solve(t, tc, 8)
+	clk.Advance(3 * time.Second)
+	tc.Action("Send").WithSignal("via_fg_hp", "").WithSignal("via_fg_n", nonce).Fire()
+	require.Empty(t, *errs)
+
+	// The proof and the fill time were spent on the accepted submission.
+	tc.Action("Send").WithSignal("via_fg_hp", "").WithSignal("via_fg_n", nonce).Fire()
+	require.Len(t, *errs, 1)
+	assert.ErrorContains(t, (*errs)[0], "via: form submission rejected")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.form.rejected:reason,too_fast")
+}
+
+func TestFormGuard_rejectsBotSubmissions(t *testing.T) {
+	t.Parallel()
+	tc, clk, m, errs := guardedApp[contactPage](t)
+	nonce := solve(t, tc, 8)
+
+	tc.Action("Send").WithSignal("via_fg_hp", "http://spam.example").WithSignal("via_fg_n", nonce).Fire()
+	tc.Action("Send").WithSignal("via_fg_hp", "").WithSignal("via_fg_n", nonce).Fire()
+	clk.Advance(3 * time.Second)
+	tc.Action("Send").WithSignal("via_fg_hp", "").WithSignal("via_fg_n", "").Fire()
+
+	require.Len(t, *errs, 3)
+	for _, err := range *errs {
+		assert.ErrorContains(t, err, "via: form submission rejected")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.form.rejected:reason,honeypot")
+	assert.Contains(t, m.counters, "via.form.rejected:reason,too_fast")
+	assert.Contains(t, m.counters, "via.form.rejected:reason,pow")
+}
+
+func TestFormGuard_verifyHookRunsLast(t *testing.T) {
+	t.Parallel()
+	tc, _, m, errs := guardedApp[signupPage](t)
+	assert.NotContains(t, tc.HTML(), "via_fg_c", "no proof-of-work challenge when disabled")
+
+	tc.Action("Join").WithSignal("via_fg_hp", "").Fire()
+	require.Len(t, *errs, 1)
+	assert.ErrorContains(t, (*errs)[0], "via: form submission rejected")
+	assert.ErrorContains(t, (*errs)[0], "captcha failed")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.form.rejected:reason,verify")
+}
+
+func TestFormGuard_panicsOnAnImpossibleProofOfWork(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, "via.FormGuard: ProofOfWork must be 0..32 bits, got 33", func() {
+		(&via.FormGuard{ProofOfWork: 33}).Fields(nil)
+	})
+}
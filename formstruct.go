@@ -0,0 +1,427 @@
+package via
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-via/via/h"
+)
+
+// Form is a form generated from the struct T: one input per exported
+// field, bound to a signal, with the field's label, input type and
+// constraints read from its `form` tag. Add it as a composition field,
+// render it with View, and call Submit from the action it posts to:
+//
+//	type Signup struct {
+//	    Name  string `form:"name,label=Your name,required,min=2,max=40"`
+//	    Email string `form:"email,type=email,required"`
+//	    Age   int    `form:"age,min=18"`
+//	    Terms bool   `form:"terms,label=I accept the terms,required"`
+//	}
+//
+//	type Page struct {
+//	    Form via.Form[Signup]
+//	}
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H { return p.Form.View(ctx, on.Submit(p.Save)) }
+//
+//	func (p *Page) Save(ctx *via.Ctx) error {
+//	    s, ok := p.Form.Submit(ctx)
+//	    if !ok {
+//	        return nil // the form re-renders with its errors
+//	    }
+//	    ...
+//	}
+//
+// The tag's first segment is the field's key (the lower-cased field name
+// when empty), as for DecodeForm. Options:
+//
+//   - label=…: the label text; the field name otherwise. It can't contain
+//     a comma.
+//   - type=…: the input type (email, password, tel, url, date, textarea,
+//     …); text for strings, number for numbers and checkbox for bools by
+//     default.
+//   - required: a string must be non-blank, a bool must be checked, a
+//     number must be filled in.
+//   - min=N, max=N: the length bounds of a string, the value bounds of a
+//     number.
+//
+// Fields may be strings, bools, ints, uints or floats; Mount panics on
+// any other exported field, and on an unknown option. The signals ride
+// under the field's wire key, like a Signal's (`Form.name`), so two forms
+// on one page never collide. Validation runs on the server in Submit;
+// the same constraints are rendered as HTML attributes so browsers catch
+// most mistakes before posting.
+type Form[T any] struct {
+	mu    sync.Mutex
+	key   string
+	value T
+	errs  map[string]string
+}
+
+// formBinder is implemented by *Form[T] so the walker can spot it and
+// bindSlots can hand it its wire key.
+type formBinder interface {
+	bindFormKey(key string)
+	formSpec() *formSpec
+}
+
+func (f *Form[T]) bindFormKey(key string) { f.key = key }
+
+func (f *Form[T]) formSpec() *formSpec { return formSpecFor(reflect.TypeFor[T]()) }
+
+// bindFormKeys writes the wire key into every via.Form field of the
+// freshly allocated *C. Mirrors bindFileKeys.
+func bindFormKeys(cmpVal reflect.Value, d *cmpDescriptor) {
+	elem := cmpVal.Elem()
+	for _, s := range d.formSlots {
+		fieldByPath(elem, s.fieldPath).Addr().Interface().(formBinder).bindFormKey(s.wireKey)
+	}
+}
+
+// formSpec is the parsed `form` tags of one struct type.
+type formSpec struct {
+	fields []formField
+}
+
+type formField struct {
+	index     int
+	key       string
+	label     string
+	inputType string
+	kind      reflect.Kind
+	required  bool
+	min, max  float64
+	hasMin    bool
+	hasMax    bool
+}
+
+var formSpecs sync.Map // reflect.Type → *formSpec
+
+// formSpecFor parses t's tags once, panicking on a field Form can't
+// render.
+func formSpecFor(t reflect.Type) *formSpec {
+	if s, ok := formSpecs.Load(t); ok {
+		return s.(*formSpec)
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("via.Form[%s]: type parameter must be a struct", t))
+	}
+	spec := &formSpec{}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := formField{index: i, kind: sf.Type.Kind(), label: sf.Name}
+		switch f.kind {
+		case reflect.String:
+			f.inputType = "text"
+		case reflect.Bool:
+			f.inputType = "checkbox"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			f.inputType = "number"
+		default:
+			panic(fmt.Sprintf("via.Form[%s]: field %s has unsupported type %s "+
+				"(use string, bool or a number)", t, sf.Name, sf.Type))
+		}
+		key, opts, _ := strings.Cut(sf.Tag.Get("form"), ",")
+		if key == "" {
+			key = lowerFirst(sf.Name)
+		}
+		f.key = key
+		for opt := range strings.SplitSeq(opts, ",") {
+			name, val, _ := strings.Cut(opt, "=")
+			var err error
+			switch name {
+			case "":
+			case "label":
+				f.label = val
+			case "type":
+				f.inputType = val
+			case "required":
+				f.required = true
+			case "min":
+				f.min, err = strconv.ParseFloat(val, 64)
+				f.hasMin = true
+			case "max":
+				f.max, err = strconv.ParseFloat(val, 64)
+				f.hasMax = true
+			default:
+				panic(fmt.Sprintf("via.Form[%s]: field %s has unknown form-tag option %q "+
+					"(label=, type=, required, min=, max=)", t, sf.Name, opt))
+			}
+			if err != nil {
+				panic(fmt.Sprintf("via.Form[%s]: field %s: %s=%q is not a number", t, sf.Name, name, val))
+			}
+		}
+		spec.fields = append(spec.fields, f)
+	}
+	s, _ := formSpecs.LoadOrStore(t, spec)
+	return s.(*formSpec)
+}
+
+// Set fills the form with v and clears its errors. Call it from OnInit
+// for initial values, or from an action to reset the form after a
+// successful submit.
+func (f *Form[T]) Set(ctx *Ctx, v T) {
+	f.mu.Lock()
+	f.value = v
+	f.errs = nil
+	f.mu.Unlock()
+	if ctx == nil {
+		return
+	}
+	ctx.patch.Signals(nestSignals(f.key, f.signalValues(v)))
+	ctx.markStateDirty()
+}
+
+// Value returns the form's current value: what was last Set, or the last
+// submission, valid or not.
+func (f *Form[T]) Value(_ readCtx) T {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value
+}
+
+// Submit decodes the in-flight action's posted fields into a T,
+// validates it against the tags, and reports whether it passed. The
+// result becomes the form's Value either way; the errors, if any, show
+// next to their fields on the next render, which Submit schedules.
+func (f *Form[T]) Submit(ctx *Ctx) (T, bool) {
+	spec := f.formSpec()
+	f.mu.Lock()
+	v := f.value
+	f.mu.Unlock()
+	rv := reflect.ValueOf(&v).Elem()
+	errs := map[string]string{}
+	for _, fd := range spec.fields {
+		raw := ""
+		if ctx != nil {
+			// Flat or, as the browser sends it, nested under the form's key.
+			key := f.key + "." + fd.key
+			x, ok := ctx.lastSignals[key]
+			if !ok {
+				x, ok = nestedSignal(ctx.lastSignals, key)
+			}
+			if ok {
+				raw = formatScalar(x)
+			}
+		}
+		field := rv.Field(fd.index)
+		field.SetZero()
+		decodeScalarString(field, fd.kind, strings.TrimSpace(raw))
+		if msg := fd.check(raw, field); msg != "" {
+			errs[fd.key] = msg
+		}
+	}
+	f.mu.Lock()
+	f.value = v
+	f.errs = errs
+	f.mu.Unlock()
+	if ctx != nil {
+		ctx.markStateDirty()
+	}
+	return v, len(errs) == 0
+}
+
+// check validates one decoded field, returning the message to show.
+func (fd formField) check(raw string, v reflect.Value) string {
+	raw = strings.TrimSpace(raw)
+	switch fd.kind {
+	case reflect.String:
+		n := float64(len([]rune(v.String())))
+		switch {
+		case raw == "" && fd.required:
+			return fd.label + " is required"
+		case raw == "":
+			return ""
+		case fd.hasMin && n < fd.min:
+			return fmt.Sprintf("%s must be at least %s characters", fd.label, formatNum(fd.min))
+		case fd.hasMax && n > fd.max:
+			return fmt.Sprintf("%s must be at most %s characters", fd.label, formatNum(fd.max))
+		case fd.inputType == "email":
+			if a, err := mail.ParseAddress(raw); err != nil || a.Address != raw {
+				return fd.label + " must be an email address"
+			}
+		}
+	case reflect.Bool:
+		if fd.required && !v.Bool() {
+			return fd.label + " must be checked"
+		}
+	default:
+		if raw == "" {
+			if fd.required {
+				return fd.label + " is required"
+			}
+			return ""
+		}
+		var n float64
+		var err error
+		switch {
+		case v.CanInt():
+			var i int64
+			i, err = strconv.ParseInt(raw, 10, 64)
+			n = float64(i)
+		case v.CanUint():
+			var u uint64
+			u, err = strconv.ParseUint(raw, 10, 64)
+			n = float64(u)
+		default:
+			n, err = strconv.ParseFloat(raw, 64)
+		}
+		switch {
+		case err != nil:
+			return fd.label + " must be a number"
+		case fd.hasMin && n < fd.min:
+			return fmt.Sprintf("%s must be at least %s", fd.label, formatNum(fd.min))
+		case fd.hasMax && n > fd.max:
+			return fmt.Sprintf("%s must be at most %s", fd.label, formatNum(fd.max))
+		}
+	}
+	return ""
+}
+
+func formatNum(n float64) string { return strconv.FormatFloat(n, 'f', -1, 64) }
+
+// SetError marks field (its key, as in the tag) invalid with msg — for a
+// check the tags can't express, such as "that email is taken". An empty
+// field is an error for the form as a whole, shown above the buttons.
+// Call it after Submit, which replaces every error.
+func (f *Form[T]) SetError(ctx *Ctx, field, msg string) {
+	f.mu.Lock()
+	if f.errs == nil {
+		f.errs = map[string]string{}
+	}
+	f.errs[field] = msg
+	f.mu.Unlock()
+	if ctx != nil {
+		ctx.markStateDirty()
+	}
+}
+
+// Error returns field's current error message, or "".
+func (f *Form[T]) Error(field string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.errs[field]
+}
+
+// View renders the whole form: every field in struct order, any
+// form-level error, then buttons — a single Submit button unless
+// buttons are given. onSubmit is the parent's binding, e.g.
+// on.Submit(p.Save).
+func (f *Form[T]) View(ctx *CtxR, onSubmit h.H, buttons ...h.H) h.H {
+	spec := f.formSpec()
+	nodes := make([]h.H, 0, len(spec.fields)+4)
+	nodes = append(nodes, onSubmit, f.seed())
+	for _, fd := range spec.fields {
+		nodes = append(nodes, f.field(fd))
+	}
+	if msg := f.Error(""); msg != "" {
+		nodes = append(nodes, h.P(h.Class("via-form-error"), h.Role("alert"), h.Text(msg)))
+	}
+	if len(buttons) == 0 {
+		buttons = []h.H{h.Button(h.Type("submit"), h.Text("Submit"))}
+	}
+	nodes = append(nodes, buttons...)
+	return h.Form(nodes...)
+}
+
+// Field renders the one field keyed key, for a custom layout: place each
+// Field inside your own h.Form, along with [Form.Seed]. It panics on a
+// key T doesn't have.
+func (f *Form[T]) Field(_ *CtxR, key string) h.H {
+	for _, fd := range f.formSpec().fields {
+		if fd.key == key {
+			return f.field(fd)
+		}
+	}
+	panic(fmt.Sprintf("via.Form[%s].Field: no field %q", reflect.TypeFor[T](), key))
+}
+
+// Seed renders the attribute that gives the form's signals their initial
+// values. View includes it; a custom layout built from Field puts it on
+// its own form element.
+func (f *Form[T]) Seed(_ *CtxR) h.H { return f.seed() }
+
+func (f *Form[T]) seed() h.H {
+	f.mu.Lock()
+	v := f.value
+	f.mu.Unlock()
+	b, _ := json.Marshal(nestSignals(f.key, f.signalValues(v)))
+	// ifmissing: a re-render must not overwrite what the user has typed
+	// since. Set pushes explicit values when the server means to.
+	return h.Data("signals__ifmissing", string(b))
+}
+
+// signalValues maps each field key to v's value for it.
+func (f *Form[T]) signalValues(v T) map[string]any {
+	rv := reflect.ValueOf(v)
+	out := map[string]any{}
+	for _, fd := range f.formSpec().fields {
+		out[fd.key] = rv.Field(fd.index).Interface()
+	}
+	return out
+}
+
+func (f *Form[T]) field(fd formField) h.H {
+	id := strings.ReplaceAll(f.key, ".", "-") + "-" + fd.key
+	errID := id + "-error"
+	msg := f.Error(fd.key)
+	attrs := []h.H{h.ID(id), h.Name(fd.key), h.Data("bind", f.key+"."+fd.key)}
+	if fd.inputType != "textarea" {
+		attrs = append(attrs, h.Type(fd.inputType))
+	}
+	if fd.required {
+		attrs = append(attrs, h.Required())
+	}
+	if fd.kind == reflect.String {
+		if fd.hasMin {
+			attrs = append(attrs, h.MinLength(int(fd.min)))
+		}
+		if fd.hasMax {
+			attrs = append(attrs, h.MaxLength(int(fd.max)))
+		}
+	} else if fd.kind != reflect.Bool {
+		if fd.hasMin {
+			attrs = append(attrs, h.Min(formatNum(fd.min)))
+		}
+		if fd.hasMax {
+			attrs = append(attrs, h.Max(formatNum(fd.max)))
+		}
+	}
+	var errNode h.H
+	if msg != "" {
+		attrs = append(attrs, h.Aria("invalid", "true"), h.Aria("describedby", errID))
+		errNode = h.P(h.ID(errID), h.Class("via-field-error"), h.Text(msg))
+	}
+	var input h.H
+	if fd.inputType == "textarea" {
+		input = h.Textarea(attrs...)
+	} else {
+		input = h.Input(attrs...)
+	}
+	if fd.kind == reflect.Bool {
+		return h.Div(h.Class("via-field"), h.Label(input, h.Text(" "+fd.label)), errNode)
+	}
+	return h.Div(h.Class("via-field"), h.Label(h.For(id), h.Text(fd.label)), input, errNode)
+}
+
+// nestSignals wraps values under a dotted wire key, the shape datastar
+// merges signals in: "Card.form" → {"Card":{"form":values}}.
+func nestSignals(key string, values map[string]any) map[string]any {
+	out := any(values)
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		out = map[string]any{parts[i]: out}
+	}
+	return out.(map[string]any)
+}
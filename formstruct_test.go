@@ -0,0 +1,148 @@
+package via_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupInput struct {
+	Name  string `form:"name,label=Your name,required,min=2,max=40"`
+	Email string `form:"email,type=email,required"`
+	Age   int    `form:"age,min=18"`
+	Terms bool   `form:"terms,label=I accept the terms,required"`
+}
+
+type registerPage struct {
+	Signup via.Form[signupInput]
+	Saved  via.StateTabStr
+}
+
+func (p *registerPage) OnInit(ctx *via.Ctx) error {
+	p.Signup.Set(ctx, signupInput{Age: 30})
+	return nil
+}
+
+func (p *registerPage) Save(ctx *via.Ctx) error {
+	s, ok := p.Signup.Submit(ctx)
+	if !ok {
+		return nil
+	}
+	if s.Email == "taken@example.com" {
+		p.Signup.SetError(ctx, "email", "That email is already registered")
+		return nil
+	}
+	p.Saved.Write(ctx, fmt.Sprintf("%+v", s))
+	return nil
+}
+
+func (p *registerPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Signup.View(ctx, on.Submit(p.Save)), h.P(h.Text("saved="+p.Saved.Read(ctx))))
+}
+
+func newRegisterClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[registerPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+func TestForm_rendersAFieldPerStructField(t *testing.T) {
+	t.Parallel()
+	tc, _ := newRegisterClient(t)
+
+	html := tc.HTML()
+	assert.Contains(t, html, `<label for="signup-name">Your name</label>`)
+	assert.Contains(t, html, `id="signup-name" name="name" data-bind="signup.name" type="text" required minlength="2" maxlength="40"`)
+	assert.Contains(t, html, `data-bind="signup.email" type="email" required`)
+	assert.Contains(t, html, `data-bind="signup.age" type="number" min="18"`)
+	assert.Contains(t, html, `data-bind="signup.terms" type="checkbox" required`)
+	assert.Contains(t, html, "I accept the terms")
+	assert.Contains(t, html, `data-signals__ifmissing="{&#34;signup&#34;:{&#34;age&#34;:30,`,
+		"the value Set in OnInit seeds the signals")
+	assert.Contains(t, html, `<button type="submit">Submit</button>`)
+}
+
+func TestForm_submitShowsFieldErrors(t *testing.T) {
+	t.Parallel()
+	tc, frames := newRegisterClient(t)
+
+	require.Equal(t, 200, tc.Action("Save").
+		WithSignal("signup.name", "A").
+		WithSignal("signup.email", "not-an-email").
+		WithSignal("signup.age", 12).
+		WithSignal("signup.terms", false).Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second,
+		"Your name must be at least 2 characters",
+		"Email must be an email address",
+		"Age must be at least 18",
+		"I accept the terms must be checked",
+		`aria-invalid="true" aria-describedby="signup-name-error"`)
+	assert.Contains(t, frame, "saved=<", "nothing was saved")
+}
+
+func TestForm_submitDecodesATypedValue(t *testing.T) {
+	t.Parallel()
+	tc, frames := newRegisterClient(t)
+
+	require.Equal(t, 200, tc.Action("Save").WithSignal("signup", map[string]any{
+		"name": " Ada ", "email": "ada@example.com", "age": 1000000, "terms": true,
+	}).Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "saved={Name:Ada Email:ada@example.com Age:1000000 Terms:true}")
+	assert.NotContains(t, frame, "via-field-error")
+}
+
+func TestForm_setErrorMarksAFieldAfterSubmit(t *testing.T) {
+	t.Parallel()
+	tc, frames := newRegisterClient(t)
+
+	require.Equal(t, 200, tc.Action("Save").WithSignal("signup", map[string]any{
+		"name": "Ada", "email": "taken@example.com", "age": 40, "terms": true,
+	}).Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="signup-email-error"`, "That email is already registered")
+}
+
+type badFormInput struct {
+	Tags []string
+}
+
+type badFormPage struct {
+	F via.Form[badFormInput]
+}
+
+func (p *badFormPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestForm_mountPanicsOnAnUnsupportedField(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	assert.PanicsWithValue(t,
+		"via.Form[via_test.badFormInput]: field Tags has unsupported type []string (use string, bool or a number)",
+		func() { via.Mount[badFormPage](app, "/") })
+}
+
+type badOptionInput struct {
+	Name string `form:"name,requird"`
+}
+
+type badOptionPage struct {
+	F via.Form[badOptionInput]
+}
+
+func (p *badOptionPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestForm_mountPanicsOnAnUnknownTagOption(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	assert.Panics(t, func() { via.Mount[badOptionPage](app, "/") })
+}
@@ -0,0 +1,280 @@
+package via
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// UserDataStore is an app-owned store holding personal data — a users
+// table, an upload bucket, a CRM mirror — that [App.ExportUserData] and
+// [App.EraseUserData] should cover alongside via's own session state.
+// Register each with [WithUserDataStore].
+type UserDataStore interface {
+	// ExportUserData returns everything the store holds about subject, in
+	// a JSON-encodable form; nil (or an empty value) when it holds nothing.
+	ExportUserData(ctx context.Context, subject string) (any, error)
+	// EraseUserData deletes everything the store holds about subject. It
+	// must be idempotent: erasing an absent subject is not an error.
+	EraseUserData(ctx context.Context, subject string) error
+}
+
+// WithUserDataStore registers s under name for the GDPR helpers. name keys
+// the store's section in a [UserData] export and is listed in the audit
+// record; registering a name twice panics.
+func WithUserDataStore(name string, s UserDataStore) Option {
+	return func(c *config) {
+		if name == "" || s == nil {
+			panic("via: WithUserDataStore needs a name and a store")
+		}
+		for _, ns := range c.userStores {
+			if ns.name == name {
+				panic(fmt.Sprintf("via: WithUserDataStore: %q registered twice", name))
+			}
+		}
+		c.userStores = append(c.userStores, namedUserStore{name: name, store: s})
+	}
+}
+
+type namedUserStore struct {
+	name  string
+	store UserDataStore
+}
+
+// UserData is a machine-readable export of one data subject, as returned
+// by [App.ExportUserData]. It encodes to JSON as-is.
+type UserData struct {
+	Subject    string            `json:"subject"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Sessions   []SessionData     `json:"sessions"`
+	Stores     map[string]any    `json:"stores,omitempty"` // by WithUserDataStore name
+	Errors     map[string]string `json:"errors,omitempty"` // stores that failed to export
+}
+
+// SessionData is one session's part of a [UserData] export: the sign-in
+// record, when there is one, and every session value (sess.Put values
+// and StateSess handles alike) by key.
+type SessionData struct {
+	ID         string         `json:"id"`
+	User       string         `json:"user,omitempty"`
+	Device     string         `json:"device,omitempty"`
+	Addr       string         `json:"addr,omitempty"`
+	SignedInAt time.Time      `json:"signed_in_at,omitzero"`
+	Values     map[string]any `json:"values"`
+}
+
+// UserDataAudit is the audit record of one export or erasure. Both helpers
+// return it (erasure) or log it (export), and append it to the
+// backplane's "via.audit.userdata" log so the trail outlives the pod.
+type UserDataAudit struct {
+	Action   string    `json:"action"` // "export" or "erase"
+	Subject  string    `json:"subject"`
+	At       time.Time `json:"at"`
+	Sessions int       `json:"sessions"` // sessions matched
+	Cells    int       `json:"cells"`    // backplane StateSess cells cleared (erase)
+	Stores   []string  `json:"stores"`   // WithUserDataStore names covered
+	// CryptoShredded reports that the subject's event-log key was dropped
+	// (EraseDataSubject); false when no KeyStore is configured.
+	CryptoShredded bool `json:"crypto_shredded"`
+	// Verified reports that a re-export after erasure found nothing left.
+	Verified bool `json:"verified"`
+}
+
+// auditUserDataKey is the EventLog key the audit records are appended to.
+const auditUserDataKey = "via.audit.userdata"
+
+// subjectSessions returns the sessions subject names: those signed in as
+// subject with Ctx.SignIn, or the one whose SessionInfo.ID is subject —
+// anonymous visitors have no user id, only the session handle.
+func (a *App) subjectSessions(subject string) []*session {
+	a.sessionsMu.RLock()
+	defer a.sessionsMu.RUnlock()
+	var out []*session
+	for id, s := range a.sessions {
+		in := s.auth.Load()
+		if (in != nil && in.user == subject) || sessionDigest(id) == subject {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ExportUserData collects everything the app holds about subject — a user
+// id passed to Ctx.SignIn, or a session's SessionInfo.ID — from the
+// sessions on this pod and every [WithUserDataStore] store, for a GDPR
+// access request:
+//
+//	data, err := app.ExportUserData(ctx, userID)
+//	json.NewEncoder(w).Encode(data)
+//
+// A store that fails to export is named in UserData.Errors rather than
+// failing the export; err is non-nil only when subject is empty. Event-log
+// payloads are not exported: project them into a store you register. The
+// export is audited like an erasure.
+func (a *App) ExportUserData(ctx context.Context, subject string) (UserData, error) {
+	if subject == "" {
+		return UserData{}, errors.New("via: ExportUserData: empty subject")
+	}
+	out := a.collectUserData(ctx, subject)
+	a.recordUserDataAudit(ctx, UserDataAudit{
+		Action:   "export",
+		Subject:  subject,
+		At:       out.ExportedAt,
+		Sessions: len(out.Sessions),
+		Stores:   a.userStoreNames(),
+	})
+	return out, nil
+}
+
+func (a *App) collectUserData(ctx context.Context, subject string) UserData {
+	out := UserData{Subject: subject, ExportedAt: a.now().UTC(), Sessions: []SessionData{}}
+	for _, s := range a.subjectSessions(subject) {
+		sd := SessionData{ID: sessionDigest(s.id), Values: map[string]any{}}
+		if in := s.auth.Load(); in != nil {
+			sd.User, sd.Device, sd.Addr, sd.SignedInAt = in.user, in.device, in.addr, in.at
+		}
+		s.data.Range(func(k, v any) bool {
+			sd.Values[k.(string)] = v
+			return true
+		})
+		out.Sessions = append(out.Sessions, sd)
+	}
+	sort.Slice(out.Sessions, func(i, j int) bool { return out.Sessions[i].ID < out.Sessions[j].ID })
+	for _, ns := range a.cfg.userStores {
+		v, err := ns.store.ExportUserData(ctx, subject)
+		if err != nil {
+			if out.Errors == nil {
+				out.Errors = map[string]string{}
+			}
+			out.Errors[ns.name] = err.Error()
+			continue
+		}
+		if isEmptyExport(v) {
+			continue
+		}
+		if out.Stores == nil {
+			out.Stores = map[string]any{}
+		}
+		out.Stores[ns.name] = v
+	}
+	return out
+}
+
+// EraseUserData deletes everything the app holds about subject, for a GDPR
+// erasure request. On this pod it ends every matching session (its tabs
+// reload signed out), overwrites the session's StateSess cells in the
+// backplane, erases subject from each [WithUserDataStore] store, and — with
+// a KeyStore — crypto-shreds subject's event-log payloads via
+// [App.EraseDataSubject]. It then re-exports to verify nothing is left.
+//
+// The returned audit record is also logged and appended to the backplane.
+// The first failing step aborts with its error; everything is idempotent,
+// so retry the call. A session for subject held only by another pod is
+// reached when that pod runs the erasure too — fan the call out, or keep
+// it on the pod the user talks to.
+func (a *App) EraseUserData(ctx context.Context, subject string) (UserDataAudit, error) {
+	if subject == "" {
+		return UserDataAudit{}, errors.New("via: EraseUserData: empty subject")
+	}
+	rec := UserDataAudit{Action: "erase", Subject: subject, At: a.now().UTC(), Stores: a.userStoreNames()}
+
+	a.sessDecodersMu.Lock()
+	keys := make([]string, 0, len(a.sessDecoders))
+	for k := range a.sessDecoders {
+		keys = append(keys, k)
+	}
+	a.sessDecodersMu.Unlock()
+
+	for _, s := range a.subjectSessions(subject) {
+		a.sessionsMu.Lock()
+		delete(a.sessions, s.id)
+		a.sessionsMu.Unlock()
+		a.endSession(s)
+		rec.Sessions++
+		for _, k := range keys {
+			cleared, err := a.clearCell(ctx, sessValKey(s.id, k))
+			if err != nil {
+				return rec, fmt.Errorf("via: EraseUserData: clear %q: %w", k, err)
+			}
+			if cleared {
+				rec.Cells++
+			}
+		}
+	}
+	for _, ns := range a.cfg.userStores {
+		if err := ns.store.EraseUserData(ctx, subject); err != nil {
+			return rec, fmt.Errorf("via: EraseUserData: store %q: %w", ns.name, err)
+		}
+	}
+	if a.cfg.keyStore != nil {
+		if err := a.EraseDataSubject(ctx, subject); err != nil {
+			return rec, fmt.Errorf("via: EraseUserData: %w", err)
+		}
+		rec.CryptoShredded = true
+	}
+
+	left := a.collectUserData(ctx, subject)
+	rec.Verified = len(left.Sessions) == 0 && len(left.Stores) == 0 && len(left.Errors) == 0
+	a.recordUserDataAudit(ctx, rec)
+	return rec, nil
+}
+
+// clearCell overwrites a Store cell with JSON null — the Store has no
+// delete, and null decodes to the zero value. Reports whether the cell
+// existed.
+func (a *App) clearCell(ctx context.Context, key string) (bool, error) {
+	for {
+		data, rev, ok, err := a.backplane.LoadSnapshot(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if !ok || string(data) == "null" {
+			return false, nil
+		}
+		_, err = a.backplane.CAS(ctx, key, rev, []byte("null"))
+		if errors.Is(err, ErrCASConflict) {
+			continue
+		}
+		return err == nil, err
+	}
+}
+
+func (a *App) userStoreNames() []string {
+	names := make([]string, 0, len(a.cfg.userStores))
+	for _, ns := range a.cfg.userStores {
+		names = append(names, ns.name)
+	}
+	return names
+}
+
+// recordUserDataAudit logs rec and appends it to the backplane audit log.
+// A failed append is logged, not returned: the request itself succeeded.
+func (a *App) recordUserDataAudit(ctx context.Context, rec UserDataAudit) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	a.logInfo(nil, "via user data %s", b)
+	if _, err := a.backplane.Append(ctx, auditUserDataKey, b); err != nil {
+		a.logErr(nil, "via: append user data audit: %v", err)
+	}
+}
+
+// isEmptyExport reports whether a store's export holds nothing.
+func isEmptyExport(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
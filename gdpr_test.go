@@ -0,0 +1,127 @@
+package via_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profilePage struct {
+	Theme via.StateSessStr
+}
+
+func (p *profilePage) Login(ctx *via.Ctx) { ctx.SignIn(ctx.EventArg("user")) }
+
+func (p *profilePage) Paint(ctx *via.Ctx) {
+	_ = p.Theme.Update(ctx, func(string) (string, error) { return "midnight", nil })
+}
+
+func (p *profilePage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Text(ctx.User()+":"), p.Theme.Text(ctx))
+}
+
+// orderStore is a UserDataStore fake holding orders by user.
+type orderStore struct {
+	mu     sync.Mutex
+	orders map[string][]string
+}
+
+func (s *orderStore) ExportUserData(_ context.Context, subject string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.orders[subject], nil
+}
+
+func (s *orderStore) EraseUserData(_ context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.orders, subject)
+	return nil
+}
+
+func TestExportUserData_collectsSessionsAndStores(t *testing.T) {
+	t.Parallel()
+
+	orders := &orderStore{orders: map[string][]string{"ada": {"#1", "#2"}}}
+	app := via.New(via.WithUserDataStore("orders", orders))
+	server := vt.Serve(t, app)
+	via.Mount[profilePage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, http.StatusOK, tc.Action("Login").WithArg("user", "ada").Fire())
+	require.Equal(t, http.StatusOK, tc.Action("Paint").Fire())
+	vt.NewClient(t, server, "/") // someone else's session
+
+	data, err := app.ExportUserData(context.Background(), "ada")
+	require.NoError(t, err)
+	require.Len(t, data.Sessions, 1)
+	assert.Equal(t, "ada", data.Sessions[0].User)
+	assert.Equal(t, []string{"#1", "#2"}, data.Stores["orders"])
+
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"midnight"`)
+}
+
+func TestEraseUserData_deletesEverythingAndAudits(t *testing.T) {
+	t.Parallel()
+
+	bp := via.InMemory()
+	orders := &orderStore{orders: map[string][]string{"ada": {"#1"}}}
+	app := via.New(via.WithBackplane(bp), via.WithKeyStore(via.InMemoryKeyStore()),
+		via.WithUserDataStore("orders", orders))
+	server := vt.Serve(t, app)
+	via.Mount[profilePage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, http.StatusOK, tc.Action("Login").WithArg("user", "ada").Fire())
+	require.Equal(t, http.StatusOK, tc.Action("Paint").Fire())
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	rec, err := app.EraseUserData(context.Background(), "ada")
+	require.NoError(t, err)
+	assert.Equal(t, 1, rec.Sessions)
+	assert.Equal(t, 1, rec.Cells, "the StateSess cell is overwritten in the backplane")
+	assert.True(t, rec.CryptoShredded)
+	assert.True(t, rec.Verified)
+	assert.Equal(t, []string{"orders"}, rec.Stores)
+
+	vt.AwaitFrame(t, frames, 2*time.Second, "location.reload()")
+	data, err := app.ExportUserData(context.Background(), "ada")
+	require.NoError(t, err)
+	assert.Empty(t, data.Sessions)
+	assert.Empty(t, data.Stores)
+
+	ctx, stop := context.WithTimeout(context.Background(), 2*time.Second)
+	defer stop()
+	audit, err := bp.Subscribe(ctx, "via.audit.userdata", 0)
+	require.NoError(t, err)
+	var actions []string
+	for range 2 {
+		r := <-audit
+		var got via.UserDataAudit
+		require.NoError(t, json.Unmarshal(r.Data, &got))
+		assert.Equal(t, "ada", got.Subject)
+		actions = append(actions, got.Action)
+	}
+	assert.Equal(t, []string{"erase", "export"}, actions)
+}
+
+func TestWithUserDataStore_rejectsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	s := &orderStore{}
+	assert.Panics(t, func() {
+		via.New(via.WithUserDataStore("orders", s), via.WithUserDataStore("orders", s))
+	})
+}
@@ -5,6 +5,7 @@ go 1.24.0
 toolchain go1.26.4
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/chromedp/chromedp v0.14.1
 	github.com/shirou/gopsutil/v4 v4.26.3
 	github.com/starfederation/datastar-go v1.0.3
@@ -14,7 +15,6 @@ require (
 
 require (
 	github.com/CAFxX/httpcompression v0.0.9 // indirect
-	github.com/andybalholm/brotli v1.2.0 // indirect
 	github.com/chromedp/cdproto v0.0.0-20250724212937-08a3db8b4327 // indirect
 	github.com/chromedp/sysutil v1.1.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,61 @@
+package h
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// AnimateChildren plays CSS classes on the element's children as patches
+// add and remove them, so list changes can fade or slide without custom
+// JS. enter is added to a child when it is inserted — by a morph, an
+// AppendTo or a PrependTo — and taken off once its animation ends. leave
+// is played on a copy of a removed child, left in its place until the
+// animation ends:
+//
+//	h.Ul(h.ID("messages"), h.AnimateChildren("msg-in", "msg-out"),
+//	    h.Each(msgs, func(m Msg) h.H { return h.Li(h.ID(m.ID), h.T(m.Text)) }),
+//	)
+//
+//	.msg-in  { animation: fade-in 200ms ease-out; }
+//	.msg-out { animation: fade-out 200ms ease-in; }
+//
+// Both take space-separated class lists; an empty list skips that side.
+// Durations are read from the computed animation and transition times,
+// so a class without either is removed straight away. Only direct
+// children are watched, and a child the morph merely moves plays
+// neither. The leaving copy is inert and keeps its id: a patch that
+// brings the element back takes it over instead of adding a duplicate.
+// Children present when the page loads don't play enter. Returns nil
+// when both lists are empty.
+func AnimateChildren(enter, leave string) H {
+	e, l := strings.Fields(enter), strings.Fields(leave)
+	if len(e) == 0 && len(l) == 0 {
+		return nil
+	}
+	ej, _ := json.Marshal(e)
+	lj, _ := json.Marshal(l)
+	return Data("init", `(()=>{if(el._viaAnim)return;el._viaAnim=1;var E=`+string(ej)+`,L=`+string(lj)+`;`+animateChildrenJS)
+}
+
+// animateChildrenJS is the body of AnimateChildren's observer, after E
+// (enter classes) and L (leave classes) are declared. It avoids '$' and
+// '@': Datastar rewrites those in an expression as signals and actions.
+const animateChildrenJS = `function ms(n){var s=getComputedStyle(n),t=0;` +
+	`function mx(d,l){d=d.split(',');l=l.split(',');for(var i=0;i<d.length;i++)` +
+	`t=Math.max(t,(parseFloat(d[i])||0)+(parseFloat(l[i%l.length])||0))}` +
+	`mx(s.animationDuration,s.animationDelay);mx(s.transitionDuration,s.transitionDelay);return t*1000}` +
+	`new MutationObserver(function(rs){var add=new Set(),gone=new Set(),rem=[];` +
+	`rs.forEach(function(r){` +
+	`r.addedNodes.forEach(function(n){if(n.nodeType===1&&!n.hasAttribute('data-via-leaving'))add.add(n)});` +
+	`r.removedNodes.forEach(function(n){if(n.nodeType===1&&!n.hasAttribute('data-via-leaving')){gone.add(n);rem.push([n,r])}})});` +
+	// A removed node that is connected again was moved, not removed.
+	`if(L.length)rem.forEach(function(x){var n=x[0],r=x[1];if(n.isConnected||add.has(n))return;` +
+	`var c=n.cloneNode(true),nx=r.nextSibling,pv=r.previousSibling;` +
+	`c.setAttribute('data-via-leaving','');c.setAttribute('inert','');` +
+	`el.insertBefore(c,nx&&nx.parentNode===el?nx:pv&&pv.parentNode===el?pv.nextSibling:null);` +
+	`c.classList.add.apply(c.classList,L);` +
+	`setTimeout(function(){if(c.hasAttribute('data-via-leaving'))c.remove()},ms(c))});` +
+	`if(E.length)add.forEach(function(n){if(n.parentNode!==el||gone.has(n))return;` +
+	`n.classList.add.apply(n.classList,E);` +
+	`setTimeout(function(){n.classList.remove.apply(n.classList,E)},ms(n))})` +
+	`}).observe(el,{childList:true})})()`
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strings"
 )
 
 // numeric constrains [AttrNum] and the numeric attribute siblings to the
@@ -120,6 +121,11 @@ func Aria(name, value string) H {
 	return buildAttr("aria-"+name, value)
 }
 
+// AriaLabel emits the aria-label attribute: the accessible name of an
+// element whose visible content doesn't describe it, such as an icon
+// button.
+func AriaLabel(v string) H { return buildAttr("aria-label", v) }
+
 // One shorthand per common HTML attribute — each emits `name="value"`
 // (HTML-escaped) via [buildAttr]. For an attribute without a shorthand use
 // [Attr]; for data-* use [Data]; for boolean attributes see [Selected],
@@ -184,6 +190,10 @@ func AutoComplete(v string) H { return buildAttr("autocomplete", v) }
 // TabIndex emits the tabindex attribute.
 func TabIndex(v string) H { return buildAttr("tabindex", v) }
 
+// TabIndexNum is the numeric form of [TabIndex]: 0 joins the tab order,
+// -1 makes an element focusable from script only.
+func TabIndexNum(n int) H { return AttrNum("tabindex", n) }
+
 // ColSpan emits the colspan attribute.
 func ColSpan(v string) H { return buildAttr("colspan", v) }
 
@@ -274,6 +284,55 @@ func Styles(parts ...string) H {
 	return &attrNode{name: "style", value: string(out)}
 }
 
+// TransitionName names the element for view transitions — emitted as
+// `style="view-transition-name:name"` — so a patch applied with
+// via's Ctx.ViewTransition moves it from its old position to its new one
+// instead of cross-fading. Names must be unique on the page; derive them
+// from the item's key:
+//
+//	h.Li(h.TransitionName("card-"+strconv.Itoa(c.ID)), h.T(c.Title))
+//
+// It sets the style attribute, so an element that has other inline
+// styles takes [TransitionStyle] into its [Styles] call instead. Empty
+// name returns nil. Panics on a name that isn't a CSS identifier.
+func TransitionName(name string) H {
+	if name == "" {
+		return nil
+	}
+	return Style(TransitionStyle(name))
+}
+
+// TransitionStyle is the `view-transition-name:name` declaration
+// [TransitionName] emits, for combining with other inline styles:
+//
+//	h.Styles("flex:1", h.TransitionStyle("card-3"))
+//
+// Empty name returns "". Panics on a name that isn't a CSS identifier.
+func TransitionStyle(name string) string {
+	if name == "" {
+		return ""
+	}
+	if !isCSSIdent(name) {
+		panic(fmt.Sprintf("h: TransitionName: %q is not a CSS identifier", name))
+	}
+	return "view-transition-name:" + name
+}
+
+// isCSSIdent reports whether s is a plain CSS identifier: ASCII letters,
+// digits, '-' and '_', not starting with a digit or "-" plus a digit.
+func isCSSIdent(s string) bool {
+	rest := strings.TrimPrefix(s, "-")
+	if rest == "" || rest[0] >= '0' && rest[0] <= '9' {
+		return false
+	}
+	for _, c := range s {
+		if !(c == '-' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // Selected emits the boolean `selected` attribute.
 func Selected() H { return buildBool("selected") }
 
@@ -286,6 +345,9 @@ func Required() H { return buildBool("required") }
 // Disabled emits the boolean `disabled` attribute.
 func Disabled() H { return buildBool("disabled") }
 
+// Autofocus emits the boolean `autofocus` attribute.
+func Autofocus() H { return buildBool("autofocus") }
+
 // Class joins non-empty class names with spaces and emits a single
 // class attribute. Returns nil when no class names remain so the
 // attribute is omitted entirely.
@@ -356,6 +418,37 @@ func ClassMap(m map[string]bool) H {
 	return Class(keys...)
 }
 
+// StyleMap is the map form of [Styles]: each property with a non-empty
+// value becomes a `property:value` declaration, in sorted property order
+// so the output is stable across renders. An empty value drops its
+// property, which keeps conditionals inline:
+//
+//	h.StyleMap(map[string]string{
+//	    "width":   fmt.Sprintf("%d%%", pct),
+//	    "opacity": h.IfStr(faded, "0.5"),
+//	})
+func StyleMap(m map[string]string) H {
+	if len(m) == 0 {
+		return nil
+	}
+	props := make([]string, 0, len(m))
+	for k, v := range m {
+		if k == "" || v == "" {
+			continue
+		}
+		props = append(props, k)
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	slices.Sort(props)
+	decls := make([]string, len(props))
+	for i, k := range props {
+		decls[i] = k + ":" + m[k]
+	}
+	return Styles(decls...)
+}
+
 // IfStr returns s if cond is true, "" otherwise. Pairs with [Class]
 // and [Styles] for inline conditional fragments.
 func IfStr(cond bool, s string) string {
@@ -28,6 +28,9 @@ func TestShorthands_emitNamedHTMLEscapedAttribute(t *testing.T) {
 		{"method", h.Method("post"), `method="post"`},
 		{"autocomplete", h.AutoComplete("email"), `autocomplete="email"`},
 		{"tabindex", h.TabIndex("0"), `tabindex="0"`},
+		{"tabindex num", h.TabIndexNum(-1), `tabindex="-1"`},
+		{"aria-label", h.AriaLabel("Close <dialog>"), `aria-label="Close &lt;dialog&gt;"`},
+		{"autofocus", h.Autofocus(), ` autofocus>`},
 		{"colspan", h.ColSpan("2"), `colspan="2"`},
 		{"rowspan", h.RowSpan("3"), `rowspan="3"`},
 	}
@@ -90,3 +93,24 @@ func TestNumericRangeSiblings_avoidStrconvAtCallSite(t *testing.T) {
 	assert.Contains(t, got, `step="0.5"`)
 	assert.Contains(t, got, `value="3"`)
 }
+
+func TestTransitionName_emitsViewTransitionStyle(t *testing.T) {
+	t.Parallel()
+	assert.Contains(t, render(t, h.Li(h.TransitionName("card-3"))), `style="view-transition-name:card-3"`)
+	assert.Nil(t, h.TransitionName(""))
+	assert.Contains(t, render(t, h.Li(h.Styles("flex:1", h.TransitionStyle("_row")))),
+		`style="flex:1;view-transition-name:_row"`)
+	for _, bad := range []string{"3card", "-3", "-", "card 3", "card;color:red", `card"`} {
+		assert.Panics(t, func() { h.TransitionStyle(bad) }, bad)
+	}
+}
+
+func TestAnimateChildren_embedsClassListsInAnInitObserver(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Ul(h.AnimateChildren("fade in", "")))
+	assert.Contains(t, got, `data-init="(()=&gt;{if(el._viaAnim)return;`)
+	assert.Contains(t, got, `var E=[&#34;fade&#34;,&#34;in&#34;],L=[];`)
+	assert.Contains(t, got, `MutationObserver`)
+	assert.NotContains(t, got, "$", "Datastar would read it as a signal")
+	assert.Nil(t, h.AnimateChildren(" ", ""))
+}
@@ -12,6 +12,14 @@ type HTML5Props struct {
 	Head        []H
 	Body        []H
 	HTMLAttrs   []H
+
+	// BasePath prefixes the Datastar runtime's URL for an app served
+	// under a path prefix ("/app" loads "/app/_datastar.js").
+	BasePath string
+
+	// DatastarSrc, when set, is the Datastar runtime's URL as is, in
+	// place of BasePath + "/_datastar.js".
+	DatastarSrc string
 }
 
 // doctype is a stateless sentinel that prefixes its sibling with the
@@ -43,7 +51,11 @@ func HTML5(p HTML5Props) H {
 			head = append(head, n)
 		}
 	}
-	head = append(head, Script(Type("module"), Src("/_datastar.js")))
+	src := p.DatastarSrc
+	if src == "" {
+		src = p.BasePath + "/_datastar.js"
+	}
+	head = append(head, Script(Type("module"), Src(src)))
 
 	body := make([]H, 0, len(p.Body))
 	for _, n := range p.Body {
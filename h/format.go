@@ -0,0 +1,199 @@
+package h
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale is how numbers and amounts are written for a language: the
+// decimal and grouping separators, and which side of the amount a
+// currency symbol goes. The zero Locale formats like [LocaleEN]. Pick a
+// preset, parse one with [ParseLocale], or build your own.
+type Locale struct {
+	// Tag is the BCP 47 language tag, e.g. "de".
+	Tag string
+	// Decimal separates the fraction: "." or ",".
+	Decimal string
+	// Group separates thousands: ",", ".", a no-break space, or "" for
+	// none.
+	Group string
+	// CurrencyAfter writes "1.234,50 €" rather than "€1,234.50".
+	CurrencyAfter bool
+}
+
+// Locale presets for the common European languages.
+var (
+	LocaleEN = Locale{Tag: "en", Decimal: ".", Group: ","}
+	LocaleDE = Locale{Tag: "de", Decimal: ",", Group: ".", CurrencyAfter: true}
+	LocaleFR = Locale{Tag: "fr", Decimal: ",", Group: "\u202f", CurrencyAfter: true}
+	LocaleES = Locale{Tag: "es", Decimal: ",", Group: ".", CurrencyAfter: true}
+	LocaleIT = Locale{Tag: "it", Decimal: ",", Group: ".", CurrencyAfter: true}
+)
+
+var locales = map[string]Locale{
+	"en": LocaleEN, "de": LocaleDE, "fr": LocaleFR, "es": LocaleES, "it": LocaleIT,
+}
+
+// ParseLocale picks the first language of an Accept-Language header (or
+// a single tag such as "de-AT") that has a preset, in the order listed —
+// q-weights aren't consulted — and [LocaleEN] when none does.
+func ParseLocale(acceptLanguage string) Locale {
+	for part := range strings.SplitSeq(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		if l, ok := locales[strings.ToLower(lang)]; ok {
+			return l
+		}
+	}
+	return LocaleEN
+}
+
+func (l Locale) orDefault() Locale {
+	if l.Decimal == "" {
+		return LocaleEN
+	}
+	return l
+}
+
+// NumberOpts configures [TextNumber].
+type NumberOpts struct {
+	// Decimals is the fixed number of fraction digits; the value is
+	// rounded to fit.
+	Decimals int
+	// Locale supplies the separators.
+	Locale Locale
+}
+
+// TextNumber renders n as a text node with opts.Locale's separators and
+// exactly opts.Decimals fraction digits:
+//
+//	h.TextNumber(1234567.891, h.NumberOpts{Decimals: 2, Locale: h.LocaleDE}) // 1.234.567,89
+func TextNumber[T numeric](n T, opts NumberOpts) H {
+	return Text(formatNumber(n, opts.Decimals, opts.Locale.orDefault()))
+}
+
+// currencies maps ISO 4217 codes to their symbol and minor-unit digits.
+// Codes not listed render as the code itself with two decimals.
+var currencies = map[string]struct {
+	symbol   string
+	decimals int
+}{
+	"EUR": {"€", 2}, "USD": {"$", 2}, "GBP": {"£", 2}, "JPY": {"¥", 0},
+	"CHF": {"CHF", 2}, "CNY": {"¥", 2}, "INR": {"₹", 2}, "KRW": {"₩", 0},
+	"SEK": {"kr", 2}, "NOK": {"kr", 2}, "DKK": {"kr.", 2}, "PLN": {"zł", 2},
+}
+
+// TextCurrency renders amount in the ISO 4217 currency (e.g. "EUR"), with
+// the currency's usual number of decimals and loc's separators and symbol
+// placement:
+//
+//	h.TextCurrency(1234.5, "EUR", h.LocaleEN) // €1,234.50
+//	h.TextCurrency(1234.5, "EUR", h.LocaleDE) // 1.234,50 €
+func TextCurrency(amount float64, currency string, loc Locale) H {
+	loc = loc.orDefault()
+	code := strings.ToUpper(currency)
+	cur, ok := currencies[code]
+	if !ok {
+		cur.symbol, cur.decimals = code, 2
+	}
+	num := formatNumber(math.Abs(amount), cur.decimals, loc)
+	sign := ""
+	if amount < 0 && strings.ContainsAny(num, "123456789") {
+		sign = "-"
+	}
+	if loc.CurrencyAfter {
+		return Text(sign + num + "\u00a0" + cur.symbol)
+	}
+	if len(cur.symbol) > 1 && cur.symbol[0] < 0x80 {
+		// A lettered symbol ("CHF") needs a space before the digits.
+		return Text(sign + cur.symbol + "\u00a0" + num)
+	}
+	return Text(sign + cur.symbol + num)
+}
+
+// TextTime renders t as a <time> element: its text is t formatted with
+// the Go layout, its datetime attribute the machine-readable RFC 3339
+// instant. Month and day names come out in English.
+//
+//	h.TextTime(order.PlacedAt, "2 Jan 2006 15:04")
+func TextTime(t time.Time, layout string) H {
+	return Time(Attr("datetime", t.Format(time.RFC3339)), Text(t.Format(layout)))
+}
+
+// TimeAgo renders how long before now t was — "just now", "5 minutes
+// ago", "in 2 days" — as a <time> element whose title holds the exact
+// instant. The wording is English. Pass the app's clock reading as now,
+// so tests can pin it.
+func TimeAgo(t, now time.Time) H {
+	return Time(Attr("datetime", t.Format(time.RFC3339)),
+		Attr("title", t.Format("2006-01-02 15:04:05 MST")),
+		Text(relativeTime(now.Sub(t))))
+}
+
+func relativeTime(d time.Duration) string {
+	future := d < 0
+	if future {
+		d = -d
+	}
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "year"
+	}
+	s := strconv.Itoa(n) + " " + unit
+	if n != 1 {
+		s += "s"
+	}
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
+
+// formatNumber writes n with decimals fraction digits and loc's
+// separators. Integers are formatted exactly, not through float64.
+func formatNumber[T numeric](n T, decimals int, loc Locale) string {
+	decimals = max(decimals, 0)
+	var s string
+	if T(1)/T(2) == 0 { // an integer kind
+		s = fmt.Sprint(n)
+		if decimals > 0 {
+			s += "." + strings.Repeat("0", decimals)
+		}
+	} else {
+		s = strconv.FormatFloat(float64(n), 'f', decimals, 64)
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if neg && strings.ContainsAny(s, "123456789") {
+		b.WriteByte('-') // never "-0,00"
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(loc.Group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(loc.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package h_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextNumber_usesTheLocaleSeparators(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		node h.H
+		want string
+	}{
+		{"english", h.TextNumber(1234567.891, h.NumberOpts{Decimals: 2}), "1,234,567.89"},
+		{"german", h.TextNumber(1234567.891, h.NumberOpts{Decimals: 2, Locale: h.LocaleDE}), "1.234.567,89"},
+		{"french", h.TextNumber(-9876, h.NumberOpts{Locale: h.LocaleFR}), "-9\u202f876"},
+		{"integer with decimals", h.TextNumber(int64(1<<62), h.NumberOpts{Decimals: 1}), "4,611,686,018,427,387,904.0"},
+		{"no negative zero", h.TextNumber(-0.001, h.NumberOpts{Decimals: 2}), "0.00"},
+		{"short", h.TextNumber(999, h.NumberOpts{}), "999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, r(t, tt.node))
+		})
+	}
+}
+
+func TestTextCurrency_placesTheSymbolForTheLocale(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "€1,234.50", r(t, h.TextCurrency(1234.5, "EUR", h.LocaleEN)))
+	assert.Equal(t, "1.234,50\u00a0€", r(t, h.TextCurrency(1234.5, "eur", h.LocaleDE)))
+	assert.Equal(t, "-¥1,500", r(t, h.TextCurrency(-1499.6, "JPY", h.LocaleEN)))
+	assert.Equal(t, "CHF\u00a012.00", r(t, h.TextCurrency(12, "CHF", h.Locale{})))
+	assert.Equal(t, "XYZ\u00a01.00", r(t, h.TextCurrency(1, "XYZ", h.LocaleEN)), "an unknown code stands in for the symbol")
+}
+
+func TestParseLocale_takesTheFirstKnownLanguage(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, h.LocaleDE, h.ParseLocale("de-AT,de;q=0.9,en;q=0.8"))
+	assert.Equal(t, h.LocaleFR, h.ParseLocale("pt-BR, fr;q=0.5"))
+	assert.Equal(t, h.LocaleEN, h.ParseLocale(""))
+}
+
+func TestTextTime_carriesTheMachineReadableInstant(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 3, 9, 14, 5, 0, 0, time.UTC)
+	assert.Equal(t, `<time datetime="2026-03-09T14:05:00Z">9 Mar 2026 14:05</time>`,
+		r(t, h.TextTime(at, "2 Jan 2006 15:04")))
+}
+
+func TestTimeAgo_describesTheDistanceToNow(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(-20 * time.Second), "just now"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-5 * time.Hour), "5 hours ago"},
+		{now.Add(-3 * 24 * time.Hour), "3 days ago"},
+		{now.Add(2 * 24 * time.Hour), "in 2 days"},
+		{now.Add(-800 * 24 * time.Hour), "2 years ago"},
+	}
+	for _, tt := range tests {
+		assert.Contains(t, r(t, h.TimeAgo(tt.at, now)), ">"+tt.want+"</time>")
+	}
+	assert.Contains(t, r(t, h.TimeAgo(now, now)), `title="2026-03-09 12:00:00 UTC"`)
+}
@@ -36,6 +36,11 @@ func (g group) Render(w io.Writer) error {
 //	return Fragment(H2(T("title")), Hr())
 //	return Fragment(items...)
 //
+// It is the way to return siblings that can't take a wrapper — table
+// rows, list items, select options — and it nests: a fragment inside a
+// fragment renders flat. Patched to the client (via's Patch.Elements,
+// AppendTo, …), each top-level element morphs by its own id.
+//
 // Attribute arguments (ID, Class, on.*, …) panic at construction: a
 // fragment has no opening tag to put them in, so they were previously
 // dropped without a trace — a programming mistake better caught where
@@ -199,19 +204,41 @@ func EachSeq2[K, V any](seq iter.Seq2[K, V], fn func(K, V) H) H {
 }
 
 // SwitchCase pairs a key with the node to render when [Switch]'s value
-// matches the key. Build with [Case] / [Default].
+// matches the key. Build with [Case] / [Default], or [CaseFn] /
+// [DefaultFn] for a branch built only when it wins.
 type SwitchCase[K comparable] struct {
 	key       K
 	node      H
+	build     func() H
 	isDefault bool
 }
 
+func (c SwitchCase[K]) render() H {
+	if c.build != nil {
+		return c.build()
+	}
+	return c.node
+}
+
 // Case returns a [SwitchCase] that fires when [Switch]'s value equals
 // key.
 func Case[K comparable](key K, node H) SwitchCase[K] {
 	return SwitchCase[K]{key: key, node: node}
 }
 
+// CaseFn is [Case] with a builder, so the branch is constructed only if
+// it is the one rendered — the [When] of switches. Use it when building
+// a losing branch would dereference data that only the winning key
+// guarantees:
+//
+//	h.Switch(p.Tab,
+//	    h.CaseFn("invoice", func() h.H { return invoiceView(p.Invoice) }), // p.Invoice may be nil
+//	    h.DefaultFn[string](func() h.H { return overview(p) }),
+//	)
+func CaseFn[K comparable](key K, build func() H) SwitchCase[K] {
+	return SwitchCase[K]{key: key, build: orNothing(build)}
+}
+
 // Default returns a [SwitchCase] that fires when no other case matches.
 // At most one Default per Switch is honoured (the first one wins).
 //
@@ -221,6 +248,21 @@ func Default[K comparable](node H) SwitchCase[K] {
 	return SwitchCase[K]{node: node, isDefault: true}
 }
 
+// DefaultFn is [Default] with a builder that runs only when no other
+// case matches. Like Default, K is spelled explicitly.
+func DefaultFn[K comparable](build func() H) SwitchCase[K] {
+	return SwitchCase[K]{build: orNothing(build), isDefault: true}
+}
+
+// orNothing maps a nil builder to one rendering nothing, so a nil
+// CaseFn still counts as a matched (empty) branch.
+func orNothing(build func() H) func() H {
+	if build == nil {
+		return func() H { return nil }
+	}
+	return build
+}
+
 // Switch renders the first matching [SwitchCase] and nothing else.
 //
 // value and every [Case] key share the comparable type K, so a
@@ -230,19 +272,22 @@ func Default[K comparable](node H) SwitchCase[K] {
 // for tab-style branching on such a value, project it to a simple key
 // first (e.g. a tag string or enum) and Switch on that.
 func Switch[K comparable](value K, cases ...SwitchCase[K]) H {
-	var fallback H
-	for _, c := range cases {
+	fallback := -1
+	for i, c := range cases {
 		if c.isDefault {
-			if fallback == nil {
-				fallback = c.node
+			if fallback < 0 {
+				fallback = i
 			}
 			continue
 		}
 		if c.key == value {
-			return c.node
+			return c.render()
 		}
 	}
-	return fallback
+	if fallback < 0 {
+		return nil
+	}
+	return cases[fallback].render()
 }
 
 // staticNode is an [H] whose render output is the captured byte slice
@@ -310,3 +355,59 @@ func With(base H, more ...H) H {
 	out = append(out, more...)
 	return group(out)
 }
+
+// Keyed is [Each] for lists that change in the middle: every rendered
+// item carries id=key(item), so a morph pairs old and new nodes by
+// identity rather than by position. Inserting or removing an item then
+// leaves its siblings' DOM untouched — input focus, scroll offsets and
+// running CSS transitions survive the patch.
+//
+//	h.Ul(h.Keyed(todos, func(t Todo) string { return "todo-" + t.ID },
+//	    func(t Todo) h.H { return h.Li(h.Text(t.Title)) }))
+//
+// Ids are document-global, so namespace the key ("todo-42", not "42").
+// When fn returns an element the id is set on it, replacing any id it
+// already carries; any other node (text, a fragment) is wrapped in a
+// div that carries the id. A key repeated within items panics — two
+// nodes sharing an id defeat the pairing the helper exists for.
+func Keyed[T any](items []T, key func(T) string, fn func(T) H) H {
+	if len(items) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(items))
+	out := make(group, 0, len(items))
+	for _, it := range items {
+		n := fn(it)
+		if n == nil {
+			continue
+		}
+		k := key(it)
+		if _, dup := seen[k]; dup {
+			panic(fmt.Sprintf("h.Keyed: key %q is repeated — every item needs a distinct id", k))
+		}
+		seen[k] = struct{}{}
+		out = append(out, withID(n, k))
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// withID returns n carrying id. Elements are copied, never mutated, so
+// a node the caller caches and reuses keeps its original attributes.
+func withID(n H, id string) H {
+	e, ok := n.(*element)
+	if !ok {
+		return el("div", []H{ID(id), n})
+	}
+	children := make([]H, 0, len(e.children)+1)
+	children = append(children, ID(id))
+	for _, c := range e.children {
+		if a, ok := c.(*attrNode); ok && a.name == "id" {
+			continue
+		}
+		children = append(children, c)
+	}
+	return &element{tag: e.tag, children: children, void: e.void}
+}
@@ -141,6 +141,49 @@ func BenchmarkStaticHeader_render(b *testing.B) {
 	}
 }
 
+// siteShell is the chrome most pages repeat around their content: a
+// long inline style block, a nav bar and a footer — none of it
+// dependent on per-request state.
+func siteShell() h.H {
+	links := make([]h.H, 0, 12)
+	for i := range 12 {
+		links = append(links, h.Li(h.A(h.Href(fmt.Sprintf("/section/%d", i)), h.Textf("Section %d", i))))
+	}
+	rules := make([]h.H, 0, 40)
+	for i := range 40 {
+		rules = append(rules, h.Textf(".c%d{margin:%dpx;padding:%dpx}", i, i, i*2))
+	}
+	return h.Fragment(
+		h.StyleEl(rules...),
+		h.Nav(h.Class("container"), h.Ul(h.Li(h.Strong(h.T("Acme")))), h.Ul(links...)),
+		h.Footer(h.Class("container"),
+			h.P(h.T("© Acme Corp. All rights reserved.")),
+			h.Ul(h.Li(h.A(h.Href("/privacy"), h.T("Privacy"))), h.Li(h.A(h.Href("/terms"), h.T("Terms")))),
+		),
+	)
+}
+
+// BenchmarkSiteShell_render rebuilds the shell on every render, as a
+// View that constructs it inline does on every Sync.
+func BenchmarkSiteShell_render(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		_ = h.Body(siteShell(), h.Main(h.P(h.T("content")))).Render(io.Discard)
+	}
+}
+
+// BenchmarkSiteShell_static_render holds the same shell in [Static],
+// built once, so each render writes its bytes in one call. Compare
+// with BenchmarkSiteShell_render.
+func BenchmarkSiteShell_static_render(b *testing.B) {
+	shell := h.Static(siteShell())
+	b.ResetTimer()
+	b.ReportAllocs()
+	for range b.N {
+		_ = h.Body(shell, h.Main(h.P(h.T("content")))).Render(io.Discard)
+	}
+}
+
 // SysmonShape mirrors the tree shape rendered by the system-monitor
 // example (`internal/examples/sysmon`): a nav with a single title, an
 // Article with two controls (a range input + a button), and four
@@ -1,6 +1,7 @@
 package h_test
 
 import (
+	"fmt"
 	"slices"
 	"strings"
 	"testing"
@@ -147,6 +148,36 @@ func TestEachSeq2_consumesIndexedIterators(t *testing.T) {
 	assert.Equal(t, "<ul><li>0:x</li><li>1:y</li></ul>", got)
 }
 
+func TestKeyed_stampsKeyAsIDOnEachItem(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Ul(
+		h.Keyed([]int{3, 7}, func(n int) string { return fmt.Sprintf("row-%d", n) },
+			func(n int) h.H { return h.Li(h.ID("ignored"), h.Class("row"), h.Textf("%d", n)) }),
+	))
+	assert.Equal(t, `<ul><li id="row-3" class="row">3</li><li id="row-7" class="row">7</li></ul>`, got)
+}
+
+func TestKeyed_wrapsNonElementItems(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Keyed([]string{"a"}, func(s string) string { return "k-" + s },
+		func(s string) h.H { return h.Text(s) }))
+	assert.Equal(t, `<div id="k-a">a</div>`, got)
+}
+
+func TestKeyed_doesNotMutateReusedNodes(t *testing.T) {
+	t.Parallel()
+	shared := h.Li(h.Text("x"))
+	_ = render(t, h.Keyed([]int{1}, func(int) string { return "one" }, func(int) h.H { return shared }))
+	assert.Equal(t, `<li>x</li>`, render(t, shared))
+}
+
+func TestKeyed_repeatedKeyPanics(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, `h.Keyed: key "same" is repeated — every item needs a distinct id`, func() {
+		h.Keyed([]int{1, 2}, func(int) string { return "same" }, func(int) h.H { return h.Li() })
+	})
+}
+
 func TestClasses_joinsAndSkipsEmpty(t *testing.T) {
 	t.Parallel()
 	got := render(t, h.Div(h.Classes("btn", "", "primary")))
@@ -189,6 +220,22 @@ func TestClassMap_allFalseProducesNoAttribute(t *testing.T) {
 	assert.Equal(t, `<div></div>`, got)
 }
 
+func TestStyleMap_emitsSortedDeclarationsSkippingEmptyValues(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Div(h.StyleMap(map[string]string{
+		"width":   "40%",
+		"color":   "red",
+		"opacity": h.IfStr(false, "0.5"),
+	})))
+	assert.Equal(t, `<div style="color:red;width:40%"></div>`, got)
+}
+
+func TestStyleMap_allEmptyProducesNoAttribute(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Div(h.StyleMap(map[string]string{"opacity": ""})))
+	assert.Equal(t, `<div></div>`, got)
+}
+
 func TestIfStr_returnsConditional(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "yes", h.IfStr(true, "yes"))
@@ -236,6 +283,38 @@ func TestSwitch_typedKeysCompareEquality(t *testing.T) {
 	assert.Contains(t, got, "β")
 }
 
+func TestSwitch_caseFnBuildsOnlyTheWinningBranch(t *testing.T) {
+	t.Parallel()
+	type invoice struct{ Number string }
+	var inv *invoice // nil: only the "overview" branch is safe to build
+	got := render(t, h.Div(h.Switch("overview",
+		h.CaseFn("invoice", func() h.H { return h.P(h.Text(inv.Number)) }),
+		h.CaseFn("overview", func() h.H { return h.P(h.Text("o")) }),
+		h.DefaultFn[string](func() h.H { panic("default built") }),
+	)))
+	assert.Equal(t, "<div><p>o</p></div>", got)
+}
+
+func TestSwitch_defaultFnBuildsWhenNothingMatches(t *testing.T) {
+	t.Parallel()
+	built := 0
+	got := render(t, h.Div(h.Switch(3,
+		h.Case(1, h.P(h.Text("one"))),
+		h.DefaultFn[int](func() h.H { built++; return h.P(h.Text("other")) }),
+	)))
+	assert.Equal(t, "<div><p>other</p></div>", got)
+	assert.Equal(t, 1, built)
+}
+
+func TestSwitch_nilCaseFnMatchesAndRendersNothing(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.Div(h.Switch("a",
+		h.CaseFn[string]("a", nil),
+		h.Default[string](h.P(h.Text("d"))),
+	)))
+	assert.Equal(t, "<div></div>", got)
+}
+
 func TestFragment_rendersChildrenWithoutWrapper(t *testing.T) {
 	t.Parallel()
 	got := render(t, h.Div(h.Fragment(
@@ -399,6 +478,12 @@ func TestHTML5_omitsOptionalsWhenEmpty(t *testing.T) {
 	assert.NotContains(t, got, "description", "Description meta must be omitted when empty")
 }
 
+func TestHTML5_basePathPrefixesRuntime(t *testing.T) {
+	t.Parallel()
+	got := render(t, h.HTML5(h.HTML5Props{Title: "T", BasePath: "/app"}))
+	assert.Contains(t, got, `<script type="module" src="/app/_datastar.js"></script>`)
+}
+
 func TestRawAttr_inlinesPreEscapedBytes(t *testing.T) {
 	t.Parallel()
 	got := render(t, h.Div(h.RawAttr([]byte(` data-x="1"`))))
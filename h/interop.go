@@ -0,0 +1,60 @@
+package h
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+)
+
+// templateNode renders an html/template with its data at Render time.
+type templateNode struct {
+	t    *template.Template
+	data any
+}
+
+func (n *templateNode) Render(w io.Writer) error {
+	// Execute into a buffer first so a failing template writes nothing
+	// rather than half an element.
+	var buf bytes.Buffer
+	if err := n.t.Execute(&buf, n.data); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// FromTemplate renders tmpl with data in place, so a page can move to
+// via while its existing html/template partials keep working:
+//
+//	h.Main(
+//	    h.H1(h.Text("Orders")),
+//	    h.FromTemplate(legacy.Lookup("orders_table.html"), orders),
+//	)
+//
+// The template runs on every Render, with html/template's own
+// contextual escaping; its output is trusted like [Raw]. An execution
+// error is returned from Render and the template writes nothing.
+func FromTemplate(tmpl *template.Template, data any) H {
+	if tmpl == nil {
+		return nil
+	}
+	return &templateNode{t: tmpl, data: data}
+}
+
+// ParseHTML parses s as an HTML fragment and keeps only what's safe to
+// show from an untrusted source — CMS content, an imported post, a
+// partial pasted from an older page:
+//
+//	h.Article(h.ParseHTML(post.BodyHTML))
+//
+// Formatting, list, table and sectioning elements survive with a few
+// plain attributes each; links and images keep http, https, mailto and
+// relative URLs only. Scripts, styles, frames and form controls are
+// dropped with their content, other unknown tags are unwrapped to
+// their text, and event handlers, style, id and data-* attributes are
+// removed. Unclosed elements are closed, so the fragment can't swallow
+// the page around it. Unlike [Raw], nothing in s is trusted. It is
+// [Sanitize] with [SafePolicy].
+func ParseHTML(s string) H {
+	return Sanitize(s, safePolicy)
+}
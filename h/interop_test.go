@@ -0,0 +1,71 @@
+package h_test
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromTemplate_rendersLegacyTemplateInPlace(t *testing.T) {
+	t.Parallel()
+	tmpl := template.Must(template.New("row").Parse(`<td>{{.Name}}</td><td>{{.Qty}}</td>`))
+	got := r(t, h.Tr(h.Class("order"), h.FromTemplate(tmpl, struct {
+		Name string
+		Qty  int
+	}{"<Widget>", 3})))
+	assert.Equal(t, `<tr class="order"><td>&lt;Widget&gt;</td><td>3</td></tr>`, got)
+}
+
+func TestFromTemplate_executionErrorWritesNothing(t *testing.T) {
+	t.Parallel()
+	tmpl := template.Must(template.New("bad").Parse(`<p>{{.Missing.Field}}</p>`))
+	var buf strings.Builder
+	err := h.FromTemplate(tmpl, struct{ Missing *struct{ Field string } }{}).Render(&buf)
+	assert.Error(t, err)
+	assert.Empty(t, buf.String())
+	assert.Nil(t, h.FromTemplate(nil, nil))
+}
+
+func TestParseHTML_keepsSafeMarkup(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, src, want string
+	}{
+		{"formatting", `<p class="lead">Hi <B>there</B> &amp; <em>you</em></p>`,
+			`<p class="lead">Hi <b>there</b> &amp; <em>you</em></p>`},
+		{"links and images", `<a href="/docs" title='Docs'>docs</a><img src=https://x.test/a.png alt="A">`,
+			`<a href="/docs" title="Docs">docs</a><img src="https://x.test/a.png" alt="A">`},
+		{"tables", `<table><tr><td colspan=2>x</td></tr></table>`,
+			`<table><tr><td colspan="2">x</td></tr></table>`},
+		{"unclosed elements are closed", `<div><p>open`, `<div><p>open</p></div>`},
+		{"mismatched closers are matched", `<ul><li>a</ul>`, `<ul><li>a</li></ul>`},
+		{"unknown tags are unwrapped", `<font color=red>red</font> <custom-el>x</custom-el>`, `red x`},
+		{"comments are removed", `a<!-- secret -->b`, `ab`},
+		{"stray angle brackets are escaped", `1 < 2 > 0`, `1 &lt; 2 &gt; 0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, r(t, h.ParseHTML(tt.src)))
+		})
+	}
+}
+
+func TestParseHTML_removesUnsafeMarkup(t *testing.T) {
+	t.Parallel()
+	got := r(t, h.ParseHTML(`<script>alert(1)</script><STYLE>p{}</STYLE>`+
+		`<p onclick="x()" style="color:red" id="main" data-on-click="@post('/x')">ok</p>`+
+		`<a href="javascript:alert(1)">j</a><a href="jav&#x09;ascript:alert(1)">k</a>`+
+		`<img src="data:image/png;base64,AA"><iframe src="/x">inside</iframe>`+
+		`<form action="/x"><input name="q"></form>`))
+	assert.Equal(t, `<p>ok</p><a>j</a><a>k</a><img>`, got)
+}
+
+func TestParseHTML_emptyInputRendersNothing(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, h.ParseHTML(""))
+	assert.Nil(t, h.ParseHTML("<script>x</script>"))
+}
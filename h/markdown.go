@@ -0,0 +1,641 @@
+package h
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MarkdownOption configures [Markdown].
+type MarkdownOption func(*mdConfig)
+
+type mdConfig struct {
+	noImages     bool
+	headingShift int
+}
+
+// MarkdownNoImages renders images as their alt text — for user content
+// such as chat messages, where an image URL would let the author load
+// (and track) anything in every reader's browser.
+func MarkdownNoImages() MarkdownOption {
+	return func(c *mdConfig) { c.noImages = true }
+}
+
+// MarkdownHeadingShift demotes every heading by n levels, capped at h6,
+// so a document's "# Title" can sit under the page's own <h1>.
+func MarkdownHeadingShift(n int) MarkdownOption {
+	return func(c *mdConfig) { c.headingShift = max(n, 0) }
+}
+
+// Markdown renders src, written in Markdown, to HTML on the server:
+//
+//	h.Article(h.Markdown(post.Body, h.MarkdownHeadingShift(1)))
+//
+// It covers the CommonMark constructs content actually uses — ATX and
+// setext headings, paragraphs, emphasis, inline and fenced code, block
+// quotes, nested bullet and ordered lists, thematic breaks, hard line
+// breaks, links, images and autolinks — plus ~~strikethrough~~.
+// Reference-style links and HTML entities are not recognised.
+//
+// The output is safe for untrusted input: all text is escaped, raw HTML
+// in src renders as text, and a link or image whose URL is not http,
+// https, mailto or relative keeps its text but loses the URL. An empty
+// src renders nothing.
+func Markdown(src string, opts ...MarkdownOption) H {
+	var r mdRenderer
+	for _, o := range opts {
+		o(&r.cfg)
+	}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	r.blocks(strings.Split(src, "\n"))
+	if r.out.Len() == 0 {
+		return nil
+	}
+	return &rawNode{s: r.out.String()}
+}
+
+// Limits that keep hostile input from going quadratic: block quotes and
+// lists nest at most mdMaxDepth deep (deeper markers render as text),
+// and a link's text, destination, title and autolink are each at most
+// mdMaxLinkText bytes.
+const (
+	mdMaxDepth    = 16
+	mdMaxLinkText = 1000
+)
+
+type mdRenderer struct {
+	cfg mdConfig
+	out strings.Builder
+	// tight renders paragraphs without <p>, for the items of a tight list.
+	tight bool
+	depth int
+}
+
+func (r *mdRenderer) blocks(lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		text := strings.TrimRight(strings.Join(para, "\n"), " ")
+		if r.tight {
+			r.out.WriteString(r.inline(text))
+			r.out.WriteByte('\n')
+		} else {
+			r.out.WriteString("<p>" + r.inline(text) + "</p>\n")
+		}
+		para = para[:0]
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		switch {
+		case trimmed == "":
+			flush()
+			i++
+		case indent >= 4 && len(para) == 0:
+			var code []string
+			for ; i < len(lines) && (strings.TrimSpace(lines[i]) == "" || mdIndent(lines[i]) >= 4); i++ {
+				code = append(code, mdDedent(lines[i], 4))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			r.code(code, "")
+		case indent < 4 && mdFence(trimmed) != "":
+			flush()
+			fence := mdFence(trimmed)
+			lang, _, _ := strings.Cut(strings.TrimSpace(trimmed[len(fence):]), " ")
+			var code []string
+			for i++; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, mdDedent(lines[i], indent))
+			}
+			r.code(code, lang)
+		case indent < 4 && len(para) > 0 && mdSetext(trimmed) > 0:
+			level := mdSetext(trimmed)
+			text := strings.Join(para, "\n")
+			para = para[:0]
+			r.heading(level, text)
+			i++
+		case indent < 4 && mdATX(trimmed) > 0:
+			flush()
+			level := mdATX(trimmed)
+			text := strings.TrimSpace(trimmed[level:])
+			if t := strings.TrimRight(text, "#"); t == "" || strings.HasSuffix(t, " ") {
+				text = strings.TrimSpace(t)
+			}
+			r.heading(level, text)
+			i++
+		case indent < 4 && mdBreak(trimmed):
+			flush()
+			r.out.WriteString("<hr>\n")
+			i++
+		case indent < 4 && trimmed[0] == '>' && r.depth < mdMaxDepth:
+			flush()
+			var inner []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimLeft(lines[i], " ")
+				if mdIndent(lines[i]) >= 4 || !strings.HasPrefix(t, ">") {
+					break
+				}
+				t = t[1:]
+				inner = append(inner, strings.TrimPrefix(t, " "))
+			}
+			sub := mdRenderer{cfg: r.cfg, depth: r.depth + 1}
+			sub.blocks(inner)
+			r.out.WriteString("<blockquote>\n" + sub.out.String() + "</blockquote>\n")
+		case indent < 4 && r.depth < mdMaxDepth && mdListStart(line):
+			flush()
+			i = r.list(lines, i)
+		default:
+			para = append(para, trimmed)
+			i++
+		}
+	}
+	flush()
+}
+
+func (r *mdRenderer) heading(level int, text string) {
+	level = min(level+r.cfg.headingShift, 6)
+	tag := "h" + strconv.Itoa(level)
+	r.out.WriteString("<" + tag + ">" + r.inline(text) + "</" + tag + ">\n")
+}
+
+func (r *mdRenderer) code(lines []string, lang string) {
+	r.out.WriteString("<pre><code")
+	if lang != "" {
+		r.out.WriteString(` class="language-` + htmlEscape(lang) + `"`)
+	}
+	r.out.WriteByte('>')
+	for _, l := range lines {
+		r.out.WriteString(htmlEscape(l))
+		r.out.WriteByte('\n')
+	}
+	r.out.WriteString("</code></pre>\n")
+}
+
+// mdMarker is a parsed list item marker.
+type mdMarker struct {
+	ordered bool
+	delim   byte // '-', '*', '+' for bullets; '.' or ')' for ordered
+	start   int
+	width   int // columns from the line start to the item content
+}
+
+func (m mdMarker) sameList(o mdMarker) bool {
+	return m.ordered == o.ordered && m.delim == o.delim
+}
+
+// mdParseMarker parses a list marker at the start of line.
+func mdParseMarker(line string) (mdMarker, bool) {
+	indent := mdIndent(line)
+	if indent >= 4 {
+		return mdMarker{}, false
+	}
+	s := line[indent:]
+	var m mdMarker
+	n := 0
+	switch {
+	case s != "" && (s[0] == '-' || s[0] == '*' || s[0] == '+'):
+		m.delim, n = s[0], 1
+	default:
+		for n < len(s) && n < 9 && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n == 0 || n >= len(s) || (s[n] != '.' && s[n] != ')') {
+			return mdMarker{}, false
+		}
+		m.ordered, m.delim = true, s[n]
+		m.start, _ = strconv.Atoi(s[:n])
+		n++
+	}
+	rest := s[n:]
+	if rest != "" && rest[0] != ' ' {
+		return mdMarker{}, false
+	}
+	spaces := len(rest) - len(strings.TrimLeft(rest, " "))
+	if spaces == 0 || spaces > 4 || strings.TrimSpace(rest) == "" {
+		spaces = 1
+	}
+	m.width = indent + n + spaces
+	return m, true
+}
+
+func mdListStart(line string) bool {
+	_, ok := mdParseMarker(line)
+	return ok
+}
+
+// list renders the list starting at lines[i] and returns the index of
+// the first line after it.
+func (r *mdRenderer) list(lines []string, i int) int {
+	first, _ := mdParseMarker(lines[i])
+	var items [][]string
+	loose, blank := false, false
+	width := first.width
+	for i < len(lines) {
+		line := lines[i]
+		if m, ok := mdParseMarker(line); ok && mdIndent(line) < width && !mdBreak(strings.TrimSpace(line)) {
+			if !m.sameList(first) {
+				break
+			}
+			if blank && len(items) > 0 {
+				loose = true
+			}
+			blank = false
+			width = m.width
+			content := ""
+			if len(line) > width {
+				content = line[width:]
+			}
+			items = append(items, []string{content})
+			i++
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			items[len(items)-1] = append(items[len(items)-1], "")
+			i++
+			continue
+		}
+		if mdIndent(line) >= width {
+			if blank {
+				loose = true
+			}
+			blank = false
+			items[len(items)-1] = append(items[len(items)-1], mdDedent(line, width))
+			i++
+			continue
+		}
+		// A lazy continuation of the item's paragraph.
+		cur := items[len(items)-1]
+		t := strings.TrimLeft(line, " ")
+		if blank || cur[len(cur)-1] == "" || mdFence(t) != "" || mdATX(t) > 0 || mdBreak(t) || t[0] == '>' {
+			break
+		}
+		items[len(items)-1] = append(cur, t)
+		i++
+	}
+	// Trailing blank lines belong after the list, not in its last item.
+	for len(items) > 0 {
+		last := items[len(items)-1]
+		if len(last) == 0 || last[len(last)-1] != "" {
+			break
+		}
+		items[len(items)-1] = last[:len(last)-1]
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	r.out.WriteString("<" + tag)
+	if first.ordered && first.start != 1 {
+		r.out.WriteString(` start="` + strconv.Itoa(first.start) + `"`)
+	}
+	r.out.WriteString(">\n")
+	for _, item := range items {
+		sub := mdRenderer{cfg: r.cfg, tight: !loose, depth: r.depth + 1}
+		sub.blocks(item)
+		r.out.WriteString("<li>" + strings.TrimSuffix(sub.out.String(), "\n") + "</li>\n")
+	}
+	r.out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// inline renders the span-level Markdown of s.
+func (r *mdRenderer) inline(s string) string {
+	var b strings.Builder
+	// unclosed records delimiter runs (char, length) that found no
+	// closer: a later opener of the same run can't find one either.
+	var unclosed [256][4]bool
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(s) && mdPunct(s[i+1]):
+			b.WriteString(htmlEscape(s[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			n := mdRun(s, i, '`')
+			end := mdFindRun(s, i+n, '`', n)
+			if end < 0 {
+				b.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + htmlEscape(code) + "</code>")
+			i = end + n
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			text, dest, title, end, ok := mdParseLink(s, i+1)
+			if !ok {
+				b.WriteByte('!')
+				i++
+				continue
+			}
+			alt := mdPlain(text)
+			if r.cfg.noImages || !safePolicy.allowsURL(dest) {
+				b.WriteString(htmlEscape(alt))
+			} else {
+				b.WriteString(`<img src="` + htmlEscape(dest) + `" alt="` + htmlEscape(alt) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + htmlEscape(title) + `"`)
+				}
+				b.WriteByte('>')
+			}
+			i = end
+		case c == '[':
+			text, dest, title, end, ok := mdParseLink(s, i)
+			if !ok {
+				b.WriteByte('[')
+				i++
+				continue
+			}
+			inner := r.inline(text)
+			if safePolicy.allowsURL(dest) {
+				b.WriteString(`<a href="` + htmlEscape(dest) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + htmlEscape(title) + `"`)
+				}
+				b.WriteString(">" + inner + "</a>")
+			} else {
+				b.WriteString(inner)
+			}
+			i = end
+		case c == '<':
+			if end := strings.IndexByte(s[i:min(len(s), i+mdMaxLinkText)], '>'); end > 0 && mdAutolink(s[i+1:i+end]) {
+				u := s[i+1 : i+end]
+				b.WriteString(`<a href="` + htmlEscape(u) + `">` + htmlEscape(strings.TrimPrefix(u, "mailto:")) + "</a>")
+				i += end + 1
+				continue
+			}
+			b.WriteString("&lt;")
+			i++
+		case c == '*' || c == '_' || c == '~':
+			n := mdRun(s, i, c)
+			if n < 4 && !unclosed[c][n] {
+				out, end, ok := r.emphasis(s, i)
+				if ok {
+					b.WriteString(out)
+					i = end
+					continue
+				}
+				if end < 0 {
+					unclosed[c][n] = true
+				}
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+		case c == ' ':
+			n := mdRun(s, i, ' ')
+			if i+n < len(s) && s[i+n] == '\n' {
+				if n >= 2 {
+					b.WriteString("<br>")
+				}
+				i += n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+		default:
+			j := i + 1
+			for j < len(s) && !strings.ContainsRune("\\`![<*_~ ", rune(s[j])) {
+				j++
+			}
+			b.WriteString(htmlEscape(s[i:j]))
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// emphasis renders the *em*, **strong**, ***both*** or ~~del~~ span
+// opening at s[i], if it closes. When the run can't open a span end is
+// 0; when nothing up to the end of s closes it, -1.
+func (r *mdRenderer) emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	n := mdRun(s, i, c)
+	if (c == '~' && n != 2) || n > 3 || i+n >= len(s) || s[i+n] == ' ' || s[i+n] == '\n' {
+		return "", 0, false
+	}
+	if c == '_' && i > 0 && mdWord(s[i-1]) {
+		return "", 0, false // snake_case, not emphasis
+	}
+	// Past here, a failed search scanned to the end: end is -1.
+	for j := i + n; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			m := mdRun(s, j, '`')
+			if end := mdFindRun(s, j+m, '`', m); end >= 0 {
+				j = end + m - 1
+			}
+			continue
+		case c:
+		default:
+			continue
+		}
+		m := mdRun(s, j, c)
+		// A longer run closes too when it ends the span ("**a *b***"):
+		// its last n delimiters are ours, the rest close an inner span.
+		closes := m == n || (m > n && (j+m == len(s) || !mdWord(s[j+m])))
+		if !closes || s[j-1] == ' ' || s[j-1] == '\n' || (c == '_' && j+m < len(s) && mdWord(s[j+m])) {
+			j += m - 1
+			continue
+		}
+		j += m - n
+		inner := r.inline(s[i+n : j])
+		switch {
+		case c == '~':
+			inner = "<del>" + inner + "</del>"
+		case n == 1:
+			inner = "<em>" + inner + "</em>"
+		case n == 2:
+			inner = "<strong>" + inner + "</strong>"
+		default:
+			inner = "<em><strong>" + inner + "</strong></em>"
+		}
+		return inner, j + n, true
+	}
+	return "", -1, false
+}
+
+// mdParseLink parses `[text](dest "title")` with s[i] == '['.
+func mdParseLink(s string, i int) (text, dest, title string, end int, ok bool) {
+	depth := 0
+	j := i
+	for limit := min(len(s), i+mdMaxLinkText); j < limit; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if depth != 0 || j >= len(s)-1 || s[j+1] != '(' {
+		return "", "", "", 0, false
+	}
+	text = s[i+1 : j]
+	k := j + 2
+	for k < len(s) && s[k] == ' ' {
+		k++
+	}
+	if k < len(s) && s[k] == '<' {
+		e := strings.IndexByte(s[k:min(len(s), k+mdMaxLinkText)], '>')
+		if e < 0 {
+			return "", "", "", 0, false
+		}
+		dest, k = s[k+1:k+e], k+e+1
+	} else {
+		start, parens := k, 0
+		for limit := min(len(s), k+mdMaxLinkText); k < limit && s[k] != ' ' && s[k] != '\n'; k++ {
+			if s[k] == '(' {
+				parens++
+			} else if s[k] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:k]
+	}
+	for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+		k++
+	}
+	if k < len(s) && (s[k] == '"' || s[k] == '\'') {
+		q := s[k]
+		e := strings.IndexByte(s[k+1:min(len(s), k+1+mdMaxLinkText)], q)
+		if e < 0 {
+			return "", "", "", 0, false
+		}
+		title, k = s[k+1:k+1+e], k+e+2
+		for k < len(s) && s[k] == ' ' {
+			k++
+		}
+	}
+	if k >= len(s) || s[k] != ')' {
+		return "", "", "", 0, false
+	}
+	return text, dest, title, k + 1, true
+}
+
+func mdAutolink(u string) bool {
+	lower := strings.ToLower(u)
+	return (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "mailto:")) && !strings.ContainsAny(u, " <\n")
+}
+
+// mdPlain strips the Markdown from link text for an image's alt.
+func mdPlain(s string) string {
+	return strings.NewReplacer("*", "", "_", "", "`", "", "~~", "", "\\", "").Replace(s)
+}
+
+func mdFence(t string) string {
+	for _, f := range []string{"```", "~~~"} {
+		if strings.HasPrefix(t, f) {
+			n := mdRun(t, 0, f[0])
+			if f[0] == '`' && strings.Contains(t[n:], "`") {
+				return "" // an inline code span, not a fence
+			}
+			return t[:n]
+		}
+	}
+	return ""
+}
+
+func mdATX(t string) int {
+	n := mdRun(t, 0, '#')
+	if n == 0 || n > 6 || (n < len(t) && t[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+func mdSetext(t string) int {
+	t = strings.TrimRight(t, " ")
+	switch {
+	case t != "" && strings.Trim(t, "=") == "":
+		return 1
+	case t != "" && strings.Trim(t, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+func mdBreak(t string) bool {
+	if t == "" || (t[0] != '-' && t[0] != '*' && t[0] != '_') {
+		return false
+	}
+	n := 0
+	for i := 0; i < len(t); i++ {
+		switch t[i] {
+		case t[0]:
+			n++
+		case ' ':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+func mdIndent(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+
+// mdDedent removes up to n leading spaces.
+func mdDedent(line string, n int) string {
+	return line[min(n, mdIndent(line)):]
+}
+
+func mdRun(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// mdFindRun returns the index of the next run of exactly n c's at or
+// after i, or -1.
+func mdFindRun(s string, i int, c byte, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], c)
+		if j < 0 {
+			return -1
+		}
+		j += i
+		m := mdRun(s, j, c)
+		if m == n {
+			return j
+		}
+		i = j + m
+	}
+	return -1
+}
+
+func mdPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func mdWord(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
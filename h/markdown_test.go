@@ -0,0 +1,82 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown_rendersCommonMarkBlocks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, src, want string
+	}{
+		{"heading and paragraph", "# Title #\n\nHello  \nworld",
+			"<h1>Title</h1>\n<p>Hello<br>\nworld</p>\n"},
+		{"setext heading and rule", "Setext\n---\n\n***",
+			"<h2>Setext</h2>\n<hr>\n"},
+		{"tight nested list", "- one\n- two\n  - nested\n- three",
+			"<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n<li>three</li>\n</ul>\n"},
+		{"loose ordered list", "3. first\n\n4. second",
+			"<ol start=\"3\">\n<li><p>first</p></li>\n<li><p>second</p></li>\n</ol>\n"},
+		{"block quote", "> quoted\n> *text*",
+			"<blockquote>\n<p>quoted\n<em>text</em></p>\n</blockquote>\n"},
+		{"fenced code", "```go\nif a < b {}\n```",
+			"<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>\n"},
+		{"indented code", "    x := 1",
+			"<pre><code>x := 1\n</code></pre>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, r(t, h.Markdown(tt.src)))
+		})
+	}
+}
+
+func TestMarkdown_rendersInlineSpans(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, src, want string
+	}{
+		{"emphasis", "*em* _em_ **strong** ~~del~~", "<em>em</em> <em>em</em> <strong>strong</strong> <del>del</del>"},
+		{"nested emphasis", "**bold *and em***", "<strong>bold <em>and em</em></strong>"},
+		{"snake case stays literal", "a snake_case_name", "a snake_case_name"},
+		{"code span", "run `go test` now", "run <code>go test</code> now"},
+		{"escapes", `\*not em\*`, "*not em*"},
+		{"link with title", `[docs](https://go.dev/doc "Go")`, `<a href="https://go.dev/doc" title="Go">docs</a>`},
+		{"image", "![a cat](/cat.png)", `<img src="/cat.png" alt="a cat">`},
+		{"autolink", "<https://go.dev>", `<a href="https://go.dev">https://go.dev</a>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, "<p>"+tt.want+"</p>\n", r(t, h.Markdown(tt.src)))
+		})
+	}
+}
+
+func TestMarkdown_sanitizesUntrustedInput(t *testing.T) {
+	t.Parallel()
+	got := r(t, h.Markdown("<script>alert(1)</script> [x](javascript:alert(1)) "+
+		`![y](data:text/html,hi) [z](" onclick="a)`))
+	assert.NotContains(t, got, "<script")
+	assert.NotContains(t, got, "javascript:")
+	assert.NotContains(t, got, "data:")
+	assert.NotContains(t, got, `" onclick`)
+	assert.Contains(t, got, "&lt;script&gt;alert(1)&lt;/script&gt; x y")
+}
+
+func TestMarkdown_options(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "<p>a cat</p>\n", r(t, h.Markdown("![a cat](/cat.png)", h.MarkdownNoImages())))
+	assert.Equal(t, "<h3>Title</h3>\n<h6>Deep</h6>\n",
+		r(t, h.Markdown("## Title\n##### Deep", h.MarkdownHeadingShift(1))))
+}
+
+func TestMarkdown_emptySourceRendersNothing(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, h.Markdown(""))
+	assert.Nil(t, h.Markdown("\n\n"))
+}
@@ -0,0 +1,192 @@
+package h
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// RenderOption configures [Render].
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	indent string
+}
+
+// Pretty makes [Render] put each block-level element on its own line,
+// indented two spaces per level — for reading a page or a patch while
+// debugging, not for production. Inline elements and text stay on
+// their parent's line, and pre, textarea, script and style content is
+// kept verbatim, so the page lays out as the compact form would.
+func Pretty() RenderOption {
+	return func(c *renderConfig) { c.indent = "  " }
+}
+
+// Render writes n to w, shaped by opts. With no options it is
+// n.Render(w); nil renders nothing.
+//
+//	h.Render(os.Stdout, page, h.Pretty())
+func Render(w io.Writer, n H, opts ...RenderOption) error {
+	if n == nil {
+		return nil
+	}
+	var cfg renderConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.indent == "" {
+		return n.Render(w)
+	}
+	var buf bytes.Buffer
+	if err := n.Render(&buf); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, indentHTML(buf.String(), cfg.indent))
+	return err
+}
+
+// prettyBlock are the elements Pretty starts on a new line.
+var prettyBlock = map[string]bool{
+	"address": true, "article": true, "aside": true, "base": true, "blockquote": true,
+	"body": true, "caption": true, "colgroup": true, "dd": true, "details": true,
+	"dialog": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"head": true, "header": true, "hgroup": true, "hr": true, "html": true,
+	"legend": true, "li": true, "link": true, "main": true, "menu": true,
+	"meta": true, "nav": true, "noscript": true, "ol": true, "optgroup": true,
+	"option": true, "p": true, "pre": true, "script": true, "search": true,
+	"section": true, "select": true, "style": true, "summary": true,
+	"table": true, "tbody": true, "td": true, "template": true, "tfoot": true,
+	"th": true, "thead": true, "title": true, "tr": true, "ul": true,
+}
+
+// prettyVerbatim are the elements whose content Pretty copies as is.
+var prettyVerbatim = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true}
+
+// indentHTML re-lays rendered HTML with block-level elements on their
+// own lines. It trusts its input to be what a render produced: tags
+// well formed, attribute values quoted.
+func indentHTML(s, indent string) string {
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4)
+	// broke holds, per open block element, whether a block child went
+	// on its own line — then the end tag does too.
+	var broke []bool
+	lastBlock := false
+	newline := func() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+			b.WriteString(strings.Repeat(indent, len(broke)))
+		}
+	}
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			j := strings.IndexByte(s[i:], '<')
+			if j < 0 {
+				j = len(s) - i
+			}
+			text := s[i : i+j]
+			i += j
+			// Whitespace beside a block tag is layout-neutral; drop it
+			// so it doesn't fight the indentation.
+			if strings.TrimSpace(text) == "" && (lastBlock || i < len(s) && prettyBlock[tagName(s[i:])]) {
+				continue
+			}
+			b.WriteString(text)
+			lastBlock = false
+			continue
+		}
+		end := tagEnd(s, i)
+		tag := s[i:end]
+		i = end
+		name := tagName(tag)
+		switch {
+		case strings.HasPrefix(tag, "<!"):
+			newline()
+			b.WriteString(tag)
+			lastBlock = true
+		case strings.HasPrefix(tag, "</"):
+			if !prettyBlock[name] || len(broke) == 0 {
+				b.WriteString(tag)
+				lastBlock = false
+				continue
+			}
+			hadBlock := broke[len(broke)-1]
+			broke = broke[:len(broke)-1]
+			if hadBlock {
+				newline()
+			}
+			b.WriteString(tag)
+			lastBlock = true
+		default:
+			block := prettyBlock[name]
+			if block {
+				if len(broke) > 0 {
+					broke[len(broke)-1] = true
+				}
+				newline()
+			}
+			b.WriteString(tag)
+			lastBlock = block
+			if htmlVoid[name] || strings.HasSuffix(tag, "/>") {
+				continue
+			}
+			if prettyVerbatim[name] {
+				// Copy the content up to the end tag untouched.
+				stop := strings.Index(s[i:], "</"+name)
+				if stop < 0 {
+					stop = len(s) - i
+				}
+				b.WriteString(s[i : i+stop])
+				i += stop
+			}
+			if block {
+				broke = append(broke, false)
+			}
+		}
+	}
+	return b.String()
+}
+
+// tagEnd returns the index just past the tag starting at s[i], skipping
+// quoted attribute values and whole comments.
+func tagEnd(s string, i int) int {
+	if strings.HasPrefix(s[i:], "<!--") {
+		if e := strings.Index(s[i+4:], "-->"); e >= 0 {
+			return i + 4 + e + 3
+		}
+		return len(s)
+	}
+	var quote byte
+	for j := i + 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// tagName returns the lower-cased element name of the tag at the start
+// of s, "" if s doesn't start with one.
+func tagName(s string) string {
+	i := 1
+	if strings.HasPrefix(s, "</") {
+		i = 2
+	}
+	if i >= len(s) || !isASCIILetter(s[i]) {
+		return ""
+	}
+	j := i
+	for j < len(s) && !isTagSpace(s[j]) && s[j] != '>' && s[j] != '/' {
+		j++
+	}
+	return strings.ToLower(s[i:j])
+}
@@ -0,0 +1,80 @@
+package h_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+)
+
+func pretty(t *testing.T, n h.H) string {
+	t.Helper()
+	var b strings.Builder
+	if err := h.Render(&b, n, h.Pretty()); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestRender_withoutOptionsMatchesCompactRender(t *testing.T) {
+	t.Parallel()
+	n := h.Div(h.ID("c"), h.P(h.T("hi")))
+	var b strings.Builder
+	assert.NoError(t, h.Render(&b, n))
+	assert.Equal(t, r(t, n), b.String())
+	assert.NoError(t, h.Render(&b, nil, h.Pretty()))
+}
+
+func TestPretty_indentsBlockElements(t *testing.T) {
+	t.Parallel()
+	got := pretty(t, h.Div(h.ID("c"),
+		h.H1(h.T("Counter")),
+		h.Ul(h.Li(h.T("one")), h.Li(h.T("two"))),
+		h.Hr(),
+	))
+	assert.Equal(t, `<div id="c">
+  <h1>Counter</h1>
+  <ul>
+    <li>one</li>
+    <li>two</li>
+  </ul>
+  <hr>
+</div>`, got)
+}
+
+func TestPretty_keepsInlineContentAndVerbatimBlocksIntact(t *testing.T) {
+	t.Parallel()
+	got := pretty(t, h.Section(
+		h.P(h.T("Count: "), h.Span(h.T("3")), h.T(" "), h.A(h.Href("/x"), h.T("more"))),
+		h.Pre(h.T("a\n  <div>b</div>")),
+		h.Raw("<pre>\n<div>raw</div>\n</pre>"),
+	))
+	assert.Equal(t, `<section>
+  <p>Count: <span>3</span> <a href="/x">more</a></p>
+  <pre>a
+  &lt;div&gt;b&lt;/div&gt;</pre>
+  <pre>
+<div>raw</div>
+</pre>
+</section>`, got)
+}
+
+func TestPretty_putsTheDoctypeOnItsOwnLine(t *testing.T) {
+	t.Parallel()
+	got := pretty(t, h.Fragment(h.Raw("<!DOCTYPE html>"), h.HTML(h.Body(h.Div(h.T("x"))))))
+	assert.Equal(t, "<!DOCTYPE html>\n<html>\n  <body>\n    <div>x</div>\n  </body>\n</html>", got)
+}
+
+type failingNode struct{}
+
+func (failingNode) Render(io.Writer) error { return errors.New("boom") }
+
+func TestRender_prettyReturnsTheRenderError(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	assert.EqualError(t, h.Render(&b, failingNode{}, h.Pretty()), "boom")
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,342 @@
+package h
+
+import (
+	"html"
+	"slices"
+	"strings"
+)
+
+// Policy is the allowlist [Sanitize] keeps untrusted HTML to. Anything
+// it doesn't name is removed: unknown elements are unwrapped to their
+// text, unknown attributes dropped. The zero Policy keeps text only.
+//
+// Some markup is removed whatever the policy says: script, style,
+// iframe, object, embed, svg, textarea, select and the like go with
+// their content, and event handlers (on*) and data-* attributes, which
+// Datastar would run, are always dropped.
+type Policy struct {
+	// Elements maps each kept element (lower case) to the attributes
+	// it may carry besides Global.
+	Elements map[string][]string
+	// Global lists attributes allowed on every kept element. An entry
+	// ending in "*" matches by prefix, e.g. "aria-*".
+	Global []string
+	// URLSchemes lists the schemes URL attributes (href, src, srcset,
+	// cite, poster, ...) may use, e.g. "https". Relative URLs are always
+	// allowed; any other value drops the attribute.
+	URLSchemes []string
+}
+
+// SafePolicy returns the built-in policy [ParseHTML] uses, as a fresh
+// copy to extend for a particular feed:
+//
+//	p := h.SafePolicy()
+//	p.Elements["video"] = []string{"src", "controls", "poster"}
+//	h.Sanitize(post.Body, p)
+//
+// It keeps formatting, list, table and sectioning elements; class,
+// title, lang, dir, role and aria-* on each; href on links, src, alt
+// and size on images; and http, https and mailto URLs. id and style
+// are left out: untrusted ids can collide with the ids patches target.
+func SafePolicy() Policy {
+	p := Policy{
+		Elements:   make(map[string][]string, len(safePolicy.Elements)),
+		Global:     append([]string(nil), safePolicy.Global...),
+		URLSchemes: append([]string(nil), safePolicy.URLSchemes...),
+	}
+	for el, attrs := range safePolicy.Elements {
+		p.Elements[el] = append([]string(nil), attrs...)
+	}
+	return p
+}
+
+var safePolicy = Policy{
+	Elements: map[string][]string{
+		"a": {"href"}, "abbr": nil, "article": nil, "aside": nil,
+		"b": nil, "bdi": nil, "bdo": nil, "blockquote": {"cite"},
+		"br": nil, "caption": nil, "cite": nil, "code": nil,
+		"col": {"span"}, "colgroup": {"span"},
+		"dd": nil, "del": {"cite", "datetime"},
+		"details": {"open"}, "dfn": nil, "div": nil, "dl": nil, "dt": nil,
+		"em": nil, "figcaption": nil, "figure": nil, "footer": nil,
+		"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+		"header": nil, "hr": nil, "i": nil,
+		"img": {"src", "alt", "width", "height"},
+		"ins": {"cite", "datetime"}, "kbd": nil, "li": {"value"},
+		"main": nil, "mark": nil, "nav": nil, "ol": {"start", "reversed", "type"},
+		"p": nil, "pre": nil, "q": {"cite"}, "s": nil, "samp": nil,
+		"section": nil, "small": nil, "span": nil, "strong": nil,
+		"sub": nil, "summary": nil, "sup": nil,
+		"table": nil, "tbody": nil, "tfoot": nil, "thead": nil, "tr": nil,
+		"td":   {"colspan", "rowspan", "headers"},
+		"th":   {"colspan", "rowspan", "headers", "scope", "abbr"},
+		"time": {"datetime"}, "u": nil, "ul": nil, "var": nil, "wbr": nil,
+	},
+	Global:     []string{"class", "title", "lang", "dir", "role", "aria-*"},
+	URLSchemes: []string{"http", "https", "mailto"},
+}
+
+// Sanitize parses untrusted as an HTML fragment and keeps only what
+// policy allows — for comments, posts and other user-generated rich
+// text that would otherwise need a sanitizer wrapped around [Raw]:
+//
+//	h.Div(h.Class("comment"), h.Sanitize(c.BodyHTML, h.SafePolicy()))
+//
+// Text is re-escaped, entities are decoded before attribute values are
+// checked, and unclosed elements are closed, so the fragment can't
+// swallow the page around it.
+func Sanitize(untrusted string, policy Policy) H {
+	out := sanitizeHTML(untrusted, &policy)
+	if out == "" {
+		return nil
+	}
+	return &rawNode{s: out}
+}
+
+// htmlVoid are the elements without content or a closing tag.
+var htmlVoid = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// htmlMaxDepth bounds element nesting; deeper tags are unwrapped to
+// their text, which keeps closing-tag matching linear.
+const htmlMaxDepth = 64
+
+// htmlDropped are elements removed together with their content, under
+// any policy.
+var htmlDropped = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true,
+	"svg": true, "math": true, "title": true, "head": true, "xmp": true,
+	"noembed": true, "noframes": true, "plaintext": true, "frameset": true,
+}
+
+func sanitizeHTML(s string, p *Policy) string {
+	var b strings.Builder
+	var open []string
+	for i := 0; i < len(s); {
+		lt := strings.IndexByte(s[i:], '<')
+		if lt < 0 {
+			b.WriteString(htmlEscape(html.UnescapeString(s[i:])))
+			break
+		}
+		b.WriteString(htmlEscape(html.UnescapeString(s[i : i+lt])))
+		i += lt
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return closeOpen(&b, open)
+			}
+			i += 4 + end + 3
+			continue
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return closeOpen(&b, open)
+			}
+			i += end + 1
+			continue
+		}
+		closing := strings.HasPrefix(rest, "</")
+		nameAt := 1
+		if closing {
+			nameAt = 2
+		}
+		if nameAt >= len(rest) || !isASCIILetter(rest[nameAt]) {
+			b.WriteString("&lt;")
+			i++
+			continue
+		}
+		name, attrs, n := parseTag(rest[nameAt:])
+		i += nameAt + n
+		extra, allowed := p.Elements[name]
+		void := htmlVoid[name]
+		switch {
+		case closing:
+			for k := len(open) - 1; k >= 0; k-- {
+				if open[k] == name {
+					for j := len(open) - 1; j >= k; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:k]
+					break
+				}
+			}
+		case htmlDropped[name]:
+			if void {
+				break
+			}
+			end := indexFold(s[i:], "</"+name)
+			if end < 0 {
+				return closeOpen(&b, open)
+			}
+			i += end
+			if gt := strings.IndexByte(s[i:], '>'); gt >= 0 {
+				i += gt + 1
+			} else {
+				i = len(s)
+			}
+		case allowed && (void || len(open) < htmlMaxDepth):
+			b.WriteString("<" + name)
+			p.writeAttrs(&b, extra, attrs)
+			b.WriteByte('>')
+			if !void {
+				open = append(open, name)
+			}
+		}
+	}
+	return closeOpen(&b, open)
+}
+
+// closeOpen closes the elements still open, innermost first, and
+// returns the output.
+func closeOpen(b *strings.Builder, open []string) string {
+	for j := len(open) - 1; j >= 0; j-- {
+		b.WriteString("</" + open[j] + ">")
+	}
+	return b.String()
+}
+
+type htmlAttr struct{ name, value string }
+
+// parseTag reads a tag from just after "<" or "</": its lower-cased
+// name, its attributes with values entity-decoded, and how many bytes
+// up to and including the closing ">" it spans.
+func parseTag(s string) (name string, attrs []htmlAttr, n int) {
+	i := 0
+	for i < len(s) && !isTagSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	name = strings.ToLower(s[:i])
+	for i < len(s) {
+		for i < len(s) && (isTagSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			return name, attrs, i + 1
+		}
+		start := i
+		for i < len(s) && !isTagSpace(s[i]) && s[i] != '>' && s[i] != '/' && s[i] != '=' {
+			i++
+		}
+		if i == start { // a stray "=" with no name
+			i++
+			continue
+		}
+		a := htmlAttr{name: strings.ToLower(s[start:i])}
+		for i < len(s) && isTagSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isTagSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				q := s[i]
+				end := strings.IndexByte(s[i+1:], q)
+				if end < 0 {
+					return name, attrs, len(s)
+				}
+				a.value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				vs := i
+				for i < len(s) && !isTagSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				a.value = s[vs:i]
+			}
+			a.value = html.UnescapeString(a.value)
+		}
+		attrs = append(attrs, a)
+	}
+	return name, attrs, len(s)
+}
+
+// writeAttrs writes the attributes of a kept element that p allows,
+// dropping URLs with a scheme it doesn't list.
+func (p *Policy) writeAttrs(b *strings.Builder, extra []string, attrs []htmlAttr) {
+	var seen []string
+	for _, a := range attrs {
+		if strings.HasPrefix(a.name, "on") || strings.HasPrefix(a.name, "data-") ||
+			slices.Contains(seen, a.name) || !(attrAllowed(extra, a.name) || attrAllowed(p.Global, a.name)) {
+			continue
+		}
+		if htmlURLAttrs[a.name] && !p.allowsURL(a.value) || a.name == "srcset" && !p.allowsSrcset(a.value) {
+			continue
+		}
+		seen = append(seen, a.name)
+		b.WriteString(" " + a.name + `="` + htmlEscape(a.value) + `"`)
+	}
+}
+
+// attrAllowed reports whether name is in list, where an entry ending in
+// "*" matches by prefix.
+func attrAllowed(list []string, name string) bool {
+	for _, n := range list {
+		if n == name || strings.HasSuffix(n, "*") && strings.HasPrefix(name, n[:len(n)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlURLAttrs are the attributes holding a URL, checked against the
+// policy's schemes.
+var htmlURLAttrs = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true, "formaction": true,
+	"poster": true, "background": true, "longdesc": true, "ping": true,
+	"data": true, "xlink:href": true, "manifest": true, "codebase": true,
+}
+
+// allowsSrcset reports whether every candidate URL in a srcset passes
+// allowsURL.
+func (p *Policy) allowsSrcset(v string) bool {
+	for cand := range strings.SplitSeq(v, ",") {
+		if u, _, _ := strings.Cut(strings.TrimSpace(cand), " "); !p.allowsURL(u) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsURL reports whether u is relative or uses one of p's schemes.
+func (p *Policy) allowsURL(u string) bool {
+	u = strings.TrimLeft(u, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\t\n\v\f\r ")
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	for _, scheme := range p.URLSchemes {
+		if strings.EqualFold(u[:colon], scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTagSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isASCIILetter(c byte) bool {
+	return c|0x20 >= 'a' && c|0x20 <= 'z'
+}
+
+// indexFold is strings.Index ignoring ASCII case in s.
+func indexFold(s, sub string) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
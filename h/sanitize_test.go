@@ -0,0 +1,47 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize_safePolicyMatchesParseHTML(t *testing.T) {
+	t.Parallel()
+	src := `<p class="x" onclick="y()">a <a href="https://go.dev">b</a><script>c</script></p>`
+	assert.Equal(t, r(t, h.ParseHTML(src)), r(t, h.Sanitize(src, h.SafePolicy())))
+}
+
+func TestSanitize_extendedPolicyKeepsMore(t *testing.T) {
+	t.Parallel()
+	p := h.SafePolicy()
+	p.Elements["video"] = []string{"src", "controls", "poster"}
+	p.Global = append(p.Global, "id")
+	p.URLSchemes = []string{"https"}
+
+	got := r(t, h.Sanitize(`<video id="v" src="https://cdn.test/a.mp4" controls>x</video>`+
+		`<video poster="javascript:x()">y</video><a href="http://plain.test">p</a>`, p))
+	assert.Equal(t, `<video id="v" src="https://cdn.test/a.mp4" controls="">x</video>`+
+		`<video>y</video><a>p</a>`, got)
+
+	// Extending a copy leaves the built-in policy alone.
+	assert.Equal(t, "x", r(t, h.Sanitize(`<video>x</video>`, h.SafePolicy())))
+}
+
+func TestSanitize_zeroPolicyKeepsTextOnly(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "Hello &lt;world&gt;",
+		r(t, h.Sanitize(`<p><b>Hello</b> &lt;world&gt;</p><style>p{}</style>`, h.Policy{})))
+}
+
+func TestSanitize_neverKeepsScriptingWhateverThePolicy(t *testing.T) {
+	t.Parallel()
+	p := h.Policy{
+		Elements: map[string][]string{"div": {"onclick", "data-on-click"}, "script": nil, "embed": nil, "img": nil},
+		Global:   []string{"*"},
+	}
+	got := r(t, h.Sanitize(`<div onclick="a()" data-on-click="@post('/x')" onmouseover="b()" class="c">`+
+		`<script>evil()</script><embed src="/x.swf"><img srcset="/a.png 1x, javascript:x() 2x">ok</div>`, p))
+	assert.Equal(t, `<div class="c"><img>ok</div>`, got)
+}
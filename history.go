@@ -0,0 +1,124 @@
+package via
+
+import (
+	"html/template"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// PushURL adds a browser history entry for path and query at the next
+// flush — the address bar changes, the page doesn't reload — so a
+// filter, a selected tab, or an open detail pane leaves a link that
+// can be shared and reloaded:
+//
+//	func (p *List) Filter(ctx *via.Ctx) error {
+//	    p.Status = ctx.EventArg("status") // `query:"status"`
+//	    ctx.PushURL("", url.Values{"status": {p.Status}})
+//	    return nil
+//	}
+//
+// The view re-renders with the action, so plain fields changed alongside
+// show. path "" keeps the current path; otherwise it must be a
+// same-origin absolute path as the app routes it — PushURL adds the
+// [WithBasePath] prefix — or it is dropped and logged. Going back or
+// forward to the entry re-binds the page's `query:` fields from its URL
+// and runs the [Ctx.OnPopState] handler, if any.
+func (ctx *Ctx) PushURL(path string, query url.Values) {
+	if ctx == nil || ctx.queue == nil {
+		return
+	}
+	if path != "" && (!strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//")) {
+		if ctx.app != nil {
+			ctx.app.logErr(ctx, "PushURL: rejected non-local path %q", path)
+		}
+		return
+	}
+	target := "location.pathname"
+	if path != "" {
+		if ctx.app != nil {
+			path = ctx.app.cfg.basePath + path
+		}
+		target = jsString(path)
+	}
+	if qs := query.Encode(); qs != "" {
+		target += "+" + jsString("?"+qs)
+	}
+	ctx.watchPopState()
+	enqueueScript(ctx, "history.pushState(null,'',"+target+")")
+	ctx.markStateDirty()
+}
+
+// OnPopState sets fn to run when the user goes back or forward between
+// the tab's history entries, with the URL navigated to (its path without
+// the base path). It runs like an action — behind the route's group
+// middleware, signed and audited as "popstate" — after the page's
+// `query:` fields are re-bound from that URL, so most handlers only load
+// what the fields now select. Register it from OnInit; a later call
+// replaces fn.
+func (ctx *Ctx) OnPopState(fn func(ctx *Ctx, u *url.URL)) {
+	if ctx == nil {
+		return
+	}
+	ctx.mu.Lock()
+	ctx.popFn = fn
+	ctx.mu.Unlock()
+	ctx.watchPopState()
+}
+
+// watchPopState installs, once per tab, the browser listener reporting
+// history navigation to /_sse/popstate. It is a Datastar window listener
+// on a hidden element, so the beacon can carry the tab's popstate
+// signature when the app signs actions.
+func (ctx *Ctx) watchPopState() {
+	if ctx.app == nil {
+		return
+	}
+	ctx.popOnce.Do(func() {
+		expr := `navigator.sendBeacon('` + template.JSEscapeString(ctx.app.cfg.basePath) + `/_sse/popstate','` +
+			template.JSEscapeString(ctx.id) + ` '+location.pathname+location.search`
+		if ctx.app.signer.covers(popStateAction) {
+			expr += `+' '+$` + sigSignalKey + `.` + popStateAction
+		}
+		ctx.ExecScript(`(()=>{if(document.getElementById('via-popstate'))return;` +
+			`const d=document.createElement('div');d.id='via-popstate';d.hidden=true;` +
+			`d.setAttribute('data-on:popstate__window',` + jsString(expr+")") + `);document.body.append(d)})()`)
+	})
+}
+
+// popState re-binds the query fields from u and runs the OnPopState
+// handler, serialized with actions and flushed like one.
+func (ctx *Ctx) popState(u *url.URL) {
+	ctx.actionMu.Lock()
+	defer ctx.actionMu.Unlock()
+	ctx.silent.Store(false)
+	defer func() {
+		if ctx.silent.Load() {
+			ctx.discardDirty()
+			return
+		}
+		flushDirty(ctx)
+	}()
+	if ctx.desc != nil && ctx.cmpReflect.IsValid() && len(ctx.desc.querySlots) > 0 {
+		elem := ctx.cmpReflect.Elem()
+		q := u.Query()
+		for _, p := range ctx.desc.querySlots {
+			f := fieldByPath(elem, p.fieldPath)
+			// A param the entry lacks resets the field, as a fresh page
+			// load would leave it.
+			f.Set(reflect.Zero(f.Type()))
+			if raw := q.Get(p.name); raw != "" {
+				decodeScalarString(f, p.kind, raw)
+			}
+		}
+		ctx.markStateDirty()
+	}
+	ctx.mu.Lock()
+	fn := ctx.popFn
+	ctx.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	defer recoverLog(ctx, "OnPopState")
+	fn(ctx, u)
+}
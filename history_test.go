@@ -0,0 +1,172 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inboxPage struct {
+	Status string `query:"status"`
+	Popped via.StateTabStr
+}
+
+func (p *inboxPage) OnInit(ctx *via.Ctx) error {
+	ctx.OnPopState(func(ctx *via.Ctx, u *url.URL) {
+		p.Popped.Write(ctx, u.String())
+	})
+	return nil
+}
+
+func (p *inboxPage) Filter(ctx *via.Ctx) error {
+	p.Status = ctx.EventArg("status")
+	ctx.PushURL("", url.Values{"status": {p.Status}})
+	return nil
+}
+
+func (p *inboxPage) Open(ctx *via.Ctx) error {
+	ctx.PushURL("//evil.example/x", nil)
+	return nil
+}
+
+func (p *inboxPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Text("status=" + p.Status + " popped=" + p.Popped.Read(ctx)))
+}
+
+func TestPushURL_addsAHistoryEntry(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[inboxPage](app, "/inbox")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/inbox?status=open")
+	assert.Contains(t, tc.HTML(), "status=open")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Filter").WithArg("status", "done").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second,
+		`history.pushState(null,'',location.pathname+"?status=done")`, "status=done")
+}
+
+func TestPushURL_rejectsANonLocalPath(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[inboxPage](app, "/inbox")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/inbox")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Open").Fire())
+	require.Equal(t, 200, tc.Action("Filter").WithArg("status", "x").Fire())
+	got := vt.AwaitFrame(t, frames, 2*time.Second, "status=x")
+	assert.NotContains(t, got, "evil.example")
+}
+
+func TestOnPopState_rebindsQueryFieldsAndRunsTheHandler(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[inboxPage](app, "/inbox")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/inbox?status=open")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.PopState("/inbox?status=archived"))
+	vt.AwaitFrame(t, frames, 2*time.Second, "status=archived popped=/inbox?status=archived")
+
+	// An entry without the param resets the field.
+	require.Equal(t, 200, tc.PopState("/inbox"))
+	vt.AwaitFrame(t, frames, 2*time.Second, "status= popped=/inbox")
+}
+
+func TestOnPopState_rejectsAMalformedURL(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[inboxPage](app, "/inbox")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/inbox")
+	assert.Equal(t, 400, tc.PopState("not a url"))
+}
+
+func TestOnPopState_runsBehindGroupMiddleware(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	group := app.Group("/admin")
+	var locked atomic.Bool
+	group.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		if locked.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	via.Mount[inboxPage](group, "/inbox")
+	tc := vt.NewClient(t, server, "/admin/inbox")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	locked.Store(true)
+	assert.Equal(t, http.StatusForbidden, tc.PopState("/admin/inbox?status=archived"))
+	locked.Store(false)
+	require.Equal(t, 200, tc.Action("Filter").WithArg("status", "x").Fire())
+	got := vt.AwaitFrame(t, frames, 2*time.Second, "status=x")
+	assert.NotContains(t, got, "popped=/", "a popstate the group middleware refused must not run the handler")
+}
+
+func TestOnPopState_isSignedAndAudited(t *testing.T) {
+	t.Parallel()
+	log := &auditLog{}
+	app := via.New(via.WithAuditHook(log.record),
+		via.WithActionSigning(via.ActionSigning{Key: sigKey}))
+	server := vt.Serve(t, app)
+	via.Mount[inboxPage](app, "/inbox")
+	tc := vt.NewClient(t, server, "/inbox")
+
+	require.Equal(t, http.StatusOK, tc.PopState("/inbox?status=archived"))
+	assert.Equal(t, "popstate", log.last(t).Action)
+	assert.Equal(t, tc.TabID(), log.last(t).TabID)
+
+	httpc := jarClient(t)
+	resp, err := httpc.Get(server.URL + "/inbox")
+	require.NoError(t, err)
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	tab := vt.TabIDFromHTML(string(page))
+	require.NotEmpty(t, tab)
+	resp, err = httpc.Post(server.URL+"/_sse/popstate", "text/plain",
+		strings.NewReader(tab+" /inbox?status=forged"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "an unsigned beacon")
+}
+
+func TestPushURL_prefixesTheBasePath(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"))
+	via.Mount[baseInboxPage](app, "/inbox")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/inbox")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Archive").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `history.pushState(null,'',"/app/inbox"+"?status=archived")`)
+
+	// The browser reports the prefixed path; the handler sees the route's.
+	require.Equal(t, 200, tc.PopState("/app/inbox?status=open"))
+	vt.AwaitFrame(t, frames, 2*time.Second, "popped=/inbox?status=open")
+}
+
+type baseInboxPage struct{ inboxPage }
+
+func (p *baseInboxPage) Archive(ctx *via.Ctx) error {
+	ctx.PushURL("/inbox", url.Values{"status": {"archived"}})
+	return nil
+}
@@ -0,0 +1,267 @@
+package via
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScopedID namespaces an element id to one composition instance, so two
+// instances of the same child type can each render h.ID(ctx.ScopedID(c,
+// "row")) without colliding — a duplicate id makes a patch land on
+// whichever element the browser finds first:
+//
+//	type Page struct {
+//	    Left, Right *Card
+//	}
+//
+//	func (c *Card) View(ctx *via.CtxR) h.H {
+//	    return h.Div(h.ID(ctx.ScopedID(c, "row")), ...) // "Left-row", "Right-row"
+//	}
+//
+// owner is the composition the id belongs to — pass the View's receiver.
+// The id is its field path in the page, joined with "-", plus name; the
+// page's own composition leaves name unchanged. Ids are stable across
+// renders, so actions can target them with Patch().Elements. An owner that
+// is not this tab's composition or one of its children panics.
+func (ctx *Ctx) ScopedID(owner any, name string) string {
+	c, root := ctx.ownerSlot(owner, "ScopedID", name)
+	if root {
+		return name
+	}
+	return c.idPrefix + "-" + name
+}
+
+// ScopedSignal namespaces an ad-hoc signal key to one composition
+// instance, the way typed Signal fields of a child are namespaced for you.
+// Use it for raw keys a child binds itself (h.Data("bind", …), on.* or
+// Patch().Signal), so two instances of the child don't share one client
+// signal:
+//
+//	func (c *Card) View(ctx *via.CtxR) h.H {
+//	    key := ctx.ScopedSignal(c, "draft") // "Left.draft", "Right.draft"
+//	    return h.Input(h.Data("bind", key))
+//	}
+//
+// Same owner contract as [Ctx.ScopedID]; the page's own composition
+// leaves name unchanged.
+func (ctx *Ctx) ScopedSignal(owner any, name string) string {
+	c, root := ctx.ownerSlot(owner, "ScopedSignal", name)
+	if root {
+		return name
+	}
+	return c.wirePrefix + "." + name
+}
+
+// ownerSlot resolves owner to this tab's composition (root) or the child
+// slot holding it, panicking on anything else.
+func (ctx *Ctx) ownerSlot(owner any, fn, name string) (slot childSlot, root bool) {
+	v := reflect.ValueOf(owner)
+	if ctx != nil && ctx.desc != nil && v.Kind() == reflect.Pointer && !v.IsNil() {
+		if ctx.cmpReflect.IsValid() && v.Pointer() == ctx.cmpReflect.Pointer() {
+			return childSlot{}, true
+		}
+		found := false
+		for _, c := range ctx.desc.childSlots {
+			p, ok := childPointer(ctx.cmpReflect, c.fieldPath)
+			if !ok || p != v.Pointer() {
+				continue
+			}
+			if found {
+				// Zero-size structs share one address, so instances of a
+				// field-less child can't be told apart.
+				panic(fmt.Sprintf("via: %s(%T, %q): %T has no fields, so its "+
+					"instances share an address; give it a field or build the id "+
+					"from a prop", fn, owner, name, owner))
+			}
+			slot, found = c, true
+		}
+		if found {
+			return slot, false
+		}
+	}
+	panic(fmt.Sprintf("via: %s(%T, %q): owner is not this tab's composition "+
+		"or one of its child compositions", fn, owner, name))
+}
+
+// ScopedID namespaces an element id to one composition instance. See
+// [Ctx.ScopedID].
+func (r *CtxR) ScopedID(owner any, name string) string { return r.rctx().ScopedID(owner, name) }
+
+// ScopedSignal namespaces an ad-hoc signal key to one composition
+// instance. See [Ctx.ScopedSignal].
+func (r *CtxR) ScopedSignal(owner any, name string) string {
+	return r.rctx().ScopedSignal(owner, name)
+}
+
+// childPointer resolves the child composition at path under root without
+// allocating: unlike fieldByPath it reports false for a nil child instead of
+// filling it in, since ScopedID runs inside a View.
+func childPointer(root reflect.Value, path []int) (uintptr, bool) {
+	if !root.IsValid() || root.IsNil() {
+		return 0, false
+	}
+	v := root.Elem()
+	for _, idx := range path {
+		v = v.Field(idx)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return 0, false
+			}
+			v = v.Elem()
+		}
+	}
+	return v.Addr().Pointer(), true
+}
+
+// checkDuplicates logs every element id that appears more than once in a
+// rendered view, and every signal key two-way bound by more than one
+// element, once per route and id or key. WithMarkupChecks only.
+func (a *App) checkDuplicates(ctx *Ctx, doc string) {
+	for _, id := range duplicateIDs(doc) {
+		key := ctx.desc.route + "\x00id\x00" + id
+		if _, seen := a.dupIDSeen.LoadOrStore(key, struct{}{}); seen {
+			continue
+		}
+		a.logWarn(ctx, "duplicate element id %q on %s: patches target only the first; "+
+			"namespace per-instance ids with ScopedID", id, ctx.desc.route)
+	}
+	for _, sig := range duplicateBinds(doc) {
+		key := ctx.desc.route + "\x00bind\x00" + sig
+		if _, seen := a.dupIDSeen.LoadOrStore(key, struct{}{}); seen {
+			continue
+		}
+		a.logWarn(ctx, "signal %q is bound by more than one element on %s: every "+
+			"input edits one shared value; if they belong to separate component "+
+			"instances, namespace the key with ScopedSignal", sig, ctx.desc.route)
+	}
+}
+
+// duplicateIDs returns the ids that more than one element in doc carries,
+// in order of their second appearance. Like auditA11y it scans HTML as the
+// h package renders it.
+func duplicateIDs(doc string) []string {
+	var (
+		out  []string
+		seen = map[string]int{}
+	)
+	walkTags(doc, func(_ string, attrs map[string]string) {
+		if id := attrs["id"]; id != "" {
+			if seen[id]++; seen[id] == 2 {
+				out = append(out, id)
+			}
+		}
+	})
+	return out
+}
+
+// duplicateBinds returns the signal keys two-way bound by more than one
+// element in doc, in order of their second appearance. Both datastar
+// spellings count: data-bind="key" and data-bind:key (modifiers after
+// "__" are dropped). Radio inputs are skipped: a group of them sharing one
+// key is how a radio group binds.
+func duplicateBinds(doc string) []string {
+	var (
+		out  []string
+		seen = map[string]int{}
+	)
+	walkTags(doc, func(tag string, attrs map[string]string) {
+		if tag == "input" && strings.EqualFold(attrs["type"], "radio") {
+			return
+		}
+		for name, val := range attrs {
+			key := ""
+			switch {
+			case name == "data-bind":
+				key = val
+			case strings.HasPrefix(name, "data-bind:"):
+				key, _, _ = strings.Cut(name[len("data-bind:"):], "__")
+			}
+			if key == "" {
+				continue
+			}
+			if seen[key]++; seen[key] == 2 {
+				out = append(out, key)
+			}
+		}
+	})
+	return out
+}
+
+// walkTags calls fn for every opening tag in doc, skipping comments and
+// the bodies of script and style elements.
+func walkTags(doc string, fn func(tag string, attrs map[string]string)) {
+	scanHTML(doc, func(t htmlToken) {
+		if t.kind == tokenOpen {
+			fn(t.tag, t.attrs)
+		}
+	})
+}
+
+type htmlTokenKind uint8
+
+const (
+	tokenText htmlTokenKind = iota
+	tokenOpen
+	tokenClose
+)
+
+// htmlToken is one piece of a document as scanHTML hands it out.
+type htmlToken struct {
+	kind  htmlTokenKind
+	tag   string            // lowercased name; open and close tokens
+	attrs map[string]string // open tokens only
+	raw   string            // the token's source
+	leaf  bool              // an open tag that has no closing tag: void or "/>"
+}
+
+// scanHTML calls fn for each run of text, opening tag and closing tag in
+// doc, in order. It scans HTML as the h package renders it (well-formed,
+// quoted attributes): comments and doctypes are dropped, and the raw-text
+// bodies of script and style elements are skipped whole, so markup-looking
+// strings inside them aren't taken for elements. The duplicate-id, nesting
+// and a11y checks all build on it.
+func scanHTML(doc string, fn func(htmlToken)) {
+	for i := 0; i < len(doc); {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			lt = len(doc) - i
+		}
+		if lt > 0 {
+			fn(htmlToken{kind: tokenText, raw: doc[i : i+lt]})
+			i += lt
+			continue
+		}
+		if strings.HasPrefix(doc[i:], "<!--") {
+			end := strings.Index(doc[i:], "-->")
+			if end < 0 {
+				return
+			}
+			i += end + 3
+			continue
+		}
+		gt := tagEnd(doc, i)
+		if gt < 0 {
+			return
+		}
+		raw := doc[i : gt+1]
+		i = gt + 1
+		switch {
+		case strings.HasPrefix(raw, "<!"):
+			continue // doctype
+		case strings.HasPrefix(raw, "</"):
+			fn(htmlToken{kind: tokenClose, tag: strings.ToLower(strings.TrimSpace(raw[2 : len(raw)-1])), raw: raw})
+			continue
+		}
+		tag, attrs := parseTag(raw)
+		fn(htmlToken{kind: tokenOpen, tag: tag, attrs: attrs, raw: raw,
+			leaf: voidTags[tag] || strings.HasSuffix(raw, "/>")})
+		if tag == "script" || tag == "style" {
+			end := strings.Index(strings.ToLower(doc[i:]), "</"+tag)
+			if end < 0 {
+				return
+			}
+			i += end
+		}
+	}
+}
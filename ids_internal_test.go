@@ -0,0 +1,61 @@
+package via
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateIDs_reportsEachRepeatedIDOnce(t *testing.T) {
+	t.Parallel()
+
+	doc := `<div id="a"><p id="b" title="x>y"></p><p id="a"></p><p id="a"></p>` +
+		`<script>document.write('<i id="b"></i>')</script><!-- <i id="b"> --></div>`
+
+	assert.Equal(t, []string{"a"}, duplicateIDs(doc))
+	assert.Empty(t, duplicateIDs(`<div id="a"></div><div id="b"></div>`))
+}
+
+func TestDuplicateBinds_reportsBothSpellings(t *testing.T) {
+	t.Parallel()
+
+	doc := `<input data-bind="a"><input data-bind:a__case.kebab><input data-bind="b">` +
+		`<!-- <input data-bind="b"> -->`
+
+	assert.Equal(t, []string{"a"}, duplicateBinds(doc))
+	assert.Empty(t, duplicateBinds(`<input data-bind="a"><input data-bind="b">`))
+}
+
+func TestDuplicateBinds_skipsRadioGroups(t *testing.T) {
+	t.Parallel()
+
+	doc := `<input type="radio" data-bind="size" value="s">` +
+		`<input type="radio" data-bind="size" value="m">` +
+		`<input type="RADIO" data-bind:size value="l">`
+
+	assert.Empty(t, duplicateBinds(doc))
+}
+
+func TestScanHTML_yieldsTextOpenAndCloseTokens(t *testing.T) {
+	t.Parallel()
+
+	doc := `<!doctype html><p class="a>b">hi<br></p><!-- <i> -->` +
+		`<script>if (a<b) document.write('<i>')</script><img src=x />`
+
+	var got []string
+	scanHTML(doc, func(tok htmlToken) {
+		switch tok.kind {
+		case tokenText:
+			got = append(got, "text "+tok.raw)
+		case tokenOpen:
+			got = append(got, fmt.Sprintf("open %s leaf=%v", tok.tag, tok.leaf))
+		case tokenClose:
+			got = append(got, "close "+tok.tag)
+		}
+	})
+	assert.Equal(t, []string{
+		"open p leaf=false", "text hi", "open br leaf=true", "close p",
+		"open script leaf=false", "close script", "open img leaf=true",
+	}, got)
+}
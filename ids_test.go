@@ -0,0 +1,183 @@
+package via_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+type idCard struct {
+	Open via.Signal[bool]
+}
+
+func (c *idCard) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID(ctx.ScopedID(c, "row")))
+}
+
+type scopedIDPage struct {
+	Left, Right *idCard
+}
+
+func (p *scopedIDPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID(ctx.ScopedID(p, "root")), p.Left.View(ctx), p.Right.View(ctx))
+}
+
+type rawIDCard struct{}
+
+func (c *rawIDCard) View(ctx *via.CtxR) h.H { return h.Div(h.ID("row")) }
+
+type dupIDPage struct {
+	Left, Right *rawIDCard
+}
+
+func (p *dupIDPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Left.View(ctx), p.Right.View(ctx))
+}
+
+func dupIDWarnings(logger *captureLogger) []string {
+	var out []string
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogWarn && strings.HasPrefix(r.msg, "duplicate element id") {
+			out = append(out, r.msg)
+		}
+	}
+	return out
+}
+
+func TestScopedID_namespacesIDsPerChildInstance(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithMarkupChecks())
+	via.Mount[scopedIDPage](app, "/")
+
+	html := vt.NewClient(t, server, "/").HTML()
+
+	assert.Contains(t, html, `id="root"`)
+	assert.Contains(t, html, `id="Left-row"`)
+	assert.Contains(t, html, `id="Right-row"`)
+	assert.Empty(t, dupIDWarnings(logger))
+}
+
+func TestMarkupChecks_warnOnDuplicateIDOncePerRoute(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithMarkupChecks())
+	via.Mount[dupIDPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/")
+
+	warns := dupIDWarnings(logger)
+	if assert.Len(t, warns, 1) {
+		assert.Contains(t, warns[0], `"row"`)
+	}
+}
+
+func TestMarkupChecks_duplicateIDCheckIsOffByDefault(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug)
+	via.Mount[dupIDPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+
+	assert.Empty(t, dupIDWarnings(logger))
+}
+
+type draftCard struct {
+	Open via.Signal[bool]
+}
+
+func (c *draftCard) View(ctx *via.CtxR) h.H {
+	return h.Input(h.Data("bind", ctx.ScopedSignal(c, "draft")))
+}
+
+type scopedSignalPage struct {
+	Left, Right *draftCard
+}
+
+func (p *scopedSignalPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.Input(h.Data("bind", ctx.ScopedSignal(p, "q"))), p.Left.View(ctx), p.Right.View(ctx))
+}
+
+type rawBindCard struct{}
+
+func (c *rawBindCard) View(ctx *via.CtxR) h.H { return h.Input(h.Data("bind", "draft")) }
+
+type dupBindPage struct {
+	Left, Right *rawBindCard
+}
+
+func (p *dupBindPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Left.View(ctx), p.Right.View(ctx))
+}
+
+func dupBindWarnings(logger *captureLogger) []string {
+	var out []string
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogWarn && strings.Contains(r.msg, "is bound by more than one element") {
+			out = append(out, r.msg)
+		}
+	}
+	return out
+}
+
+func TestScopedSignal_namespacesKeysPerChildInstance(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithMarkupChecks())
+	via.Mount[scopedSignalPage](app, "/")
+
+	html := vt.NewClient(t, server, "/").HTML()
+
+	assert.Contains(t, html, `data-bind="q"`)
+	assert.Contains(t, html, `data-bind="Left.draft"`)
+	assert.Contains(t, html, `data-bind="Right.draft"`)
+	assert.Empty(t, dupBindWarnings(logger))
+}
+
+func TestMarkupChecks_warnOnSharedBindOncePerRoute(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithMarkupChecks())
+	via.Mount[dupBindPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/")
+
+	warns := dupBindWarnings(logger)
+	if assert.Len(t, warns, 1) {
+		assert.Contains(t, warns[0], `"draft"`)
+		assert.Contains(t, warns[0], "ScopedSignal")
+	}
+}
+
+type badNestPage struct{}
+
+func (p *badNestPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.T("Total: "), h.Div(h.T("42")))
+}
+
+func TestMarkupChecks_warnOnInvalidNestingOncePerRoute(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug, via.WithMarkupChecks())
+	via.Mount[badNestPage](app, "/")
+
+	vt.NewClient(t, server, "/")
+	vt.NewClient(t, server, "/")
+
+	var warns []string
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogWarn && strings.HasPrefix(r.msg, "invalid nesting") {
+			warns = append(warns, r.msg)
+		}
+	}
+	if assert.Len(t, warns, 1) {
+		assert.Contains(t, warns[0], "<div> inside <p>")
+	}
+}
@@ -2,7 +2,9 @@ package via
 
 import (
 	"cmp"
+	"maps"
 	"slices"
+	"time"
 )
 
 // CompositionInfo is one entry in App.Compositions().
@@ -32,6 +34,69 @@ func (a *App) Compositions() []CompositionInfo {
 	return out
 }
 
+// ContextInfo is a read-only snapshot of one live tab, as returned by
+// [App.Contexts] and handed to the predicate of [App.BroadcastFunc].
+type ContextInfo struct {
+	ID    string // tab id, as Ctx.ID
+	Route string // mounted pattern of the tab's composition
+	// SessionID is an opaque, stable handle for the tab's session — tabs
+	// sharing a browser session share it. It is a digest, never the
+	// session cookie itself, so it is safe to log or show on an admin page.
+	// Empty for a tab without a session.
+	SessionID   string
+	User        string            // user the session signed in as (Ctx.SignIn); "" if none
+	Connected   bool              // an SSE stream is open right now
+	ConnectedAt time.Time         // when the latest SSE stream opened; zero if none ever has
+	LastActive  time.Time         // last page load, action, or stream write
+	Labels      map[string]string // labels set with Ctx.SetLabel; a copy
+	// FrameGap is the minimum gap the stream currently holds between SSE
+	// frames because the client drains them slowly (see
+	// WithSSEAdaptiveRate); 0 means full rate.
+	FrameGap time.Duration
+}
+
+// info snapshots ctx for a ContextInfo consumer.
+func (ctx *Ctx) info() ContextInfo {
+	ctx.labelsMu.RLock()
+	labels := maps.Clone(ctx.labels)
+	ctx.labelsMu.RUnlock()
+	info := ContextInfo{
+		ID:         ctx.id,
+		Route:      ctx.desc.route,
+		Connected:  ctx.connected.Load() > 0,
+		LastActive: time.Unix(0, ctx.lastAccess.Load()),
+		Labels:     labels,
+		FrameGap:   time.Duration(ctx.frameGap.Load()),
+	}
+	if at := ctx.connectedAt.Load(); at != 0 {
+		info.ConnectedAt = time.Unix(0, at)
+	}
+	if sess := ctx.session.Load(); sess != nil {
+		info.SessionID = sessionDigest(sess.id)
+		if in := sess.auth.Load(); in != nil {
+			info.User = in.user
+		}
+	}
+	return info
+}
+
+// Contexts returns a snapshot of every live tab on this pod, sorted by
+// id — the introspection behind admin pages and ops tooling, without
+// reaching into the registry:
+//
+//	for _, c := range app.Contexts() {
+//	    log.Printf("%s %s idle %s", c.ID, c.Route, time.Since(c.LastActive))
+//	}
+func (a *App) Contexts() []ContextInfo {
+	ctxs := a.snapshotContexts()
+	out := make([]ContextInfo, 0, len(ctxs))
+	for _, c := range ctxs {
+		out = append(out, c.info())
+	}
+	slices.SortFunc(out, func(a, b ContextInfo) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
 // RouteInfo is one entry in App.Routes().
 type RouteInfo struct {
 	Pattern      string // method-and-pattern, e.g. "GET /counter/{id}"
@@ -59,7 +124,5 @@ func (a *App) Routes() []RouteInfo {
 // snapshot — it may have changed by the time the caller reads the
 // return value.
 func (a *App) LiveTabs() int {
-	a.contextRegistryMu.RLock()
-	defer a.contextRegistryMu.RUnlock()
-	return len(a.contextRegistry)
+	return a.contexts.len()
 }
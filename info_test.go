@@ -1,7 +1,9 @@
 package via_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-via/via"
 	"github.com/go-via/via/h"
@@ -31,3 +33,62 @@ func TestLiveTabs_reflectsRegisteredCount(t *testing.T) {
 			"each fresh page render registers one ctx")
 	}
 }
+
+func TestContexts_snapshotsLiveTabs(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[labelPage](app, "/")
+	via.Mount[liveTabsPage](app, "/other")
+
+	before := time.Now()
+	tc := vt.NewClient(t, server, "/?tenant=acme")
+	forked := tc.Fork("/other")
+	loner := vt.NewClient(t, server, "/other")
+	_, cancel := tc.SSEReady()
+	defer cancel()
+
+	infos := app.Contexts()
+	require.Len(t, infos, 3)
+	byID := map[string]via.ContextInfo{}
+	for _, c := range infos {
+		byID[c.ID] = c
+	}
+	assert.IsIncreasing(t, []string{infos[0].ID, infos[1].ID, infos[2].ID}, "sorted by id")
+
+	live := byID[tc.TabID()]
+	assert.Equal(t, "/", live.Route)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, live.Labels)
+	assert.True(t, live.Connected)
+	assert.False(t, live.ConnectedAt.Before(before))
+	assert.False(t, live.LastActive.Before(before))
+
+	idle := byID[forked.TabID()]
+	assert.Equal(t, "/other", idle.Route)
+	assert.False(t, idle.Connected)
+	assert.True(t, idle.ConnectedAt.IsZero(), "never opened a stream")
+	assert.Equal(t, live.SessionID, idle.SessionID, "tabs on one browser session share the handle")
+	assert.NotEqual(t, live.SessionID, byID[loner.TabID()].SessionID)
+}
+
+func TestContexts_sessionIDIsNotTheSessionCookie(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[liveTabsPage](app, "/")
+
+	resp, err := server.Client().Get(server.URL + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	infos := app.Contexts()
+	require.Len(t, infos, 1)
+	require.NotEmpty(t, infos[0].SessionID)
+	require.NotEmpty(t, resp.Cookies(), "the page set a session cookie to compare against")
+	for _, c := range resp.Cookies() {
+		assert.False(t, strings.Contains(c.Value, infos[0].SessionID) || strings.Contains(infos[0].SessionID, c.Value),
+			"cookie %s must not be recoverable from ContextInfo", c.Name)
+	}
+}
@@ -93,6 +93,7 @@ func (p *LoginPage) Submit(ctx *via.Ctx) error {
 	}
 	sess.Rotate(ctx)
 	sess.Put(ctx, user)
+	ctx.SignIn(user.Email)
 	ctx.Redirect("/profile")
 	return nil
 }
@@ -157,6 +158,7 @@ func errBanner(msg string) h.H {
 type ProfilePage struct{}
 
 func (p *ProfilePage) Logout(ctx *via.Ctx) error {
+	ctx.SignOut()
 	sess.Clear[User](ctx)
 	sess.Rotate(ctx)
 	ctx.Redirect("/")
@@ -201,32 +203,29 @@ func shell(ctx *via.CtxR, content h.H) h.H {
 	)
 }
 
-// Middleware: redirect to /login if no User in the session.
-func requireAuth(w http.ResponseWriter, r *http.Request, next http.Handler) {
-	if u, ok := sess.Get[User](r); !ok || u.Email == "" {
-		http.Redirect(w, r, "/login", http.StatusSeeOther)
-		return
-	}
-	next.ServeHTTP(w, r)
-}
-
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("Via Auth"),
 		via.WithPlugins(picocss.Plugin(
 			picocss.WithThemes([]picocss.PicoTheme{picocss.PicoThemeAmber}),
 		)),
-	)
+	}, opts...)...)
 
 	// Public pages
 	via.Mount[LandingPage](app, "/")
 	via.Mount[LoginPage](app, "/login")
 	via.Mount[RegisterPage](app, "/register")
 
-	// Protected page
+	// Protected page: signed-out loads, actions and streams go to /login.
 	protected := app.Group("")
-	protected.Use(requireAuth)
+	protected.Use(via.RequireAuth("/login"))
 	via.Mount[ProfilePage](protected, "/profile")
 
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
@@ -96,11 +96,17 @@ func (r *Room) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("Via Chat"),
 		via.WithPlugins(picocss.Plugin()),
-	)
+	}, opts...)...)
 	via.Mount[Room](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
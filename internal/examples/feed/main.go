@@ -65,8 +65,14 @@ func (p *Feed) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(via.WithTitle("Feed"))
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{via.WithTitle("Feed")}, opts...)...)
 	via.Mount[Feed](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
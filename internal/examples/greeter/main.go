@@ -31,8 +31,14 @@ func (g *Greeter) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(via.WithTitle("Greeter"))
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{via.WithTitle("Greeter")}, opts...)...)
 	via.Mount[Greeter](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
@@ -46,11 +46,17 @@ func (c *CounterPage) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("Path Params"),
 		via.WithPlugins(picocss.Plugin()),
-	)
+	}, opts...)...)
 	via.Mount[CounterPage](app, "/counters/{counter_id}/{start_at_step}")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
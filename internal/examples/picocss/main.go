@@ -137,8 +137,10 @@ func (p *Page) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("Via + Pico CSS"),
 		via.WithPlugins(
 			picocss.Plugin(
@@ -147,7 +149,11 @@ func main() {
 				picocss.WithColorClasses(),
 			),
 		),
-	)
+	}, opts...)...)
 	via.Mount[Page](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
@@ -258,21 +258,10 @@ func (p *Page) OnInit(ctx *via.Ctx) error {
 
 func (p *Page) ApplyControls(ctx *via.Ctx) {
 	p.ticker.SetInterval(time.Duration(p.IntervalMs.Read(ctx)) * time.Millisecond)
-	if p.Running.Read(ctx) {
-		p.ticker.Resume()
-	} else {
-		p.ticker.Pause()
-	}
 }
 
 func (p *Page) ToggleRunning(ctx *via.Ctx) {
-	v := !p.Running.Read(ctx)
-	p.Running.Write(ctx, v)
-	if v {
-		p.ticker.Resume()
-	} else {
-		p.ticker.Pause()
-	}
+	p.Running.Op(ctx).Toggle()
 }
 
 func (p *Page) OnConnect(ctx *via.Ctx) error {
@@ -315,6 +304,9 @@ func (p *Page) OnConnect(ctx *via.Ctx) error {
 			echarts.LineDense("TX", p.netTXBuf.snapshot()),
 		)
 	})
+	// Running gates the stream, and a background tab costs no samples.
+	p.ticker.BindTo(&p.Running)
+	p.ticker.PauseWhileHidden()
 	return nil
 }
 
@@ -363,8 +355,10 @@ func (p *Page) View(ctx *via.CtxR) h.H {
 	)
 }
 
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("System Monitor"),
 		via.WithPlugins(
 			picocss.Plugin(
@@ -373,7 +367,11 @@ func main() {
 			),
 			echarts.Plugin(),
 		),
-	)
+	}, opts...)...)
 	via.Mount[Page](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
@@ -168,11 +168,17 @@ func (t *Todos) FilterDone(ctx *via.Ctx) {
 	}
 }
 
-func main() {
-	app := via.New(
+// newApp builds the example app; opts are applied after its own (the
+// examples smoke test passes its logger).
+func newApp(opts ...via.Option) *via.App {
+	app := via.New(append([]via.Option{
 		via.WithTitle("Via Todos"),
 		via.WithPlugins(picocss.Plugin()),
-	)
+	}, opts...)...)
 	via.Mount[Todos](app, "/")
-	_ = http.ListenAndServe(":3000", app)
+	return app
+}
+
+func main() {
+	_ = http.ListenAndServe(":3000", newApp())
 }
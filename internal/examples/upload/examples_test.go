@@ -0,0 +1,13 @@
+//go:build examples
+
+package main
+
+import (
+	"testing"
+
+	"github.com/go-via/via/internal/exampletest"
+)
+
+func TestExample_smoke(t *testing.T) {
+	exampletest.Smoke(t, newApp, "/")
+}
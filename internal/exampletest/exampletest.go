@@ -0,0 +1,77 @@
+// Package exampletest drives the apps under internal/examples the way a
+// visitor would, turning them into a regression suite for the framework.
+// Each example carries an examples_test.go behind the "examples" build
+// tag:
+//
+//	go test -tags examples ./internal/examples/...
+package exampletest
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settled is the script Smoke broadcasts after a page's actions: its
+// tab's queue is FIFO, so once it arrives every patch the actions queued
+// has been written.
+const settled = "/* exampletest: settled */"
+
+// Smoke boots the app newApp builds in-process and, for each page: loads
+// it, opens its SSE stream, fires every action its HTML binds (see
+// vt.Client.FireAll), waits for the stream to deliver what they queued
+// and reloads it. It fails on any 5xx answer and on any error-level log
+// line.
+//
+// newApp receives the options the harness needs (its logger) and must
+// apply them after the example's own.
+func Smoke(t *testing.T, newApp func(...via.Option) *via.App, pages ...string) {
+	t.Helper()
+	logs := &errorLog{}
+	app := newApp(via.WithLogger(logs))
+	server := vt.Serve(t, app)
+
+	for _, page := range pages {
+		c := vt.NewClient(t, server, page)
+		frames, cancel := c.SSEReady()
+
+		fired := c.FireAll()
+		for _, f := range fired {
+			assert.Less(t, f.Status, http.StatusInternalServerError, "%s: action %s", page, f.Action)
+		}
+		require.Positive(t, app.Broadcast(settled))
+		vt.AwaitFrame(t, frames, 2*time.Second, settled)
+		cancel()
+		t.Logf("%s: fired %d actions", page, len(fired))
+		assert.NotEmpty(t, c.Reload(), "%s: empty page on reload", page)
+	}
+	assert.Empty(t, logs.lines(), "error-level log lines")
+}
+
+// errorLog collects error-level records.
+type errorLog struct {
+	mu  sync.Mutex
+	got []string
+}
+
+func (l *errorLog) Log(level via.LogLevel, msg string, kv ...any) {
+	if level < via.LogError {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.got = append(l.got, fmt.Sprint(append([]any{msg}, kv...)...))
+}
+
+func (l *errorLog) lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.got...)
+}
@@ -77,6 +77,11 @@ type Trigger struct {
 	Modifiers []string // e.g. ["prevent", "stop"]
 	KeyFilter string   // e.g. "Enter" for on:keydown
 
+	// Guard, when non-empty, is a JS boolean expression that must hold for
+	// the action to fire; rendered as `<Guard>&&` ahead of the @post. Set
+	// by on.Hotkey for its key-combination match.
+	Guard string
+
 	// Confirm, when non-empty, is a JSON-encoded string used as a
 	// confirm(<Confirm>) guard that short-circuits the action POST unless
 	// the user accepts. Set by on.Confirm.
@@ -86,6 +91,14 @@ type Trigger struct {
 	// @post(...) call fires. Used by on.SetSignal to bundle a typed
 	// signal write into the same trigger.
 	Pre []string
+
+	// Args is the URL-encoded query (k=v&…) appended to the action URL,
+	// built by on.Arg and read back with Ctx.EventArg.
+	Args string
+
+	// Optimistic lists the wire keys of signals written client-side by
+	// on.Optimistic; rendered into the POST's OptimisticHeader.
+	Optimistic []string
 }
 
 // AppendPre adds a JS statement that will run before the action POST.
@@ -97,3 +110,9 @@ func (s *Trigger) AppendPre(stmt string) {
 	}
 	s.Pre = append(s.Pre, stmt)
 }
+
+// OptimisticHeader names the request header an on.Optimistic trigger
+// attaches to its action POST: the comma-separated wire keys of the
+// signals it wrote client-side before firing. The runtime reverts any of
+// them the action does not write itself.
+const OptimisticHeader = "Via-Optimistic"
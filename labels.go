@@ -0,0 +1,145 @@
+package via
+
+import "slices"
+
+// SetLabel tags this tab with key=value — a user id, a tenant, an
+// experiment variant. Labels ride every log record the tab produces
+// (runtime warnings and [Log] alike), feed the action metrics named by
+// [WithMetricLabels], and select the audience of [App.BroadcastWhere]:
+//
+//	func (p *Dashboard) OnInit(ctx *via.Ctx) error {
+//	    ctx.SetLabel("tenant", currentTenant(ctx))
+//	    return nil
+//	}
+//
+// An empty value removes the label. Safe from any goroutine.
+func (ctx *Ctx) SetLabel(key, value string) {
+	if ctx == nil || key == "" {
+		return
+	}
+	ctx.labelsMu.Lock()
+	defer ctx.labelsMu.Unlock()
+	if value == "" {
+		delete(ctx.labels, key)
+		return
+	}
+	if ctx.labels == nil {
+		ctx.labels = make(map[string]string, 2)
+	}
+	ctx.labels[key] = value
+}
+
+// Label returns the value set with [Ctx.SetLabel] for key, or "".
+func (ctx *Ctx) Label(key string) string {
+	if ctx == nil {
+		return ""
+	}
+	ctx.labelsMu.RLock()
+	defer ctx.labelsMu.RUnlock()
+	return ctx.labels[key]
+}
+
+// Label returns the tab's label for key, or "". See [Ctx.SetLabel].
+func (r *CtxR) Label(key string) string { return r.rctx().Label(key) }
+
+// labelPairs returns the tab's labels as flat key,value log fields, sorted
+// by key so records from one tab always line up.
+func (ctx *Ctx) labelPairs() []any {
+	ctx.labelsMu.RLock()
+	defer ctx.labelsMu.RUnlock()
+	if len(ctx.labels) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(ctx.labels))
+	for k := range ctx.labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	out := make([]any, 0, 2*len(keys))
+	for _, k := range keys {
+		out = append(out, k, ctx.labels[k])
+	}
+	return out
+}
+
+// metricLabels appends the WithMetricLabels keys and this tab's values for
+// them (empty when unset, so every series carries the same label set).
+func (ctx *Ctx) metricLabels(base ...string) []string {
+	keys := ctx.app.cfg.metricLabels
+	if len(keys) == 0 {
+		return base
+	}
+	out := make([]string, 0, len(base)+2*len(keys))
+	out = append(out, base...)
+	for _, k := range keys {
+		out = append(out, k, ctx.Label(k))
+	}
+	return out
+}
+
+// labelMatch selects the tabs whose label Key equals Value. It travels
+// inside a broadcastRecord, so every pod filters its own tabs.
+type labelMatch struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (m *labelMatch) matches(ctx *Ctx) bool {
+	if m == nil {
+		return true
+	}
+	ctx.labelsMu.RLock()
+	defer ctx.labelsMu.RUnlock()
+	v, ok := ctx.labels[m.Key]
+	return ok && v == m.Value
+}
+
+// Audience is the set of live tabs carrying one label value, returned by
+// [App.BroadcastWhere]. Its methods mirror the app-wide broadcasts and share
+// their delivery semantics: cross-pod when a backplane is wired, pod-local
+// otherwise. Each returns this pod's matching-tab count at call time.
+type Audience struct {
+	app   *App
+	where labelMatch
+}
+
+// BroadcastWhere targets the tabs labelled key=value with [Ctx.SetLabel]:
+//
+//	app.BroadcastWhere("tenant", "acme").Notify("Billing is down for maintenance")
+//
+// A tab without the label never matches, even when value is "". Labels are
+// read when the broadcast is applied, so a tab labelled after
+// the call on another pod may or may not receive it.
+func (a *App) BroadcastWhere(key, value string) Audience {
+	return Audience{app: a, where: labelMatch{Key: key, Value: value}}
+}
+
+// Broadcast queues script on every matching tab. See [App.Broadcast].
+func (au Audience) Broadcast(script string) int {
+	if script == "" {
+		return 0
+	}
+	return au.app.dispatchBroadcast(broadcastRecord{Kind: bcScript, Script: script, Where: &au.where})
+}
+
+// Notify shows an XSS-safe notification on every matching tab. See
+// [App.BroadcastNotify].
+func (au Audience) Notify(message string) int {
+	if message == "" {
+		return 0
+	}
+	script, ok := buildToastScript(message)
+	if !ok {
+		return 0
+	}
+	return au.Broadcast(script)
+}
+
+// Signals pushes a signal patch to every matching tab. See
+// [App.BroadcastSignals].
+func (au Audience) Signals(values map[string]any) int {
+	if len(values) == 0 {
+		return 0
+	}
+	return au.app.dispatchBroadcast(broadcastRecord{Kind: bcSignals, Signals: values, Where: &au.where})
+}
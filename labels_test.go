@@ -0,0 +1,103 @@
+package via_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type labelPage struct {
+	Tenant string `query:"tenant"`
+}
+
+func (p *labelPage) OnInit(ctx *via.Ctx) error {
+	ctx.SetLabel("tenant", p.Tenant)
+	return nil
+}
+
+func (p *labelPage) Work(ctx *via.Ctx) {
+	via.Log(ctx).Log(via.LogInfo, "working")
+}
+
+func (p *labelPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID("tenant"), h.Text(ctx.Label("tenant")))
+}
+
+func TestBroadcastWhere_reachesOnlyMatchingTabs(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[labelPage](app, "/")
+
+	acme := vt.NewClient(t, server, "/?tenant=acme")
+	acmeFrames, cancelAcme := acme.SSEReady()
+	defer cancelAcme()
+	other := vt.NewClient(t, server, "/?tenant=globex")
+	otherFrames, cancelOther := other.SSEReady()
+	defer cancelOther()
+
+	n := app.BroadcastWhere("tenant", "acme").Notify("acme-only notice")
+	assert.Equal(t, 1, n, "count is this pod's matching tabs")
+
+	vt.AwaitFrame(t, acmeFrames, 2*time.Second, "acme-only notice")
+	assert.NotContains(t, drainFor(otherFrames, 200*time.Millisecond), "acme-only notice")
+}
+
+func TestBroadcastWhere_skipsTabsWithoutTheLabel(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[labelPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	_, cancel := tc.SSEReady()
+	defer cancel()
+
+	assert.Zero(t, app.BroadcastWhere("tenant", "").Notify("x"),
+		"an empty SetLabel value removes the label, so nothing matches")
+}
+
+func TestLabels_stampLogRecords(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogInfo)
+	via.Mount[labelPage](app, "/")
+	tc := vt.NewClient(t, server, "/?tenant=acme")
+
+	tc.Action("Work").Fire()
+
+	require.Eventually(t, func() bool {
+		for _, r := range logger.snapshot() {
+			if r.msg == "working" {
+				i := slices.Index(r.kv, any("tenant"))
+				return i >= 0 && i+1 < len(r.kv) && r.kv[i+1] == "acme"
+			}
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestWithMetricLabels_slicesActionMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := &captureMetrics{}
+	app := via.New(via.WithMetrics(m), via.WithMetricLabels("tenant", "variant"))
+	server := vt.Serve(t, app)
+	via.Mount[labelPage](app, "/")
+	tc := vt.NewClient(t, server, "/?tenant=acme")
+
+	tc.Action("Work").Fire()
+
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return slices.Contains(m.counters, "via.action.total:method,Work,tenant,acme,variant,")
+	}, 2*time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,42 @@
+package via
+
+import "github.com/go-via/via/h"
+
+// Locale returns the tab's locale for the h formatting helpers — the one
+// set with [Ctx.SetLocale], else the browser's preferred language from
+// the Accept-Language header, else [h.LocaleEN]:
+//
+//	h.TextCurrency(p.Total, "EUR", ctx.Locale())
+//
+// The header is read once, on the first call after the page load — from
+// a View re-rendered by a broadcast too, with no request in flight.
+func (ctx *Ctx) Locale() h.Locale {
+	if ctx == nil {
+		return h.LocaleEN
+	}
+	if l := ctx.locale.Load(); l != nil {
+		return *l
+	}
+	ctx.mu.Lock()
+	header := ctx.clientHeader // the request's, as Client reports it
+	ctx.mu.Unlock()
+	if header == nil {
+		return h.LocaleEN
+	}
+	l := h.ParseLocale(header.Get("Accept-Language"))
+	ctx.locale.CompareAndSwap(nil, &l)
+	return *ctx.locale.Load()
+}
+
+// SetLocale overrides the tab's locale — a language the user picked in
+// their settings, say — and re-renders the view with it.
+func (ctx *Ctx) SetLocale(l h.Locale) {
+	if ctx == nil {
+		return
+	}
+	ctx.locale.Store(&l)
+	ctx.markStateDirty()
+}
+
+// Locale returns the tab's locale. See [Ctx.Locale].
+func (r *CtxR) Locale() h.Locale { return r.rctx().Locale() }
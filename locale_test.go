@@ -0,0 +1,55 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoicePage struct{}
+
+func (p *invoicePage) German(ctx *via.Ctx) error {
+	ctx.SetLocale(h.LocaleDE)
+	return nil
+}
+
+func (p *invoicePage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Text("total="), h.TextCurrency(1234.5, "EUR", ctx.Locale()))
+}
+
+func TestLocale_followsAcceptLanguage(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[invoicePage](app, "/")
+	server := vt.Serve(t, app)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "total=1.234,50\u00a0€")
+}
+
+func TestSetLocale_reRendersWithTheChosenLocale(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[invoicePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	assert.Contains(t, tc.HTML(), "total=€1,234.50")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("German").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "total=1.234,50\u00a0€")
+}
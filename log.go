@@ -49,6 +49,11 @@ func Log(ctx *Ctx) Logger {
 		if level < app.cfg.logLevel {
 			return
 		}
+		// Labels are read per record, so one taken before SetLabel still
+		// stamps labels set later.
+		if labels := ctx.labelPairs(); labels != nil {
+			kv = append(labels, kv...)
+		}
 		// Prepend correlation pairs in one allocation. The previous
 		// implementation made a 4-cap head slice unconditionally, then
 		// appended kv into it (a second alloc whenever both correlation
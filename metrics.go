@@ -10,25 +10,44 @@ package via
 //
 // Event catalogue (every name via emits; keep in sync with the call sites):
 //
+// Requests:
+//   - "via.request.total"     counter, labels: endpoint ("page", "action", "internal") — see App.Endpoint
+//   - "via.api.error"         counter, labels: status — an App.API handler answered with an error
+//   - "via.form.rejected"     counter, labels: reason — FormGuard.Check refused a submission
+//   - "via.auth.denied"       counter, labels: endpoint — RequireAuth turned away a signed-out request
+//
 // Actions & render:
-//   - "via.action.total"      counter, labels: method
-//   - "via.action.latency"    histogram (seconds), labels: method
+//   - "via.action.total"      counter, labels: method (+ WithMetricLabels keys)
+//   - "via.action.latency"    histogram (seconds), labels: method (+ WithMetricLabels keys)
+//   - "via.action.signature"  counter, labels: reason — WithActionSigning refused an action
 //   - "via.render.total"      counter, labels: route
+//   - "via.render.crawler"    counter, labels: route — a WithCrawlerRender page served without a tab
+//   - "via.render.shell"      counter, labels: result ("full", "not_modified") — a CacheableShell page
 //
 // SSE lifecycle:
 //   - "via.sse.connect"       counter — each successful handshake
 //   - "via.sse.disconnect"    counter, labels: reason ("client", "shutdown")
 //   - "via.sse.recover"       counter, labels: mode ("reload", "rebootstrap")
 //   - "via.sse.resync"        counter — a tab re-synced its signal state
+//   - "via.sse.replay"        counter — a reconnect was caught up from the replay buffer
+//   - "via.sse.write"         histogram (seconds), labels: route — time one frame took to write
+//   - "via.sse.paced"         counter, labels: route — a frame was held back for a slow client
+//
+// Stream tickers, all labelled by route:
+//   - "via.stream.lag"        histogram (seconds) — how late a tick started against its schedule
+//   - "via.stream.duration"   histogram (seconds) — time the tick callback took
+//   - "via.stream.skipped"    counter — an overrun skipped one or more ticks to rejoin the grid
 //
 // Tab (Ctx) lifecycle:
 //   - "via.ctx.live"          gauge — current registered tab count
 //   - "via.ctx.reap"          counter, labels: reason ("ttl", "shutdown")
+//   - "via.persist.error"     counter, labels: key — a StateTab's Persistence.Save failed
 //
 // Session:
 //   - "via.session.mismatch"  counter — an action/SSE handshake's bound
 //     session no longer matched the request cookie (403); usually two
 //     co-located via apps clobbering one another's session cookie
+//   - "via.session.rejected"  counter — a session cookie failed WithSessionKeys verification
 //
 // Event-log projection (StateAppEvents projector), all labelled by key:
 //   - "via.events.epoch_reset"           counter — stream generation reset, re-folded
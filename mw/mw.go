@@ -71,9 +71,24 @@ func RequestIDFrom(r *http.Request) string { return via.RequestIDFrom(r) }
 // Format: method=GET path=/foo status=200 duration=1.2ms rid=…
 // Status is captured by wrapping the ResponseWriter; default 200 if
 // the handler never calls WriteHeader.
-func AccessLog(a *via.App) via.Middleware {
+//
+// Framework plumbing ([via.EndpointInternal]: SSE streams and beacons,
+// the runtime script, plugin assets, [via.WithInternalPaths]) is not
+// logged, so the log reads as the pages and actions people used. Use
+// [AccessLogAll] to log it too.
+func AccessLog(a *via.App) via.Middleware { return accessLog(a, false) }
+
+// AccessLogAll is [AccessLog] without the plumbing filter: every request
+// is logged.
+func AccessLogAll(a *via.App) via.Middleware { return accessLog(a, true) }
+
+func accessLog(a *via.App, internal bool) via.Middleware {
 	logger := a.Logger()
 	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		if !internal && a.Endpoint(r) == via.EndpointInternal {
+			next.ServeHTTP(w, r)
+			return
+		}
 		start := time.Now()
 		sw := &statusWriter{ResponseWriter: w, status: 200}
 		next.ServeHTTP(sw, r)
@@ -340,6 +340,28 @@ func TestAccessLog_stripsCRLFFromRequestID(t *testing.T) {
 		"scrubbed rid keeps surrounding bytes minus the line break")
 }
 
+func TestAccessLog_skipsInternalEndpointsUnlessAsked(t *testing.T) {
+	t.Parallel()
+
+	app, _, logger := newLoggedApp(t, via.LogInfo, via.WithInternalPaths("/metrics"))
+	quiet, loud := mw.AccessLog(app), mw.AccessLogAll(app)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+
+	for _, p := range []string{"/_sse", "/_datastar.js", "/metrics", "/_action/Save", "/pricing"} {
+		quiet(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil), ok)
+	}
+	var logged []string
+	for _, r := range logger.snapshot() {
+		logged = append(logged, r.msg)
+	}
+	require.Len(t, logged, 2, "only the action and the page: %v", logged)
+	assert.Contains(t, logged[0], "/_action/Save")
+	assert.Contains(t, logged[1], "/pricing")
+
+	loud(httptest.NewRecorder(), httptest.NewRequest("GET", "/_sse", nil), ok)
+	assert.Len(t, logger.snapshot(), 3, "AccessLogAll logs plumbing too")
+}
+
 // Recover
 
 func TestRecover_panicAfterPartialWriteKeepsServerAlive(t *testing.T) {
@@ -0,0 +1,88 @@
+package via
+
+// checkNesting logs every element nested where HTML forbids it — a <div>
+// inside a <p>, a <button> inside an <a>, a <form> inside a <form> — once
+// per route and pair. The browser repairs such markup as it parses, so its
+// DOM no longer matches the view and morphs land on the wrong elements.
+// WithMarkupChecks only.
+func (a *App) checkNesting(ctx *Ctx, doc string) {
+	for _, n := range invalidNesting(doc) {
+		key := ctx.desc.route + "\x00nest\x00" + n.child + "\x00" + n.parent
+		if _, seen := a.dupIDSeen.LoadOrStore(key, struct{}{}); seen {
+			continue
+		}
+		a.logWarn(ctx, "invalid nesting on %s: <%s> inside <%s> (%s); the browser "+
+			"re-parents it, so the page no longer matches the view and patches can "+
+			"miss", ctx.desc.route, n.child, n.parent, n.why)
+	}
+}
+
+// nesting is one element placed inside an ancestor that can't hold it.
+type nesting struct {
+	child, parent, why string
+}
+
+// closesP are the elements whose start tag ends an open <p>.
+var closesP = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"details": true, "dialog": true, "div": true, "dl": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hgroup": true, "hr": true, "main": true, "menu": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "ul": true,
+}
+
+// interactive are the elements that can't sit inside an <a> or <button>.
+var interactive = map[string]bool{
+	"a": true, "button": true, "details": true, "embed": true, "iframe": true,
+	"input": true, "label": true, "select": true, "textarea": true,
+}
+
+// invalidNesting returns the forbidden placements in doc, in document
+// order, on the scanHTML walk the other markup checks share.
+func invalidNesting(doc string) []nesting {
+	var (
+		out  []nesting
+		open []string
+	)
+	inside := func(tag string) bool {
+		for _, t := range open {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+	scanHTML(doc, func(t htmlToken) {
+		switch t.kind {
+		case tokenClose:
+			for k := len(open) - 1; k >= 0; k-- {
+				if open[k] == t.tag {
+					open = open[:k]
+					break
+				}
+			}
+			return
+		case tokenText:
+			return
+		}
+		switch tag := t.tag; {
+		case closesP[tag] && inside("p"):
+			out = append(out, nesting{tag, "p", "a paragraph holds only phrasing content"})
+		case tag == "form" && inside("form"):
+			out = append(out, nesting{tag, "form", "forms don't nest"})
+		case interactive[tag] && !(tag == "input" && t.attrs["type"] == "hidden"):
+			for _, p := range []string{"a", "button"} {
+				if inside(p) {
+					out = append(out, nesting{tag, p, "interactive content can't nest"})
+					break
+				}
+			}
+		}
+		if !t.leaf {
+			open = append(open, t.tag)
+		}
+	})
+	return out
+}
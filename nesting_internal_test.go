@@ -0,0 +1,35 @@
+package via
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidNesting_reportsForbiddenPlacements(t *testing.T) {
+	t.Parallel()
+
+	doc := `<p>intro <span><div>block</div></span></p>` +
+		`<a href="/x"><button>go</button></a>` +
+		`<button><input type="hidden" name="t"><a href="/y">y</a></button>` +
+		`<form><div><form></form></div></form>`
+
+	got := invalidNesting(doc)
+	assert.Equal(t, []nesting{
+		{"div", "p", "a paragraph holds only phrasing content"},
+		{"button", "a", "interactive content can't nest"},
+		{"a", "button", "interactive content can't nest"},
+		{"form", "form", "forms don't nest"},
+	}, got)
+}
+
+func TestInvalidNesting_acceptsValidMarkup(t *testing.T) {
+	t.Parallel()
+
+	doc := `<div><p>a <em>b</em></p><p>c</p></div><label>Name <input name="n"></label>` +
+		`<a href="/"><span>home</span><img src="/i.png" alt=""></a>` +
+		`<form><button>send</button></form><form></form>` +
+		`<script>document.write('<p><div></div></p>')</script><!-- <a><a></a></a> -->`
+
+	assert.Empty(t, invalidNesting(doc))
+}
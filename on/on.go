@@ -5,6 +5,7 @@
 //	h.Form(h.Input(...), on.Submit(c.Save))
 //	h.Input(on.Input(c.Filter, on.Debounce("200ms")))
 //	h.Div(on.Key("Enter", c.Send))
+//	h.Div(on.Hotkey("ctrl+k", c.OpenPalette))
 //
 // Pass a bound method value of signature `func(*via.Ctx) error` or
 // `func(*via.Ctx)` (drop the error when nothing in the body can fail).
@@ -14,7 +15,9 @@ package on
 
 import (
 	"encoding/json"
+	"fmt"
 	"html/template"
+	"net/url"
 	"reflect"
 	"runtime"
 	"strings"
@@ -48,6 +51,19 @@ var eventAttrCache = map[string]string{
 	"mouseenter": "on:mouseenter",
 	"mouseleave": "on:mouseleave",
 	"load":       "on:load",
+	"scroll":     "on:scroll",
+	"scrollend":  "on:scrollend",
+	// Not a DOM event: datastar's IntersectionObserver plugin is its own
+	// attribute (data-on-intersect), so the name is not "on:" + event.
+	"intersect": "on-intersect",
+}
+
+// eventAttr returns the attribute name (after "data-") for an event.
+func eventAttr(name string) string {
+	if attr, ok := eventAttrCache[name]; ok {
+		return attr
+	}
+	return "on:" + name
 }
 
 // Click binds a click handler.
@@ -81,6 +97,24 @@ func MouseLeave[F via.Action](fn F, opts ...Option) h.H {
 	return event("mouseleave", fn, opts...)
 }
 
+// Scroll binds a scroll handler. Scroll fires at frame rate; pair it with
+// on.Throttle to bound the POST rate.
+func Scroll[F via.Action](fn F, opts ...Option) h.H { return event("scroll", fn, opts...) }
+
+// ScrollEnd binds a scrollend handler: it fires once the user (or a smooth
+// scroll) has stopped scrolling, the cheap way to persist a scroll position.
+func ScrollEnd[F via.Action](fn F, opts ...Option) h.H { return event("scrollend", fn, opts...) }
+
+// Intersect fires the action when the element enters the viewport — lazy
+// loading, "seen" receipts, infinite scroll off a sentinel at the list end:
+//
+//	h.Div(h.ID("more"), on.Intersect(p.LoadMore))
+//
+// By default any visible pixel counts; on.Half and on.Full raise the
+// threshold, on.Exit fires on leaving instead, and on.Once disconnects the
+// observer after the first fire.
+func Intersect[F via.Action](fn F, opts ...Option) h.H { return event("intersect", fn, opts...) }
+
 // Load fires the action once when Datastar evaluates the attribute on
 // the element — useful for kicking off a refresh as soon as a fragment
 // appears in the DOM:
@@ -117,6 +151,91 @@ func Key[F via.Action](key string, fn F, opts ...Option) h.H {
 	return render(spec)
 }
 
+// Hotkey binds a page-wide keyboard shortcut: a window-level keydown
+// listener that fires the action when the combination matches and
+// suppresses the browser's default for it (ctrl+k no longer focuses the
+// address bar). Place it anywhere in the view:
+//
+//	h.Div(on.Hotkey("ctrl+k", p.OpenPalette), on.Hotkey("escape", p.Close))
+//
+// combo is "+"-joined modifiers then a key, case-insensitive. Modifiers are
+// ctrl, shift, alt, meta, and mod (meta on macOS, ctrl elsewhere); the key
+// is a character or a W3C key name ("escape", "arrowup", "/"). ctrl, alt
+// and meta match exactly, so "ctrl+k" ignores ctrl+alt+k. shift is matched
+// exactly for named keys and letters; for punctuation it is left to the
+// character itself, so "?" works without spelling out shift. A combo with
+// no ctrl/alt/meta/mod skips keystrokes typed into inputs, textareas,
+// selects and contenteditable elements, so "/" to search doesn't hijack
+// typing. Panics on an unknown modifier or an empty key.
+func Hotkey[F via.Action](combo string, fn F, opts ...Option) h.H {
+	spec := &spec.Trigger{
+		Event:  "keydown",
+		Method: fn,
+		Guard:  hotkeyGuard(combo),
+	}
+	windowFn(spec)
+	for _, o := range opts {
+		o(spec)
+	}
+	return render(spec)
+}
+
+// hotkeyGuard compiles a Hotkey combo into the JS condition that matches
+// it. preventDefault runs inside the guard rather than as a __prevent
+// modifier, which would cancel every keystroke on the page.
+func hotkeyGuard(combo string) string {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(combo)), "+")
+	key := parts[len(parts)-1]
+	if key == "" && len(parts) > 1 {
+		// "ctrl++" — the key itself is a plus.
+		key, parts = "+", parts[:len(parts)-1]
+	}
+	if key == "" {
+		panic(fmt.Sprintf("on: Hotkey(%q) has no key", combo))
+	}
+	mods := map[string]bool{}
+	for _, m := range parts[:len(parts)-1] {
+		switch m {
+		case "ctrl", "shift", "alt", "meta", "mod":
+			mods[m] = true
+		default:
+			panic(fmt.Sprintf("on: Hotkey(%q) has unknown modifier %q (want ctrl, shift, alt, meta or mod)", combo, m))
+		}
+	}
+
+	var g strings.Builder
+	if mods["mod"] {
+		// Exactly one of ctrl/meta, so mod+k doesn't also catch ctrl+meta+k.
+		g.WriteString("evt.ctrlKey!==evt.metaKey&&")
+	} else {
+		for _, m := range [...]string{"ctrl", "meta"} {
+			if !mods[m] {
+				g.WriteByte('!')
+			}
+			g.WriteString("evt." + m + "Key&&")
+		}
+	}
+	if !mods["alt"] {
+		g.WriteByte('!')
+	}
+	g.WriteString("evt.altKey&&")
+	if len([]rune(key)) > 1 || strings.ContainsAny(key, "abcdefghijklmnopqrstuvwxyz") || mods["shift"] {
+		if !mods["shift"] {
+			g.WriteByte('!')
+		}
+		g.WriteString("evt.shiftKey&&")
+	}
+	if !mods["ctrl"] && !mods["meta"] && !mods["mod"] && !mods["alt"] {
+		g.WriteString("!evt.target.closest('input,textarea,select')&&!evt.target.isContentEditable&&")
+	}
+	// json.Marshal of a string cannot fail.
+	encoded, _ := json.Marshal(key)
+	g.WriteString("evt.key.toLowerCase()===")
+	g.Write(encoded)
+	g.WriteString("&&(evt.preventDefault(),true)")
+	return g.String()
+}
+
 // Debounce returns a trigger option that debounces firing.
 func Debounce(d string) Option { return func(s *spec.Trigger) { s.Debounce = d } }
 
@@ -132,6 +251,9 @@ var (
 	onceFn    Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "once") }
 	outsideFn Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "outside") }
 	windowFn  Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "window") }
+	halfFn    Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "half") }
+	fullFn    Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "full") }
+	exitFn    Option = func(s *spec.Trigger) { s.Modifiers = append(s.Modifiers, "exit") }
 )
 
 // Prevent calls e.preventDefault() before invoking the action.
@@ -151,6 +273,16 @@ func Outside() Option { return outsideFn }
 // handler fires for the event anywhere on the page (e.g. global shortcuts).
 func Window() Option { return windowFn }
 
+// Half makes [Intersect] fire only once half the element is visible.
+func Half() Option { return halfFn }
+
+// Full makes [Intersect] fire only once the whole element is visible.
+func Full() Option { return fullFn }
+
+// Exit makes [Intersect] fire when the element leaves the viewport rather
+// than when it enters.
+func Exit() Option { return exitFn }
+
 // Confirm gates the action behind a browser confirm() dialog: the @post
 // fires only if the user accepts. message is JSON-encoded so arbitrary
 // text is safe inside the generated JS.
@@ -195,6 +327,55 @@ func SetSignal[T any](sig *via.Signal[T], value T) Option {
 	return func(s *spec.Trigger) { s.AppendPre(stmt) }
 }
 
+// Arg binds a named argument to this trigger; the action reads it with
+// ctx.EventArg(name). Use it to tell which list item or key fired a shared
+// action without declaring a signal per element:
+//
+//	h.Button(h.Text("Delete"), on.Click(p.Delete, on.Arg("id", item.ID)))
+//
+// value is formatted with fmt.Sprint and rides on the action URL, so keep
+// it short — an id, not a payload. Repeat for several arguments.
+func Arg(name string, value any) Option {
+	pair := url.QueryEscape(name) + "=" + url.QueryEscape(fmt.Sprint(value))
+	return func(s *spec.Trigger) {
+		if s.Args != "" {
+			s.Args += "&"
+		}
+		s.Args += pair
+	}
+}
+
+// Optimistic writes value to sig client-side the moment the trigger fires,
+// so the UI reflects the expected outcome before the server answers — a
+// disabled button, a spinner, a checked "liked" heart. With Saving a
+// via.SignalBool:
+//
+//	h.Button(h.Text("Save"), c.Saving.Attr("disabled"),
+//	    on.Click(c.Save, on.Optimistic(&c.Saving.Signal, true)),
+//	)
+//
+// The server stays authoritative: an action that writes sig confirms (or
+// corrects) the value; one that does not — including one that fails —
+// reverts sig to its pre-fire server value in the same frame as the
+// action's other patches, so the hint clears exactly when the result
+// lands. Unlike [Indicator], which flips back when the POST settles, the
+// hint never drops before the re-render reaches the page.
+//
+// sig must be a Signal[T] handle bound at Mount; value is JSON-encoded
+// into the rendered JS expression.
+func Optimistic[T any](sig *via.Signal[T], value T) Option {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic("on.Optimistic: signal " + sig.Key() + " value cannot be JSON-encoded: " + err.Error())
+	}
+	key := sig.Key()
+	stmt := "$" + key + "=" + string(encoded)
+	return func(s *spec.Trigger) {
+		s.AppendPre(stmt)
+		s.Optimistic = append(s.Optimistic, key)
+	}
+}
+
 // notMethodPanic builds the panic text for an on.* helper that received
 // something other than a bound method value. Splitting nil / top-level
 // function / closure makes the most common authoring mistake debuggable
@@ -294,10 +475,7 @@ func bareAttr(eventName, method string) h.H {
 	if ok {
 		return cached
 	}
-	attr, ok := eventAttrCache[eventName]
-	if !ok {
-		attr = "on:" + eventName
-	}
+	attr := eventAttr(eventName)
 	expr := "@post('/_action/" + method + "')"
 	// Pre-render: leading space + data-on:... + ="<escaped expr>". Matches
 	// the renderer's attribute output byte-for-byte.
@@ -329,27 +507,29 @@ func render(s *spec.Trigger) h.H {
 	// key filter, no debounce/throttle, no pre statements. By far the
 	// common case; skipping two strings.Builder allocations per render
 	// per binding adds up across a moderately interactive view.
-	if len(s.Pre) == 0 && len(s.Modifiers) == 0 &&
-		s.KeyFilter == "" && s.Debounce == "" && s.Throttle == "" && s.Confirm == "" {
+	if len(s.Pre) == 0 && len(s.Modifiers) == 0 && len(s.Optimistic) == 0 && s.Args == "" &&
+		s.KeyFilter == "" && s.Guard == "" && s.Debounce == "" && s.Throttle == "" && s.Confirm == "" {
 		return bareAttr(s.Event, method)
 	}
 
 	var attr strings.Builder
-	attr.WriteString("on:")
-	attr.WriteString(s.Event)
-	// KeyFilter is NOT an attribute modifier: datastar v1 has no keyboard-key
-	// modifier, so `on:keydown.Enter` would fire on every keystroke. The filter
-	// is applied as an evt.key expression guard below instead.
+	attr.WriteString(eventAttr(s.Event))
+	// Datastar splits modifiers off the attribute name on "__" and a
+	// modifier's arguments on ".": on:input__debounce.200ms__stop. A dotted
+	// on:input.debounce.200ms is read as one unknown key and silently
+	// ignored. KeyFilter is NOT an attribute modifier: datastar v1 has no
+	// keyboard-key modifier, so it is applied as an evt.key expression
+	// guard below instead.
 	for _, m := range s.Modifiers {
-		attr.WriteByte('.')
+		attr.WriteString("__")
 		attr.WriteString(m)
 	}
 	if s.Debounce != "" {
-		attr.WriteString(".debounce.")
+		attr.WriteString("__debounce.")
 		attr.WriteString(s.Debounce)
 	}
 	if s.Throttle != "" {
-		attr.WriteString(".throttle.")
+		attr.WriteString("__throttle.")
 		attr.WriteString(s.Throttle)
 	}
 
@@ -365,6 +545,10 @@ func render(s *spec.Trigger) h.H {
 		expr.WriteString(s.KeyFilter)
 		expr.WriteString("'&&")
 	}
+	if s.Guard != "" {
+		expr.WriteString(s.Guard)
+		expr.WriteString("&&")
+	}
 	if s.Confirm != "" {
 		expr.WriteString("confirm(")
 		expr.WriteString(s.Confirm)
@@ -372,7 +556,23 @@ func render(s *spec.Trigger) h.H {
 	}
 	expr.WriteString("@post('/_action/")
 	expr.WriteString(method)
-	expr.WriteString("')")
+	if s.Args != "" {
+		// QueryEscape leaves no quote or backslash, so the pairs are safe
+		// inside the single-quoted JS string.
+		expr.WriteByte('?')
+		expr.WriteString(s.Args)
+	}
+	expr.WriteByte('\'')
+	if len(s.Optimistic) > 0 {
+		// Wire keys are Go identifiers joined by dots — safe inside a JS
+		// single-quoted string without escaping.
+		expr.WriteString(",{headers:{'")
+		expr.WriteString(spec.OptimisticHeader)
+		expr.WriteString("':'")
+		expr.WriteString(strings.Join(s.Optimistic, ","))
+		expr.WriteString("'}}")
+	}
+	expr.WriteByte(')')
 	// Emit pre-escaped bytes so Render writes them verbatim — same trick
 	// as bareAttr. The optioned path is non-cached (every spec.Trigger
 	// shape is bespoke), but skipping per-render escaping still wins
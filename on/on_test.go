@@ -2,8 +2,10 @@ package on_test
 
 import (
 	"bytes"
+	"html"
 	"io"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-via/via"
@@ -81,10 +83,10 @@ func TestOn_modifiersAppendToTrigger(t *testing.T) {
 	cases := []struct {
 		name, needle, why string
 	}{
-		{"debounce", "on:input.debounce.200ms", "Debounce should append .debounce.<dur>"},
-		{"throttle", "on:input.throttle.500ms", "Throttle should append .throttle.<dur>"},
-		{"prevent", "on:submit.prevent", "Prevent should append .prevent"},
-		{"stop", "on:click.stop", "Stop should append .stop"},
+		{"debounce", "on:input__debounce.200ms", "Debounce should append __debounce.<dur>"},
+		{"throttle", "on:input__throttle.500ms", "Throttle should append __throttle.<dur>"},
+		{"prevent", "on:submit__prevent", "Prevent should append __prevent"},
+		{"stop", "on:click__stop", "Stop should append __stop"},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -121,9 +123,9 @@ func TestOn_lifecycleModifiersAppendToTrigger(t *testing.T) {
 	cases := []struct {
 		name, needle, why string
 	}{
-		{"once", "on:click.once", "Once should append .once"},
-		{"outside", "on:click.outside", "Outside should append .outside"},
-		{"window", "on:click.window", "Window should append .window"},
+		{"once", "on:click__once", "Once should append __once"},
+		{"outside", "on:click__outside", "Outside should append __outside"},
+		{"window", "on:click__window", "Window should append __window"},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -262,6 +264,8 @@ func (p *eventCoveragePage) View(ctx *via.CtxR) h.H {
 		h.Div(on.MouseLeave(p.Hit)),
 		h.Div(on.Load(p.Hit)),
 		h.Div(on.Event("contextmenu", p.Hit)),
+		h.Div(on.Scroll(p.Hit, on.Throttle("100ms"))),
+		h.Div(on.ScrollEnd(p.Hit)),
 	)
 }
 
@@ -281,6 +285,7 @@ func TestOn_NamedEventHelpersRenderExpectedTriggers(t *testing.T) {
 		"on:mouseenter", "on:mouseleave",
 		"on:load",
 		"on:contextmenu",
+		"on:scroll__throttle.100ms", "on:scrollend",
 	} {
 		assert.Contains(t, body, want,
 			"each named helper / on.Event must emit on:<name> attribute")
@@ -470,3 +475,146 @@ func getBody(t *testing.T, server *httptest.Server, path string) string {
 	buf, _ := io.ReadAll(resp.Body)
 	return string(buf)
 }
+
+type optimisticPage struct {
+	Saving via.SignalBool `via:"saving"`
+}
+
+func (p *optimisticPage) Save(ctx *via.Ctx) {}
+
+func (p *optimisticPage) View(ctx *via.CtxR) h.H {
+	return h.Button(h.Text("Save"), on.Click(p.Save, on.Optimistic(&p.Saving.Signal, true)))
+}
+
+func TestOptimistic_writesSignalAndNamesItInHeader(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[optimisticPage](app, "/")
+
+	body := getBody(t, server, "/")
+	assert.Contains(t, body,
+		`$saving=true;@post(&#39;/_action/Save&#39;,{headers:{&#39;Via-Optimistic&#39;:&#39;saving&#39;}})`)
+}
+
+type argPage struct{}
+
+func (p *argPage) Delete(ctx *via.Ctx) {}
+
+func (p *argPage) View(ctx *via.CtxR) h.H {
+	return h.Button(h.Text("x"), on.Click(p.Delete, on.Arg("id", 42), on.Arg("name", "a b&c")))
+}
+
+func TestArg_appendsEscapedQueryToActionURL(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[argPage](app, "/")
+
+	body := getBody(t, server, "/")
+	assert.Contains(t, body, `@post(&#39;/_action/Delete?id=42&amp;name=a+b%26c&#39;)`)
+}
+
+type intersectPage struct{}
+
+func (p *intersectPage) LoadMore(ctx *via.Ctx) {}
+
+func (p *intersectPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.Div(h.ID("bare"), on.Intersect(p.LoadMore)),
+		h.Div(h.ID("opts"), on.Intersect(p.LoadMore, on.Once(), on.Half())),
+		h.Div(h.ID("generic"), on.Event("intersect", p.LoadMore, on.Exit())),
+	)
+}
+
+func TestIntersect_rendersDatastarIntersectPlugin(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[intersectPage](app, "/")
+
+	body := getBody(t, server, "/")
+	assert.Contains(t, body, `id="bare" data-on-intersect="@post(&#39;/_action/LoadMore&#39;)"`)
+	assert.Contains(t, body, `data-on-intersect__once__half=`)
+	assert.Contains(t, body, `data-on-intersect__exit=`)
+	assert.NotContains(t, body, "on:intersect", "intersect is an attribute plugin, not a DOM event")
+}
+
+type hotkeyPage struct{}
+
+func (p *hotkeyPage) Palette(ctx *via.Ctx) {}
+
+func (p *hotkeyPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.Div(h.ID("ctrl"), on.Hotkey("Ctrl+K", p.Palette)),
+		h.Div(h.ID("bare"), on.Hotkey("/", p.Palette)),
+		h.Div(h.ID("mod"), on.Hotkey("mod+shift+p", p.Palette)),
+	)
+}
+
+func TestHotkey_bindsWindowKeydownWithComboGuard(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[hotkeyPage](app, "/")
+
+	body := getBody(t, server, "/")
+	assert.Contains(t, body, `id="ctrl" data-on:keydown__window="evt.ctrlKey&amp;&amp;!evt.metaKey&amp;&amp;!evt.altKey&amp;&amp;!evt.shiftKey&amp;&amp;evt.key.toLowerCase()===&#34;k&#34;&amp;&amp;(evt.preventDefault(),true)&amp;&amp;@post(&#39;/_action/Palette&#39;)"`)
+	assert.Contains(t, body, `!evt.target.closest(&#39;input,textarea,select&#39;)&amp;&amp;!evt.target.isContentEditable&amp;&amp;evt.key.toLowerCase()===&#34;/&#34;`,
+		"a bare-key hotkey must not fire while the user types into a field")
+	assert.Contains(t, body, `evt.ctrlKey!==evt.metaKey&amp;&amp;!evt.altKey&amp;&amp;evt.shiftKey&amp;&amp;evt.key.toLowerCase()===&#34;p&#34;`)
+	assert.NotContains(t, body, "keydown__window__prevent",
+		"a __prevent modifier would cancel every keystroke on the page")
+}
+
+func TestHotkey_panicsOnUnknownModifier(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t,
+		`on: Hotkey("cmd+k") has unknown modifier "cmd" (want ctrl, shift, alt, meta or mod)`,
+		func() { on.Hotkey("cmd+k", (&hotkeyPage{}).Palette) })
+	assert.Panics(t, func() { on.Hotkey("", (&hotkeyPage{}).Palette) })
+}
+
+type sortPage struct{}
+
+func (p *sortPage) Reorder(ctx *via.Ctx) {}
+
+func (p *sortPage) View(ctx *via.CtxR) h.H {
+	return h.Ul(h.ID("list"), on.Sortable(p.Reorder, on.Arg("col", "todo")),
+		h.Li(on.SortItem(`a"b`), h.Text("a")),
+	)
+}
+
+func TestSortable_bindsDragListenersAndPostsOrder(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[sortPage](app, "/")
+
+	body := getBody(t, server, "/")
+	for _, ev := range []string{"dragstart", "dragover", "drop", "dragend"} {
+		assert.Contains(t, body, ` data-on:`+ev+`="`)
+	}
+	assert.Contains(t, body, `@post(&#39;/_action/Reorder?col=todo&amp;&#39; + o.map((i) =&gt; &#39;order=&#39; + encodeURIComponent(i))`,
+		"bound args lead the query, then one order= pair per item")
+	assert.Contains(t, body, `<li draggable="true" data-via-sort-id="a&#34;b">`, "item ids are attribute-escaped")
+
+	var attr strings.Builder
+	require.NoError(t, on.Sortable((&sortPage{}).Reorder).Render(&attr))
+	assert.NotContains(t, html.UnescapeString(attr.String()), ";",
+		"datastar splits expressions on ';' — the listeners must be single expressions")
+}
+
+func TestSortable_panicsOnAnonymousFunction(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t,
+		"on: sortable requires a bound method value (e.g. on.Click(c.Inc)); got a closure",
+		func() { on.Sortable(func(ctx *via.Ctx) {}) })
+}
@@ -0,0 +1,108 @@
+package on
+
+import (
+	_ "embed"
+	"html/template"
+	"strings"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/internal/spec"
+)
+
+// SortOrderArg is the action argument that carries a [Sortable] list's new
+// order: one value per item, top to bottom. Read it with Ctx.EventArgs.
+const SortOrderArg = "order"
+
+// sortableJS holds the drag listeners Sortable binds, kept as a script
+// file so it can be syntax-checked and linted like one.
+//
+//go:embed sortable.js
+var sortableJS string
+
+// sortListener is one of sortable.js's handlers as a datastar expression.
+type sortListener struct {
+	event, expr string
+}
+
+var sortListeners = parseSortListeners(sortableJS)
+
+// parseSortListeners picks the `const <event> = (…) =>` arrows out of src,
+// joining each body's trimmed lines up to the one that ends in ';'.
+func parseSortListeners(src string) []sortListener {
+	var (
+		out  []sortListener
+		cur  *sortListener
+		body strings.Builder
+	)
+	for line := range strings.Lines(src) {
+		line = strings.TrimSpace(line)
+		if cur == nil {
+			decl, ok := strings.CutPrefix(line, "const ")
+			if !ok {
+				continue
+			}
+			event, _, _ := strings.Cut(decl, " ")
+			_, line, _ = strings.Cut(decl, "=>")
+			cur = &sortListener{event: event}
+			line = strings.TrimSpace(line)
+		}
+		body.WriteString(line)
+		if expr, done := strings.CutSuffix(body.String(), ";"); done {
+			cur.expr = expr
+			out = append(out, *cur)
+			cur = nil
+			body.Reset()
+		}
+	}
+	return out
+}
+
+// Sortable makes the element's [SortItem] children drag-and-drop
+// reorderable. Items move live under the pointer; on drop the action fires
+// with the new order as repeated "order" arguments, and only if the order
+// actually changed:
+//
+//	h.Ul(on.Sortable(p.Reorder),
+//	    h.Each(p.Tasks, func(t Task) h.H {
+//	        return h.Li(on.SortItem(t.ID), h.Text(t.Title))
+//	    }),
+//	)
+//
+//	func (p *Board) Reorder(ctx *via.Ctx) { p.store.Reorder(ctx.EventArgs(on.SortOrderArg)) }
+//
+// The server owns the order: persist it in the action and let the re-render
+// confirm it. A row-direction flex container sorts horizontally, anything
+// else vertically. [Arg] options ride along with the order (e.g. the column
+// id on a kanban board); other options are ignored.
+func Sortable[F via.Action](fn F, opts ...Option) h.H {
+	s := &spec.Trigger{Event: "dragend", Method: fn}
+	for _, o := range opts {
+		o(s)
+	}
+	method := spec.MethodName(s.Method)
+	if method == "" {
+		panic(notMethodPanic("sortable", s.Method))
+	}
+	query := "?"
+	if s.Args != "" {
+		query += s.Args + "&"
+	}
+
+	post := strings.NewReplacer("post(ACTION", "@post('/_action/"+method+query+"'")
+	var buf strings.Builder
+	for _, l := range sortListeners {
+		buf.WriteString(" data-on:")
+		buf.WriteString(l.event)
+		buf.WriteString(`="`)
+		buf.WriteString(template.HTMLEscapeString(post.Replace(l.expr)))
+		buf.WriteByte('"')
+	}
+	return h.RawAttr([]byte(buf.String()))
+}
+
+// SortItem marks an element as a draggable item of the enclosing
+// [Sortable] list; id is what the action receives in the new order.
+func SortItem(id string) h.H {
+	return h.RawAttr([]byte(` draggable="true" data-via-sort-id="` + template.HTMLEscapeString(id) + `"`))
+}
@@ -0,0 +1,46 @@
+// The Datastar expressions behind on.Sortable, one per drag event on the
+// list element. sortable.go splices each arrow's body into a
+// data-on:<event> attribute, its lines joined with nothing between them,
+// so every body must stay one expression: datastar splits on ';' and
+// keeps the last piece. `el` and `evt` are the element and event datastar
+// binds. post(ACTION + …) stands for the action's @post: ACTION becomes
+// its URL with the bound query and a trailing '&', and 'order=' must
+// match on.SortOrderArg. The drag state lives on the list element itself
+// (el._viaDrag, el._viaOrder).
+/* global post, ACTION */
+/* exported dragstart, dragover, drop, dragend */
+
+const dragstart = (el, evt) =>
+  ((d) => d && d.parentNode === el && (
+    el._viaDrag = d,
+    el._viaOrder = JSON.stringify([...el.children].map((c) => c.dataset.viaSortId).filter((i) => i !== undefined)),
+    evt.dataTransfer.effectAllowed = 'move',
+    evt.dataTransfer.setData('text/plain', '')
+  ))(evt.target.closest('[data-via-sort-id]'));
+
+// Moves the dragged item before or after the one under the pointer,
+// splitting it across the main axis: horizontally in a row-direction
+// flex list, vertically otherwise.
+const dragover = (el, evt) =>
+  el._viaDrag && (
+    evt.preventDefault(),
+    ((t) => t && t !== el._viaDrag && t.parentNode === el && ((r) => el.insertBefore(
+      el._viaDrag,
+      (((s) => s.display.endsWith('flex') && !s.flexDirection.startsWith('column'))(getComputedStyle(el))
+        ? evt.clientX > r.left + r.width / 2
+        : evt.clientY > r.top + r.height / 2)
+        ? t.nextSibling
+        : t
+    ))(t.getBoundingClientRect()))(evt.target.closest('[data-via-sort-id]'))
+  );
+
+const drop = (el, evt) => el._viaDrag && evt.preventDefault();
+
+// Posts the new order, one order= pair per item, unless it's unchanged.
+const dragend = (el) =>
+  el._viaDrag && (
+    el._viaDrag = null,
+    ((o) => JSON.stringify(o) !== el._viaOrder &&
+      post(ACTION + o.map((i) => 'order=' + encodeURIComponent(i)).join('&'))
+    )([...el.children].map((c) => c.dataset.viaSortId).filter((i) => i !== undefined))
+  );
@@ -0,0 +1,70 @@
+package via
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-via/via/internal/spec"
+)
+
+// maxOptimisticKeys caps how many keys one POST may name in the optimistic
+// header; a real trigger carries one or two.
+const maxOptimisticKeys = 16
+
+// optimisticSnap is a signal's server value captured before an action's
+// request body overwrote it with a client-side optimistic write.
+type optimisticSnap struct {
+	slot int
+	prev any
+}
+
+// snapshotOptimistic records the current server value of every signal the
+// request's on.Optimistic trigger wrote client-side. Must run before
+// injectSignals replaces those values with the optimistic ones. Unknown
+// keys and non-Signal slots are ignored.
+func snapshotOptimistic(ctx *Ctx, r *http.Request) []optimisticSnap {
+	hdr := r.Header.Get(spec.OptimisticHeader)
+	if hdr == "" {
+		return nil
+	}
+	keys := strings.SplitN(hdr, ",", maxOptimisticKeys+1)
+	if len(keys) > maxOptimisticKeys {
+		keys = keys[:maxOptimisticKeys]
+	}
+	var snaps []optimisticSnap
+	for _, key := range keys {
+		for slot, s := range ctx.desc.signalSlots {
+			if s.kind != kindSignal || s.wireKey != key {
+				continue
+			}
+			b, err := ctx.signalRefs[slot].encode()
+			if err != nil {
+				break
+			}
+			var prev any
+			if json.Unmarshal(b, &prev) == nil {
+				snaps = append(snaps, optimisticSnap{slot: slot, prev: prev})
+			}
+			break
+		}
+	}
+	return snaps
+}
+
+// restoreOptimistic reverts every snapshotted signal the action did not
+// write, marking it dirty so the revert ships in the action's own frame.
+// A signal the action wrote keeps the action's value.
+func restoreOptimistic(ctx *Ctx, snaps []optimisticSnap) {
+	for _, snap := range snaps {
+		ctx.queue.mu.Lock()
+		written := ctx.dirtySignals.get(snap.slot)
+		ctx.queue.mu.Unlock()
+		if written {
+			continue
+		}
+		if ctx.signalRefs[snap.slot].decodeRaw(snap.prev) == nil {
+			ctx.markSignalDirty(uint16(snap.slot))
+		}
+	}
+}
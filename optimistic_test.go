@@ -0,0 +1,85 @@
+package via_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+type optimisticPage struct {
+	Saving via.SignalBool `via:"saving"`
+	Liked  via.SignalBool `via:"liked"`
+}
+
+func (p *optimisticPage) Save(ctx *via.Ctx) {}
+
+func (p *optimisticPage) Like(ctx *via.Ctx) { p.Liked.Write(ctx, true) }
+
+func (p *optimisticPage) Fail(ctx *via.Ctx) error { return errors.New("nope") }
+
+func (p *optimisticPage) View(ctx *via.CtxR) h.H { return h.Div(h.ID("opt")) }
+
+func TestOptimistic_revertsSignalTheActionLeftAlone(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[optimisticPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Save").WithSignal("saving", true).WithHeader("Via-Optimistic", "saving").Fire()
+
+	vt.AwaitFrame(t, frames, 2*time.Second, `"saving":false`)
+}
+
+func TestOptimistic_keepsValueTheActionWrote(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[optimisticPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Like").WithSignal("liked", true).WithHeader("Via-Optimistic", "liked").Fire()
+
+	vt.AwaitFrame(t, frames, 2*time.Second, `"liked":true`)
+}
+
+func TestOptimistic_revertsOnActionError(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[optimisticPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Fail").WithSignal("liked", true).WithHeader("Via-Optimistic", "liked").Fire()
+
+	vt.AwaitFrame(t, frames, 2*time.Second, `"liked":false`)
+}
+
+func TestOptimistic_ignoresSignalsWithoutHeader(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[optimisticPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Save").WithSignal("saving", true).Fire()
+
+	assert.NotContains(t, drainFor(frames, 200*time.Millisecond), `"saving"`)
+}
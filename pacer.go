@@ -0,0 +1,49 @@
+package via
+
+import "time"
+
+// framePacer adapts one SSE connection's frame rate to how fast its client
+// drains it. A frame write returns as soon as the bytes fit in the socket
+// buffer, so on a fast peer it costs microseconds; a slow one (mobile, a
+// congested link) fills the buffer and every write blocks until the peer
+// reads. The pacer keeps a moving average of that write time and spaces
+// frames pacerFactor times further apart, so a slow connection spends at
+// most about a quarter of its time writing. Patches that arrive inside the
+// gap coalesce in the queue — last-wins renders, merged signals — and ship
+// as one frame, instead of piling up behind a socket that can't keep up.
+type framePacer struct {
+	max  time.Duration // interval ceiling; 0 disables pacing
+	avg  time.Duration // moving average of frame write time
+	seen bool
+}
+
+const (
+	// pacerFactor is the gap between frames as a multiple of write time.
+	pacerFactor = 3
+	// pacerFloor is the smallest gap worth enforcing: below it the client
+	// keeps up and gets every frame at full rate.
+	pacerFloor = 5 * time.Millisecond
+)
+
+// observe folds one frame's write duration into the average (weight 1/4,
+// so a single stall doesn't throttle the stream and a recovered client
+// regains full rate within a few frames).
+func (p *framePacer) observe(write time.Duration) {
+	if !p.seen {
+		p.avg, p.seen = write, true
+		return
+	}
+	p.avg += (write - p.avg) / 4
+}
+
+// interval is the minimum gap to hold before the next frame.
+func (p *framePacer) interval() time.Duration {
+	if p.max <= 0 {
+		return 0
+	}
+	gap := p.avg * pacerFactor
+	if gap < pacerFloor {
+		return 0
+	}
+	return min(gap, p.max)
+}
@@ -0,0 +1,59 @@
+package via
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFramePacer_fastClientRunsAtFullRate(t *testing.T) {
+	t.Parallel()
+
+	p := framePacer{max: time.Second}
+	for range 10 {
+		p.observe(50 * time.Microsecond)
+	}
+	assert.Zero(t, p.interval(), "writes that never block must not be paced")
+}
+
+func TestFramePacer_slowClientIsSpacedOutAndCapped(t *testing.T) {
+	t.Parallel()
+
+	p := framePacer{max: time.Second}
+	p.observe(40 * time.Millisecond)
+	assert.Equal(t, 120*time.Millisecond, p.interval(),
+		"the gap is pacerFactor times the write time")
+
+	for range 20 {
+		p.observe(2 * time.Second)
+	}
+	assert.Equal(t, time.Second, p.interval(), "the gap never exceeds max")
+}
+
+func TestFramePacer_recoversOnceWritesSpeedUp(t *testing.T) {
+	t.Parallel()
+
+	p := framePacer{max: time.Second}
+	p.observe(100 * time.Millisecond)
+	assert.Positive(t, p.interval())
+
+	for range 20 {
+		p.observe(100 * time.Microsecond)
+	}
+	assert.Zero(t, p.interval(), "a client that catches up regains full rate")
+}
+
+func TestFramePacer_zeroMaxDisablesPacing(t *testing.T) {
+	t.Parallel()
+
+	p := framePacer{}
+	p.observe(time.Second)
+	assert.Zero(t, p.interval())
+}
+
+func TestWithSSEAdaptiveRate_isOffByDefault(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, New().cfg.sseMaxFrameGap, "pacing must be opt-in")
+	assert.Equal(t, time.Second, New(WithSSEAdaptiveRate(time.Second)).cfg.sseMaxFrameGap)
+}
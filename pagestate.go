@@ -0,0 +1,118 @@
+package via
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Scopes a state snapshot groups its fields under.
+const (
+	scopeSignals = "signals"
+	scopeTab     = "tab"
+	scopeSession = "session"
+	scopeApp     = "app"
+)
+
+// pageStater is implemented by every reactive handle (pointer receiver,
+// promoted through the shape wrappers) so WithStateSnapshots can read a
+// field without knowing its type parameter.
+type pageStater interface {
+	pageState(rc *CtxR) (scope string, v any)
+}
+
+func (s *Signal[T]) pageState(rc *CtxR) (string, any)    { return scopeSignals, s.Read(rc) }
+func (s *StateTab[T]) pageState(rc *CtxR) (string, any)  { return scopeTab, s.Read(rc) }
+func (s *StateSess[T]) pageState(rc *CtxR) (string, any) { return scopeSession, s.Read(rc) }
+func (a *StateApp[T]) pageState(rc *CtxR) (string, any)  { return scopeApp, a.Read(rc) }
+func (l *StateAppEvents[E, V]) pageState(rc *CtxR) (string, any) {
+	return scopeApp, l.Read(rc)
+}
+
+// stateSnapshot is the JSON body of a snapshot response.
+type stateSnapshot struct {
+	Route   string         `json:"route"`
+	Signals map[string]any `json:"signals"`
+	Tab     map[string]any `json:"tab"`
+	Session map[string]any `json:"session"`
+	App     map[string]any `json:"app"`
+}
+
+// wantsSnapshot reports whether a page GET should be answered with the
+// state snapshot: the option is on and the client asked for JSON rather
+// than HTML, so a browser (which always lists text/html) never gets it.
+func (a *App) wantsSnapshot(r *http.Request) bool {
+	if !a.cfg.stateSnapshots {
+		return false
+	}
+	wants := false
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, _ := strings.Cut(part, ";")
+		if acceptQ(params) == 0 {
+			continue // explicitly refused
+		}
+		switch strings.ToLower(strings.TrimSpace(mt)) {
+		case "text/html":
+			return false
+		case "application/json":
+			wants = true
+		}
+	}
+	return wants
+}
+
+// acceptQ returns the q weight in one Accept entry's parameters, 1 when
+// absent.
+func acceptQ(params string) float64 {
+	for _, p := range strings.Split(params, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if strings.EqualFold(k, "q") {
+			if q, err := strconv.ParseFloat(v, 64); err == nil {
+				return q
+			}
+		}
+	}
+	return 1
+}
+
+// writeStateSnapshot encodes every handle of cmpVal, grouped by scope and keyed
+// by wire key.
+func (a *App) writeStateSnapshot(w http.ResponseWriter, ctx *Ctx, cmpVal reflect.Value, d *cmpDescriptor) {
+	snap := stateSnapshot{
+		Route:   d.route,
+		Signals: map[string]any{},
+		Tab:     map[string]any{},
+		Session: map[string]any{},
+		App:     map[string]any{},
+	}
+	byScope := map[string]map[string]any{
+		scopeSignals: snap.Signals,
+		scopeTab:     snap.Tab,
+		scopeSession: snap.Session,
+		scopeApp:     snap.App,
+	}
+	rc := ctx.readView()
+	add := func(path []int, key string) {
+		if s, ok := fieldByPath(cmpVal.Elem(), path).Addr().Interface().(pageStater); ok {
+			scope, v := s.pageState(rc)
+			byScope[scope][key] = v
+		}
+	}
+	for _, s := range d.signalSlots {
+		add(s.fieldPath, s.wireKey)
+	}
+	for _, s := range d.scopeSlots {
+		add(s.fieldPath, s.wireKey)
+	}
+	body, err := json.Marshal(snap)
+	if err != nil {
+		a.logErr(ctx, "state snapshot: %v", err)
+		http.Error(w, "state snapshot is not JSON-encodable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -0,0 +1,85 @@
+package via_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inventoryPage struct {
+	SKU    string        `path:"sku"`
+	Filter via.SignalStr `via:"filter,init=all"`
+	Stock  via.StateTabNum[int]
+	Recent via.StateSessSlice[string]
+	Views  via.StateAppNum[int]
+}
+
+func (p *inventoryPage) OnInit(ctx *via.Ctx) error {
+	p.Stock.Write(ctx, 12)
+	return nil
+}
+
+func (p *inventoryPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Textf("%s: %d", p.SKU, p.Stock.Read(ctx)))
+}
+
+func getAccept(app *via.App, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStateSnapshot_jsonAcceptGetsPageState(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithStateSnapshots())
+	via.Mount[inventoryPage](app, "/items/{sku}")
+
+	rec := getAccept(app, "/items/ab-1", "application/json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Accept")
+
+	var snap struct {
+		Route   string         `json:"route"`
+		Signals map[string]any `json:"signals"`
+		Tab     map[string]any `json:"tab"`
+		Session map[string]any `json:"session"`
+		App     map[string]any `json:"app"`
+	}
+	body, _ := io.ReadAll(rec.Body)
+	require.NoError(t, json.Unmarshal(body, &snap), string(body))
+	assert.Equal(t, "/items/{sku}", snap.Route)
+	assert.Equal(t, "all", snap.Signals["filter"])
+	assert.EqualValues(t, 12, snap.Tab["stock"], "OnInit ran before the snapshot")
+	assert.Contains(t, snap.Session, "recent")
+	assert.EqualValues(t, 0, snap.App["views"])
+	assert.Empty(t, app.Contexts(), "a snapshot never registers a tab")
+}
+
+func TestStateSnapshot_browsersAndDefaultAppsGetHTML(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithStateSnapshots())
+	via.Mount[inventoryPage](app, "/items/{sku}")
+
+	rec := getAccept(app, "/items/ab-1", "text/html,application/xhtml+xml,application/json;q=0.9,*/*;q=0.8")
+	assert.Contains(t, rec.Body.String(), "<!doctype html>")
+	rec = getAccept(app, "/items/ab-1", "application/json;q=0, text/plain")
+	assert.Contains(t, rec.Body.String(), "ab-1: 12")
+
+	plain := via.New()
+	via.Mount[inventoryPage](plain, "/items/{sku}")
+	rec = getAccept(plain, "/items/ab-1", "application/json")
+	assert.Contains(t, rec.Body.String(), "ab-1: 12", "snapshots are opt-in")
+	assert.NotContains(t, rec.Header().Values("Vary"), "Accept")
+}
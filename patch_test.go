@@ -134,3 +134,20 @@ func TestPatch_ElementsEmitsVariadicElementMorphs(t *testing.T) {
 	vt.AwaitFrame(t, frames, 2*time.Second,
 		`<div id="a">first</div>`, `<div id="b">second</div>`)
 }
+
+type seededPage struct{}
+
+func (p *seededPage) OnInit(ctx *via.Ctx) error {
+	ctx.Patch().Signal("_density", 3)
+	return nil
+}
+
+func (p *seededPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestPatch_SignalBeforeTheDocumentSeedsIt(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[seededPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	require.Contains(t, tc.HTML(), "&#34;_density&#34;:3", "the first paint must have the pushed signal")
+}
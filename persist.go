@@ -0,0 +1,130 @@
+package via
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultPersistDebounce is the quiet period before a persisted StateTab
+// is saved when Persistence.Debounce is zero.
+const defaultPersistDebounce = 500 * time.Millisecond
+
+// Persistence bridges a [StateTab] with durable storage — a database row,
+// a file — so actions only ever touch the typed handle. Attach it with
+// [StateTab.Persist].
+type Persistence[T any] struct {
+	// Load fetches the stored value. ok false means there is none yet,
+	// and the handle keeps its initial value.
+	Load func(ctx *Ctx) (v T, ok bool, err error)
+	// Save stores v. It runs off the action, once writes have paused for
+	// Debounce, and once more when the tab goes away if a write is still
+	// pending; saves never overlap. An error is logged and counted as
+	// via.persist.error.
+	Save func(ctx *Ctx, v T) error
+	// Debounce is how long writes must pause before Save runs; 500ms
+	// when zero.
+	Debounce time.Duration
+}
+
+// Persist hydrates the handle from p.Load and has every later write
+// saved with p.Save. Call it once, from OnInit:
+//
+//	func (p *Editor) OnInit(ctx *via.Ctx) error {
+//	    return p.Draft.Persist(ctx, via.Persistence[string]{
+//	        Load: func(ctx *via.Ctx) (string, bool, error) { return drafts.Get(ctx.Request().Context(), p.DocID) },
+//	        Save: func(ctx *via.Ctx, s string) error { return drafts.Put(context.Background(), p.DocID, s) },
+//	    })
+//	}
+//
+// A Load error is returned as is, leaving the value alone; the initial
+// value doesn't count as a write, so it isn't saved back.
+func (s *StateTab[T]) Persist(ctx *Ctx, p Persistence[T]) error {
+	if ctx == nil {
+		panic("via: StateTab.Persist called with nil *Ctx")
+	}
+	if p.Load != nil {
+		v, ok, err := p.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			s.val = v
+			ctx.markStateDirty()
+		}
+	}
+	if p.Save == nil {
+		s.saver = nil
+		return nil
+	}
+	wait := p.Debounce
+	if wait <= 0 {
+		wait = defaultPersistDebounce
+	}
+	sv := &stateSaver[T]{ctx: ctx, key: s.key, save: p.Save, wait: wait}
+	s.saver = sv
+	ctx.mu.Lock()
+	ctx.persistFlush = append(ctx.persistFlush, sv.flush)
+	ctx.mu.Unlock()
+	return nil
+}
+
+// stateSaver debounces the saves of one persisted StateTab.
+type stateSaver[T any] struct {
+	ctx  *Ctx
+	key  string
+	save func(*Ctx, T) error
+	wait time.Duration
+
+	saveMu sync.Mutex // held across save, so saves land in write order
+
+	mu      sync.Mutex // guards the fields below
+	timer   *time.Timer
+	val     T
+	pending bool
+}
+
+// schedule records v as the value to save and restarts the quiet period.
+func (w *stateSaver[T]) schedule(v T) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.val, w.pending = v, true
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.wait, w.flush)
+		return
+	}
+	w.timer.Reset(w.wait)
+}
+
+// flush saves the pending value, if any.
+func (w *stateSaver[T]) flush() {
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	w.mu.Lock()
+	v, pending := w.val, w.pending
+	w.pending = false
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	if !pending {
+		return
+	}
+	defer recoverLog(w.ctx, "Persistence.Save")
+	if err := w.save(w.ctx, v); err != nil {
+		if a := w.ctx.app; a != nil {
+			a.logErr(w.ctx, "via: persisting %q: %v", w.key, err)
+			a.metricsOrNoop().Counter("via.persist.error", "key", w.key)
+		}
+	}
+}
+
+// flushPersisted saves every persisted StateTab's pending write, so a
+// tab going away loses nothing still inside its debounce window.
+func (ctx *Ctx) flushPersisted() {
+	ctx.mu.Lock()
+	fns := ctx.persistFlush
+	ctx.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
@@ -0,0 +1,131 @@
+package via_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// draftStore stands in for a database table, keyed by document.
+type draftStore struct {
+	mu    sync.Mutex
+	rows  map[string]string
+	saves map[string][]string
+}
+
+var drafts = &draftStore{rows: map[string]string{}, saves: map[string][]string{}}
+
+// reset empties doc, so each test (and each -count run) starts clean.
+func (s *draftStore) reset(doc string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rows, doc)
+	delete(s.saves, doc)
+}
+
+func (s *draftStore) put(doc, v string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows[doc] = v
+}
+
+func (s *draftStore) saved(doc string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.saves[doc]...)
+}
+
+type persistedPage struct {
+	Doc   string `query:"doc"`
+	Draft via.StateTabStr
+}
+
+func (p *persistedPage) OnInit(ctx *via.Ctx) error {
+	return p.Draft.Persist(ctx, via.Persistence[string]{
+		Load: func(ctx *via.Ctx) (string, bool, error) {
+			if p.Doc == "broken" {
+				return "", false, errors.New("db down")
+			}
+			drafts.mu.Lock()
+			defer drafts.mu.Unlock()
+			v, ok := drafts.rows[p.Doc]
+			return v, ok, nil
+		},
+		Save: func(ctx *via.Ctx, v string) error {
+			drafts.mu.Lock()
+			defer drafts.mu.Unlock()
+			drafts.rows[p.Doc] = v
+			drafts.saves[p.Doc] = append(drafts.saves[p.Doc], v)
+			return nil
+		},
+		Debounce: 20 * time.Millisecond,
+	})
+}
+
+func (p *persistedPage) Type(ctx *via.Ctx) error {
+	p.Draft.Op(ctx).Append(ctx.EventArg("s"))
+	return nil
+}
+
+func (p *persistedPage) View(ctx *via.CtxR) h.H { return h.P(h.Text("draft=" + p.Draft.Read(ctx))) }
+
+func TestPersist_hydratesFromLoad(t *testing.T) {
+	t.Parallel()
+	drafts.reset("hydrate")
+	drafts.put("hydrate", "hello")
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[persistedPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/?doc=hydrate")
+	assert.Contains(t, tc.HTML(), "draft=hello")
+	assert.Empty(t, drafts.saved("hydrate"), "the loaded value isn't saved back")
+}
+
+func TestPersist_debouncesWritesIntoOneSave(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[persistedPage](app, "/")
+	drafts.reset("debounce")
+
+	tc := vt.NewClient(t, server, "/?doc=debounce")
+	for _, s := range []string{"a", "b", "c"} {
+		require.Equal(t, 200, tc.Action("Type").WithArg("s", s).Fire())
+	}
+	require.Eventually(t, func() bool { return len(drafts.saved("debounce")) > 0 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"abc"}, drafts.saved("debounce"), "a burst of writes is saved once, with the last value")
+}
+
+func TestPersist_flushesAPendingSaveOnDispose(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[persistedPage](app, "/")
+	drafts.reset("dispose")
+
+	tc := vt.NewClient(t, server, "/?doc=dispose")
+	require.Equal(t, 200, tc.Action("Type").WithArg("s", "x").Fire())
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []string{"x"}, drafts.saved("dispose"))
+}
+
+func TestPersist_loadErrorLeavesTheInitialValue(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[persistedPage](app, "/")
+
+	// Persist returns the error, which OnInit returns and the runtime
+	// logs; the page still renders, with the handle untouched.
+	tc := vt.NewClient(t, server, "/?doc=broken")
+	assert.Contains(t, tc.HTML(), "<p>draft=</p>")
+}
@@ -0,0 +1,127 @@
+package via
+
+// A plugin joins the request lifecycle by implementing any of the hook
+// interfaces below next to [Plugin]. New checks each registered plugin
+// once; a hook a plugin doesn't implement costs nothing. Hooks run on
+// the request's goroutine, in registration order, and a panicking hook
+// is logged and skipped — it never fails the page or the action.
+//
+// EXPERIMENTAL: like [Plugin], the hook set may change before 1.0.
+
+// BeforePageRenderer is a [Plugin] that runs before each page view
+// renders, after OnInit — to set the tab's locale or theme, or read the
+// request's CSP nonce. Cached shells and state snapshots render for no
+// particular visitor and skip it.
+type BeforePageRenderer interface {
+	BeforePageRender(ctx *Ctx)
+}
+
+// AfterPageRenderer is a [Plugin] that sees each page document once it
+// is written — for analytics, size budgets or audits. doc is the HTML as
+// sent, before compression. Cached shells and state snapshots skip it.
+type AfterPageRenderer interface {
+	AfterPageRender(ctx *Ctx, doc string)
+}
+
+// ActionObserver is a [Plugin] told of each action just before its
+// handler runs, with the client's signals already applied. action is
+// the name the action was registered under.
+type ActionObserver interface {
+	OnAction(ctx *Ctx, action string)
+}
+
+// SessionObserver is a [Plugin] told when sessions start and end on this
+// pod. id is the session's opaque digest, the same as
+// ContextInfo.SessionID — never the cookie. A session ends when it
+// expires, is revoked or erased; [Session.Rotate] ends the old id and
+// starts the new one.
+type SessionObserver interface {
+	OnSessionStart(id string)
+	OnSessionEnd(id string)
+}
+
+// pluginHooks are the registered plugins sorted by the hooks they
+// implement, collected once at New.
+type pluginHooks struct {
+	beforeRender []BeforePageRenderer
+	afterRender  []AfterPageRenderer
+	actions      []ActionObserver
+	sessions     []SessionObserver
+}
+
+func (hk *pluginHooks) add(p Plugin) {
+	if x, ok := p.(BeforePageRenderer); ok {
+		hk.beforeRender = append(hk.beforeRender, x)
+	}
+	if x, ok := p.(AfterPageRenderer); ok {
+		hk.afterRender = append(hk.afterRender, x)
+	}
+	if x, ok := p.(ActionObserver); ok {
+		hk.actions = append(hk.actions, x)
+	}
+	if x, ok := p.(SessionObserver); ok {
+		hk.sessions = append(hk.sessions, x)
+	}
+}
+
+func (a *App) beforePageRender(ctx *Ctx) {
+	for _, p := range a.hooks.beforeRender {
+		func() {
+			defer recoverLog(ctx, "BeforePageRender")
+			p.BeforePageRender(ctx)
+		}()
+	}
+}
+
+func (a *App) afterPageRender(ctx *Ctx, doc string) {
+	for _, p := range a.hooks.afterRender {
+		func() {
+			defer recoverLog(ctx, "AfterPageRender")
+			p.AfterPageRender(ctx, doc)
+		}()
+	}
+}
+
+func (a *App) observeAction(ctx *Ctx, action string) {
+	for _, p := range a.hooks.actions {
+		func() {
+			defer recoverLog(ctx, "OnAction")
+			p.OnAction(ctx, action)
+		}()
+	}
+}
+
+// sessionStarted and sessionEnded take the raw session id and hand the
+// observers its digest.
+func (a *App) sessionStarted(id string) {
+	if len(a.hooks.sessions) == 0 {
+		return
+	}
+	digest := sessionDigest(id)
+	for _, p := range a.hooks.sessions {
+		func() {
+			defer a.recoverHook("OnSessionStart")
+			p.OnSessionStart(digest)
+		}()
+	}
+}
+
+func (a *App) sessionEnded(id string) {
+	if len(a.hooks.sessions) == 0 {
+		return
+	}
+	digest := sessionDigest(id)
+	for _, p := range a.hooks.sessions {
+		func() {
+			defer a.recoverHook("OnSessionEnd")
+			p.OnSessionEnd(digest)
+		}()
+	}
+}
+
+// recoverHook is recoverLog for hooks that run with no Ctx.
+func (a *App) recoverHook(what string) {
+	if rec := recover(); rec != nil {
+		a.logErr(nil, "%s panicked: %v", what, rec)
+	}
+}
@@ -0,0 +1,88 @@
+package via_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookRecorder implements every lifecycle hook and logs what it saw.
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *hookRecorder) Register(*via.App) {}
+
+func (p *hookRecorder) record(e string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+}
+
+func (p *hookRecorder) seen() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.events...)
+}
+
+func (p *hookRecorder) BeforePageRender(ctx *via.Ctx) { p.record("before") }
+
+func (p *hookRecorder) AfterPageRender(ctx *via.Ctx, doc string) {
+	if strings.Contains(doc, "user:") {
+		p.record("after:doc")
+	}
+}
+
+func (p *hookRecorder) OnAction(ctx *via.Ctx, action string) { p.record("action:" + action) }
+func (p *hookRecorder) OnSessionStart(id string)             { p.record("start:" + id) }
+func (p *hookRecorder) OnSessionEnd(id string)               { p.record("end:" + id) }
+
+type panickyHooks struct{}
+
+func (panickyHooks) Register(*via.App)                {}
+func (panickyHooks) BeforePageRender(*via.Ctx)        { panic("before") }
+func (panickyHooks) AfterPageRender(*via.Ctx, string) { panic("after") }
+func (panickyHooks) OnAction(*via.Ctx, string)        { panic("action") }
+
+func TestPluginHooks_followThePageActionAndSessionLifecycle(t *testing.T) {
+	t.Parallel()
+	rec := &hookRecorder{}
+	app := via.New(via.WithPlugins(rec))
+	via.Mount[accountPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	events := rec.seen()
+	require.Len(t, events, 3)
+	first := strings.TrimPrefix(events[0], "start:")
+	assert.Equal(t, []string{"start:" + first, "before", "after:doc"}, events)
+	require.Len(t, app.Contexts(), 1)
+	assert.Equal(t, app.Contexts()[0].SessionID, first, "observers get the session digest")
+
+	require.Equal(t, http.StatusOK, tc.Action("Login").WithArg("user", "ada").Fire())
+	events = rec.seen()[3:]
+	require.Len(t, events, 3)
+	assert.Equal(t, "action:Login", events[0], "OnAction runs before the handler")
+	assert.Equal(t, "end:"+first, events[1], "Rotate ends the old id")
+	assert.True(t, strings.HasPrefix(events[2], "start:"))
+	assert.NotEqual(t, "start:"+first, events[2])
+}
+
+func TestPluginHooks_panicsAreLoggedNotFatal(t *testing.T) {
+	t.Parallel()
+	rec := &hookRecorder{}
+	app := via.New(via.WithPlugins(panickyHooks{}, rec), via.WithLogger(discardLogger{}))
+	via.Mount[accountPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	assert.Contains(t, tc.HTML(), "user:")
+	assert.Equal(t, http.StatusOK, tc.Action("Logout").Fire())
+	assert.Contains(t, rec.seen(), "after:doc", "a later plugin's hooks still run")
+	assert.Contains(t, rec.seen(), "action:Logout")
+}
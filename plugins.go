@@ -0,0 +1,137 @@
+package via
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedPlugin is a [Plugin] registered under a name, so other plugins
+// and the app can find it with [App.Plugin]. Names are unique per App:
+// registering a second plugin under a taken name panics at boot.
+type NamedPlugin interface {
+	Plugin
+	Name() string
+}
+
+// DependentPlugin is a [Plugin] that needs other plugins registered
+// first — a component library on top of its CSS plugin, say. Requires
+// names them; [WithPlugins] registers them ahead of it whatever order
+// they were passed in, and New panics naming the one that is missing.
+type DependentPlugin interface {
+	Plugin
+	Requires() []string
+}
+
+// RegisterPlugin registers p under name and runs its Register. Use it
+// for a plugin that doesn't name itself, or to give one a second name.
+// Like [App.HandleFunc] it is for boot: call it before the server
+// starts. It panics on an empty or taken name, or if p requires a
+// plugin that isn't registered yet.
+func (a *App) RegisterPlugin(name string, p Plugin) {
+	if name == "" {
+		panic("via.RegisterPlugin: empty plugin name")
+	}
+	if p == nil {
+		panic(fmt.Sprintf("via.RegisterPlugin: nil plugin %q", name))
+	}
+	a.claimPluginName(name, p)
+	a.checkRequires(name, p)
+	a.runPlugin(p)
+}
+
+// Plugin returns the plugin registered under name, or nil. Assert it to
+// the plugin package's own type or interface to use it.
+func (a *App) Plugin(name string) Plugin {
+	return a.pluginsByName[name]
+}
+
+// registerPlugins runs the WithPlugins list: each plugin after the ones
+// it requires, otherwise in the order given, so boot is deterministic.
+func (a *App) registerPlugins(list []Plugin) {
+	named := map[string]int{} // name -> index in list
+	for i, p := range list {
+		if n, ok := p.(NamedPlugin); ok {
+			if _, dup := named[n.Name()]; dup {
+				panic(fmt.Sprintf("via: two plugins are named %q; register one of them "+
+					"under another name with App.RegisterPlugin", n.Name()))
+			}
+			named[n.Name()] = i
+		}
+	}
+	// Indexes, not the plugins, key the walk: a plugin's dynamic type
+	// needn't be comparable.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make([]int, len(list))
+	var path []string
+	var visit func(i int)
+	visit = func(i int) {
+		p := list[i]
+		switch state[i] {
+		case done:
+			return
+		case visiting:
+			panic("via: plugin dependency cycle: " + strings.Join(append(path, pluginLabel(p)), " -> "))
+		}
+		state[i] = visiting
+		path = append(path, pluginLabel(p))
+		if d, ok := p.(DependentPlugin); ok {
+			for _, req := range d.Requires() {
+				if dep, ok := named[req]; ok {
+					visit(dep)
+				} else if a.pluginsByName[req] == nil {
+					panic(fmt.Sprintf("via: plugin %s requires plugin %q, which is not registered",
+						pluginLabel(p), req))
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+		if n, ok := p.(NamedPlugin); ok {
+			a.claimPluginName(n.Name(), p)
+		}
+		a.runPlugin(p)
+	}
+	for i, p := range list {
+		if p != nil {
+			visit(i)
+		}
+	}
+}
+
+func (a *App) claimPluginName(name string, p Plugin) {
+	if _, dup := a.pluginsByName[name]; dup {
+		panic(fmt.Sprintf("via: plugin name %q is already registered", name))
+	}
+	if a.pluginsByName == nil {
+		a.pluginsByName = map[string]Plugin{}
+	}
+	a.pluginsByName[name] = p
+}
+
+func (a *App) checkRequires(name string, p Plugin) {
+	d, ok := p.(DependentPlugin)
+	if !ok {
+		return
+	}
+	for _, req := range d.Requires() {
+		if a.pluginsByName[req] == nil {
+			panic(fmt.Sprintf("via: plugin %q requires plugin %q, which is not registered", name, req))
+		}
+	}
+}
+
+func (a *App) runPlugin(p Plugin) {
+	p.Register(a)
+	a.hooks.add(p)
+}
+
+// pluginLabel names p in a panic: its Name, else its type.
+func pluginLabel(p Plugin) string {
+	if n, ok := p.(NamedPlugin); ok {
+		return fmt.Sprintf("%q", n.Name())
+	}
+	return fmt.Sprintf("%T", p)
+}
@@ -100,6 +100,9 @@ type plugin struct {
 	js   *asset
 }
 
+// Name registers the plugin as "echarts", for via.App.Plugin.
+func (p *plugin) Name() string { return "echarts" }
+
 func (p *plugin) Register(v *via.App) {
 	v.HandleFunc("GET "+assetPathPrefix, p.serveAssets)
 
@@ -113,6 +116,6 @@ func (p *plugin) Register(v *via.App) {
 	case p.opts.source != "":
 		v.AppendToHead(h.Script(h.Src(p.opts.source)))
 	default:
-		v.AppendToHead(h.Script(h.Src(p.js.path())))
+		v.AppendToHead(h.Script(h.Src(v.BasePath() + p.js.path())))
 	}
 }
@@ -31,6 +31,10 @@ func (p *chartActionPage) SetTwoSer(ctx *via.Ctx) error {
 	)
 }
 
+func (p *chartActionPage) SetHostileSer(ctx *via.Ctx) error {
+	return p.Chart.SetSeries(ctx, echarts.Line("</script><script>alert(1)//\u2028", [][]any{{0, 1}}))
+}
+
 func (p *chartActionPage) AppendPoints(ctx *via.Ctx) error {
 	return p.Chart.AppendData(ctx, 0, [][]any{{42, 17}})
 }
@@ -166,6 +170,13 @@ func TestChartAPI_SetSeries_carriesAllVariadicSeriesIntoTheFrame(t *testing.T) {
 	fireChartAction(t, "SetTwoSer", "setOption", `"Read"`, `"Write"`)
 }
 
+func TestChartAPI_SetSeries_encodesUserTextInertly(t *testing.T) {
+	t.Parallel()
+	// Series names often come from user data; the JSON must not be able
+	// to close the script element it rides in.
+	fireChartAction(t, "SetHostileSer", `"\u003c/script\u003e\u003cscript\u003ealert(1)//\u2028"`)
+}
+
 func TestChartAPI_AppendData_emitsAppendDataCall(t *testing.T) {
 	t.Parallel()
 	fireChartAction(t, "AppendPoints", "appendData", "seriesIndex:0")
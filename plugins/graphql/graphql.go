@@ -0,0 +1,235 @@
+// Package graphql bridges a GraphQL subscription into a Via app, so Via can
+// act as a live frontend over an existing realtime GraphQL API.
+//
+// The bridge speaks the GraphQL over Server-Sent Events protocol
+// ("distinct connections" mode): one POST per subscription with
+// Accept: text/event-stream, each `next` event carrying one execution
+// result. Every result is decoded into T, kept as the subscription's latest
+// value, and announced on the subscription's cursor — views that called
+// ctx.Subscribe(cursor) re-render.
+//
+//	type Ticker struct{ Price struct{ Symbol string; Last float64 } }
+//
+//	prices := graphql.Subscribe[Ticker]("https://api.example.com/graphql/stream",
+//	    `subscription { price(symbol: "ACME") { symbol last } }`,
+//	    graphql.WithCursor("acme"))
+//	app := via.New(via.WithPlugins(graphql.Plugin(prices)))
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H {
+//	    ctx.Subscribe(prices.Cursor())
+//	    t, _ := prices.Latest()
+//	    return h.Span(h.Textf("%.2f", t.Price.Last))
+//	}
+package graphql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-via/via"
+)
+
+// Option configures a [Subscription].
+type Option func(*config)
+
+type config struct {
+	variables map[string]any
+	header    http.Header
+	client    *http.Client
+	cursor    string
+}
+
+// WithVariables sets the subscription's GraphQL variables.
+func WithVariables(vars map[string]any) Option {
+	return func(c *config) { c.variables = vars }
+}
+
+// WithHeader adds a request header to the subscription POST — typically
+// Authorization. Repeat for several headers.
+func WithHeader(key, value string) Option {
+	return func(c *config) { c.header.Add(key, value) }
+}
+
+// WithHTTPClient overrides the client used for the long-lived stream. The
+// client must not set an overall Timeout, or it will cut the stream off.
+func WithHTTPClient(client *http.Client) Option {
+	if client == nil {
+		panic("graphql: WithHTTPClient requires a non-nil client")
+	}
+	return func(c *config) { c.client = client }
+}
+
+// WithCursor names the cursor announced on every event. Defaults to the
+// subscription query text, which is rarely what a view wants to type.
+func WithCursor(cursor string) Option {
+	if cursor == "" {
+		panic("graphql: WithCursor requires a non-empty cursor")
+	}
+	return func(c *config) { c.cursor = cursor }
+}
+
+// Subscription is one GraphQL subscription bridged into the app. It
+// implements [via.ChangeSource]; hand it to [Plugin] (or
+// App.WatchChanges) to start it.
+type Subscription[T any] struct {
+	endpoint string
+	query    string
+	cfg      config
+
+	mu     sync.RWMutex
+	latest T
+	seen   bool
+}
+
+// Subscribe declares a subscription against endpoint. No I/O happens until
+// the app starts watching it. Panics on an empty endpoint or query.
+func Subscribe[T any](endpoint, query string, opts ...Option) *Subscription[T] {
+	if endpoint == "" || query == "" {
+		panic("graphql: Subscribe requires an endpoint and a query")
+	}
+	s := &Subscription[T]{
+		endpoint: endpoint,
+		query:    query,
+		cfg:      config{header: http.Header{}, client: &http.Client{}, cursor: query},
+	}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+	return s
+}
+
+// Cursor returns the cursor views pass to ctx.Subscribe to re-render on
+// every event.
+func (s *Subscription[T]) Cursor() string { return s.cfg.cursor }
+
+// Latest returns the most recently decoded event and whether one has
+// arrived yet.
+func (s *Subscription[T]) Latest() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest, s.seen
+}
+
+// result is one GraphQL execution result on the wire.
+type result struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Watch opens the stream and decodes events until ctx is cancelled or the
+// connection drops; every return while the app runs is reconnected by the
+// runtime. A `complete` from the server ends the subscription for good:
+// Watch then holds until ctx is cancelled, so it is never reopened.
+func (s *Subscription[T]) Watch(ctx context.Context, notify func(cursor string)) error {
+	body, err := json.Marshal(map[string]any{"query": s.query, "variables": s.cfg.variables})
+	if err != nil {
+		return fmt.Errorf("graphql: encode request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("graphql: %v", err)
+	}
+	for k, vs := range s.cfg.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("graphql: subscribe %s: %v", s.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql: subscribe %s: status %d", s.endpoint, resp.StatusCode)
+	}
+
+	var event string
+	var data strings.Builder
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64<<10), 4<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "":
+			done, err := s.dispatch(event, data.String(), notify)
+			if err != nil {
+				return err
+			}
+			if done {
+				resp.Body.Close()
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(line[len("data:"):], " "))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("graphql: read stream: %v", err)
+	}
+	return errors.New("graphql: stream closed")
+}
+
+// dispatch applies one complete SSE event. done reports a `complete` event:
+// the server has finished the subscription and won't send more.
+func (s *Subscription[T]) dispatch(event, data string, notify func(string)) (done bool, err error) {
+	switch event {
+	case "complete":
+		return true, nil
+	case "next", "":
+		if data == "" {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	var res result
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		return false, fmt.Errorf("graphql: decode result: %v", err)
+	}
+	if len(res.Errors) > 0 {
+		return false, fmt.Errorf("graphql: subscription error: %s", res.Errors[0].Message)
+	}
+	var v T
+	if err := json.Unmarshal(res.Data, &v); err != nil {
+		return false, fmt.Errorf("graphql: decode data: %v", err)
+	}
+	s.mu.Lock()
+	s.latest = v
+	s.seen = true
+	s.mu.Unlock()
+	notify(s.cfg.cursor)
+	return false, nil
+}
+
+// Plugin starts every subscription when the app is constructed and stops
+// them on Shutdown.
+func Plugin(subs ...via.ChangeSource) via.Plugin {
+	return plugin(subs)
+}
+
+type plugin []via.ChangeSource
+
+func (p plugin) Register(app *via.App) {
+	for _, s := range p {
+		app.WatchChanges(s)
+	}
+}
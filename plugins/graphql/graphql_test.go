@@ -0,0 +1,161 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/plugins/graphql"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type price struct {
+	Price struct {
+		Symbol string  `json:"symbol"`
+		Last   float64 `json:"last"`
+	} `json:"price"`
+}
+
+// sseServer answers a GraphQL-over-SSE subscription by replaying events,
+// one per value sent on next, and records the request it received.
+func sseServer(t *testing.T, next <-chan string, got chan<- map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["auth"] = r.Header.Get("Authorization")
+		body["accept"] = r.Header.Get("Accept")
+		select {
+		case got <- body:
+		default:
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case data := <-next:
+				fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+				w.(http.Flusher).Flush()
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSubscription_decodesEventsIntoLatest(t *testing.T) {
+	t.Parallel()
+
+	next := make(chan string, 1)
+	got := make(chan map[string]any, 1)
+	srv := sseServer(t, next, got)
+	sub := graphql.Subscribe[price](srv.URL, `subscription { price { symbol last } }`,
+		graphql.WithCursor("acme"),
+		graphql.WithVariables(map[string]any{"symbol": "ACME"}),
+		graphql.WithHeader("Authorization", "Bearer t"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notified := make(chan string, 1)
+	go func() { _ = sub.Watch(ctx, func(c string) { notified <- c }) }()
+
+	req := <-got
+	assert.Equal(t, "Bearer t", req["auth"])
+	assert.Equal(t, "text/event-stream", req["accept"])
+	assert.Equal(t, map[string]any{"symbol": "ACME"}, req["variables"])
+
+	next <- `{"data":{"price":{"symbol":"ACME","last":12.5}}}`
+	select {
+	case c := <-notified:
+		assert.Equal(t, "acme", c)
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no notification for the event")
+	}
+	v, ok := sub.Latest()
+	require.True(t, ok)
+	assert.Equal(t, 12.5, v.Price.Last)
+}
+
+func TestSubscription_returnsErrorOnGraphQLErrors(t *testing.T) {
+	t.Parallel()
+
+	next := make(chan string, 1)
+	srv := sseServer(t, next, make(chan map[string]any, 1))
+	sub := graphql.Subscribe[price](srv.URL, `subscription { price { last } }`)
+
+	next <- `{"errors":[{"message":"not authorized"}]}`
+	err := sub.Watch(context.Background(), func(string) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not authorized")
+	_, ok := sub.Latest()
+	assert.False(t, ok)
+}
+
+func TestPlugin_doesNotResubscribeAfterComplete(t *testing.T) {
+	t.Parallel()
+
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: next\ndata: {\"data\":{\"price\":{\"last\":1}}}\n\n")
+		fmt.Fprint(w, "event: complete\ndata:\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	sub := graphql.Subscribe[price](srv.URL, `subscription { price { last } }`)
+
+	app := via.New(via.WithPlugins(graphql.Plugin(sub)))
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+
+	require.Eventually(t, func() bool { _, ok := sub.Latest(); return ok }, 2*time.Second, 5*time.Millisecond)
+	// The runtime's first retry would land within 10ms; give it far longer.
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 1, posts.Load(), "a completed subscription must not be reopened")
+}
+
+var pricesForPage *graphql.Subscription[price]
+
+type pricePage struct{}
+
+func (p *pricePage) View(ctx *via.CtxR) h.H {
+	ctx.Subscribe(pricesForPage.Cursor())
+	v, _ := pricesForPage.Latest()
+	return h.Span(h.ID("last"), h.Textf("last=%.1f", v.Price.Last))
+}
+
+func TestPlugin_rerendersSubscribedViewsOnEvent(t *testing.T) {
+	t.Parallel()
+
+	next := make(chan string, 1)
+	srv := sseServer(t, next, make(chan map[string]any, 1))
+	pricesForPage = graphql.Subscribe[price](srv.URL, `subscription { price { last } }`,
+		graphql.WithCursor("page"))
+
+	app := via.New(via.WithPlugins(graphql.Plugin(pricesForPage)))
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+	via.Mount[pricePage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	next <- `{"data":{"price":{"symbol":"ACME","last":7.5}}}`
+	vt.AwaitFrame(t, frames, 2*time.Second, "last=7.5")
+}
+
+func TestSubscribe_panicsWithoutQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { graphql.Subscribe[price]("http://x", "") })
+}
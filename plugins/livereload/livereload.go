@@ -0,0 +1,267 @@
+// Package livereload reloads open pages while you develop: after the
+// binary restarts, and when a watched file changes.
+//
+//	app := via.New(via.WithPlugins(
+//	    livereload.Plugin(
+//	        livereload.WithWatch("static", "content"),
+//	        livereload.WithIgnore("*.tmp", "node_modules"),
+//	    ),
+//	))
+//
+// Every page carries the version it was rendered under: a random boot id
+// plus a counter bumped whenever a watched file changes (debounced, so a
+// save that touches several files reloads once). Visible tabs poll the
+// current version and reload when it no longer matches — the reloaded
+// page carries the new version, so each stale tab reloads exactly once,
+// and a tab whose server is down just keeps polling until it is back.
+//
+// Files are watched by polling their size and modification time; there is
+// no platform watcher to install. The plugin is for development: leave it
+// out of production builds.
+package livereload
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+)
+
+const (
+	pathPrefix  = "/_plugins/livereload/"
+	clientPath  = pathPrefix + "client.js"
+	versionPath = pathPrefix + "version"
+)
+
+// Option configures the plugin.
+type Option func(*plugin)
+
+// WithWatch adds directories whose files reload the page when they change
+// — templates, stylesheets, content read at request time. Subdirectories
+// are watched too. Go sources need no watching: rebuilding restarts the
+// binary, which reloads the page on its own.
+func WithWatch(dirs ...string) Option {
+	return func(p *plugin) { p.dirs = append(p.dirs, dirs...) }
+}
+
+// WithIgnore skips files and directories matching any of patterns, in
+// path.Match syntax, tested against both the base name and the slash
+// path relative to the watched directory. ".git", editor swap files
+// ("*.swp", "*~", ".#*") and "*.tmp" are always ignored.
+func WithIgnore(patterns ...string) Option {
+	for _, pat := range patterns {
+		if _, err := path.Match(pat, ""); err != nil {
+			panic("livereload: WithIgnore: bad pattern " + strconv.Quote(pat))
+		}
+	}
+	return func(p *plugin) { p.ignore = append(p.ignore, patterns...) }
+}
+
+// WithDebounce sets how long the watched files must stay quiet after a
+// change before pages reload. Defaults to 200ms.
+func WithDebounce(d time.Duration) Option {
+	return func(p *plugin) {
+		if d > 0 {
+			p.debounce = d
+		}
+	}
+}
+
+// WithInterval sets how often watched files are scanned and open tabs
+// check the version. Defaults to 500ms.
+func WithInterval(d time.Duration) Option {
+	return func(p *plugin) {
+		if d > 0 {
+			p.interval = d
+		}
+	}
+}
+
+type plugin struct {
+	dirs     []string
+	ignore   []string
+	debounce time.Duration
+	interval time.Duration
+
+	boot       string
+	generation atomic.Uint64
+	logger     via.Logger
+}
+
+// Plugin builds the live-reload plugin. With no options it reloads pages
+// after a restart only.
+func Plugin(opts ...Option) via.Plugin {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	p := &plugin{
+		ignore:   []string{".git", "*.swp", "*~", ".#*", "*.tmp"},
+		debounce: 200 * time.Millisecond,
+		interval: 500 * time.Millisecond,
+		boot:     hex.EncodeToString(id[:]),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Name registers the plugin as "livereload", for via.App.Plugin.
+func (p *plugin) Name() string { return "livereload" }
+
+func (p *plugin) Register(app *via.App) {
+	p.logger = app.Logger()
+	app.HandleFunc("GET "+clientPath, p.serveClient)
+	app.HandleFunc("GET "+versionPath, p.serveVersion)
+	app.AppendToFoot(clientScript{p: p, base: app.BasePath()})
+	if len(p.dirs) > 0 {
+		app.WatchChanges(p)
+	}
+}
+
+// version is what a page rendered now carries.
+func (p *plugin) version() string {
+	return p.boot + "-" + strconv.FormatUint(p.generation.Load(), 10)
+}
+
+// clientScript renders the poller's <script>, stamped with the version
+// current at render time.
+type clientScript struct {
+	p    *plugin
+	base string
+}
+
+func (s clientScript) Render(w io.Writer) error {
+	src := s.base + clientPath + "?v=" + s.p.version() +
+		"&every=" + strconv.FormatInt(s.p.interval.Milliseconds(), 10)
+	return h.Script(h.Src(src)).Render(w)
+}
+
+// clientJS polls the version while the tab is visible and reloads once it
+// changes; busy stays set after reload() so a slow unload can't reload
+// twice.
+const clientJS = `(function(){
+var s=new URL(document.currentScript.src),mine=s.searchParams.get('v'),
+url=s.pathname.replace(/client\.js$/,'version'),busy=false;
+function check(){if(busy||document.hidden)return;busy=true;
+fetch(url,{cache:'no-store'}).then(function(r){return r.ok?r.text():mine}).then(function(v){
+if(v!==mine){location.reload();return}busy=false},function(){busy=false})}
+setInterval(check,+s.searchParams.get('every')||500);
+document.addEventListener('visibilitychange',check)})();
+`
+
+func (p *plugin) serveClient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = io.WriteString(w, clientJS)
+}
+
+func (p *plugin) serveVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = io.WriteString(w, p.version())
+}
+
+// fileStamp is what a scan compares to spot a change.
+type fileStamp struct {
+	size int64
+	mod  time.Time
+}
+
+// Watch scans the watched directories every interval and bumps the
+// version once they have been quiet for the debounce period after a
+// change. It implements via.ChangeSource, so the app runs it for its
+// lifetime.
+func (p *plugin) Watch(ctx context.Context, _ func(cursor string)) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	quiet := time.NewTimer(time.Hour)
+	quiet.Stop()
+	defer quiet.Stop()
+
+	last := p.scan()
+	var changed string
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			now := p.scan()
+			if name := diff(last, now); name != "" {
+				changed = name
+				quiet.Reset(p.debounce)
+			}
+			last = now
+		case <-quiet.C:
+			p.generation.Add(1)
+			p.logger.Log(via.LogInfo, "livereload: reloading pages", "changed", changed)
+		}
+	}
+}
+
+// scan stamps every file under the watched directories that no ignore
+// pattern matches. Unreadable entries are skipped.
+func (p *plugin) scan() map[string]fileStamp {
+	files := map[string]fileStamp{}
+	for _, dir := range p.dirs {
+		_ = filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(dir, name)
+			if rel != "." && p.ignored(filepath.ToSlash(rel), d.Name()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			files[name] = fileStamp{size: info.Size(), mod: info.ModTime()}
+			return nil
+		})
+	}
+	return files
+}
+
+func (p *plugin) ignored(rel, base string) bool {
+	for _, pat := range p.ignore {
+		if ok, _ := path.Match(pat, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(pat, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// diff names a file added, removed or changed between two scans, or
+// returns "" when they match.
+func diff(before, after map[string]fileStamp) string {
+	for name, st := range after {
+		if old, ok := before[name]; !ok || old.size != st.size || !old.mod.Equal(st.mod) {
+			return name
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			return name
+		}
+	}
+	return ""
+}
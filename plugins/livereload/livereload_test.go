@@ -0,0 +1,118 @@
+package livereload_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/plugins/livereload"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page struct{}
+
+func (p *page) View(ctx *via.CtxR) h.H { return h.P(h.T("hello")) }
+
+var versionRE = regexp.MustCompile(`/_plugins/livereload/client\.js\?v=([0-9a-f]+-\d+)&amp;every=(\d+)`)
+
+// pageVersion returns the version stamped into a freshly rendered page.
+func pageVersion(t *testing.T, tc *vt.Client) string {
+	t.Helper()
+	m := versionRE.FindStringSubmatch(tc.Reload())
+	require.NotNil(t, m, "page must load the live-reload client")
+	return m[1]
+}
+
+func get(t *testing.T, url string) (string, *http.Response) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(b), resp
+}
+
+func TestPlugin_servesTheClientAndTheCurrentVersion(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithPlugins(livereload.Plugin(livereload.WithInterval(250 * time.Millisecond))))
+	via.Mount[page](app, "/")
+	srv := vt.Serve(t, app)
+	tc := vt.NewClient(t, srv, "/")
+
+	m := versionRE.FindStringSubmatch(tc.HTML())
+	require.NotNil(t, m)
+	assert.Equal(t, "250", m[2])
+
+	version, resp := get(t, srv.URL+"/_plugins/livereload/version")
+	assert.Equal(t, m[1], version)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	js, resp := get(t, srv.URL+"/_plugins/livereload/client.js")
+	assert.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, js, "location.reload()")
+}
+
+func TestPlugin_eachBootHasItsOwnVersion(t *testing.T) {
+	t.Parallel()
+	boot := func() string {
+		app := via.New(via.WithPlugins(livereload.Plugin()))
+		via.Mount[page](app, "/")
+		return pageVersion(t, vt.NewClient(t, vt.Serve(t, app), "/"))
+	}
+	assert.NotEqual(t, boot(), boot(), "a restarted binary must invalidate open tabs")
+}
+
+func TestPlugin_changedFileBumpsTheVersionOnceAfterDebounce(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	css := filepath.Join(dir, "site.css")
+	require.NoError(t, os.WriteFile(css, []byte("p{}"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "node_modules"), 0o700))
+
+	app := via.New(via.WithPlugins(livereload.Plugin(
+		livereload.WithWatch(dir),
+		livereload.WithIgnore("node_modules"),
+		livereload.WithInterval(10*time.Millisecond),
+		livereload.WithDebounce(30*time.Millisecond),
+	)))
+	via.Mount[page](app, "/")
+	srv := vt.Serve(t, app)
+	tc := vt.NewClient(t, srv, "/")
+	before := pageVersion(t, tc)
+
+	// Ignored paths never reload.
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "x.js"), []byte("1"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "draft.tmp"), []byte("1"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, before, pageVersion(t, tc))
+
+	// A burst of saves reloads once.
+	for i := range 3 {
+		require.NoError(t, os.WriteFile(css, []byte("p{margin:"+string(rune('0'+i))+"px}"), 0o600))
+		time.Sleep(5 * time.Millisecond)
+	}
+	var after string
+	require.Eventually(t, func() bool {
+		after = pageVersion(t, tc)
+		return after != before
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, before[:len(before)-1]+"1", after)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, after, pageVersion(t, tc), "a quiet tree keeps the version")
+}
+
+func TestWithIgnore_panicsOnABadPattern(t *testing.T) {
+	t.Parallel()
+	assert.Panics(t, func() { livereload.WithIgnore("[") })
+}
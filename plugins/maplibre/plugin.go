@@ -192,6 +192,9 @@ type plugin struct {
 	assetsByName map[string]*asset
 }
 
+// Name registers the plugin as "maplibre", for via.App.Plugin.
+func (p *plugin) Name() string { return "maplibre" }
+
 func (p *plugin) Register(v *via.App) {
 	v.HandleFunc("GET "+assetPathPrefix, p.serveAssets)
 
@@ -206,7 +209,7 @@ func (p *plugin) Register(v *via.App) {
 	case p.opts.cssSource != "":
 		v.AppendToHead(h.Link(h.Rel("stylesheet"), h.Href(p.opts.cssSource)))
 	default:
-		v.AppendToHead(h.Link(h.Rel("stylesheet"), h.Href(p.css.path())))
+		v.AppendToHead(h.Link(h.Rel("stylesheet"), h.Href(v.BasePath()+p.css.path())))
 	}
 
 	// Synchronous, no defer/async: maplibregl must be defined before any
@@ -221,13 +224,13 @@ func (p *plugin) Register(v *via.App) {
 	case p.opts.jsSource != "":
 		v.AppendToHead(h.Script(h.Src(p.opts.jsSource)))
 	case p.opts.cspBuild:
-		v.AppendToHead(h.Script(h.Src(p.cspJS.path())))
+		v.AppendToHead(h.Script(h.Src(v.BasePath() + p.cspJS.path())))
 		// The CSP bundle ships with an empty worker URL by design; maps
 		// can't boot until it points at the companion worker script.
 		v.AppendToHead(h.Script(h.Raw(
-			"maplibregl.workerUrl='" + p.cspWorker.path() + "';")))
+			"maplibregl.workerUrl='" + v.BasePath() + p.cspWorker.path() + "';")))
 	default:
-		v.AppendToHead(h.Script(h.Src(p.js.path())))
+		v.AppendToHead(h.Script(h.Src(v.BasePath() + p.js.path())))
 	}
 
 	v.AppendToHead(h.Script(h.Raw(registryJS)))
@@ -0,0 +1,261 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// JSON decodes a JSON payload with encoding/json.
+func JSON(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// CBOR decodes a CBOR (RFC 8949) payload into v using v's json struct tags:
+// the payload is read into maps, slices and scalars, then mapped onto v as
+// JSON would be. Tags are skipped, byte strings become []byte (base64 for a
+// string field), and map keys are stringified. For CBOR-native struct tags
+// or streaming use, plug a full codec in with [WithDecoder].
+func CBOR(data []byte, v any) error {
+	d := cborDecoder{b: data}
+	val, err := d.value(0)
+	if err != nil {
+		return err
+	}
+	if d.off != len(d.b) {
+		return errors.New("mqtt: cbor: trailing bytes after value")
+	}
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("mqtt: cbor: %v", err)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// cborMaxDepth bounds nesting so a hostile payload cannot exhaust the stack.
+const cborMaxDepth = 64
+
+var errCBORTruncated = errors.New("mqtt: cbor: truncated payload")
+
+type cborDecoder struct {
+	b   []byte
+	off int
+}
+
+func (d *cborDecoder) byte() (byte, error) {
+	if d.off >= len(d.b) {
+		return 0, errCBORTruncated
+	}
+	c := d.b[d.off]
+	d.off++
+	return c, nil
+}
+
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.b)-d.off) {
+		return nil, errCBORTruncated
+	}
+	out := d.b[d.off : d.off+int(n)]
+	d.off += int(n)
+	return out, nil
+}
+
+// arg reads the argument encoded by additional info ai. indefinite reports
+// ai 31, which only strings, arrays and maps accept.
+func (d *cborDecoder) arg(ai byte) (n uint64, indefinite bool, err error) {
+	switch {
+	case ai < 24:
+		return uint64(ai), false, nil
+	case ai == 24:
+		b, err := d.take(1)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b[0]), false, nil
+	case ai == 25:
+		b, err := d.take(2)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), false, nil
+	case ai == 26:
+		b, err := d.take(4)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), false, nil
+	case ai == 27:
+		b, err := d.take(8)
+		if err != nil {
+			return 0, false, err
+		}
+		return binary.BigEndian.Uint64(b), false, nil
+	case ai == 31:
+		return 0, true, nil
+	}
+	return 0, false, fmt.Errorf("mqtt: cbor: reserved additional info %d", ai)
+}
+
+// isBreak consumes the 0xff stop code ending an indefinite-length item.
+func (d *cborDecoder) isBreak() bool {
+	if d.off < len(d.b) && d.b[d.off] == 0xff {
+		d.off++
+		return true
+	}
+	return false
+}
+
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("mqtt: cbor: nesting too deep")
+	}
+	head, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	major, ai := head>>5, head&0x1f
+	if major == 7 {
+		return d.simple(ai)
+	}
+	n, indefinite, err := d.arg(ai)
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && major < 2 || indefinite && major == 6 {
+		return nil, errors.New("mqtt: cbor: indefinite length on a non-container")
+	}
+	switch major {
+	case 0:
+		return n, nil
+	case 1:
+		if n > math.MaxInt64 {
+			return -float64(n) - 1, nil
+		}
+		return -int64(n) - 1, nil
+	case 2, 3:
+		b, err := d.str(major, n, indefinite, depth)
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(b), nil
+		}
+		return b, nil
+	case 4:
+		out := []any{}
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case 5:
+		out := map[string]any{}
+		for i := uint64(0); indefinite || i < n; i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if ks, ok := k.(string); ok {
+				out[ks] = v
+			} else {
+				out[fmt.Sprint(k)] = v
+			}
+		}
+		return out, nil
+	default: // 6: tag — the tagged item stands for itself
+		return d.value(depth + 1)
+	}
+}
+
+// str reads a byte or text string, joining the chunks of an indefinite one.
+func (d *cborDecoder) str(major byte, n uint64, indefinite bool, depth int) ([]byte, error) {
+	if !indefinite {
+		return d.take(n)
+	}
+	var out []byte
+	for !d.isBreak() {
+		head, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		if head>>5 != major {
+			return nil, errors.New("mqtt: cbor: mixed chunk types in indefinite string")
+		}
+		cn, ind, err := d.arg(head & 0x1f)
+		if err != nil {
+			return nil, err
+		}
+		if ind {
+			return nil, errors.New("mqtt: cbor: nested indefinite string chunk")
+		}
+		chunk, err := d.take(cn)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+func (d *cborDecoder) simple(ai byte) (any, error) {
+	switch ai {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		b, err := d.take(2)
+		if err != nil {
+			return nil, err
+		}
+		return halfFloat(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.take(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.take(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	}
+	return nil, fmt.Errorf("mqtt: cbor: unsupported simple value %d", ai)
+}
+
+// halfFloat widens an IEEE 754 binary16 value, as sensors commonly send.
+func halfFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 31:
+		if frac == 0 {
+			return sign * math.Inf(1)
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
@@ -0,0 +1,383 @@
+// Package mqtt bridges MQTT topics into a Via app, making Via a dashboard
+// layer over an IoT fleet without a separate ingestion service.
+//
+// A [Subscription] connects to an MQTT 3.1.1 broker, subscribes to one topic
+// filter (wildcards included), decodes every payload into T and announces
+// the subscription's cursor — views that called ctx.Subscribe(cursor)
+// re-render. It keeps the latest value, the latest value per concrete topic
+// (one per device under a wildcard), and optionally a bounded series for
+// charts.
+//
+//	type Reading struct{ Celsius float64 `json:"c"` }
+//
+//	temps := mqtt.Subscribe[Reading]("broker.local:1883", "sensors/+/temp",
+//	    mqtt.WithQoS(1), mqtt.WithSeries(120), mqtt.WithCursor("temps"))
+//	app := via.New(via.WithPlugins(mqtt.Plugin(temps)))
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H {
+//	    ctx.Subscribe(temps.Cursor())
+//	    m, _ := temps.Latest()
+//	    return h.Span(h.Textf("%s: %.1f°C", m.Topic, m.Value.Celsius))
+//	}
+//
+// A dropped connection is reconnected by the runtime with jittered backoff;
+// the subscription is renewed on every connect.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+)
+
+// Decoder unmarshals one message payload into v. [JSON] and [CBOR] are
+// provided; any func with this shape (e.g. a protobuf adapter) works.
+type Decoder func(data []byte, v any) error
+
+// Message is one decoded payload and where it came from.
+type Message[T any] struct {
+	Topic string
+	Value T
+	At    time.Time
+}
+
+// Option configures a [Subscription].
+type Option func(*config)
+
+type config struct {
+	qos       byte
+	clientID  string
+	user      string
+	pass      string
+	keepAlive time.Duration
+	cursor    string
+	decode    Decoder
+	series    int
+	tls       *tls.Config
+}
+
+// WithQoS sets the subscription's maximum QoS: 0 (at most once), 1 (at least
+// once) or 2 (exactly once). Defaults to 0. Panics on any other value.
+func WithQoS(qos byte) Option {
+	if qos > 2 {
+		panic(fmt.Sprintf("mqtt: WithQoS(%d): QoS must be 0, 1 or 2", qos))
+	}
+	return func(c *config) { c.qos = qos }
+}
+
+// WithClientID sets the MQTT client identifier. Defaults to a random
+// "via-" id; set a stable one when the broker's ACLs key on it. Pods of a
+// cluster need distinct ids, or the broker disconnects one for the other.
+func WithClientID(id string) Option {
+	return func(c *config) { c.clientID = id }
+}
+
+// WithCredentials sets the CONNECT user name and password.
+func WithCredentials(user, pass string) Option {
+	return func(c *config) { c.user, c.pass = user, pass }
+}
+
+// WithKeepAlive sets the interval between pings. Defaults to 30s; a broker
+// silent for 1.5× the interval is treated as a dropped connection.
+func WithKeepAlive(d time.Duration) Option {
+	if d < time.Second || d > 18*time.Hour {
+		panic("mqtt: WithKeepAlive must be between 1s and 18h")
+	}
+	return func(c *config) { c.keepAlive = d }
+}
+
+// WithCursor names the cursor announced on every message. Defaults to the
+// topic filter.
+func WithCursor(cursor string) Option {
+	if cursor == "" {
+		panic("mqtt: WithCursor requires a non-empty cursor")
+	}
+	return func(c *config) { c.cursor = cursor }
+}
+
+// WithDecoder sets the payload decoder. Defaults to [JSON].
+func WithDecoder(d Decoder) Option {
+	if d == nil {
+		panic("mqtt: WithDecoder requires a non-nil decoder")
+	}
+	return func(c *config) { c.decode = d }
+}
+
+// WithSeries keeps the last n messages, oldest first, for [Subscription.Series].
+func WithSeries(n int) Option {
+	if n < 1 {
+		panic("mqtt: WithSeries requires n >= 1")
+	}
+	return func(c *config) { c.series = n }
+}
+
+// WithTLS connects over TLS with cfg (typically port 8883).
+func WithTLS(cfg *tls.Config) Option {
+	if cfg == nil {
+		panic("mqtt: WithTLS requires a non-nil config")
+	}
+	return func(c *config) { c.tls = cfg }
+}
+
+// Subscription is one MQTT topic filter bridged into the app. It implements
+// [via.ChangeSource]; hand it to [Plugin] (or App.WatchChanges) to start it.
+type Subscription[T any] struct {
+	broker string
+	filter string
+	cfg    config
+
+	mu      sync.RWMutex
+	latest  Message[T]
+	seen    bool
+	byTopic map[string]Message[T]
+	series  []Message[T]
+}
+
+// Subscribe declares a subscription to filter on the broker at addr
+// ("host:port"). No I/O happens until the app starts watching it. Panics on
+// an empty addr or filter.
+func Subscribe[T any](addr, filter string, opts ...Option) *Subscription[T] {
+	if addr == "" || filter == "" {
+		panic("mqtt: Subscribe requires a broker address and a topic filter")
+	}
+	s := &Subscription[T]{
+		broker:  addr,
+		filter:  filter,
+		cfg:     config{keepAlive: 30 * time.Second, cursor: filter, decode: JSON},
+		byTopic: map[string]Message[T]{},
+	}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+	if s.cfg.clientID == "" {
+		var b [6]byte
+		_, _ = rand.Read(b[:])
+		s.cfg.clientID = "via-" + hex.EncodeToString(b[:])
+	}
+	return s
+}
+
+// Cursor returns the cursor views pass to ctx.Subscribe to re-render on
+// every message.
+func (s *Subscription[T]) Cursor() string { return s.cfg.cursor }
+
+// Latest returns the most recent message on any matching topic and whether
+// one has arrived yet.
+func (s *Subscription[T]) Latest() (Message[T], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest, s.seen
+}
+
+// ByTopic returns a copy of the latest message per concrete topic.
+func (s *Subscription[T]) ByTopic() map[string]Message[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]Message[T], len(s.byTopic))
+	for k, v := range s.byTopic {
+		out[k] = v
+	}
+	return out
+}
+
+// Series returns a copy of the retained messages, oldest first. Empty
+// unless [WithSeries] is set.
+func (s *Subscription[T]) Series() []Message[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Message[T](nil), s.series...)
+}
+
+// Watch connects, subscribes and delivers messages until ctx is cancelled or
+// the connection drops. Every return while the app runs is reconnected by
+// the runtime. Payloads that fail to decode are dropped: a malformed
+// retained message must not wedge the bridge in a reconnect loop.
+func (s *Subscription[T]) Watch(ctx context.Context, notify func(cursor string)) error {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("mqtt: dial %s: %v", s.broker, err)
+	}
+	defer conn.Close()
+	c := &session{conn: conn, r: bufio.NewReader(conn), grace: s.cfg.keepAlive * 3 / 2}
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.write(encodePacket(pktDisconnect, 0, nil))
+		conn.Close()
+	})
+	defer stop()
+
+	if err := s.handshake(c); err != nil {
+		return err
+	}
+
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go c.ping(s.cfg.keepAlive, pingDone)
+
+	// QoS 2 ids delivered but not yet released; a redelivery (DUP) of one of
+	// them must not reach the views twice.
+	inflight := map[uint16]bool{}
+	for {
+		p, err := c.read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("mqtt: read: %v", err)
+		}
+		switch p.kind {
+		case pktPublish:
+			pub, err := parsePublish(p)
+			if err != nil {
+				return err
+			}
+			switch pub.qos {
+			case 1:
+				err = c.write(ackPacket(pktPuback, 0, pub.id))
+			case 2:
+				err = c.write(ackPacket(pktPubrec, 0, pub.id))
+			}
+			if err != nil {
+				return fmt.Errorf("mqtt: ack: %v", err)
+			}
+			if pub.qos == 2 {
+				if inflight[pub.id] {
+					continue
+				}
+				inflight[pub.id] = true
+			}
+			s.deliver(pub, notify)
+		case pktPubrel:
+			if len(p.body) < 2 {
+				return errors.New("mqtt: PUBREL missing packet id")
+			}
+			id := uint16(p.body[0])<<8 | uint16(p.body[1])
+			delete(inflight, id)
+			if err := c.write(ackPacket(pktPubcomp, 0, id)); err != nil {
+				return fmt.Errorf("mqtt: ack: %v", err)
+			}
+		}
+	}
+}
+
+func (s *Subscription[T]) dial(ctx context.Context) (net.Conn, error) {
+	if s.cfg.tls != nil {
+		d := &tls.Dialer{Config: s.cfg.tls}
+		return d.DialContext(ctx, "tcp", s.broker)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", s.broker)
+}
+
+// handshake sends CONNECT and SUBSCRIBE and waits for their acks.
+func (s *Subscription[T]) handshake(c *session) error {
+	keepAlive := uint16(s.cfg.keepAlive / time.Second)
+	if err := c.write(connectPacket(s.cfg.clientID, s.cfg.user, s.cfg.pass, keepAlive)); err != nil {
+		return fmt.Errorf("mqtt: connect: %v", err)
+	}
+	p, err := c.read()
+	if err != nil {
+		return fmt.Errorf("mqtt: connect: %v", err)
+	}
+	if p.kind != pktConnack || len(p.body) < 2 {
+		return fmt.Errorf("mqtt: connect: expected CONNACK, got packet type %d", p.kind)
+	}
+	if p.body[1] != 0 {
+		return connackError(p.body[1])
+	}
+
+	if err := c.write(subscribePacket(1, []string{s.filter}, s.cfg.qos)); err != nil {
+		return fmt.Errorf("mqtt: subscribe: %v", err)
+	}
+	p, err = c.read()
+	if err != nil {
+		return fmt.Errorf("mqtt: subscribe: %v", err)
+	}
+	if p.kind != pktSuback || len(p.body) < 3 {
+		return fmt.Errorf("mqtt: subscribe: expected SUBACK, got packet type %d", p.kind)
+	}
+	if p.body[2] == 0x80 {
+		return fmt.Errorf("mqtt: subscribe %q: refused by broker", s.filter)
+	}
+	return nil
+}
+
+func (s *Subscription[T]) deliver(pub publish, notify func(string)) {
+	var v T
+	if err := s.cfg.decode(pub.payload, &v); err != nil {
+		return
+	}
+	m := Message[T]{Topic: pub.topic, Value: v, At: time.Now()}
+	s.mu.Lock()
+	s.latest = m
+	s.seen = true
+	s.byTopic[pub.topic] = m
+	if s.cfg.series > 0 {
+		s.series = append(s.series, m)
+		if over := len(s.series) - s.cfg.series; over > 0 {
+			s.series = append(s.series[:0], s.series[over:]...)
+		}
+	}
+	s.mu.Unlock()
+	notify(s.cfg.cursor)
+}
+
+// session is one broker connection. Writes come from both the read loop
+// (acks) and the pinger, so they are serialised.
+type session struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	grace time.Duration
+
+	wmu sync.Mutex
+}
+
+func (c *session) read() (packet, error) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.grace))
+	return readPacket(c.r)
+}
+
+func (c *session) write(b []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.grace))
+	_, err := c.conn.Write(b)
+	return err
+}
+
+func (c *session) ping(every time.Duration, done <-chan struct{}) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if c.write(encodePacket(pktPingreq, 0, nil)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// Plugin starts every subscription when the app is constructed and stops
+// them on Shutdown.
+func Plugin(subs ...via.ChangeSource) via.Plugin {
+	return plugin(subs)
+}
+
+type plugin []via.ChangeSource
+
+func (p plugin) Register(app *via.App) {
+	for _, s := range p {
+		app.WatchChanges(s)
+	}
+}
@@ -0,0 +1,186 @@
+package mqtt_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/plugins/mqtt"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reading struct {
+	Celsius float64 `json:"c"`
+	Device  string  `json:"device"`
+}
+
+// broker is a single-connection MQTT 3.1.1 stand-in. It accepts CONNECT and
+// SUBSCRIBE, publishes every payload sent on pub at QoS 1, and reports what
+// the client sent.
+type broker struct {
+	addr      string
+	pub       chan [2]string // topic, payload
+	connect   chan []byte
+	subscribe chan []byte
+	acks      chan uint16
+}
+
+func newBroker(t *testing.T) *broker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	b := &broker{
+		addr:      ln.Addr().String(),
+		pub:       make(chan [2]string, 4),
+		connect:   make(chan []byte, 1),
+		subscribe: make(chan []byte, 1),
+		acks:      make(chan uint16, 4),
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, body := readFrame(conn)
+		b.connect <- body
+		conn.Write([]byte{0x20, 2, 0, 0})
+		_, body = readFrame(conn)
+		b.subscribe <- body
+		conn.Write([]byte{0x90, 3, body[0], body[1], body[len(body)-1]})
+		go func() {
+			for {
+				kind, body := readFrame(conn)
+				if body == nil {
+					return
+				}
+				if kind == 4 {
+					b.acks <- binary.BigEndian.Uint16(body)
+				}
+			}
+		}()
+		for id := uint16(1); ; id++ {
+			m := <-b.pub
+			vh := binary.BigEndian.AppendUint16(nil, uint16(len(m[0])))
+			vh = append(vh, m[0]...)
+			vh = binary.BigEndian.AppendUint16(vh, id)
+			vh = append(vh, m[1]...)
+			if _, err := conn.Write(append([]byte{0x32, byte(len(vh))}, vh...)); err != nil {
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// readFrame reads one packet whose remaining length fits in a single byte.
+func readFrame(r io.Reader) (kind byte, body []byte) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil
+	}
+	body = make([]byte, head[1])
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil
+	}
+	return head[0] >> 4, body
+}
+
+func TestSubscription_decodesPublishesAndAcksQoS1(t *testing.T) {
+	t.Parallel()
+
+	b := newBroker(t)
+	sub := mqtt.Subscribe[reading](b.addr, "sensors/+/temp",
+		mqtt.WithQoS(1), mqtt.WithClientID("dash-1"), mqtt.WithCursor("temps"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notified := make(chan string, 4)
+	go func() { _ = sub.Watch(ctx, func(c string) { notified <- c }) }()
+
+	assert.Contains(t, string(<-b.connect), "dash-1")
+	s := <-b.subscribe
+	assert.Contains(t, string(s), "sensors/+/temp")
+	assert.Equal(t, byte(1), s[len(s)-1], "requested QoS")
+
+	b.pub <- [2]string{"sensors/a/temp", `{"c":21.5,"device":"a"}`}
+	b.pub <- [2]string{"sensors/b/temp", `{"c":19,"device":"b"}`}
+	for range 2 {
+		select {
+		case c := <-notified:
+			assert.Equal(t, "temps", c)
+		case <-time.After(2 * time.Second):
+			require.FailNow(t, "no notification for the publish")
+		}
+	}
+	assert.Equal(t, uint16(1), <-b.acks)
+	assert.Equal(t, uint16(2), <-b.acks)
+
+	m, ok := sub.Latest()
+	require.True(t, ok)
+	assert.Equal(t, "sensors/b/temp", m.Topic)
+	byTopic := sub.ByTopic()
+	assert.Equal(t, 21.5, byTopic["sensors/a/temp"].Value.Celsius)
+	assert.Equal(t, "b", byTopic["sensors/b/temp"].Value.Device)
+}
+
+func TestCBOR_decodesIntoJSONTaggedStruct(t *testing.T) {
+	t.Parallel()
+
+	// {"c": 1.5 (half float), "device": "x"}
+	payload := []byte{0xa2, 0x61, 'c', 0xf9, 0x3e, 0x00, 0x66, 'd', 'e', 'v', 'i', 'c', 'e', 0x61, 'x'}
+	var r reading
+	require.NoError(t, mqtt.CBOR(payload, &r))
+	assert.Equal(t, reading{Celsius: 1.5, Device: "x"}, r)
+
+	assert.Error(t, mqtt.CBOR(payload[:5], &r), "truncated payload")
+}
+
+var tempsForPage *mqtt.Subscription[reading]
+
+type tempPage struct{}
+
+func (p *tempPage) View(ctx *via.CtxR) h.H {
+	ctx.Subscribe(tempsForPage.Cursor())
+	return h.Span(h.ID("n"), h.Textf("points=%d", len(tempsForPage.Series())))
+}
+
+func TestPlugin_rerendersSubscribedViewsOnMessage(t *testing.T) {
+	t.Parallel()
+
+	b := newBroker(t)
+	tempsForPage = mqtt.Subscribe[reading](b.addr, "sensors/#", mqtt.WithSeries(2))
+
+	app := via.New(via.WithPlugins(mqtt.Plugin(tempsForPage)))
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+	via.Mount[tempPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	b.pub <- [2]string{"sensors/a/temp", `{"c":1}`}
+	vt.AwaitFrame(t, frames, 2*time.Second, "points=1")
+	b.pub <- [2]string{"sensors/a/temp", `{"c":2}`}
+	b.pub <- [2]string{"sensors/a/temp", `{"c":3}`}
+	require.Eventually(t, func() bool {
+		m, _ := tempsForPage.Latest()
+		return m.Value.Celsius == 3
+	}, 2*time.Second, 10*time.Millisecond)
+	series := tempsForPage.Series()
+	require.Len(t, series, 2, "series keeps the last n")
+	assert.Equal(t, 2.0, series[0].Value.Celsius)
+}
+
+func TestWithQoS_panicsAboveTwo(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { mqtt.WithQoS(3) })
+}
@@ -0,0 +1,175 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MQTT 3.1.1 control packet types (high nibble of the fixed header).
+const (
+	pktConnect    = 1
+	pktConnack    = 2
+	pktPublish    = 3
+	pktPuback     = 4
+	pktPubrec     = 5
+	pktPubrel     = 6
+	pktPubcomp    = 7
+	pktSubscribe  = 8
+	pktSuback     = 9
+	pktPingreq    = 12
+	pktPingresp   = 13
+	pktDisconnect = 14
+)
+
+type packet struct {
+	kind  byte
+	flags byte
+	body  []byte
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	head, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	n, err := readVarint(r)
+	if err != nil {
+		return packet{}, err
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+	return packet{kind: head >> 4, flags: head & 0x0f, body: body}, nil
+}
+
+func readVarint(r io.ByteReader) (int, error) {
+	n, mult := 0, 1
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		n += int(b&0x7f) * mult
+		if b&0x80 == 0 {
+			return n, nil
+		}
+		mult *= 128
+	}
+	return 0, errors.New("mqtt: malformed remaining length")
+}
+
+func encodePacket(kind, flags byte, body []byte) []byte {
+	out := []byte{kind<<4 | flags}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func readString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, errors.New("mqtt: truncated string")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, errors.New("mqtt: truncated string")
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
+
+func connectPacket(clientID, user, pass string, keepAlive uint16) []byte {
+	var flags byte = 0x02 // clean session: the bridge re-subscribes on every connect
+	if user != "" {
+		flags |= 0x80
+	}
+	if pass != "" {
+		flags |= 0x40
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, keepAlive)
+	body = appendString(body, clientID)
+	if user != "" {
+		body = appendString(body, user)
+	}
+	if pass != "" {
+		body = appendString(body, pass)
+	}
+	return encodePacket(pktConnect, 0, body)
+}
+
+func subscribePacket(id uint16, topics []string, qos byte) []byte {
+	body := binary.BigEndian.AppendUint16(nil, id)
+	for _, t := range topics {
+		body = appendString(body, t)
+		body = append(body, qos)
+	}
+	// SUBSCRIBE's fixed-header flags are reserved as 0b0010.
+	return encodePacket(pktSubscribe, 0x02, body)
+}
+
+func ackPacket(kind, flags byte, id uint16) []byte {
+	return encodePacket(kind, flags, binary.BigEndian.AppendUint16(nil, id))
+}
+
+// publish is an inbound PUBLISH, decoded from its packet.
+type publish struct {
+	topic   string
+	qos     byte
+	id      uint16
+	payload []byte
+}
+
+func parsePublish(p packet) (publish, error) {
+	qos := (p.flags >> 1) & 0x03
+	if qos > 2 {
+		return publish{}, errors.New("mqtt: invalid PUBLISH QoS 3")
+	}
+	topic, rest, err := readString(p.body)
+	if err != nil {
+		return publish{}, err
+	}
+	pub := publish{topic: topic, qos: qos}
+	if qos > 0 {
+		if len(rest) < 2 {
+			return publish{}, errors.New("mqtt: PUBLISH missing packet id")
+		}
+		pub.id = binary.BigEndian.Uint16(rest)
+		rest = rest[2:]
+	}
+	pub.payload = rest
+	return pub, nil
+}
+
+// connackError maps a CONNACK return code to a readable refusal.
+func connackError(code byte) error {
+	reasons := map[byte]string{
+		1: "unacceptable protocol version",
+		2: "client identifier rejected",
+		3: "server unavailable",
+		4: "bad user name or password",
+		5: "not authorized",
+	}
+	if r, ok := reasons[code]; ok {
+		return fmt.Errorf("mqtt: connection refused: %s", r)
+	}
+	return fmt.Errorf("mqtt: connection refused: code %d", code)
+}
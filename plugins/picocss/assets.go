@@ -29,13 +29,20 @@ type asset struct {
 	hash        string
 }
 
-func newAsset(name string) *asset {
+func newAsset(name string) *asset { return assetFrom(name, readEmbedded(name)) }
+
+func readEmbedded(name string) []byte {
 	body, err := embeddedCSS.ReadFile("assets/" + name)
 	if err != nil {
 		// The embedded tree is fixed at compile time; a miss means the
 		// vendored assets are broken, not a runtime condition.
 		panic(fmt.Sprintf("picocss: embedded asset %q missing: %v", name, err))
 	}
+	return body
+}
+
+// assetFrom serves body under name, hashed and precompressed.
+func assetFrom(name string, body []byte) *asset {
 	sum := sha256.Sum256(body)
 	return &asset{
 		name:        name,
@@ -0,0 +1,148 @@
+package picocss
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Variables are the CSS custom properties a custom theme overrides on
+// top of a stock one. Empty fields keep Base's value; the primary
+// variants left empty are derived from Primary. Values are plain CSS
+// values — "#0f62fe", "0.5rem", "'Inter', sans-serif" — applied in both
+// light and dark mode.
+type Variables struct {
+	// Base is the stock theme the custom one starts from. Defaults to
+	// PicoThemeAmber.
+	Base PicoTheme
+
+	Primary           string // links, buttons, focus rings
+	PrimaryBackground string // filled buttons; defaults to Primary
+	PrimaryHover      string // hovered links and buttons; defaults to Primary darkened
+	PrimaryFocus      string // focus ring; defaults to Primary at half opacity
+	PrimaryInverse    string // text on PrimaryBackground; defaults to white
+	FontFamily        string
+	BorderRadius      string
+	Spacing           string
+
+	// Extra sets any other Pico variable, keyed by its name without the
+	// "--pico-" prefix: {"form-element-spacing-vertical": "0.5rem"}.
+	Extra map[string]string
+}
+
+var (
+	// customThemeName is what WithCustomTheme accepts as a theme name.
+	customThemeName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	// picoProperty is a Pico custom property name.
+	picoProperty = regexp.MustCompile(`^--pico-[a-z0-9-]+$`)
+)
+
+// WithCustomTheme adds a theme named name, served as the vars.Base
+// stylesheet with vars applied over it. It is switched to like a stock
+// theme — WithDefaultTheme, SetTheme or the ThemeRef signal — and is
+// available whatever WithThemes lists.
+//
+//	picocss.WithCustomTheme("brand", picocss.Variables{
+//	    Primary:      "#0f62fe",
+//	    BorderRadius: "0",
+//	})
+//
+// Panics on a name that isn't lower-case letters, digits and dashes, one
+// that is a stock theme or taken, an unknown Base, or a value that could
+// break out of its declaration.
+func WithCustomTheme(name string, vars Variables) PicoOption {
+	theme := PicoTheme(name)
+	if !customThemeName.MatchString(name) {
+		panic(fmt.Sprintf("picocss: WithCustomTheme: bad theme name %q — use lower-case letters, digits and dashes", name))
+	}
+	if slices.Contains(AllPicoThemes, theme) {
+		panic(fmt.Sprintf("picocss: WithCustomTheme: %q is a stock theme", name))
+	}
+	if vars.Base == "" {
+		vars.Base = PicoThemeAmber
+	}
+	if !slices.Contains(AllPicoThemes, vars.Base) {
+		panic(fmt.Sprintf("picocss: WithCustomTheme(%q): unknown base theme %q", name, vars.Base))
+	}
+	decls := vars.declarations()
+	for _, d := range decls {
+		if !picoProperty.MatchString(d[0]) {
+			panic(fmt.Sprintf("picocss: WithCustomTheme(%q): bad variable name %q", name, d[0]))
+		}
+		if strings.ContainsAny(d[1], ";{}<>\\") || strings.Contains(d[1], "/*") {
+			panic(fmt.Sprintf("picocss: WithCustomTheme(%q): invalid value %q for %s", name, d[1], d[0]))
+		}
+	}
+	return func(p *plugin) {
+		if slices.ContainsFunc(p.opts.custom, func(c customTheme) bool { return c.name == theme }) {
+			panic(fmt.Sprintf("picocss: WithCustomTheme: theme %q added twice", name))
+		}
+		p.opts.custom = append(p.opts.custom, customTheme{name: theme, base: vars.Base, decls: decls})
+	}
+}
+
+type customTheme struct {
+	name  PicoTheme
+	base  PicoTheme
+	decls [][2]string // property, value
+}
+
+// declarations lists the custom properties vars sets, in a fixed order.
+func (v Variables) declarations() [][2]string {
+	var out [][2]string
+	set := func(name, value string) {
+		if value != "" {
+			out = append(out, [2]string{"--pico-" + name, value})
+		}
+	}
+	if p := v.Primary; p != "" {
+		bg := cmp.Or(v.PrimaryBackground, p)
+		hover := cmp.Or(v.PrimaryHover, "color-mix(in srgb, "+p+" 85%, black)")
+		set("primary", p)
+		set("primary-background", bg)
+		set("primary-border", bg)
+		set("primary-underline", "color-mix(in srgb, "+p+" 50%, transparent)")
+		set("primary-hover", hover)
+		set("primary-hover-background", cmp.Or(v.PrimaryHover, "color-mix(in srgb, "+bg+" 85%, black)"))
+		set("primary-hover-border", "var(--pico-primary-hover-background)")
+		set("primary-hover-underline", "var(--pico-primary-hover)")
+		set("primary-focus", cmp.Or(v.PrimaryFocus, "color-mix(in srgb, "+p+" 50%, transparent)"))
+		set("primary-inverse", cmp.Or(v.PrimaryInverse, "#fff"))
+	} else {
+		set("primary-background", v.PrimaryBackground)
+		set("primary-hover", v.PrimaryHover)
+		set("primary-focus", v.PrimaryFocus)
+		set("primary-inverse", v.PrimaryInverse)
+	}
+	set("font-family", v.FontFamily)
+	set("border-radius", v.BorderRadius)
+	set("spacing", v.Spacing)
+	keys := make([]string, 0, len(v.Extra))
+	for k := range v.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		set(strings.TrimPrefix(k, "--pico-"), v.Extra[k])
+	}
+	return out
+}
+
+// customAsset builds the stylesheet for c: its base theme followed by
+// the overrides. The selector matches every light/dark rule in Pico at
+// equal or higher specificity, and comes later, so it wins in both
+// modes.
+func (p *plugin) customAsset(c customTheme) *asset {
+	base := readEmbedded(p.themeFile(c.base))
+	var b strings.Builder
+	b.Write(base)
+	b.WriteString(":root:not([data-theme]),:root[data-theme]{")
+	for _, d := range c.decls {
+		b.WriteString(d[0] + ":" + d[1] + ";")
+	}
+	b.WriteString("}")
+	return assetFrom(p.themeFile(c.name), []byte(b.String()))
+}
@@ -98,6 +98,8 @@ type pluginOptions struct {
 	classless    bool
 	colorClasses bool
 	darkMode     string // "system" | "dark" | "light"
+	localStorage bool
+	custom       []customTheme
 
 	themesSet       bool
 	defaultThemeSet bool
@@ -126,6 +128,13 @@ func Plugin(opts ...PicoOption) via.Plugin {
 	for _, opt := range opts {
 		opt(p)
 	}
+	custom := make(map[PicoTheme]customTheme, len(p.opts.custom))
+	for _, c := range p.opts.custom {
+		custom[c.name] = c
+	}
+	if _, ok := custom[p.opts.defaultTheme]; !ok && !slices.Contains(AllPicoThemes, p.opts.defaultTheme) {
+		panic(fmt.Sprintf("picocss: unknown theme %q — no embedded asset for it", p.opts.defaultTheme))
+	}
 	// A lone WithDefaultTheme implies that theme is wanted; requiring a
 	// redundant WithThemes([theme]) would be hostile for the common
 	// single-theme app.
@@ -135,6 +144,11 @@ func Plugin(opts ...PicoOption) via.Plugin {
 	if !p.opts.defaultThemeSet {
 		p.opts.defaultTheme = p.opts.themes[0]
 	}
+	for _, c := range p.opts.custom {
+		if !slices.Contains(p.opts.themes, c.name) {
+			p.opts.themes = append(slices.Clip(p.opts.themes), c.name)
+		}
+	}
 	if !slices.Contains(p.opts.themes, p.opts.defaultTheme) {
 		panic(fmt.Sprintf(
 			"picocss: default theme %q is not in WithThemes(%v) — the initial stylesheet would never load",
@@ -144,7 +158,12 @@ func Plugin(opts ...PicoOption) via.Plugin {
 	p.themeAssets = make(map[PicoTheme]*asset, len(p.opts.themes))
 	p.assetsByName = make(map[string]*asset, len(p.opts.themes)+1)
 	for _, theme := range p.opts.themes {
-		a := newAsset(p.themeFile(theme))
+		var a *asset
+		if c, ok := custom[theme]; ok {
+			a = p.customAsset(c)
+		} else {
+			a = newAsset(p.themeFile(theme))
+		}
 		p.themeAssets[theme] = a
 		p.assetsByName[a.name] = a
 	}
@@ -188,17 +207,14 @@ func WithThemes(themes []PicoTheme) PicoOption {
 	}
 }
 
-// WithDefaultTheme sets the initial theme on page load. Panics on an
-// unknown theme or when set twice; Plugin panics when the default is
-// not among the WithThemes list.
+// WithDefaultTheme sets the initial theme on page load, stock or added
+// with WithCustomTheme. Panics when set twice; Plugin panics on an
+// unknown theme or when the default is not among the WithThemes list.
 func WithDefaultTheme(theme PicoTheme) PicoOption {
 	return func(p *plugin) {
 		if p.opts.defaultThemeSet {
 			panic("picocss: WithDefaultTheme set twice — conflicting defaults")
 		}
-		if !slices.Contains(AllPicoThemes, theme) {
-			panic(fmt.Sprintf("picocss: unknown theme %q — no embedded asset for it", theme))
-		}
 		p.opts.defaultTheme = theme
 		p.opts.defaultThemeSet = true
 	}
@@ -302,17 +318,25 @@ func ThemeRef() string { return "$" + themeSignalID }
 // DarkModeRef returns the Datastar reference for the dark-mode signal.
 func DarkModeRef() string { return "$" + darkModeSignalID }
 
+// Name registers the plugin as "picocss", for via.App.Plugin.
+func (p *plugin) Name() string { return "picocss" }
+
 func (p *plugin) Register(v *via.App) {
 	v.RegisterAppSignal(darkModeSignalID, p.opts.darkMode)
 	v.RegisterAppSignal(themeSignalID, string(p.opts.defaultTheme))
 
 	v.AppendAttrToHTML(h.Data("attr:data-theme", darkModeBindExpr))
+	restore := ""
+	if p.opts.localStorage {
+		v.AppendAttrToHTML(h.Data("effect", saveChoiceExpr))
+		restore = restoreChoiceJS
+	}
 
 	// Theme URLs are content-hashed, so the client maps theme name to
 	// URL instead of concatenating a stable prefix.
 	urls := make(map[string]string, len(p.themeAssets))
 	for theme, a := range p.themeAssets {
-		urls[string(theme)] = a.path()
+		urls[string(theme)] = v.BasePath() + a.path()
 	}
 	urlsJSON, err := json.Marshal(urls)
 	if err != nil {
@@ -330,6 +354,7 @@ func (p *plugin) Register(v *via.App) {
 		`var m=document.querySelector('meta[data-signals]');`+
 		`if(!m)return;`+
 		`try{var s=JSON.parse(m.getAttribute('data-signals'));`+
+		restore+
 		`var dm=s._picoDarkMode;`+
 		`if(dm==='dark'||dm==='light')document.documentElement.setAttribute('data-theme',dm);`+
 		`else if(dm==='system')document.documentElement.setAttribute('data-theme',`+
@@ -341,7 +366,7 @@ func (p *plugin) Register(v *via.App) {
 	if p.opts.colorClasses {
 		v.AppendToHead(h.Link(
 			h.Rel("stylesheet"),
-			h.Href(p.colorClassesAsset.path()),
+			h.Href(v.BasePath()+p.colorClassesAsset.path()),
 		))
 	}
 
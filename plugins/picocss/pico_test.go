@@ -1,10 +1,13 @@
 package picocss_test
 
 import (
+	"encoding/json"
+	"html"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/go-via/via"
@@ -384,3 +387,120 @@ func TestPicocss_ThemeRef_andDarkModeRef_returnDatastarExpressions(t *testing.T)
 		"ThemeRef must surface the $-prefixed signal name for inline Datastar expressions")
 	assert.Equal(t, "$_picoDarkMode", picocss.DarkModeRef())
 }
+
+type settingsPage struct{}
+
+func (p *settingsPage) UseBlue(ctx *via.Ctx) { picocss.SetTheme(ctx, picocss.PicoThemeBlue) }
+func (p *settingsPage) UseLime(ctx *via.Ctx) { picocss.SetTheme(ctx, picocss.PicoThemeLime) }
+func (p *settingsPage) GoDark(ctx *via.Ctx)  { picocss.SetDarkMode(ctx, "dark") }
+func (p *settingsPage) View(*via.CtxR) h.H   { return h.Div() }
+
+var signalSeed = regexp.MustCompile(`data-signals="([^"]*)"`)
+
+// seed returns the theme and dark mode a page document opens with.
+func seed(t *testing.T, doc string) (theme, darkMode string) {
+	t.Helper()
+	m := signalSeed.FindStringSubmatch(doc)
+	require.NotNil(t, m, "page must carry a signal seed")
+	var sigs struct {
+		Theme    string `json:"_picoTheme"`
+		DarkMode string `json:"_picoDarkMode"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(html.UnescapeString(m[1])), &sigs))
+	return sigs.Theme, sigs.DarkMode
+}
+
+func TestSetTheme_persistsForTheSession(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithPlugins(picocss.Plugin(
+		picocss.WithThemes([]picocss.PicoTheme{picocss.PicoThemeAmber, picocss.PicoThemeBlue}),
+	)))
+	via.Mount[settingsPage](app, "/")
+	server := vt.Serve(t, app)
+	tc := vt.NewClient(t, server, "/")
+
+	theme, mode := seed(t, tc.HTML())
+	assert.Equal(t, "amber", theme)
+	assert.Equal(t, "system", mode)
+
+	require.Equal(t, http.StatusOK, tc.Action("UseBlue").Fire())
+	require.Equal(t, http.StatusOK, tc.Action("GoDark").Fire())
+	theme, mode = seed(t, tc.Reload())
+	assert.Equal(t, "blue", theme, "a reload opens in the session's theme")
+	assert.Equal(t, "dark", mode)
+
+	require.Equal(t, http.StatusOK, tc.Action("UseLime").Fire())
+	theme, _ = seed(t, tc.Reload())
+	assert.Equal(t, "amber", theme, "a theme the plugin doesn't serve falls back to the default")
+
+	other, _ := seed(t, vt.NewClient(t, server, "/").HTML())
+	assert.Equal(t, "amber", other, "other sessions keep the default")
+}
+
+func TestPicocss_WithLocalStorage_restoresTheBrowsersChoice(t *testing.T) {
+	t.Parallel()
+	assert.NotContains(t, renderPage(t), "localStorage")
+
+	body := renderPage(t, picocss.WithLocalStorage())
+	assert.Contains(t, body, `data-effect="localStorage.setItem(`)
+	assert.Contains(t, body, "localStorage.getItem(")
+}
+
+func TestWithCustomTheme_servesTheBaseWithOverrides(t *testing.T) {
+	t.Parallel()
+	server := serveApp(t,
+		picocss.WithThemes([]picocss.PicoTheme{picocss.PicoThemeBlue}),
+		picocss.WithCustomTheme("brand", picocss.Variables{
+			Base:         picocss.PicoThemeSlate,
+			Primary:      "#0f62fe",
+			BorderRadius: "0",
+			Extra:        map[string]string{"form-element-spacing-vertical": "0.5rem"},
+		}),
+		picocss.WithDefaultTheme("brand"),
+	)
+	resp, err := server.Client().Get(server.URL + "/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	theme, _ := seed(t, string(body))
+	assert.Equal(t, "brand", theme)
+	assert.Contains(t, string(body), "pico.blue.min.css", "stock themes stay available")
+
+	path := regexp.MustCompile(`/via/assets/picocss/[0-9a-f]+/pico\.brand\.min\.css`).FindString(string(body))
+	require.NotEmpty(t, path)
+	asset, err := server.Client().Get(server.URL + path)
+	require.NoError(t, err)
+	defer asset.Body.Close()
+	css, _ := io.ReadAll(asset.Body)
+	assert.Equal(t, "public, max-age=31536000, immutable", asset.Header.Get("Cache-Control"))
+	assert.Contains(t, string(css), "Pico CSS")
+	assert.True(t, strings.HasSuffix(string(css), ":root:not([data-theme]),:root[data-theme]{"+
+		"--pico-primary:#0f62fe;--pico-primary-background:#0f62fe;--pico-primary-border:#0f62fe;"+
+		"--pico-primary-underline:color-mix(in srgb, #0f62fe 50%, transparent);"+
+		"--pico-primary-hover:color-mix(in srgb, #0f62fe 85%, black);"+
+		"--pico-primary-hover-background:color-mix(in srgb, #0f62fe 85%, black);"+
+		"--pico-primary-hover-border:var(--pico-primary-hover-background);"+
+		"--pico-primary-hover-underline:var(--pico-primary-hover);"+
+		"--pico-primary-focus:color-mix(in srgb, #0f62fe 50%, transparent);"+
+		"--pico-primary-inverse:#fff;--pico-border-radius:0;"+
+		"--pico-form-element-spacing-vertical:0.5rem;}"), "overrides come last so they win")
+}
+
+func TestWithCustomTheme_panicsOnBadInput(t *testing.T) {
+	t.Parallel()
+	for name, build := range map[string]func(){
+		"stock name":     func() { picocss.WithCustomTheme("blue", picocss.Variables{}) },
+		"bad name":       func() { picocss.WithCustomTheme("My Brand", picocss.Variables{}) },
+		"unknown base":   func() { picocss.WithCustomTheme("brand", picocss.Variables{Base: "mauve"}) },
+		"breaking value": func() { picocss.WithCustomTheme("brand", picocss.Variables{Primary: "red;}body{x:y"}) },
+		"bad variable": func() {
+			picocss.WithCustomTheme("brand", picocss.Variables{Extra: map[string]string{"a b": "1"}})
+		},
+		"added twice": func() {
+			picocss.Plugin(picocss.WithCustomTheme("brand", picocss.Variables{}),
+				picocss.WithCustomTheme("brand", picocss.Variables{}))
+		},
+	} {
+		assert.Panics(t, build, name)
+	}
+}
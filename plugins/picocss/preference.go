@@ -0,0 +1,85 @@
+package picocss
+
+import (
+	"github.com/go-via/via"
+	"github.com/go-via/via/sess"
+)
+
+// preference is the theme and dark mode chosen for a session with
+// SetTheme / SetDarkMode. Empty fields fall back to the plugin's
+// defaults.
+type preference struct {
+	Theme    PicoTheme
+	DarkMode string
+}
+
+// SetTheme switches the tab to theme and remembers it for the session,
+// so later page loads open with it. The theme must be one the plugin
+// serves (see WithThemes and WithCustomTheme); page loads ignore any
+// other.
+//
+//	func (p *Settings) UseBlue(ctx *via.Ctx) { picocss.SetTheme(ctx, picocss.PicoThemeBlue) }
+func SetTheme(ctx *via.Ctx, theme PicoTheme) {
+	if ctx == nil || theme == "" {
+		return
+	}
+	pref, _ := sess.Get[preference](ctx)
+	pref.Theme = theme
+	sess.Put(ctx, pref)
+	ctx.Patch().Signal(themeSignalID, string(theme))
+}
+
+// SetDarkMode is SetTheme for the dark-mode signal: mode is "system",
+// "dark" or "light"; anything else is ignored.
+func SetDarkMode(ctx *via.Ctx, mode string) {
+	if ctx == nil || !validDarkMode(mode) {
+		return
+	}
+	pref, _ := sess.Get[preference](ctx)
+	pref.DarkMode = mode
+	sess.Put(ctx, pref)
+	ctx.Patch().Signal(darkModeSignalID, mode)
+}
+
+func validDarkMode(mode string) bool {
+	return mode == "system" || mode == "dark" || mode == "light"
+}
+
+// WithLocalStorage remembers the theme and dark mode in the browser, so
+// a choice made client-side — a button setting ThemeRef() — survives a
+// reload. The browser's last choice wins over the session's on page
+// load; SetTheme and SetDarkMode update both.
+func WithLocalStorage() PicoOption { return func(p *plugin) { p.opts.localStorage = true } }
+
+// BeforePageRender seeds the page with the session's preference, so it
+// paints in the chosen theme from the first frame.
+func (p *plugin) BeforePageRender(ctx *via.Ctx) {
+	pref, ok := sess.Get[preference](ctx)
+	if !ok {
+		return
+	}
+	sigs := map[string]any{}
+	if _, served := p.themeAssets[pref.Theme]; served {
+		sigs[themeSignalID] = string(pref.Theme)
+	}
+	if validDarkMode(pref.DarkMode) {
+		sigs[darkModeSignalID] = pref.DarkMode
+	}
+	ctx.Patch().Signals(sigs)
+}
+
+// localStorageKey holds {"theme":…,"darkMode":…} under WithLocalStorage.
+const localStorageKey = "via-picocss"
+
+// saveChoiceExpr is the data-effect that stores the signals whenever
+// either changes.
+const saveChoiceExpr = `localStorage.setItem('` + localStorageKey + `',` +
+	`JSON.stringify({theme:$_picoTheme,darkMode:$_picoDarkMode}))`
+
+// restoreChoiceJS runs in the head script before Datastar reads the
+// signal seed: it writes the stored choice into the seed. u is the
+// theme URL map, s the parsed seed, m its meta element.
+const restoreChoiceJS = `try{var c=JSON.parse(localStorage.getItem('` + localStorageKey + `')||'{}');` +
+	`if(c.theme&&u[c.theme])s._picoTheme=c.theme;` +
+	`if(c.darkMode==='system'||c.darkMode==='dark'||c.darkMode==='light')s._picoDarkMode=c.darkMode;` +
+	`m.setAttribute('data-signals',JSON.stringify(s));}catch(e){}`
@@ -0,0 +1,333 @@
+// Package pwa makes a Via app installable: it serves a web app manifest,
+// registers a service worker and shows an offline page when the network
+// is gone.
+//
+//	app := via.New(via.WithPlugins(
+//	    pwa.Plugin("Acme Tasks",
+//	        pwa.WithShortName("Tasks"),
+//	        pwa.WithThemeColor("#0f62fe"),
+//	        pwa.WithIcon("/static/icon-192.png", "192x192"),
+//	        pwa.WithIcon("/static/icon-512.png", "512x512"),
+//	    ),
+//	))
+//
+// The service worker keeps hashed assets (/via/assets/…, immutable by
+// construction) and the URLs passed to WithPrecache in a cache, and
+// answers a page navigation it can't reach the server for with the
+// offline page. Pages themselves are never served from the cache: every
+// render mints a live tab, so a cached page would be a dead one. Actions
+// and the SSE stream always go to the network.
+package pwa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+)
+
+const (
+	pathPrefix   = "/_plugins/pwa/"
+	manifestPath = pathPrefix + "manifest.webmanifest"
+	workerPath   = pathPrefix + "sw.js"
+	registerPath = pathPrefix + "register.js"
+	offlinePath  = pathPrefix + "offline"
+)
+
+// Option configures the plugin.
+type Option func(*plugin)
+
+// Icon is one entry of the manifest's icons list.
+type Icon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Type    string `json:"type,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// WithShortName sets the name shown under the home-screen icon, where
+// the full name doesn't fit. Defaults to the name.
+func WithShortName(name string) Option { return func(p *plugin) { p.shortName = name } }
+
+// WithDescription sets the manifest description.
+func WithDescription(desc string) Option { return func(p *plugin) { p.description = desc } }
+
+// WithThemeColor sets the browser UI color, in the manifest and a
+// theme-color meta tag.
+func WithThemeColor(color string) Option { return func(p *plugin) { p.themeColor = color } }
+
+// WithBackgroundColor sets the splash screen color shown while the app
+// starts. Defaults to white.
+func WithBackgroundColor(color string) Option { return func(p *plugin) { p.background = color } }
+
+// WithDisplay sets the manifest display mode: "standalone" (the
+// default), "fullscreen", "minimal-ui" or "browser".
+func WithDisplay(mode string) Option {
+	switch mode {
+	case "standalone", "fullscreen", "minimal-ui", "browser":
+	default:
+		panic(fmt.Sprintf("pwa: WithDisplay: unknown display mode %q", mode))
+	}
+	return func(p *plugin) { p.display = mode }
+}
+
+// WithStartURL sets the page the installed app opens, relative to the
+// app's base path. Defaults to "/".
+func WithStartURL(path string) Option {
+	if !strings.HasPrefix(path, "/") {
+		panic(fmt.Sprintf("pwa: WithStartURL: %q must start with /", path))
+	}
+	return func(p *plugin) { p.startURL = path }
+}
+
+// WithIcon adds a home-screen icon. sizes is the manifest form, e.g.
+// "192x192"; install prompts want at least a 192 and a 512 pixel icon.
+// The image type is taken from the file extension.
+func WithIcon(src, sizes string) Option {
+	return WithIcons(Icon{Src: src, Sizes: sizes})
+}
+
+// WithIcons adds icons with every manifest field spelled out — a
+// maskable icon, say.
+func WithIcons(icons ...Icon) Option {
+	return func(p *plugin) { p.icons = append(p.icons, icons...) }
+}
+
+// WithOfflinePage replaces the default offline page's body. It is
+// rendered once at boot into a self-contained document: keep it to
+// inline content, as it is shown with no network.
+func WithOfflinePage(body h.H) Option { return func(p *plugin) { p.offline = body } }
+
+// WithPrecache adds same-origin URLs the service worker caches when it
+// installs — stylesheets, images and fonts the offline page or your
+// pages need. Paths are relative to the app's base path.
+func WithPrecache(paths ...string) Option {
+	return func(p *plugin) { p.precache = append(p.precache, paths...) }
+}
+
+type plugin struct {
+	name, shortName, description string
+	themeColor, background       string
+	display, startURL            string
+	icons                        []Icon
+	offline                      h.H
+	precache                     []string
+
+	manifest, worker, offlineDoc []byte
+}
+
+// Plugin builds the PWA plugin for an app called name. Panics on an
+// empty name.
+func Plugin(name string, opts ...Option) via.Plugin {
+	if name == "" {
+		panic("pwa: Plugin: the app needs a name")
+	}
+	p := &plugin{name: name, background: "#ffffff", display: "standalone", startURL: "/"}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.shortName == "" {
+		p.shortName = name
+	}
+	if p.offline == nil {
+		p.offline = h.Main(
+			h.H1(h.Text("You're offline")),
+			h.P(h.Text("Check your connection, then try again.")),
+			h.Button(h.Attr("onclick", "location.reload()"), h.Text("Retry")),
+		)
+	}
+	return p
+}
+
+// Name registers the plugin as "pwa", for via.App.Plugin.
+func (p *plugin) Name() string { return "pwa" }
+
+func (p *plugin) Register(app *via.App) {
+	base := app.BasePath()
+	p.manifest = p.buildManifest(base)
+	p.offlineDoc = p.buildOffline()
+	p.worker = p.buildWorker(base)
+
+	app.HandleFunc("GET "+manifestPath, p.serve("application/manifest+json", p.manifest))
+	app.HandleFunc("GET "+workerPath, p.serveWorker(base))
+	app.HandleFunc("GET "+registerPath, p.serve("text/javascript; charset=utf-8", []byte(registerJS)))
+	app.HandleFunc("GET "+offlinePath, p.serve("text/html; charset=utf-8", p.offlineDoc))
+
+	app.AppendToHead(h.Link(h.Rel("manifest"), h.Href(base+manifestPath)))
+	if p.themeColor != "" {
+		app.AppendToHead(h.Meta(h.Name("theme-color"), h.Content(p.themeColor)))
+	}
+	app.AppendToFoot(h.Script(h.Src(base+registerPath),
+		h.Data("sw", base+workerPath), h.Data("scope", base+"/")))
+}
+
+type manifest struct {
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	Description     string `json:"description,omitempty"`
+	StartURL        string `json:"start_url"`
+	Scope           string `json:"scope"`
+	Display         string `json:"display"`
+	BackgroundColor string `json:"background_color"`
+	ThemeColor      string `json:"theme_color,omitempty"`
+	Icons           []Icon `json:"icons"`
+}
+
+func (p *plugin) buildManifest(base string) []byte {
+	icons := make([]Icon, len(p.icons))
+	for i, ic := range p.icons {
+		if ic.Type == "" {
+			ic.Type = iconType(ic.Src)
+		}
+		if strings.HasPrefix(ic.Src, "/") {
+			ic.Src = base + ic.Src
+		}
+		icons[i] = ic
+	}
+	b, err := json.MarshalIndent(manifest{
+		Name:            p.name,
+		ShortName:       p.shortName,
+		Description:     p.description,
+		StartURL:        base + p.startURL,
+		Scope:           base + "/",
+		Display:         p.display,
+		BackgroundColor: p.background,
+		ThemeColor:      p.themeColor,
+		Icons:           icons,
+	}, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("pwa: encode manifest: %v", err))
+	}
+	return b
+}
+
+func iconType(src string) string {
+	switch {
+	case strings.HasSuffix(src, ".png"):
+		return "image/png"
+	case strings.HasSuffix(src, ".svg"):
+		return "image/svg+xml"
+	case strings.HasSuffix(src, ".webp"):
+		return "image/webp"
+	case strings.HasSuffix(src, ".ico"):
+		return "image/x-icon"
+	}
+	return ""
+}
+
+func (p *plugin) buildOffline() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<!doctype html>")
+	head := []h.H{
+		h.Meta(h.Charset("utf-8")),
+		h.Meta(h.Name("viewport"), h.Content("width=device-width, initial-scale=1")),
+		h.Title(p.name),
+		h.StyleEl(h.Raw(offlineCSS)),
+	}
+	if p.themeColor != "" {
+		head = append(head, h.Meta(h.Name("theme-color"), h.Content(p.themeColor)))
+	}
+	if err := h.HTML(h.Head(head...), h.Body(p.offline)).Render(&buf); err != nil {
+		panic(fmt.Sprintf("pwa: render offline page: %v", err))
+	}
+	return buf.Bytes()
+}
+
+const offlineCSS = `body{font-family:system-ui,sans-serif;display:grid;place-items:center;` +
+	`min-height:100vh;margin:0;text-align:center;padding:1rem}`
+
+// buildWorker fills the worker template. Its cache name carries a hash
+// of the worker's inputs, so a deploy that changes them installs a
+// fresh cache and the activate step drops the old one.
+func (p *plugin) buildWorker(base string) []byte {
+	precache := []string{base + offlinePath}
+	for _, u := range p.precache {
+		if strings.HasPrefix(u, "/") {
+			u = base + u
+		}
+		precache = append(precache, u)
+	}
+	list, _ := json.Marshal(precache)
+	sum := sha256.New()
+	sum.Write(list)
+	sum.Write(p.offlineDoc)
+	version := hex.EncodeToString(sum.Sum(nil)[:8])
+	js := strings.NewReplacer(
+		"__CACHE__", `"via-pwa-`+version+`"`,
+		"__PRECACHE__", string(list),
+		"__OFFLINE__", jsString(base+offlinePath),
+		"__ASSETS__", jsString(base+"/via/assets/"),
+		"__SKIP__", jsString(base+"/_"),
+	).Replace(workerJS)
+	return []byte(js)
+}
+
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (p *plugin) serve(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(body)
+	}
+}
+
+// serveWorker serves the worker from under /_plugins/ while letting it
+// control the whole app.
+func (p *plugin) serveWorker(base string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Service-Worker-Allowed", base+"/")
+		_, _ = w.Write(p.worker)
+	}
+}
+
+// registerJS registers the worker named by its own script tag.
+const registerJS = `(function(){
+var s=document.currentScript;
+if(!('serviceWorker' in navigator)||!s)return;
+navigator.serviceWorker.register(s.dataset.sw,{scope:s.dataset.scope}).catch(function(){});
+})();
+`
+
+// workerJS is the service worker. Hashed assets are cache-first (their
+// URL changes with their content); navigations are network-only with
+// the offline page as the fallback; via's own endpoints and anything
+// not a GET are left to the browser.
+const workerJS = `const CACHE=__CACHE__,OFFLINE=__OFFLINE__,ASSETS=__ASSETS__,SKIP=__SKIP__;
+self.addEventListener('install',e=>{
+  e.waitUntil(caches.open(CACHE).then(c=>c.addAll(__PRECACHE__)).then(()=>self.skipWaiting()));
+});
+self.addEventListener('activate',e=>{
+  e.waitUntil(caches.keys().then(ks=>Promise.all(
+    ks.filter(k=>k.startsWith('via-pwa-')&&k!==CACHE).map(k=>caches.delete(k))
+  )).then(()=>self.clients.claim()));
+});
+self.addEventListener('fetch',e=>{
+  const r=e.request,u=new URL(r.url);
+  if(r.method!=='GET'||u.origin!==location.origin)return;
+  if(r.mode==='navigate'){
+    e.respondWith(fetch(r).catch(()=>caches.match(OFFLINE)));
+    return;
+  }
+  if(u.pathname.startsWith(ASSETS)){
+    e.respondWith(caches.open(CACHE).then(c=>c.match(r).then(hit=>hit||fetch(r).then(res=>{
+      if(res.ok)c.put(r,res.clone());
+      return res;
+    }))));
+    return;
+  }
+  if(u.pathname.startsWith(SKIP))return;
+  e.respondWith(caches.match(r).then(hit=>hit||fetch(r)));
+});
+`
@@ -0,0 +1,117 @@
+package pwa_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/plugins/pwa"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page struct{}
+
+func (p *page) View(ctx *via.CtxR) h.H { return h.P(h.T("hello")) }
+
+func get(t *testing.T, url string) (string, *http.Response) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(b), resp
+}
+
+func TestPlugin_servesTheManifest(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"), via.WithPlugins(pwa.Plugin("Acme Tasks",
+		pwa.WithShortName("Tasks"),
+		pwa.WithThemeColor("#0f62fe"),
+		pwa.WithIcon("/static/icon-192.png", "192x192"),
+		pwa.WithIcons(pwa.Icon{Src: "https://cdn.example/i.svg", Sizes: "any", Purpose: "maskable"}),
+	)))
+	via.Mount[page](app, "/")
+	srv := vt.Serve(t, app)
+
+	body, resp := get(t, srv.URL+"/app/_plugins/pwa/manifest.webmanifest")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/manifest+json", resp.Header.Get("Content-Type"))
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &m))
+	assert.Equal(t, "Acme Tasks", m["name"])
+	assert.Equal(t, "Tasks", m["short_name"])
+	assert.Equal(t, "/app/", m["start_url"])
+	assert.Equal(t, "/app/", m["scope"])
+	assert.Equal(t, "standalone", m["display"])
+	assert.Equal(t, "#0f62fe", m["theme_color"])
+	assert.Equal(t, []any{
+		map[string]any{"src": "/app/static/icon-192.png", "sizes": "192x192", "type": "image/png"},
+		map[string]any{"src": "https://cdn.example/i.svg", "sizes": "any", "type": "image/svg+xml", "purpose": "maskable"},
+	}, m["icons"])
+}
+
+func TestPlugin_linksTheManifestAndRegistersTheWorker(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"), via.WithPlugins(pwa.Plugin("Acme", pwa.WithThemeColor("#123456"))))
+	via.Mount[page](app, "/")
+	html := vt.NewClient(t, vt.Serve(t, app), "/").HTML()
+
+	assert.Contains(t, html, `<link rel="manifest" href="/app/_plugins/pwa/manifest.webmanifest">`)
+	assert.Contains(t, html, `<meta name="theme-color" content="#123456">`)
+	assert.Contains(t, html, `src="/app/_plugins/pwa/register.js" data-sw="/app/_plugins/pwa/sw.js" data-scope="/app/"`)
+}
+
+func TestPlugin_workerControlsTheWholeApp(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithBasePath("/app"), via.WithPlugins(pwa.Plugin("Acme", pwa.WithPrecache("/static/site.css"))))
+	srv := vt.Serve(t, app)
+
+	js, resp := get(t, srv.URL+"/app/_plugins/pwa/sw.js")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/app/", resp.Header.Get("Service-Worker-Allowed"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	assert.Contains(t, js, `["/app/_plugins/pwa/offline","/app/static/site.css"]`)
+	assert.Contains(t, js, `ASSETS="/app/via/assets/"`)
+	assert.Regexp(t, `CACHE="via-pwa-[0-9a-f]{16}"`, js)
+	assert.NotContains(t, js, "__", "every placeholder must be filled")
+}
+
+func TestPlugin_cacheNameFollowsTheContent(t *testing.T) {
+	t.Parallel()
+	worker := func(opts ...pwa.Option) string {
+		app := via.New(via.WithPlugins(pwa.Plugin("Acme", opts...)))
+		js, _ := get(t, vt.Serve(t, app).URL+"/_plugins/pwa/sw.js")
+		return js
+	}
+	assert.Equal(t, worker(), worker(), "same config, same worker: no needless reinstall")
+	assert.NotEqual(t, worker(), worker(pwa.WithOfflinePage(h.P(h.T("gone")))))
+}
+
+func TestPlugin_servesTheOfflinePage(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithPlugins(pwa.Plugin("Acme",
+		pwa.WithOfflinePage(h.Main(h.H1(h.T("No signal <here>")))),
+	)))
+	srv := vt.Serve(t, app)
+
+	body, resp := get(t, srv.URL+"/_plugins/pwa/offline")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, "<title>Acme</title>")
+	assert.Contains(t, body, "No signal &lt;here&gt;")
+	assert.NotContains(t, body, "datastar", "the offline page must not need the network")
+}
+
+func TestPlugin_rejectsBadConfig(t *testing.T) {
+	t.Parallel()
+	assert.Panics(t, func() { pwa.Plugin("") })
+	assert.Panics(t, func() { pwa.WithDisplay("kiosk") })
+	assert.Panics(t, func() { pwa.WithStartURL("home") })
+}
@@ -0,0 +1,44 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+//go:embed assets/webauthn.js
+var webauthnJS []byte
+
+const assetPathPrefix = "/via/assets/webauthn/"
+
+// asset is the embedded client script, content-hashed at init so its URL
+// changes whenever the body does and can be cached immutably.
+type asset struct {
+	name string
+	body []byte
+	hash string
+}
+
+var clientJS = newAsset("webauthn.js", webauthnJS)
+
+func newAsset(name string, body []byte) *asset {
+	sum := sha256.Sum256(body)
+	return &asset{name: name, body: body, hash: hex.EncodeToString(sum[:8])}
+}
+
+func (a *asset) path() string { return assetPathPrefix + a.hash + "/" + a.name }
+
+func serveAsset(w http.ResponseWriter, r *http.Request) {
+	hash, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, assetPathPrefix), "/")
+	// A stale hash means the script changed under a cached page; serving the
+	// new body at the old URL would poison caches.
+	if !ok || name != clientJS.name || hash != clientJS.hash {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/javascript")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	_, _ = w.Write(clientJS.body)
+}
@@ -0,0 +1,47 @@
+// Client half of the via webauthn plugin. The server starts a ceremony by
+// calling viaWebAuthn.create or viaWebAuthn.get with the ceremony options
+// and the id of the Ceremony element; the prompt's result (or the name of
+// the error that ended it) is written to that element's bound signal as
+// JSON, then the element fires "webauthn-done" for the finishing action.
+(() => {
+  const enc = (buf) =>
+    btoa(String.fromCharCode(...new Uint8Array(buf)))
+      .replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+  const dec = (s) =>
+    Uint8Array.from(atob(s.replace(/-/g, "+").replace(/_/g, "/")), (c) => c.charCodeAt(0));
+  const reply = (id, value) => {
+    const el = document.getElementById(id);
+    if (!el) return;
+    el.value = JSON.stringify(value);
+    el.dispatchEvent(new Event("input", { bubbles: true }));
+    el.dispatchEvent(new CustomEvent("webauthn-done", { bubbles: true }));
+  };
+  const fail = (id, err) => reply(id, { error: (err && err.name) || "Error" });
+  const run = (id, kind, options, encode) => {
+    if (!window.PublicKeyCredential) return fail(id, { name: "NotSupportedError" });
+    (options.allowCredentials || []).concat(options.excludeCredentials || [])
+      .forEach((c) => { c.id = dec(c.id); });
+    options.challenge = dec(options.challenge);
+    navigator.credentials[kind]({ publicKey: options }).then(
+      (c) => reply(id, { id: c.id, type: c.type, response: encode(c.response) }),
+      (err) => fail(id, err),
+    );
+  };
+  window.viaWebAuthn = {
+    create(options, id) {
+      options.user.id = dec(options.user.id);
+      run(id, "create", options, (r) => ({
+        clientDataJSON: enc(r.clientDataJSON),
+        attestationObject: enc(r.attestationObject),
+      }));
+    },
+    get(options, id) {
+      run(id, "get", options, (r) => ({
+        clientDataJSON: enc(r.clientDataJSON),
+        authenticatorData: enc(r.authenticatorData),
+        signature: enc(r.signature),
+        userHandle: r.userHandle ? enc(r.userHandle) : "",
+      }));
+    },
+  };
+})();
@@ -0,0 +1,149 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// CTAP2 encodes attestation objects and COSE keys in canonical CBOR: every
+// length is definite and map keys are integers or text. This decoder reads
+// exactly that subset — unsigned and negative integers as int64, byte
+// strings as []byte, text as string, arrays as []any and maps as
+// map[any]any — and rejects the rest.
+
+// cborMaxDepth bounds nesting so a hostile payload cannot exhaust the stack.
+const cborMaxDepth = 16
+
+var errCBORTruncated = errors.New("webauthn: cbor: truncated data")
+
+type cborDecoder struct {
+	b   []byte
+	off int
+}
+
+// decodeCBOR decodes the single item at the start of b and returns it with
+// the number of bytes it took, so a caller can find what follows it.
+func decodeCBOR(b []byte) (any, int, error) {
+	d := cborDecoder{b: b}
+	v, err := d.value(0)
+	return v, d.off, err
+}
+
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.b)-d.off) {
+		return nil, errCBORTruncated
+	}
+	out := d.b[d.off : d.off+int(n)]
+	d.off += int(n)
+	return out, nil
+}
+
+func (d *cborDecoder) arg(ai byte) (uint64, error) {
+	switch {
+	case ai < 24:
+		return uint64(ai), nil
+	case ai == 24:
+		b, err := d.take(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(b[0]), nil
+	case ai == 25:
+		b, err := d.take(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case ai == 26:
+		b, err := d.take(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case ai == 27:
+		b, err := d.take(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	}
+	return 0, fmt.Errorf("webauthn: cbor: unsupported additional info %d", ai)
+}
+
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("webauthn: cbor: nesting too deep")
+	}
+	head, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	major, ai := head[0]>>5, head[0]&0x1f
+	if major == 7 {
+		switch ai {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("webauthn: cbor: unsupported simple value %d", ai)
+	}
+	n, err := d.arg(ai)
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case 0, 1:
+		if n > 1<<63-1 {
+			return nil, errors.New("webauthn: cbor: integer out of range")
+		}
+		if major == 1 {
+			return -int64(n) - 1, nil
+		}
+		return int64(n), nil
+	case 2:
+		return d.take(n)
+	case 3:
+		b, err := d.take(n)
+		return string(b), err
+	case 4:
+		if n > uint64(len(d.b)-d.off) {
+			return nil, errCBORTruncated
+		}
+		out := make([]any, 0, n)
+		for range n {
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case 5:
+		if n > uint64(len(d.b)-d.off) {
+			return nil, errCBORTruncated
+		}
+		out := make(map[any]any, n)
+		for range n {
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("webauthn: cbor: unsupported map key %T", k)
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = v
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("webauthn: cbor: unsupported major type %d", major)
+}
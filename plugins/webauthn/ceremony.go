@@ -0,0 +1,336 @@
+package webauthn
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+)
+
+// User is the account a passkey is registered for.
+type User struct {
+	// ID is an opaque, stable handle of at most 64 bytes. It is stored on
+	// the authenticator and returned at login, so it must not carry
+	// personal data — use a random id, not an email address.
+	ID []byte
+	// Name is the account identifier shown in the prompt, e.g. an email.
+	Name string
+	// DisplayName is the human-friendly name; defaults to Name.
+	DisplayName string
+}
+
+// Ceremony is the child composition that carries one tab's ceremonies:
+// it holds the challenge between the begin and finish actions and renders
+// the hidden element the browser answers through. Embed it as a pointer
+// field of the page and render its View once.
+type Ceremony struct {
+	// Response is the authenticator's answer, written by the client script.
+	Response via.Signal[string] `via:"response"`
+
+	mu      sync.Mutex
+	pending *pending
+}
+
+// pending is a begun ceremony awaiting its finish.
+type pending struct {
+	kind      string // "webauthn.create" or "webauthn.get"
+	challenge []byte
+	userID    []byte
+	expires   time.Time
+}
+
+// View renders the element the client script answers through. onDone is
+// the binding that runs the finishing action — on.Event(DoneEvent, …).
+func (c *Ceremony) View(ctx *via.CtxR, onDone h.H) h.H {
+	return h.Input(h.Type("hidden"), h.ID(ctx.ScopedID(c, "webauthn")), c.Response.Bind(), onDone)
+}
+
+// begin records a fresh challenge for kind, open until expires, and
+// returns it.
+func (c *Ceremony) begin(kind string, userID []byte, expires time.Time) []byte {
+	challenge := make([]byte, 32)
+	_, _ = rand.Read(challenge)
+	c.mu.Lock()
+	c.pending = &pending{kind: kind, challenge: challenge, userID: userID, expires: expires}
+	c.mu.Unlock()
+	return challenge
+}
+
+// take consumes the pending ceremony of kind: a challenge answers at most
+// one finish, so a replayed response is refused.
+func (c *Ceremony) take(kind string, now time.Time) (*pending, error) {
+	c.mu.Lock()
+	p := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	if p == nil || p.kind != kind || now.After(p.expires) {
+		return nil, errors.New("webauthn: no ceremony in progress")
+	}
+	return p, nil
+}
+
+// response is the client script's JSON for either ceremony.
+type response struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Error    string `json:"error"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AttestationObject string `json:"attestationObject"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// readResponse takes the client's answer off the ceremony's signal,
+// clearing it so a later finish can't act on the same value.
+func (c *Ceremony) readResponse(ctx *via.Ctx) (response, error) {
+	raw := c.Response.Read(ctx)
+	c.Response.Write(ctx, "")
+	var resp response
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		return response{}, fmt.Errorf("webauthn: malformed response: %v", err)
+	}
+	if resp.Error != "" {
+		return response{}, fmt.Errorf("webauthn: ceremony cancelled: %s", resp.Error)
+	}
+	if resp.Type != "public-key" {
+		return response{}, fmt.Errorf("webauthn: credential type %q", resp.Type)
+	}
+	return resp, nil
+}
+
+// decodeFields base64url-decodes each field, failing on the first bad one.
+func decodeFields(fields ...string) ([][]byte, error) {
+	out := make([][]byte, len(fields))
+	for i, f := range fields {
+		b, err := b64.DecodeString(f)
+		if err != nil {
+			return nil, errors.New("webauthn: malformed response encoding")
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+// credentialDescriptor names a credential in ceremony options.
+type credentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// BeginRegistration starts creating a passkey for user: it issues a
+// challenge and opens the browser prompt on c's tab. Call it from an
+// action; the user's answer arrives through c and the action bound to
+// [DoneEvent], which calls FinishRegistration. Passkeys user already has
+// are excluded, so an authenticator is not registered twice.
+func (rp *RP) BeginRegistration(ctx *via.Ctx, c *Ceremony, user User) error {
+	if len(user.ID) == 0 || len(user.ID) > 64 || user.Name == "" {
+		return errors.New("webauthn: user needs an ID of 1 to 64 bytes and a Name")
+	}
+	existing, err := rp.store.Credentials(storeContext(ctx), user.ID)
+	if err != nil {
+		return err
+	}
+	exclude := make([]credentialDescriptor, 0, len(existing))
+	for _, cred := range existing {
+		exclude = append(exclude, credentialDescriptor{Type: "public-key", ID: b64.EncodeToString(cred.ID)})
+	}
+	params := make([]map[string]any, 0, len(supportedAlgs))
+	for _, alg := range supportedAlgs {
+		params = append(params, map[string]any{"type": "public-key", "alg": alg})
+	}
+	challenge := c.begin("webauthn.create", slices.Clone(user.ID), rp.now().Add(rp.timeout))
+	return rp.prompt(ctx, c, "create", map[string]any{
+		"rp": map[string]string{"id": rp.id, "name": rp.name},
+		"user": map[string]string{
+			"id":          b64.EncodeToString(user.ID),
+			"name":        user.Name,
+			"displayName": cmp.Or(user.DisplayName, user.Name),
+		},
+		"challenge":          b64.EncodeToString(challenge),
+		"pubKeyCredParams":   params,
+		"timeout":            rp.timeout.Milliseconds(),
+		"attestation":        "none",
+		"excludeCredentials": exclude,
+		"authenticatorSelection": map[string]string{
+			"residentKey":      "required",
+			"userVerification": string(rp.verification),
+		},
+	})
+}
+
+// FinishRegistration verifies the browser's answer to BeginRegistration
+// and saves the new credential to the store. Call it from the action
+// bound to [DoneEvent].
+func (rp *RP) FinishRegistration(ctx *via.Ctx, c *Ceremony) (Credential, error) {
+	p, err := c.take("webauthn.create", rp.now())
+	if err != nil {
+		return Credential{}, err
+	}
+	resp, err := c.readResponse(ctx)
+	if err != nil {
+		return Credential{}, err
+	}
+	fields, err := decodeFields(resp.ID, resp.Response.ClientDataJSON, resp.Response.AttestationObject)
+	if err != nil {
+		return Credential{}, err
+	}
+	rawID, clientJSON, attObj := fields[0], fields[1], fields[2]
+	clientHash, err := rp.checkClientData(clientJSON, p.kind, p.challenge)
+	if err != nil {
+		return Credential{}, err
+	}
+	v, n, err := decodeCBOR(attObj)
+	if err != nil {
+		return Credential{}, fmt.Errorf("webauthn: attestation object: %v", err)
+	}
+	att, ok := v.(map[any]any)
+	if !ok || n != len(attObj) {
+		return Credential{}, errors.New("webauthn: malformed attestation object")
+	}
+	format, _ := att["fmt"].(string)
+	stmt, _ := att["attStmt"].(map[any]any)
+	authRaw, _ := att["authData"].([]byte)
+	ad, err := parseAuthData(authRaw)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := rp.checkAuthData(ad); err != nil {
+		return Credential{}, err
+	}
+	if ad.credentialID == nil || !bytes.Equal(ad.credentialID, rawID) {
+		return Credential{}, errors.New("webauthn: attested credential does not match the response")
+	}
+	key, err := parseCOSEKey(ad.publicKey)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := verifyAttestation(format, stmt, authRaw, clientHash, key); err != nil {
+		return Credential{}, err
+	}
+	sctx := storeContext(ctx)
+	if _, exists, err := rp.store.Credential(sctx, rawID); err != nil {
+		return Credential{}, err
+	} else if exists {
+		return Credential{}, errors.New("webauthn: credential already registered")
+	}
+	cred := Credential{
+		ID:        slices.Clone(rawID),
+		UserID:    p.userID,
+		PublicKey: slices.Clone(ad.publicKey),
+		SignCount: ad.signCount,
+		AAGUID:    slices.Clone(ad.aaguid),
+		Created:   rp.now(),
+	}
+	if err := rp.store.Add(sctx, cred); err != nil {
+		return Credential{}, err
+	}
+	return cred, nil
+}
+
+// BeginLogin starts a passkey login: it issues a challenge and opens the
+// browser prompt on c's tab, offering every passkey the browser holds for
+// the relying party. The answer arrives through c and the action bound to
+// [DoneEvent], which calls FinishLogin.
+func (rp *RP) BeginLogin(ctx *via.Ctx, c *Ceremony) error {
+	challenge := c.begin("webauthn.get", nil, rp.now().Add(rp.timeout))
+	return rp.prompt(ctx, c, "get", map[string]any{
+		"rpId":             rp.id,
+		"challenge":        b64.EncodeToString(challenge),
+		"timeout":          rp.timeout.Milliseconds(),
+		"userVerification": string(rp.verification),
+	})
+}
+
+// FinishLogin verifies the browser's answer to BeginLogin and returns the
+// credential the user proved they hold; its UserID names the account to
+// sign in. Call it from the action bound to [DoneEvent].
+func (rp *RP) FinishLogin(ctx *via.Ctx, c *Ceremony) (Credential, error) {
+	p, err := c.take("webauthn.get", rp.now())
+	if err != nil {
+		return Credential{}, err
+	}
+	resp, err := c.readResponse(ctx)
+	if err != nil {
+		return Credential{}, err
+	}
+	fields, err := decodeFields(resp.ID, resp.Response.ClientDataJSON,
+		resp.Response.AuthenticatorData, resp.Response.Signature, resp.Response.UserHandle)
+	if err != nil {
+		return Credential{}, err
+	}
+	rawID, clientJSON, authRaw, sig, userHandle := fields[0], fields[1], fields[2], fields[3], fields[4]
+	sctx := storeContext(ctx)
+	cred, ok, err := rp.store.Credential(sctx, rawID)
+	if err != nil {
+		return Credential{}, err
+	}
+	if !ok {
+		return Credential{}, errors.New("webauthn: unknown credential")
+	}
+	if len(userHandle) > 0 && !bytes.Equal(userHandle, cred.UserID) {
+		return Credential{}, errors.New("webauthn: user handle does not match the credential")
+	}
+	clientHash, err := rp.checkClientData(clientJSON, p.kind, p.challenge)
+	if err != nil {
+		return Credential{}, err
+	}
+	ad, err := parseAuthData(authRaw)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := rp.checkAuthData(ad); err != nil {
+		return Credential{}, err
+	}
+	key, err := parseCOSEKey(cred.PublicKey)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := key.verify(append(slices.Clip(authRaw), clientHash[:]...), sig); err != nil {
+		return Credential{}, err
+	}
+	// Authenticators that keep no counter always report 0; one that does
+	// must report more than last time, or a copy of the key is in use.
+	if (ad.signCount != 0 || cred.SignCount != 0) && ad.signCount <= cred.SignCount {
+		return Credential{}, errors.New("webauthn: signature counter did not advance")
+	}
+	if err := rp.store.UpdateSignCount(sctx, cred.ID, ad.signCount); err != nil {
+		return Credential{}, err
+	}
+	cred.SignCount = ad.signCount
+	return cred, nil
+}
+
+// prompt runs the client script's ceremony call on c's tab.
+func (rp *RP) prompt(ctx *via.Ctx, c *Ceremony, kind string, options map[string]any) error {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("webauthn: encode options: %v", err)
+	}
+	id, err := json.Marshal(ctx.ScopedID(c, "webauthn"))
+	if err != nil {
+		return fmt.Errorf("webauthn: encode element id: %v", err)
+	}
+	ctx.ExecScript("viaWebAuthn." + kind + "(" + string(opts) + "," + string(id) + ")")
+	return nil
+}
+
+// storeContext is the in-flight request's context, so a store call is
+// cancelled with the action that made it.
+func storeContext(ctx *via.Ctx) context.Context {
+	if r := ctx.Request(); r != nil {
+		return r.Context()
+	}
+	return context.Background()
+}
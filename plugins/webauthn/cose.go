@@ -0,0 +1,129 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers (RFC 9053) the relying party accepts, in the
+// order it offers them to the authenticator.
+const (
+	algES256 int64 = -7
+	algEdDSA int64 = -8
+	algRS256 int64 = -257
+)
+
+var supportedAlgs = []int64{algES256, algEdDSA, algRS256}
+
+// COSE_Key labels and values.
+const (
+	coseKty = 1
+	coseAlg = 3
+	coseCrv = -1 // EC2/OKP curve; RSA modulus n
+	coseX   = -2 // EC2/OKP x; RSA exponent e
+	coseY   = -3
+
+	ktyOKP = 1
+	ktyEC2 = 2
+	ktyRSA = 3
+
+	crvP256    = 1
+	crvEd25519 = 6
+)
+
+// publicKey is a credential public key decoded from its COSE_Key form.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parseCOSEKey decodes a COSE_Key. Only the algorithms in supportedAlgs
+// are accepted.
+func parseCOSEKey(b []byte) (publicKey, error) {
+	v, n, err := decodeCBOR(b)
+	if err != nil {
+		return publicKey{}, err
+	}
+	if n != len(b) {
+		return publicKey{}, errors.New("webauthn: trailing bytes after COSE key")
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return publicKey{}, errors.New("webauthn: COSE key is not a map")
+	}
+	kty, _ := m[int64(coseKty)].(int64)
+	alg, _ := m[int64(coseAlg)].(int64)
+	bytesAt := func(label int64) []byte { b, _ := m[label].([]byte); return b }
+	switch {
+	case kty == ktyEC2 && alg == algES256:
+		if crv, _ := m[int64(coseCrv)].(int64); crv != crvP256 {
+			return publicKey{}, fmt.Errorf("webauthn: ES256 key on unsupported curve %d", crv)
+		}
+		x, y := bytesAt(coseX), bytesAt(coseY)
+		if len(x) != 32 || len(y) != 32 {
+			return publicKey{}, errors.New("webauthn: malformed P-256 key")
+		}
+		// ecdh validates that the point is on the curve.
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return publicKey{}, fmt.Errorf("webauthn: P-256 key: %v", err)
+		}
+		k := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return publicKey{alg: alg, key: k}, nil
+	case kty == ktyOKP && alg == algEdDSA:
+		if crv, _ := m[int64(coseCrv)].(int64); crv != crvEd25519 {
+			return publicKey{}, fmt.Errorf("webauthn: EdDSA key on unsupported curve %d", crv)
+		}
+		x := bytesAt(coseX)
+		if len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("webauthn: malformed Ed25519 key")
+		}
+		return publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == ktyRSA && alg == algRS256:
+		n, e := bytesAt(coseCrv), bytesAt(coseX)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return publicKey{}, errors.New("webauthn: malformed or short RSA key")
+		}
+		exp := int(new(big.Int).SetBytes(e).Int64())
+		return publicKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exp}}, nil
+	}
+	return publicKey{}, fmt.Errorf("webauthn: unsupported key type %d with algorithm %d", kty, alg)
+}
+
+// verify checks sig over data with the key's algorithm.
+func (k publicKey) verify(data, sig []byte) error {
+	return verifySignature(k.alg, k.key, data, sig)
+}
+
+// verifySignature checks sig over data with key under COSE algorithm alg.
+// It is shared by credential keys and packed attestation certificates.
+func verifySignature(alg int64, key crypto.PublicKey, data, sig []byte) error {
+	ok := false
+	switch alg {
+	case algES256:
+		if k, isEC := key.(*ecdsa.PublicKey); isEC {
+			sum := sha256.Sum256(data)
+			ok = ecdsa.VerifyASN1(k, sum[:], sig)
+		}
+	case algEdDSA:
+		if k, isEd := key.(ed25519.PublicKey); isEd {
+			ok = ed25519.Verify(k, data, sig)
+		}
+	case algRS256:
+		if k, isRSA := key.(*rsa.PublicKey); isRSA {
+			sum := sha256.Sum256(data)
+			ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, sum[:], sig) == nil
+		}
+	}
+	if !ok {
+		return errors.New("webauthn: invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Credential is a registered passkey: the public half of a key pair an
+// authenticator holds for one user.
+type Credential struct {
+	// ID is the authenticator-chosen credential id.
+	ID []byte
+	// UserID is the [User.ID] the credential was registered for.
+	UserID []byte
+	// PublicKey is the credential public key in COSE_Key form.
+	PublicKey []byte
+	// SignCount is the authenticator's signature counter as of the last
+	// ceremony; 0 for authenticators that don't keep one.
+	SignCount uint32
+	// AAGUID identifies the authenticator model; all zero when the
+	// authenticator withholds it.
+	AAGUID []byte
+	// Created is when the credential was registered.
+	Created time.Time
+}
+
+// Store persists credentials. A clustered deployment backs it with the
+// app's database so every pod sees every passkey; [InMemoryStore] is the
+// reference implementation for tests and single-process apps.
+type Store interface {
+	// Add saves a newly registered credential.
+	Add(ctx context.Context, c Credential) error
+	// Credential returns the credential with id and ok=true, or ok=false
+	// if there is none.
+	Credential(ctx context.Context, id []byte) (c Credential, ok bool, err error)
+	// Credentials returns every credential registered for userID.
+	Credentials(ctx context.Context, userID []byte) ([]Credential, error)
+	// UpdateSignCount records the signature counter of a successful login.
+	UpdateSignCount(ctx context.Context, id []byte, count uint32) error
+	// Remove revokes a credential. Removing an unknown id is not an error.
+	Remove(ctx context.Context, id []byte) error
+}
+
+// InMemoryStore returns a process-local Store backed by a slice. Its
+// credentials are lost on restart.
+func InMemoryStore() Store { return &memStore{} }
+
+type memStore struct {
+	mu    sync.Mutex
+	creds []Credential
+}
+
+func (s *memStore) Add(_ context.Context, c Credential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creds = append(s.creds, c)
+	return nil
+}
+
+func (s *memStore) Credential(_ context.Context, id []byte) (Credential, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.creds, func(c Credential) bool { return bytes.Equal(c.ID, id) })
+	if i < 0 {
+		return Credential{}, false, nil
+	}
+	return s.creds[i], true, nil
+}
+
+func (s *memStore) Credentials(_ context.Context, userID []byte) ([]Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Credential
+	for _, c := range s.creds {
+		if bytes.Equal(c.UserID, userID) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) UpdateSignCount(_ context.Context, id []byte, count uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.creds {
+		if bytes.Equal(s.creds[i].ID, id) {
+			s.creds[i].SignCount = count
+		}
+	}
+	return nil
+}
+
+func (s *memStore) Remove(_ context.Context, id []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creds = slices.DeleteFunc(s.creds, func(c Credential) bool { return bytes.Equal(c.ID, id) })
+	return nil
+}
@@ -0,0 +1,149 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Authenticator data flags (WebAuthn §6.1).
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// b64 is the base64url (unpadded) encoding WebAuthn uses on the wire.
+var b64 = base64.RawURLEncoding
+
+// clientData is the subset of CollectedClientData the relying party checks.
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// checkClientData verifies the browser-collected ceremony data: its type,
+// that it echoes the challenge this tab was issued, and that it was
+// collected on one of the relying party's origins. Returns the hash the
+// authenticator signed alongside its data.
+func (rp *RP) checkClientData(raw []byte, typ string, challenge []byte) ([32]byte, error) {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return [32]byte{}, fmt.Errorf("webauthn: client data: %v", err)
+	}
+	if cd.Type != typ {
+		return [32]byte{}, fmt.Errorf("webauthn: client data type %q, want %q", cd.Type, typ)
+	}
+	got, err := b64.DecodeString(cd.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return [32]byte{}, errors.New("webauthn: challenge mismatch")
+	}
+	if !slices.Contains(rp.origins, cd.Origin) {
+		return [32]byte{}, fmt.Errorf("webauthn: unexpected origin %q", cd.Origin)
+	}
+	return sha256.Sum256(raw), nil
+}
+
+// authData is parsed authenticator data.
+type authData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// Set when flagAttested is: the newly created credential.
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthData(b []byte) (authData, error) {
+	if len(b) < 37 {
+		return authData{}, errors.New("webauthn: authenticator data too short")
+	}
+	ad := authData{
+		rpIDHash:  b[:32],
+		flags:     b[32],
+		signCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	if ad.flags&flagAttested == 0 {
+		return ad, nil
+	}
+	rest := b[37:]
+	if len(rest) < 18 {
+		return authData{}, errors.New("webauthn: attested credential data too short")
+	}
+	ad.aaguid = rest[:16]
+	n := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if n == 0 || n > 1023 || len(rest) < n {
+		return authData{}, errors.New("webauthn: malformed credential id")
+	}
+	ad.credentialID, rest = rest[:n], rest[n:]
+	// The COSE key may be followed by extension outputs; decode it once to
+	// learn where it ends.
+	_, used, err := decodeCBOR(rest)
+	if err != nil {
+		return authData{}, fmt.Errorf("webauthn: credential public key: %v", err)
+	}
+	ad.publicKey = rest[:used]
+	return ad, nil
+}
+
+// checkAuthData verifies the authenticator data was produced for this
+// relying party with the user present (and verified, when required).
+func (rp *RP) checkAuthData(ad authData) error {
+	want := sha256.Sum256([]byte(rp.id))
+	if subtle.ConstantTimeCompare(ad.rpIDHash, want[:]) != 1 {
+		return errors.New("webauthn: authenticator data is for another relying party")
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return errors.New("webauthn: user was not present")
+	}
+	if rp.verification == VerificationRequired && ad.flags&flagUserVerified == 0 {
+		return errors.New("webauthn: user was not verified")
+	}
+	return nil
+}
+
+// verifyAttestation checks the attestation statement over a new
+// credential. "none" carries nothing to check; "packed" is verified
+// against its certificate or, for self attestation, the credential key
+// itself. The certificate is not chained to a trust root — the relying
+// party asks for no attestation, so this only proves the statement is
+// internally consistent. Other formats are refused.
+func verifyAttestation(format string, stmt map[any]any, authDataRaw []byte, clientHash [32]byte, cred publicKey) error {
+	switch format {
+	case "none":
+		if len(stmt) != 0 {
+			return errors.New("webauthn: none attestation with a statement")
+		}
+		return nil
+	case "packed":
+		alg, _ := stmt["alg"].(int64)
+		sig, _ := stmt["sig"].([]byte)
+		signed := append(slices.Clip(authDataRaw), clientHash[:]...)
+		x5c, hasCert := stmt["x5c"].([]any)
+		if !hasCert {
+			if alg != cred.alg {
+				return errors.New("webauthn: packed self attestation algorithm mismatch")
+			}
+			return cred.verify(signed, sig)
+		}
+		if len(x5c) == 0 {
+			return errors.New("webauthn: packed attestation with an empty certificate chain")
+		}
+		leaf, _ := x5c[0].([]byte)
+		cert, err := x509.ParseCertificate(leaf)
+		if err != nil {
+			return fmt.Errorf("webauthn: attestation certificate: %v", err)
+		}
+		return verifySignature(alg, cert.PublicKey, signed, sig)
+	}
+	return fmt.Errorf("webauthn: unsupported attestation format %q", format)
+}
@@ -0,0 +1,182 @@
+// Package webauthn adds passkey registration and login to a Via app. The
+// relying party side of both WebAuthn ceremonies runs in actions: one
+// action issues a challenge and starts the browser prompt, a second
+// verifies what the authenticator returned and yields the credential.
+//
+//	rp := webauthn.NewRP("example.com", "Example",
+//	    webauthn.WithOrigins("https://example.com"),
+//	    webauthn.WithStore(credentialStore))
+//	app := via.New(via.WithPlugins(webauthn.Plugin(rp)))
+//
+//	type Account struct {
+//	    Passkey *webauthn.Ceremony
+//	}
+//
+//	func (p *Account) Enroll(ctx *via.Ctx) error {
+//	    return rp.BeginRegistration(ctx, p.Passkey, webauthn.User{ID: uid, Name: email})
+//	}
+//
+//	func (p *Account) Enrolled(ctx *via.Ctx) error {
+//	    _, err := rp.FinishRegistration(ctx, p.Passkey)
+//	    return err
+//	}
+//
+//	func (p *Account) View(ctx *via.CtxR) h.H {
+//	    return h.Div(
+//	        h.Button(h.Text("Add a passkey"), on.Click(p.Enroll)),
+//	        p.Passkey.View(ctx, on.Event(webauthn.DoneEvent, p.Enrolled)),
+//	    )
+//	}
+//
+// Login is the same shape with BeginLogin and FinishLogin, which returns
+// the credential the user signed in with — hand its UserID to
+// ctx.SignIn.
+//
+// Passkeys are discoverable credentials: login needs no username, the
+// browser offers the passkeys it holds for the relying party. Attestation
+// is not requested; "none" and "packed" statements are verified, other
+// formats are refused. ES256, EdDSA and RS256 keys are supported.
+package webauthn
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+)
+
+// Verification is the user verification requirement (PIN, biometric)
+// passed to the authenticator.
+type Verification string
+
+const (
+	// VerificationRequired fails a ceremony the authenticator did not
+	// verify the user for.
+	VerificationRequired Verification = "required"
+	// VerificationPreferred asks for verification where the authenticator
+	// supports it.
+	VerificationPreferred Verification = "preferred"
+	// VerificationDiscouraged asks the authenticator to skip it.
+	VerificationDiscouraged Verification = "discouraged"
+)
+
+// DoneEvent is the DOM event a [Ceremony] element fires once the browser
+// prompt settles, successfully or not. Bind the finishing action to it
+// with on.Event(webauthn.DoneEvent, …).
+const DoneEvent = "webauthn-done"
+
+// defaultTimeout is how long a begun ceremony waits for its finish.
+const defaultTimeout = 5 * time.Minute
+
+// Option configures an [RP].
+type Option func(*RP)
+
+// WithOrigins sets the origins ceremonies may complete on, e.g.
+// "https://example.com" or "http://localhost:8080" in development.
+// Defaults to "https://" + the relying party id. Repeat to add several.
+func WithOrigins(origins ...string) Option {
+	return func(rp *RP) { rp.origins = append(rp.origins, origins...) }
+}
+
+// WithStore sets where credentials are kept. Defaults to [InMemoryStore].
+func WithStore(s Store) Option {
+	if s == nil {
+		panic("webauthn: WithStore requires a non-nil store")
+	}
+	return func(rp *RP) { rp.store = s }
+}
+
+// WithUserVerification sets the user verification requirement. Defaults
+// to VerificationPreferred; with VerificationRequired a ceremony whose
+// authenticator did not verify the user fails.
+func WithUserVerification(v Verification) Option {
+	switch v {
+	case VerificationRequired, VerificationPreferred, VerificationDiscouraged:
+	default:
+		panic("webauthn: WithUserVerification: unknown requirement " + string(v))
+	}
+	return func(rp *RP) { rp.verification = v }
+}
+
+// WithTimeout bounds how long the user has to answer the prompt, and how
+// long a begun ceremony stays open on the server. Defaults to 5 minutes.
+func WithTimeout(d time.Duration) Option {
+	if d <= 0 {
+		panic("webauthn: WithTimeout requires a positive duration")
+	}
+	return func(rp *RP) { rp.timeout = d }
+}
+
+// RP is a WebAuthn relying party: the app's identity to authenticators
+// and the configuration both ceremonies verify against. It is safe for
+// concurrent use; one RP serves every tab.
+type RP struct {
+	id           string
+	name         string
+	origins      []string
+	store        Store
+	verification Verification
+	timeout      time.Duration
+
+	mu    sync.Mutex
+	clock via.Clock // the app's, once Plugin is registered; guarded by mu
+}
+
+// NewRP declares the relying party. id is the registrable domain passkeys
+// are scoped to ("example.com" covers its subdomains); name is shown in
+// the browser prompt. Panics on an empty id or name.
+func NewRP(id, name string, opts ...Option) *RP {
+	if id == "" || name == "" {
+		panic("webauthn: NewRP requires an id and a name")
+	}
+	rp := &RP{id: id, name: name, verification: VerificationPreferred, timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(rp)
+	}
+	if len(rp.origins) == 0 {
+		rp.origins = []string{"https://" + id}
+	}
+	if rp.store == nil {
+		rp.store = InMemoryStore()
+	}
+	return rp
+}
+
+// now reads the app's clock, or the wall clock before Plugin registers.
+func (rp *RP) now() time.Time {
+	rp.mu.Lock()
+	clk := rp.clock
+	rp.mu.Unlock()
+	if clk == nil {
+		return time.Now()
+	}
+	return clk.Now()
+}
+
+// Store returns the credential store, for listing or revoking a user's
+// passkeys.
+func (rp *RP) Store() Store { return rp.store }
+
+// Plugin serves the client half of the ceremonies: a small script, from a
+// content-hashed same-origin path, that runs the browser prompt and hands
+// its result back to a [Ceremony].
+func Plugin(rp *RP) via.Plugin {
+	if rp == nil {
+		panic("webauthn: Plugin requires a relying party")
+	}
+	return &plugin{rp: rp}
+}
+
+type plugin struct{ rp *RP }
+
+// Name registers the plugin as "webauthn", for via.App.Plugin.
+func (p *plugin) Name() string { return "webauthn" }
+
+func (p *plugin) Register(v *via.App) {
+	p.rp.mu.Lock()
+	p.rp.clock = v.Clock()
+	p.rp.mu.Unlock()
+	v.HandleFunc("GET "+assetPathPrefix, serveAsset)
+	v.AppendToHead(h.Script(h.Src(v.BasePath() + clientJS.path())))
+}
@@ -0,0 +1,413 @@
+package webauthn_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/plugins/webauthn"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const origin = "https://example.com"
+
+var testRP = webauthn.NewRP("example.com", "Example", webauthn.WithOrigins(origin))
+
+// passkeyPage runs registration through Enroll and login through SignIn,
+// rendering each finish's outcome.
+type passkeyPage struct {
+	Enroll  *webauthn.Ceremony
+	SignIn  *webauthn.Ceremony
+	Outcome via.StateTab[string]
+}
+
+func (p *passkeyPage) Register(ctx *via.Ctx) error {
+	return testRP.BeginRegistration(ctx, p.Enroll,
+		webauthn.User{ID: []byte(ctx.EventArg("user")), Name: "alice@example.com"})
+}
+
+func (p *passkeyPage) Registered(ctx *via.Ctx) {
+	cred, err := testRP.FinishRegistration(ctx, p.Enroll)
+	p.report(ctx, "registered", cred, err)
+}
+
+func (p *passkeyPage) Login(ctx *via.Ctx) error { return testRP.BeginLogin(ctx, p.SignIn) }
+
+func (p *passkeyPage) LoggedIn(ctx *via.Ctx) {
+	cred, err := testRP.FinishLogin(ctx, p.SignIn)
+	p.report(ctx, "signed in", cred, err)
+}
+
+func (p *passkeyPage) report(ctx *via.Ctx, what string, cred webauthn.Credential, err error) {
+	if err != nil {
+		p.Outcome.Write(ctx, "failed: "+err.Error())
+		return
+	}
+	p.Outcome.Write(ctx, what+" as "+string(cred.UserID))
+}
+
+func (p *passkeyPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.ID("outcome"), h.Text(p.Outcome.Read(ctx))),
+		p.Enroll.View(ctx, on.Event(webauthn.DoneEvent, p.Registered)),
+		p.SignIn.View(ctx, on.Event(webauthn.DoneEvent, p.LoggedIn)),
+	)
+}
+
+func newClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New(via.WithPlugins(webauthn.Plugin(testRP)))
+	server := vt.Serve(t, app)
+	via.Mount[passkeyPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+// authenticator is a software passkey: one key pair, one credential id.
+type authenticator struct {
+	signer crypto.Signer
+	cose   []byte
+	id     []byte
+	count  uint32
+	origin string
+}
+
+func newES256(t *testing.T) *authenticator {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	x, y := make([]byte, 32), make([]byte, 32)
+	k.X.FillBytes(x)
+	k.Y.FillBytes(y)
+	return newAuthenticator(k, cborMap{{1, 2}, {3, -7}, {-1, 1}, {-2, x}, {-3, y}})
+}
+
+func newEdDSA(t *testing.T) *authenticator {
+	t.Helper()
+	pub, k, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return newAuthenticator(k, cborMap{{1, 1}, {3, -8}, {-1, 6}, {-2, []byte(pub)}})
+}
+
+func newAuthenticator(signer crypto.Signer, key cborMap) *authenticator {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return &authenticator{signer: signer, cose: encodeCBOR(key), id: id, origin: origin}
+}
+
+func (a *authenticator) sign(data []byte) []byte {
+	var (
+		sig []byte
+		err error
+	)
+	if _, ed := a.signer.(ed25519.PrivateKey); ed {
+		sig, err = a.signer.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		sum := sha256.Sum256(data)
+		sig, err = a.signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+func (a *authenticator) authData(flags byte, attested bool) []byte {
+	rpHash := sha256.Sum256([]byte("example.com"))
+	out := append(rpHash[:], flags)
+	out = binary.BigEndian.AppendUint32(out, a.count)
+	if attested {
+		out = append(out, make([]byte, 16)...) // aaguid
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.id)))
+		out = append(out, a.id...)
+		out = append(out, a.cose...)
+	}
+	return out
+}
+
+func (a *authenticator) clientData(typ string, challenge string) []byte {
+	b, _ := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": a.origin})
+	return b
+}
+
+// create answers a registration prompt with a "none" attestation.
+func (a *authenticator) create(challenge string) string {
+	att := encodeCBOR(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", a.authData(0x45, true)}})
+	return a.reply(map[string]string{
+		"clientDataJSON":    b64(a.clientData("webauthn.create", challenge)),
+		"attestationObject": b64(att),
+	})
+}
+
+// get answers a login prompt, advancing the signature counter.
+func (a *authenticator) get(challenge string, userID []byte) string {
+	a.count++
+	ad := a.authData(0x05, false)
+	cd := a.clientData("webauthn.get", challenge)
+	sum := sha256.Sum256(cd)
+	return a.reply(map[string]string{
+		"clientDataJSON":    b64(cd),
+		"authenticatorData": b64(ad),
+		"signature":         b64(a.sign(append(ad, sum[:]...))),
+		"userHandle":        b64(userID),
+	})
+}
+
+func (a *authenticator) reply(response map[string]string) string {
+	b, _ := json.Marshal(map[string]any{"id": b64(a.id), "type": "public-key", "response": response})
+	return string(b)
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// prompt waits for the server to open the browser prompt and returns the
+// options it passed, with the frame that carried them.
+func prompt(t *testing.T, frames <-chan string, kind string) (map[string]any, string) {
+	t.Helper()
+	call := "viaWebAuthn." + kind + "("
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, call)
+	var opts map[string]any
+	require.NoError(t, json.NewDecoder(strings.NewReader(frame[strings.Index(frame, call)+len(call):])).Decode(&opts))
+	return opts, frame
+}
+
+func register(t *testing.T, tc *vt.Client, frames <-chan string, a *authenticator, user string) {
+	t.Helper()
+	tc.Action("Register").WithArg("user", user).Fire()
+	opts, _ := prompt(t, frames, "create")
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": a.create(opts["challenge"].(string))}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "registered as "+user)
+}
+
+func login(t *testing.T, tc *vt.Client, frames <-chan string, a *authenticator, user string) {
+	t.Helper()
+	tc.Action("Login").Fire()
+	opts, _ := prompt(t, frames, "get")
+	tc.Action("LoggedIn").WithSignal("SignIn", map[string]any{"response": a.get(opts["challenge"].(string), []byte(user))}).Fire()
+}
+
+func TestCeremonies_registerThenSignInWithAPasskey(t *testing.T) {
+	t.Parallel()
+	for name, newKey := range map[string]func(*testing.T) *authenticator{"ES256": newES256, "EdDSA": newEdDSA} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc, frames := newClient(t)
+			a := newKey(t)
+			user := "user-" + name
+
+			register(t, tc, frames, a, user)
+			login(t, tc, frames, a, user)
+			vt.AwaitFrame(t, frames, 2*time.Second, "signed in as "+user)
+		})
+	}
+}
+
+func TestBeginRegistration_offersTheCeremonyToTheTab(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	tc.Action("Register").WithArg("user", "user-opts").Fire()
+
+	opts, frame := prompt(t, frames, "create")
+	assert.Contains(t, frame, `"Enroll-webauthn"`, "the prompt answers through the tab's Enroll element")
+	assert.Equal(t, map[string]any{"id": "example.com", "name": "Example"}, opts["rp"])
+	assert.Equal(t, b64([]byte("user-opts")), opts["user"].(map[string]any)["id"])
+	assert.Equal(t, "none", opts["attestation"])
+	challenge, err := base64.RawURLEncoding.DecodeString(opts["challenge"].(string))
+	require.NoError(t, err)
+	assert.Len(t, challenge, 32)
+}
+
+func TestFinishRegistration_refusesAReplayedResponse(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	a := newES256(t)
+	tc.Action("Register").WithArg("user", "user-replay").Fire()
+	opts, _ := prompt(t, frames, "create")
+	resp := a.create(opts["challenge"].(string))
+
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": resp}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "registered as user-replay")
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": resp}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: no ceremony in progress")
+}
+
+func TestFinishRegistration_refusesAForeignOrigin(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	a := newES256(t)
+	a.origin = "https://evil.example"
+	tc.Action("Register").WithArg("user", "user-origin").Fire()
+	opts, _ := prompt(t, frames, "create")
+
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": a.create(opts["challenge"].(string))}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: unexpected origin")
+}
+
+func TestFinishRegistration_reportsACancelledPrompt(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	tc.Action("Register").WithArg("user", "user-cancel").Fire()
+	prompt(t, frames, "create")
+
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": `{"error":"NotAllowedError"}`}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: ceremony cancelled: NotAllowedError")
+}
+
+func TestFinishLogin_refusesAnotherChallenge(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	a := newES256(t)
+	register(t, tc, frames, a, "user-challenge")
+
+	tc.Action("Login").Fire()
+	prompt(t, frames, "get")
+	stale := b64(make([]byte, 32))
+	tc.Action("LoggedIn").WithSignal("SignIn", map[string]any{"response": a.get(stale, []byte("user-challenge"))}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: challenge mismatch")
+}
+
+func TestFinishLogin_refusesAForgedSignature(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	a := newES256(t)
+	register(t, tc, frames, a, "user-forged")
+
+	impostor := newES256(t)
+	impostor.id = a.id
+	login(t, tc, frames, impostor, "user-forged")
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: invalid signature")
+}
+
+func TestFinishLogin_refusesACounterThatWentBackwards(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	a := newES256(t)
+	register(t, tc, frames, a, "user-clone")
+	login(t, tc, frames, a, "user-clone")
+	vt.AwaitFrame(t, frames, 2*time.Second, "signed in as user-clone")
+
+	a.count = 0 // a copy of the key, replaying from an older counter
+	login(t, tc, frames, a, "user-clone")
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: signature counter did not advance")
+}
+
+func TestFinishLogin_refusesAnUnknownCredential(t *testing.T) {
+	t.Parallel()
+	tc, frames := newClient(t)
+	login(t, tc, frames, newES256(t), "user-unknown")
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: unknown credential")
+}
+
+func TestPlugin_servesTheClientScript(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithPlugins(webauthn.Plugin(testRP)))
+	server := vt.Serve(t, app)
+	via.Mount[passkeyPage](app, "/")
+
+	html := vt.NewClient(t, server, "/").HTML()
+	i := strings.Index(html, "/via/assets/webauthn/")
+	require.GreaterOrEqual(t, i, 0, "the page loads the client script")
+	src := html[i : i+strings.IndexByte(html[i:], '"')]
+
+	resp, err := http.Get(server.URL + src)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "window.viaWebAuthn")
+	assert.Contains(t, string(body), webauthn.DoneEvent)
+}
+
+// cborMap is an ordered CBOR map, so encodings are deterministic.
+type cborMap [][2]any
+
+// encodeCBOR encodes the few shapes WebAuthn payloads use.
+func encodeCBOR(v any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 1<<8:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		}
+	}
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case cborMap:
+		out := head(5, uint64(len(v)))
+		for _, kv := range v {
+			out = append(out, encodeCBOR(kv[0])...)
+			out = append(out, encodeCBOR(kv[1])...)
+		}
+		return out
+	}
+	panic("encodeCBOR: unsupported type")
+}
+
+var clockedRP = webauthn.NewRP("example.com", "Example",
+	webauthn.WithOrigins(origin), webauthn.WithTimeout(time.Minute))
+
+// clockedPage runs registration against clockedRP, whose ceremonies
+// expire on the app's clock.
+type clockedPage struct {
+	Enroll  *webauthn.Ceremony
+	Outcome via.StateTab[string]
+}
+
+func (p *clockedPage) Register(ctx *via.Ctx) error {
+	return clockedRP.BeginRegistration(ctx, p.Enroll, webauthn.User{ID: []byte("user-late"), Name: "late@example.com"})
+}
+
+func (p *clockedPage) Registered(ctx *via.Ctx) {
+	if _, err := clockedRP.FinishRegistration(ctx, p.Enroll); err != nil {
+		p.Outcome.Write(ctx, "failed: "+err.Error())
+	}
+}
+
+func (p *clockedPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.P(h.Text(p.Outcome.Read(ctx))), p.Enroll.View(ctx, on.Event(webauthn.DoneEvent, p.Registered)))
+}
+
+func TestFinishRegistration_expiresOnTheAppClock(t *testing.T) {
+	t.Parallel()
+	clk := vt.NewClock(time.Unix(1_700_000_000, 0))
+	app := via.New(via.WithClock(clk), via.WithPlugins(webauthn.Plugin(clockedRP)))
+	via.Mount[clockedPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	tc.Action("Register").Fire()
+	opts, _ := prompt(t, frames, "create")
+	clk.Advance(2 * time.Minute)
+	tc.Action("Registered").WithSignal("Enroll", map[string]any{"response": newES256(t).create(opts["challenge"].(string))}).Fire()
+	vt.AwaitFrame(t, frames, 2*time.Second, "webauthn: no ceremony in progress")
+}
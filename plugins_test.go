@@ -0,0 +1,81 @@
+package via_test
+
+import (
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/plugins/picocss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderPlugin appends its name to *log when it registers.
+type orderPlugin struct {
+	name     string
+	requires []string
+	log      *[]string
+}
+
+func (p orderPlugin) Register(*via.App)  { *p.log = append(*p.log, p.name) }
+func (p orderPlugin) Name() string       { return p.name }
+func (p orderPlugin) Requires() []string { return p.requires }
+
+// unnamedPlugin has a slice field, so its value isn't comparable.
+type unnamedPlugin struct{ tags []string }
+
+func (unnamedPlugin) Register(*via.App) {}
+
+func TestWithPlugins_registersDependenciesFirst(t *testing.T) {
+	t.Parallel()
+	var log []string
+	app := via.New(via.WithPlugins(
+		orderPlugin{name: "widgets", requires: []string{"css"}, log: &log},
+		unnamedPlugin{tags: []string{"x"}},
+		orderPlugin{name: "css", log: &log},
+		orderPlugin{name: "charts", log: &log},
+	))
+	assert.Equal(t, []string{"css", "widgets", "charts"}, log)
+	assert.Equal(t, "css", app.Plugin("css").(orderPlugin).name)
+	assert.Nil(t, app.Plugin("missing"))
+}
+
+func TestWithPlugins_panicsOnAMissingDependency(t *testing.T) {
+	t.Parallel()
+	var log []string
+	assert.PanicsWithValue(t, `via: plugin "widgets" requires plugin "css", which is not registered`, func() {
+		via.New(via.WithPlugins(orderPlugin{name: "widgets", requires: []string{"css"}, log: &log}))
+	})
+	assert.Empty(t, log)
+}
+
+func TestWithPlugins_panicsOnDuplicateNamesAndCycles(t *testing.T) {
+	t.Parallel()
+	var log []string
+	assert.Panics(t, func() {
+		via.New(via.WithPlugins(orderPlugin{name: "css", log: &log}, orderPlugin{name: "css", log: &log}))
+	})
+	assert.PanicsWithValue(t, `via: plugin dependency cycle: "a" -> "b" -> "a"`, func() {
+		via.New(via.WithPlugins(
+			orderPlugin{name: "a", requires: []string{"b"}, log: &log},
+			orderPlugin{name: "b", requires: []string{"a"}, log: &log},
+		))
+	})
+}
+
+func TestRegisterPlugin_namesAndChecksAfterNew(t *testing.T) {
+	t.Parallel()
+	var log []string
+	app := via.New(via.WithPlugins(picocss.Plugin()))
+	require.NotNil(t, app.Plugin("picocss"), "bundled plugins name themselves")
+
+	app.RegisterPlugin("theme", orderPlugin{name: "theme", requires: []string{"picocss"}, log: &log})
+	assert.Equal(t, []string{"theme"}, log)
+	assert.NotNil(t, app.Plugin("theme"))
+
+	assert.Panics(t, func() { app.RegisterPlugin("theme", unnamedPlugin{}) })
+	assert.Panics(t, func() { app.RegisterPlugin("", unnamedPlugin{}) })
+	assert.Panics(t, func() {
+		app.RegisterPlugin("grid", orderPlugin{name: "grid", requires: []string{"layout"}, log: &log})
+	})
+	assert.Equal(t, []string{"theme"}, log)
+}
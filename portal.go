@@ -0,0 +1,58 @@
+package via
+
+import "github.com/go-via/via/h"
+
+// portalSuffix names a tab's portal host: "<tab id>-portal".
+const portalSuffix = "-portal"
+
+// Portal renders content at the top level of the page body instead of
+// where the call sits, so a modal, tooltip or toast authored deep inside
+// a child composition escapes its ancestors' overflow, transform and
+// z-index. It returns nil, and content lands in a host element that
+// follows the view's root:
+//
+//	func (c *Editor) View(ctx *via.CtxR, onDelete h.H) h.H {
+//	    if c.Confirming.Read(ctx) {
+//	        ctx.Portal(confirmDialog(c))
+//	    }
+//	    return h.Div(h.Button(h.Text("Delete"), onDelete))
+//	}
+//
+// Call it only when the content should show: the call itself is what
+// renders, and h.If(cond, ctx.Portal(…)) would render it either way.
+//
+// Portals are part of the render: each full render re-renders them with
+// the view and morphs the host, so their state stays live, and a portal
+// the latest render didn't produce is removed. Content from several calls
+// appears in call order. A Portal inside a [CtxR.Region] is only updated
+// by full renders, not by [Ctx.SyncRegion].
+func (r *CtxR) Portal(content ...h.H) h.H {
+	if r == nil || r.ctx == nil {
+		return nil
+	}
+	ctx := r.ctx
+	ctx.readsMu.Lock()
+	if ctx.rendering {
+		ctx.portals = append(ctx.portals, content...)
+		ctx.portalUsed = true
+	}
+	ctx.readsMu.Unlock()
+	return nil
+}
+
+// portalHost returns the element holding the portals of the last render.
+func (ctx *Ctx) portalHost() h.H {
+	ctx.readsMu.Lock()
+	defer ctx.readsMu.Unlock()
+	children := make([]h.H, 0, 1+len(ctx.portals))
+	children = append(children, h.ID(ctx.id+portalSuffix))
+	children = append(children, ctx.portals...)
+	return h.Div(children...)
+}
+
+// portalInUse reports whether re-renders must patch the portal host.
+func (ctx *Ctx) portalInUse() bool {
+	ctx.readsMu.Lock()
+	defer ctx.readsMu.Unlock()
+	return ctx.portalUsed
+}
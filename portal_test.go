@@ -0,0 +1,68 @@
+package via_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type portalDialog struct {
+	Open via.StateTab[bool]
+}
+
+func (c *portalDialog) View(ctx *via.CtxR) h.H {
+	if c.Open.Read(ctx) {
+		ctx.Portal(h.Div(h.ID("dialog"), h.Text("Are you sure?")))
+	}
+	return h.Span(h.Text("nested"))
+}
+
+type portalPage struct {
+	Dialog *portalDialog
+}
+
+func (p *portalPage) Open(ctx *via.Ctx)  { p.Dialog.Open.Write(ctx, true) }
+func (p *portalPage) Close(ctx *via.Ctx) { p.Dialog.Open.Write(ctx, false) }
+
+func (p *portalPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.Div(h.Class("scroller"), p.Dialog.View(ctx)))
+}
+
+func TestPortal_pageHasAnEmptyHost(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[portalPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	host := `<div id="` + tc.TabID() + `-portal">`
+	assert.Contains(t, tc.HTML(), `</div>`+host+"</div>",
+		"an empty host follows the view root, so a later portal can morph in")
+}
+
+func TestPortal_reRendersWithTheView(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[portalPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+	host := `<div id="` + tc.TabID() + `-portal">`
+
+	require.Equal(t, 200, tc.Action("Open").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, host+`<div id="dialog">Are you sure?</div></div>`)
+	assert.NotContains(t, frame, `<span>nested</span><div id="dialog">`,
+		"the portal is not rendered where it was called")
+
+	require.Equal(t, 200, tc.Action("Close").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, host+"</div>")
+}
@@ -0,0 +1,80 @@
+package via
+
+import (
+	"fmt"
+
+	"github.com/go-via/via/h"
+)
+
+// Progress reports the advance of long-running work — an export, an import,
+// report generation — to a [ProgressBar] with the same id. Each Set pushes a
+// client-only signal patch, so the bar moves without a view re-render and
+// without hand-written ExecScript:
+//
+//	func (p *Page) Export(ctx *via.Ctx) {
+//	    bar := ctx.Progress("export")
+//	    for i, chunk := range chunks {
+//	        write(chunk)
+//	        bar.Set((i+1)*100/len(chunks), "Writing "+chunk.Name)
+//	    }
+//	}
+//
+//	func (p *Page) View(ctx *via.CtxR) h.H {
+//	    return h.Div(via.ProgressBar("export"), h.Button(on.Click(p.Export)))
+//	}
+//
+// Set reaches the browser immediately — also from inside an action handler,
+// whose other patches are otherwise held until the handler returns.
+type Progress struct {
+	ctx *Ctx
+	key string
+}
+
+// Progress returns the handle driving the [ProgressBar] rendered with id.
+// Panics if id is not a valid signal identifier (letters, digits, '_').
+func (ctx *Ctx) Progress(id string) *Progress {
+	return &Progress{ctx: ctx, key: progressKey(id)}
+}
+
+// Set moves the bar to percent (clamped to 0–100) and replaces its label.
+// Safe to call from any goroutine; a no-op once the tab is gone.
+func (p *Progress) Set(percent int, label string) {
+	if p == nil || p.ctx == nil || p.ctx.queue == nil {
+		return
+	}
+	percent = min(max(percent, 0), 100)
+	p.ctx.patch.Signal(p.key, map[string]any{"pct": percent, "label": label})
+	// Bypass the action-scoped wake hold: drainQueue ships only signals while
+	// an action is in flight, so this frame cannot split the action's
+	// element patches from its auto render.
+	p.ctx.queue.signal()
+}
+
+// ProgressBar renders a <progress> element and a label bound to the
+// [Progress] handle with the same id. The bar starts at 0 and keeps its
+// value across view re-renders; attrs are added to the <progress> element.
+// Panics if id is not a valid signal identifier (letters, digits, '_').
+func ProgressBar(id string, attrs ...h.H) h.H {
+	key := progressKey(id)
+	return h.Div(
+		h.Class("via-progress"),
+		h.Data("signals__ifmissing", `{"`+key+`":{"pct":0,"label":""}}`),
+		h.With(h.Progress(h.ID("via-progress-"+id), h.Max("100"), h.Value("0"),
+			h.Data("attr:value", "$"+key+".pct")), attrs...),
+		h.Label(h.For("via-progress-"+id), h.Data("text", "$"+key+".label")),
+	)
+}
+
+// progressKey maps a progress id to its client-only signal. The leading
+// underscore keeps Datastar from POSTing it back with every action.
+func progressKey(id string) string {
+	if id == "" {
+		panic("via: progress id cannot be empty")
+	}
+	for _, r := range id {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			panic(fmt.Sprintf("via: progress id %q must contain only letters, digits, and '_'", id))
+		}
+	}
+	return "_progress_" + id
+}
@@ -0,0 +1,80 @@
+package via_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+type progressPage struct {
+	Done via.StateTab[bool]
+}
+
+// releaseKey carries the test's release channel on the action request, so
+// each run (and each -count repeat) blocks on a channel of its own.
+type releaseKey struct{}
+
+func (p *progressPage) Export(ctx *via.Ctx) {
+	ctx.Progress("export").Set(40, "Writing rows")
+	<-ctx.Request().Context().Value(releaseKey{}).(chan struct{})
+	p.Done.Write(ctx, true)
+}
+
+func (p *progressPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID("page"),
+		via.ProgressBar("export"),
+		h.If(p.Done.Read(ctx), h.P(h.Text("export-finished"))),
+		h.Button(on.Click(p.Export)),
+	)
+}
+
+func TestProgress_setReachesBrowserWhileActionRuns(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	app := via.New()
+	app.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), releaseKey{}, release)))
+	})
+	via.Mount[progressPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	fired := make(chan int, 1)
+	go func() { fired <- tc.Action("Export").Fire() }()
+
+	got := vt.AwaitFrame(t, frames, 2*time.Second, `"_progress_export":{"label":"Writing rows","pct":40}`)
+	assert.NotContains(t, got, "export-finished")
+
+	close(release)
+	<-fired
+	vt.AwaitFrame(t, frames, 2*time.Second, "export-finished")
+}
+
+func TestProgressBar_bindsToTheProgressSignal(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	_ = via.ProgressBar("export", h.Class("wide")).Render(&buf)
+	out := buf.String()
+
+	assert.Contains(t, out, `data-signals__ifmissing="{&#34;_progress_export&#34;:{&#34;pct&#34;:0,&#34;label&#34;:&#34;&#34;}}"`)
+	assert.Contains(t, out, `data-attr:value="$_progress_export.pct"`)
+	assert.Contains(t, out, `data-text="$_progress_export.label"`)
+	assert.Contains(t, out, `class="wide"`)
+}
+
+func TestProgressBar_panicsOnInvalidID(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { via.ProgressBar("export-job") })
+}
@@ -0,0 +1,203 @@
+package via
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// WithTrustedProxies names the reverse proxies and load balancers in
+// front of the app, as CIDRs ("10.0.0.0/8") or bare addresses. A request
+// whose peer is one of them has its client address, scheme and host taken
+// from the Forwarded (RFC 7239) or X-Forwarded-For/-Proto/-Host headers:
+// r.RemoteAddr becomes the client's IP, r.Host the host it asked for, and
+// [ClientIP] and [Scheme] report them. A request from any other peer is
+// left as it arrived, so a client can't forge its address by sending the
+// headers itself.
+//
+// The derived scheme never clears the session cookie's Secure flag: a
+// proxy that terminates TLS but omits the proto header must not downgrade
+// it. Only [WithInsecureCookies] does. Panics at New on an entry that is
+// neither a CIDR nor an IP.
+func WithTrustedProxies(cidrs ...string) Option {
+	return func(c *config) { c.trustedProxyCIDRs = append(c.trustedProxyCIDRs, cidrs...) }
+}
+
+// parseTrustedProxies resolves WithTrustedProxies entries to prefixes.
+func parseTrustedProxies(cidrs []string) []netip.Prefix {
+	out := make([]netip.Prefix, 0, len(cidrs))
+	for _, s := range cidrs {
+		if p, err := netip.ParsePrefix(s); err == nil {
+			out = append(out, p.Masked())
+			continue
+		}
+		ip, err := netip.ParseAddr(s)
+		if err != nil {
+			panic(fmt.Sprintf("via.WithTrustedProxies: %q is neither a CIDR nor an IP", s))
+		}
+		ip = ip.Unmap()
+		out = append(out, netip.PrefixFrom(ip, ip.BitLen()))
+	}
+	return out
+}
+
+type forwardedKey struct{}
+
+// forwarded is what a trusted proxy reported about the original request.
+type forwarded struct {
+	scheme string
+}
+
+// ClientIP returns the address of the client that made r: the one a
+// trusted proxy reported ([WithTrustedProxies]), else the peer's. ""
+// when r's RemoteAddr isn't an address.
+func ClientIP(r *http.Request) string {
+	if ip, ok := remoteIP(r.RemoteAddr); ok {
+		return ip.String()
+	}
+	return ""
+}
+
+// Scheme returns "https" when r reached the app over TLS, directly or —
+// as reported by a trusted proxy ([WithTrustedProxies]) — at the proxy,
+// and "http" otherwise. Untrusted X-Forwarded-Proto headers are ignored.
+func Scheme(r *http.Request) string {
+	if f, ok := r.Context().Value(forwardedKey{}).(*forwarded); ok && f.scheme != "" {
+		return f.scheme
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func remoteIP(addr string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip, err := netip.ParseAddr(strings.Trim(host, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+func (a *App) trusted(ip netip.Addr) bool {
+	for _, p := range a.cfg.trustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyForwarded rewrites r from its forwarding headers when the peer is
+// a trusted proxy; otherwise r is returned as-is.
+//
+// The client is the rightmost hop that isn't itself a trusted proxy: every
+// proxy appends the address it received from, so hops right of that one
+// were written by proxies we trust, and anything left of it is whatever
+// the client chose to send.
+func (a *App) applyForwarded(r *http.Request) *http.Request {
+	peer, ok := remoteIP(r.RemoteAddr)
+	if !ok || !a.trusted(peer) {
+		return r
+	}
+	var client, scheme, host string
+	if fwd := r.Header.Values("Forwarded"); len(fwd) > 0 {
+		client, scheme, host = a.fromForwarded(fwd)
+	} else {
+		client = a.clientFromXFF(r.Header.Values("X-Forwarded-For"))
+		scheme = lastListValue(r.Header.Values("X-Forwarded-Proto"))
+		host = lastListValue(r.Header.Values("X-Forwarded-Host"))
+	}
+	scheme = strings.ToLower(scheme)
+	if scheme != "http" && scheme != "https" {
+		scheme = ""
+	}
+	if client == "" && scheme == "" && host == "" {
+		return r
+	}
+	r2 := r.WithContext(context.WithValue(r.Context(), forwardedKey{}, &forwarded{scheme: scheme}))
+	if client != "" {
+		r2.RemoteAddr = client
+	}
+	if host != "" {
+		r2.Host = host
+	}
+	return r2
+}
+
+// clientFromXFF picks the client out of X-Forwarded-For.
+func (a *App) clientFromXFF(values []string) string {
+	hops := splitList(values)
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := remoteIP(hops[i])
+		if !ok {
+			return "" // garbage in the trusted part of the chain: don't guess
+		}
+		if !a.trusted(ip) || i == 0 {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// fromForwarded picks the client's element out of RFC 7239 Forwarded
+// headers and returns its for, proto and host. proto and host come from
+// the same element, written by the proxy that accepted the client.
+func (a *App) fromForwarded(values []string) (client, proto, host string) {
+	elems := splitList(values)
+	for i := len(elems) - 1; i >= 0; i-- {
+		var forIP netip.Addr
+		var forOK bool
+		proto, host = "", ""
+		for _, pair := range strings.Split(elems[i], ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			v = strings.Trim(v, `"`)
+			switch strings.ToLower(k) {
+			case "for":
+				forIP, forOK = remoteIP(v)
+			case "proto":
+				proto = v
+			case "host":
+				host = v
+			}
+		}
+		if !forOK {
+			// "unknown", an obfuscated "_node" or garbage: stop here, with
+			// the element's proto and host but no address.
+			return "", proto, host
+		}
+		if !a.trusted(forIP) || i == 0 {
+			return forIP.String(), proto, host
+		}
+	}
+	return "", "", ""
+}
+
+// splitList flattens comma-separated header values, repeated or not.
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// lastListValue is the value the nearest proxy added.
+func lastListValue(values []string) string {
+	list := splitList(values)
+	if len(list) == 0 {
+		return ""
+	}
+	return list[len(list)-1]
+}
@@ -0,0 +1,112 @@
+package via_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoApp answers /who with what via derived about the request.
+func echoApp(opts ...via.Option) *via.App {
+	app := via.New(opts...)
+	app.HandleFunc("GET /who", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(via.ClientIP(r) + " " + via.Scheme(r) + " " + r.Host))
+	})
+	return app
+}
+
+func who(app *via.App, peer string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "http://internal:8080/who", nil)
+	r.RemoteAddr = peer
+	for k, vs := range header {
+		r.Header[k] = vs
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	return w
+}
+
+func sessionCookieOf(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "via_session" {
+			return c
+		}
+	}
+	require.FailNow(t, "no session cookie")
+	return nil
+}
+
+func TestTrustedProxies_readsXForwardedFromATrustedPeer(t *testing.T) {
+	t.Parallel()
+	app := echoApp(via.WithTrustedProxies("10.0.0.0/8"))
+
+	w := who(app, "10.0.0.1:5000", http.Header{
+		// The client forged the first hop; 10.0.0.5 is an inner proxy.
+		"X-Forwarded-For":   {"6.6.6.6, 203.0.113.9", "10.0.0.5"},
+		"X-Forwarded-Proto": {"https"},
+		"X-Forwarded-Host":  {"app.example.com"},
+	})
+	assert.Equal(t, "203.0.113.9 https app.example.com", w.Body.String())
+	assert.True(t, sessionCookieOf(t, w).Secure)
+}
+
+func TestTrustedProxies_ignoresHeadersFromOtherPeers(t *testing.T) {
+	t.Parallel()
+	app := echoApp(via.WithTrustedProxies("10.0.0.0/8"))
+
+	w := who(app, "198.51.100.7:5000", http.Header{
+		"X-Forwarded-For":   {"1.2.3.4"},
+		"X-Forwarded-Proto": {"https"},
+		"X-Forwarded-Host":  {"evil.example"},
+	})
+	assert.Equal(t, "198.51.100.7 http internal:8080", w.Body.String())
+}
+
+func TestTrustedProxies_neverDowngradeTheSecureCookie(t *testing.T) {
+	t.Parallel()
+	app := echoApp(via.WithTrustedProxies("10.0.0.0/8"))
+
+	// A TLS-terminating proxy that sends no proto header.
+	w := who(app, "10.0.0.1:5000", http.Header{"X-Forwarded-For": {"203.0.113.9"}})
+	assert.Equal(t, "203.0.113.9 http internal:8080", w.Body.String())
+	assert.True(t, sessionCookieOf(t, w).Secure, "a missing proto header must not strip Secure")
+
+	insecure := echoApp(via.WithTrustedProxies("10.0.0.0/8"), via.WithInsecureCookies())
+	w = who(insecure, "10.0.0.1:5000", http.Header{"X-Forwarded-Proto": {"https"}})
+	assert.False(t, sessionCookieOf(t, w).Secure, "WithInsecureCookies still opts out")
+}
+
+func TestTrustedProxies_readsForwarded(t *testing.T) {
+	t.Parallel()
+	app := echoApp(via.WithTrustedProxies("127.0.0.1", "10.0.0.0/8"))
+
+	w := who(app, "127.0.0.1:5000", http.Header{
+		"Forwarded":       {`for="[2001:db8::1]:4711";proto=https;host=app.example, for=10.0.0.5;proto=http`},
+		"X-Forwarded-For": {"9.9.9.9"},
+	})
+	assert.Equal(t, "2001:db8::1 https app.example", w.Body.String(), "Forwarded wins over X-Forwarded-*")
+}
+
+func TestTrustedProxies_leaveDefaultsAloneWithoutTheOption(t *testing.T) {
+	t.Parallel()
+	app := echoApp()
+
+	w := who(app, "10.0.0.1:5000", http.Header{"X-Forwarded-For": {"1.2.3.4"}, "X-Forwarded-Proto": {"https"}})
+	assert.Equal(t, "10.0.0.1 http internal:8080", w.Body.String())
+	assert.True(t, sessionCookieOf(t, w).Secure, "Secure by default")
+
+	pinned := echoApp(via.WithTrustedProxies("10.0.0.0/8"), via.WithSecureCookies())
+	assert.True(t, sessionCookieOf(t, who(pinned, "10.0.0.1:5000", nil)).Secure, "WithSecureCookies pins Secure")
+}
+
+func TestWithTrustedProxies_validates(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, `via.WithTrustedProxies: "10.0.0.0/99" is neither a CIDR nor an IP`, func() {
+		via.New(via.WithTrustedProxies("10.0.0.0/99"))
+	})
+}
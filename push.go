@@ -2,10 +2,13 @@ package via
 
 import (
 	"encoding/json"
+	"fmt"
 	"maps"
+	"regexp"
 	"strings"
 
 	"github.com/go-via/via/h"
+	"github.com/starfederation/datastar-go/datastar"
 )
 
 // Imperative client-push helpers on *Ctx: ways for the server to tell
@@ -34,7 +37,8 @@ type Patch struct {
 // push values to client-only signals they own (e.g. picocss's
 // "_picoTheme") without going through a typed Signal[T] handle.
 // Multiple Signal/Signals calls within the same flush window are merged
-// — last write wins per key. Empty key is a no-op.
+// — last write wins per key. Signals pushed before the page document is
+// written also seed it. Empty key is a no-op.
 func (p *Patch) Signal(key string, value any) {
 	if key == "" {
 		return
@@ -87,6 +91,11 @@ func (p *Patch) Element(el h.H) {
 // element patch independently by ID, so a State write followed by a
 // targeted Elements call both reach the DOM in one SSE frame. Nil
 // elements within the variadic list are skipped.
+//
+// An h.Fragment counts as its children: each top-level element in it is
+// patched by its own id, so a builder returning sibling rows or options
+// needs no wrapper element — <tr> and <option> survive the client's
+// parse outside a table or select.
 func (p *Patch) Elements(elements ...h.H) {
 	if p == nil || p.ctx == nil || p.ctx.queue == nil || len(elements) == 0 {
 		return
@@ -111,6 +120,60 @@ func (p *Patch) Elements(elements ...h.H) {
 	q.notify()
 }
 
+// AppendTo inserts elements as the last children of the element with the
+// given id, leaving its existing children untouched — a chat log or feed
+// streams each new item without resending the list. Nil elements are
+// skipped; an empty id or nothing to send is a no-op.
+//
+// The four non-morph modes (AppendTo, PrependTo, ReplaceChildren,
+// RemoveElement) drain in call order after the frame's morph patches.
+// They are not idempotent, so a frame cut short by a dropped connection
+// is not replayed for them on reconnect the way morphs are.
+func (p *Patch) AppendTo(id string, elements ...h.H) {
+	p.moded(datastar.ElementPatchModeAppend, id, elements, false)
+}
+
+// PrependTo inserts elements as the first children of the element with
+// the given id. Same contract as [Patch.AppendTo].
+func (p *Patch) PrependTo(id string, elements ...h.H) {
+	p.moded(datastar.ElementPatchModePrepend, id, elements, false)
+}
+
+// ReplaceChildren morphs the children of the element with the given id
+// into elements, keeping the element itself (and its attributes). With no
+// elements it empties the container. Same contract as [Patch.AppendTo].
+func (p *Patch) ReplaceChildren(id string, elements ...h.H) {
+	p.moded(datastar.ElementPatchModeInner, id, elements, true)
+}
+
+// RemoveElement removes the element with the given id from the DOM.
+// Same contract as [Patch.AppendTo].
+func (p *Patch) RemoveElement(id string) {
+	p.moded(datastar.ElementPatchModeRemove, id, nil, true)
+}
+
+func (p *Patch) moded(mode datastar.ElementPatchMode, id string, elements []h.H, allowEmpty bool) {
+	if p == nil || p.ctx == nil || p.ctx.queue == nil || id == "" {
+		return
+	}
+	buf := getRenderBuf()
+	defer putRenderBuf(buf)
+	for _, el := range elements {
+		if el == nil {
+			continue
+		}
+		_ = el.Render(buf)
+	}
+	if buf.Len() == 0 && !allowEmpty {
+		return
+	}
+	q := p.ctx.queue
+	q.mu.Lock()
+	q.moded = append(q.moded, modedPatch{mode: mode, id: id, html: buf.String()})
+	q.mu.Unlock()
+	q.notify()
+}
+
 // ExecScript queues a JavaScript snippet for execution on the client at
 // the next flush. Use sparingly — most reactivity should flow through
 // signals/state rather than imperative scripts.
@@ -121,6 +184,193 @@ func (ctx *Ctx) ExecScript(s string) {
 	enqueueScript(ctx, s)
 }
 
+// RegisterJS defines a named client function for [Ctx.InvokeJS]. fn is a
+// JavaScript function expression — `(points) => chart.setData(points)` —
+// shipped once per tab: calling RegisterJS again with the same source is
+// free, so it can sit in OnConnect or at the top of a Stream callback.
+// Re-registering a name with different source replaces the function.
+//
+// Pair it with InvokeJS for anything driven at high frequency: ExecScript
+// re-sends and re-parses the whole snippet every call, a registered
+// function sends only the call and its data.
+func (ctx *Ctx) RegisterJS(name, fn string) {
+	if ctx == nil || name == "" || fn == "" {
+		return
+	}
+	ctx.mu.Lock()
+	if ctx.jsFns[name] == fn {
+		ctx.mu.Unlock()
+		return
+	}
+	if ctx.jsFns == nil {
+		ctx.jsFns = make(map[string]string)
+	}
+	ctx.jsFns[name] = fn
+	ctx.mu.Unlock()
+	enqueueScript(ctx, "(window.__viaFn=window.__viaFn||{})["+jsString(name)+"]=("+fn+")")
+}
+
+// InvokeJS calls the client function name, registered with
+// [Ctx.RegisterJS], at the next flush. args are JSON-encoded, so Go
+// values arrive as their JSON shapes and can neither break out of the
+// call nor the surrounding script element; a [Float32Array] or
+// [Float64Array] arg arrives as the matching JS typed array instead.
+// Invoking a name this tab never registered, or args that don't encode,
+// logs an error and sends nothing.
+func (ctx *Ctx) InvokeJS(name string, args ...any) {
+	if ctx == nil || name == "" {
+		return
+	}
+	ctx.mu.Lock()
+	_, ok := ctx.jsFns[name]
+	ctx.mu.Unlock()
+	if !ok {
+		ctx.app.logErr(ctx, "InvokeJS %q: no such function; call RegisterJS first", name)
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString("window.__viaFn[" + jsString(name) + "](")
+	binary, err := writeJSArgs(&sb, args)
+	if err != nil {
+		ctx.app.logErr(ctx, "InvokeJS %q: %v", name, err)
+		return
+	}
+	sb.WriteByte(')')
+	enqueueScript(ctx, withTypedArrays(sb.String(), binary))
+}
+
+// CallJS calls the client function fn — a global name or dotted path
+// such as "chart.setOption" or "window.app?.refresh" — at the next
+// flush, with args JSON-encoded as for [Ctx.InvokeJS]. Unlike InvokeJS
+// nothing need be registered first. fn is code: a name that isn't a
+// plain identifier path, or args that don't encode, logs an error and
+// sends nothing.
+//
+//	ctx.CallJS("chart.setOption", opts)  // chart.setOption({"series":[…]})
+func (ctx *Ctx) CallJS(fn string, args ...any) {
+	if ctx == nil || fn == "" {
+		return
+	}
+	if !jsPathRE.MatchString(fn) {
+		ctx.app.logErr(ctx, "CallJS %q: not a function name or dotted path", fn)
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(fn + "(")
+	binary, err := writeJSArgs(&sb, args)
+	if err != nil {
+		ctx.app.logErr(ctx, "CallJS %q: %v", fn, err)
+		return
+	}
+	sb.WriteByte(')')
+	enqueueScript(ctx, withTypedArrays(sb.String(), binary))
+}
+
+// ExecScriptf queues a script built from template at the next flush,
+// with each %v replaced by the next arg encoded as a JavaScript value —
+// JSON, escaped so no string can end the script element it travels in.
+// %% is a literal percent sign; no other verbs are recognised. A
+// template whose placeholders don't match args, or args that don't
+// encode, logs an error and sends nothing.
+//
+//	ctx.ExecScriptf("document.title = %v", title)
+//	ctx.ExecScriptf("localStorage.setItem(%v, %v)", "theme", theme)
+func (ctx *Ctx) ExecScriptf(template string, args ...any) {
+	if ctx == nil || template == "" {
+		return
+	}
+	var sb strings.Builder
+	binary := false
+	next := 0
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if c != '%' {
+			sb.WriteByte(c)
+			continue
+		}
+		if i+1 == len(template) {
+			ctx.app.logErr(ctx, "ExecScriptf: trailing %% in %q", template)
+			return
+		}
+		i++
+		switch template[i] {
+		case '%':
+			sb.WriteByte('%')
+		case 'v':
+			if next == len(args) {
+				ctx.app.logErr(ctx, "ExecScriptf: %q has more %%v than its %d args", template, len(args))
+				return
+			}
+			b, err := writeJSValue(&sb, args[next])
+			if err != nil {
+				ctx.app.logErr(ctx, "ExecScriptf: encode arg %d: %v", next, err)
+				return
+			}
+			binary = binary || b
+			next++
+		default:
+			ctx.app.logErr(ctx, "ExecScriptf: unknown verb %%%c in %q (use %%v)", template[i], template)
+			return
+		}
+	}
+	if next != len(args) {
+		ctx.app.logErr(ctx, "ExecScriptf: %q has %d %%v for %d args", template, next, len(args))
+		return
+	}
+	enqueueScript(ctx, withTypedArrays(sb.String(), binary))
+}
+
+// jsPathRE matches the function names CallJS accepts: identifiers joined
+// by "." or "?.".
+var jsPathRE = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\??\.[A-Za-z_$][\w$]*)*$`)
+
+// writeJSArgs writes args comma-separated as JavaScript values: JSON,
+// whose HTML escaping keeps a "</script>" inert, or for a [Float32Array]
+// or [Float64Array] the call that decodes it. binary reports whether any
+// was a typed array, whose decoder the script then needs.
+func writeJSArgs(sb *strings.Builder, args []any) (binary bool, err error) {
+	for i, a := range args {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		b, err := writeJSValue(sb, a)
+		if err != nil {
+			return false, fmt.Errorf("encode arg %d: %w", i, err)
+		}
+		binary = binary || b
+	}
+	return binary, nil
+}
+
+// writeJSValue writes one arg as writeJSArgs does.
+func writeJSValue(sb *strings.Builder, a any) (binary bool, err error) {
+	if ta, ok := a.(typedArray); ok {
+		ta.writeJS(sb)
+		return true, nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	sb.Write(b)
+	return false, nil
+}
+
+// withTypedArrays prefixes script with the typed-array decoder when one
+// of its args needs it.
+func withTypedArrays(script string, binary bool) string {
+	if binary {
+		return typedArrayDecoder + script
+	}
+	return script
+}
+
+// jsString encodes s as a JS string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 // Reload tells the browser to reload the current page on the next
 // flush. Convenience wrapper for the common "the data changed
 // drastically; just refetch" pattern after multi-step actions.
@@ -1,6 +1,7 @@
 package via_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,13 @@ func (p *syncPage) PushList(ctx *via.Ctx) error {
 	return nil
 }
 
+func (p *syncPage) PushRows(ctx *via.Ctx) error {
+	row := func(id, name string) h.H { return h.Tr(h.ID(id), h.Td(h.Text(name))) }
+	ctx.Patch().Elements(h.Fragment(row("row-1", "ada"), row("row-2", "grace")))
+	ctx.Patch().AppendTo("rows", h.Fragment(row("row-3", "linus"), row("row-4", "barbara")))
+	return nil
+}
+
 func (p *syncPage) PickTheme(ctx *via.Ctx) error {
 	ctx.Patch().Signal("_picoTheme", "purple")
 	return nil
@@ -47,6 +55,25 @@ func TestSyncElements_pushesManualPatchOverSSE(t *testing.T) {
 	vt.AwaitFrame(t, frames, 2*time.Second, `id="results"`, "first")
 }
 
+func TestSyncElements_patchesEachElementOfAFragment(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[syncPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("PushRows").Fire())
+	got := vt.AwaitFrame(t, frames, 2*time.Second,
+		`elements <tr id="row-1"><td>ada</td></tr><tr id="row-2"><td>grace</td></tr>`,
+		"selector #rows",
+		`elements <tr id="row-3"><td>linus</td></tr><tr id="row-4"><td>barbara</td></tr>`)
+	assert.NotContains(t, got, "<div", "a fragment adds no wrapper element")
+}
+
 func TestCtx_pushHelpersToleratesNilReceiver(t *testing.T) {
 	t.Parallel()
 	// Every push.go helper has `if ctx == nil { return }` as its first
@@ -62,6 +89,8 @@ func TestCtx_pushHelpersToleratesNilReceiver(t *testing.T) {
 		{"Reload", func() { ctx.Reload() }},
 		{"Notify", func() { ctx.Notify("hi") }},
 		{"Redirect", func() { ctx.Redirect("/") }},
+		{"RegisterJS", func() { ctx.RegisterJS("f", "()=>{}") }},
+		{"InvokeJS", func() { ctx.InvokeJS("f", 1) }},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -85,3 +114,215 @@ func TestPatchSignal_pushesKeyedValueToClient(t *testing.T) {
 	require.Equal(t, 200, tc.Action("PickTheme").Fire())
 	vt.AwaitFrame(t, frames, 2*time.Second, `"_picoTheme":"purple"`)
 }
+
+type streamFeedPage struct{}
+
+func (p *streamFeedPage) Append(ctx *via.Ctx) {
+	ctx.Patch().AppendTo("feed", h.Li(h.Text("newest")))
+}
+
+func (p *streamFeedPage) Prepend(ctx *via.Ctx) {
+	ctx.Patch().PrependTo("feed", h.Li(h.Text("oldest")))
+}
+
+func (p *streamFeedPage) Reset(ctx *via.Ctx) {
+	ctx.Patch().ReplaceChildren("feed", h.Li(h.Text("only")))
+}
+
+func (p *streamFeedPage) Drop(ctx *via.Ctx) {
+	ctx.Patch().RemoveElement("feed")
+}
+
+func (p *streamFeedPage) View(ctx *via.CtxR) h.H {
+	return h.Ul(h.ID("feed"), h.Li(h.Text("first")))
+}
+
+func TestPatchModes_shipSelectorAndMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  string
+		needles []string
+	}{
+		{"append", "Append", []string{"selector #feed", "mode append", "newest"}},
+		{"prepend", "Prepend", []string{"selector #feed", "mode prepend", "oldest"}},
+		{"replace children", "Reset", []string{"selector #feed", "mode inner", "only"}},
+		{"remove", "Drop", []string{"selector #feed", "mode remove"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := via.New()
+			server := vt.Serve(t, app)
+			via.Mount[streamFeedPage](app, "/")
+
+			tc := vt.NewClient(t, server, "/")
+			frames, cancel := tc.SSEReady()
+			defer cancel()
+
+			require.Equal(t, 200, tc.Action(tt.action).Fire())
+			vt.AwaitFrame(t, frames, 2*time.Second, tt.needles...)
+		})
+	}
+}
+
+func TestPatchModes_toleratesNilReceiver(t *testing.T) {
+	t.Parallel()
+
+	var p *via.Patch
+	assert.NotPanics(t, func() {
+		p.AppendTo("", h.Li())
+		p.RemoveElement("feed")
+	})
+}
+
+type chartFnPage struct{}
+
+func (p *chartFnPage) Tick(ctx *via.Ctx) {
+	ctx.RegisterJS("plot", "(pts, label) => window.plotted = [pts, label]")
+	ctx.InvokeJS("plot", []int{1, 2}, "</script>")
+}
+
+func (p *chartFnPage) TickBinary(ctx *via.Ctx) {
+	ctx.RegisterJS("plot", "(pts) => window.plotted = pts")
+	ctx.InvokeJS("plot", via.Float32Array{1, 2}, via.Float64Array{-0.5})
+}
+
+func (p *chartFnPage) Stray(ctx *via.Ctx) {
+	ctx.InvokeJS("missing")
+}
+
+func (p *chartFnPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestInvokeJS_shipsFunctionOnceThenOnlyTheCall(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[chartFnPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Tick").Fire())
+	first := vt.AwaitFrame(t, frames, 2*time.Second, `window.__viaFn["plot"]([1,2],"\u003c/script\u003e")`)
+	assert.Contains(t, first, `["plot"]=((pts, label) =>`)
+
+	require.Equal(t, 200, tc.Action("Tick").Fire())
+	second := vt.AwaitFrame(t, frames, 2*time.Second, `window.__viaFn["plot"]([1,2]`)
+	assert.NotContains(t, second, "=>", "an unchanged registration must not be re-sent")
+}
+
+func TestInvokeJS_shipsTypedArraysAsBase64Bytes(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[chartFnPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("TickBinary").Fire())
+	// Little-endian IEEE-754: 1.0f = 00 00 80 3f, 2.0f = 00 00 00 40,
+	// -0.5 = 00 .. e0 bf.
+	frame := vt.AwaitFrame(t, frames, 2*time.Second,
+		`window.__viaFn["plot"](__viaB64(Float32Array,"AACAPwAAAEA="),__viaB64(Float64Array,"AAAAAAAA4L8="))`)
+	assert.Contains(t, frame, "window.__viaB64=window.__viaB64||",
+		"a call carrying typed arrays must ship the decoder it relies on")
+}
+
+func TestInvokeJS_unregisteredNameLogsAndSendsNothing(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogDebug)
+	via.Mount[chartFnPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Stray").Fire())
+
+	var found bool
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogError && strings.Contains(r.msg, `InvokeJS "missing": no such function`) {
+			found = true
+		}
+	}
+	assert.True(t, found, "invoking an unregistered function should log an error")
+}
+
+type scriptfPage struct{}
+
+func (p *scriptfPage) Title(ctx *via.Ctx) error {
+	ctx.ExecScriptf("document.title = %v + ' 100%%'", `"</script><script>alert(1)`)
+	return nil
+}
+
+func (p *scriptfPage) Plot(ctx *via.Ctx) error {
+	ctx.CallJS("window.chart?.setData", []int{1, 2}, via.Float32Array{1})
+	return nil
+}
+
+func (p *scriptfPage) Bad(ctx *via.Ctx) error {
+	ctx.ExecScriptf("f(%v, %v)", 1)
+	ctx.ExecScriptf("f(%s)", "x")
+	ctx.CallJS("alert(1);f", 1)
+	return nil
+}
+
+func (p *scriptfPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestExecScriptf_encodesArgsAsInertJSValues(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[scriptfPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Title").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second,
+		`document.title = "\"\u003c/script\u003e\u003cscript\u003ealert(1)" + ' 100%'`)
+	assert.NotContains(t, frame, "</script><script>")
+}
+
+func TestCallJS_callsAPathWithEncodedArgs(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[scriptfPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Plot").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second,
+		"window.__viaB64=window.__viaB64||",
+		`window.chart?.setData([1,2],__viaB64(Float32Array,"AACAPw=="))`)
+}
+
+func TestExecScriptfAndCallJS_logMisuseAndSendNothing(t *testing.T) {
+	t.Parallel()
+	app, server, logger := newLoggedApp(t, via.LogDebug)
+	via.Mount[scriptfPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Bad").Fire())
+
+	var msgs []string
+	for _, r := range logger.snapshot() {
+		if r.level == via.LogError {
+			msgs = append(msgs, r.msg)
+		}
+	}
+	joined := strings.Join(msgs, "\n")
+	assert.Contains(t, joined, `ExecScriptf: "f(%v, %v)" has more %v than its 1 args`)
+	assert.Contains(t, joined, `ExecScriptf: unknown verb %s`)
+	assert.Contains(t, joined, `CallJS "alert(1);f": not a function name or dotted path`)
+}
@@ -84,7 +84,11 @@ func (a *App) recoverSSE(w http.ResponseWriter, r *http.Request, staleID string)
 			a.streamReloadScript(w, r)
 			return
 		}
-		m.Counter("via.sse.recover", "mode", "rebootstrap")
+		mode := "rebootstrap"
+		if isShellTabID(staleID) {
+			mode = "shell"
+		}
+		m.Counter("via.sse.recover", "mode", mode)
 		runSSEStream(a, ctx, w, r, boot)
 	})
 	applyMiddleware(d.groupMW, handler).ServeHTTP(w, r)
@@ -141,9 +145,11 @@ func (a *App) rebootstrapCtx(d *cmpDescriptor, w http.ResponseWriter, r, pageReq
 	cmpVal := reflect.New(d.typ)
 	ctx := newCtx(a, d, cmpVal, genTabID(d.route))
 	ctx.session.Store(a.sessionFromRequest(r))
+	ctx.takeFlashes()
 	ctx.mu.Lock()
 	ctx.w = w
 	ctx.r = r
+	ctx.clientHeader, ctx.clientAddr = r.Header, r.RemoteAddr // kept for Client
 	ctx.mu.Unlock()
 	ctx.captureCSPNonce(r)
 	// Same scoping as renderPage: writer/request live only for the
@@ -186,8 +192,8 @@ func (a *App) rebootstrapCtx(d *cmpDescriptor, w http.ResponseWriter, r, pageReq
 	// (a no-op close after recovery), so the recovered ctx would only ever
 	// be reclaimed by the TTL sweep. Queue a replacement beacon for the
 	// fresh id; drainQueue ships it right after the bootstrap frames.
-	enqueueScript(ctx, "window.addEventListener('beforeunload',()=>{navigator.sendBeacon('/_sse/close','"+
-		template.JSEscapeString(ctx.id)+"');})")
+	enqueueScript(ctx, "window.addEventListener('beforeunload',()=>{navigator.sendBeacon('"+
+		template.JSEscapeString(a.cfg.basePath)+"/_sse/close','"+template.JSEscapeString(ctx.id)+"');})")
 
 	return ctx, &sseBootstrap{
 		signals:  sigs,
@@ -210,16 +216,22 @@ func staleContainerSelector(staleID string) string {
 // (a 200, so Datastar stops hammering the endpoint) and push an explicit
 // reload. The subsequent page GET re-bootstraps everything from scratch.
 func (a *App) streamReloadScript(w http.ResponseWriter, r *http.Request) {
-	sse := datastar.NewSSE(w, r,
-		datastar.WithCompression(datastar.WithBrotli(datastar.WithBrotliLevel(sseLevel))))
+	sse := datastar.NewSSE(w, r, a.cfg.compression.sseOptions()...)
 	setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
-	var opts []datastar.ExecuteScriptOption
-	// No ctx on this path — thread the request's strict-CSP nonce (if a
-	// CSP middleware installed one) straight onto the injected <script>.
-	if n, ok := r.Context().Value(cspNonceKey{}).(string); ok && n != "" {
-		opts = append(opts, datastar.WithExecuteScriptAttributes(`nonce="`+html.EscapeString(n)+`"`))
+	_ = sse.ExecuteScript("window.location.reload()", requestNonceOpts(r)...)
+}
+
+// requestNonceOpts is scriptNonceOpts for paths with no ctx: it threads
+// the request's strict-CSP nonce (if a CSP middleware installed one)
+// straight onto the injected <script>.
+func requestNonceOpts(r *http.Request) []datastar.ExecuteScriptOption {
+	n, ok := r.Context().Value(cspNonceKey{}).(string)
+	if !ok || n == "" {
+		return nil
+	}
+	return []datastar.ExecuteScriptOption{
+		datastar.WithExecuteScriptAttributes(`nonce="` + html.EscapeString(n) + `"`),
 	}
-	_ = sse.ExecuteScript("window.location.reload()", opts...)
 }
 
 // noopResponseWriter absorbs the throwaway mux's output (the 404 it writes
@@ -0,0 +1,166 @@
+package via
+
+import (
+	"html/template"
+	"slices"
+
+	"github.com/go-via/via/h"
+)
+
+// Region renders fn wrapped in a <div id="name"> and registers it as a
+// named sub-view of the page, so an action can later re-render and patch
+// just that element with [Ctx.SyncRegion] instead of re-rendering the
+// whole view. fn is re-invoked on each SyncRegion, so it must read the
+// current state rather than capture values:
+//
+//	func (p *Dash) View(ctx *via.CtxR) h.H {
+//	    return h.Div(
+//	        bigStaticTable(),
+//	        ctx.Region("chart", func() h.H { return chart(p.points) }),
+//	    )
+//	}
+//
+// name is the element id on the wire; use [Ctx.ScopedID] when the same
+// region is rendered by repeated components.
+func (r *CtxR) Region(name string, fn func() h.H) h.H {
+	if r == nil || r.ctx == nil || fn == nil {
+		return nil
+	}
+	ctx := r.ctx
+	ctx.readsMu.Lock()
+	if ctx.regions == nil {
+		ctx.regions = make(map[string]func() h.H)
+	}
+	ctx.regions[name] = fn
+	ctx.readsMu.Unlock()
+	return h.Div(h.ID(name), fn())
+}
+
+// Lazy is a [CtxR.Region] whose content waits until it scrolls into view.
+// Until then it renders placeholder, and fn is not called at all, so a
+// long dashboard pays only for the widgets someone has scrolled to:
+//
+//	ctx.Lazy("traffic", h.P(h.Text("Loading…")), func() h.H { return trafficChart(p.db) })
+//
+// The first time the placeholder becomes visible, the browser reports it
+// and the region is rendered and patched in over the SSE stream; the
+// report runs behind the route's group middleware, signed and audited as
+// "lazy" like an action. From
+// then on it behaves as a plain Region for the tab's lifetime: full
+// renders include it and [Ctx.SyncRegion] updates it. A SyncRegion before
+// the reveal re-sends the placeholder.
+func (r *CtxR) Lazy(name string, placeholder h.H, fn func() h.H) h.H {
+	if r == nil || r.ctx == nil || fn == nil {
+		return nil
+	}
+	ctx := r.ctx
+	payload := "'" + template.JSEscapeString(ctx.id+" "+name) + "'"
+	if ctx.app.signer.covers(lazyAction) {
+		payload += "+' '+$" + sigSignalKey + "." + lazyAction
+	}
+	trigger := h.Div(
+		h.Data("on-intersect__once", "navigator.sendBeacon('"+
+			template.JSEscapeString(ctx.app.cfg.basePath)+"/_sse/lazy',"+payload+")"),
+		placeholder,
+	)
+	return r.Region(name, func() h.H {
+		ctx.readsMu.Lock()
+		shown := ctx.lazyShown[name]
+		ctx.readsMu.Unlock()
+		if !shown {
+			return trigger
+		}
+		return fn()
+	})
+}
+
+// revealLazy marks the Lazy region name as seen and patches its content
+// in. Serialized with actions, so fn reads state no handler is mid-way
+// through writing. A name already revealed, or never rendered, is ignored.
+func (ctx *Ctx) revealLazy(name string) {
+	ctx.actionMu.Lock()
+	defer ctx.actionMu.Unlock()
+	ctx.readsMu.Lock()
+	_, known := ctx.regions[name]
+	if !known || ctx.lazyShown[name] {
+		ctx.readsMu.Unlock()
+		return
+	}
+	if ctx.lazyShown == nil {
+		ctx.lazyShown = make(map[string]bool)
+	}
+	ctx.lazyShown[name] = true
+	ctx.readsMu.Unlock()
+	ctx.syncRegion(name)
+}
+
+// SyncRegion re-renders the region registered under name by the last
+// render and queues it as a single element patch — the rest of the DOM
+// is not re-rendered or sent. Use it for pages where most of the view is
+// static and an action (or a Stream tick) only changes one area.
+//
+// SyncRegion does not suppress the end-of-action full re-render that a
+// State write triggers: mutate plain fields (or call SyncOff first) when
+// the region is the only thing meant to change. A name the last render
+// didn't register is logged and ignored.
+//
+// Safe from any goroutine: the region renders serialized with the tab's
+// actions, so called from an action or stream tick it is patched once
+// the handler returns.
+func (ctx *Ctx) SyncRegion(name string) {
+	if ctx == nil {
+		return
+	}
+	ctx.readsMu.Lock()
+	first := len(ctx.regionSyncs) == 0
+	if !slices.Contains(ctx.regionSyncs, name) {
+		ctx.regionSyncs = append(ctx.regionSyncs, name)
+	}
+	ctx.readsMu.Unlock()
+	if first {
+		go ctx.syncRegions()
+	}
+}
+
+// syncRegions renders the regions SyncRegion queued under actionMu, as
+// runBroadcastFunc does; a tab disposed meanwhile is skipped.
+func (ctx *Ctx) syncRegions() {
+	ctx.actionMu.Lock()
+	defer ctx.actionMu.Unlock()
+	ctx.readsMu.Lock()
+	names := ctx.regionSyncs
+	ctx.regionSyncs = nil
+	ctx.readsMu.Unlock()
+	if ctx.Disposed() {
+		return
+	}
+	for _, name := range names {
+		ctx.syncRegion(name)
+	}
+}
+
+func (ctx *Ctx) syncRegion(name string) {
+	ctx.readsMu.Lock()
+	fn := ctx.regions[name]
+	ctx.readsMu.Unlock()
+	if fn == nil {
+		ctx.app.logWarn(ctx, "SyncRegion: no region %q rendered on this page", name)
+		return
+	}
+	var (
+		body h.H
+		ok   bool
+	)
+	func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				ctx.app.logErr(ctx, "region %q panicked: %v", name, rec)
+			}
+		}()
+		body, ok = fn(), true
+	}()
+	if !ok {
+		return
+	}
+	ctx.patch.Element(h.Div(h.ID(name), body))
+}
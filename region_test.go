@@ -0,0 +1,207 @@
+package via_test
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type regionPage struct {
+	points int
+}
+
+func (p *regionPage) Tick(ctx *via.Ctx) {
+	p.points++
+	ctx.SyncRegion("chart")
+}
+
+func (p *regionPage) Missing(ctx *via.Ctx) {
+	ctx.SyncRegion("nope")
+}
+
+func (p *regionPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.ID("static"), h.Text("static table")),
+		ctx.Region("chart", func() h.H {
+			return h.Span(h.Text("points=" + strconv.Itoa(p.points)))
+		}),
+	)
+}
+
+func TestRegion_rendersWrappedInNamedElement(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[regionPage](app, "/")
+
+	html := vt.NewClient(t, server, "/").HTML()
+
+	assert.Contains(t, html, `<div id="chart"><span>points=0</span></div>`)
+}
+
+func TestSyncRegion_patchesOnlyTheRegion(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[regionPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Tick").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, `id="chart"`, "points=1")
+	assert.NotContains(t, frame, "static table", "the rest of the view is not re-sent")
+}
+
+type foldingPage struct {
+	Folded via.StateTabBool
+	points atomic.Int32
+}
+
+func (p *foldingPage) Fold(ctx *via.Ctx) { p.Folded.Write(ctx, true) }
+
+func (p *foldingPage) Sync(ctx *via.Ctx) { ctx.SyncRegion("chart") }
+
+// Background re-renders the region from outside any action, the way a
+// goroutine fed by a message queue would.
+func (p *foldingPage) Background(ctx *via.Ctx) {
+	go func() {
+		p.points.Add(1)
+		ctx.SyncRegion("chart")
+	}()
+}
+
+func (p *foldingPage) View(ctx *via.CtxR) h.H {
+	if p.Folded.Read(ctx) {
+		return h.P(h.Text("folded"))
+	}
+	return ctx.Region("chart", func() h.H {
+		return h.Span(h.Textf("points=%d", p.points.Load()))
+	})
+}
+
+func TestSyncRegion_forgetsRegionsTheLastRenderDropped(t *testing.T) {
+	t.Parallel()
+
+	app, server, logger := newLoggedApp(t, via.LogWarn)
+	via.Mount[foldingPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Fold").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "folded")
+	require.Equal(t, 200, tc.Action("Sync").Fire())
+	assert.Eventually(t, func() bool {
+		for _, r := range logger.snapshot() {
+			if strings.Contains(r.msg, `no region "chart"`) {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond, "a region the view no longer renders must not be patched")
+}
+
+func TestSyncRegion_fromAnotherGoroutine(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[foldingPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Background").Fire())
+	require.Equal(t, 200, tc.Action("Sync").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="chart"`, "points=1")
+}
+
+func TestSyncRegion_ignoresUnknownName(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[regionPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	assert.Equal(t, 200, tc.Action("Missing").Fire())
+}
+
+type lazyPage struct {
+	renders atomic.Int32
+}
+
+func (p *lazyPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.ID("static"), h.Text("static table")),
+		ctx.Lazy("heavy", h.Span(h.Text("loading")), func() h.H {
+			return h.Span(h.Text("widget #" + strconv.Itoa(int(p.renders.Add(1)))))
+		}),
+	)
+}
+
+func TestLazy_rendersPlaceholderUntilRevealed(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[lazyPage](app, "/")
+
+	html := vt.NewClient(t, server, "/").HTML()
+
+	assert.Contains(t, html, `<div id="heavy"><div data-on-intersect__once=`)
+	assert.Contains(t, html, "/_sse/lazy")
+	assert.Contains(t, html, "loading")
+	assert.NotContains(t, html, "widget", "the content isn't rendered before it is visible")
+}
+
+func TestLazy_revealPatchesContentIn(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[lazyPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Reveal("heavy"))
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, `id="heavy"`, "widget #1")
+	assert.NotContains(t, frame, "static table", "only the region is patched")
+	assert.NotContains(t, frame, "loading")
+
+	// Repeat and unknown reports are ignored, not errors.
+	assert.Equal(t, 200, tc.Reveal("heavy"))
+	assert.Equal(t, 200, tc.Reveal("unknown"))
+}
+
+func TestLazy_revealIsSigned(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithActionSigning(via.ActionSigning{Key: sigKey}))
+	server := vt.Serve(t, app)
+	via.Mount[lazyPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	assert.Contains(t, tc.HTML(), "$via_sig.lazy")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Reveal("heavy"))
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="heavy"`, "widget #1")
+}
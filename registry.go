@@ -0,0 +1,137 @@
+package via
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// registryShards is the number of independently locked slices of the tab
+// registry. A power of two so the shard pick is a mask; 64 keeps two
+// concurrent lookups from sharing a lock in the common case without
+// making the whole-registry walks (broadcast, TTL sweep) noticeably
+// slower.
+const registryShards = 64
+
+// ctxRegistry maps tab id → live *Ctx. Every page load, action and SSE
+// attach touches it, so it is split into shards, each with its own lock,
+// and the live count is kept outside them: a lookup only ever contends
+// with other requests for tabs in the same shard. The zero value is ready
+// to use.
+type ctxRegistry struct {
+	live   atomic.Int64
+	shards [registryShards]ctxShard
+}
+
+// cacheLine is the cache line size the shard padding targets (amd64,
+// most arm64).
+const cacheLine = 64
+
+type ctxShard struct {
+	mu sync.RWMutex
+	m  map[string]*Ctx
+	// Round the shard up to whole cache lines, so neighbouring shards'
+	// locks never share one.
+	_ [cacheLine - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(map[string]*Ctx(nil)))%cacheLine]byte
+}
+
+// A shard that isn't a whole number of cache lines fails to compile.
+var _ [0]struct{} = [unsafe.Sizeof(ctxShard{}) % cacheLine]struct{}{}
+
+// shard picks by the id's last bytes: a tab id ends in genSecureID's random
+// hex, so its tail is already uniformly spread and hashing the (shared)
+// route prefix would only cost time.
+func (r *ctxRegistry) shard(id string) *ctxShard {
+	var h uint
+	for i := max(0, len(id)-4); i < len(id); i++ {
+		h = h*31 + uint(id[i])
+	}
+	return &r.shards[h&(registryShards-1)]
+}
+
+// tryAdd registers ctx unless limit > 0 and limit tabs are already live,
+// reporting the live count after the insert. The cap is claimed with a
+// CAS on the count before the shard is touched, so concurrent adds can't
+// both slip under it.
+func (r *ctxRegistry) tryAdd(ctx *Ctx, limit int) (live int, ok bool) {
+	for {
+		n := r.live.Load()
+		if limit > 0 && n >= int64(limit) {
+			return int(n), false
+		}
+		if r.live.CompareAndSwap(n, n+1) {
+			break
+		}
+	}
+	s := r.shard(ctx.id)
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = make(map[string]*Ctx)
+	}
+	_, replaced := s.m[ctx.id]
+	s.m[ctx.id] = ctx
+	s.mu.Unlock()
+	if replaced {
+		return int(r.live.Add(-1)), true
+	}
+	return int(r.live.Load()), true
+}
+
+// remove drops id, reporting the live count afterwards.
+func (r *ctxRegistry) remove(id string) int {
+	s := r.shard(id)
+	s.mu.Lock()
+	_, ok := s.m[id]
+	delete(s.m, id)
+	s.mu.Unlock()
+	if ok {
+		return int(r.live.Add(-1))
+	}
+	return int(r.live.Load())
+}
+
+func (r *ctxRegistry) get(id string) (*Ctx, bool) {
+	s := r.shard(id)
+	s.mu.RLock()
+	ctx, ok := s.m[id]
+	s.mu.RUnlock()
+	return ctx, ok
+}
+
+func (r *ctxRegistry) len() int { return int(r.live.Load()) }
+
+// all copies every live *Ctx, one shard at a time, so callers can iterate
+// without holding any registry lock. Not a point-in-time snapshot across
+// shards — a tab registered mid-walk may or may not be included, as with
+// any walk that races a registration.
+func (r *ctxRegistry) all() []*Ctx {
+	out := make([]*Ctx, 0, r.len())
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.mu.RLock()
+		for _, c := range s.m {
+			out = append(out, c)
+		}
+		s.mu.RUnlock()
+	}
+	return out
+}
+
+// removeIf drops and returns every tab drop reports true for. drop runs
+// under the shard lock and must not touch the registry.
+func (r *ctxRegistry) removeIf(drop func(*Ctx) bool) []*Ctx {
+	var out []*Ctx
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.mu.Lock()
+		for id, c := range s.m {
+			if drop(c) {
+				delete(s.m, id)
+				out = append(out, c)
+			}
+		}
+		s.mu.Unlock()
+	}
+	r.live.Add(-int64(len(out)))
+	return out
+}
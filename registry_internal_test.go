@@ -0,0 +1,122 @@
+package via
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCtxRegistry_capHoldsUnderConcurrentAdds(t *testing.T) {
+	t.Parallel()
+
+	var r ctxRegistry
+	var wg sync.WaitGroup
+	var admitted atomic.Int32
+	for i := range 500 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := r.tryAdd(&Ctx{id: fmt.Sprintf("/_%d", i)}, 100); ok {
+				admitted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 100, admitted.Load())
+	assert.Equal(t, 100, r.len())
+	assert.Len(t, r.all(), 100)
+}
+
+func TestCtxRegistry_reAddAndRemoveKeepTheCount(t *testing.T) {
+	t.Parallel()
+
+	var r ctxRegistry
+	r.tryAdd(&Ctx{id: "/_a"}, 0)
+	live, _ := r.tryAdd(&Ctx{id: "/_a"}, 0)
+	assert.Equal(t, 1, live, "re-adding an id must replace, not grow")
+	assert.Equal(t, 1, r.remove("/_missing"), "removing an unknown id must not shrink")
+
+	r.tryAdd(&Ctx{id: "/_b"}, 0)
+	gone := r.removeIf(func(c *Ctx) bool { return c.id == "/_b" })
+	assert.Len(t, gone, 1)
+	assert.Equal(t, 1, r.len())
+	_, ok := r.get("/_a")
+	assert.True(t, ok, "the untouched tab must survive removeIf")
+}
+
+// singleLockRegistry is the layout the sharded registry replaced: one map
+// behind one RWMutex. Kept here only as the baseline for the benchmark.
+type singleLockRegistry struct {
+	mu sync.RWMutex
+	m  map[string]*Ctx
+}
+
+func (r *singleLockRegistry) get(id string) (*Ctx, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.m[id]
+	return c, ok
+}
+
+func (r *singleLockRegistry) add(c *Ctx) {
+	r.mu.Lock()
+	r.m[c.id] = c
+	r.mu.Unlock()
+}
+
+func (r *singleLockRegistry) remove(id string) {
+	r.mu.Lock()
+	delete(r.m, id)
+	r.mu.Unlock()
+}
+
+// BenchmarkContextRegistry runs the registry's real mix — mostly lookups
+// (actions, SSE attach), one page load and one dispose in every 16 ops —
+// over 20k live tabs, against the single-lock baseline. Run with -cpu to
+// see the gap grow with cores.
+func BenchmarkContextRegistry(b *testing.B) {
+	const tabs = 20_000
+	ids := make([]string, tabs)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("/dash_%064x", i)
+	}
+	mix := func(b *testing.B, get func(string), add func(*Ctx), remove func(string)) {
+		var next atomic.Int64
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			fresh := &Ctx{}
+			for pb.Next() {
+				n := next.Add(1)
+				id := ids[n%tabs]
+				switch n % 16 {
+				case 0:
+					fresh.id = id
+					add(fresh)
+				case 1:
+					remove(id)
+				default:
+					get(id)
+				}
+			}
+		})
+	}
+	b.Run("sharded", func(b *testing.B) {
+		var r ctxRegistry
+		for _, id := range ids {
+			r.tryAdd(&Ctx{id: id}, 0)
+		}
+		mix(b, func(id string) { r.get(id) },
+			func(c *Ctx) { r.tryAdd(c, 0) },
+			func(id string) { r.remove(id) })
+	})
+	b.Run("single-lock", func(b *testing.B) {
+		r := &singleLockRegistry{m: make(map[string]*Ctx, tabs)}
+		for _, id := range ids {
+			r.add(&Ctx{id: id})
+		}
+		mix(b, func(id string) { r.get(id) }, r.add, r.remove)
+	})
+}
@@ -3,6 +3,7 @@ package via
 import (
 	"encoding/json"
 	"html/template"
+	"io"
 	"maps"
 	"net/http"
 	"reflect"
@@ -14,12 +15,32 @@ import (
 // path params + initial signal values, optionally calls OnInit, renders the
 // view inside the HTML5 envelope.
 func (a *App) renderPage(d *cmpDescriptor, w http.ResponseWriter, r *http.Request) {
+	w, done := a.pageCompress.wrap(w, r)
+	defer done()
 	cmpVal := reflect.New(d.typ)
-	ctx := newCtx(a, d, cmpVal, genTabID(d.route))
-	ctx.session.Store(a.sessionFromRequest(r))
+	if a.cfg.stateSnapshots {
+		w.Header().Add("Vary", "Accept")
+	}
+	snapshot := a.wantsSnapshot(r)
+	shell := !snapshot && a.servesShell(d, r)
+	var ctx *Ctx
+	if shell {
+		ctx = newCtx(a, d, cmpVal, shellTabID(d.route))
+	} else {
+		ctx = a.newPageCtx(d, cmpVal, r)
+	}
+	if !shell {
+		// A shell is the same for every visitor, so its render sees no
+		// session; the tab the SSE handshake mints binds it.
+		ctx.session.Store(a.sessionFromRequest(r))
+		if !snapshot && !a.isCrawler(r) {
+			ctx.takeFlashes()
+		}
+	}
 	ctx.mu.Lock()
 	ctx.w = w
 	ctx.r = r
+	ctx.clientHeader, ctx.clientAddr = r.Header, r.RemoteAddr // kept for Client
 	ctx.mu.Unlock()
 	// Capture the document's CSP nonce now, while the page request is in
 	// hand, so server-pushed scripts drained over the (later, separate) SSE
@@ -39,11 +60,21 @@ func (a *App) renderPage(d *cmpDescriptor, w http.ResponseWriter, r *http.Reques
 	decodePathParams(cmpVal, r, d)
 	decodeQueryParams(cmpVal, r, d)
 
+	// A crawler render never joins the registry: nothing will connect to
+	// the tab, so it lives only as long as this request.
+	// A state snapshot is the same: the tab exists to be read once.
+	ctx.static = a.isCrawler(r) || snapshot
+	if ctx.static || shell {
+		defer a.disposeCtx(ctx, disconnectClient)
+	}
+
 	// Cap check is fused with the registry insert so two concurrent
 	// renders can't both observe live==limit-1 and both proceed. Runs
 	// BEFORE OnInit so an over-capacity (503-bound) request never executes
 	// user init work.
-	if !a.tryRegisterCtx(ctx, a.cfg.maxContexts) {
+	// A shell's tab is likewise never connected to: the SSE handshake
+	// mints the real one.
+	if !ctx.static && !shell && !a.tryRegisterCtx(ctx, a.cfg.maxContexts) {
 		a.logWarn(nil, "max contexts reached (%d); rejecting page render", a.cfg.maxContexts)
 		http.Error(w, "server is at capacity", http.StatusServiceUnavailable)
 		return
@@ -76,12 +107,47 @@ func (a *App) renderPage(d *cmpDescriptor, w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	if snapshot {
+		a.writeStateSnapshot(w, ctx, cmpVal, d)
+		return
+	}
+	if !shell {
+		a.beforePageRender(ctx)
+	}
 	body, ok := a.renderView(ctx, w)
 	if !ok {
 		return
 	}
-	a.writePageDocument(w, ctx, body)
+	if a.cfg.markupChecks {
+		// Render once to scan for duplicate ids and invalid nesting, then
+		// hand the document the rendered bytes so the check doesn't cost a
+		// second render.
+		buf := getRenderBuf()
+		if body != nil && body.Render(buf) == nil {
+			a.checkDuplicates(ctx, buf.String())
+			a.checkNesting(ctx, buf.String())
+			body = h.Raw(buf.String())
+		}
+		putRenderBuf(buf)
+	}
+	if a.cfg.a11yAudit {
+		a.auditPage(ctx, body)
+	}
+	switch {
+	case shell:
+		a.writeShell(w, r, ctx, body)
+	case len(a.hooks.afterRender) > 0:
+		buf := getRenderBuf()
+		a.writePageDocument(io.MultiWriter(w, buf), ctx, body)
+		a.afterPageRender(ctx, buf.String())
+		putRenderBuf(buf)
+	default:
+		a.writePageDocument(w, ctx, body)
+	}
 	a.metricsOrNoop().Counter("via.render.total", "route", d.route)
+	if ctx.static {
+		a.metricsOrNoop().Counter("via.render.crawler", "route", d.route)
+	}
 }
 
 // renderView runs the page's view inside the render window, recovering a
@@ -104,9 +170,12 @@ func (a *App) renderView(ctx *Ctx, w http.ResponseWriter) (body h.H, ok bool) {
 }
 
 // initialSignals assembles the signal seed for a fresh ctx: via_tab,
-// every plugin-registered app signal, and every typed Signal[T] slot's
-// current value. Shared by the page document render and the SSE
-// re-bootstrap path (recoverSSE), which must seed the same set.
+// every plugin-registered app signal, every typed Signal[T] slot's
+// current value, and the signals already pushed to the tab with
+// Patch.Signal — from OnInit or a BeforePageRender hook, so the first
+// paint has them rather than the connect's patch. Shared by the page
+// document render and the SSE re-bootstrap path (recoverSSE), which must
+// seed the same set.
 func (a *App) initialSignals(ctx *Ctx) map[string]any {
 	a.appSignalsMu.RLock()
 	// Size hint: via_tab + every app signal + every typed signal slot.
@@ -116,6 +185,9 @@ func (a *App) initialSignals(ctx *Ctx) map[string]any {
 	sigs[tabSignalKey] = ctx.id
 	maps.Copy(sigs, a.appSignals)
 	a.appSignalsMu.RUnlock()
+	if toks := a.signer.tokens(ctx, a.now()); toks != nil {
+		sigs[sigSignalKey] = toks
+	}
 	for i, s := range ctx.desc.signalSlots {
 		if s.kind != kindSignal {
 			continue
@@ -126,31 +198,25 @@ func (a *App) initialSignals(ctx *Ctx) map[string]any {
 		}
 		sigs[s.wireKey] = json.RawMessage(v)
 	}
+	if q := ctx.queue; q != nil {
+		q.mu.Lock()
+		maps.Copy(sigs, ctx.pushedSignals)
+		q.mu.Unlock()
+	}
 	return sigs
 }
 
-func (a *App) writePageDocument(w http.ResponseWriter, ctx *Ctx, body h.H) {
-	sigsJSON, err := json.Marshal(a.initialSignals(ctx))
-	if err != nil {
-		// A plugin pushed an unmarshalable value via RegisterAppSignal,
-		// or a typed Signal[T]'s init value can't round-trip. Log so
-		// the page render doesn't silently emit empty data-signals.
-		a.logErr(ctx, "writePageDocument: json.Marshal initial signals: %v", err)
-	}
-	head := make([]h.H, 0, 3+len(a.documentHeadIncludes))
-	head = append(head,
-		h.Meta(h.Data("signals", string(sigsJSON))),
-		h.Meta(h.Data("init", "@get('/_sse')")),
-		h.Meta(h.Data("init",
-			`window.addEventListener('beforeunload',(e)=>{navigator.sendBeacon('/_sse/close','`+template.JSEscapeString(ctx.id)+`');});`)),
-	)
-	if !a.cfg.noReconnect {
-		head = append(head, h.Meta(h.Data("init", reconnectInit)))
+func (a *App) writePageDocument(w io.Writer, ctx *Ctx, body h.H) {
+	head := make([]h.H, 0, 4+len(a.documentHeadIncludes))
+	if !ctx.static {
+		head = a.liveBootstrap(ctx, head)
 	}
 	head = append(head, a.documentHeadIncludes...)
 
-	bodyEls := make([]h.H, 0, 1+len(a.documentFootIncludes))
-	bodyEls = append(bodyEls, h.Div(h.ID(ctx.id), body))
+	bodyEls := make([]h.H, 0, 2+len(a.documentFootIncludes))
+	// The portal host is always present, even empty, so a portal that
+	// first opens on a later render has an element to morph into.
+	bodyEls = append(bodyEls, h.Div(ctx.idAttr, body), ctx.portalHost())
 	bodyEls = append(bodyEls, a.documentFootIncludes...)
 
 	doc := h.HTML5(h.HTML5Props{
@@ -160,12 +226,44 @@ func (a *App) writePageDocument(w http.ResponseWriter, ctx *Ctx, body h.H) {
 		Head:        head,
 		Body:        bodyEls,
 		HTMLAttrs:   a.documentHTMLAttrs,
+		BasePath:    a.cfg.basePath,
+		DatastarSrc: a.datastarSrc,
 	})
-	if err := doc.Render(w); err != nil {
+	var opts []h.RenderOption
+	if a.cfg.prettyHTML {
+		opts = append(opts, h.Pretty())
+	}
+	if err := h.Render(w, doc, opts...); err != nil {
 		a.logWarn(ctx, "page render write failed: %v", err)
 	}
 }
 
+// liveBootstrap appends the head elements that bring a page to life: the
+// signal seed, the SSE connect, the close beacon and the reconnect manager.
+func (a *App) liveBootstrap(ctx *Ctx, head []h.H) []h.H {
+	sigsJSON, err := json.Marshal(a.initialSignals(ctx))
+	if err != nil {
+		// A plugin pushed an unmarshalable value via RegisterAppSignal,
+		// or a typed Signal[T]'s init value can't round-trip. Log so
+		// the page render doesn't silently emit empty data-signals.
+		a.logErr(ctx, "writePageDocument: json.Marshal initial signals: %v", err)
+	}
+	base := template.JSEscapeString(a.cfg.basePath)
+	head = append(head, h.Meta(h.Data("signals", string(sigsJSON))))
+	if base != "" {
+		head = append(head, h.Meta(h.Data("init", basePathInit(a.cfg.basePath))))
+	}
+	head = append(head,
+		h.Meta(h.Data("init", "@get('"+base+"/_sse')")),
+		h.Meta(h.Data("init",
+			`window.addEventListener('beforeunload',(e)=>{navigator.sendBeacon('`+base+`/_sse/close','`+template.JSEscapeString(ctx.id)+`');});`)),
+	)
+	if !a.cfg.noReconnect {
+		head = append(head, h.Meta(h.Data("init", reconnectInit)))
+	}
+	return head
+}
+
 // decodeSlots writes raw values from getRaw into every slot's field.
 // Empty raw is skipped so missing query params leave the field at its
 // zero value. Path params come back non-empty when the route matched
@@ -281,7 +379,11 @@ func (a *App) renderFragment(ctx *Ctx) string {
 		}
 	}()
 	body := ctx.viewFn(ctx.readView())
-	if err := h.Div(h.ID(ctx.id), body).Render(buf); err != nil {
+	frame := h.Div(ctx.idAttr, body)
+	if ctx.portalInUse() {
+		frame = h.Fragment(frame, ctx.portalHost())
+	}
+	if err := frame.Render(buf); err != nil {
 		// Consistent with the page-render path (which logs Render errors):
 		// return "" rather than a half-written fragment so the empty-frag
 		// guard in flushDirty preserves the last good frame instead of
@@ -289,5 +391,10 @@ func (a *App) renderFragment(ctx *Ctx) string {
 		a.logErr(ctx, "fragment render: %v", err)
 		return ""
 	}
-	return buf.String()
+	frag := buf.String()
+	if a.cfg.markupChecks {
+		a.checkDuplicates(ctx, frag)
+		a.checkNesting(ctx, frag)
+	}
+	return frag
 }
@@ -211,3 +211,19 @@ func TestView_panicInBroadcastReRenderIsRecoveredNotProcessCrashing(t *testing.T
 	require.Equal(t, http.StatusOK, peer.Action("Bump").Fire())
 	_ = peerFrames
 }
+
+func TestWithPrettyHTML_indentsThePageDocument(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithPrettyHTML())
+	via.Mount[goldenPage](app, "/orders/{id}")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/orders/7")
+
+	assert.Regexp(t, `\n {6,}<p>order 7</p>\n {6,}<p>visit 1</p>`, tc.HTML())
+	assert.NotEmpty(t, tc.TabID(), "the indented page still boots its tab")
+
+	plain := via.New()
+	via.Mount[goldenPage](plain, "/orders/{id}")
+	compact, err := plain.RenderPage("/orders/7", "")
+	require.NoError(t, err)
+	assert.NotContains(t, compact, "\n  <")
+}
@@ -0,0 +1,120 @@
+package via
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// pageRenderKey carries a *pageRender on a RenderPage request's context.
+// Its presence switches the page render's random ids to seed-derived ones.
+type pageRenderKey struct{}
+
+// pageRender is one RenderPage call: the seed in, and the tab the render
+// created out, so RenderPage can dispose it once the document is written.
+type pageRender struct {
+	seed   string
+	static bool // Export: render the crawler document, no SSE bootstrap
+	tab    *Ctx
+}
+
+// RenderPage renders the page at target (a path with optional query, e.g.
+// "/orders/42?tab=items") to a complete HTML document without a server or
+// an SSE stream. The request runs through the app's own middleware, mux
+// and composition lifecycle — OnInit included, OnConnect not — exactly as
+// a browser GET would.
+//
+// Output is deterministic for a given seed: the tab id, the session id and
+// the CSP nonce that a live render draws from crypto/rand are derived from
+// seed instead, so the same app state yields byte-identical HTML. That
+// makes the result fit for golden-file tests:
+//
+//	got, err := app.RenderPage("/pricing", "golden")
+//	require.NoError(t, err)
+//	golden.Assert(t, got, "pricing.html")
+//
+// The seed also names the session: renders sharing a seed share StateSess
+// values, like tabs in one browser. Never use a seed for live traffic — it
+// makes the session id predictable. The tab is disposed before RenderPage
+// returns; concurrent renders must use distinct seeds. A non-200 response
+// (a middleware redirect, a 404, a panicking View) is returned as an error.
+func (a *App) RenderPage(target, seed string) (string, error) {
+	return a.renderTarget(target, seed, false)
+}
+
+// renderTarget is RenderPage, or with static the document Export writes.
+func (a *App) renderTarget(target, seed string, static bool) (string, error) {
+	pr := &pageRender{seed: seed, static: static}
+	ctx := context.WithValue(context.Background(), pageRenderKey{}, pr)
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", fmt.Errorf("via: RenderPage %q: %w", target, err)
+	}
+	r.RemoteAddr = "127.0.0.1:0"
+	a.plantSessionCookie(r, seededID(seed, "session"))
+	r = RequestWithCSPNonce(r, seededNonce(seed))
+
+	rec := &pageRecorder{header: http.Header{}}
+	a.ServeHTTP(rec, r)
+
+	// A static render's tab was never registered and renderPage has
+	// already disposed it.
+	if pr.tab != nil && !pr.tab.static {
+		a.unregisterCtx(pr.tab.id)
+		a.disposeCtx(pr.tab, disconnectClient)
+	}
+	if rec.status != http.StatusOK {
+		return "", fmt.Errorf("via: RenderPage %q: status %d: %s", target, rec.status, bytes.TrimSpace(rec.body.Bytes()))
+	}
+	return rec.body.String(), nil
+}
+
+// newPageCtx allocates the tab for a page render: seed-derived ids under
+// RenderPage (which is told the tab so it can dispose it), random otherwise.
+func (a *App) newPageCtx(d *cmpDescriptor, cmpVal reflect.Value, r *http.Request) *Ctx {
+	pr, ok := r.Context().Value(pageRenderKey{}).(*pageRender)
+	if !ok {
+		return newCtx(a, d, cmpVal, genTabID(d.route))
+	}
+	ctx := newCtx(a, d, cmpVal, d.route+"_"+seededID(pr.seed, "tab"))
+	pr.tab = ctx
+	return ctx
+}
+
+// seededID derives a genSecureID-shaped id (64 hex chars) for one use of
+// seed, so ids for different purposes never collide.
+func seededID(seed, purpose string) string {
+	sum := sha256.Sum256([]byte("via:" + purpose + "\x00" + seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// seededNonce is the genCSPNonce-shaped counterpart of seededID.
+func seededNonce(seed string) string {
+	sum := sha256.Sum256([]byte("via:nonce\x00" + seed))
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
+
+// pageRecorder is the in-memory ResponseWriter behind RenderPage.
+type pageRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (p *pageRecorder) Header() http.Header { return p.header }
+
+func (p *pageRecorder) WriteHeader(status int) {
+	if p.status == 0 {
+		p.status = status
+	}
+}
+
+func (p *pageRecorder) Write(b []byte) (int, error) {
+	p.WriteHeader(http.StatusOK)
+	return p.body.Write(b)
+}
@@ -0,0 +1,83 @@
+package via_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type goldenPage struct {
+	ID     int                `path:"id"`
+	Visits via.StateSess[int] `via:"visits"`
+	Name   via.SignalStr      `via:"name,init=ada"`
+}
+
+func (p *goldenPage) OnInit(ctx *via.Ctx) error {
+	return p.Visits.Update(ctx, func(n int) (int, error) { return n + 1, nil })
+}
+
+func (p *goldenPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.Text("order "+strconv.Itoa(p.ID))),
+		h.P(h.Text("visit "+strconv.Itoa(p.Visits.Read(ctx)))),
+		h.P(h.Text("nonce "+ctx.CSPNonce())),
+	)
+}
+
+func TestRenderPage_isDeterministicPerSeed(t *testing.T) {
+	t.Parallel()
+
+	render := func(seed string) string {
+		app := via.New()
+		via.Mount[goldenPage](app, "/orders/{id}")
+		got, err := app.RenderPage("/orders/42", seed)
+		require.NoError(t, err)
+		return got
+	}
+
+	first := render("golden")
+	assert.Equal(t, first, render("golden"), "same seed, fresh app: byte-identical document")
+	assert.NotEqual(t, first, render("other"), "the seed drives the ids")
+	assert.Contains(t, first, "order 42", "path params decode as on a live GET")
+	assert.Contains(t, first, `&#34;name&#34;:&#34;ada&#34;`, "initial signals are seeded")
+}
+
+func TestRenderPage_sharesSessionAcrossRendersWithOneSeed(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[goldenPage](app, "/orders/{id}")
+
+	_, err := app.RenderPage("/orders/1", "s")
+	require.NoError(t, err)
+	got, err := app.RenderPage("/orders/1", "s")
+	require.NoError(t, err)
+	assert.Contains(t, got, "visit 2")
+	assert.Zero(t, app.LiveTabs(), "each render disposes its tab")
+}
+
+func TestRenderPage_returnsNonOKAsError(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[goldenPage](app, "/orders/{id}")
+	app.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		if r.URL.Query().Has("deny") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+
+	_, err := app.RenderPage("/orders/1?deny", "s")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403: forbidden")
+
+	_, err = app.RenderPage("/missing", "s")
+	assert.ErrorContains(t, err, "status 404")
+}
@@ -0,0 +1,172 @@
+package via
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// defaultReplayBytes is the per-tab replay buffer when WithSSEReplayBytes
+// isn't set: room for a handful of typical frames, not a whole session —
+// it is held for every open tab.
+const defaultReplayBytes = 16 << 10
+
+// WithSSEReplayBytes sizes the buffer each tab keeps of the events it was
+// last sent. Every patch event carries an id; when a dropped stream comes
+// back, Datastar reports the last id it received (Last-Event-ID) and via
+// replays just the events after it — appends, signal pushes and
+// redirects included — instead of re-rendering the whole view. Scripts
+// (ExecScript, Eval) are never replayed: one missed in the gap is dropped
+// rather than run late. A reconnect the buffer can't cover (the gap
+// outgrew it, or the client sent no id) falls back to that full resync.
+// The buffer is held for every open tab, so size it with the tab count in
+// mind. Default 16 KiB; 0 turns replay off, so every reconnect resyncs.
+// Panics at New on a negative value.
+func WithSSEReplayBytes(n int) Option { return func(c *config) { c.sseReplayBytes = n } }
+
+type replayKind uint8
+
+const (
+	replayElements replayKind = iota
+	replaySignals
+	replayScript
+	replayRedirect
+)
+
+// replayEvent is one patch event as it went out on the wire, minus its
+// framing, so it can be re-sent verbatim.
+type replayEvent struct {
+	id       uint64
+	kind     replayKind
+	data     string
+	selector string
+	mode     datastar.ElementPatchMode
+	// transition wraps the patch in document.startViewTransition.
+	transition bool
+}
+
+// replayRing numbers a tab's events and keeps the newest of them, up to
+// max bytes of payload, for [App.replayMissed].
+type replayRing struct {
+	mu     sync.Mutex
+	max    int
+	size   int
+	seq    uint64 // last id handed out
+	floor  uint64 // events with ids <= floor can't be replayed
+	events []replayEvent
+}
+
+// emit writes ev under the next event id and, once the write landed,
+// keeps it for replay. A failed write burns its id: the client never saw
+// it, and whatever it carried is still queued for the next drain.
+func (r *replayRing) emit(sse *datastar.ServerSentEventGenerator, ctx *Ctx, w http.ResponseWriter, writeTimeout time.Duration, ev replayEvent) error {
+	r.mu.Lock()
+	r.seq++
+	ev.id = r.seq
+	r.mu.Unlock()
+	setSSEWriteDeadline(w, writeTimeout)
+	if err := sendEvent(sse, ctx, ev); err != nil {
+		return err
+	}
+	r.keep(ev)
+	return nil
+}
+
+func (r *replayRing) keep(ev replayEvent) {
+	if ev.kind == replayScript {
+		return // a script re-run after the gap could act on a page that moved on
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(ev.data) + len(ev.selector)
+	if n > r.max {
+		// Too big to keep at all (or replay is off): nothing before it
+		// can be replayed either, since the client would miss it.
+		r.events, r.size, r.floor = r.events[:0], 0, ev.id
+		return
+	}
+	r.events = append(r.events, ev)
+	r.size += n
+	for r.size > r.max {
+		old := r.events[0]
+		r.events[0] = replayEvent{}
+		r.events = r.events[1:]
+		r.size -= len(old.data) + len(old.selector)
+		r.floor = old.id
+	}
+}
+
+// reset forgets everything sent so far; the next reconnect that names an
+// earlier id resyncs.
+func (r *replayRing) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.events)
+	r.events, r.size, r.floor = r.events[:0], 0, r.seq
+}
+
+// since returns the kept events after id last, or false when some of
+// them are gone (or last is an id this tab never sent).
+func (r *replayRing) since(last uint64) ([]replayEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.max == 0 || last < r.floor || last > r.seq {
+		return nil, false
+	}
+	var out []replayEvent
+	for _, ev := range r.events {
+		if ev.id > last {
+			out = append(out, ev)
+		}
+	}
+	return out, true
+}
+
+func sendEvent(sse *datastar.ServerSentEventGenerator, ctx *Ctx, ev replayEvent) error {
+	id := strconv.FormatUint(ev.id, 10)
+	switch ev.kind {
+	case replaySignals:
+		return sse.PatchSignals([]byte(ev.data), datastar.WithPatchSignalsEventID(id))
+	case replayScript, replayRedirect:
+		opts := append(ctx.scriptNonceOpts(), datastar.WithExecuteScriptEventID(id))
+		if ev.kind == replayRedirect {
+			return sse.Redirect(ev.data, opts...)
+		}
+		return sse.ExecuteScript(ev.data, opts...)
+	default:
+		opts := []datastar.PatchElementOption{datastar.WithPatchElementsEventID(id)}
+		if ev.selector != "" {
+			opts = append(opts, datastar.WithSelector(ev.selector), datastar.WithMode(ev.mode))
+		}
+		if ev.transition {
+			opts = append(opts, datastar.WithViewTransitions())
+		}
+		return sse.PatchElements(ev.data, opts...)
+	}
+}
+
+// replayMissed re-sends, on a reconnecting stream, the events the client
+// missed since the Last-Event-ID it reports. It returns false — having
+// written nothing — when the buffer can't cover the gap, leaving the
+// caller to resync. A write error ends the stream; the next reconnect's
+// Last-Event-ID picks up from what did land.
+func (a *App) replayMissed(sse *datastar.ServerSentEventGenerator, ctx *Ctx, w http.ResponseWriter, r *http.Request) (bool, error) {
+	last, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	missed, ok := ctx.replay.since(last)
+	if !ok {
+		return false, nil
+	}
+	for _, ev := range missed {
+		setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
+		if err := sendEvent(sse, ctx, ev); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
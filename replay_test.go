@@ -0,0 +1,158 @@
+package via_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resumeSSE reconnects tabID's stream the way Datastar's retry does,
+// reporting the last event id it received.
+func resumeSSE(t *testing.T, httpc *http.Client, serverURL, tabID, lastEventID string) <-chan string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		serverURL+"/_sse?datastar="+url.QueryEscape(`{"via_tab":"`+tabID+`"}`), nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", lastEventID)
+	resp, err := httpc.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	out := make(chan string, 16)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		buf := make([]byte, 4096)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				out <- string(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+var eventIDRE = regexp.MustCompile(`(?m)^id: (\d+)$`)
+
+// firstEventID returns the id of the first event in an SSE body.
+func firstEventID(t *testing.T, body string) string {
+	t.Helper()
+	m := eventIDRE.FindStringSubmatch(body)
+	require.NotNil(t, m, "event carries no id: %q", body)
+	return m[1]
+}
+
+func TestSSE_reconnectReplaysOnlyTheMissedEvents(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	app := via.New(via.WithMetrics(m))
+	server := vt.Serve(t, app)
+	via.Mount[resyncPushPage](app, "/rp")
+
+	httpc := jarClient(t)
+	tabID := openPage(t, httpc, server.URL, "/rp")
+	_, frames, cancel := openRawSSE(t, httpc, server.URL, tabID, "")
+	vt.AwaitFrame(t, frames, 2*time.Second, ": ready")
+	fireAction(t, httpc, server.URL, tabID, "PushList")
+	seen := firstEventID(t, vt.AwaitFrame(t, frames, 2*time.Second, `id="results"`))
+	// The notice goes out, but the connection drops before it arrives.
+	fireAction(t, httpc, server.URL, tabID, "PushNotice")
+	vt.AwaitFrame(t, frames, 2*time.Second, `"_notice":"maintenance"`)
+	cancel()
+
+	body := vt.AwaitFrame(t, resumeSSE(t, httpc, server.URL, tabID, seen), 2*time.Second,
+		`"_notice":"maintenance"`, ": ready")
+	assert.NotContains(t, body, `id="results"`, "an event the client acknowledged is not resent")
+	assert.NotContains(t, body, "hello", "a covered reconnect skips the view re-render")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.sse.replay:")
+	assert.NotContains(t, m.counters, "via.sse.resync:")
+}
+
+func TestSSE_reconnectDoesNotReplayScripts(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[resyncPushPage](app, "/rs")
+
+	httpc := jarClient(t)
+	tabID := openPage(t, httpc, server.URL, "/rs")
+	_, frames, cancel := openRawSSE(t, httpc, server.URL, tabID, "")
+	vt.AwaitFrame(t, frames, 2*time.Second, ": ready")
+	fireAction(t, httpc, server.URL, tabID, "PushNotice")
+	seen := firstEventID(t, vt.AwaitFrame(t, frames, 2*time.Second, `"_notice":"maintenance"`))
+	fireAction(t, httpc, server.URL, tabID, "PushAll")
+	vt.AwaitFrame(t, frames, 2*time.Second, "queued-script")
+	cancel()
+
+	body := vt.AwaitFrame(t, resumeSSE(t, httpc, server.URL, tabID, seen), 2*time.Second,
+		`id="results"`, ": ready")
+	assert.NotContains(t, body, "queued-script", "a script missed in the gap must not run late")
+	assert.NotContains(t, body, "hello", "skipping a script still replays, not resyncs")
+}
+
+func TestSSE_reconnectResyncsWhenTheGapOutgrewTheBuffer(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	app := via.New(via.WithMetrics(m), via.WithSSEReplayBytes(16))
+	server := vt.Serve(t, app)
+	via.Mount[resyncPushPage](app, "/rb")
+
+	httpc := jarClient(t)
+	tabID := openPage(t, httpc, server.URL, "/rb")
+	_, frames, cancel := openRawSSE(t, httpc, server.URL, tabID, "")
+	vt.AwaitFrame(t, frames, 2*time.Second, ": ready")
+	fireAction(t, httpc, server.URL, tabID, "PushNotice")
+	seen := firstEventID(t, vt.AwaitFrame(t, frames, 2*time.Second, `"_notice":"maintenance"`))
+	// Bigger than the whole buffer: once sent, it can't be replayed.
+	fireAction(t, httpc, server.URL, tabID, "PushList")
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="results"`)
+	cancel()
+
+	vt.AwaitFrame(t, resumeSSE(t, httpc, server.URL, tabID, seen), 2*time.Second,
+		`"_notice":"maintenance"`, "hello", ": ready")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.sse.resync:")
+	assert.NotContains(t, m.counters, "via.sse.replay:")
+}
+
+func TestSSE_reconnectResyncsOnAnIDTheTabNeverSent(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[resyncPushPage](app, "/ri")
+
+	httpc := jarClient(t)
+	tabID := openPage(t, httpc, server.URL, "/ri")
+	_, frames, cancel := openRawSSE(t, httpc, server.URL, tabID, "")
+	vt.AwaitFrame(t, frames, 2*time.Second, ": ready")
+	cancel()
+
+	// An id from another tab's stream (a re-bootstrapped tab keeps the
+	// header) must not be mistaken for a covered gap.
+	vt.AwaitFrame(t, resumeSSE(t, httpc, server.URL, tabID, "9000"), 2*time.Second, "hello")
+}
+
+func TestWithSSEReplayBytes_rejectsNegative(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, "via.WithSSEReplayBytes: must be >= 0, got -1", func() {
+		via.New(via.WithSSEReplayBytes(-1))
+	})
+}
@@ -0,0 +1,53 @@
+package via
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// RequireAuth is group middleware that admits only requests whose session
+// is signed in ([Ctx.SignIn]). It guards everything the group mounts — the
+// page render before OnInit runs, every action POST, and the SSE
+// handshake — so a tab whose session signs out or is revoked stops
+// working with it:
+//
+//	admin := app.Group("/admin")
+//	admin.Use(via.RequireAuth("/login"))
+//	via.Mount[Dashboard](admin, "/")
+//
+// A signed-out page load is redirected to loginPath; a signed-out action
+// or stream gets a client-side navigation there instead, since the
+// browser isn't loading a document. With loginPath "" they get a 401.
+// To guard a single page, mount it in its own Group("").
+func RequireAuth(loginPath string) Middleware {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		a, _ := r.Context().Value(appKey{}).(*App)
+		if a == nil {
+			// Not served by a via App: nothing to check against, so refuse.
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if s := a.sessionFromRequest(r); s != nil && s.auth.Load() != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		kind := a.Endpoint(r)
+		a.metricsOrNoop().Counter("via.auth.denied", "endpoint", string(kind))
+		target := loginPath
+		if strings.HasPrefix(target, "/") {
+			target = a.BasePath() + target
+		}
+		switch {
+		case loginPath == "":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case kind == EndpointPage:
+			http.Redirect(w, r, target, http.StatusSeeOther)
+		default:
+			sse := datastar.NewSSE(w, r, a.cfg.compression.sseOptions()...)
+			setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
+			_ = sse.Redirect(target, requestNonceOpts(r)...)
+		}
+	}
+}
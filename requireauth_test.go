@@ -0,0 +1,113 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type authGatePage struct{}
+
+func (p *authGatePage) In(ctx *via.Ctx)  { ctx.SignIn("alice") }
+func (p *authGatePage) Out(ctx *via.Ctx) { ctx.SignOut() }
+func (p *authGatePage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.Text("gate"))
+}
+
+type adminPage struct{}
+
+func (p *adminPage) Ping(ctx *via.Ctx) {}
+func (p *adminPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.Text("admin for " + ctx.User()))
+}
+
+// guardedServer mounts a public sign-in page at /gate and adminPage at
+// /admin/ behind RequireAuth(loginPath).
+func guardedServer(t *testing.T, loginPath string, opts ...via.Option) string {
+	t.Helper()
+	app := via.New(opts...)
+	server := vt.Serve(t, app)
+	via.Mount[authGatePage](app, "/gate")
+	admin := app.Group("/admin")
+	admin.Use(via.RequireAuth(loginPath))
+	via.Mount[adminPage](admin, "/")
+	return server.URL
+}
+
+func noFollow(c *http.Client) *http.Client {
+	c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return c
+}
+
+func TestRequireAuth_redirectsSignedOutPageLoads(t *testing.T) {
+	t.Parallel()
+	url := guardedServer(t, "/login")
+	httpc := noFollow(jarClient(t))
+
+	resp, err := httpc.Get(url + "/admin/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/login", resp.Header.Get("Location"))
+}
+
+func TestRequireAuth_admitsASignedInSession(t *testing.T) {
+	t.Parallel()
+	url := guardedServer(t, "/login")
+	httpc := noFollow(jarClient(t))
+
+	fireAction(t, httpc, url, openPage(t, httpc, url, "/gate"), "In")
+	tab := openPage(t, httpc, url, "/admin/")
+	fireAction(t, httpc, url, tab, "Ping")
+}
+
+func TestRequireAuth_sendsASignedOutTabToLogin(t *testing.T) {
+	t.Parallel()
+	url := guardedServer(t, "/login")
+	httpc := noFollow(jarClient(t))
+	gate := openPage(t, httpc, url, "/gate")
+	fireAction(t, httpc, url, gate, "In")
+	tab := openPage(t, httpc, url, "/admin/")
+
+	fireAction(t, httpc, url, gate, "Out")
+
+	resp, err := httpc.Post(url+"/_action/Ping", "application/json", strings.NewReader(`{"via_tab":"`+tab+`"}`))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "/login", "the action is answered with a navigation to the login page")
+
+	status, frames, cancel := openRawSSE(t, httpc, url, tab, "")
+	defer cancel()
+	require.Equal(t, http.StatusOK, status)
+	vt.AwaitFrame(t, frames, 2*time.Second, "/login")
+}
+
+func TestRequireAuth_answers401WithoutALoginPage(t *testing.T) {
+	t.Parallel()
+	url := guardedServer(t, "")
+
+	resp, err := http.Get(url + "/admin/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequireAuth_prefixesTheBasePath(t *testing.T) {
+	t.Parallel()
+	url := guardedServer(t, "/login", via.WithBasePath("/app"))
+
+	resp, err := noFollow(jarClient(t)).Get(url + "/app/admin/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/app/login", resp.Header.Get("Location"))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-via/via/h"
+	"github.com/starfederation/datastar-go/datastar"
 )
 
 // tabSignalKey is the wire-protocol signal name carrying a Ctx's tab id.
@@ -55,10 +56,22 @@ type patchQueue struct {
 	// call order. Drained AFTER autoElements so an explicit patch
 	// targeting an id the auto render also ships stays authoritative.
 	elements string
-	signals  map[string]any
-	scripts  strings.Builder
+	// moded holds Patch.AppendTo/PrependTo/ReplaceChildren/RemoveElement
+	// pushes in call order, drained after the morphs. Unlike a morph they
+	// are not idempotent, so each is dropped as soon as its write lands
+	// rather than redelivered with the rest of a failed frame.
+	moded   []modedPatch
+	signals map[string]any
+	scripts strings.Builder
+	// evals holds Ctx.Eval requests. Unlike scripts they ship even while
+	// an action holds wakes: the action is usually the one waiting on
+	// the reply.
+	evals    strings.Builder
 	redirect string
 	wake     chan struct{}
+	// transition applies the next frame's element patches inside a view
+	// transition (Ctx.ViewTransition). Cleared once such a frame lands.
+	transition bool
 	// hold defers wakes while an action handler runs so all of the
 	// action's patches — the auto re-render and any explicit Patch pushes
 	// — drain in a SINGLE frame at action end. Without it a mid-action
@@ -72,6 +85,14 @@ type patchQueue struct {
 	pending bool
 }
 
+// modedPatch is one element patch applied with a non-morph datastar mode
+// against the element selected by id.
+type modedPatch struct {
+	mode datastar.ElementPatchMode
+	id   string
+	html string
+}
+
 func newPatchQueue() *patchQueue {
 	return &patchQueue{wake: make(chan struct{}, 1)}
 }
@@ -133,6 +154,7 @@ func (q *patchQueue) releaseNotify() {
 func newCtx(a *App, d *cmpDescriptor, cmpVal reflect.Value, id string) *Ctx {
 	ctx := &Ctx{
 		id:           id,
+		idAttr:       h.ID(id),
 		desc:         d,
 		signalRefs:   make([]signalRef, len(d.signalSlots)),
 		dirtySignals: newBitset(len(d.signalSlots)),
@@ -140,6 +162,9 @@ func newCtx(a *App, d *cmpDescriptor, cmpVal reflect.Value, id string) *Ctx {
 		doneChan:     make(chan struct{}),
 	}
 	ctx.app = a
+	if a != nil {
+		ctx.replay.max = a.cfg.sseReplayBytes
+	}
 	ctx.ctxR = &CtxR{ctx: ctx}
 	ctx.patch = &Patch{ctx: ctx}
 	ctx.touch()
@@ -147,6 +172,7 @@ func newCtx(a *App, d *cmpDescriptor, cmpVal reflect.Value, id string) *Ctx {
 	bindSlots(ctx, cmpVal, d)
 	bindScopeKeys(cmpVal, d, a)
 	bindFileKeys(cmpVal, d)
+	bindFormKeys(cmpVal, d)
 	bindDispatchFns(ctx, cmpVal, d)
 	return ctx
 }
@@ -156,7 +182,14 @@ func newCtx(a *App, d *cmpDescriptor, cmpVal reflect.Value, id string) *Ctx {
 // per-request hot path. Called once per newCtx; the resulting funcs
 // dispatch directly.
 func bindDispatchFns(ctx *Ctx, cmpVal reflect.Value, d *cmpDescriptor) {
-	ctx.viewFn = cmpVal.Method(d.viewIdx).Interface().(func(*CtxR) h.H)
+	// View runs on every render: bind it through the interface so the call
+	// is a plain method value, not reflect's method-value trampoline, which
+	// allocates per call.
+	if c, ok := cmpVal.Interface().(Composition); ok {
+		ctx.viewFn = c.View
+	} else {
+		ctx.viewFn = cmpVal.Method(d.viewIdx).Interface().(func(*CtxR) h.H)
+	}
 	if d.initIdx >= 0 {
 		ctx.initFn = cmpVal.Method(d.initIdx).Interface().(func(*Ctx) error)
 	}
@@ -187,6 +220,11 @@ func bindDispatchFns(ctx *Ctx, cmpVal reflect.Value, d *cmpDescriptor) {
 // d.signalSlots only once per Ctx setup.
 func bindSlots(ctx *Ctx, cmpVal reflect.Value, d *cmpDescriptor) {
 	elem := cmpVal.Elem()
+	// Allocate every child up front, not just those a handle below reaches,
+	// so a stateless child still has an instance address for ScopedID.
+	for _, c := range d.childSlots {
+		fieldByPath(elem, c.fieldPath)
+	}
 	for i, s := range d.signalSlots {
 		field := fieldByPath(elem, s.fieldPath)
 		ref := field.Addr().Interface().(signalRef)
@@ -287,37 +325,30 @@ func enqueueScript(ctx *Ctx, s string) {
 // on every tick, exiting when stopSweep closes. Used by both the session
 // and context expirers — the only thing that varies is the cadence and
 // the per-tick action. interval ≤ 0 falls back to the supplied default.
-func (a *App) runSweep(interval, fallback time.Duration, sweep func()) {
+// The TTL sweeps tick on the app's Clock so a fake clock drives expiry.
+func (a *App) runSweep(clock Clock, interval, fallback time.Duration, sweep func()) {
 	defer a.bgWG.Done()
 	if interval <= 0 {
 		interval = fallback
 	}
-	ticker := time.NewTicker(interval)
+	ticker := clock.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-a.stopSweep:
 			return
-		case <-ticker.C:
+		case <-ticker.Chan():
 			sweep()
 		}
 	}
 }
 
 func (a *App) removeExpiredContexts() {
-	cutoff := time.Now().Add(-a.cfg.contextTTL).UnixNano()
-	a.contextRegistryMu.Lock()
-	var expired []*Ctx
-	for id, c := range a.contextRegistry {
-		if c.connected.Load() > 0 {
-			continue // a live SSE stream keeps the tab alive regardless of lastAccess
-		}
-		if c.lastAccess.Load() < cutoff {
-			expired = append(expired, c)
-			delete(a.contextRegistry, id)
-		}
-	}
-	a.contextRegistryMu.Unlock()
+	cutoff := a.now().Add(-a.cfg.contextTTL).UnixNano()
+	expired := a.contexts.removeIf(func(c *Ctx) bool {
+		// A live SSE stream keeps the tab alive regardless of lastAccess.
+		return c.connected.Load() == 0 && c.lastAccess.Load() < cutoff
+	})
 	for _, c := range expired {
 		a.disposeCtx(c, disconnectTTL)
 	}
@@ -348,24 +379,54 @@ func (a *App) signalDispose(ctx *Ctx, reason string) bool {
 }
 
 // disposeCtx closes the ctx (idempotent with signalDispose) and runs
-// OnDispose if defined. Serialized against in-flight actions via
-// actionMu so OnDispose sees a composition that isn't being mutated by
-// a concurrent handler. reason is threaded to signalDispose to label
-// the via.sse.disconnect counter on the woken SSE loop.
+// the root's OnDispose if defined. Pending saves of persisted StateTabs
+// run last. Serialized against in-flight actions via actionMu so
+// OnDispose sees a composition that isn't being mutated by a concurrent
+// handler. reason is threaded to signalDispose to label the
+// via.sse.disconnect counter on the woken SSE loop.
 func (a *App) disposeCtx(ctx *Ctx, reason string) {
 	a.signalDispose(ctx, reason)
 
 	ctx.actionMu.Lock()
 	defer ctx.actionMu.Unlock()
 
-	if ctx.disposeFn == nil {
-		return
-	}
-	defer recoverLog(ctx, "OnDispose")
 	// disposeFn may itself observe ctx.disposed; the flag was set in
 	// signalDispose before actionMu was taken, so OnDispose sees a
 	// consistent "yes, disposed" view.
-	ctx.disposeFn(ctx)
+	if ctx.disposeFn != nil {
+		runDispose(ctx, ctx.disposeFn)
+	}
+	// After the hooks, so a last write from OnDispose is saved too.
+	ctx.flushPersisted()
+}
+
+// Dispose runs child's OnDispose now, for a child composition the parent
+// stops rendering — a tab switched away from, a closed panel — so what it
+// holds is released with it. The runtime only disposes the root on its
+// own, so a child still shown when the tab goes away is disposed from the
+// root's OnDispose:
+//
+//	func (p *Page) Show(ctx *via.Ctx) { ctx.Dispose(p.Chart); p.Tab = "table" }
+//
+//	func (p *Page) OnDispose(ctx *via.Ctx) {
+//	    if p.Tab == "chart" {
+//	        ctx.Dispose(p.Chart)
+//	    }
+//	}
+//
+// A panic in the hook is logged, not propagated. Call it from an action
+// or lifecycle hook, which already hold the tab's action lock.
+func (ctx *Ctx) Dispose(child Disposer) {
+	if ctx == nil || child == nil {
+		return
+	}
+	runDispose(ctx, child.OnDispose)
+}
+
+// runDispose calls one OnDispose hook, logging and swallowing a panic.
+func runDispose(ctx *Ctx, fn func(*Ctx)) {
+	defer recoverLog(ctx, "OnDispose")
+	fn(ctx)
 }
 
 // recoverLog is a deferred-recover helper that logs the panic value via
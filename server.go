@@ -46,7 +46,7 @@ func (a *App) Run() error {
 	a.serverMu.Lock()
 	a.server = srv
 	a.serverMu.Unlock()
-	a.logInfo(nil, "via started at [%s]", a.cfg.addr)
+	a.logStartup()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -85,6 +85,7 @@ func (a *App) Start() {
 //  4. Per-Ctx OnDispose runs, serialized against any in-flight action
 //     via the per-Ctx action mutex.
 //
+// With WithAppStateStore, a last snapshot of app state is saved next.
 // Sessions and the TTL sweeper are torn down last. The error from the
 // http.Server's Shutdown is returned; a wedged OnDispose handler does
 // not propagate but is logged.
@@ -93,13 +94,7 @@ func (a *App) Shutdown(ctx context.Context) error {
 	// drains traffic away before we start tearing anything down.
 	a.draining.Store(true)
 
-	a.contextRegistryMu.Lock()
-	ctxs := make([]*Ctx, 0, len(a.contextRegistry))
-	for _, c := range a.contextRegistry {
-		ctxs = append(ctxs, c)
-	}
-	clear(a.contextRegistry)
-	a.contextRegistryMu.Unlock()
+	ctxs := a.contexts.removeIf(func(*Ctx) bool { return true })
 
 	// Step 1: wake every long-lived loop on this Ctx (SSE drain,
 	// Stream goroutines, user code watching Done) so they exit before
@@ -130,6 +125,12 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	})
 
+	// The last app-state snapshot, after OnDispose so its writes are in
+	// it, and before the backplane closes underneath it.
+	if a.cfg.appState != nil {
+		a.saveAppState(ctx)
+	}
+
 	a.sessionsMu.Lock()
 	clear(a.sessions)
 	a.sessionsMu.Unlock()
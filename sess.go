@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/go-via/via/internal/sessbridge"
 )
@@ -32,6 +31,8 @@ type session struct {
 	// idempotent and non-regressing. Lazily initialized under revsMu.
 	revs   map[string]Rev
 	revsMu sync.Mutex
+
+	auth atomic.Pointer[signIn] // set by Ctx.SignIn; nil while signed out
 }
 
 // loadRev returns the highest revision applied for key on this session (0 if none).
@@ -129,13 +130,14 @@ func (s *Session) Rotate() string {
 	old := s.data
 
 	fresh := &session{id: genSecureID()}
-	fresh.lastAccess.Store(time.Now().UnixNano())
+	fresh.lastAccess.Store(app.now().UnixNano())
 
 	if old != nil {
 		old.data.Range(func(k, v any) bool {
 			fresh.data.Store(k.(string), v)
 			return true
 		})
+		fresh.auth.Store(old.auth.Load())
 	}
 
 	app.sessionsMu.Lock()
@@ -144,6 +146,10 @@ func (s *Session) Rotate() string {
 		delete(app.sessions, old.id)
 	}
 	app.sessionsMu.Unlock()
+	if old != nil {
+		app.sessionEnded(old.id)
+	}
+	app.sessionStarted(fresh.id)
 
 	s.ctx.session.Store(fresh)
 	s.data = fresh
@@ -192,13 +198,21 @@ func (a *App) adoptSession(sid string) *session {
 // needed. Returns nil ONLY when WithMaxSessions is set and the cap is met for a
 // new session — a client that already holds a live session is never refused.
 func (a *App) getOrCreateSession(w http.ResponseWriter, r *http.Request) *session {
-	now := time.Now().UnixNano()
+	now := a.now().UnixNano()
 	if c, err := r.Cookie(a.cookieName()); err == nil {
+		id, ok, stale := a.decodeSessionCookie(c.Value)
+		if !ok {
+			a.metricsOrNoop().Counter("via.session.rejected")
+		}
 		a.sessionsMu.RLock()
-		sess, ok := a.sessions[c.Value]
+		sess, found := a.sessions[id]
 		a.sessionsMu.RUnlock()
-		if ok {
+		if ok && found {
 			sess.lastAccess.Store(now)
+			if stale {
+				// Verified under a retired key: move it to the current one.
+				http.SetCookie(w, a.sessionCookie(id))
+			}
 			return sess
 		}
 		// Cross-pod adoption: a well-formed sid this pod never issued is a
@@ -206,13 +220,16 @@ func (a *App) getOrCreateSession(w http.ResponseWriter, r *http.Request) *sessio
 		// 256-bit sid is the bearer credential). Adopt it under the SAME id so
 		// state keyed by that sid converges here — no sticky sessions needed.
 		// A malformed value is never adopted; it falls through to a fresh mint.
-		if validSessionID(c.Value) {
-			sess := a.adoptSession(c.Value)
+		if ok && validSessionID(id) {
+			sess := a.adoptSession(id)
 			if sess == nil {
 				return nil // at capacity
 			}
 			sess.lastAccess.Store(now)
-			r.AddCookie(&http.Cookie{Name: a.cookieName(), Value: sess.id})
+			if stale {
+				http.SetCookie(w, a.sessionCookie(id))
+			}
+			a.plantSessionCookie(r, id)
 			return sess
 		}
 	}
@@ -228,15 +245,30 @@ func (a *App) getOrCreateSession(w http.ResponseWriter, r *http.Request) *sessio
 	a.sessions[sess.id] = sess
 	a.sessionsMu.Unlock()
 
+	a.sessionStarted(sess.id)
 	http.SetCookie(w, a.sessionCookie(sess.id))
 	// Plant the cookie on the request too so sessionFromRequest in
 	// downstream handlers (renderPage/handleAction/handleSSE) can find
 	// the session it just created without waiting for the next round-trip.
-	r.AddCookie(&http.Cookie{Name: a.cookieName(), Value: sess.id})
+	a.plantSessionCookie(r, sess.id)
 
 	return sess
 }
 
+// plantSessionCookie makes r carry the session cookie for id in place of
+// whatever session cookie it arrived with — r.Cookie returns the first
+// match, so appending alone would leave a rejected one in front.
+func (a *App) plantSessionCookie(r *http.Request, id string) {
+	others := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, c := range others {
+		if c.Name != a.cookieName() {
+			r.AddCookie(c)
+		}
+	}
+	r.AddCookie(&http.Cookie{Name: a.cookieName(), Value: a.encodeSessionCookie(id)})
+}
+
 type appKey struct{}
 
 // sessionCookie returns the canonical via_session cookie for id with
@@ -265,7 +297,7 @@ func (a *App) cookieName() string {
 func (a *App) sessionCookie(id string) *http.Cookie {
 	return &http.Cookie{
 		Name:     a.cookieName(),
-		Value:    id,
+		Value:    a.encodeSessionCookie(id),
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   a.cfg.secureCookies,
@@ -282,9 +314,13 @@ func (a *App) sessionFromRequest(r *http.Request) *session {
 	if err != nil {
 		return nil
 	}
+	id, ok, _ := a.decodeSessionCookie(c.Value)
+	if !ok {
+		return nil
+	}
 	a.sessionsMu.RLock()
 	defer a.sessionsMu.RUnlock()
-	return a.sessions[c.Value]
+	return a.sessions[id]
 }
 
 // touchSession bumps the bound session's lastAccess so a live SSE stream keeps
@@ -295,17 +331,22 @@ func (a *App) sessionFromRequest(r *http.Request) *session {
 // session mismatch. Nil-safe: a ctx with no bound session is a no-op.
 func (ctx *Ctx) touchSession() {
 	if sess := ctx.session.Load(); sess != nil {
-		sess.lastAccess.Store(time.Now().UnixNano())
+		sess.lastAccess.Store(ctx.app.now().UnixNano())
 	}
 }
 
 func (a *App) removeExpiredSessions() {
-	cutoff := time.Now().Add(-a.cfg.sessionTTL).UnixNano()
+	cutoff := a.now().Add(-a.cfg.sessionTTL).UnixNano()
+	var expired []string
 	a.sessionsMu.Lock()
 	for id, sess := range a.sessions {
 		if sess.lastAccess.Load() < cutoff {
 			delete(a.sessions, id)
+			expired = append(expired, id)
 		}
 	}
 	a.sessionsMu.Unlock()
+	for _, id := range expired {
+		a.sessionEnded(id)
+	}
 }
@@ -36,10 +36,11 @@ import (
 )
 
 // Source constrains where a session can be resolved from: a *via.Ctx
-// (actions / handlers), a *via.CtxR (reads during a render), or an
-// *http.Request (middleware, before any composition is rendered).
+// (actions / handlers), a *via.CtxR (reads during a render), an
+// *http.Request (middleware, before any composition is rendered), or a
+// *via.Request (JSON endpoints registered with App.API).
 type Source interface {
-	*via.Ctx | *via.CtxR | *http.Request
+	*via.Ctx | *via.CtxR | *http.Request | *via.Request
 }
 
 // session resolves src to its *via.Session. The type switch is
@@ -53,6 +54,8 @@ func session[S Source](src S) *via.Session {
 		return v.Session()
 	case *http.Request:
 		return via.RequestSession(v)
+	case *via.Request:
+		return v.Session()
 	}
 	return nil
 }
@@ -0,0 +1,81 @@
+package via
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sessionCookiePrefix versions the signed cookie format, so a later
+// format can be told apart and migrated.
+const sessionCookiePrefix = "v1."
+
+// WithSessionKeys signs the session cookie with HMAC-SHA256. The cookie
+// then reads "v1.<id>.<mac>": a request whose cookie doesn't verify is
+// treated as having no session, so it is neither found nor adopted from
+// another pod (counted as via.session.rejected), however the id was
+// learned or guessed.
+//
+// keys[0] signs new cookies; every key verifies. To rotate, put the new
+// key first and keep the old one after it until the session TTL has
+// passed: a cookie signed with an older key keeps working and is re-issued
+// under the new one on its next request, so no one is logged out. Every
+// pod must share the list. Enabling signing on a running app ends the
+// sessions whose cookies predate it.
+//
+// The cookie carries only the session id — session data stays on the
+// server — so there is nothing in it to encrypt. Panics at New on a key
+// shorter than 16 bytes.
+func WithSessionKeys(keys ...[]byte) Option {
+	return func(c *config) { c.sessionKeys = append(c.sessionKeys, keys...) }
+}
+
+func validateSessionKeys(keys [][]byte) {
+	for i, k := range keys {
+		if len(k) < 16 {
+			panic(fmt.Sprintf("via.WithSessionKeys: key %d must be at least 16 bytes, got %d", i, len(k)))
+		}
+	}
+}
+
+func sessionMAC(key []byte, id string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(sessionCookiePrefix))
+	m.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// encodeSessionCookie is the cookie value for session id.
+func (a *App) encodeSessionCookie(id string) string {
+	if len(a.cfg.sessionKeys) == 0 {
+		return id
+	}
+	return sessionCookiePrefix + id + "." + sessionMAC(a.cfg.sessionKeys[0], id)
+}
+
+// decodeSessionCookie returns the session id a cookie value carries.
+// ok is false for a value that doesn't verify; stale is true when it
+// verified under a key other than the signing one and should be re-issued.
+// Without WithSessionKeys the value is the id itself.
+func (a *App) decodeSessionCookie(v string) (id string, ok, stale bool) {
+	keys := a.cfg.sessionKeys
+	if len(keys) == 0 {
+		return v, true, false
+	}
+	rest, found := strings.CutPrefix(v, sessionCookiePrefix)
+	if !found {
+		return "", false, false
+	}
+	id, mac, found := strings.Cut(rest, ".")
+	if !found || !validSessionID(id) {
+		return "", false, false
+	}
+	for i, k := range keys {
+		if hmac.Equal([]byte(mac), []byte(sessionMAC(k, id))) {
+			return id, true, i > 0
+		}
+	}
+	return "", false, false
+}
@@ -0,0 +1,113 @@
+package via_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sessKeyA = []byte("0123456789abcdef-key-a")
+	sessKeyB = []byte("0123456789abcdef-key-b")
+)
+
+func keyedApp(m via.Metrics, keys ...[]byte) *via.App {
+	opts := []via.Option{via.WithSessionKeys(keys...)}
+	if m != nil {
+		opts = append(opts, via.WithMetrics(m))
+	}
+	app := via.New(opts...)
+	app.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {})
+	return app
+}
+
+// ping sends GET /ping carrying cookie (when non-empty) and returns the
+// session cookie the response set, or nil.
+func ping(app *via.App, cookie string) *http.Cookie {
+	r := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	if cookie != "" {
+		r.AddCookie(&http.Cookie{Name: "via_session", Value: cookie})
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "via_session" {
+			return c
+		}
+	}
+	return nil
+}
+
+// sessionIDOf is the id inside a signed cookie value.
+func sessionIDOf(t *testing.T, v string) string {
+	t.Helper()
+	parts := strings.Split(v, ".")
+	require.Len(t, parts, 3, "signed cookie is v1.<id>.<mac>: %q", v)
+	require.Equal(t, "v1", parts[0])
+	return parts[1]
+}
+
+func TestSessionKeys_signTheCookieAndKeepAVerifiedSession(t *testing.T) {
+	t.Parallel()
+	app := keyedApp(nil, sessKeyA)
+
+	issued := ping(app, "")
+	require.NotNil(t, issued)
+	sessionIDOf(t, issued.Value)
+	assert.Nil(t, ping(app, issued.Value), "a verified cookie keeps its session")
+}
+
+func TestSessionKeys_rejectACookieWithAForgedMAC(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	app := keyedApp(m, sessKeyA)
+	id := sessionIDOf(t, ping(app, "").Value)
+
+	for _, forged := range []string{
+		"v1." + id + ".AAAA",
+		id, // the bare id, as an unsigned app would have issued it
+	} {
+		fresh := ping(app, forged)
+		require.NotNil(t, fresh, "%q must not reach the session", forged)
+		assert.NotEqual(t, id, sessionIDOf(t, fresh.Value))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.session.rejected:")
+}
+
+func TestSessionKeys_adoptASignedCookieAcrossPods(t *testing.T) {
+	t.Parallel()
+	issued := ping(keyedApp(nil, sessKeyA), "")
+
+	assert.Nil(t, ping(keyedApp(nil, sessKeyA), issued.Value),
+		"a pod sharing the key adopts the session without re-issuing it")
+	forger := ping(keyedApp(nil, sessKeyB), "")
+	other := ping(keyedApp(nil, sessKeyA), forger.Value)
+	require.NotNil(t, other, "a cookie signed with a foreign key is never adopted")
+	assert.NotEqual(t, sessionIDOf(t, forger.Value), sessionIDOf(t, other.Value))
+}
+
+func TestSessionKeys_rotateWithoutEndingSessions(t *testing.T) {
+	t.Parallel()
+	old := ping(keyedApp(nil, sessKeyA), "")
+	rotated := keyedApp(nil, sessKeyB, sessKeyA)
+
+	reissued := ping(rotated, old.Value)
+	require.NotNil(t, reissued, "a cookie under a retired key is re-signed")
+	assert.Equal(t, sessionIDOf(t, old.Value), sessionIDOf(t, reissued.Value), "same session")
+	assert.NotEqual(t, old.Value, reissued.Value)
+	assert.Nil(t, ping(rotated, reissued.Value), "the re-signed cookie is current")
+}
+
+func TestWithSessionKeys_rejectsShortKeys(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithValue(t, "via.WithSessionKeys: key 1 must be at least 16 bytes, got 5", func() {
+		via.New(via.WithSessionKeys(sessKeyA, []byte("short")))
+	})
+}
@@ -0,0 +1,82 @@
+package via
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/go-via/via/h"
+)
+
+// shellTabSuffix stands in for the random half of a shell document's tab
+// id. genSecureID never yields it in practice, and it still matches
+// staleTabSuffixRE, so the shell's SSE handshake takes the stale-tab
+// re-bootstrap path: a fresh tab is minted there and swapped in.
+var shellTabSuffix = strings.Repeat("0", 64)
+
+// shellTabID is the fixed tab id a CacheableShell document is rendered
+// with, so the document is byte-identical across visitors.
+func shellTabID(route string) string { return route + "_" + shellTabSuffix }
+
+func isShellTabID(id string) bool { return strings.HasSuffix(id, "_"+shellTabSuffix) }
+
+// servesShell reports whether this GET of d is answered with a cacheable
+// shell. Crawler, RenderPage and Export renders keep their own documents,
+// and a request carrying a CSP nonce can't be: the nonce differs per
+// response, so neither the bytes nor a 304 could be reused.
+func (a *App) servesShell(d *cmpDescriptor, r *http.Request) bool {
+	if !d.shell || a.isCrawler(r) {
+		return false
+	}
+	if _, ok := r.Context().Value(pageRenderKey{}).(*pageRender); ok {
+		return false
+	}
+	if n, _ := r.Context().Value(cspNonceKey{}).(string); n != "" {
+		return false
+	}
+	return true
+}
+
+// writeShell writes the shell document with its ETag, or a bare 304 when
+// the client already holds it. Cache-Control no-cache lets the browser
+// keep the copy but makes it ask every time, so a deploy that changes
+// the page is picked up on the next visit.
+func (a *App) writeShell(w http.ResponseWriter, r *http.Request, ctx *Ctx, body h.H) {
+	buf := getRenderBuf()
+	defer putRenderBuf(buf)
+	a.writePageDocument(buf, ctx, body)
+	sum := sha256.Sum256(buf.Bytes())
+	// Weak: the compressed and plain encodings of one document share it.
+	etag := `W/"` + base64.RawURLEncoding.EncodeToString(sum[:18]) + `"`
+
+	hdr := w.Header()
+	hdr.Set("ETag", etag)
+	hdr.Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		a.metricsOrNoop().Counter("via.render.shell", "result", "not_modified")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	a.metricsOrNoop().Counter("via.render.shell", "result", "full")
+	hdr.Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		a.logWarn(ctx, "page render write failed: %v", err)
+	}
+}
+
+// etagMatches applies If-None-Match's weak comparison: any listed tag (or
+// "*") equal to etag once W/ prefixes are ignored.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
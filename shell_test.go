@@ -0,0 +1,128 @@
+package via_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type landingPage struct {
+	Visits via.StateTab[int]
+}
+
+func (p *landingPage) CacheableShell() {}
+
+func (p *landingPage) OnInit(ctx *via.Ctx) error {
+	p.Visits.Write(ctx, 1)
+	return nil
+}
+
+func (p *landingPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.H1(h.Text("Welcome")), h.P(h.Textf("visits %d", p.Visits.Read(ctx))))
+}
+
+func getShell(t *testing.T, url, ifNoneMatch string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCacheableShell_revalidatesWith304(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[landingPage](app, "/")
+
+	first, body := getShell(t, server.URL+"/", "")
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	etag := first.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Contains(t, first.Header.Get("Cache-Control"), "no-cache")
+	assert.Contains(t, body, "Welcome")
+	assert.Empty(t, app.Contexts(), "the shell's tab is never registered")
+
+	second, body2 := getShell(t, server.URL+"/", "")
+	assert.Equal(t, etag, second.Header.Get("ETag"), "every visitor gets the same bytes")
+	assert.Equal(t, body, body2)
+
+	again, rest := getShell(t, server.URL+"/", `"other", `+etag)
+	assert.Equal(t, http.StatusNotModified, again.StatusCode)
+	assert.Empty(t, rest)
+}
+
+func TestCacheableShell_onlyForMarkedPages(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[recoverPage](app, "/")
+
+	resp, _ := getShell(t, server.URL+"/", "")
+	assert.Empty(t, resp.Header.Get("ETag"))
+}
+
+func TestCacheableShell_sseHandshakeMintsTheRealTab(t *testing.T) {
+	t.Parallel()
+	m := &captureMetrics{}
+	app := via.New(via.WithMetrics(m))
+	server := vt.Serve(t, app)
+	via.Mount[landingPage](app, "/")
+
+	_, body := getShell(t, server.URL+"/", "")
+	shellID := vt.TabIDFromHTML(body)
+	require.True(t, strings.HasSuffix(shellID, strings.Repeat("0", 64)), shellID)
+
+	status, frames, cancel := openRawSSE(t, jarClient(t), server.URL, shellID, server.URL+"/")
+	defer cancel()
+	require.Equal(t, http.StatusOK, status)
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "visits 1")
+	ids := tabSignalRE.FindStringSubmatch(frame)
+	require.Len(t, ids, 2, "the handshake seeds a fresh via_tab")
+	assert.NotEqual(t, shellID, ids[1])
+	assert.Len(t, app.Contexts(), 1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Contains(t, m.counters, "via.sse.recover:mode,shell")
+	assert.NotContains(t, m.counters, "via.tab.unknown:kind,sse")
+}
+
+func TestCacheableShell_notForCSPNonceOrCrawlers(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithCrawlerRender(nil))
+	app.Use(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		if r.URL.Query().Has("nonce") {
+			r = via.RequestWithCSPNonce(r, "n0nce")
+		}
+		next.ServeHTTP(w, r)
+	})
+	via.Mount[landingPage](app, "/")
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?nonce", nil))
+	assert.Empty(t, rec.Header().Get("ETag"), "a per-response nonce can't be cached")
+	assert.NotContains(t, rec.Body.String(), strings.Repeat("0", 64), "a nonce page gets its own tab")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1)")
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), "Welcome")
+}
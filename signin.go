@@ -0,0 +1,230 @@
+package via
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+)
+
+// signIn is the identity SignIn attaches to a session.
+type signIn struct {
+	user   string
+	device string
+	addr   string
+	at     time.Time
+}
+
+// SessionInfo describes one signed-in session, as listed by
+// [App.UserSessions] and handed to a [WithSignInAlert] callback.
+type SessionInfo struct {
+	// ID is the session's opaque handle — the same digest as
+	// ContextInfo.SessionID, never the cookie — to pass to RevokeSession.
+	ID         string
+	User       string
+	Device     string    // coarse browser and OS of the sign-in, e.g. "Firefox on Linux"
+	Addr       string    // remote address of the sign-in request
+	SignedInAt time.Time // when SignIn ran
+	LastActive time.Time
+}
+
+// sessionDigest is the public handle of a session id.
+func sessionDigest(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *session) info() (SessionInfo, bool) {
+	in := s.auth.Load()
+	if in == nil {
+		return SessionInfo{}, false
+	}
+	return SessionInfo{
+		ID:         sessionDigest(s.id),
+		User:       in.user,
+		Device:     in.device,
+		Addr:       in.addr,
+		SignedInAt: in.at,
+		LastActive: time.Unix(0, s.lastAccess.Load()),
+	}, true
+}
+
+// SignIn records that this tab's session now belongs to user, stamped with
+// the device and address of the in-flight request. Call it from the login
+// action once credentials check out, after [Session.Rotate]:
+//
+//	ctx.Session().Rotate()
+//	ctx.SignIn(u.ID)
+//
+// Every other live tab of user's other sessions is then told about the new
+// sign-in when [WithSignInAlert] is set, and [App.UserSessions] lists the
+// session until it expires, signs out or is revoked. Signing in again as
+// the same user refreshes the record without re-alerting. Pod-local: the
+// record and the alert cover sessions this pod holds.
+func (ctx *Ctx) SignIn(user string) {
+	if ctx == nil || user == "" {
+		return
+	}
+	sess := ctx.session.Load()
+	if sess == nil {
+		return
+	}
+	in := &signIn{user: user, at: ctx.app.now()}
+	if r := ctx.Request(); r != nil {
+		in.device = deviceLabel(r.UserAgent())
+		in.addr = r.RemoteAddr
+	}
+	prev := sess.auth.Swap(in)
+	if prev != nil && prev.user == user {
+		return
+	}
+	alert := ctx.app.cfg.signInAlert
+	if alert == nil {
+		return
+	}
+	login, _ := sess.info()
+	ctx.app.BroadcastFunc(func(c ContextInfo) bool {
+		return c.SessionID != login.ID && c.User == user
+	}, func(c *Ctx) { alert(c, login) })
+}
+
+// SignOut detaches the signed-in user from this tab's session; the
+// session's other data is left alone.
+func (ctx *Ctx) SignOut() {
+	if ctx == nil {
+		return
+	}
+	if sess := ctx.session.Load(); sess != nil {
+		sess.auth.Store(nil)
+	}
+}
+
+// User returns the user this tab's session signed in as, or "".
+func (ctx *Ctx) User() string {
+	if ctx == nil {
+		return ""
+	}
+	sess := ctx.session.Load()
+	if sess == nil {
+		return ""
+	}
+	if in := sess.auth.Load(); in != nil {
+		return in.user
+	}
+	return ""
+}
+
+// User returns the signed-in user of the tab's session, or "". See
+// [Ctx.SignIn].
+func (r *CtxR) User() string { return r.rctx().User() }
+
+// UserSessions lists the live sessions signed in as user on this pod,
+// most recent sign-in first — the "where you're signed in" page.
+func (a *App) UserSessions(user string) []SessionInfo {
+	a.sessionsMu.RLock()
+	var out []SessionInfo
+	for _, s := range a.sessions {
+		if in, ok := s.info(); ok && in.User == user {
+			out = append(out, in)
+		}
+	}
+	a.sessionsMu.RUnlock()
+	slices.SortFunc(out, func(x, y SessionInfo) int { return y.SignedInAt.Compare(x.SignedInAt) })
+	return out
+}
+
+// RevokeSession ends the session whose [SessionInfo.ID] is id, provided it
+// is signed in as the same user as ctx — a user can revoke their own
+// sessions, nobody else's. The session and everything stored in it are
+// dropped, its open tabs lose their actions and reload signed out.
+// Reports whether a session was revoked.
+func (ctx *Ctx) RevokeSession(id string) bool {
+	user := ctx.User()
+	if user == "" || id == "" {
+		return false
+	}
+	a := ctx.app
+	a.sessionsMu.Lock()
+	var victim *session
+	for sid, s := range a.sessions {
+		if in := s.auth.Load(); in != nil && in.user == user && sessionDigest(sid) == id {
+			victim = s
+			delete(a.sessions, sid)
+			break
+		}
+	}
+	a.sessionsMu.Unlock()
+	if victim == nil {
+		return false
+	}
+	a.endSession(victim)
+	return true
+}
+
+// endSession finishes a session the caller has already removed from the
+// registry: it runs the plugins' OnSessionEnd hooks, clears the session's
+// sign-in and stored values, and reloads every live tab still bound to it.
+// A reloaded tab comes back signed out, its cookie's id adopted afresh as
+// an empty session.
+func (a *App) endSession(s *session) {
+	a.sessionEnded(s.id)
+	s.auth.Store(nil)
+	s.data.Range(func(k, _ any) bool {
+		s.data.Delete(k.(string))
+		return true
+	})
+	for _, c := range a.snapshotContexts() {
+		if c.session.Load() == s {
+			c.Reload()
+		}
+	}
+}
+
+// WithSignInAlert runs alert on every live tab of a user's other sessions
+// when [Ctx.SignIn] signs that user in somewhere new. login describes the
+// new session; offer its revocation from the alert's UI with
+// [Ctx.RevokeSession](login.ID). alert runs like a [App.BroadcastFunc]
+// callback — under the tab's action lock, its writes flushed on return. A
+// nil alert shows a plain "New sign-in from …" notification.
+func WithSignInAlert(alert func(ctx *Ctx, login SessionInfo)) Option {
+	return func(c *config) {
+		if alert == nil {
+			alert = func(ctx *Ctx, login SessionInfo) {
+				ctx.Notify("New sign-in from " + cmp.Or(login.Device, "an unknown device"))
+			}
+		}
+		c.signInAlert = alert
+	}
+}
+
+// deviceLabel reduces a User-Agent to "<browser> on <OS>". Order matters:
+// Edge and Chrome UAs also say Safari, Chrome on iOS says Safari too.
+func deviceLabel(ua string) string {
+	pick := func(pairs [][2]string) string {
+		for _, p := range pairs {
+			if strings.Contains(ua, p[0]) {
+				return p[1]
+			}
+		}
+		return ""
+	}
+	browser := pick([][2]string{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"FxiOS/", "Firefox"},
+		{"CriOS/", "Chrome"}, {"Chrome/", "Chrome"}, {"Safari/", "Safari"},
+	})
+	os := pick([][2]string{
+		{"iPhone", "iOS"}, {"iPad", "iPadOS"}, {"Android", "Android"},
+		{"Windows", "Windows"}, {"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	})
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	}
+	return ""
+}
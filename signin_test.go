@@ -0,0 +1,112 @@
+package via_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accountPage struct{}
+
+func (p *accountPage) Login(ctx *via.Ctx) {
+	ctx.Session().Rotate()
+	ctx.SignIn(ctx.EventArg("user"))
+}
+
+func (p *accountPage) Revoke(ctx *via.Ctx) {
+	if !ctx.RevokeSession(ctx.EventArg("id")) {
+		ctx.Notify("not revoked")
+	}
+}
+
+func (p *accountPage) Logout(ctx *via.Ctx) { ctx.SignOut() }
+
+func (p *accountPage) View(ctx *via.CtxR) h.H {
+	return h.P(h.Text("user:" + ctx.User()))
+}
+
+func login(t *testing.T, tc *vt.Client, user string) {
+	t.Helper()
+	require.Equal(t, http.StatusOK, tc.Action("Login").WithArg("user", user).Fire())
+}
+
+func TestSignIn_alertsTheUsersOtherSessions(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithSignInAlert(nil))
+	server := vt.Serve(t, app)
+	via.Mount[accountPage](app, "/")
+
+	laptop := vt.NewClient(t, server, "/")
+	login(t, laptop, "ada")
+	frames, cancel := laptop.SSEReady()
+	defer cancel()
+
+	stranger := vt.NewClient(t, server, "/")
+	login(t, stranger, "bob")
+	phone := vt.NewClient(t, server, "/")
+	login(t, phone, "ada")
+
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "New sign-in from an unknown device")
+	assert.NotContains(t, frame, "bob")
+
+	sessions := app.UserSessions("ada")
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "ada", sessions[0].User)
+	assert.False(t, sessions[0].SignedInAt.Before(sessions[1].SignedInAt), "most recent first")
+}
+
+func TestRevokeSession_signsOutTheOtherDevice(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[accountPage](app, "/")
+
+	laptop := vt.NewClient(t, server, "/")
+	login(t, laptop, "ada")
+	phone := vt.NewClient(t, server, "/")
+	login(t, phone, "ada")
+	frames, cancel := phone.SSEReady()
+	defer cancel()
+
+	var phoneID string
+	for _, c := range app.Contexts() {
+		if c.ID == phone.TabID() {
+			phoneID = c.SessionID
+		}
+	}
+	require.NotEmpty(t, phoneID)
+
+	require.Equal(t, http.StatusOK, laptop.Action("Revoke").WithArg("id", phoneID).Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "location.reload()")
+	assert.Len(t, app.UserSessions("ada"), 1)
+	assert.Equal(t, http.StatusForbidden, phone.Action("Logout").Fire(),
+		"the revoked session's tab can no longer act")
+}
+
+func TestRevokeSession_refusesAnotherUsersSession(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[accountPage](app, "/")
+
+	ada := vt.NewClient(t, server, "/")
+	login(t, ada, "ada")
+	bob := vt.NewClient(t, server, "/")
+	login(t, bob, "bob")
+	frames, cancel := ada.SSEReady()
+	defer cancel()
+
+	bobID := app.UserSessions("bob")[0].ID
+	require.Equal(t, http.StatusOK, ada.Action("Revoke").WithArg("id", bobID).Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "not revoked")
+	assert.Len(t, app.UserSessions("bob"), 1)
+}
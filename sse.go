@@ -1,21 +1,24 @@
 package via
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"html"
 	"io"
 	"maps"
 	"net/http"
+	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/starfederation/datastar-go/datastar"
 )
 
-// sseLevel is the brotli compression level applied to SSE streams.
-// Level 5 trades a bit of CPU for noticeable bandwidth savings on the
+// sseLevel is the default Brotli level for page responses and SSE
+// streams (see [Compression]). Level 5 trades a bit of CPU for noticeable bandwidth savings on the
 // repetitive HTML element patches via emits.
 const sseLevel = 5
 
@@ -43,7 +46,9 @@ func (a *App) handleSSE(w http.ResponseWriter, r *http.Request) {
 		// A well-formed tab id this pod doesn't hold also signals wrong-pod
 		// routing (no sticky sessions), not only a TTL sweep / restart — count
 		// it so a non-sticky LB is observable. Empty id = malformed probe.
-		if tabID != "" {
+		// A CacheableShell document's placeholder id is expected here, not
+		// a routing symptom.
+		if tabID != "" && !isShellTabID(tabID) {
 			a.metricsOrNoop().Counter("via.tab.unknown", "kind", "sse")
 		}
 		// Stale-but-plausible tab id (TTL sweep, process restart):
@@ -89,6 +94,7 @@ func runSSEStream(a *App, ctx *Ctx, w http.ResponseWriter, r *http.Request, boot
 	// stream-less ctx is reaped by the next sweep once it ages past the TTL.
 	ctx.connected.Add(1)
 	defer ctx.connected.Add(-1)
+	ctx.connectedAt.Store(a.now().UnixNano())
 	// OnConnect runs once, the first time the SSE stream is opened. Bots
 	// that hit GET without ever opening the SSE never see this fire, so
 	// expensive background work (tickers, fan-out goroutines) lives here
@@ -103,8 +109,7 @@ func runSSEStream(a *App, ctx *Ctx, w http.ResponseWriter, r *http.Request, boot
 		}
 	})
 
-	sse := datastar.NewSSE(w, r,
-		datastar.WithCompression(datastar.WithBrotli(datastar.WithBrotliLevel(sseLevel))))
+	sse := datastar.NewSSE(w, r, a.cfg.compression.sseOptions()...)
 
 	// Latch-and-branch on connection history. A re-bootstrap (boot != nil)
 	// seeds the fresh tab wholesale: signals first (incl. the new via_tab,
@@ -114,47 +119,35 @@ func runSSEStream(a *App, ctx *Ctx, w http.ResponseWriter, r *http.Request, boot
 	// clobber live client-side signal state) and then the view, so a
 	// client that drifted while disconnected (e.g. a trimmed queue, a
 	// missed frame) converges back to server truth.
+	//
+	// A reconnect whose Last-Event-ID the replay buffer still covers
+	// skips the resync: re-sending just the missed events is cheaper and,
+	// unlike a re-render, also restores appends and scripts.
 	if reconnect := ctx.everConnected.Swap(true); boot != nil {
-		setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
-		if err := sse.PatchSignals(boot.signals); err != nil {
+		if err := ctx.replay.emit(sse, ctx, w, a.cfg.sseWriteTimeout,
+			replayEvent{kind: replaySignals, data: string(boot.signals)}); err != nil {
 			return
 		}
 		if boot.elements != "" {
-			setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
-			if err := sse.PatchElements(boot.elements,
-				datastar.WithSelector(boot.selector),
-				datastar.WithMode(datastar.ElementPatchModeReplace)); err != nil {
+			if err := ctx.replay.emit(sse, ctx, w, a.cfg.sseWriteTimeout, replayEvent{kind: replayElements,
+				data: boot.elements, selector: boot.selector, mode: datastar.ElementPatchModeReplace}); err != nil {
 				return
 			}
 		}
 	} else if reconnect {
-		m.Counter("via.sse.resync")
-		// Pending-signal patch FIRST, view fragment second — mirroring the
-		// re-bootstrap order above — so data-* bindings in the incoming
-		// elements read the refreshed values. The patch coalesces
-		// (last-value-wins per key) everything still queued with every
-		// signal the server ever pushed on this ctx: a push drained onto a
-		// dying socket is otherwise lost, silently desyncing the client
-		// from what the server believes it pushed.
-		if pending := resyncSignals(ctx); len(pending) > 0 {
-			out, err := json.Marshal(pending)
-			if err != nil {
-				a.logErr(ctx, "resync: json.Marshal signals: %v", err)
-			} else {
-				setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
-				if err := sse.PatchSignals(out); err != nil {
-					return
-				}
-			}
-		}
-		if frag := a.renderFragment(ctx); frag != "" {
-			setSSEWriteDeadline(w, a.cfg.sseWriteTimeout)
-			if err := sse.PatchElements(frag); err != nil {
-				return
-			}
+		if replayed, err := a.replayMissed(sse, ctx, w, r); err != nil {
+			return
+		} else if replayed {
+			m.Counter("via.sse.replay")
+		} else if !a.resyncStream(sse, ctx, w) {
+			return
 		}
 	}
 
+	// A tab reconnecting after a long gap (a laptop waking) may hold
+	// expired action signatures; queue fresh ones ahead of the drain.
+	a.refreshActionSigs(ctx)
+
 	// Force-drain anything queued while the previous SSE was
 	// disconnected — patches accumulated during the gap have no wake
 	// notification waiting (it was either consumed by the dead loop or
@@ -200,6 +193,37 @@ func runSSEStream(a *App, ctx *Ctx, w http.ResponseWriter, r *http.Request, boot
 	t := time.NewTicker(keepalive)
 	defer t.Stop()
 
+	// Adaptive pacing: a wake that arrives before the pacer's gap has
+	// elapsed arms pace instead of draining, and everything queued until
+	// it fires ships as one frame. The one timer is reused for the life of
+	// the stream.
+	pacer := framePacer{max: a.cfg.sseMaxFrameGap}
+	var (
+		next  time.Time
+		pace  *time.Timer
+		paceC <-chan time.Time
+	)
+	defer func() {
+		if pace != nil {
+			pace.Stop()
+		}
+		ctx.frameGap.Store(0)
+	}()
+	flush := func() error {
+		start := time.Now()
+		if err := drainQueue(sse, ctx, w, a.cfg.sseWriteTimeout); err != nil {
+			return err
+		}
+		took := time.Since(start)
+		m.Histogram("via.sse.write", took.Seconds(), "route", ctx.desc.route)
+		pacer.observe(took)
+		gap := pacer.interval()
+		ctx.frameGap.Store(int64(gap))
+		next = start.Add(gap)
+		ctx.touch()
+		return nil
+	}
+
 	for {
 		select {
 		case <-sse.Context().Done():
@@ -218,15 +242,64 @@ func runSSEStream(a *App, ctx *Ctx, w http.ResponseWriter, r *http.Request, boot
 				return
 			}
 			ctx.touchSession()
+			a.refreshActionSigs(ctx)
 		case <-ctx.queue.wake:
-			if err := drainQueue(sse, ctx, w, a.cfg.sseWriteTimeout); err != nil {
+			if wait := time.Until(next); wait > 0 {
+				if paceC == nil {
+					m.Counter("via.sse.paced", "route", ctx.desc.route)
+					if pace == nil {
+						pace = time.NewTimer(wait)
+					} else {
+						pace.Reset(wait)
+					}
+					paceC = pace.C
+				}
+				continue
+			}
+			if err := flush(); err != nil {
+				return
+			}
+		case <-paceC:
+			paceC = nil
+			if err := flush(); err != nil {
 				return
 			}
-			ctx.touch()
 		}
 	}
 }
 
+// resyncStream brings a reconnecting client the replay buffer can't catch
+// up back to server truth: the signals the server pushed, then the view.
+// Reports false when a write failed and the stream is done.
+func (a *App) resyncStream(sse *datastar.ServerSentEventGenerator, ctx *Ctx, w http.ResponseWriter) bool {
+	a.metricsOrNoop().Counter("via.sse.resync")
+	// Events sent before the resync are superseded by it.
+	ctx.replay.reset()
+	// Pending-signal patch FIRST, view fragment second — mirroring the
+	// re-bootstrap order in runSSEStream — so data-* bindings in the incoming
+	// elements read the refreshed values. The patch coalesces
+	// (last-value-wins per key) everything still queued with every
+	// signal the server ever pushed on this ctx: a push drained onto a
+	// dying socket is otherwise lost, silently desyncing the client
+	// from what the server believes it pushed.
+	if pending := resyncSignals(ctx); len(pending) > 0 {
+		out, err := json.Marshal(pending)
+		if err != nil {
+			a.logErr(ctx, "resync: json.Marshal signals: %v", err)
+		} else if err := ctx.replay.emit(sse, ctx, w, a.cfg.sseWriteTimeout,
+			replayEvent{kind: replaySignals, data: string(out)}); err != nil {
+			return false
+		}
+	}
+	if frag := a.renderFragment(ctx); frag != "" {
+		if err := ctx.replay.emit(sse, ctx, w, a.cfg.sseWriteTimeout,
+			replayEvent{kind: replayElements, data: frag}); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
 // setSSEWriteDeadline installs a per-call write deadline so a stalled
 // peer can't pin the SSE goroutine forever. Wrapped to swallow the
 // "not supported" case the response writer may surface when the runtime
@@ -249,8 +322,8 @@ func hasPending(q *patchQueue) bool {
 	}
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return q.autoElements != "" || q.elements != "" || q.redirect != "" ||
-		len(q.signals) > 0 || q.scripts.Len() > 0
+	return q.autoElements != "" || q.elements != "" || len(q.moded) > 0 ||
+		q.redirect != "" || len(q.signals) > 0 || q.scripts.Len() > 0 || q.evals.Len() > 0
 }
 
 // drainQueue flushes the patch queue to the stream. The queue is
@@ -268,37 +341,70 @@ func drainQueue(sse *datastar.ServerSentEventGenerator, ctx *Ctx, w http.Respons
 	// live map after the unlock would race with them.
 	signals := maps.Clone(q.signals)
 	scripts := q.scripts.String()
+	evals := q.evals.String()
 	redirect := q.redirect
+	moded := slices.Clone(q.moded)
+	transition := q.transition
+	if q.hold {
+		// An action is mid-flight and something forced a wake (Progress.Set,
+		// Ctx.Eval). Ship only signals and evals: the action's elements,
+		// scripts, and redirect stay queued to land as one frame with its
+		// end-of-action render.
+		autoElems, userElems, scripts, redirect = "", "", "", ""
+		moded = nil
+	}
 	q.mu.Unlock()
 	// Auto render first, explicit patches after: the morph applies
 	// same-id patches last-wins, so the user's targeted override beats
 	// the auto render of the same element.
 	elems := autoElems + userElems
+	if ctx.app != nil && ctx.app.cfg.viewTransitions {
+		transition = true
+	}
 
 	// Re-arm the write deadline before EACH network write: a single deadline
 	// set at entry would span the sum of up to four sequential writes, so a
 	// peer that stalls on a later write has already burned the budget on the
 	// earlier ones. Per-write keeps every write bounded independently.
-	nonceOpts := ctx.scriptNonceOpts()
+	rp := &ctx.replay
 	if redirect != "" {
-		setSSEWriteDeadline(w, writeTimeout)
-		if err := sse.Redirect(redirect, nonceOpts...); err != nil {
+		if err := rp.emit(sse, ctx, w, writeTimeout, replayEvent{kind: replayRedirect, data: redirect}); err != nil {
 			return err
 		}
 		// The browser is navigating away: the rest of the snapshot is
 		// deliberately dropped with the redirect, as it always was.
 		clearDrained(q, autoElems, userElems, signals, scripts, redirect)
+		q.mu.Lock()
+		q.moded = q.moded[len(moded):]
+		q.mu.Unlock()
 		return nil
 	}
 	if elems != "" {
-		setSSEWriteDeadline(w, writeTimeout)
-		if err := sse.PatchElements(elems); err != nil {
+		if err := rp.emit(sse, ctx, w, writeTimeout,
+			replayEvent{kind: replayElements, data: elems, transition: transition}); err != nil {
 			return err
 		}
 	}
+	for _, mp := range moded {
+		if err := rp.emit(sse, ctx, w, writeTimeout, replayEvent{kind: replayElements,
+			data: mp.html, selector: "#" + mp.id, mode: mp.mode, transition: transition}); err != nil {
+			return err
+		}
+		// Producers only append and only the drain consumes, so the
+		// head is the entry just written.
+		q.mu.Lock()
+		q.moded = q.moded[1:]
+		q.mu.Unlock()
+	}
+	if elems != "" || len(moded) > 0 {
+		q.mu.Lock()
+		q.transition = false
+		q.mu.Unlock()
+	}
 	if len(signals) > 0 {
-		out, err := json.Marshal(signals)
-		if err != nil {
+		buf := getRenderBuf()
+		defer putRenderBuf(buf)
+		if err := appendSignalsJSON(buf, signals); err != nil {
 			// User pushed an unmarshalable value via PatchSignal(s) /
 			// BroadcastSignals (e.g. a channel or func in the map). Log and
 			// drop the poison batch outright — value-compared clearing can't
@@ -316,18 +422,26 @@ func drainQueue(sse *datastar.ServerSentEventGenerator, ctx *Ctx, w http.Respons
 			q.mu.Unlock()
 			signals = nil
 		} else {
-			setSSEWriteDeadline(w, writeTimeout)
-			if err := sse.PatchSignals(out); err != nil {
+			if err := rp.emit(sse, ctx, w, writeTimeout, replayEvent{kind: replaySignals, data: buf.String()}); err != nil {
 				return err
 			}
 		}
 	}
 	if scripts != "" {
-		setSSEWriteDeadline(w, writeTimeout)
-		if err := sse.ExecuteScript(scripts, nonceOpts...); err != nil {
+		if err := rp.emit(sse, ctx, w, writeTimeout, replayEvent{kind: replayScript, data: scripts}); err != nil {
 			return err
 		}
 	}
+	if evals != "" {
+		if err := rp.emit(sse, ctx, w, writeTimeout, replayEvent{kind: replayScript, data: evals}); err != nil {
+			return err
+		}
+		q.mu.Lock()
+		cur := q.evals.String()
+		q.evals.Reset()
+		q.evals.WriteString(strings.TrimPrefix(cur, evals))
+		q.mu.Unlock()
+	}
 	clearDrained(q, autoElems, userElems, signals, scripts, redirect)
 	return nil
 }
@@ -346,7 +460,7 @@ func clearDrained(q *patchQueue, autoElems, userElems string, signals map[string
 	}
 	q.elements = strings.TrimPrefix(q.elements, userElems)
 	for k, v := range signals {
-		if cur, ok := q.signals[k]; ok && reflect.DeepEqual(cur, v) {
+		if cur, ok := q.signals[k]; ok && sameSignal(cur, v) {
 			delete(q.signals, k)
 		}
 	}
@@ -359,6 +473,83 @@ func clearDrained(q *patchQueue, autoElems, userElems string, signals map[string
 	}
 }
 
+// sameSignal reports whether the queued value is still the one drained.
+// Typed signals queue json.RawMessage, so the common case is a byte
+// compare; anything else falls back to reflect.DeepEqual.
+func sameSignal(cur, v any) bool {
+	if a, ok := cur.(json.RawMessage); ok {
+		if b, ok := v.(json.RawMessage); ok {
+			return bytes.Equal(a, b)
+		}
+	}
+	return reflect.DeepEqual(cur, v)
+}
+
+// appendSignalsJSON writes signals into buf as the same bytes
+// json.Marshal(signals) produces — sorted keys, compact, HTML-escaped —
+// without boxing the map through reflection on every tick. Pre-encoded
+// json.RawMessage values are compacted straight into buf; any value that
+// would need escaping, and every non-raw value, goes through
+// json.Marshal so the output and its errors stay exactly the stdlib's.
+func appendSignalsJSON(buf *bytes.Buffer, signals map[string]any) error {
+	var stack [16]string
+	keys := stack[:0]
+	for k := range signals {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if plainKey(k) {
+			buf.WriteByte('"')
+			buf.WriteString(k)
+			buf.WriteByte('"')
+		} else {
+			kb, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(kb)
+		}
+		buf.WriteByte(':')
+		if raw, ok := signals[k].(json.RawMessage); ok {
+			mark := buf.Len()
+			if json.Compact(buf, raw) == nil && !needsHTMLEscape(buf.Bytes()[mark:]) {
+				continue
+			}
+			buf.Truncate(mark)
+		}
+		vb, err := json.Marshal(signals[k])
+		if err != nil {
+			return err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// plainKey reports whether k encodes as itself between quotes.
+func plainKey(k string) bool {
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+		if c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			return false
+		}
+	}
+	return true
+}
+
+// needsHTMLEscape reports whether json.Marshal would rewrite b's bytes:
+// it escapes <, >, & and the JS line terminators U+2028 / U+2029.
+func needsHTMLEscape(b []byte) bool {
+	return bytes.ContainsAny(b, "<>&") ||
+		bytes.Contains(b, []byte("\xe2\x80\xa8")) || bytes.Contains(b, []byte("\xe2\x80\xa9"))
+}
+
 // resyncSignals builds the reconnect resync's coalesced signal patch:
 // every server-pushed signal's last value overlaid with whatever is still
 // queued, last-value-wins per key. Returns nil when there is nothing to
@@ -395,22 +586,11 @@ func (ctx *Ctx) scriptNonceOpts() []datastar.ExecuteScriptOption {
 }
 
 func (a *App) handleSSEClose(w http.ResponseWriter, r *http.Request) {
-	maxBody := a.cfg.maxRequestBody
-	if maxBody == 0 {
-		maxBody = 4096
-	}
-	r.Body = http.MaxBytesReader(w, r.Body, maxBody)
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		var mb *http.MaxBytesError
-		if errors.As(err, &mb) {
-			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
-			return
-		}
-		http.Error(w, "bad request", http.StatusBadRequest)
+	body, ok := a.readBeacon(w, r)
+	if !ok {
 		return
 	}
-	tabID := strings.TrimSpace(string(body))
+	tabID := strings.TrimSpace(body)
 	if ctx, ok := a.getCtx(tabID); ok {
 		if sess := ctx.session.Load(); sess != nil && a.sessionFromRequest(r) != sess {
 			return
@@ -423,3 +603,115 @@ func (a *App) handleSSEClose(w http.ResponseWriter, r *http.Request) {
 		a.disposeCtx(ctx, disconnectClient)
 	}
 }
+
+// handleVisibility records the page visibility the client reports for a
+// tab ("<tab id> hidden|visible"), which gates tickers opted in with
+// Ticker.PauseWhileHidden.
+func (a *App) handleVisibility(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readBeacon(w, r)
+	if !ok {
+		return
+	}
+	tabID, state, _ := strings.Cut(strings.TrimSpace(body), " ")
+	if ctx, ok := a.getCtx(tabID); ok {
+		if sess := ctx.session.Load(); sess != nil && a.sessionFromRequest(r) != sess {
+			return
+		}
+		ctx.hidden.Store(state == "hidden")
+	}
+}
+
+// handleEval delivers a Ctx.Eval reply ("<tab id> <eval id> <json>").
+func (a *App) handleEval(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readBeacon(w, r)
+	if !ok {
+		return
+	}
+	tabID, rest, _ := strings.Cut(body, " ")
+	id, rest, _ := strings.Cut(rest, " ")
+	var tok string
+	if a.signer.covers(evalAction) {
+		tok, rest, _ = strings.Cut(rest, " ")
+	}
+	if ctx, ok := a.getCtx(tabID); ok {
+		if sess := ctx.session.Load(); sess != nil && a.sessionFromRequest(r) != sess {
+			return
+		}
+		a.runBeacon(w, r, ctx, evalAction, tok, func() { ctx.answerEval(id, rest) })
+	}
+}
+
+// handlePopState runs a tab's history navigation ("<tab id> <path?query>")
+// through Ctx.popState. The path arrives with the base path, which the
+// handler sees stripped, as the routes do.
+func (a *App) handlePopState(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readBeacon(w, r)
+	if !ok {
+		return
+	}
+	tabID, rest, _ := strings.Cut(strings.TrimSpace(body), " ")
+	loc, tok := a.beaconToken(popStateAction, rest)
+	u, err := url.ParseRequestURI(loc)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	u.Path, _ = a.trimBasePath(u.Path)
+	u.RawPath = ""
+	if ctx, ok := a.getCtx(tabID); ok {
+		if sess := ctx.session.Load(); sess != nil && a.sessionFromRequest(r) != sess {
+			return
+		}
+		a.runBeacon(w, r, ctx, popStateAction, tok, func() { ctx.popState(u) })
+	}
+}
+
+// handleLazy reveals a CtxR.Lazy region the client reports has scrolled
+// into view ("<tab id> <region name>").
+func (a *App) handleLazy(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readBeacon(w, r)
+	if !ok {
+		return
+	}
+	tabID, rest, _ := strings.Cut(strings.TrimSpace(body), " ")
+	name, tok := a.beaconToken(lazyAction, rest)
+	if ctx, ok := a.getCtx(tabID); ok {
+		if sess := ctx.session.Load(); sess != nil && a.sessionFromRequest(r) != sess {
+			return
+		}
+		a.runBeacon(w, r, ctx, lazyAction, tok, func() { ctx.revealLazy(name) })
+	}
+}
+
+// beaconToken splits off the signature a beacon appends to its body when
+// the app signs it; rest is returned whole otherwise.
+func (a *App) beaconToken(action, rest string) (body, tok string) {
+	if !a.signer.covers(action) {
+		return rest, ""
+	}
+	if i := strings.LastIndexByte(rest, ' '); i >= 0 {
+		return rest[:i], rest[i+1:]
+	}
+	return rest, ""
+}
+
+// readBeacon reads the small text/plain body of a navigator.sendBeacon
+// POST, answering 413/400 itself when it fails.
+func (a *App) readBeacon(w http.ResponseWriter, r *http.Request) (string, bool) {
+	maxBody := a.cfg.maxRequestBody
+	if maxBody == 0 {
+		maxBody = 4096
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var mb *http.MaxBytesError
+		if errors.As(err, &mb) {
+			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+			return "", false
+		}
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return "", false
+	}
+	return string(body), true
+}
@@ -0,0 +1,91 @@
+package via
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-via/via/h"
+	"github.com/starfederation/datastar-go/datastar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendSignalsJSON_matchesJSONMarshal(t *testing.T) {
+	t.Parallel()
+	signals := map[string]any{
+		"count":     json.RawMessage(`42`),
+		"label":     json.RawMessage(`"a<b & c>d"`),
+		"spaced":    json.RawMessage(`{ "x" : [1, 2] }`),
+		"sep":       json.RawMessage("\"line\u2028break\""),
+		"nilRaw":    json.RawMessage(nil),
+		"plain":     map[string]int{"b": 2, "a": 1},
+		"odd<key>":  true,
+		"zeta.nest": "text",
+	}
+	want, err := json.Marshal(signals)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, appendSignalsJSON(&buf, signals))
+	assert.Equal(t, string(want), buf.String())
+}
+
+func TestAppendSignalsJSON_surfacesMarshalErrors(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.Error(t, appendSignalsJSON(&buf, map[string]any{"bad": json.RawMessage(`{nope`)}))
+	assert.Error(t, appendSignalsJSON(&buf, map[string]any{"fn": func() {}}))
+}
+
+// tickBenchPage is a dashboard tile: a handful of live numbers in a table,
+// re-rendered and re-signalled on every tick.
+type tickBenchPage struct {
+	Rows  StateTab[[]int]
+	Load  Signal[float64] `via:"load"`
+	Label Signal[string]  `via:"label"`
+}
+
+func (p *tickBenchPage) View(ctx *CtxR) h.H {
+	return h.Table(h.Each(p.Rows.Read(ctx), func(n int) h.H {
+		return h.Tr(h.Td(h.Textf("%d", n)), h.Td(h.Text("ok")))
+	}))
+}
+
+// discardSSE is a flushable ResponseWriter that drops the stream.
+type discardSSE struct{ header http.Header }
+
+func (d *discardSSE) Header() http.Header         { return d.header }
+func (d *discardSSE) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardSSE) WriteHeader(int)             {}
+func (d *discardSSE) Flush()                      {}
+
+// BenchmarkSyncTick measures one server-push tick with no HTTP in the way:
+// a state write and two signal writes, the re-render, and the drain onto
+// the SSE stream — the loop a 100 Hz dashboard runs per tab.
+func BenchmarkSyncTick(b *testing.B) {
+	app := New(WithoutDevChecks())
+	Mount[tickBenchPage](app, "/")
+	app.ServeHTTP(&pageRecorder{header: http.Header{}}, httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx := app.snapshotContexts()[0]
+	page := ctx.cmpReflect.Interface().(*tickBenchPage)
+
+	w := &discardSSE{header: http.Header{}}
+	sse := datastar.NewSSE(w, httptest.NewRequest(http.MethodGet, "/_sse", nil))
+	rows := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		rows[0] = i
+		page.Rows.Write(ctx, rows)
+		page.Load.Write(ctx, float64(i)/7)
+		page.Label.Write(ctx, "steady")
+		flushDirty(ctx)
+		if err := drainQueue(sse, ctx, w, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
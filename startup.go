@@ -0,0 +1,154 @@
+package via
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+)
+
+// StartupSummary is the configuration snapshot [App.Run] logs when it binds,
+// as returned by [App.StartupSummary]. Its JSON form is the machine-readable
+// startup line deploy tooling can diff across releases to catch drift.
+type StartupSummary struct {
+	Addr          string       `json:"addr"`
+	Routes        int          `json:"routes"`  // routes claimed by Mount, Handle, HandleStatic, …
+	Plugins       []PluginInfo `json:"plugins"` // in WithPlugins order
+	DevChecks     bool         `json:"dev_checks"`
+	MarkupChecks  bool         `json:"markup_checks"`
+	A11yAudit     bool         `json:"a11y_audit"`
+	PrettyHTML    bool         `json:"pretty_html"`
+	VerboseErrors bool         `json:"verbose_errors"`
+	Backplane     string       `json:"backplane"` // concrete type, e.g. "*via.inMemory"
+	GoVersion     string       `json:"go_version"`
+}
+
+// PluginInfo names one registered plugin in a [StartupSummary].
+type PluginInfo struct {
+	Name string `json:"name"` // concrete type, e.g. "*picocss.plugin"
+	// Version is the version of the Go module that provides the plugin, read
+	// from the binary's build info. Empty when the build carries none (go
+	// run, tests) or the plugin lives in the main module of a devel build.
+	Version string `json:"version,omitempty"`
+}
+
+// StartupSummary snapshots the app's effective configuration: bind address,
+// route count, plugins with their module versions, dev switches and the
+// state backplane.
+func (a *App) StartupSummary() StartupSummary {
+	a.routesMu.Lock()
+	routes := len(a.routes)
+	a.routesMu.Unlock()
+
+	bi, _ := debug.ReadBuildInfo()
+	plugins := make([]PluginInfo, 0, len(a.cfg.plugins))
+	for _, p := range a.cfg.plugins {
+		if p == nil {
+			continue
+		}
+		plugins = append(plugins, PluginInfo{
+			Name:    fmt.Sprintf("%T", p),
+			Version: moduleVersion(bi, pkgPathOf(p)),
+		})
+	}
+	s := StartupSummary{
+		Addr:          a.cfg.addr,
+		Routes:        routes,
+		Plugins:       plugins,
+		DevChecks:     a.cfg.devChecks,
+		MarkupChecks:  a.cfg.markupChecks,
+		A11yAudit:     a.cfg.a11yAudit,
+		PrettyHTML:    a.cfg.prettyHTML,
+		VerboseErrors: a.cfg.verboseErrors,
+		Backplane:     fmt.Sprintf("%T", a.backplane),
+	}
+	if bi != nil {
+		s.GoVersion = bi.GoVersion
+	}
+	return s
+}
+
+// String renders the human-readable banner.
+func (s StartupSummary) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "via started at [%s]: %d routes, backplane %s", s.Addr, s.Routes, s.Backplane)
+	if len(s.Plugins) > 0 {
+		sb.WriteString(", plugins")
+		for i, p := range s.Plugins {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte(' ')
+			sb.WriteString(p.Name)
+			if p.Version != "" {
+				sb.WriteByte('@')
+				sb.WriteString(p.Version)
+			}
+		}
+	}
+	if s.DevChecks {
+		sb.WriteString(", dev checks on")
+	}
+	if s.MarkupChecks {
+		sb.WriteString(", markup checks on")
+	}
+	if s.A11yAudit {
+		sb.WriteString(", a11y audit on")
+	}
+	if s.PrettyHTML {
+		sb.WriteString(", pretty HTML on")
+	}
+	if s.VerboseErrors {
+		sb.WriteString(", verbose errors on")
+	}
+	return sb.String()
+}
+
+// logStartup emits the banner and the one-line JSON summary at info level.
+func (a *App) logStartup() {
+	s := a.StartupSummary()
+	a.logInfo(nil, "%s", s)
+	b, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	a.logInfo(nil, "via startup %s", b)
+}
+
+func pkgPathOf(v any) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+// moduleVersion resolves the version of the module that owns pkg: the
+// longest module path that prefixes it wins, so a nested module
+// (github.com/x/y/plugins/z) isn't attributed to its parent.
+func moduleVersion(bi *debug.BuildInfo, pkg string) string {
+	if bi == nil || pkg == "" {
+		return ""
+	}
+	best, version := "", ""
+	consider := func(m *debug.Module) {
+		if m == nil || len(m.Path) <= len(best) {
+			return
+		}
+		if pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/") {
+			best, version = m.Path, m.Version
+			if m.Replace != nil && m.Replace.Version != "" {
+				version = m.Replace.Version
+			}
+		}
+	}
+	consider(&bi.Main)
+	for _, d := range bi.Deps {
+		consider(d)
+	}
+	if version == "(devel)" {
+		return ""
+	}
+	return version
+}
@@ -0,0 +1,68 @@
+package via_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type startupPage struct{}
+
+func (p *startupPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestStartupSummary_reportsEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	ran := false
+	app := via.New(
+		via.WithAddr(":4321"),
+		via.WithPlugins(noopPlugin{called: &ran}),
+		via.WithA11yAudit(),
+		via.WithPrettyHTML(),
+	)
+	via.Mount[startupPage](app, "/")
+	via.Mount[startupPage](app, "/other")
+
+	s := app.StartupSummary()
+
+	assert.Equal(t, ":4321", s.Addr)
+	assert.Equal(t, 2, s.Routes)
+	require.Len(t, s.Plugins, 1)
+	assert.Equal(t, "via_test.noopPlugin", s.Plugins[0].Name)
+	assert.True(t, s.DevChecks, "dev checks default on")
+	assert.True(t, s.A11yAudit)
+	assert.True(t, s.PrettyHTML)
+	assert.NotEmpty(t, s.Backplane)
+}
+
+func TestStartupSummary_marshalsToOneJSONLine(t *testing.T) {
+	t.Parallel()
+
+	app := via.New(via.WithoutDevChecks())
+	b, err := json.Marshal(app.StartupSummary())
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(b), "\n")
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, ":3000", got["addr"])
+	assert.Equal(t, false, got["dev_checks"])
+	assert.Contains(t, got, "backplane")
+}
+
+func TestStartupSummary_stringIsHumanBanner(t *testing.T) {
+	t.Parallel()
+
+	ran := false
+	app := via.New(via.WithPlugins(noopPlugin{called: &ran}))
+
+	banner := app.StartupSummary().String()
+
+	assert.Contains(t, banner, "via started at [:3000]")
+	assert.Contains(t, banner, "0 routes")
+	assert.Contains(t, banner, "via_test.noopPlugin")
+}
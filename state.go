@@ -24,8 +24,9 @@ import (
 // The optional `via:"name,init=value"` tag mirrors Signal[T]: either part
 // is optional, and init=… is decoded into the field at bind time.
 type StateTab[T any] struct {
-	val T
-	key string
+	val   T
+	key   string
+	saver *stateSaver[T] // set by Persist; nil otherwise
 }
 
 // Read returns the current value. The ctx is unused today but kept so
@@ -79,6 +80,9 @@ func (s *StateTab[T]) Update(ctx *Ctx, fn func(T) (T, error)) error {
 	}
 	s.val = next
 	ctx.markStateDirty()
+	if s.saver != nil {
+		s.saver.schedule(next)
+	}
 	return nil
 }
 
@@ -122,34 +122,36 @@ func (a *StateApp[T]) Update(ctx *Ctx, fn func(T) (T, error)) error {
 		if err != nil {
 			return err
 		}
-		// Success. Set the SHARED L1 cell synchronously so every session/tab on
-		// THIS pod sees the new value immediately (single-pod byte-for-byte);
-		// peers converge via the changes feed.
-		if vc := app.valCellFor(a.wireKey); vc != nil {
-			vc.mu.Lock()
-			if newRev > vc.l1Rev {
-				vc.l1 = next
-				vc.l1Rev = newRev
-			}
-			vc.mu.Unlock()
-		}
-		// Append a value-less liveness hint so peers (and this pod's tailer)
-		// re-pull — UNLESS the action is silent (sync off), which must suppress
-		// all fan-out for this write. The value still persists in the Store; a
-		// later loud write or the reconcile sweep propagates it. Best-effort:
-		// correctness rests on the Store, not on this Append being delivered.
-		if !ctx.silent.Load() {
-			if hint, mErr := json.Marshal(change{Key: a.wireKey, Rev: newRev}); mErr == nil {
-				_, _ = app.backplane.Append(bg, changesKey, hint)
-			}
-		}
-		ctx.markStateDirty()
-		app.broadcastRender(ctx, nil, a.wireKey)
+		a.applied(ctx, next, newRev)
 		return nil
 	}
 	return errCASExhausted
 }
 
+// applied publishes a successful CAS of next at newRev: the shared L1 cell,
+// the cross-pod liveness hint, and the local re-render fan-out.
+func (a *StateApp[T]) applied(ctx *Ctx, next T, newRev Rev) {
+	app := ctx.app
+	// Set the SHARED L1 cell synchronously so every session/tab on THIS pod
+	// sees the new value immediately (single-pod byte-for-byte); peers
+	// converge via the changes feed.
+	if vc := app.valCellFor(a.wireKey); vc != nil {
+		vc.advance(next, newRev)
+	}
+	// Append a value-less liveness hint so peers (and this pod's tailer)
+	// re-pull — UNLESS the action is silent (sync off), which must suppress
+	// all fan-out for this write. The value still persists in the Store; a
+	// later loud write or the reconcile sweep propagates it. Best-effort:
+	// correctness rests on the Store, not on this Append being delivered.
+	if !ctx.silent.Load() {
+		if hint, mErr := json.Marshal(change{Key: a.wireKey, Rev: newRev}); mErr == nil {
+			_, _ = app.backplane.Append(app.backplaneCtx, changesKey, hint)
+		}
+	}
+	ctx.markStateDirty()
+	app.broadcastRender(ctx, nil, a.wireKey)
+}
+
 // Text returns a static text node carrying the current value. Accepts either
 // *Ctx (action handlers) or *CtxR (View).
 func (a *StateApp[T]) Text(rc readCtx) h.H { return h.Textf("%v", a.Read(rc)) }
@@ -126,23 +126,30 @@ func (s *StateSess[T]) Update(ctx *Ctx, fn func(T) (T, error)) error {
 		if err != nil {
 			return err
 		}
-		// Success: set this session's L1 synchronously (sync RYW for every tab
-		// on this session, this pod) and record the rev for the monotone gate.
-		sess.data.Store(s.wireKey, next)
-		sess.advanceRev(s.wireKey, newRev)
-		// Liveness hint carrying the FULL sid — suppressed for a silent action.
-		if !ctx.silent.Load() {
-			if hint, mErr := json.Marshal(change{Sid: sess.id, Key: s.wireKey, Rev: newRev}); mErr == nil {
-				_, _ = app.backplane.Append(bg, changesKey, hint)
-			}
-		}
-		ctx.markStateDirty()
-		app.broadcastRender(ctx, sess, s.wireKey)
+		s.applied(ctx, sess, next, newRev)
 		return nil
 	}
 	return errCASExhausted
 }
 
+// applied publishes a successful CAS of next at newRev: the session's L1,
+// the cross-pod liveness hint, and the re-render of the session's tabs.
+func (s *StateSess[T]) applied(ctx *Ctx, sess *session, next T, newRev Rev) {
+	app := ctx.app
+	// Set this session's L1 synchronously (sync RYW for every tab on this
+	// session, this pod) and record the rev for the monotone gate.
+	sess.data.Store(s.wireKey, next)
+	sess.advanceRev(s.wireKey, newRev)
+	// Liveness hint carrying the FULL sid — suppressed for a silent action.
+	if !ctx.silent.Load() {
+		if hint, mErr := json.Marshal(change{Sid: sess.id, Key: s.wireKey, Rev: newRev}); mErr == nil {
+			_, _ = app.backplane.Append(app.backplaneCtx, changesKey, hint)
+		}
+	}
+	ctx.markStateDirty()
+	app.broadcastRender(ctx, sess, s.wireKey)
+}
+
 // Text returns a static text node carrying the current value. Accepts
 // either *Ctx (action handlers) or *CtxR (View).
 func (s *StateSess[T]) Text(rc readCtx) h.H { return h.Textf("%v", s.Read(rc)) }
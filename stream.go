@@ -1,6 +1,11 @@
 package via
 
 import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"math/rand/v2"
+	"slices"
 	"sync/atomic"
 	"time"
 )
@@ -15,6 +20,104 @@ type Ticker struct {
 	interval atomic.Int64  // nanoseconds; read by the goroutine after each reset
 	reset    chan struct{} // wakes the goroutine when interval changes
 	stop     chan struct{} // closed by Stop to wake the goroutine for exit
+
+	ctx     *Ctx
+	tab     string // owning Ctx id, for Status
+	started time.Time
+	ticks   atomic.Uint64
+	lastRun atomic.Int64           // UnixNano of the latest tick start; 0 before the first
+	lastErr atomic.Pointer[string] // recovered panic of the latest failing tick
+
+	whileHidden atomic.Bool
+	bound       atomic.Pointer[boolBinding]
+	gated       atomic.Bool // the latest tick was skipped by BindTo
+}
+
+// BoolReader is any reactive bool handle — Signal[bool], StateTab[bool],
+// StateSess[bool], StateApp[bool] and their Bool-specialized forms — as
+// accepted by [Ticker.BindTo].
+type BoolReader interface {
+	Read(readCtx) bool
+}
+
+type boolBinding struct{ src BoolReader }
+
+// Ticker states reported by [TickerStatus].
+const (
+	TickerRunning = "running"
+	TickerPaused  = "paused"
+	TickerStopped = "stopped" // Stop was called or the tab was disposed; see Ctx.Tickers
+)
+
+// TickerStatus is a point-in-time snapshot of a [Ticker], for debugging a
+// dashboard whose updates stalled: a stopped or paused ticker, a stretched
+// interval, a LastRun far in the past, or a callback that keeps panicking.
+type TickerStatus struct {
+	Tab       string        // id of the owning tab
+	State     string        // TickerRunning, TickerPaused or TickerStopped
+	Interval  time.Duration // current cadence, after any SetInterval
+	Started   time.Time     // when Stream started the ticker
+	LastRun   time.Time     // start of the latest tick; zero before the first
+	Ticks     uint64        // callbacks run so far, failed ones included
+	LastError string        // panic of the latest failing tick, "" if none has failed
+}
+
+// Status reports the ticker's current state. The zero TickerStatus for a
+// nil ticker.
+func (t *Ticker) Status() TickerStatus {
+	if t == nil {
+		return TickerStatus{}
+	}
+	st := TickerStatus{
+		Tab:      t.tab,
+		State:    TickerRunning,
+		Interval: time.Duration(t.interval.Load()),
+		Started:  t.started,
+		Ticks:    t.ticks.Load(),
+	}
+	switch {
+	case t.stopped.Load():
+		st.State = TickerStopped
+	case t.paused.Load() || t.hiddenGate() || t.gated.Load():
+		st.State = TickerPaused
+	}
+	if at := t.lastRun.Load(); at != 0 {
+		st.LastRun = time.Unix(0, at)
+	}
+	if msg := t.lastErr.Load(); msg != nil {
+		st.LastError = *msg
+	}
+	return st
+}
+
+// Tickers reports every ticker [Stream] started on this tab that has not
+// been stopped, in start order. A stopped ticker's own Status still
+// reports TickerStopped.
+func (ctx *Ctx) Tickers() []TickerStatus {
+	if ctx == nil {
+		return nil
+	}
+	ctx.mu.Lock()
+	tickers := slices.Clone(ctx.tickers)
+	ctx.mu.Unlock()
+	out := make([]TickerStatus, len(tickers))
+	for i, t := range tickers {
+		out[i] = t.Status()
+	}
+	return out
+}
+
+// Tickers reports the tickers of every live tab on this pod, grouped by
+// tab id and in start order within a tab — the app-wide view behind an
+// ops page asking which live updates are stuck.
+func (a *App) Tickers() []TickerStatus {
+	ctxs := a.snapshotContexts()
+	slices.SortFunc(ctxs, func(x, y *Ctx) int { return cmp.Compare(x.id, y.id) })
+	var out []TickerStatus
+	for _, c := range ctxs {
+		out = append(out, c.Tickers()...)
+	}
+	return out
 }
 
 // Pause stops further callbacks from firing until Resume is called.
@@ -34,6 +137,52 @@ func (t *Ticker) Resume() {
 	t.paused.Store(false)
 }
 
+// PauseWhileHidden skips ticks while the tab is in the background
+// (document.visibilityState "hidden") and picks up again on the first
+// tick after it becomes visible, so a dashboard nobody is looking at
+// costs nothing. The first call on a tab installs a small visibility
+// listener in the page, which reports changes back over a beacon.
+// Independent of Pause: a paused ticker stays paused when shown.
+func (t *Ticker) PauseWhileHidden() {
+	if t == nil || t.ctx == nil {
+		return
+	}
+	t.whileHidden.Store(true)
+	ctx := t.ctx
+	ctx.visOnce.Do(func() {
+		ctx.ExecScript(`(()=>{if(window.__viaVis)return;window.__viaVis=1;` +
+			`function s(){navigator.sendBeacon('` + template.JSEscapeString(ctx.app.cfg.basePath) + `/_sse/visibility','` +
+			template.JSEscapeString(ctx.id) + ` '+document.visibilityState)}` +
+			`document.addEventListener('visibilitychange',s);s()})()`)
+	})
+}
+
+// BindTo gates the ticker on a reactive bool: each tick first reads src
+// and is skipped while it is false, so a "live" toggle the user flips
+// drives the stream with no Pause/Resume bookkeeping in the action:
+//
+//	t := via.Stream(ctx, time.Second, p.refresh)
+//	t.BindTo(&p.Live) // Live via.Signal[bool]
+//
+// src is read on the tick goroutine under the tab's action lock. A nil
+// src removes the binding. Independent of Pause and PauseWhileHidden —
+// a tick runs only when none of them holds it back.
+func (t *Ticker) BindTo(src BoolReader) {
+	if t == nil {
+		return
+	}
+	if src == nil {
+		t.bound.Store(nil)
+		t.gated.Store(false)
+		return
+	}
+	t.bound.Store(&boolBinding{src: src})
+}
+
+func (t *Ticker) hiddenGate() bool {
+	return t.whileHidden.Load() && t.ctx != nil && t.ctx.hidden.Load()
+}
+
 // Stop terminates the ticker permanently. After Stop returns, no further
 // callbacks fire and the underlying goroutine exits — Pause/Resume on a
 // stopped ticker are no-ops. Idempotent; calling Stop on an already-
@@ -50,6 +199,9 @@ func (t *Ticker) Stop() {
 		return
 	}
 	close(t.stop)
+	t.ctx.mu.Lock()
+	t.ctx.tickers = slices.DeleteFunc(t.ctx.tickers, func(o *Ticker) bool { return o == t })
+	t.ctx.mu.Unlock()
 }
 
 // SetInterval changes the tick cadence to d. The new interval takes
@@ -66,6 +218,66 @@ func (t *Ticker) SetInterval(d time.Duration) {
 	}
 }
 
+// StreamOption tunes how [Stream] schedules its ticks.
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	fixedDelay    bool
+	fixedDelaySet bool
+	jitter        time.Duration
+	maxCatchUp    int
+	catchUpSet    bool
+}
+
+// StreamFixedDelay schedules each tick interval after the previous
+// callback returned, instead of on the fixed-rate grid. A slow callback
+// then stretches the cadence rather than being followed by a late tick —
+// the right choice when ticks must never bunch up. Panics alongside
+// StreamCatchUp, which only applies to the fixed-rate grid.
+func StreamFixedDelay() StreamOption {
+	return func(c *streamConfig) {
+		if c.catchUpSet {
+			panic("via: StreamFixedDelay conflicts with StreamCatchUp")
+		}
+		c.fixedDelay, c.fixedDelaySet = true, true
+	}
+}
+
+// StreamJitter delays every tick by a random amount in [0, d), so the
+// tickers of many tabs started together don't all fire in the same
+// instant. The fixed-rate grid itself does not drift: jitter is applied
+// per tick, never accumulated. Negative d panics.
+func StreamJitter(d time.Duration) StreamOption {
+	if d < 0 {
+		panic(fmt.Sprintf("via: StreamJitter: must be >= 0, got %v", d))
+	}
+	return func(c *streamConfig) { c.jitter = d }
+}
+
+// StreamCatchUp lets a fixed-rate stream run up to n missed ticks back
+// to back after a callback overran its interval, before skipping the rest
+// to rejoin the grid. The default, 0, skips every missed tick, so an
+// overrun never causes a burst. Use it when each tick must be counted,
+// e.g. sampling at a nominal rate. Negative n panics.
+func StreamCatchUp(n int) StreamOption {
+	if n < 0 {
+		panic(fmt.Sprintf("via: StreamCatchUp: must be >= 0, got %d", n))
+	}
+	return func(c *streamConfig) {
+		if c.fixedDelaySet {
+			panic("via: StreamCatchUp conflicts with StreamFixedDelay")
+		}
+		c.maxCatchUp, c.catchUpSet = n, true
+	}
+}
+
+func (c *streamConfig) jitterDelay() time.Duration {
+	if c.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(c.jitter)))
+}
+
 // Stream runs fn on a ticker until ctx is disposed. Use it in OnConnect
 // to drive periodic UI updates without managing a goroutine and ticker by
 // hand:
@@ -86,47 +298,125 @@ func (t *Ticker) SetInterval(d time.Duration) {
 // Signal/State writes don't race with concurrent action POSTs or with
 // other Stream callbacks on the same Ctx.
 //
+// Ticks are fixed-rate by default: they stay on the interval grid set at
+// start without drifting, and ticks missed while fn overran are skipped.
+// [StreamFixedDelay], [StreamJitter] and [StreamCatchUp] change that.
+// Each tick reports its lateness against the schedule as the
+// via.stream.lag histogram and its callback time as via.stream.duration;
+// an overrun that skipped ticks counts as via.stream.skipped (all labelled
+// by route).
+//
 // The returned [*Ticker] lets the caller pause, resume, or change the
 // cadence at runtime. It is safe to ignore the return value if those
 // controls are not needed.
-func Stream(ctx *Ctx, interval time.Duration, fn func(ctx *Ctx, t time.Time)) *Ticker {
+func Stream(ctx *Ctx, interval time.Duration, fn func(ctx *Ctx, t time.Time), opts ...StreamOption) *Ticker {
 	if ctx == nil || interval <= 0 || fn == nil {
 		return nil
 	}
+	var cfg streamConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	t := &Ticker{
-		reset: make(chan struct{}, 1),
-		stop:  make(chan struct{}),
+		reset:   make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		ctx:     ctx,
+		tab:     ctx.id,
+		started: ctx.app.now(),
 	}
 	t.interval.Store(int64(interval))
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.doneChan:
-				return
-			case <-t.stop:
-				return
-			case <-t.reset:
-				ticker.Reset(time.Duration(t.interval.Load()))
-			case now := <-ticker.C:
-				if t.paused.Load() {
-					continue
+	ctx.mu.Lock()
+	ctx.tickers = append(ctx.tickers, t)
+	ctx.mu.Unlock()
+	// Arm the clock ticker before returning so the first tick is scheduled
+	// from the Stream call, not from whenever the goroutine gets to run.
+	clk := ctx.app.Clock()
+	due := clk.Now().Add(interval)
+	target := due.Add(cfg.jitterDelay())
+	ticker := clk.NewTicker(max(target.Sub(clk.Now()), 1))
+	go t.run(ctx, &cfg, interval, fn, ticker, due, target)
+	return t
+}
+
+// run is the Stream goroutine. The clock ticker is used one-shot: every
+// tick re-arms it for the next scheduled time, which is what lets one
+// loop serve fixed-rate, fixed-delay and jittered schedules alike. due is
+// the next tick's slot on the schedule, target the same slot with its
+// jitter applied.
+func (t *Ticker) run(ctx *Ctx, cfg *streamConfig, iv time.Duration, fn func(*Ctx, time.Time),
+	ticker ClockTicker, due, target time.Time) {
+	clk := ctx.app.Clock()
+	m := ctx.app.metricsOrNoop()
+	route := ""
+	if ctx.desc != nil {
+		route = ctx.desc.route
+	}
+	defer ticker.Stop()
+	rearm := func() {
+		target = due.Add(cfg.jitterDelay())
+		ticker.Reset(max(target.Sub(clk.Now()), 1))
+	}
+	tick := func(at time.Time) {
+		began := clk.Now()
+		m.Histogram("via.stream.lag", max(began.Sub(target), 0).Seconds(), "route", route)
+		streamTick(ctx, t, at, fn)
+		m.Histogram("via.stream.duration", clk.Now().Sub(began).Seconds(), "route", route)
+	}
+	for {
+		select {
+		case <-ctx.doneChan:
+			t.stopped.Store(true)
+			return
+		case <-t.stop:
+			return
+		case <-t.reset:
+			iv = time.Duration(t.interval.Load())
+			due = clk.Now().Add(iv)
+			rearm()
+		case now := <-ticker.Chan():
+			if !t.paused.Load() && !t.hiddenGate() {
+				tick(now)
+			}
+			if cfg.fixedDelay {
+				due = clk.Now().Add(iv)
+				rearm()
+				continue
+			}
+			due = due.Add(iv)
+			for caught := 0; !due.After(clk.Now()); caught++ {
+				gated := t.paused.Load() || t.hiddenGate()
+				if caught >= cfg.maxCatchUp || gated || t.stopped.Load() || ctx.Disposed() {
+					missed := clk.Now().Sub(due)/iv + 1
+					due = due.Add(missed * iv)
+					if !gated {
+						m.Counter("via.stream.skipped", "route", route)
+					}
+					break
 				}
-				streamTick(ctx, now, fn)
+				target = due
+				tick(due)
+				due = due.Add(iv)
 			}
+			rearm()
 		}
-	}()
-	return t
+	}
 }
 
 // streamTick runs one fn invocation under actionMu and flushes any
 // dirty state before releasing the lock — same exclusivity as an
 // action handler, so fn's reads/writes don't race with a concurrent
-// POST or another Stream callback on the same Ctx.
-func streamTick(ctx *Ctx, t time.Time, fn func(*Ctx, time.Time)) {
+// POST or another Stream callback on the same Ctx. A panic is logged
+// like recoverLog does and recorded on tk for Status.
+func streamTick(ctx *Ctx, tk *Ticker, t time.Time, fn func(*Ctx, time.Time)) {
 	ctx.actionMu.Lock()
 	defer ctx.actionMu.Unlock()
+	if b := tk.bound.Load(); b != nil {
+		live := b.src.Read(ctx)
+		tk.gated.Store(!live)
+		if !live {
+			return
+		}
+	}
 	ctx.silent.Store(false)
 	defer func() {
 		if ctx.silent.Load() {
@@ -135,6 +425,14 @@ func streamTick(ctx *Ctx, t time.Time, fn func(*Ctx, time.Time)) {
 		}
 		flushDirty(ctx)
 	}()
-	defer recoverLog(ctx, "Stream callback")
+	tk.ticks.Add(1)
+	tk.lastRun.Store(t.UnixNano())
+	defer func() {
+		if rec := recover(); rec != nil {
+			msg := fmt.Sprint(rec)
+			tk.lastErr.Store(&msg)
+			ctx.app.logErr(ctx, "Stream callback panicked: %v", rec)
+		}
+	}()
 	fn(ctx, t)
 }
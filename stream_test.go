@@ -2,6 +2,7 @@ package via_test
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -92,9 +93,7 @@ func TestStream_stopsWhenCtxDone(t *testing.T) {
 	via.Mount[clockPage](app, "/")
 
 	tc := vt.NewClient(t, server, "/")
-	_, cancel := tc.SSE()
-	time.Sleep(120 * time.Millisecond)
-	cancel()
+	tc.WaitForText("<p>3</p>", 2*time.Second)
 
 	resp, err := server.Client().Post(server.URL+"/_sse/close", "text/plain", strings.NewReader(tc.TabID()))
 	require.NoError(t, err)
@@ -358,3 +357,201 @@ func TestTicker_stopIsIdempotent(t *testing.T) {
 			"a second Stop must not double-close the channel")
 	}
 }
+
+type tickerStatusPage struct{}
+
+func (p *tickerStatusPage) OnConnect(ctx *via.Ctx) error {
+	via.Stream(ctx, 5*time.Millisecond, func(*via.Ctx, time.Time) {})
+	via.Stream(ctx, 5*time.Millisecond, func(*via.Ctx, time.Time) { panic("feed offline") })
+	via.Stream(ctx, time.Hour, func(*via.Ctx, time.Time) {}).Pause()
+	return nil
+}
+
+func (p *tickerStatusPage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestTickers_reportStateRunsAndLastError(t *testing.T) {
+	t.Parallel()
+
+	app, server, _ := newLoggedApp(t, via.LogError)
+	via.Mount[tickerStatusPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	_, cancel := tc.SSEReady()
+	defer cancel()
+
+	var got []via.TickerStatus
+	require.Eventually(t, func() bool {
+		got = app.Tickers()
+		return len(got) == 3 && got[0].Ticks >= 2 && got[1].Ticks >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	for _, st := range got {
+		assert.Equal(t, tc.TabID(), st.Tab)
+		assert.False(t, st.Started.IsZero())
+	}
+	assert.Equal(t, via.TickerRunning, got[0].State)
+	assert.Empty(t, got[0].LastError)
+	assert.WithinDuration(t, time.Now(), got[0].LastRun, time.Second)
+
+	assert.Equal(t, "feed offline", got[1].LastError, "a panicking tick is recorded, and keeps ticking")
+
+	assert.Equal(t, via.TickerPaused, got[2].State)
+	assert.Equal(t, time.Hour, got[2].Interval)
+	assert.Zero(t, got[2].Ticks)
+	assert.True(t, got[2].LastRun.IsZero())
+}
+
+func TestTickers_dropAStoppedTicker(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[tickerStopPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSE()
+	defer cancel()
+	vt.AwaitFrame(t, frames, 2*time.Second, `id="n"`)
+
+	require.Len(t, app.Tickers(), 1)
+	require.Equal(t, http.StatusOK, tc.Action("Halt").Fire())
+
+	assert.Empty(t, app.Tickers(), "a stopped ticker must not linger in the tab's list")
+	var nilTicker *via.Ticker
+	assert.Zero(t, nilTicker.Status())
+}
+
+type tickerGatePage struct {
+	Live via.Signal[bool]
+
+	bound, hidden atomic.Int32
+}
+
+func (p *tickerGatePage) OnConnect(ctx *via.Ctx) error {
+	via.Stream(ctx, 5*time.Millisecond, func(*via.Ctx, time.Time) { p.bound.Add(1) }).BindTo(&p.Live)
+	via.Stream(ctx, 5*time.Millisecond, func(*via.Ctx, time.Time) { p.hidden.Add(1) }).PauseWhileHidden()
+	return nil
+}
+
+func (p *tickerGatePage) GoLive(ctx *via.Ctx) { p.Live.Write(ctx, true) }
+
+func (p *tickerGatePage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestTicker_bindToSkipsTicksWhileSourceIsFalse(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[tickerGatePage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	_, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Eventually(t, func() bool {
+		got := app.Tickers()
+		return len(got) == 2 && got[1].Ticks >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+	got := app.Tickers()
+	assert.Equal(t, via.TickerPaused, got[0].State, "bound to a false signal")
+	assert.Zero(t, got[0].Ticks)
+
+	require.Equal(t, http.StatusOK, tc.Action("GoLive").Fire())
+	require.Eventually(t, func() bool { return app.Tickers()[0].Ticks >= 2 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, via.TickerRunning, app.Tickers()[0].State)
+}
+
+func TestTicker_pauseWhileHiddenFollowsTabVisibility(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[tickerGatePage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSE()
+	defer cancel()
+	vt.AwaitFrame(t, frames, 2*time.Second, "/_sse/visibility", "visibilitychange")
+
+	require.Equal(t, http.StatusOK, tc.SetHidden(true))
+	assert.Eventually(t, func() bool { return app.Tickers()[1].State == via.TickerPaused },
+		2*time.Second, 5*time.Millisecond)
+	before := app.Tickers()[1].Ticks
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, before, app.Tickers()[1].Ticks, "no ticks while hidden")
+
+	require.Equal(t, http.StatusOK, tc.SetHidden(false))
+	assert.Eventually(t, func() bool { return app.Tickers()[1].Ticks > before },
+		2*time.Second, 5*time.Millisecond)
+}
+
+type streamSchedulePage struct {
+	Mode string `query:"mode"`
+}
+
+func (p *streamSchedulePage) OnConnect(ctx *via.Ctx) error {
+	var opts []via.StreamOption
+	switch p.Mode {
+	case "catchup":
+		opts = append(opts, via.StreamCatchUp(5))
+	case "delay":
+		opts = append(opts, via.StreamFixedDelay())
+	}
+	via.Stream(ctx, time.Second, func(*via.Ctx, time.Time) {}, opts...)
+	return nil
+}
+
+func (p *streamSchedulePage) View(ctx *via.CtxR) h.H { return h.Div() }
+
+func TestStream_schedulesMissedTicksPerOption(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		mode        string
+		wantTicks   uint64
+		wantSkipped bool
+		nextAt      time.Duration // from the tick, to fire the next one
+	}{
+		{"fixed rate skips missed ticks", "", 1, true, 500 * time.Millisecond},
+		{"catch-up runs missed ticks", "catchup", 3, false, 500 * time.Millisecond},
+		{"fixed delay restarts from the tick", "delay", 1, false, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &captureMetrics{}
+			clk := vt.NewClock(time.Unix(0, 0))
+			app := via.New(via.WithClock(clk), via.WithMetrics(m))
+			srv := vt.Serve(t, app)
+			via.Mount[streamSchedulePage](app, "/")
+			tc := vt.NewClient(t, srv, "/?mode="+tt.mode)
+			_, cancel := tc.SSEReady()
+			defer cancel()
+			require.Eventually(t, func() bool { return len(app.Tickers()) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+			clk.Advance(3500 * time.Millisecond)
+			require.Eventually(t, func() bool { return app.Tickers()[0].Ticks == tt.wantTicks },
+				2*time.Second, 5*time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, tt.wantTicks, app.Tickers()[0].Ticks)
+
+			m.mu.Lock()
+			assert.Equal(t, tt.wantSkipped, slices.Contains(m.counters, "via.stream.skipped:route,/"))
+			assert.Contains(t, m.histograms, "via.stream.lag:route,/")
+			assert.Contains(t, m.histograms, "via.stream.duration:route,/")
+			m.mu.Unlock()
+
+			clk.Advance(tt.nextAt - time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, tt.wantTicks, app.Tickers()[0].Ticks, "not due yet")
+			clk.Advance(time.Millisecond)
+			assert.Eventually(t, func() bool { return app.Tickers()[0].Ticks == tt.wantTicks+1 },
+				2*time.Second, 5*time.Millisecond)
+		})
+	}
+}
+
+func TestStream_invalidScheduleOptionsPanic(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { via.StreamJitter(-time.Second) })
+	assert.Panics(t, func() { via.StreamCatchUp(-1) })
+}
@@ -0,0 +1,115 @@
+package via
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/go-via/via/h"
+)
+
+// Rule is one check [Validate] runs against a signal's current value.
+// Build rules with [Signal.Check] or [Signal.Required].
+type Rule struct {
+	key   string
+	check func() string
+}
+
+// FieldErrors maps the wire key of each signal that failed validation to
+// its message. An empty map means every rule passed.
+type FieldErrors map[string]string
+
+// Check returns a Rule that validates this signal with fn, which returns
+// the message to show for a bad value, or "" for a good one.
+//
+//	p.Age.Check(func(n int) string {
+//	    if n < 18 {
+//	        return "You must be 18 or over"
+//	    }
+//	    return ""
+//	})
+func (s *Signal[T]) Check(fn func(T) string) Rule {
+	return Rule{key: s.key, check: func() string { return fn(s.val) }}
+}
+
+// Required returns a Rule that fails with msg while the signal holds its
+// type's zero value — an empty string, 0 or false.
+func (s *Signal[T]) Required(msg string) Rule {
+	return s.Check(func(v T) string {
+		if rv := reflect.ValueOf(&v).Elem(); rv.IsZero() ||
+			rv.Kind() == reflect.String && strings.TrimSpace(rv.String()) == "" {
+			return msg
+		}
+		return ""
+	})
+}
+
+// ErrorText renders the signal's validation message: a paragraph that
+// shows the message from the last [Validate] of this signal and hides
+// while there is none. Place it next to the input.
+//
+//	h.Input(p.Email.Bind()), p.Email.ErrorText()
+func (s *Signal[T]) ErrorText() h.H {
+	sig := errorSignal(s.key)
+	seed, _ := json.Marshal(map[string]string{sig: ""})
+	return h.P(
+		h.Class("via-field-error"),
+		h.Aria("live", "polite"),
+		h.Data("signals__ifmissing", string(seed)),
+		h.Data("show", "$"+sig),
+		h.Data("text", "$"+sig),
+	)
+}
+
+// Validate runs rules against the signals' current values — in an action,
+// the values the browser just posted — and reports the failures. Each
+// signal a rule names has its [Signal.ErrorText] set to the message of
+// its first failing rule, or cleared once all its rules pass. Signals no
+// rule names keep their messages, so a per-field change handler can
+// validate just that field:
+//
+//	func (p *Signup) CheckEmail(ctx *via.Ctx) error {
+//	    via.Validate(ctx, p.emailRules()...)
+//	    return nil
+//	}
+//
+//	func (p *Signup) Save(ctx *via.Ctx) error {
+//	    if errs := via.Validate(ctx, p.rules()...); len(errs) > 0 {
+//	        return nil // the messages are already on the page
+//	    }
+//	    ...
+//	}
+//
+// Messages travel as client-only signals, so they cost no re-render and
+// are never posted back.
+func Validate(ctx *Ctx, rules ...Rule) FieldErrors {
+	if ctx == nil {
+		panic("via: Validate called with nil *Ctx")
+	}
+	errs := FieldErrors{}
+	push := make(map[string]any, len(rules))
+	for _, r := range rules {
+		if r.check == nil {
+			continue
+		}
+		if _, failed := errs[r.key]; failed {
+			continue
+		}
+		if msg := r.check(); msg != "" {
+			errs[r.key] = msg
+			push[errorSignal(r.key)] = msg
+			continue
+		}
+		push[errorSignal(r.key)] = ""
+	}
+	ctx.Patch().Signals(push)
+	return errs
+}
+
+// errorSignal names the client-only signal holding key's validation
+// message. The leading underscore keeps Datastar from posting it back;
+// the dots of a child's qualified key are flattened so the name stays a
+// single top-level signal.
+func errorSignal(key string) string {
+	return "_err_" + strings.ReplaceAll(key, ".", "_")
+}
@@ -0,0 +1,110 @@
+package via_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatePage struct {
+	Email via.SignalStr
+	Age   via.Signal[int]
+	Saved via.StateTabStr
+}
+
+func (p *validatePage) emailRules() []via.Rule {
+	return []via.Rule{
+		p.Email.Required("Email is required"),
+		p.Email.Check(func(s string) string {
+			if !strings.Contains(s, "@") {
+				return "Enter an email address"
+			}
+			return ""
+		}),
+	}
+}
+
+func (p *validatePage) CheckEmail(ctx *via.Ctx) error {
+	via.Validate(ctx, p.emailRules()...)
+	return nil
+}
+
+func (p *validatePage) Save(ctx *via.Ctx) error {
+	rules := append(p.emailRules(), p.Age.Check(func(n int) string {
+		if n < 18 {
+			return "You must be 18 or over"
+		}
+		return ""
+	}))
+	if errs := via.Validate(ctx, rules...); len(errs) > 0 {
+		return nil
+	}
+	p.Saved.Write(ctx, p.Email.Read(ctx))
+	return nil
+}
+
+func (p *validatePage) View(ctx *via.CtxR) h.H {
+	return h.Form(on.Submit(p.Save),
+		h.Input(p.Email.Bind(), on.Change(p.CheckEmail)), p.Email.ErrorText(),
+		h.Input(p.Age.Bind()), p.Age.ErrorText(),
+		h.P(h.Text("saved="+p.Saved.Read(ctx))),
+	)
+}
+
+func newValidateClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[validatePage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+func TestSignal_errorTextBindsAClientOnlySignal(t *testing.T) {
+	t.Parallel()
+	tc, _ := newValidateClient(t)
+
+	html := tc.HTML()
+	assert.Contains(t, html, `data-signals__ifmissing="{&#34;_err_email&#34;:&#34;&#34;}"`)
+	assert.Contains(t, html, `data-show="$_err_email" data-text="$_err_email"`)
+	assert.Contains(t, html, `data-text="$_err_age"`)
+}
+
+func TestValidate_pushesTheFirstFailingMessagePerSignal(t *testing.T) {
+	t.Parallel()
+	tc, frames := newValidateClient(t)
+
+	require.Equal(t, 200, tc.Action("Save").WithSignal("email", "").WithSignal("age", 12).Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second,
+		`"_err_age":"You must be 18 or over"`, `"_err_email":"Email is required"`)
+	assert.NotContains(t, frame, "Enter an email address")
+}
+
+func TestValidate_changeHandlerTouchesOnlyItsOwnSignal(t *testing.T) {
+	t.Parallel()
+	tc, frames := newValidateClient(t)
+
+	require.Equal(t, 200, tc.Action("CheckEmail").WithSignal("email", "ada").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, `"_err_email":"Enter an email address"`)
+	assert.NotContains(t, frame, "_err_age")
+
+	require.Equal(t, 200, tc.Action("CheckEmail").WithSignal("email", "ada@example.com").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `"_err_email":""`)
+}
+
+func TestValidate_passingRulesLetTheActionProceed(t *testing.T) {
+	t.Parallel()
+	tc, frames := newValidateClient(t)
+
+	require.Equal(t, 200, tc.Action("Save").WithSignal("email", "ada@example.com").WithSignal("age", 40).Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "saved=ada@example.com", `"_err_age":""`)
+}
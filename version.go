@@ -0,0 +1,161 @@
+package via
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/go-via/via/h"
+)
+
+// ErrStaleVersion is returned by SetIfVersion when the value was changed
+// — by another tab, session or pod — after the version the caller read.
+var ErrStaleVersion = errors.New("via: state changed since it was read")
+
+// ReadVersion is Read plus the value's version: the Store revision it was
+// written at, 0 while unset. Keep the version with an edit (a StateTab
+// field is the natural place) and hand it back to SetIfVersion on save.
+func (a *StateApp[T]) ReadVersion(rc readCtx) (T, Rev) {
+	var zero T
+	if rc == nil {
+		return zero, 0
+	}
+	ctx := rc.rctx()
+	if ctx == nil || ctx.app == nil {
+		return zero, 0
+	}
+	ctx.trackRead(a.wireKey)
+	vc := ctx.app.valCellFor(a.wireKey)
+	if vc == nil {
+		return zero, 0
+	}
+	v, rev := vc.load()
+	t, _ := v.(T)
+	return t, rev
+}
+
+// SetIfVersion writes value only if the stored value is still at version v,
+// returning value and its new version. If anyone wrote in between, nothing
+// is written and it returns the newer value, its version and
+// ErrStaleVersion — so the caller can show "someone else changed this"
+// (see [ConflictNotice]) instead of silently overwriting their edit. v = 0
+// means "only if never set".
+//
+// Panics on nil ctx, like Update.
+func (a *StateApp[T]) SetIfVersion(ctx *Ctx, v Rev, value T) (T, Rev, error) {
+	if ctx == nil {
+		panic("via: StateApp.SetIfVersion called with nil *Ctx")
+	}
+	var zero T
+	if ctx.app == nil {
+		return zero, 0, nil
+	}
+	newRev, err := casIfVersion(ctx.app, valKey(a.wireKey), v, value)
+	if errors.Is(err, ErrCASConflict) {
+		cur, rev, err := loadVersion[T](ctx.app, valKey(a.wireKey))
+		if err != nil {
+			return zero, 0, err
+		}
+		// The conflict proves this pod's L1 may trail the Store: catch it
+		// up so the next render shows what the caller lost to.
+		if vc := ctx.app.valCellFor(a.wireKey); vc != nil {
+			vc.advance(cur, rev)
+		}
+		return cur, rev, ErrStaleVersion
+	}
+	if err != nil {
+		return zero, 0, err
+	}
+	a.applied(ctx, value, newRev)
+	return value, newRev, nil
+}
+
+// ReadVersion is Read plus the value's version: the Store revision it was
+// written at, 0 while unset. See [StateApp.ReadVersion].
+func (s *StateSess[T]) ReadVersion(rc readCtx) (T, Rev) {
+	var zero T
+	if rc == nil {
+		return zero, 0
+	}
+	ctx := rc.rctx()
+	if ctx == nil {
+		return zero, 0
+	}
+	sess := ctx.session.Load()
+	if sess == nil {
+		return zero, 0
+	}
+	ctx.trackRead(s.wireKey)
+	// Revision first: applied stores the value before advancing it, so a
+	// racing write can pair an old version with a new value (a spurious
+	// conflict later) but never a new version with an old value.
+	rev := sess.loadRev(s.wireKey)
+	v, _ := sess.data.Load(s.wireKey)
+	t, _ := v.(T)
+	return t, rev
+}
+
+// SetIfVersion writes value only if the session's stored value is still at
+// version v — i.e. no other tab of the session saved in between. See
+// [StateApp.SetIfVersion].
+func (s *StateSess[T]) SetIfVersion(ctx *Ctx, v Rev, value T) (T, Rev, error) {
+	if ctx == nil {
+		panic("via: StateSess.SetIfVersion called with nil *Ctx")
+	}
+	var zero T
+	sess := ctx.session.Load()
+	if sess == nil || ctx.app == nil {
+		return zero, 0, nil
+	}
+	cellKey := sessValKey(sess.id, s.wireKey)
+	newRev, err := casIfVersion(ctx.app, cellKey, v, value)
+	if errors.Is(err, ErrCASConflict) {
+		cur, rev, err := loadVersion[T](ctx.app, cellKey)
+		if err != nil {
+			return zero, 0, err
+		}
+		if rev > sess.loadRev(s.wireKey) {
+			sess.data.Store(s.wireKey, cur)
+			sess.advanceRev(s.wireKey, rev)
+		}
+		return cur, rev, ErrStaleVersion
+	}
+	if err != nil {
+		return zero, 0, err
+	}
+	s.applied(ctx, sess, value, newRev)
+	return value, newRev, nil
+}
+
+func casIfVersion[T any](app *App, key string, v Rev, value T) (Rev, error) {
+	enc, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	return app.backplane.CAS(app.backplaneCtx, key, v, enc)
+}
+
+func loadVersion[T any](app *App, key string) (T, Rev, error) {
+	var cur T
+	data, rev, ok, err := app.backplane.LoadSnapshot(app.backplaneCtx, key)
+	if err != nil || !ok {
+		return cur, rev, err
+	}
+	_ = json.Unmarshal(data, &cur)
+	return cur, rev, nil
+}
+
+// ConflictNotice renders the standard answer to ErrStaleVersion: an alert
+// saying someone else changed the value, with a button to discard the edit
+// and load theirs and one to save over it. reload and overwrite are the
+// buttons' event attributes, e.g. on.Click of the page's actions.
+//
+//	if p.Conflict.Read(ctx) {
+//	    return via.ConflictNotice(on.Click(p.TakeTheirs), on.Click(p.SaveAnyway))
+//	}
+func ConflictNotice(reload, overwrite h.H) h.H {
+	return h.Div(h.Role("alert"), h.Class("via-conflict"),
+		h.P(h.Text("Someone else changed this while you were editing.")),
+		h.Button(h.Type("button"), h.Text("Load their version"), reload),
+		h.Button(h.Type("button"), h.Text("Keep mine"), overwrite),
+	)
+}
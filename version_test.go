@@ -0,0 +1,142 @@
+package via_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wikiPage edits a shared note with optimistic concurrency: Edit pins the
+// version the draft starts from, Save only lands on that version.
+type wikiPage struct {
+	Note     via.StateApp[string]
+	Base     via.StateTab[via.Rev]
+	Conflict via.StateTab[bool]
+	Draft    via.Signal[string] `via:"draft"`
+}
+
+func (p *wikiPage) Edit(ctx *via.Ctx) error {
+	note, v := p.Note.ReadVersion(ctx)
+	p.Draft.Write(ctx, note)
+	p.Base.Write(ctx, v)
+	return nil
+}
+
+func (p *wikiPage) Save(ctx *via.Ctx) error {
+	_, v, err := p.Note.SetIfVersion(ctx, p.Base.Read(ctx), p.Draft.Read(ctx))
+	p.Base.Write(ctx, v)
+	p.Conflict.Write(ctx, err != nil)
+	return nil
+}
+
+func (p *wikiPage) TakeTheirs(ctx *via.Ctx) error {
+	p.Conflict.Write(ctx, false)
+	return p.Edit(ctx)
+}
+
+func (p *wikiPage) View(ctx *via.CtxR) h.H {
+	var notice h.H
+	if p.Conflict.Read(ctx) {
+		notice = via.ConflictNotice(on.Click(p.TakeTheirs), on.Click(p.Save))
+	}
+	return h.Div(notice, h.P(h.Text("note: "+p.Note.Read(ctx))))
+}
+
+func TestSetIfVersion_secondEditorSeesConflictNotLastWriteWins(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[wikiPage](app, "/")
+
+	alice := vt.NewClient(t, server, "/")
+	bob := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, alice.Action("Edit").Fire())
+	require.Equal(t, 200, bob.Action("Edit").Fire())
+
+	require.Equal(t, 200, alice.Action("Save").WithSignal("draft", "alice's text").Fire())
+
+	frames, cancel := bob.SSEReady()
+	defer cancel()
+	require.Equal(t, 200, bob.Action("Save").WithSignal("draft", "bob's text").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "Someone else changed this")
+	assert.Contains(t, frame, "note: alice&#39;s text", "bob's write did not land")
+
+	// Bob takes Alice's version, then his next save is based on it.
+	require.Equal(t, 200, bob.Action("TakeTheirs").Fire())
+	require.Equal(t, 200, bob.Action("Save").WithSignal("draft", "merged text").Fire())
+	assert.Contains(t, bob.Reload(), "note: merged text")
+	assert.NotContains(t, bob.HTML(), "Someone else changed this")
+}
+
+type draftPage struct {
+	Draft via.StateSess[string]
+	Seen  via.StateTab[via.Rev]
+	Err   via.StateTab[string]
+}
+
+func (p *draftPage) Open(ctx *via.Ctx) error {
+	_, v := p.Draft.ReadVersion(ctx)
+	p.Seen.Write(ctx, v)
+	return nil
+}
+
+func (p *draftPage) Write(ctx *via.Ctx) error {
+	cur, v, err := p.Draft.SetIfVersion(ctx, p.Seen.Read(ctx), ctx.EventArg("text"))
+	p.Seen.Write(ctx, v)
+	if err != nil {
+		p.Err.Write(ctx, err.Error()+"; now "+cur)
+	}
+	return nil
+}
+
+func (p *draftPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.P(h.Text("draft: "+p.Draft.Read(ctx))), h.P(h.Text("err: "+p.Err.Read(ctx))))
+}
+
+func TestStateSessSetIfVersion_guardsAcrossTabsOfOneSession(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[draftPage](app, "/")
+
+	tab1 := vt.NewClient(t, server, "/")
+	tab2 := tab1.Fork("/")
+	require.Equal(t, 200, tab1.Action("Open").Fire())
+	require.Equal(t, 200, tab2.Action("Open").Fire())
+
+	require.Equal(t, 200, tab1.Action("Write").WithArg("text", "one").Fire())
+	require.Equal(t, 200, tab2.Action("Write").WithArg("text", "two").Fire())
+
+	body := tab2.Reload()
+	assert.Contains(t, body, "draft: one")
+
+	// tab1 holds the current version, so its next write lands.
+	frames, cancel := tab1.SSEReady()
+	defer cancel()
+	require.Equal(t, 200, tab1.Action("Write").WithArg("text", "three").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "draft: three")
+}
+
+func TestSetIfVersion_zeroMeansOnlyIfUnset(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[wikiPage](app, "/")
+
+	tc := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, tc.Action("Save").WithSignal("draft", "first").Fire())
+	require.Equal(t, 200, tc.Action("Edit").Fire())
+	require.Equal(t, 200, tc.Action("Save").WithSignal("draft", "second").Fire())
+	assert.Contains(t, tc.Reload(), "note: second")
+
+	other := vt.NewClient(t, server, "/")
+	require.Equal(t, 200, other.Action("Save").WithSignal("draft", "clobber").Fire())
+	body := other.Reload()
+	assert.Contains(t, body, "note: second", "a version-0 save never overwrites an existing value")
+}
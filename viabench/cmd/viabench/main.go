@@ -0,0 +1,55 @@
+// Command viabench load-tests a running Via server: it opens many
+// simulated sessions (page load, SSE stream, actions on an interval) and
+// prints patch latency percentiles, dropped patches and, with -stats,
+// the heap each context costs.
+//
+//	go run github.com/go-via/via/viabench/cmd/viabench \
+//	    -sessions 2000 -duration 1m -stats http://localhost:3000/_viabench \
+//	    http://localhost:3000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/go-via/via/viabench"
+)
+
+func main() {
+	var cfg viabench.Config
+	var actions string
+	flag.StringVar(&cfg.Path, "path", "/", "page each session loads")
+	flag.IntVar(&cfg.Sessions, "sessions", 1000, "simulated sessions")
+	flag.DurationVar(&cfg.Ramp, "ramp", 0, "spread the session opens over this long")
+	flag.DurationVar(&cfg.Duration, "duration", 0, "how long to fire actions once all sessions are open (default 30s)")
+	flag.DurationVar(&cfg.Interval, "interval", 0, "pause between one session's actions (default 1s)")
+	flag.StringVar(&actions, "actions", "", "comma-separated actions to fire (default: every action the page binds)")
+	flag.DurationVar(&cfg.PatchTimeout, "timeout", 0, "wait this long for an action's patch before counting it dropped (default 5s)")
+	flag.StringVar(&cfg.StatsURL, "stats", "", "URL of the server's viabench.StatsHandler, for memory per context")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: viabench [flags] URL")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cfg.URL = flag.Arg(0)
+	if actions != "" {
+		cfg.Actions = strings.Split(actions, ",")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	rep, err := viabench.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(rep)
+}
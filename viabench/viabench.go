@@ -0,0 +1,491 @@
+// Package viabench load-tests a running Via server the way its users do:
+// each simulated session loads a page, holds its SSE stream open and
+// fires the page's actions on an interval. Run reports how long each
+// action took to come back as a patch, how many never did, and — when
+// the server mounts StatsHandler — how much heap each live context costs,
+// the numbers for sizing a deployment.
+//
+//	rep, err := viabench.Run(ctx, viabench.Config{
+//	    URL:      "http://localhost:3000",
+//	    Sessions: 2000,
+//	    Duration: time.Minute,
+//	    StatsURL: "http://localhost:3000/_viabench",
+//	})
+//	fmt.Println(rep)
+//
+// The viabench command (cmd/viabench) wraps Run with flags. Point it at a
+// page whose actions change the view: an action with nothing to patch
+// counts as dropped.
+package viabench
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+)
+
+// Config shapes a Run. URL is required; the rest have defaults.
+type Config struct {
+	URL      string // base URL of the server, e.g. http://localhost:3000
+	Path     string // the page each session loads; default "/"
+	Sessions int    // simulated sessions, each with one tab; default 1000
+	// Ramp spreads the session opens evenly over this long, so a
+	// thousand page loads don't land in the same millisecond; default 0.
+	Ramp time.Duration
+	// Duration is how long the sessions fire actions once all are open;
+	// default 30s.
+	Duration time.Duration
+	// Interval is the pause between one session's actions; default 1s.
+	Interval time.Duration
+	// Actions names the actions to fire, in turn; empty fires every
+	// action the page's bindings post to.
+	Actions []string
+	// PatchTimeout is how long an action's patch may take before it
+	// counts as dropped; default 5s.
+	PatchTimeout time.Duration
+	// StatsURL is where the server mounts StatsHandler. Without it the
+	// report has no memory figure.
+	StatsURL string
+}
+
+// Report is what a Run measured.
+type Report struct {
+	Sessions int // sessions whose page and stream opened
+	Failed   int // sessions that didn't open
+	Actions  int // actions fired
+	Errors   int // actions the server refused or that failed in transit
+	Patches  int // actions answered by a patch on the stream
+	Dropped  int // actions no patch followed within PatchTimeout
+
+	// Patch latency, from firing an action to its patch arriving.
+	P50, P90, P99, Max time.Duration
+
+	// Contexts is how many contexts the server registered for the run,
+	// and BytesPerContext the heap each cost; both need StatsURL.
+	Contexts        int
+	BytesPerContext int64
+}
+
+// String lays the report out for a terminal.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sessions  %d open, %d failed\n", r.Sessions, r.Failed)
+	fmt.Fprintf(&b, "actions   %d fired, %d errors\n", r.Actions, r.Errors)
+	fmt.Fprintf(&b, "patches   %d received, %d dropped\n", r.Patches, r.Dropped)
+	fmt.Fprintf(&b, "latency   p50 %v  p90 %v  p99 %v  max %v\n", r.P50, r.P90, r.P99, r.Max)
+	if r.Contexts > 0 {
+		fmt.Fprintf(&b, "memory    %d contexts, %d KiB each\n", r.Contexts, r.BytesPerContext/1024)
+	}
+	return b.String()
+}
+
+// Stats is the server-side snapshot StatsHandler serves.
+type Stats struct {
+	HeapAlloc  uint64 `json:"heapAlloc"`
+	LiveTabs   int    `json:"liveTabs"`
+	Goroutines int    `json:"goroutines"`
+}
+
+// StatsHandler serves app's heap and live-tab count as JSON, after a
+// garbage collection so the heap is what's live. Mount it for the
+// benchmark's memory figure, and keep it off a public listener:
+//
+//	app.HandleFunc("GET /_viabench", viabench.StatsHandler(app))
+func StatsHandler(app *via.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		runtime.GC()
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Stats{
+			HeapAlloc:  m.HeapAlloc,
+			LiveTabs:   app.LiveTabs(),
+			Goroutines: runtime.NumGoroutine(),
+		})
+	}
+}
+
+// Run opens cfg.Sessions sessions against the server, drives them for
+// cfg.Duration and reports what it saw. It returns early, with what it
+// has, when ctx ends. An error means the run couldn't start: a bad
+// config, or the page unreachable.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return Report{}, err
+	}
+	transport := &http.Transport{MaxIdleConnsPerHost: 256}
+	defer transport.CloseIdleConnections()
+
+	var before Stats
+	if cfg.StatsURL != "" {
+		if before, err = fetchStats(ctx, cfg.StatsURL, transport); err != nil {
+			return Report{}, err
+		}
+	}
+	// The first session finds the actions, and fails fast on a bad URL.
+	first, err := openSession(ctx, cfg, transport)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(cfg.Actions) == 0 {
+		cfg.Actions = boundActions(first.page)
+		if len(cfg.Actions) == 0 {
+			first.close()
+			return Report{}, fmt.Errorf("viabench: the page at %s binds no actions; name some in Config.Actions", cfg.Path)
+		}
+	}
+
+	sessions := []*session{first}
+	var rep Report
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 1; i < cfg.Sessions; i++ {
+		if cfg.Ramp > 0 && !sleep(ctx, cfg.Ramp/time.Duration(cfg.Sessions)) {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := openSession(ctx, cfg, transport)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rep.Failed++
+				return
+			}
+			sessions = append(sessions, s)
+		}()
+	}
+	wg.Wait()
+	defer func() {
+		for _, s := range sessions {
+			s.close()
+		}
+	}()
+	rep.Sessions = len(sessions)
+	if cfg.StatsURL != "" {
+		if after, err := fetchStats(ctx, cfg.StatsURL, transport); err == nil {
+			rep.Contexts = after.LiveTabs - before.LiveTabs
+			if grew := int64(after.HeapAlloc) - int64(before.HeapAlloc); rep.Contexts > 0 && grew > 0 {
+				rep.BytesPerContext = grew / int64(rep.Contexts)
+			}
+		}
+	}
+
+	drive, stop := context.WithTimeout(ctx, cfg.Duration)
+	defer stop()
+	var latencies []time.Duration
+	for i, s := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Stagger the sessions so their actions don't fire in lockstep.
+			if !sleep(drive, cfg.Interval*time.Duration(i)/time.Duration(len(sessions))) {
+				return
+			}
+			got := s.drive(drive, cfg)
+			mu.Lock()
+			rep.Actions += got.actions
+			rep.Errors += got.errors
+			rep.Dropped += got.dropped
+			latencies = append(latencies, got.latencies...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	rep.Patches = len(latencies)
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		rep.P50 = percentile(latencies, 50)
+		rep.P90 = percentile(latencies, 90)
+		rep.P99 = percentile(latencies, 99)
+		rep.Max = latencies[len(latencies)-1]
+	}
+	return rep, nil
+}
+
+func (cfg Config) withDefaults() (Config, error) {
+	if cfg.URL == "" {
+		return cfg, errors.New("viabench: Config.URL is required")
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+	if cfg.Sessions <= 0 {
+		cfg.Sessions = 1000
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = 30 * time.Second
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.PatchTimeout <= 0 {
+		cfg.PatchTimeout = 5 * time.Second
+	}
+	return cfg, nil
+}
+
+// percentile returns the p-th percentile of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// sleep waits d, reporting false if ctx ended first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func fetchStats(ctx context.Context, statsURL string, transport http.RoundTripper) (Stats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statsURL, nil)
+	if err != nil {
+		return Stats{}, fmt.Errorf("viabench: stats: %w", err)
+	}
+	resp, err := (&http.Client{Transport: transport, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return Stats{}, fmt.Errorf("viabench: stats: %w", err)
+	}
+	defer resp.Body.Close()
+	var s Stats
+	if resp.StatusCode != http.StatusOK {
+		return s, fmt.Errorf("viabench: stats: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return s, fmt.Errorf("viabench: stats: %w", err)
+	}
+	return s, nil
+}
+
+// session is one simulated browser: a cookie jar, a loaded page and the
+// tab's stream.
+type session struct {
+	httpc   *http.Client
+	base    string
+	page    string
+	tab     string
+	sig     any
+	patches chan time.Time // arrival of each patch on the stream
+	cancel  func()
+}
+
+// openSession loads cfg.Path in a fresh session and opens its stream,
+// returning once the server's handshake arrives.
+func openSession(ctx context.Context, cfg Config, transport http.RoundTripper) (*session, error) {
+	jar, _ := cookiejar.New(nil)
+	s := &session{
+		httpc:   &http.Client{Jar: jar, Transport: transport, Timeout: 30 * time.Second},
+		base:    cfg.URL,
+		patches: make(chan time.Time, 64),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL+cfg.Path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("viabench: %w", err)
+	}
+	resp, err := s.httpc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("viabench: GET %s: %w", cfg.Path, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("viabench: GET %s: %s", cfg.Path, resp.Status)
+	}
+	s.page = string(body)
+	if s.tab = tabIDFrom(s.page); s.tab == "" {
+		return nil, fmt.Errorf("viabench: no tab id in the page at %s; is it a Via page?", cfg.Path)
+	}
+	s.sig = sigFrom(s.page)
+
+	q, _ := json.Marshal(map[string]any{"via_tab": s.tab})
+	streamCtx, cancel := context.WithCancel(ctx)
+	req, _ = http.NewRequestWithContext(streamCtx, http.MethodGet,
+		cfg.URL+"/_sse?datastar="+url.QueryEscape(string(q)), nil)
+	stream := &http.Client{Jar: jar, Transport: transport} // no timeout — SSE is long-lived
+	resp, err = stream.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("viabench: SSE: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("viabench: SSE: %s", resp.Status)
+	}
+	s.cancel = func() { cancel(); resp.Body.Close() }
+	ready := make(chan struct{})
+	go s.read(resp.Body, ready)
+	select {
+	case <-ready:
+		return s, nil
+	case <-time.After(cfg.PatchTimeout):
+	case <-ctx.Done():
+	}
+	s.cancel()
+	return nil, errors.New("viabench: SSE: no handshake")
+}
+
+func (s *session) close() { s.cancel() }
+
+// heartbeat is the keepalive event the server sends on an idle stream.
+const heartbeat = "event: datastar-patch-signals\ndata: signals {}"
+
+// read splits the stream into events, closing ready at the handshake
+// and noting when each patch arrives.
+func (s *session) read(body io.Reader, ready chan struct{}) {
+	r := bufio.NewReader(body)
+	var event []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == ": ready":
+			close(ready)
+		case line == "":
+			ev := strings.Join(event, "\n")
+			event = event[:0]
+			if strings.HasPrefix(ev, "event: datastar-patch-") && ev != heartbeat {
+				select {
+				case s.patches <- time.Now():
+				default: // the driver is behind; it only needs the next one
+				}
+			}
+		case !strings.HasPrefix(line, "id:") && !strings.HasPrefix(line, ":"):
+			event = append(event, line)
+		}
+	}
+}
+
+// tally is what one session saw while driving.
+type tally struct {
+	actions, errors, dropped int
+	latencies                []time.Duration
+}
+
+// drive fires cfg.Actions in turn every cfg.Interval until ctx ends,
+// timing each one's patch.
+func (s *session) drive(ctx context.Context, cfg Config) (t tally) {
+	for i := 0; ctx.Err() == nil; i++ {
+		// A patch that arrived unprompted (a broadcast) isn't this
+		// action's answer.
+		for len(s.patches) > 0 {
+			<-s.patches
+		}
+		sent := time.Now()
+		status, err := s.fire(ctx, cfg.Actions[i%len(cfg.Actions)])
+		if ctx.Err() != nil {
+			return t
+		}
+		t.actions++
+		if err != nil || status >= 400 {
+			t.errors++
+		} else {
+			select {
+			case at := <-s.patches:
+				t.latencies = append(t.latencies, at.Sub(sent))
+			case <-time.After(cfg.PatchTimeout):
+				t.dropped++
+			case <-ctx.Done():
+				t.actions-- // cut short, neither answered nor dropped
+				return t
+			}
+		}
+		if !sleep(ctx, cfg.Interval-time.Since(sent)) {
+			return t
+		}
+	}
+	return t
+}
+
+// fire posts the action as the page's binding does.
+func (s *session) fire(ctx context.Context, action string) (int, error) {
+	body := map[string]any{"via_tab": s.tab}
+	if s.sig != nil {
+		body["via_sig"] = s.sig
+	}
+	buf, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.base+"/_action/"+action, strings.NewReader(string(buf)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// tabRE picks the via_tab id out of the page's escaped data-signals.
+var tabRE = regexp.MustCompile(`&#34;via_tab&#34;:&#34;([^"&]+)&#34;`)
+
+func tabIDFrom(page string) string {
+	m := tabRE.FindStringSubmatch(page)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// signalsRE picks the data-signals attribute off the rendered <meta>.
+var signalsRE = regexp.MustCompile(`data-signals="([^"]*)"`)
+
+// sigFrom returns the page's via_sig signal (the action signatures of an
+// app using via.WithActionSigning), or nil.
+func sigFrom(page string) any {
+	m := signalsRE.FindStringSubmatch(page)
+	if m == nil {
+		return nil
+	}
+	var sigs map[string]any
+	if json.Unmarshal([]byte(html.UnescapeString(m[1])), &sigs) != nil {
+		return nil
+	}
+	return sigs["via_sig"]
+}
+
+// actionRE picks action names out of the page's bindings.
+var actionRE = regexp.MustCompile(`/_action/([^'"?&/]+)`)
+
+// boundActions lists the actions page posts to, each once, in order.
+func boundActions(page string) []string {
+	var names []string
+	for _, m := range actionRE.FindAllStringSubmatch(page, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
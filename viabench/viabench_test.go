@@ -0,0 +1,109 @@
+package viabench_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/viabench"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type benchPage struct {
+	N via.StateTabNum[int]
+}
+
+func (p *benchPage) Bump(ctx *via.Ctx) error {
+	p.N.Write(ctx, p.N.Read(ctx)+1)
+	return nil
+}
+
+// Idle changes nothing, so no patch answers it.
+func (p *benchPage) Idle(ctx *via.Ctx) error { return nil }
+
+func (p *benchPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.N.Text(ctx), h.Button(h.Text("+"), on.Click(p.Bump)))
+}
+
+func benchServer(t *testing.T) string {
+	t.Helper()
+	app := via.New()
+	via.Mount[benchPage](app, "/")
+	app.HandleFunc("GET /_viabench", viabench.StatsHandler(app))
+	return vt.Serve(t, app).URL
+}
+
+func TestRun_reportsLatencyAndMemoryPerContext(t *testing.T) {
+	t.Parallel()
+	url := benchServer(t)
+
+	rep, err := viabench.Run(context.Background(), viabench.Config{
+		URL:      url,
+		Sessions: 20,
+		Duration: 500 * time.Millisecond,
+		Interval: 50 * time.Millisecond,
+		StatsURL: url + "/_viabench",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, rep.Sessions)
+	assert.Zero(t, rep.Failed)
+	assert.Positive(t, rep.Actions)
+	assert.Zero(t, rep.Errors)
+	assert.Zero(t, rep.Dropped, "every Bump re-renders, so every one is answered")
+	assert.Equal(t, rep.Actions, rep.Patches)
+	assert.Positive(t, rep.P50)
+	assert.LessOrEqual(t, rep.P50, rep.P99)
+	assert.LessOrEqual(t, rep.P99, rep.Max)
+	assert.Equal(t, 20, rep.Contexts)
+	assert.Contains(t, rep.String(), "20 contexts")
+}
+
+func TestRun_countsActionsWithoutAPatchAsDropped(t *testing.T) {
+	t.Parallel()
+
+	rep, err := viabench.Run(context.Background(), viabench.Config{
+		URL:          benchServer(t),
+		Sessions:     2,
+		Duration:     300 * time.Millisecond,
+		Interval:     10 * time.Millisecond,
+		Actions:      []string{"Idle"},
+		PatchTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, rep.Dropped)
+	assert.Zero(t, rep.Patches)
+	assert.Zero(t, rep.Contexts, "no memory figure without StatsURL")
+}
+
+func TestRun_countsRefusedActionsAsErrors(t *testing.T) {
+	t.Parallel()
+
+	rep, err := viabench.Run(context.Background(), viabench.Config{
+		URL:      benchServer(t),
+		Sessions: 2,
+		Duration: 200 * time.Millisecond,
+		Interval: 20 * time.Millisecond,
+		Actions:  []string{"Nope"},
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, rep.Errors)
+	assert.Equal(t, rep.Actions, rep.Errors)
+}
+
+func TestRun_failsOnAPageThatIsNotVia(t *testing.T) {
+	t.Parallel()
+
+	_, err := viabench.Run(context.Background(), viabench.Config{URL: benchServer(t), Path: "/_viabench"})
+	assert.ErrorContains(t, err, "no tab id")
+
+	_, err = viabench.Run(context.Background(), viabench.Config{})
+	assert.ErrorContains(t, err, "URL is required")
+}
@@ -38,7 +38,7 @@ func (p *Login) View(ctx *via.CtxR) h.H {
 			h.Form(
 				h.Label(h.Text("Email"),
 					h.Input(h.Type("email"), p.Email.Bind(), h.Placeholder("you@example.com"),
-						h.AutoComplete("email"), h.Attr("autofocus"), h.Required(),
+						h.AutoComplete("email"), h.Autofocus(), h.Required(),
 						h.If(bad, h.Aria("invalid", "true")))),
 				h.Label(h.Text("Password"),
 					h.Input(h.Type("password"), p.Password.Bind(), h.Placeholder("Your password"),
@@ -93,7 +93,7 @@ func (p *Signup) View(ctx *via.CtxR) h.H {
 			h.Form(
 				h.Label(h.Text("Display name"),
 					h.Input(h.Type("text"), p.Display.Bind(), h.Placeholder("Ada Lovelace"),
-						h.AutoComplete("name"), h.Attr("autofocus"), h.Required(),
+						h.AutoComplete("name"), h.Autofocus(), h.Required(),
 						h.If(bad, h.Aria("invalid", "true")))),
 				h.Label(h.Text("Email"),
 					h.Input(h.Type("email"), p.Email.Bind(), h.Placeholder("you@example.com"),
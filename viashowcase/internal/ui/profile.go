@@ -2,7 +2,6 @@ package ui
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/go-via/via"
@@ -86,14 +85,14 @@ func (p *Profile) apply(ctx *via.Ctx, theme, mode string) {
 		ctx.ExecScript("document.documentElement.setAttribute('data-theme'," +
 			"window.matchMedia('(prefers-color-scheme:dark)').matches?'dark':'light')")
 	} else {
-		ctx.ExecScript(fmt.Sprintf("document.documentElement.setAttribute('data-theme',%q)", mode))
+		ctx.ExecScriptf("document.documentElement.setAttribute('data-theme',%v)", mode)
 	}
-	ctx.ExecScript(fmt.Sprintf(
-		"document.getElementById('_picoThemeLink')?.setAttribute('href',document.getElementById('_picoThemeLink').getAttribute('href').replace(/[^/]*$/,%q))",
-		theme))
+	ctx.ExecScriptf(
+		"document.getElementById('_picoThemeLink')?.setAttribute('href',document.getElementById('_picoThemeLink').getAttribute('href').replace(/[^/]*$/,%v))",
+		theme)
 	// Persist so the choice survives navigation (restored by the head script),
 	// matching how the shell's live picker persists it.
-	ctx.ExecScript(fmt.Sprintf("localStorage.setItem('signal-theme',%q);localStorage.setItem('signal-mode',%q)", theme, mode))
+	ctx.ExecScriptf("localStorage.setItem('signal-theme',%v);localStorage.setItem('signal-mode',%v)", theme, mode)
 }
 
 // Upload saves the posted avatar bytes to Postgres, then redirects back.
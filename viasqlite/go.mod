@@ -0,0 +1,25 @@
+module github.com/go-via/via/viasqlite
+
+go 1.24.0
+
+require (
+	github.com/go-via/via v0.0.0-00010101000000-000000000000
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/CAFxX/httpcompression v0.0.9 // indirect
+	github.com/andybalholm/brotli v1.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-json-experiment/json v0.0.0-20250725192818-e39067aee2d2 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/starfederation/datastar-go v1.0.3 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	golang.org/x/crypto v0.48.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/go-via/via => ../
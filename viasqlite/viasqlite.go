@@ -0,0 +1,402 @@
+// Package viasqlite is an embedded SQLite backend for the Via state
+// backplane, for single-binary deployments that want durable sessions and app
+// state without running NATS or Redis. It implements via.Backplane (plus
+// via.Compactor) with one table for the CAS value Store and one for the
+// EventLog, over a caller-supplied *sql.DB. It is verified against
+// backplanetest.RunConformance.
+//
+// The package depends only on database/sql: pick a driver (modernc.org/sqlite
+// for pure Go, github.com/mattn/go-sqlite3 for cgo) and open the file
+// yourself:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	db, _ := sql.Open("sqlite", "file:via.db?_pragma=busy_timeout(5000)")
+//	bp, _ := viasqlite.Open(db)
+//	app := via.New(via.WithBackplane(bp))
+//
+// Open switches the database to WAL mode, so page renders reading state never
+// block on a writer. Writes from this process are serialised in Go; set a
+// busy timeout in the DSN if another process shares the file. The runtime
+// compacts each event log after a covering snapshot (via.Compactor), and a
+// background checkpoint folds the WAL back into the main file so it does not
+// grow without bound.
+package viasqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+)
+
+// batchSize bounds how many records one Subscribe query reads before
+// delivering them; the rows are drained before any send so a slow subscriber
+// never pins a connection.
+const batchSize = 256
+
+// Backplane is a via.Backplane backed by an embedded SQLite database.
+type Backplane struct {
+	db    *sql.DB
+	cfg   config
+	epoch via.Epoch // creation identity of the log table; see ensureSchema
+
+	q struct {
+		load, insert, update, appendRec, head, read, compact string
+	}
+
+	// wmu serialises this process's writes, so concurrent Appends and CASes
+	// never race each other into SQLITE_BUSY.
+	wmu sync.Mutex
+
+	mu      sync.Mutex
+	closed  bool
+	done    chan struct{} // closed by Close to unwind live subscriptions
+	changed chan struct{} // closed and replaced on every Append
+	bgDone  chan struct{} // closed when the checkpoint loop exits
+}
+
+// Option configures a Backplane at construction.
+type Option func(*config)
+
+type config struct {
+	prefix     string
+	poll       time.Duration
+	checkpoint time.Duration
+}
+
+// WithPrefix sets the table-name prefix (`<prefix>_store`, `<prefix>_log`,
+// `<prefix>_meta`), so several apps can share one database file. Defaults to
+// "via". Letters, digits and '_' only.
+func WithPrefix(p string) Option { return func(c *config) { c.prefix = p } }
+
+// WithPollInterval sets how often a live Subscribe re-checks the log for
+// records appended by another process sharing the file. Appends from this
+// process wake subscribers immediately. Defaults to 500ms.
+func WithPollInterval(d time.Duration) Option { return func(c *config) { c.poll = d } }
+
+// WithCheckpointInterval sets how often the background loop truncates the
+// WAL into the main database file. Zero disables the loop. Defaults to 5m.
+func WithCheckpointInterval(d time.Duration) Option {
+	return func(c *config) { c.checkpoint = d }
+}
+
+// Open prepares db as a backplane: enables WAL mode and creates the tables
+// if they do not exist. It does NOT take ownership of db — Close leaves it
+// open for the caller.
+func Open(db *sql.DB, opts ...Option) (*Backplane, error) {
+	if db == nil {
+		return nil, errors.New("viasqlite: Open requires a non-nil *sql.DB")
+	}
+	cfg := config{prefix: "via", poll: 500 * time.Millisecond, checkpoint: 5 * time.Minute}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if !validPrefix(cfg.prefix) {
+		return nil, fmt.Errorf("viasqlite: prefix %q must be non-empty letters, digits, and '_'", cfg.prefix)
+	}
+	if cfg.poll <= 0 {
+		return nil, errors.New("viasqlite: poll interval must be positive")
+	}
+
+	b := &Backplane{
+		db:      db,
+		cfg:     cfg,
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+		bgDone:  make(chan struct{}),
+	}
+	if err := b.ensureSchema(context.Background()); err != nil {
+		return nil, err
+	}
+	p := cfg.prefix
+	b.q.load = `SELECT data, rev FROM ` + p + `_store WHERE key = ?`
+	b.q.insert = `INSERT INTO ` + p + `_store (key, rev, data) VALUES (?, 1, ?) ON CONFLICT(key) DO NOTHING`
+	b.q.update = `UPDATE ` + p + `_store SET data = ?, rev = rev + 1 WHERE key = ? AND rev = ?`
+	b.q.appendRec = `INSERT INTO ` + p + `_log (key, data) VALUES (?, ?)`
+	b.q.head = `SELECT COALESCE(MAX(id), 0) FROM ` + p + `_log WHERE key = ?`
+	b.q.read = `SELECT id, data FROM ` + p + `_log WHERE key = ? AND id > ? ORDER BY id LIMIT ?`
+	b.q.compact = `DELETE FROM ` + p + `_log WHERE key = ? AND id < ?`
+
+	if cfg.checkpoint > 0 {
+		go b.checkpointLoop(cfg.checkpoint)
+	} else {
+		close(b.bgDone)
+	}
+	return b, nil
+}
+
+// ensureSchema enables WAL and creates the tables. The log uses
+// AUTOINCREMENT so an id is never reused after compaction deletes the rows
+// below it — offsets stay strictly increasing for the life of the file. The
+// meta row records the file's epoch: a recreated database starts its offsets
+// over, and the fresh epoch tells resuming pods their cursors are void.
+func (b *Backplane) ensureSchema(ctx context.Context) error {
+	p := b.cfg.prefix
+	stmts := []string{
+		`PRAGMA journal_mode=WAL`,
+		`CREATE TABLE IF NOT EXISTS ` + p + `_store (key TEXT PRIMARY KEY, rev INTEGER NOT NULL, data BLOB NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS ` + p + `_log (id INTEGER PRIMARY KEY AUTOINCREMENT, key TEXT NOT NULL, data BLOB NOT NULL)`,
+		`CREATE INDEX IF NOT EXISTS ` + p + `_log_key ON ` + p + `_log (key, id)`,
+		`CREATE TABLE IF NOT EXISTS ` + p + `_meta (name TEXT PRIMARY KEY, value INTEGER NOT NULL)`,
+	}
+	for _, s := range stmts {
+		// PRAGMA journal_mode answers with a row, which some drivers refuse
+		// through Exec; Query accepts it and the DDL alike. Drain the rows:
+		// some drivers only step the statement on Next.
+		rows, err := b.db.QueryContext(ctx, s)
+		if err != nil {
+			return fmt.Errorf("viasqlite: schema: %v", err)
+		}
+		for rows.Next() {
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("viasqlite: schema: %v", err)
+		}
+	}
+	_, err := b.db.ExecContext(ctx,
+		`INSERT INTO `+p+`_meta (name, value) VALUES ('epoch', ?) ON CONFLICT(name) DO NOTHING`,
+		time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("viasqlite: schema: %v", err)
+	}
+	var epoch int64
+	if err := b.db.QueryRowContext(ctx, `SELECT value FROM `+p+`_meta WHERE name = 'epoch'`).Scan(&epoch); err != nil {
+		return fmt.Errorf("viasqlite: schema: %v", err)
+	}
+	b.epoch = via.Epoch(epoch)
+	return nil
+}
+
+// --- Store ---
+
+func (b *Backplane) LoadSnapshot(ctx context.Context, key string) ([]byte, via.Rev, bool, error) {
+	var data []byte
+	var rev int64
+	err := b.db.QueryRowContext(ctx, b.q.load, key).Scan(&data, &rev)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return data, via.Rev(rev), true, nil
+}
+
+func (b *Backplane) CAS(ctx context.Context, key string, expectedRev via.Rev, data []byte) (via.Rev, error) {
+	if data == nil {
+		data = []byte{} // the column is NOT NULL; an empty cell is still a write
+	}
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+	var res sql.Result
+	var err error
+	if expectedRev == 0 {
+		res, err = b.db.ExecContext(ctx, b.q.insert, key, data)
+	} else {
+		res, err = b.db.ExecContext(ctx, b.q.update, data, key, int64(expectedRev))
+	}
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, via.ErrCASConflict
+	}
+	if expectedRev == 0 {
+		return 1, nil
+	}
+	return expectedRev + 1, nil
+}
+
+// --- EventLog ---
+
+func (b *Backplane) Append(ctx context.Context, key string, record []byte) (via.Offset, error) {
+	if b.isClosed() {
+		return 0, via.ErrClosed
+	}
+	if record == nil {
+		record = []byte{}
+	}
+	b.wmu.Lock()
+	res, err := b.db.ExecContext(ctx, b.q.appendRec, key, record)
+	b.wmu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	b.wake()
+	return via.Offset(id), nil
+}
+
+func (b *Backplane) Head(ctx context.Context, key string) (via.Offset, via.Epoch, error) {
+	var id int64
+	if err := b.db.QueryRowContext(ctx, b.q.head, key).Scan(&id); err != nil {
+		return 0, 0, err
+	}
+	return via.Offset(id), b.epoch, nil
+}
+
+func (b *Backplane) Subscribe(ctx context.Context, key string, from via.Offset) (<-chan via.Record, error) {
+	if b.isClosed() {
+		return nil, via.ErrClosed
+	}
+	out := make(chan via.Record)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(b.cfg.poll)
+		defer ticker.Stop()
+		cursor := from
+		for {
+			// Grab the wake channel BEFORE reading so an Append landing
+			// between the read and the wait still wakes this loop.
+			wait := b.changedCh()
+			batch, err := b.read(ctx, key, cursor)
+			if err != nil {
+				return // ctx cancelled or the database failed; the tailer resubscribes
+			}
+			for _, r := range batch {
+				select {
+				case out <- r:
+					cursor = r.Offset
+				case <-ctx.Done():
+					return
+				case <-b.done:
+					return
+				}
+			}
+			if len(batch) == batchSize {
+				continue
+			}
+			select {
+			case <-wait:
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			case <-b.done:
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// read loads the next batch after cursor, fully draining the rows first.
+func (b *Backplane) read(ctx context.Context, key string, cursor via.Offset) ([]via.Record, error) {
+	rows, err := b.db.QueryContext(ctx, b.q.read, key, int64(cursor), batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var batch []via.Record
+	for rows.Next() {
+		var id int64
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		batch = append(batch, via.Record{Key: key, Epoch: b.epoch, Offset: via.Offset(id), Data: data})
+	}
+	return batch, rows.Err()
+}
+
+// --- via.Compactor ---
+
+// Compact deletes key's records below beforeOffset. The bound is clamped to
+// the key's head, so the newest record always survives and Head never moves.
+func (b *Backplane) Compact(ctx context.Context, key string, beforeOffset via.Offset) error {
+	if b.isClosed() {
+		return via.ErrClosed
+	}
+	head, _, err := b.Head(ctx, key)
+	if err != nil {
+		return err
+	}
+	beforeOffset = min(beforeOffset, head)
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+	_, err = b.db.ExecContext(ctx, b.q.compact, key, int64(beforeOffset))
+	return err
+}
+
+// checkpointLoop periodically folds the WAL into the main file. SQLite's
+// automatic checkpoint never truncates the WAL and stalls behind long-lived
+// readers, so a busy app's -wal file would otherwise only grow.
+func (b *Backplane) checkpointLoop(every time.Duration) {
+	defer close(b.bgDone)
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-t.C:
+			b.wmu.Lock()
+			rows, err := b.db.Query(`PRAGMA wal_checkpoint(TRUNCATE)`)
+			if err == nil {
+				rows.Close()
+			}
+			b.wmu.Unlock()
+		}
+	}
+}
+
+// --- io.Closer ---
+
+// Close marks the backplane closed (further Append/Subscribe return
+// via.ErrClosed), unwinds live subscriptions and stops the checkpoint loop.
+// It does NOT close the caller's *sql.DB.
+func (b *Backplane) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+	<-b.bgDone
+	return nil
+}
+
+func (b *Backplane) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func (b *Backplane) changedCh() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.changed
+}
+
+// wake releases every subscriber parked on the current changed channel.
+func (b *Backplane) wake() {
+	b.mu.Lock()
+	close(b.changed)
+	b.changed = make(chan struct{})
+	b.mu.Unlock()
+}
+
+func validPrefix(p string) bool {
+	if p == "" {
+		return false
+	}
+	for _, r := range p {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
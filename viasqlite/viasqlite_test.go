@@ -0,0 +1,89 @@
+package viasqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/backplanetest"
+	"github.com/go-via/via/viasqlite"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test binary links mattn/go-sqlite3 so the conformance run always
+// exercises a real database; viasqlite itself ships without a driver.
+const sqliteDriver = "sqlite3"
+
+// Each conformance subtest gets its own prefix in one shared database file,
+// so subtests are isolated exactly as separate apps sharing a file would be.
+func TestOpen_passesBackplaneConformance(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open(sqliteDriver, filepath.Join(t.TempDir(), "via.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	n := 0
+	backplanetest.RunConformance(t, func() via.Backplane {
+		n++
+		bp, err := viasqlite.Open(db, viasqlite.WithPrefix(fmt.Sprintf("t%d", n)))
+		require.NoError(t, err)
+		return bp
+	})
+}
+
+func TestCompact_keepsHeadAndRetainedOffsets(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open(sqliteDriver, filepath.Join(t.TempDir(), "via.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	bp, err := viasqlite.Open(db)
+	require.NoError(t, err)
+	defer bp.Close()
+	ctx := context.Background()
+
+	var offs []via.Offset
+	for i := range 3 {
+		off, err := bp.Append(ctx, "k", []byte{byte(i)})
+		require.NoError(t, err)
+		offs = append(offs, off)
+	}
+	require.NoError(t, bp.Compact(ctx, "k", offs[2]+10))
+
+	head, _, err := bp.Head(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, offs[2], head, "compaction past the head is clamped to it")
+	recs, err := bp.Subscribe(ctx, "k", 0)
+	require.NoError(t, err)
+	assert.Equal(t, offs[2], (<-recs).Offset, "replay resumes at the lowest retained offset")
+}
+
+func TestOpen_rejectsInvalidPrefix(t *testing.T) {
+	t.Parallel()
+
+	_, err := viasqlite.Open(sql.OpenDB(failConnector{}), viasqlite.WithPrefix("a-b"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prefix")
+}
+
+func TestOpen_rejectsNilDB(t *testing.T) {
+	t.Parallel()
+
+	_, err := viasqlite.Open(nil)
+	assert.Error(t, err)
+}
+
+// failConnector lets sql.OpenDB build a *sql.DB that errors on first use,
+// proving argument validation happens before Open touches the database.
+type failConnector struct{}
+
+func (failConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("unreachable")
+}
+func (failConnector) Driver() driver.Driver { return nil }
@@ -0,0 +1,33 @@
+package via
+
+// ViewTransition animates the next element patches sent to this tab with
+// the browser's View Transitions API: the old and new DOM are captured
+// and cross-faded, and elements named with [h.TransitionName] move
+// between their old and new positions. Call it from an action handler to
+// animate that action's re-render and explicit patches, which ship as
+// one frame:
+//
+//	func (p *Board) Sort(ctx *via.Ctx) error {
+//	    ctx.ViewTransition()
+//	    return p.Cards.Update(ctx, sortByTitle)
+//	}
+//
+// From a goroutine, call it before [Ctx.SyncNow]. It stays armed until a
+// frame that carries element patches goes out, then disarms; signals,
+// scripts and redirects are never animated. Browsers without the API
+// apply the patch without animation. [WithViewTransitions] animates every
+// patch instead.
+func (ctx *Ctx) ViewTransition() {
+	if ctx == nil || ctx.queue == nil {
+		return
+	}
+	ctx.queue.mu.Lock()
+	ctx.queue.transition = true
+	ctx.queue.mu.Unlock()
+}
+
+// WithViewTransitions animates every element patch with the View
+// Transitions API, as if each frame called [Ctx.ViewTransition]. Page
+// loads are untouched: for animated navigation between pages, opt in
+// from your stylesheet with `@view-transition { navigation: auto; }`.
+func WithViewTransitions() Option { return func(c *config) { c.viewTransitions = true } }
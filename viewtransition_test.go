@@ -0,0 +1,57 @@
+package via_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transitionPage struct{}
+
+func (p *transitionPage) Shuffle(ctx *via.Ctx) error {
+	ctx.ViewTransition()
+	ctx.Patch().Elements(h.Ul(h.ID("cards"), h.Li(h.TransitionName("card-2"), h.T("two"))))
+	return nil
+}
+
+func (p *transitionPage) Plain(ctx *via.Ctx) error {
+	ctx.Patch().Elements(h.Ul(h.ID("cards"), h.Li(h.T("plain"))))
+	return nil
+}
+
+func (p *transitionPage) View(ctx *via.CtxR) h.H { return h.Ul(h.ID("cards")) }
+
+func TestViewTransition_animatesTheActionsPatchOnly(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[transitionPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Shuffle").Fire())
+	got := vt.AwaitFrame(t, frames, 2*time.Second, "useViewTransition true",
+		`style="view-transition-name:card-2"`)
+	assert.NotContains(t, got, "plain")
+
+	require.Equal(t, 200, tc.Action("Plain").Fire())
+	got = vt.AwaitFrame(t, frames, 2*time.Second, "plain")
+	assert.NotContains(t, got, "useViewTransition", "the transition disarms after its frame")
+}
+
+func TestWithViewTransitions_animatesEveryPatch(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithViewTransitions())
+	via.Mount[transitionPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Equal(t, 200, tc.Action("Plain").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "useViewTransition true", "plain")
+}
@@ -0,0 +1,103 @@
+package vt
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-via/via"
+)
+
+// Clock is a manual [via.Clock] for tests: time stands still until
+// Advance moves it, firing every ticker whose period elapsed. Wire it with
+// via.WithClock to drive Stream tickers and TTL expiry without sleeping:
+//
+//	clk := vt.NewClock(time.Unix(0, 0))
+//	app := via.New(via.WithClock(clk), via.WithContextTTL(time.Minute))
+//	…
+//	clk.Advance(2 * time.Minute) // the idle tab's TTL lapses and the sweep runs
+//
+// Like a time.Ticker, a fake ticker buffers one tick and drops the rest
+// while its reader is busy, so a single large Advance delivers one tick,
+// not one per period. A tick is delivered, not processed: a test asserting
+// on its effect still waits for the receiving goroutine (e.g. with
+// require.Eventually).
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*clockTicker
+}
+
+// NewClock returns a Clock reading start until advanced.
+func NewClock(start time.Time) *Clock { return &Clock{now: start} }
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker that fires each time Advance crosses a
+// multiple of d from now. d must be positive, as for time.NewTicker.
+func (c *Clock) NewTicker(d time.Duration) via.ClockTicker {
+	if d <= 0 {
+		panic("vt: non-positive interval for Clock.NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &clockTicker{clock: c, period: d, next: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires every ticker that came
+// due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		if t.stopped || t.next.After(c.now) {
+			continue
+		}
+		select {
+		case t.ch <- t.next:
+		default:
+		}
+		// Skip the periods that elapsed while the reader was away.
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
+
+type clockTicker struct {
+	clock   *Clock
+	period  time.Duration
+	next    time.Time
+	stopped bool
+	ch      chan time.Time
+}
+
+func (t *clockTicker) Chan() <-chan time.Time { return t.ch }
+
+func (t *clockTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("vt: non-positive interval for Clock ticker Reset")
+	}
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.period, t.next, t.stopped = d, t.clock.now.Add(d), false
+	// Like time.Ticker.Reset since Go 1.23: no tick from the old
+	// schedule is received after Reset.
+	select {
+	case <-t.ch:
+	default:
+	}
+}
+
+func (t *clockTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
@@ -0,0 +1,89 @@
+package vt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock_advanceFiresDueTickersOnce(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	clk := vt.NewClock(start)
+	tk := clk.NewTicker(time.Second)
+
+	clk.Advance(500 * time.Millisecond)
+	assert.Empty(t, tk.Chan(), "not due yet")
+
+	clk.Advance(5 * time.Second)
+	require.Len(t, tk.Chan(), 1, "one buffered tick, however many periods passed")
+	assert.Equal(t, start.Add(time.Second), <-tk.Chan())
+	assert.Equal(t, start.Add(5500*time.Millisecond), clk.Now())
+
+	clk.Advance(time.Second)
+	assert.Equal(t, start.Add(6*time.Second), <-tk.Chan(), "the schedule stays on its period grid")
+
+	tk.Stop()
+	clk.Advance(time.Hour)
+	assert.Empty(t, tk.Chan())
+
+	tk.Reset(time.Minute)
+	clk.Advance(time.Minute)
+	assert.Len(t, tk.Chan(), 1, "Reset revives a stopped ticker on the new period")
+}
+
+type clockStreamPage struct {
+	N via.StateTabNum[int]
+}
+
+func (p *clockStreamPage) OnConnect(ctx *via.Ctx) error {
+	via.Stream(ctx, time.Minute, func(ctx *via.Ctx, _ time.Time) {
+		p.N.Write(ctx, p.N.Read(ctx)+1)
+	})
+	return nil
+}
+
+func (p *clockStreamPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.ID("n"), h.Textf("ticks=%d", p.N.Read(ctx)))
+}
+
+func TestClock_drivesStreamTickers(t *testing.T) {
+	t.Parallel()
+
+	clk := vt.NewClock(time.Unix(0, 0))
+	app := via.New(via.WithClock(clk))
+	srv := vt.Serve(t, app)
+	via.Mount[clockStreamPage](app, "/")
+	tc := vt.NewClient(t, srv, "/")
+	frames, cancel := tc.SSEReady()
+	defer cancel()
+
+	require.Eventually(t, func() bool { return len(app.Tickers()) == 1 }, 2*time.Second, 5*time.Millisecond)
+	clk.Advance(time.Minute)
+	vt.AwaitFrame(t, frames, 2*time.Second, "ticks=1")
+	assert.Equal(t, time.Unix(60, 0), app.Tickers()[0].LastRun)
+}
+
+func TestClock_drivesContextTTLExpiry(t *testing.T) {
+	t.Parallel()
+
+	clk := vt.NewClock(time.Unix(0, 0))
+	app := via.New(via.WithClock(clk), via.WithContextTTL(time.Minute))
+	srv := vt.Serve(t, app)
+	via.Mount[tcPage](app, "/")
+	vt.NewClient(t, srv, "/")
+	require.Equal(t, 1, app.LiveTabs())
+
+	clk.Advance(30 * time.Second)
+	assert.Equal(t, 1, app.LiveTabs(), "the tab is still within its TTL")
+
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool { return app.LiveTabs() == 0 }, 2*time.Second, 5*time.Millisecond,
+		"an idle tab is reaped once the fake clock passes its TTL")
+}
@@ -0,0 +1,322 @@
+package vt
+
+import (
+	"html"
+	"strings"
+)
+
+// element is one opening tag of a fetched page, with enough structure
+// for the event helpers to find it by selector and walk its ancestors.
+// The offsets locate it in the document: start..end is its outer HTML,
+// inner..close its content.
+type element struct {
+	tag    string
+	attrs  map[string]string
+	parent *element
+
+	start, inner, close, end int
+}
+
+// voidTags never have a closing tag, so they are never open parents.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// parseElements lists doc's elements in document order. It is a scanner
+// for the HTML via renders, not a general parser: comments are skipped,
+// script and style bodies are opaque, and an unmatched closing tag pops
+// back to the nearest open element of its name.
+func parseElements(doc string) []*element {
+	var (
+		out  []*element
+		open []*element
+	)
+	for i := 0; i < len(doc); {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			break
+		}
+		i += lt
+		if strings.HasPrefix(doc[i:], "<!--") {
+			end := strings.Index(doc[i:], "-->")
+			if end < 0 {
+				break
+			}
+			i += end + 3
+			continue
+		}
+		gt := tagEnd(doc, i)
+		if gt < 0 {
+			break
+		}
+		raw := doc[i : gt+1]
+		i = gt + 1
+		if strings.HasPrefix(raw, "<!") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(raw, "</"); ok {
+			name = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(name, ">")))
+			for j := len(open) - 1; j >= 0; j-- {
+				if open[j].tag == name {
+					for _, el := range open[j+1:] {
+						el.close, el.end = i-len(raw), i-len(raw)
+					}
+					open[j].close, open[j].end = i-len(raw), i
+					open = open[:j]
+					break
+				}
+			}
+			continue
+		}
+		tag, attrs := parseTag(raw)
+		el := &element{tag: tag, attrs: attrs, start: i - len(raw), inner: i, close: i, end: i}
+		if len(open) > 0 {
+			el.parent = open[len(open)-1]
+		}
+		out = append(out, el)
+		if !voidTags[tag] && !strings.HasSuffix(raw, "/>") {
+			open = append(open, el)
+		}
+		if tag == "script" || tag == "style" {
+			end := strings.Index(strings.ToLower(doc[i:]), "</"+tag)
+			if end < 0 {
+				break
+			}
+			i += end
+		}
+	}
+	for _, el := range open {
+		el.close, el.end = len(doc), len(doc)
+	}
+	return out
+}
+
+// textContent returns el's text as the DOM's textContent reads it, with
+// script and style bodies left out.
+func textContent(doc string, el *element) string {
+	var b strings.Builder
+	s := doc[el.inner:el.close]
+	for s != "" {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:lt])
+		s = s[lt:]
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+		gt := tagEnd(s, 0)
+		if gt < 0 {
+			break
+		}
+		tag, _ := parseTag(s[:gt+1])
+		s = s[gt+1:]
+		if tag == "script" || tag == "style" {
+			end := strings.Index(strings.ToLower(s), "</"+tag)
+			if end < 0 {
+				break
+			}
+			s = s[end:]
+		}
+	}
+	return html.UnescapeString(b.String())
+}
+
+// tagEnd returns the index of the '>' closing the tag that starts at i,
+// skipping any inside quoted attribute values, or -1.
+func tagEnd(doc string, i int) int {
+	var quote byte
+	for j := i + 1; j < len(doc); j++ {
+		switch c := doc[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j
+		}
+	}
+	return -1
+}
+
+// parseTag splits an opening tag into its lowercased name and attributes
+// (values unescaped; a bare attribute maps to "").
+func parseTag(raw string) (string, map[string]string) {
+	s := strings.TrimSuffix(strings.TrimSuffix(raw[1:], ">"), "/")
+	n := strings.IndexAny(s, " \t\n\r\f")
+	if n < 0 {
+		return strings.ToLower(s), map[string]string{}
+	}
+	tag := strings.ToLower(s[:n])
+	attrs := map[string]string{}
+	s = s[n:]
+	for {
+		s = strings.TrimLeft(s, " \t\n\r\f")
+		if s == "" {
+			return tag, attrs
+		}
+		end := strings.IndexAny(s, " \t\n\r\f=")
+		if end < 0 {
+			attrs[strings.ToLower(s)] = ""
+			return tag, attrs
+		}
+		name := strings.ToLower(s[:end])
+		s = strings.TrimLeft(s[end:], " \t\n\r\f")
+		if !strings.HasPrefix(s, "=") {
+			attrs[name] = ""
+			continue
+		}
+		s = strings.TrimLeft(s[1:], " \t\n\r\f")
+		var val string
+		if s != "" && (s[0] == '"' || s[0] == '\'') {
+			q := strings.IndexByte(s[1:], s[0])
+			if q < 0 {
+				val, s = s[1:], ""
+			} else {
+				val, s = s[1:q+1], s[q+2:]
+			}
+		} else {
+			v := strings.IndexAny(s, " \t\n\r\f")
+			if v < 0 {
+				v = len(s)
+			}
+			val, s = s[:v], s[v:]
+		}
+		attrs[name] = html.UnescapeString(val)
+	}
+}
+
+// selector is a parsed CSS selector: compound parts joined by the
+// descendant combinator, outermost first.
+type selector []compound
+
+// compound is one part of a selector — "input#q.big[name=q]".
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   [][2]string // name, value; a nil-valued test is written as value "\x00"
+}
+
+// anyValue marks an [attr] test with no value.
+const anyValue = "\x00"
+
+// parseSelector parses the subset of CSS selectors the event helpers
+// accept: tag, #id, .class, [attr] and [attr=value] (value optionally
+// quoted), compounded, and joined by spaces for descendants. ok is false
+// for anything else.
+func parseSelector(s string) (sel selector, ok bool) {
+	for part := range strings.FieldsSeq(s) {
+		var c compound
+		i := 0
+		for i < len(part) && isNameByte(part[i]) {
+			i++
+		}
+		c.tag = strings.ToLower(part[:i])
+		for i < len(part) {
+			switch part[i] {
+			case '#', '.':
+				j := i + 1
+				for j < len(part) && isNameByte(part[j]) {
+					j++
+				}
+				if j == i+1 {
+					return nil, false
+				}
+				if part[i] == '#' {
+					c.id = part[i+1 : j]
+				} else {
+					c.classes = append(c.classes, part[i+1:j])
+				}
+				i = j
+			case '[':
+				end := strings.IndexByte(part[i:], ']')
+				if end < 0 {
+					return nil, false
+				}
+				name, val, hasVal := strings.Cut(part[i+1:i+end], "=")
+				if name == "" {
+					return nil, false
+				}
+				if !hasVal {
+					val = anyValue
+				} else if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+					val = val[1 : len(val)-1]
+				}
+				c.attrs = append(c.attrs, [2]string{strings.ToLower(name), val})
+				i += end + 1
+			default:
+				return nil, false
+			}
+		}
+		sel = append(sel, c)
+	}
+	return sel, len(sel) > 0
+}
+
+func isNameByte(b byte) bool {
+	return b == '-' || b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func (c compound) matches(el *element) bool {
+	if c.tag != "" && c.tag != el.tag {
+		return false
+	}
+	if c.id != "" && el.attrs["id"] != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		have := strings.Fields(el.attrs["class"])
+		for _, want := range c.classes {
+			found := false
+			for _, h := range have {
+				found = found || h == want
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		v, ok := el.attrs[a[0]]
+		if !ok || a[1] != anyValue && v != a[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether el is selected by sel: its last part matches
+// el and each earlier part matches some ancestor, in order.
+func (sel selector) matches(el *element) bool {
+	if !sel[len(sel)-1].matches(el) {
+		return false
+	}
+	i := len(sel) - 2
+	for p := el.parent; p != nil && i >= 0; p = p.parent {
+		if sel[i].matches(p) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+// contains reports whether el is anc or one of its descendants.
+func (anc *element) contains(el *element) bool {
+	for ; el != nil; el = el.parent {
+		if el == anc {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,385 @@
+package vt
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The event helpers drive a page the way a user does: they find an
+// element in the last fetched page by CSS selector, read the on.*
+// binding Datastar would run for the event, and fire its action. They
+// keep the values Change writes into bound signals and send them with
+// every action they fire, as the browser sends its signal store. They
+// read the page as fetched, with the element patches the client's stream
+// has received applied — open it (WaitForPatch, WaitForText) to see
+// them. Datastar's own logic (debounce, confirm dialogs, modifier keys)
+// is not simulated: a confirm is always accepted.
+
+// postRE picks the action, its on.Arg query and its on.Optimistic
+// header out of a binding's @post.
+var postRE = regexp.MustCompile(`@post\('/_action/([^'?]+)(?:\?([^']*))?'(?:,\{headers:\{'([^']+)':'([^']*)'\}\})?\)`)
+
+// keyRE and hotkeyRE pick the key an on.Key or on.Hotkey binding waits for.
+var (
+	keyRE    = regexp.MustCompile(`evt\.key==='([^']*)'&&`)
+	hotkeyRE = regexp.MustCompile(`evt\.key\.toLowerCase\(\)===("(?:[^"\\]|\\.)*")`)
+)
+
+// Click fires the action a click on the element matching selector runs
+// — its own on.Click, or the nearest ancestor's, as the event bubbles —
+// and returns the HTTP status, or 0 when no binding fires.
+//
+//	tc.Click("#inc")
+//	tc.Click(`li[data-id="3"] button.delete`)
+//
+// Selectors are tag, #id, .class, [attr] and [attr=value], compounded
+// and joined by spaces. Fails the test when nothing matches selector.
+func (c *Client) Click(selector string) int {
+	c.t.Helper()
+	return c.dispatch("Click", c.find("Click", selector), "click", "")
+}
+
+// Press fires the keydown binding — on.Key or a plain keydown handler —
+// that a press of key on the element matching selector would run, then
+// any page-wide on.Hotkey for key. key is a W3C key name: "Enter",
+// "Escape", "a". A binding filtered to another key doesn't fire; 0 means
+// none did.
+//
+//	tc.Change("#search", "gophers")
+//	tc.Press("Enter", "#search")
+func (c *Client) Press(key, selector string) int {
+	c.t.Helper()
+	el := c.find("Press", selector)
+	if status := c.dispatch("Press", el, "keydown", key); status != 0 {
+		return status
+	}
+	for _, w := range c.elements() {
+		for name, expr := range w.attrs {
+			if isBinding(name, "keydown") && hasModifier(name, "window") {
+				if status := c.fireBinding("Press", expr, key); status != 0 {
+					return status
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// Change sets the signal bound (data-bind) to the element matching
+// selector to value, as typing into it and leaving it does, then fires
+// its on.Change binding, if any — returning the HTTP status, or 0. value
+// is converted to the signal's type in the page's seed: a number or a
+// bool ("true", "false") where the signal is one. The value is sent with
+// every later action the helpers fire, so Change then Submit submits the
+// form as filled in.
+func (c *Client) Change(selector, value string) int {
+	c.t.Helper()
+	el := c.find("Change", selector)
+	if !c.setBound("Change", el, selector, value) {
+		return 0
+	}
+	return c.dispatch("Change", el, "change", "")
+}
+
+// setBound stores value in the signal el binds, as typing into it does.
+// Fails the test and reports false when el binds none.
+func (c *Client) setBound(method string, el *element, selector, value string) bool {
+	c.t.Helper()
+	if el == nil {
+		return false
+	}
+	key := bindKey(el)
+	if key == "" {
+		c.t.Fatalf("vt.Client.%s: %q has no data-bind", method, selector)
+		return false
+	}
+	v := coerce(c.seedOf(key), value)
+	c.mu.Lock()
+	if c.signals == nil {
+		c.signals = map[string]any{}
+	}
+	setPath(c.signals, key, v)
+	c.mu.Unlock()
+	return true
+}
+
+// Submit fires the on.Submit binding of the form matching selector (or
+// of the form enclosing it) and returns the HTTP status, or 0 when the
+// form has none. Bound fields that Change hasn't touched are sent with
+// their rendered value attribute, when they have one.
+func (c *Client) Submit(selector string) int {
+	c.t.Helper()
+	el := c.find("Submit", selector)
+	if el == nil {
+		return 0
+	}
+	form := el
+	for form != nil && form.tag != "form" {
+		form = form.parent
+	}
+	if form == nil {
+		c.t.Fatalf("vt.Client.Submit: %q is not in a form", selector)
+		return 0
+	}
+	for _, f := range c.elements() {
+		key := bindKey(f)
+		v, ok := f.attrs["value"]
+		if key == "" || !ok || !form.contains(f) {
+			continue
+		}
+		v2 := coerce(c.seedOf(key), v)
+		c.mu.Lock()
+		if getPath(c.signals, key) == nil {
+			if c.signals == nil {
+				c.signals = map[string]any{}
+			}
+			setPath(c.signals, key, v2)
+		}
+		c.mu.Unlock()
+	}
+	return c.dispatch("Submit", form, "submit", "")
+}
+
+// Fired is one binding FireAll ran: the action it posted to and the
+// HTTP status that answered.
+type Fired struct {
+	Action string
+	Status int
+}
+
+// FireAll fires every binding in the page that posts to an action, each
+// distinct one once, in document order — a visitor trying every control.
+// Each goes out as its event would send it: its signal writes, on.Arg
+// arguments and on.Optimistic header, and for an on.Key or on.Hotkey the
+// key it waits for.
+//
+//	for _, f := range tc.FireAll() {
+//	    assert.Less(t, f.Status, 500, f.Action)
+//	}
+func (c *Client) FireAll() []Fired {
+	c.t.Helper()
+	var (
+		out  []Fired
+		seen = map[string]bool{}
+	)
+	for _, el := range c.elements() {
+		for _, name := range slices.Sorted(maps.Keys(el.attrs)) {
+			expr := el.attrs[name]
+			if !strings.HasPrefix(name, "data-on:") && name != "data-init" || seen[expr] {
+				continue
+			}
+			m := postRE.FindStringSubmatch(expr)
+			if m == nil {
+				continue
+			}
+			seen[expr] = true
+			out = append(out, Fired{Action: m[1], Status: c.fireBinding("FireAll", expr, bindingKey(expr))})
+		}
+	}
+	return out
+}
+
+// bindingKey returns the key an on.Key or on.Hotkey binding waits for,
+// or "" for any other binding.
+func bindingKey(expr string) string {
+	if k := keyRE.FindStringSubmatch(expr); k != nil {
+		return k[1]
+	}
+	var key string
+	if k := hotkeyRE.FindStringSubmatch(expr); k != nil && json.Unmarshal([]byte(k[1]), &key) == nil {
+		return key
+	}
+	return ""
+}
+
+// fireInits fires the actions the page runs as it loads: on.Load and
+// data-init bindings that post to an action, in document order.
+func (c *Client) fireInits() {
+	c.t.Helper()
+	for _, el := range c.elements() {
+		for name, expr := range el.attrs {
+			if (name == "data-init" || isBinding(name, "load")) && postRE.MatchString(expr) {
+				c.fireBinding("load", expr, "")
+			}
+		}
+	}
+}
+
+// find returns the first element in the page matching selector.
+func (c *Client) find(method, selector string) *element {
+	c.t.Helper()
+	sel, ok := parseSelector(selector)
+	if !ok {
+		c.t.Fatalf("vt.Client.%s: unsupported selector %q", method, selector)
+		return nil
+	}
+	for _, el := range c.elements() {
+		if sel.matches(el) {
+			return el
+		}
+	}
+	c.t.Fatalf("vt.Client.%s: nothing matches %q in the page", method, selector)
+	return nil
+}
+
+// elements parses the current page, once per change.
+func (c *Client) elements() []*element {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dom == nil || c.domOf != c.view {
+		c.dom, c.domOf = parseElements(c.view), c.view
+	}
+	return c.dom
+}
+
+// seedOf returns the value the page's data-signals seed key with, or
+// nil.
+func (c *Client) seedOf(key string) any {
+	for _, el := range c.elements() {
+		var seed map[string]any
+		if raw, ok := el.attrs["data-signals"]; ok && json.Unmarshal([]byte(raw), &seed) == nil {
+			if v := getPath(seed, key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// dispatch fires the nearest binding for event on the path from el up
+// to the root, skipping page-wide and click-outside listeners.
+func (c *Client) dispatch(method string, el *element, event, key string) int {
+	c.t.Helper()
+	for ; el != nil; el = el.parent {
+		for name, expr := range el.attrs {
+			if isBinding(name, event) && !hasModifier(name, "window") && !hasModifier(name, "outside") {
+				return c.fireBinding(method, expr, key)
+			}
+		}
+	}
+	return 0
+}
+
+// fireBinding runs one binding expression: its signal writes, its key
+// filter, then its @post. Returns 0 when it doesn't post.
+func (c *Client) fireBinding(method, expr, key string) int {
+	c.t.Helper()
+	m := postRE.FindStringSubmatch(expr)
+	if m == nil {
+		return 0
+	}
+	if k := keyRE.FindStringSubmatch(expr); k != nil && k[1] != key {
+		return 0
+	}
+	if k := hotkeyRE.FindStringSubmatch(expr); k != nil {
+		var want string
+		if json.Unmarshal([]byte(k[1]), &want) != nil || strings.ToLower(key) != want {
+			return 0
+		}
+	}
+	c.mu.Lock()
+	if c.signals == nil {
+		c.signals = map[string]any{}
+	}
+	for k, v := range preWrites(expr) {
+		setPath(c.signals, k, v)
+	}
+	call := c.Action(m[1])
+	call.signals = map[string]any{}
+	for k, v := range deepCopy(c.signals) {
+		// The browser keeps _-prefixed signals to itself.
+		if !strings.HasPrefix(k, "_") {
+			call.signals[k] = v
+		}
+	}
+	c.mu.Unlock()
+	if q, err := url.ParseQuery(m[2]); err == nil {
+		for name, vals := range q {
+			for _, v := range vals {
+				call.WithArg(name, v)
+			}
+		}
+	}
+	if m[3] != "" {
+		call.WithHeader(m[3], m[4])
+	}
+	return call.Fire()
+}
+
+// preWrites decodes the `$key=<json>;` statements on.SetSignal and
+// on.Optimistic put ahead of the post.
+func preWrites(expr string) map[string]any {
+	out := map[string]any{}
+	for strings.HasPrefix(expr, "$") {
+		key, rest, ok := strings.Cut(expr[1:], "=")
+		if !ok {
+			break
+		}
+		dec := json.NewDecoder(strings.NewReader(rest))
+		var v any
+		if dec.Decode(&v) != nil {
+			break
+		}
+		rest = rest[dec.InputOffset():]
+		if !strings.HasPrefix(rest, ";") {
+			break
+		}
+		out[key] = v
+		expr = rest[1:]
+	}
+	return out
+}
+
+// isBinding reports whether attribute name is a data-on binding for
+// event, with or without modifiers.
+func isBinding(name, event string) bool {
+	rest, ok := strings.CutPrefix(name, "data-on:"+event)
+	return ok && (rest == "" || strings.HasPrefix(rest, "__"))
+}
+
+// hasModifier reports whether attribute name carries modifier mod. Like
+// Datastar, modifiers follow "__" and their arguments follow ".".
+func hasModifier(name, mod string) bool {
+	_, mods, _ := strings.Cut(name, "__")
+	for m := range strings.SplitSeq(mods, "__") {
+		if m, _, _ = strings.Cut(m, "."); m == mod {
+			return true
+		}
+	}
+	return false
+}
+
+// bindKey returns the signal el two-way binds, or "".
+func bindKey(el *element) string {
+	if k := el.attrs["data-bind"]; k != "" {
+		return k
+	}
+	for name := range el.attrs {
+		if k, ok := strings.CutPrefix(name, "data-bind:"); ok {
+			k, _, _ = strings.Cut(k, "__")
+			return k
+		}
+	}
+	return ""
+}
+
+// coerce converts value to the type of the signal's seed, as Datastar's
+// binding does: numbers stay numbers and booleans booleans.
+func coerce(seed any, value string) any {
+	switch seed.(type) {
+	case float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -0,0 +1,253 @@
+package vt
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// FuzzConfig shapes a Fuzz storm. The zero value fires 200 calls from 4
+// tabs at every action the page binds, under a fresh seed.
+type FuzzConfig struct {
+	// Seed fixes the schedule: which tab fires what, with which signals,
+	// when streams drop and tabs reload, and the pauses between. 0 takes
+	// VT_FUZZ_SEED from the environment, or else picks one from the clock.
+	Seed int64
+	// Tabs is how many tabs fire at once. Every other tab is a Fork of
+	// the one before it, so sessions are shared as well as tabs racing.
+	Tabs int
+	// Calls is how many actions the storm fires across all tabs.
+	Calls int
+	// Actions names the actions to fire; empty fires every action the
+	// page's bindings post to.
+	Actions []string
+	// Signals offers values for signals sent with the actions: each call
+	// sends one of each list, picked at random, or leaves the signal out.
+	Signals map[string][]any
+	// Check runs against every tab once the storm settles, for the
+	// invariants the app must hold — a counter matching the calls that
+	// bumped it, a list without duplicates.
+	Check func(tc *Client)
+}
+
+// FuzzResult reports what a Fuzz storm did.
+type FuzzResult struct {
+	Seed  int64
+	Calls map[string]int // actions fired, by name
+}
+
+// Fuzz opens cfg.Tabs tabs on path and has them fire cfg.Calls actions
+// at once, while their SSE streams connect, drop and reconnect and tabs
+// reload into fresh contexts underneath. Each step waits a random beat
+// first, so every run interleaves differently. Run it under -race: the
+// registry, signal maps and state handles are where a concurrency bug
+// hides, and the race detector is what sees it.
+//
+//	func TestCart_survivesConcurrentClicks(t *testing.T) {
+//	    app := via.New()
+//	    via.Mount[Cart](app, "/")
+//	    vt.Fuzz(t, vt.Serve(t, app), "/", vt.FuzzConfig{
+//	        Signals: map[string][]any{"qty": {0, 1, 99, -1}},
+//	    })
+//	}
+//
+// A call the server answers with a 4xx or 5xx fails the test, as does
+// cfg.Check. The schedule follows from the seed, which Fuzz logs; set
+// FuzzConfig.Seed or VT_FUZZ_SEED to replay a failing run's schedule
+// (the goroutine interleaving is the scheduler's to pick, so a flaky
+// failure may take a few runs of the same seed).
+func Fuzz(t testing.TB, server *httptest.Server, path string, cfg FuzzConfig) FuzzResult {
+	t.Helper()
+	seed := cfg.Seed
+	if seed == 0 {
+		if env := os.Getenv("VT_FUZZ_SEED"); env != "" {
+			n, err := strconv.ParseInt(env, 10, 64)
+			if err != nil {
+				t.Fatalf("vt.Fuzz: VT_FUZZ_SEED=%q: %v", env, err)
+			}
+			seed = n
+		} else {
+			seed = time.Now().UnixNano()
+		}
+	}
+	t.Logf("vt.Fuzz: seed %d", seed)
+	tabs := cfg.Tabs
+	if tabs <= 0 {
+		tabs = 4
+	}
+	calls := cfg.Calls
+	if calls <= 0 {
+		calls = 200
+	}
+
+	clients := make([]*Client, tabs)
+	for i := range clients {
+		if i%2 == 1 {
+			clients[i] = clients[i-1].Fork(path)
+		} else {
+			clients[i] = NewClient(t, server, path)
+		}
+	}
+	actions := cfg.Actions
+	if len(actions) == 0 {
+		actions = boundActions(clients[0].HTML())
+		if len(actions) == 0 {
+			t.Fatalf("vt.Fuzz: the page at %s binds no actions; name some in FuzzConfig.Actions", path)
+		}
+	}
+
+	plans, fired := fuzzPlan(rand.New(rand.NewSource(seed)), tabs, calls, actions, cfg.Signals)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []string
+	)
+	start := make(chan struct{})
+	for i, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for _, f := range runFuzzTab(c, plans[i]) {
+				mu.Lock()
+				failures = append(failures, fmt.Sprintf("tab %d: %s", i, f))
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, f := range failures {
+		t.Errorf("vt.Fuzz: %s", f)
+	}
+	if len(failures) > 0 {
+		t.Errorf("vt.Fuzz: replay with FuzzConfig{Seed: %d} or VT_FUZZ_SEED=%d", seed, seed)
+	}
+	if cfg.Check != nil {
+		for _, c := range clients {
+			cfg.Check(c)
+		}
+	}
+	return FuzzResult{Seed: seed, Calls: fired}
+}
+
+// fuzzStep is one thing a tab does in a storm.
+type fuzzStep struct {
+	kind    fuzzKind
+	action  string
+	signals map[string]any
+	pause   time.Duration // before the step
+}
+
+type fuzzKind int
+
+const (
+	fuzzFire   fuzzKind = iota
+	fuzzStream          // open the tab's stream, or drop it when open
+	fuzzReload          // re-fetch the page into a fresh context
+)
+
+// fuzzPlan deals calls across tabs, drawing everything random from r up
+// front so the seed alone decides the schedule.
+func fuzzPlan(r *rand.Rand, tabs, calls int, actions []string, signals map[string][]any) ([][]fuzzStep, map[string]int) {
+	keys := slices.Sorted(maps.Keys(signals))
+	plans := make([][]fuzzStep, tabs)
+	fired := map[string]int{}
+	for n := 0; n < calls; {
+		tab := r.Intn(tabs)
+		step := fuzzStep{pause: time.Duration(r.Intn(2000)) * time.Microsecond}
+		switch roll := r.Intn(20); {
+		case roll < 2:
+			step.kind = fuzzStream
+		case roll < 3:
+			step.kind = fuzzReload
+		default:
+			step.action = actions[r.Intn(len(actions))]
+			for _, k := range keys {
+				if vals := signals[k]; len(vals) > 0 && r.Intn(3) > 0 {
+					if step.signals == nil {
+						step.signals = map[string]any{}
+					}
+					step.signals[k] = vals[r.Intn(len(vals))]
+				}
+			}
+			fired[step.action]++
+			n++
+		}
+		plans[tab] = append(plans[tab], step)
+	}
+	return plans, fired
+}
+
+// runFuzzTab plays plan on c from a goroutine of its own, so it reports
+// what went wrong instead of failing the test.
+func runFuzzTab(c *Client, plan []fuzzStep) (failures []string) {
+	var dropStream func()
+	defer func() {
+		if dropStream != nil {
+			dropStream()
+		}
+	}()
+	for _, step := range plan {
+		time.Sleep(step.pause)
+		switch step.kind {
+		case fuzzStream:
+			if dropStream != nil {
+				dropStream()
+				dropStream = nil
+				continue
+			}
+			frames, cancel, err := c.dialSSE("", 0)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("open stream: %v", err))
+				continue
+			}
+			go func() {
+				for range frames {
+				}
+			}()
+			dropStream = cancel
+		case fuzzReload:
+			if dropStream != nil {
+				dropStream()
+				dropStream = nil
+			}
+			if err := c.refetch(); err != nil {
+				failures = append(failures, fmt.Sprintf("reload: %v", err))
+			}
+		case fuzzFire:
+			call := &ActionCall{client: c, name: step.action, signals: step.signals}
+			status, err := call.send()
+			switch {
+			case err != nil:
+				failures = append(failures, fmt.Sprintf("%s: %v", step.action, err))
+			case status >= 400:
+				failures = append(failures, fmt.Sprintf("%s %v: status %d", step.action, step.signals, status))
+			}
+		}
+	}
+	return failures
+}
+
+// actionRE picks action names out of the page's bindings.
+var actionRE = regexp.MustCompile(`/_action/([^'"?&/]+)`)
+
+// boundActions lists the actions page posts to, each once, in order.
+func boundActions(page string) []string {
+	var names []string
+	for _, m := range actionRE.FindAllStringSubmatch(page, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+package vt
+
+import "strings"
+
+// The client keeps the page as its stream has patched it, so the event
+// helpers and Page.AssertText see what a browser would show. Patches are
+// applied the way Datastar's morph lands them, minus the morphing: an
+// element is swapped whole for the patch's element with its id, or put
+// where the patch's selector and mode say.
+
+// applyElementPatches applies the element patch events in events to doc.
+func applyElementPatches(doc, events string) string {
+	for ev := range strings.SplitSeq(events, "\n\n") {
+		if !strings.Contains(ev, "event: datastar-patch-elements") {
+			continue
+		}
+		var (
+			selector, mode string
+			frag           []string
+		)
+		for line := range strings.SplitSeq(ev, "\n") {
+			switch {
+			case strings.HasPrefix(line, "data: selector "):
+				selector = strings.TrimPrefix(line, "data: selector ")
+			case strings.HasPrefix(line, "data: mode "):
+				mode = strings.TrimPrefix(line, "data: mode ")
+			case strings.HasPrefix(line, "data: elements "):
+				frag = append(frag, strings.TrimPrefix(line, "data: elements "))
+			}
+		}
+		doc = patchElements(doc, selector, mode, strings.Join(frag, "\n"))
+	}
+	return doc
+}
+
+// patchElements lands one patch. With no selector each top-level element
+// of frag replaces the element of doc with its id; with one, frag goes
+// where mode puts it relative to the first match.
+func patchElements(doc, selector, mode, frag string) string {
+	if selector == "" {
+		for _, el := range parseElements(frag) {
+			id := el.attrs["id"]
+			if el.parent != nil || id == "" {
+				continue
+			}
+			target := findID(doc, id)
+			if target == nil {
+				continue
+			}
+			if mode == "inner" {
+				doc = doc[:target.inner] + frag[el.inner:el.close] + doc[target.close:]
+			} else {
+				doc = doc[:target.start] + frag[el.start:el.end] + doc[target.end:]
+			}
+		}
+		return doc
+	}
+	sel, ok := parseSelector(selector)
+	if !ok {
+		return doc
+	}
+	var target *element
+	for _, el := range parseElements(doc) {
+		if sel.matches(el) {
+			target = el
+			break
+		}
+	}
+	if target == nil {
+		return doc
+	}
+	switch mode {
+	case "inner":
+		return doc[:target.inner] + frag + doc[target.close:]
+	case "append":
+		return doc[:target.close] + frag + doc[target.close:]
+	case "prepend":
+		return doc[:target.inner] + frag + doc[target.inner:]
+	case "before":
+		return doc[:target.start] + frag + doc[target.start:]
+	case "after":
+		return doc[:target.end] + frag + doc[target.end:]
+	case "remove":
+		return doc[:target.start] + doc[target.end:]
+	}
+	return doc[:target.start] + frag + doc[target.end:]
+}
+
+func findID(doc, id string) *element {
+	for _, el := range parseElements(doc) {
+		if el.attrs["id"] == id {
+			return el
+		}
+	}
+	return nil
+}
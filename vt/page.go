@@ -0,0 +1,164 @@
+package vt
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+)
+
+// Page is what a user does to a page, and what they see. OpenPage
+// implements it in-process, over the same HTTP as Client; vtbrowser's
+// Session implements it in a real browser. A flow written against Page
+// runs on either, so the fast in-process run during development and the
+// browser run in CI are the same test:
+//
+//	func searchFlow(t *testing.T, p vt.Page) {
+//	    p.Visit("/")
+//	    p.Fill("#search", "gophers")
+//	    p.Press("Enter", "#search")
+//	    p.AssertText("#count", "3 results")
+//	}
+//
+//	func TestSearch(t *testing.T)        { searchFlow(t, vt.OpenPage(t, newApp())) }
+//	func TestSearch_browser(t *testing.T) { searchFlow(t, vtbrowser.Open(t, newApp())) }
+//
+// Selectors are CSS; the in-process page understands tag, #id, .class,
+// [attr] and [attr=value], compounded and joined by spaces, so keep to
+// those in shared flows. Every method fails the test on error.
+type Page interface {
+	// Visit opens path in a new tab of the same session.
+	Visit(path string)
+	// Click clicks the first element matching selector.
+	Click(selector string)
+	// Fill sets the value of the input matching selector, firing its
+	// input and change events. For a checkbox, "true" or "false".
+	Fill(selector, value string)
+	// Press presses key — a W3C key name such as "Enter", or a
+	// character — on the element matching selector.
+	Press(key, selector string)
+	// Submit submits the form matching selector, or enclosing it.
+	Submit(selector string)
+	// AssertText waits until the trimmed text of the first element
+	// matching selector is want.
+	AssertText(selector, want string)
+}
+
+// pageWait bounds how long the in-process AssertText waits for a patch.
+const pageWait = 2 * time.Second
+
+// OpenPage serves app and returns an in-process Page for it; Visit opens
+// the first tab. Its tabs fire the actions their bindings name with the
+// signals the page holds, and keep their view current from the tab's
+// stream — see Client's event helpers for what is and isn't modelled.
+func OpenPage(t testing.TB, app *via.App) Page {
+	t.Helper()
+	return &clientPage{t: t, srv: Serve(t, app)}
+}
+
+type clientPage struct {
+	t   testing.TB
+	srv *httptest.Server
+	c   *Client
+}
+
+func (p *clientPage) Visit(path string) {
+	p.t.Helper()
+	if p.c == nil {
+		p.c = NewClient(p.t, p.srv, path)
+	} else {
+		prev := p.c
+		p.c = prev.Fork(path)
+		prev.mu.Lock()
+		if prev.live != nil {
+			prev.live.cancel()
+		}
+		prev.mu.Unlock()
+	}
+	p.c.await("Visit", "the stream handshake", pageWait, func(l *live) (string, bool) {
+		return "", strings.Contains(l.text.String(), ": ready")
+	})
+}
+
+func (p *clientPage) Click(selector string) {
+	p.t.Helper()
+	p.check("Click", selector, p.client("Click").Click(selector))
+}
+
+func (p *clientPage) Fill(selector, value string) {
+	p.t.Helper()
+	c := p.client("Fill")
+	el := c.find("Fill", selector)
+	if !c.setBound("Fill", el, selector, value) {
+		return
+	}
+	p.check("Fill", selector, c.dispatch("Fill", el, "input", ""))
+	p.check("Fill", selector, c.dispatch("Fill", el, "change", ""))
+}
+
+func (p *clientPage) Press(key, selector string) {
+	p.t.Helper()
+	p.check("Press", selector, p.client("Press").Press(key, selector))
+}
+
+func (p *clientPage) Submit(selector string) {
+	p.t.Helper()
+	p.check("Submit", selector, p.client("Submit").Submit(selector))
+}
+
+func (p *clientPage) AssertText(selector, want string) {
+	p.t.Helper()
+	c := p.client("AssertText")
+	sel, ok := parseSelector(selector)
+	if !ok {
+		p.t.Fatalf("vt.Page.AssertText: unsupported selector %q", selector)
+		return
+	}
+	l := c.stream()
+	deadline := time.NewTimer(pageWait)
+	defer deadline.Stop()
+	for {
+		l.mu.Lock()
+		arrived := l.arrived
+		l.mu.Unlock()
+		got := "<no element matches " + selector + ">"
+		c.mu.Lock()
+		view := c.view
+		c.mu.Unlock()
+		for _, el := range parseElements(view) {
+			if sel.matches(el) {
+				got = strings.TrimSpace(textContent(view, el))
+				break
+			}
+		}
+		if got == want {
+			return
+		}
+		select {
+		case <-arrived:
+			continue
+		case <-deadline.C:
+		}
+		p.t.Fatalf("vt.Page.AssertText: %q never showed %q within %v; last text: %q", selector, want, pageWait, got)
+		return
+	}
+}
+
+// client returns the open tab, failing the test before the first Visit.
+func (p *clientPage) client(method string) *Client {
+	p.t.Helper()
+	if p.c == nil {
+		p.t.Fatalf("vt.Page.%s: Visit a page first", method)
+	}
+	return p.c
+}
+
+// check fails the test when the action a binding fired was refused.
+func (p *clientPage) check(method, selector string, status int) {
+	p.t.Helper()
+	if status >= 400 {
+		p.t.Fatalf("vt.Page.%s(%q): the action answered %d", method, selector, status)
+	}
+}
@@ -0,0 +1,84 @@
+package vt
+
+import (
+	"strings"
+	"time"
+)
+
+// DisconnectSSE drops the client's stream the way a flaky network does,
+// and returns once the client side is closed. Actions fired while the
+// stream is down queue their patches on the server; ReconnectSSE
+// brings them in. The stream must be open — the first WaitForPatch,
+// WaitForText or AssertSignal opens it; DisconnectSSE opens it (and
+// waits for the handshake) when none did.
+//
+//	tc.DisconnectSSE()
+//	tc.Action(p.Bump).Fire() // patch queued: nothing is listening
+//	tc.ReconnectSSE()
+//	tc.WaitForText(">1<", time.Second)
+func (c *Client) DisconnectSSE() {
+	c.t.Helper()
+	c.mu.Lock()
+	opened := c.live == nil
+	c.mu.Unlock()
+	l := c.stream()
+	if opened {
+		c.await("DisconnectSSE", "the stream handshake", 2*time.Second, func(l *live) (string, bool) {
+			return "", strings.Contains(l.text.String(), ": ready")
+		})
+	}
+	l.mu.Lock()
+	cancel, done, closed := l.cancel, l.done, l.closed
+	l.mu.Unlock()
+	if closed {
+		c.t.Fatalf("vt.Client.DisconnectSSE: the stream is already down")
+		return
+	}
+	cancel()
+	<-done
+}
+
+// ReconnectSSE reopens a dropped stream as Datastar's retry does: it
+// reports the id of the last event received (Last-Event-ID), so the
+// server replays what the client missed or resyncs the view, then sends
+// what queued meanwhile. It returns once the server has caught the
+// stream up. What arrives joins what arrived before, so WaitForPatch,
+// WaitForText and Signal carry on from where the old stream stopped.
+// Fails the test while the stream is still up.
+func (c *Client) ReconnectSSE() {
+	c.t.Helper()
+	c.mu.Lock()
+	l, delay := c.live, c.sseDelay
+	c.mu.Unlock()
+	if l == nil {
+		c.t.Fatalf("vt.Client.ReconnectSSE: no stream to reconnect; DisconnectSSE first")
+		return
+	}
+	l.mu.Lock()
+	closed, lastID, from := l.closed, l.lastID, l.text.Len()
+	l.mu.Unlock()
+	if !closed {
+		c.t.Fatalf("vt.Client.ReconnectSSE: the stream is still up; DisconnectSSE first")
+		return
+	}
+	c.attach(l, lastID, delay)
+	c.await("ReconnectSSE", "the stream handshake", 2*time.Second, func(l *live) (string, bool) {
+		return "", strings.Contains(l.text.String()[from:], ": ready")
+	})
+}
+
+// SlowSSE makes the client a slow consumer: streams it opens from now
+// on read a little at a time, pausing delay between reads, through a
+// small receive window. The server's writes back up behind it, so a
+// test can see what an app does when a tab can't keep up — its
+// WithSSEWriteTimeout dropping the stream, patches staying queued for
+// the reconnect. 0 restores full speed for the next stream.
+//
+//	app := via.New(via.WithSSEWriteTimeout(50 * time.Millisecond))
+//	…
+//	tc.SlowSSE(100 * time.Millisecond)
+func (c *Client) SlowSSE(delay time.Duration) {
+	c.mu.Lock()
+	c.sseDelay = delay
+	c.mu.Unlock()
+}
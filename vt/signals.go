@@ -0,0 +1,222 @@
+package vt
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signalWait bounds how long AssertSignal waits for a patch to bring the
+// signal to the wanted value.
+const signalWait = 2 * time.Second
+
+// Signal returns the current value of the signal named by sig — its wire
+// key, or a bound handle such as a *via.Signal[T] — as the browser would
+// hold it: the page's data-signals seed, overlaid by values Change and
+// bindings wrote, overlaid by the signal patches the client's stream has
+// received. Numbers decode as float64 and objects as map[string]any;
+// nil means the page has no such signal.
+//
+// Patches are seen once the stream is open — WaitForPatch, WaitForText
+// and AssertSignal open it — and have arrived, so wait for the action's
+// patch before reading:
+//
+//	tc.Action(p.Apply).Fire()
+//	tc.WaitForPatch(time.Second)
+//	assert.Equal(t, 5.0, tc.Signal("step"))
+func (c *Client) Signal(sig any) any {
+	c.t.Helper()
+	key := c.signalKey("Signal", sig)
+	return getPath(c.signalStore(), key)
+}
+
+// AssertSignal fails t unless the signal named by sig reaches want within
+// two seconds, waiting on the client's stream for the patch that sets
+// it. want is the value as text: strings bare, everything else as JSON —
+// "5", "true", "hello", `{"a":1}`. The failure lists every signal the
+// client holds.
+//
+//	tc.Action(p.Apply).Fire()
+//	tc.AssertSignal(t, "step", "5")
+func (c *Client) AssertSignal(t testing.TB, sig any, want string) {
+	t.Helper()
+	key := c.signalKey("AssertSignal", sig)
+	l := c.stream()
+	deadline := time.NewTimer(signalWait)
+	defer deadline.Stop()
+	for {
+		l.mu.Lock()
+		arrived := l.arrived
+		l.mu.Unlock()
+		all := c.signalStore()
+		got := getPath(all, key)
+		if signalText(got) == want {
+			return
+		}
+		select {
+		case <-arrived:
+			continue
+		case <-deadline.C:
+		}
+		dump, _ := json.MarshalIndent(all, "", "  ")
+		t.Fatalf("vt.Client.AssertSignal: %s = %s, want %s within %v; signals:\n%s",
+			key, signalText(got), want, signalWait, dump)
+		return
+	}
+}
+
+// signalKey resolves a wire key or a handle with a Key method.
+func (c *Client) signalKey(method string, sig any) string {
+	c.t.Helper()
+	var key string
+	switch s := sig.(type) {
+	case string:
+		key = s
+	case interface{ Key() string }:
+		key = s.Key()
+	default:
+		c.t.Fatalf("vt.Client.%s: %T is neither a signal key nor a signal handle", method, sig)
+		return ""
+	}
+	if key == "" {
+		c.t.Fatalf("vt.Client.%s: the handle has no key — it isn't bound to a tab; pass the signal's wire key", method)
+	}
+	return key
+}
+
+// signalStore returns the merged view Signal reads.
+func (c *Client) signalStore() map[string]any {
+	out := map[string]any{}
+	for _, el := range c.elements() {
+		var seed map[string]any
+		if raw, ok := el.attrs["data-signals"]; ok && json.Unmarshal([]byte(raw), &seed) == nil {
+			mergePatch(out, seed, false)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mergePatch(out, deepCopy(c.signals), false)
+	return out
+}
+
+// observe applies the patches in complete stream events received on l:
+// element patches to the client's view of the page, signal patches to
+// its signals. Events from a stream Reload replaced are dropped.
+func (c *Client) observe(l *live, events string) {
+	c.mu.Lock()
+	if c.live != l {
+		c.mu.Unlock()
+		return
+	}
+	c.view = applyElementPatches(c.view, events)
+	c.mu.Unlock()
+	c.applySignalPatches(events)
+}
+
+// applySignalPatches merges the signal patches in events into the
+// client's signals, as Datastar merges them into its store.
+func (c *Client) applySignalPatches(events string) {
+	for ev := range strings.SplitSeq(events, "\n\n") {
+		if !strings.Contains(ev, "event: datastar-patch-signals") {
+			continue
+		}
+		var body []string
+		onlyIfMissing := false
+		for line := range strings.SplitSeq(ev, "\n") {
+			if v, ok := strings.CutPrefix(line, "data: signals "); ok {
+				body = append(body, v)
+			} else if line == "data: onlyIfMissing true" {
+				onlyIfMissing = true
+			}
+		}
+		var patch map[string]any
+		if json.Unmarshal([]byte(strings.Join(body, "\n")), &patch) != nil || len(patch) == 0 {
+			continue
+		}
+		c.mu.Lock()
+		if c.signals == nil {
+			c.signals = map[string]any{}
+		}
+		mergePatch(c.signals, patch, onlyIfMissing)
+		c.mu.Unlock()
+	}
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to dst: objects merge,
+// null deletes, anything else replaces. With onlyIfMissing, existing keys
+// are left alone.
+func mergePatch(dst, patch map[string]any, onlyIfMissing bool) {
+	for k, v := range patch {
+		if v == nil {
+			if !onlyIfMissing {
+				delete(dst, k)
+			}
+			continue
+		}
+		if sub, ok := v.(map[string]any); ok {
+			cur, ok := dst[k].(map[string]any)
+			if !ok {
+				if _, exists := dst[k]; exists && onlyIfMissing {
+					continue
+				}
+				cur = map[string]any{}
+				dst[k] = cur
+			}
+			mergePatch(cur, sub, onlyIfMissing)
+			continue
+		}
+		if _, exists := dst[k]; exists && onlyIfMissing {
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+func deepCopy(m map[string]any) map[string]any {
+	out := maps.Clone(m)
+	for k, v := range out {
+		if sub, ok := v.(map[string]any); ok {
+			out[k] = deepCopy(sub)
+		}
+	}
+	return out
+}
+
+// getPath reads a dotted key out of nested signal objects.
+func getPath(m map[string]any, key string) any {
+	var v any = m
+	for part := range strings.SplitSeq(key, ".") {
+		obj, _ := v.(map[string]any)
+		v = obj[part]
+	}
+	return v
+}
+
+// setPath writes a dotted key into nested signal objects.
+func setPath(m map[string]any, key string, v any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := m[part].(map[string]any)
+		if !ok {
+			sub = map[string]any{}
+			m[part] = sub
+		}
+		m = sub
+	}
+	m[parts[len(parts)-1]] = v
+}
+
+// signalText renders a signal value the way AssertSignal compares it.
+func signalText(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -14,9 +14,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"html"
 	"io"
 	"maps"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
@@ -28,6 +30,7 @@ import (
 	"time"
 
 	"github.com/go-via/via"
+	"github.com/go-via/via/h"
 	"github.com/go-via/via/internal/spec"
 )
 
@@ -48,20 +51,46 @@ func Serve(t testing.TB, app *via.App) *httptest.Server {
 	return srv
 }
 
+// AssertAccessible fails t with each finding app's via.WithA11yAudit has
+// recorded so far — load the pages under test first — and reports whether
+// there were none.
+//
+//	app := via.New(via.WithA11yAudit())
+//	via.Mount[Signup](app, "/signup")
+//	vt.NewClient(t, vt.Serve(t, app), "/signup")
+//	vt.AssertAccessible(t, app)
+func AssertAccessible(t testing.TB, app *via.App) bool {
+	t.Helper()
+	findings := app.A11yFindings()
+	for _, f := range findings {
+		t.Errorf("a11y: %s", f)
+	}
+	return len(findings) == 0
+}
+
 // Client drives a mounted Composition over HTTP for tests.
 type Client struct {
 	t        testing.TB
 	server   *httptest.Server
 	tabID    string
+	sig      any    // via_sig from the page, when the app signs actions
 	path     string // captured at NewClient so Reload can re-fetch
 	jar      http.CookieJar
 	httpc    *http.Client
 	mu       sync.Mutex
 	lastBody string
+	live     *live         // opened by WaitForPatch / WaitForText
+	sseDelay time.Duration // set by SlowSSE
+
+	signals map[string]any // written by Change and binding pre-writes
+	view    string         // lastBody with the stream's element patches applied
+	dom     []*element     // view parsed, for the event helpers
+	domOf   string         // the view dom was parsed from
 }
 
 // NewClient performs a GET on path, picks up the rendered tab id, and is
-// ready to drive actions and signal updates against that tab.
+// ready to drive actions and signal updates against that tab. Like the
+// browser, it fires the page's on.Load and data-init actions.
 func NewClient(t testing.TB, server *httptest.Server, path string) *Client {
 	t.Helper()
 	jar, _ := cookiejar.New(nil)
@@ -76,7 +105,9 @@ func NewClient(t testing.TB, server *httptest.Server, path string) *Client {
 	if tab == "" {
 		t.Fatalf("vt.NewClient: no tab id in body of %s", path)
 	}
-	return &Client{t: t, server: server, tabID: tab, path: path, jar: jar, httpc: httpc, lastBody: string(body)}
+	c := &Client{t: t, server: server, tabID: tab, sig: sigFrom(string(body)), path: path, jar: jar, httpc: httpc, lastBody: string(body), view: string(body)}
+	c.fireInits()
+	return c
 }
 
 // TabID returns the active tab id.
@@ -98,7 +129,9 @@ func (c *Client) Fork(path string) *Client {
 	if tab == "" {
 		c.t.Fatalf("vt.Client.Fork: no tab id in body of %s", path)
 	}
-	return &Client{t: c.t, server: c.server, tabID: tab, path: path, jar: c.jar, httpc: httpc, lastBody: string(body)}
+	fork := &Client{t: c.t, server: c.server, tabID: tab, sig: sigFrom(string(body)), path: path, jar: c.jar, httpc: httpc, lastBody: string(body), view: string(body)}
+	fork.fireInits()
+	return fork
 }
 
 // HTML returns the most recently fetched page body.
@@ -121,17 +154,132 @@ func (c *Client) HTML() string {
 // If you need to assert against the original tab, capture HTML() first.
 func (c *Client) Reload() string {
 	c.t.Helper()
+	if err := c.refetch(); err != nil {
+		c.t.Fatalf("vt.Client.Reload: GET %s: %v", c.path, err)
+	}
+	c.fireInits()
+	return c.HTML()
+}
+
+// refetch is Reload short of firing the page's load actions, reporting
+// a failed GET instead of failing the test.
+func (c *Client) refetch() error {
 	resp, err := c.httpc.Get(c.server.URL + c.path)
 	if err != nil {
-		c.t.Fatalf("vt.Client.Reload: GET %s: %v", c.path, err)
+		return err
 	}
 	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
 	c.mu.Lock()
+	if c.live != nil {
+		c.live.cancel()
+		c.live = nil
+	}
 	c.lastBody = string(body)
+	c.view = c.lastBody
 	c.tabID = tabIDFrom(c.lastBody)
+	c.sig = sigFrom(c.lastBody)
+	c.signals = nil
 	c.mu.Unlock()
-	return c.lastBody
+	return nil
+}
+
+// SetHidden reports a page visibility change for this tab the way the
+// browser's visibility listener does (installed by Ticker.PauseWhileHidden),
+// returning the HTTP status.
+func (c *Client) SetHidden(hidden bool) int {
+	c.t.Helper()
+	state := "visible"
+	if hidden {
+		state = "hidden"
+	}
+	resp, err := c.httpc.Post(c.server.URL+"/_sse/visibility", "text/plain",
+		strings.NewReader(c.TabID()+" "+state))
+	if err != nil {
+		c.t.Fatalf("vt.Client.SetHidden: %v", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+// Reveal reports that the via.CtxR.Lazy region name has scrolled into
+// view, the way its intersection trigger does in the browser, returning
+// the HTTP status. The content arrives as an element patch on the SSE
+// stream.
+func (c *Client) Reveal(name string) int {
+	c.t.Helper()
+	resp, err := c.httpc.Post(c.server.URL+"/_sse/lazy", "text/plain",
+		strings.NewReader(c.TabID()+" "+name+c.beaconSig("lazy")))
+	if err != nil {
+		c.t.Fatalf("vt.Client.Reveal: %v", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+// PopState reports that the user went back or forward to the history
+// entry at url (a path with an optional query), the way the listener
+// installed by via.Ctx.PushURL does, returning the HTTP status.
+func (c *Client) PopState(url string) int {
+	c.t.Helper()
+	resp, err := c.httpc.Post(c.server.URL+"/_sse/popstate", "text/plain",
+		strings.NewReader(c.TabID()+" "+url+c.beaconSig("popstate")))
+	if err != nil {
+		c.t.Fatalf("vt.Client.PopState: %v", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+// beaconSig returns the " <token>" a signed page appends to the named
+// runtime beacon, or "" when the app doesn't sign actions.
+func (c *Client) beaconSig(name string) string {
+	sigs, _ := c.sig.(map[string]any)
+	if tok, _ := sigs[name].(string); tok != "" {
+		return " " + tok
+	}
+	return ""
+}
+
+// AnswerEval replies to the via.Ctx.Eval request in frame — the SSE
+// content AwaitFrame returned for it — the way the browser does once the
+// expression settles, with value JSON-encoded. Returns the HTTP status.
+//
+//	frame := vt.AwaitFrame(t, frames, time.Second, "/_sse/eval")
+//	tc.AnswerEval(frame, map[string]int{"width": 640})
+func (c *Client) AnswerEval(frame string, value any) int {
+	c.t.Helper()
+	b, err := json.Marshal(map[string]any{"v": value})
+	if err != nil {
+		c.t.Fatalf("vt.Client.AnswerEval: %v", err)
+	}
+	return c.replyEval("AnswerEval", frame, string(b))
+}
+
+// FailEval replies to the via.Ctx.Eval request in frame as if the
+// expression threw message. Returns the HTTP status.
+func (c *Client) FailEval(frame, message string) int {
+	c.t.Helper()
+	b, _ := json.Marshal(map[string]string{"e": message})
+	return c.replyEval("FailEval", frame, string(b))
+}
+
+func (c *Client) replyEval(what, frame, reply string) int {
+	c.t.Helper()
+	// The key is "<tab> <eval id>", plus a signature when the app signs.
+	re := regexp.MustCompile(`/_sse/eval","(` + regexp.QuoteMeta(c.TabID()) + ` \d+(?: [^"]+)?)"`)
+	m := re.FindAllStringSubmatch(frame, -1)
+	if m == nil {
+		c.t.Fatalf("vt.Client.%s: no Eval request for this tab in frame", what)
+	}
+	key := m[len(m)-1][1]
+	resp, err := c.httpc.Post(c.server.URL+"/_sse/eval", "text/plain",
+		strings.NewReader(key+" "+reply))
+	if err != nil {
+		c.t.Fatalf("vt.Client.%s: %v", what, err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
 }
 
 // Action returns a handle that fires an action. The target may be either
@@ -155,6 +303,8 @@ type ActionCall struct {
 	name    string
 	signals map[string]any
 	files   []actionFile
+	header  http.Header
+	args    url.Values
 }
 
 type actionFile struct {
@@ -181,6 +331,26 @@ func (a *ActionCall) WithSignal(name string, value any) *ActionCall {
 	return a
 }
 
+// WithArg adds an event argument to the action URL, as an on.Arg binding
+// does in the browser. The action reads it with ctx.EventArg(name).
+func (a *ActionCall) WithArg(name, value string) *ActionCall {
+	if a.args == nil {
+		a.args = url.Values{}
+	}
+	a.args.Add(name, value)
+	return a
+}
+
+// WithHeader adds a request header to the action POST — e.g. the header an
+// on.Optimistic trigger attaches in the browser.
+func (a *ActionCall) WithHeader(key, value string) *ActionCall {
+	if a.header == nil {
+		a.header = http.Header{}
+	}
+	a.header.Add(key, value)
+	return a
+}
+
 // WithFile attaches a file part to the action POST. Adding any file
 // switches the request from JSON to multipart/form-data; signals added
 // via WithSignal ride along as text fields. Repeat calls add multiple
@@ -201,19 +371,39 @@ func (a *ActionCall) Fire() int {
 	if len(a.files) > 0 {
 		return a.fireMultipart()
 	}
+	status, err := a.send()
+	if err != nil {
+		a.client.t.Fatalf("vt.Action(%s).Fire: %v", a.name, err)
+	}
+	return status
+}
+
+// send posts a JSON action, reporting a transport error instead of
+// failing the test.
+func (a *ActionCall) send() (int, error) {
 	body := map[string]any{"via_tab": a.client.tabID}
+	if a.client.sig != nil {
+		body["via_sig"] = a.client.sig
+	}
 	maps.Copy(body, a.signals)
 	buf, _ := json.Marshal(body)
-	resp, err := a.client.httpc.Post(
-		a.client.server.URL+"/_action/"+a.name,
-		"application/json",
-		bytes.NewReader(buf),
-	)
+	req, _ := http.NewRequest("POST", a.url(), bytes.NewReader(buf))
+	maps.Copy(req.Header, a.header)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.httpc.Do(req)
 	if err != nil {
-		a.client.t.Fatalf("vt.Action(%s).Fire: %v", a.name, err)
+		return 0, err
 	}
 	resp.Body.Close()
-	return resp.StatusCode
+	return resp.StatusCode, nil
+}
+
+func (a *ActionCall) url() string {
+	u := a.client.server.URL + "/_action/" + a.name
+	if len(a.args) > 0 {
+		u += "?" + a.args.Encode()
+	}
+	return u
 }
 
 func (a *ActionCall) fireMultipart() int {
@@ -224,6 +414,10 @@ func (a *ActionCall) fireMultipart() int {
 	var buf bytes.Buffer
 	mw := multipart.NewWriter(&buf)
 	_ = mw.WriteField("via_tab", a.client.tabID)
+	if a.client.sig != nil {
+		sig, _ := json.Marshal(a.client.sig)
+		_ = mw.WriteField("via_sig", string(sig))
+	}
 	for k, v := range a.signals {
 		_ = mw.WriteField(k, scalarToFormValue(v))
 	}
@@ -233,7 +427,8 @@ func (a *ActionCall) fireMultipart() int {
 	}
 	_ = mw.Close()
 
-	req, _ := http.NewRequest("POST", a.client.server.URL+"/_action/"+a.name, &buf)
+	req, _ := http.NewRequest("POST", a.url(), &buf)
+	maps.Copy(req.Header, a.header)
 	req.Header.Set("Content-Type", mw.FormDataContentType())
 	resp, err := a.client.httpc.Do(req)
 	if err != nil {
@@ -275,7 +470,7 @@ func AwaitFrame(t testing.TB, frames <-chan string, timeout time.Duration, needl
 		select {
 		case f, ok := <-frames:
 			if !ok {
-				t.Fatalf("SSE closed before seeing %v; got %q", needles, got.String())
+				t.Fatalf("SSE closed before seeing %v; got:\n%s", needles, prettyFrames(got.String()))
 				return got.String()
 			}
 			got.WriteString(f)
@@ -291,12 +486,41 @@ func AwaitFrame(t testing.TB, frames <-chan string, timeout time.Duration, needl
 				return accum
 			}
 		case <-deadline:
-			t.Fatalf("did not see %v within %v; got %q", needles, timeout, got.String())
+			t.Fatalf("did not see %v within %v; got:\n%s", needles, timeout, prettyFrames(got.String()))
 			return got.String()
 		}
 	}
 }
 
+// Pretty indents html one block-level element per line (see h.Pretty),
+// for reading a page in a failure message:
+//
+//	if !strings.Contains(tc.HTML(), want) {
+//	    t.Fatalf("missing %q in\n%s", want, vt.Pretty(tc.HTML()))
+//	}
+func Pretty(html string) string {
+	var b strings.Builder
+	_ = h.Render(&b, h.Raw(html), h.Pretty())
+	return b.String()
+}
+
+// prettyFrames lays out SSE frame text for a failure message, with each
+// element patch's HTML indented under its data line.
+func prettyFrames(s string) string {
+	var b strings.Builder
+	for line := range strings.SplitSeq(strings.TrimRight(s, "\n"), "\n") {
+		if markup, ok := strings.CutPrefix(line, "data: elements "); ok {
+			b.WriteString("data: elements\n")
+			for l := range strings.SplitSeq(Pretty(markup), "\n") {
+				b.WriteString("    " + l + "\n")
+			}
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
 // SSEReady opens an SSE stream and blocks until the server's handshake
 // comment (`: ready`) arrives, signalling that the server-side SSE
 // goroutine has entered its select loop and is registered to receive
@@ -321,22 +545,64 @@ func (c *Client) SSEReady() (frames <-chan string, cancel func()) {
 // waits should be bounded with AwaitFrame; cancel with the returned func.
 func (c *Client) SSE() (frames <-chan string, cancel func()) {
 	c.t.Helper()
+	c.mu.Lock()
+	delay := c.sseDelay
+	c.mu.Unlock()
+	return c.openSSE("", delay)
+}
+
+// openSSE opens the tab's stream, reporting lastEventID as Datastar's
+// retry does when it's set, and pausing delay between reads (SlowSSE).
+func (c *Client) openSSE(lastEventID string, delay time.Duration) (frames <-chan string, cancel func()) {
+	c.t.Helper()
+	frames, cancel, err := c.dialSSE(lastEventID, delay)
+	if err != nil {
+		c.t.Fatalf("vt.SSE: %v", err)
+		return frames, cancel
+	}
+	// Register cleanup so the reader goroutine + connection don't leak if a
+	// test t.Fatals before reaching its `defer cancel()`. cancel is idempotent
+	// (context cancel + Body.Close both tolerate a second call).
+	c.t.Cleanup(cancel)
+	return frames, cancel
+}
+
+// dialSSE is openSSE reporting a failed connect instead of failing the
+// test; on error the returned channel is closed and cancel is a no-op.
+func (c *Client) dialSSE(lastEventID string, delay time.Duration) (frames <-chan string, cancel func(), err error) {
 	out := make(chan string, 16)
 	ctx, cancelF := context.WithCancel(context.Background())
 	url := c.server.URL + "/_sse?datastar=" + sseQueryParam(c.tabID)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	sseClient := &http.Client{Jar: c.jar, Transport: &http.Transport{}} // no timeout — SSE is long-lived
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	transport := &http.Transport{}
+	size := 4096
+	if delay > 0 {
+		// A small receive window, so the server's writes back up soon.
+		size = 512
+		transport.DialContext = dialSmallWindow
+	}
+	sseClient := &http.Client{Jar: c.jar, Transport: transport} // no timeout — SSE is long-lived
 	resp, err := sseClient.Do(req)
 	if err != nil {
 		cancelF()
 		close(out)
-		c.t.Fatalf("vt.SSE: %v", err)
+		return out, func() {}, err
 	}
 	go func() {
 		defer close(out)
 		defer resp.Body.Close()
-		buf := make([]byte, 4096)
+		buf := make([]byte, size)
 		for {
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return
+				}
+			}
 			n, err := resp.Body.Read(buf)
 			if n > 0 {
 				out <- string(buf[:n])
@@ -346,16 +612,20 @@ func (c *Client) SSE() (frames <-chan string, cancel func()) {
 			}
 		}
 	}()
-	cancel = func() { cancelF(); resp.Body.Close() }
-	// Register cleanup so the reader goroutine + connection don't leak if a
-	// test t.Fatals before reaching its `defer cancel()`. cancel is idempotent
-	// (context cancel + Body.Close both tolerate a second call).
-	c.t.Cleanup(cancel)
-	return out, cancel
+	return out, func() { cancelF(); resp.Body.Close() }, nil
 }
 
 // helpers
 
+// dialSmallWindow dials with a small socket receive buffer, for SlowSSE.
+func dialSmallWindow(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetReadBuffer(4096)
+	}
+	return conn, err
+}
+
 // tabRE picks the via_tab id out of the data-signals attribute on the
 // rendered <meta>. The id is `<route>_<64-hex>`; the route can contain
 // any URL-safe characters (including `/`), so we match the suffix and
@@ -370,6 +640,23 @@ func tabIDFrom(html string) string {
 	return m[1]
 }
 
+// signalsRE picks the data-signals attribute off the rendered <meta>.
+var signalsRE = regexp.MustCompile(`data-signals="([^"]*)"`)
+
+// sigFrom returns the page's via_sig signal (the action signatures of an
+// app using via.WithActionSigning), or nil.
+func sigFrom(page string) any {
+	m := signalsRE.FindStringSubmatch(page)
+	if m == nil {
+		return nil
+	}
+	var sigs map[string]any
+	if json.Unmarshal([]byte(html.UnescapeString(m[1])), &sigs) != nil {
+		return nil
+	}
+	return sigs["via_sig"]
+}
+
 // TabIDFromHTML extracts the via_tab id from a rendered page's data-signals
 // meta (the id is `<route>_<64-hex>`). The HTML escapes the JSON quotes, so
 // tests reaching for the tab id directly should use this rather than
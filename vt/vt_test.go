@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -364,3 +365,526 @@ func TestWithSignal_warnsOnUnderscoreLocalSignal(t *testing.T) {
 	vt.NewClient(rec2, srv, "/").Action("Apply").WithSignal("step", 5)
 	assert.Empty(t, rec2.logs, "a normal signal name must not warn")
 }
+
+// fatalTB records Fatalf instead of stopping the test, so a test can
+// assert on vt's failure message.
+type fatalTB struct {
+	testing.TB
+	fatals []string
+}
+
+func (f *fatalTB) Helper() {}
+func (f *fatalTB) Fatalf(format string, args ...any) {
+	f.fatals = append(f.fatals, fmt.Sprintf(format, args...))
+}
+
+type unlabelledPage struct{}
+
+func (p *unlabelledPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.Img(h.ID("logo"), h.Src("/logo.png")))
+}
+
+func TestAssertAccessible_failsWithEachFinding(t *testing.T) {
+	t.Parallel()
+	app := via.New(via.WithA11yAudit())
+	via.Mount[unlabelledPage](app, "/")
+	via.Mount[tcPage](app, "/ok")
+	srv := vt.Serve(t, app)
+
+	vt.NewClient(t, srv, "/ok")
+	assert.True(t, vt.AssertAccessible(t, app))
+
+	vt.NewClient(t, srv, "/")
+	rec := &errorTB{TB: t}
+	assert.False(t, vt.AssertAccessible(rec, app))
+	assert.Equal(t, []string{"a11y: img without alt on /: #logo"}, rec.errors)
+}
+
+func TestAwaitFrame_failureDumpIndentsPatchedHTML(t *testing.T) {
+	t.Parallel()
+	frames := make(chan string, 1)
+	frames <- "event: datastar-patch-elements\ndata: elements <div id=\"c\"><p>x</p></div>\n\n"
+	close(frames)
+
+	rec := &fatalTB{TB: t}
+	vt.AwaitFrame(rec, frames, time.Second, "<p>y</p>")
+	require.Len(t, rec.fatals, 1)
+	assert.Contains(t, rec.fatals[0],
+		"data: elements\n    <div id=\"c\">\n      <p>x</p>\n    </div>\n")
+}
+
+func TestPretty_indentsAPage(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "<ul>\n  <li>a</li>\n</ul>", vt.Pretty("<ul><li>a</li></ul>"))
+}
+
+func TestClient_WaitForPatch_returnsEachPatchInTurn(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	// Fired before the stream opens: the patch waits in the queue.
+	require.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+	assert.Contains(t, tc.WaitForPatch(2*time.Second), ">1<")
+
+	require.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+	got := tc.WaitForPatch(2 * time.Second)
+	assert.Contains(t, got, ">2<")
+	assert.NotContains(t, got, ">1<", "a patch is returned once")
+}
+
+func TestClient_WaitForText_blocksUntilThePatchShowsIt(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	for range 3 {
+		require.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+	}
+	tc.WaitForText(">3<", 2*time.Second)
+
+	tc.Reload()
+	require.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+	// The reloaded tab is a fresh one, with its own state and stream.
+	tc.WaitForText(">1<", 2*time.Second)
+}
+
+func TestClient_WaitForText_failsWithWhatArrived(t *testing.T) {
+	t.Parallel()
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	rec := &fatalTB{TB: t}
+	tc := vt.NewClient(rec, vt.Serve(t, app), "/")
+
+	require.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+	tc.WaitForText(">1<", 2*time.Second)
+	tc.WaitForText("never", 50*time.Millisecond)
+	require.Len(t, rec.fatals, 1)
+	assert.Contains(t, rec.fatals[0], `vt.Client.WaitForText: no "never" within 50ms`)
+	assert.Contains(t, rec.fatals[0], ">1<")
+}
+
+type formPage struct {
+	Query  via.SignalStr  `via:"q"`
+	Done   via.SignalBool `via:"done"`
+	Name   via.SignalStr  `via:"name"`
+	Status via.StateTabStr
+	Loads  via.StateTabNum[int]
+}
+
+func (p *formPage) Search(ctx *via.Ctx) error {
+	p.Status.Write(ctx, "searched "+p.Query.Read(ctx))
+	return nil
+}
+
+func (p *formPage) Toggle(ctx *via.Ctx) error {
+	p.Status.Write(ctx, fmt.Sprintf("done=%v", p.Done.Read(ctx)))
+	return nil
+}
+
+func (p *formPage) Save(ctx *via.Ctx) error {
+	p.Status.Write(ctx, "saved "+p.Name.Read(ctx))
+	return nil
+}
+
+func (p *formPage) Close(ctx *via.Ctx) error {
+	p.Status.Write(ctx, "closed")
+	return nil
+}
+
+func (p *formPage) Loaded(ctx *via.Ctx) error {
+	p.Loads.Write(ctx, p.Loads.Read(ctx)+1)
+	return nil
+}
+
+func (p *formPage) View(ctx *via.CtxR) h.H {
+	return h.Div(on.Load(p.Loaded), on.Hotkey("escape", p.Close),
+		h.P(h.ID("status"), p.Status.Text(ctx)),
+		h.P(h.ID("loads"), p.Loads.Text(ctx)),
+		h.Input(h.ID("search"), p.Query.Bind(), on.Key("Enter", p.Search)),
+		h.Input(h.Type("checkbox"), h.Class("toggle"), p.Done.Bind(), on.Change(p.Toggle)),
+		h.Form(h.ID("profile"), on.Submit(p.Save, on.Prevent()),
+			h.Input(h.Name("name"), p.Name.Bind(), h.Value("Ada")),
+			h.Button(h.Type("submit"), h.Text("Save")),
+		),
+	)
+}
+
+func TestClient_Press_firesTheKeyBindingWithTheTypedValue(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	assert.Equal(t, 0, tc.Change("#search", "gophers"), "the search input has no change binding")
+	assert.Equal(t, 0, tc.Press("Tab", "#search"), "on.Key(Enter) must not fire for another key")
+	require.Equal(t, http.StatusOK, tc.Press("Enter", "#search"))
+	tc.WaitForText("searched gophers", 2*time.Second)
+}
+
+func TestClient_Press_firesPageWideHotkeys(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	require.Equal(t, http.StatusOK, tc.Press("Escape", "#status"))
+	tc.WaitForText("closed", 2*time.Second)
+}
+
+func TestClient_Change_sendsTheValueAsTheSignalsType(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	require.Equal(t, http.StatusOK, tc.Change("input.toggle", "true"))
+	tc.WaitForText("done=true", 2*time.Second)
+}
+
+func TestClient_Submit_sendsTheFormAsFilledIn(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	require.Equal(t, http.StatusOK, tc.Submit("#profile button"))
+	tc.WaitForText("saved Ada", 2*time.Second)
+
+	tc.Change("form input[name=name]", "Grace")
+	require.Equal(t, http.StatusOK, tc.Submit("#profile"))
+	tc.WaitForText("saved Grace", 2*time.Second)
+}
+
+func TestClient_FireAll_firesEveryBindingWithItsOwnKey(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	var actions []string
+	for _, f := range tc.FireAll() {
+		assert.Equal(t, http.StatusOK, f.Status, f.Action)
+		actions = append(actions, f.Action)
+	}
+	// on.Key(Enter) and on.Hotkey(escape) got their keys, or they'd be 0.
+	assert.Equal(t, []string{"Close", "Loaded", "Search", "Toggle", "Save"}, actions, "document order, then attribute name")
+}
+
+func TestNewClient_firesLoadActions(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	tc.WaitForText(`<p id="loads">1</p>`, 2*time.Second)
+	tc.Reload()
+	tc.WaitForText(`<p id="loads">1</p>`, 2*time.Second)
+}
+
+func TestClient_Click_bubblesToTheNearestBinding(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	require.Equal(t, http.StatusOK, tc.Click("div button"))
+	tc.WaitForText(">1<", 2*time.Second)
+	assert.Equal(t, 0, tc.Click("div"), "a click on the div doesn't reach the button's binding")
+}
+
+func TestClient_Click_failsWhenNothingMatches(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	ft := &fatalTB{TB: t}
+	tc := vt.NewClient(ft, vt.Serve(t, app), "/")
+
+	assert.Equal(t, 0, tc.Click("#missing"))
+	require.Len(t, ft.fatals, 1)
+	assert.Contains(t, ft.fatals[0], `nothing matches "#missing"`)
+}
+
+type signalPage struct {
+	Step  via.SignalNum[int] `via:"step,init=1"`
+	Label via.SignalStr      `via:"label,init=hello"`
+}
+
+func (p *signalPage) Apply(ctx *via.Ctx) error {
+	p.Step.Write(ctx, 5)
+	return nil
+}
+
+func (p *signalPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Step.Text(), h.Button(h.ID("apply"), on.Click(p.Apply)))
+}
+
+func TestClient_Signal_readsTheSeedThenPatches(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[signalPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	assert.Equal(t, 1.0, tc.Signal("step"))
+	assert.Equal(t, "hello", tc.Signal("label"))
+	assert.Nil(t, tc.Signal("missing"))
+
+	require.Equal(t, http.StatusOK, tc.Click("#apply"))
+	tc.AssertSignal(t, "step", "5")
+	assert.Equal(t, 5.0, tc.Signal("step"))
+	assert.Equal(t, "hello", tc.Signal("label"), "a patch leaves other signals alone")
+}
+
+func TestClient_AssertSignal_dumpsTheSignalsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[signalPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	ft := &fatalTB{TB: t}
+	tc.AssertSignal(ft, "label", "bye")
+	require.Len(t, ft.fatals, 1)
+	assert.Contains(t, ft.fatals[0], "label = hello, want bye")
+	assert.Contains(t, ft.fatals[0], `"step": 1`)
+}
+
+func TestClient_Signal_rejectsAnUnboundHandle(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[signalPage](app, "/")
+	ft := &fatalTB{TB: t}
+	tc := vt.NewClient(ft, vt.Serve(t, app), "/")
+
+	tc.Signal(&(&signalPage{}).Step)
+	require.Len(t, ft.fatals, 1)
+	assert.Contains(t, ft.fatals[0], "isn't bound to a tab")
+}
+
+// disconnects counts SSE loops the server has left.
+type disconnects struct{ n atomic.Int32 }
+
+func (d *disconnects) Counter(name string, _ ...string) {
+	if name == "via.sse.disconnect" {
+		d.n.Add(1)
+	}
+}
+func (d *disconnects) Gauge(string, float64, ...string)     {}
+func (d *disconnects) Histogram(string, float64, ...string) {}
+
+type feedPage struct {
+	N    via.StateTabNum[int]
+	Blob via.StateTabStr
+}
+
+func (p *feedPage) Bump(ctx *via.Ctx) error {
+	p.N.Write(ctx, p.N.Read(ctx)+1)
+	return nil
+}
+
+// Flood re-renders a view big enough to back up a slow reader.
+func (p *feedPage) Flood(ctx *via.Ctx) error {
+	p.N.Write(ctx, p.N.Read(ctx)+1)
+	p.Blob.Write(ctx, strings.Repeat(fmt.Sprintf("flood %d ", p.N.Read(ctx)), 1<<16))
+	return nil
+}
+
+func (p *feedPage) View(ctx *via.CtxR) h.H {
+	return h.Div(h.P(h.ID("n"), p.N.Text(ctx)), h.P(p.Blob.Text(ctx)))
+}
+
+func TestClient_ReconnectSSE_deliversWhatQueuedWhileDown(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[feedPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	tc.Action("Bump").Fire()
+	tc.WaitForText(`<p id="n">1</p>`, 2*time.Second)
+	tc.DisconnectSSE()
+	tc.Action("Bump").Fire()
+	tc.Action("Bump").Fire()
+	tc.ReconnectSSE()
+	tc.WaitForText(`<p id="n">3</p>`, 2*time.Second)
+}
+
+func TestClient_SlowSSE_backsUpUntilTheServerDropsTheStream(t *testing.T) {
+	t.Parallel()
+
+	d := &disconnects{}
+	app := via.New(via.WithMetrics(d), via.WithSSEWriteTimeout(250*time.Millisecond))
+	via.Mount[feedPage](app, "/")
+	tc := vt.NewClient(t, vt.Serve(t, app), "/")
+
+	tc.SlowSSE(100 * time.Millisecond)
+	tc.WaitForText(": ready", 2*time.Second)
+	// Socket buffers absorb a few renders first; keep flooding until one
+	// backs up past the write timeout.
+	floods := 0
+	require.Eventually(t, func() bool {
+		tc.Action("Flood").Fire()
+		floods++
+		return d.n.Load() > 0
+	}, 20*time.Second, 10*time.Millisecond, "the write timeout must drop a stream that can't keep up")
+
+	tc.DisconnectSSE()
+	tc.SlowSSE(0)
+	tc.ReconnectSSE()
+	tc.WaitForText(fmt.Sprintf(`<p id="n">%d</p>`, floods), 5*time.Second)
+}
+
+// formFlow drives formPage through vt.Page, as a browser-backed Page
+// would run it too.
+func formFlow(p vt.Page) {
+	p.Visit("/")
+	p.AssertText("#loads", "1")
+	p.Fill("#search", "gophers")
+	p.Press("Enter", "#search")
+	p.AssertText("#status", "searched gophers")
+	p.Fill("input.toggle", "true")
+	p.AssertText("#status", "done=true")
+	p.Fill("form input[name=name]", "Grace")
+	p.Submit("#profile")
+	p.AssertText("#status", "saved Grace")
+}
+
+func TestOpenPage_runsAFlowInProcess(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	formFlow(vt.OpenPage(t, app))
+}
+
+func TestOpenPage_clickSeesThePatchedPage(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[tcPage](app, "/")
+	p := vt.OpenPage(t, app)
+
+	p.Visit("/")
+	for want := range 3 {
+		p.Click("div button")
+		p.AssertText("div", fmt.Sprintf("%d+", want+1))
+	}
+}
+
+func TestOpenPage_AssertTextReportsTheLastText(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[formPage](app, "/")
+	ft := &fatalTB{TB: t}
+	p := vt.OpenPage(ft, app)
+
+	p.Visit("/")
+	p.AssertText("#loads", "7")
+	require.Len(t, ft.fatals, 1)
+	assert.Contains(t, ft.fatals[0], `"#loads" never showed "7"`)
+	assert.Contains(t, ft.fatals[0], `last text: "1"`)
+}
+
+type stormPage struct {
+	Hits via.StateAppNum[int]
+	N    via.StateTabNum[int]
+	Step via.SignalNum[int] `via:"step,init=1"`
+}
+
+func (p *stormPage) Hit(ctx *via.Ctx) error {
+	p.Hits.Op(ctx).Inc()
+	return nil
+}
+
+func (p *stormPage) Bump(ctx *via.Ctx) error {
+	p.N.Write(ctx, p.N.Read(ctx)+p.Step.Read(ctx))
+	return nil
+}
+
+func (p *stormPage) View(ctx *via.CtxR) h.H {
+	return h.Div(
+		h.P(h.ID("hits"), h.Textf("hits=%d", p.Hits.Read(ctx))),
+		h.Button(h.Text("hit"), on.Click(p.Hit)),
+		h.Button(h.Text("bump"), on.Click(p.Bump)),
+	)
+}
+
+// errorTB records Errorf instead of failing the test, so a test can
+// assert on what vt reported.
+type errorTB struct {
+	testing.TB
+	mu     sync.Mutex
+	errors []string
+}
+
+func (e *errorTB) Helper() {}
+func (e *errorTB) Errorf(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFuzz_firesEveryBoundActionFromEveryTab(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[stormPage](app, "/")
+	srv := vt.Serve(t, app)
+
+	checked := 0
+	res := vt.Fuzz(t, srv, "/", vt.FuzzConfig{
+		Calls:   100,
+		Signals: map[string][]any{"step": {0, 1, -3, 1000}},
+		Check: func(tc *vt.Client) {
+			checked++
+			assert.Equal(t, http.StatusOK, tc.Action("Bump").Fire())
+		},
+	})
+
+	assert.Equal(t, 4, checked, "Check runs once per tab")
+	assert.Equal(t, 100, res.Calls["Hit"]+res.Calls["Bump"])
+	assert.Positive(t, res.Calls["Hit"])
+	assert.Positive(t, res.Calls["Bump"])
+	assert.Contains(t, vt.NewClient(t, srv, "/").HTML(), fmt.Sprintf("hits=%d", res.Calls["Hit"]),
+		"every Hit the storm fired must land exactly once")
+}
+
+func TestFuzz_sameSeedPlansTheSameStorm(t *testing.T) {
+	t.Parallel()
+
+	run := func() vt.FuzzResult {
+		app := via.New()
+		via.Mount[stormPage](app, "/")
+		return vt.Fuzz(t, vt.Serve(t, app), "/", vt.FuzzConfig{Seed: 42, Tabs: 2, Calls: 40})
+	}
+	first, second := run(), run()
+	assert.Equal(t, int64(42), first.Seed)
+	assert.Equal(t, first.Calls, second.Calls)
+}
+
+func TestFuzz_reportsFailedCallsWithTheSeed(t *testing.T) {
+	t.Parallel()
+
+	app := via.New()
+	via.Mount[stormPage](app, "/")
+	et := &errorTB{TB: t}
+	vt.Fuzz(et, vt.Serve(t, app), "/", vt.FuzzConfig{Seed: 7, Tabs: 2, Calls: 4, Actions: []string{"Nope"}})
+
+	require.NotEmpty(t, et.errors)
+	assert.Contains(t, et.errors[0], "Nope")
+	assert.Contains(t, et.errors[0], "status 404")
+	assert.Contains(t, et.errors[len(et.errors)-1], "VT_FUZZ_SEED=7")
+}
@@ -0,0 +1,164 @@
+package vt
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// live is a Client's own SSE stream, opened by the first WaitForPatch,
+// WaitForText or AssertSignal and read into text as frames arrive. Its
+// signal patches are merged into the client's signals.
+type live struct {
+	mu      sync.Mutex
+	text    strings.Builder
+	next    int           // offset of the first event WaitForPatch hasn't returned
+	arrived chan struct{} // closed, and replaced, whenever text grows
+	closed  bool
+	cancel  func()
+	done    chan struct{} // closed once the reader has stopped
+	lastID  string        // id of the last event received
+}
+
+// heartbeat is the keepalive event the server sends on an idle stream.
+const heartbeat = "event: datastar-patch-signals\ndata: signals {}"
+
+// WaitForPatch blocks until the tab's next patch event arrives and
+// returns it, failing the test if none does within timeout. Each call
+// returns a later event than the one before, skipping keepalives:
+//
+//	tc.Action(p.Bump).Fire()
+//	assert.Contains(t, tc.WaitForPatch(time.Second), "<span>1</span>")
+//
+// The first WaitForPatch or WaitForText opens the client's stream;
+// patches queued before that are delivered when it connects, so firing
+// first and waiting after doesn't miss them. Reload closes the stream
+// and the next wait opens one for the new tab. Don't mix these waits
+// with SSE on the same client: the tab's patches go to one stream.
+func (c *Client) WaitForPatch(timeout time.Duration) string {
+	c.t.Helper()
+	return c.await("WaitForPatch", "a patch", timeout, func(l *live) (string, bool) {
+		s := l.text.String()
+		for {
+			end := strings.Index(s[l.next:], "\n\n")
+			if end < 0 {
+				return "", false
+			}
+			ev := s[l.next : l.next+end]
+			l.next += end + 2
+			if strings.Contains(ev, "event: datastar-patch-") && ev != heartbeat {
+				return ev + "\n\n", true
+			}
+		}
+	})
+}
+
+// WaitForText blocks until text shows up in a patch the tab received
+// since its stream opened, failing the test if it doesn't within
+// timeout. It returns everything received so far. Patches carry HTML, so
+// match escaped text: "a &amp; b", not "a & b".
+//
+//	tc.Action(p.Bump).Fire()
+//	tc.WaitForText("Count: 1", time.Second)
+func (c *Client) WaitForText(text string, timeout time.Duration) string {
+	c.t.Helper()
+	return c.await("WaitForText", strconv.Quote(text), timeout, func(l *live) (string, bool) {
+		s := l.text.String()
+		return s, strings.Contains(s, text)
+	})
+}
+
+// await re-checks match each time the stream grows until it reports a
+// hit, the stream closes, or timeout passes.
+func (c *Client) await(method, want string, timeout time.Duration, match func(*live) (string, bool)) string {
+	c.t.Helper()
+	l := c.stream()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		l.mu.Lock()
+		got, ok := match(l)
+		arrived, closed, text := l.arrived, l.closed, l.text.String()
+		l.mu.Unlock()
+		if ok {
+			return got
+		}
+		if closed {
+			c.t.Fatalf("vt.Client.%s: stream closed before %s arrived; got:\n%s", method, want, prettyFrames(text))
+			return ""
+		}
+		select {
+		case <-arrived:
+		case <-deadline.C:
+			c.t.Fatalf("vt.Client.%s: no %s within %v; got:\n%s", method, want, timeout, prettyFrames(text))
+			return ""
+		}
+	}
+}
+
+// stream returns the client's live stream, opening it on first use.
+func (c *Client) stream() *live {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.live != nil {
+		return c.live
+	}
+	l := &live{arrived: make(chan struct{})}
+	c.live = l
+	c.attach(l, "", c.sseDelay)
+	return l
+}
+
+// attach opens a stream reporting lastEventID and reads it into l,
+// merging its signal patches and noting the event ids it carries.
+func (c *Client) attach(l *live, lastEventID string, delay time.Duration) {
+	c.t.Helper()
+	frames, cancel := c.openSSE(lastEventID, delay)
+	done := make(chan struct{})
+	l.mu.Lock()
+	l.cancel, l.done, l.closed = cancel, done, false
+	l.mu.Unlock()
+	go func() {
+		defer close(done)
+		var pending []byte // events not yet observed, the last maybe partial
+		for f := range frames {
+			// Only the new bytes (and the one before) can complete an event.
+			from := max(len(pending)-1, 0)
+			pending = append(pending, f...)
+			if end := strings.LastIndex(string(pending[from:]), "\n\n"); end >= 0 {
+				end += from
+				events := string(pending[:end])
+				if id := lastID(events); id != "" {
+					l.mu.Lock()
+					l.lastID = id
+					l.mu.Unlock()
+				}
+				c.observe(l, events)
+				pending = append(pending[:0], pending[end+2:]...)
+			}
+			l.mu.Lock()
+			l.text.WriteString(f)
+			close(l.arrived)
+			l.arrived = make(chan struct{})
+			l.mu.Unlock()
+		}
+		l.mu.Lock()
+		l.closed = true
+		close(l.arrived)
+		l.arrived = make(chan struct{})
+		l.mu.Unlock()
+	}()
+}
+
+// lastID returns the id of the last event in events, or "".
+func lastID(events string) string {
+	id := ""
+	for line := range strings.SplitSeq(events, "\n") {
+		if v, ok := strings.CutPrefix(line, "id: "); ok {
+			id = v
+		}
+	}
+	return id
+}
@@ -0,0 +1,224 @@
+package vtbrowser_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vtbrowser"
+	"github.com/stretchr/testify/assert"
+)
+
+type modifierPage struct {
+	Query    via.SignalStr `via:"q"`
+	Searched via.StateTabStr
+	Onces    via.StateTabNum[int]
+	Hits     via.StateTabNum[int]
+	Seen     via.StateTabNum[int]
+	Scrolls  via.StateTabNum[int]
+}
+
+func (p *modifierPage) Search(ctx *via.Ctx) { p.Searched.Write(ctx, p.Query.Read(ctx)) }
+func (p *modifierPage) Once(ctx *via.Ctx)   { p.Onces.Op(ctx).Add(1) }
+func (p *modifierPage) Hit(ctx *via.Ctx)    { p.Hits.Op(ctx).Add(1) }
+func (p *modifierPage) Show(ctx *via.Ctx)   { p.Seen.Op(ctx).Add(1) }
+func (p *modifierPage) Scroll(ctx *via.Ctx) { p.Scrolls.Op(ctx).Add(1) }
+
+func (p *modifierPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.Style("margin-top:6rem"),
+		h.Span(h.ID("searched"), p.Searched.Text(ctx)),
+		h.Span(h.ID("onces"), p.Onces.Text(ctx)),
+		h.Span(h.ID("hits"), p.Hits.Text(ctx)),
+		h.Span(h.ID("seen"), p.Seen.Text(ctx)),
+		h.Span(h.ID("scrolls"), p.Scrolls.Text(ctx)),
+		h.Input(h.ID("q"), p.Query.Bind(), on.Input(p.Search, on.Debounce("200ms"))),
+		h.Button(h.ID("once"), h.Text("once"), on.Click(p.Once, on.Once())),
+		h.Button(h.ID("hit"), h.Text("hit"), on.Click(p.Hit)),
+		h.Div(h.ID("pane"), h.Style("height:50px;overflow:auto"),
+			on.Scroll(p.Scroll, on.Throttle("100ms")),
+			h.Div(h.Style("height:500px"))),
+		h.Div(h.Style("height:3000px")),
+		h.Div(h.ID("footer"), h.Style("height:100px"), h.Text("footer"),
+			on.Intersect(p.Show, on.Once(), on.Half())),
+	)
+}
+
+// The DOM-less harness only sees the attribute name, so a modifier
+// spelling Datastar doesn't parse renders fine and never fires. These
+// drive each modifier family in a real browser.
+func TestBrowser_debounceFiresOnceAfterTyping(t *testing.T) {
+	app := newApp()
+	via.Mount[modifierPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.Type("#q", "gophers")
+	s.WaitText("#searched", "gophers")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+func TestBrowser_onceFiresOnlyOnFirstClick(t *testing.T) {
+	app := newApp()
+	via.Mount[modifierPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.Click("#once")
+	s.WaitText("#onces", "1")
+	s.Click("#once")
+	// A bare action queued behind the second click: once it lands, a
+	// second Once fire would have landed too.
+	s.Click("#hit")
+	s.WaitText("#hits", "1")
+	s.AssertText("#onces", "1")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+func TestBrowser_throttledScrollFires(t *testing.T) {
+	app := newApp()
+	via.Mount[modifierPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#scrolls", "0")
+	var ok bool
+	s.Eval(`document.getElementById('pane').scrollTop=200,true`, &ok)
+	s.WaitText("#scrolls", "1")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+func TestBrowser_intersectOnceHalfFiresWhenScrolledIntoView(t *testing.T) {
+	app := newApp()
+	via.Mount[modifierPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#seen", "0")
+	var ok bool
+	s.Eval(`document.getElementById('footer').scrollIntoView(),true`, &ok)
+	s.WaitText("#seen", "1")
+	// Scrolling away and back must not fire again under Once.
+	s.Eval(`window.scrollTo(0,0),true`, &ok)
+	time.Sleep(200 * time.Millisecond)
+	s.Eval(`document.getElementById('footer').scrollIntoView(),true`, &ok)
+	s.Click("#hit")
+	s.WaitText("#hits", "1")
+	s.AssertText("#seen", "1")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+type hotkeyPage struct {
+	Note   via.SignalStr `via:"note"`
+	Opened via.StateTabNum[int]
+}
+
+func (p *hotkeyPage) Open(ctx *via.Ctx) { p.Opened.Op(ctx).Add(1) }
+
+func (p *hotkeyPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.ID("page"), h.Style("margin-top:6rem"),
+		h.Span(h.ID("opened"), p.Opened.Text(ctx)),
+		h.Input(h.ID("note"), p.Note.Bind()),
+		h.Div(on.Hotkey("/", p.Open)),
+	)
+}
+
+func TestBrowser_hotkeyFiresFromAnywhereButInputs(t *testing.T) {
+	app := newApp()
+	via.Mount[hotkeyPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#opened", "0")
+	// The listener sits on window, so a key pressed on an element the
+	// hotkey isn't attached to still fires it.
+	s.Press("/", "#page")
+	s.WaitText("#opened", "1")
+
+	s.Type("#note", "a/b")
+	var note string
+	s.Eval(`document.getElementById('note').value`, &note)
+	assert.Equal(t, "a/b", note, "a bare-key hotkey must not eat keystrokes typed into a field")
+	s.Press("/", "#page")
+	s.WaitText("#opened", "2")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+type lazyPage struct{}
+
+func (p *lazyPage) View(ctx *via.CtxR) h.H {
+	return h.Main(
+		h.Div(h.Style("height:3000px")),
+		h.Div(h.ID("widget"), ctx.Lazy("widget", h.Text("loading"), func() h.H {
+			return h.Text("loaded")
+		})),
+	)
+}
+
+func TestBrowser_lazyRegionRendersWhenScrolledIntoView(t *testing.T) {
+	app := newApp()
+	via.Mount[lazyPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#widget", "loading")
+	var ok bool
+	s.Eval(`document.getElementById('widget').scrollIntoView(),true`, &ok)
+	s.WaitText("#widget", "loaded")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+type sortPage struct {
+	Order via.StateTab[[]string]
+	Posts via.StateTabNum[int]
+}
+
+func (p *sortPage) OnInit(ctx *via.Ctx) error {
+	p.Order.Write(ctx, []string{"a", "b", "c"})
+	return nil
+}
+
+func (p *sortPage) Reorder(ctx *via.Ctx) {
+	p.Order.Write(ctx, ctx.EventArgs(on.SortOrderArg))
+	p.Posts.Op(ctx).Add(1)
+}
+
+func (p *sortPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.Style("margin-top:6rem"),
+		h.Span(h.ID("order"), h.Text(strings.Join(p.Order.Read(ctx), ","))),
+		h.Span(h.ID("posts"), p.Posts.Text(ctx)),
+		h.Ul(h.ID("list"), on.Sortable(p.Reorder),
+			h.Each(p.Order.Read(ctx), func(id string) h.H {
+				return h.Li(on.SortItem(id), h.Style("height:2rem"), h.Text("item-"+id))
+			})),
+	)
+}
+
+// drag replays the events a drag of item from onto the lower half of
+// item to fires. Headless Chrome doesn't start a native drag from
+// synthesized mouse input, so the DragEvents are dispatched directly.
+const drag = `(function(from,to){
+	var list=document.getElementById('list'),dt=new DataTransfer(),
+		a=list.querySelector('[data-via-sort-id="'+from+'"]'),
+		b=list.querySelector('[data-via-sort-id="'+to+'"]'),r=b.getBoundingClientRect();
+	a.dispatchEvent(new DragEvent('dragstart',{bubbles:true,dataTransfer:dt}));
+	b.dispatchEvent(new DragEvent('dragover',{bubbles:true,cancelable:true,dataTransfer:dt,clientX:r.left+1,clientY:r.bottom-1}));
+	a.dispatchEvent(new DragEvent('dragend',{bubbles:true,dataTransfer:dt}));
+	return true})`
+
+func TestBrowser_sortableDragPostsTheNewOrder(t *testing.T) {
+	app := newApp()
+	via.Mount[sortPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#order", "a,b,c")
+	var ok bool
+	s.Eval(drag+`('a','c')`, &ok)
+	s.WaitText("#order", "b,c,a")
+
+	s.WaitText("#posts", "1")
+
+	// Dropping an item back where it was changes nothing, so it posts
+	// nothing: the next real drag is only the second post.
+	s.Eval(drag+`('b','b')`, &ok)
+	s.Eval(drag+`('c','a')`, &ok)
+	s.WaitText("#order", "b,a,c")
+	s.AssertText("#posts", "2")
+	assert.Empty(t, s.ConsoleErrors())
+}
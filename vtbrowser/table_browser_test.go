@@ -0,0 +1,45 @@
+package vtbrowser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/components/table"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/vtbrowser"
+	"github.com/stretchr/testify/assert"
+)
+
+type gridPage struct {
+	Grid *table.Table[string]
+}
+
+func (p *gridPage) OnInit(ctx *via.Ctx) error {
+	p.Grid.Configure(table.Config[string]{
+		Columns: []table.Column[string]{{Title: "Fruit", Cell: func(s string) h.H { return h.Text(s) }}},
+		Key:     func(s string) string { return s },
+		All:     func() []string { return []string{"apple", "banana", "cherry"} },
+		Match:   func(s, f string) bool { return strings.Contains(s, f) },
+	})
+	return nil
+}
+
+func (p *gridPage) GridEvent(ctx *via.Ctx) error { return p.Grid.Handle(ctx) }
+
+func (p *gridPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.ID("grid"), p.Grid.View(ctx, p.GridEvent))
+}
+
+// The filter box posts through a debounced input trigger; a modifier
+// spelling Datastar can't parse leaves it silently dead.
+func TestBrowser_tableFilterNarrowsRows(t *testing.T) {
+	app := newApp()
+	via.Mount[gridPage](app, "/")
+	s := vtbrowser.Open(t, app)
+
+	s.WaitText("#grid tbody", "applebananacherry")
+	s.Type(`#grid input[type=search]`, "an")
+	s.WaitText("#grid tbody", "banana")
+	assert.Empty(t, s.ConsoleErrors())
+}
@@ -29,7 +29,9 @@ import (
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
 	"github.com/go-via/via"
+	"github.com/go-via/via/vt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,7 +50,8 @@ var browserNames = []string{
 
 // Session is a live headless-browser tab bound to an httptest server
 // running the app. All helpers fail the test on error; cleanup (browser
-// and server shutdown) is registered via t.Cleanup.
+// and server shutdown) is registered via t.Cleanup. It implements
+// vt.Page, so a flow written for vt.OpenPage runs here unchanged.
 type Session struct {
 	t   testing.TB
 	ctx context.Context
@@ -109,6 +112,8 @@ func Open(t testing.TB, app *via.App) *Session {
 	return s
 }
 
+var _ vt.Page = (*Session)(nil)
+
 // Server exposes the underlying httptest server so tests can simulate
 // infrastructure failures — e.g. CloseClientConnections to drop a live
 // SSE stream — that no DOM-level helper can express.
@@ -160,6 +165,67 @@ func (s *Session) WaitText(selector, want string) {
 	}
 }
 
+// Visit navigates the tab to path on the app's server.
+func (s *Session) Visit(path string) {
+	s.t.Helper()
+	s.run(fmt.Sprintf("navigate to %s", path),
+		chromedp.Navigate(s.srv.URL+path),
+		chromedp.WaitReady("body", chromedp.ByQuery),
+	)
+}
+
+// Fill sets the value of the first node matching the CSS selector — or,
+// for a checkbox or radio, checks it when value is "true" — and fires
+// input and change events, as a user editing the field does.
+func (s *Session) Fill(selector, value string) {
+	s.t.Helper()
+	s.run(fmt.Sprintf("fill %q with %q", selector, value),
+		chromedp.WaitReady(selector, chromedp.ByQuery),
+		chromedp.Evaluate(fmt.Sprintf(`(function(n,v){`+
+			`if(n.type==='checkbox'||n.type==='radio'){n.checked=v==='true'}else{n.value=v}`+
+			`n.dispatchEvent(new Event('input',{bubbles:true}));`+
+			`n.dispatchEvent(new Event('change',{bubbles:true}))`+
+			`})(document.querySelector(%q),%q)`, selector, value), nil),
+	)
+}
+
+// Press sends key as a real key event to the first node matching the CSS
+// selector. key is a W3C key name ("Enter", "Escape", "ArrowUp") or a
+// single character.
+func (s *Session) Press(key, selector string) {
+	s.t.Helper()
+	s.run(fmt.Sprintf("press %s on %q", key, selector),
+		chromedp.SendKeys(selector, keyText(key), chromedp.ByQuery))
+}
+
+// keyText maps a W3C key name to the text chromedp sends for it.
+func keyText(key string) string {
+	for r, k := range kb.Keys {
+		if k.Key == key && len([]rune(key)) > 1 {
+			return string(r)
+		}
+	}
+	return key
+}
+
+// Submit submits the form matching the CSS selector, or enclosing the
+// node that does, through requestSubmit — so submit listeners run, as
+// they do when a user presses a submit button.
+func (s *Session) Submit(selector string) {
+	s.t.Helper()
+	s.run(fmt.Sprintf("submit %q", selector),
+		chromedp.WaitReady(selector, chromedp.ByQuery),
+		chromedp.Evaluate(fmt.Sprintf(`(function(n){(n.closest('form')||n).requestSubmit()})(document.querySelector(%q))`,
+			selector), nil),
+	)
+}
+
+// AssertText is WaitText under vt.Page's name.
+func (s *Session) AssertText(selector, want string) {
+	s.t.Helper()
+	s.WaitText(selector, want)
+}
+
 // Eval runs a JavaScript expression in the page and unmarshals its
 // result into out — the escape hatch for assertions the named helpers
 // don't cover (focus, input values, attributes).
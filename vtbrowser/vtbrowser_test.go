@@ -7,6 +7,7 @@ import (
 	"github.com/go-via/via"
 	"github.com/go-via/via/h"
 	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
 	"github.com/go-via/via/vtbrowser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -156,3 +157,104 @@ func TestBrowser_reconnectBannerClearsOnResume(t *testing.T) {
 	s.WaitText("#hits", "1")
 	assert.Empty(t, s.ConsoleErrors())
 }
+
+type chatPage struct {
+	Msgs via.StateTab[[]string]
+}
+
+func (p *chatPage) OnInit(ctx *via.Ctx) error {
+	p.Msgs.Write(ctx, []string{"m1", "m2"})
+	return nil
+}
+
+func (p *chatPage) Post(ctx *via.Ctx) {
+	p.Msgs.Write(ctx, append(p.Msgs.Read(ctx), "m3"))
+}
+
+func (p *chatPage) Drop(ctx *via.Ctx) { p.Msgs.Write(ctx, p.Msgs.Read(ctx)[1:]) }
+
+func (p *chatPage) View(ctx *via.CtxR) h.H {
+	return h.Main(h.Style("margin-top:6rem"),
+		h.StyleEl(h.Raw(`@keyframes pop{from{opacity:0}to{opacity:1}}`+
+			`.in{animation:pop .4s} .out{animation:pop .4s reverse}`)),
+		h.Button(h.ID("post"), h.Text("post"), on.Click(p.Post)),
+		h.Button(h.ID("drop"), h.Text("drop"), on.Click(p.Drop)),
+		h.Ul(h.ID("msgs"), h.AnimateChildren("in", "out"),
+			h.Each(p.Msgs.Read(ctx), func(m string) h.H { return h.Li(h.ID(m), h.Text(m)) })),
+	)
+}
+
+// AnimateChildren's enter and leave classes run client-side off a
+// MutationObserver, so only a real browser shows them landing.
+func TestBrowser_animateChildrenPlaysEnterAndLeave(t *testing.T) {
+	app := newApp()
+	via.Mount[chatPage](app, "/")
+	s := vtbrowser.Open(t, app)
+	s.WaitText("#m2", "m2")
+
+	check := func(js string) func() bool {
+		return func() bool {
+			var v bool
+			s.Eval(js, &v)
+			return v
+		}
+	}
+	assert.False(t, check(`document.getElementById('m1').classList.contains('in')`)(),
+		"children present at load don't play enter")
+
+	s.Click("#post")
+	require.Eventually(t, check(`!!document.getElementById('m3')&&document.getElementById('m3').classList.contains('in')`),
+		5*time.Second, 20*time.Millisecond, "an inserted child plays enter")
+	require.Eventually(t, check(`!document.getElementById('m3').classList.contains('in')`),
+		5*time.Second, 20*time.Millisecond, "enter comes off when the animation ends")
+
+	s.Click("#drop")
+	require.Eventually(t, check(`!!document.querySelector('#msgs > [data-via-leaving].out')`),
+		5*time.Second, 20*time.Millisecond, "a removed child leaves through a copy")
+	require.Eventually(t, check(`!document.getElementById('m1')`),
+		5*time.Second, 20*time.Millisecond, "the copy goes when the animation ends")
+	assert.Empty(t, s.ConsoleErrors())
+}
+
+type finder struct {
+	Query  via.SignalStr `via:"q"`
+	Note   via.SignalStr `via:"note"`
+	Status via.StateTabStr
+}
+
+func (f *finder) Search(ctx *via.Ctx) { f.Status.Write(ctx, "searched "+f.Query.Read(ctx)) }
+
+func (f *finder) Save(ctx *via.Ctx) { f.Status.Write(ctx, "saved "+f.Note.Read(ctx)) }
+
+func (f *finder) View(ctx *via.CtxR) h.H {
+	return h.Main(
+		h.P(h.ID("status"), f.Status.Text(ctx)),
+		h.Input(h.ID("q"), f.Query.Bind(), on.Key("Enter", f.Search)),
+		h.Form(h.ID("notes"), on.Submit(f.Save, on.Prevent()),
+			h.Input(h.Name("note"), f.Note.Bind()),
+		),
+	)
+}
+
+// finderFlow is written once against vt.Page and run on both backends.
+func finderFlow(p vt.Page) {
+	p.Visit("/")
+	p.Fill("#q", "gophers")
+	p.Press("Enter", "#q")
+	p.AssertText("#status", "searched gophers")
+	p.Fill("form input[name=note]", "hello")
+	p.Submit("#notes")
+	p.AssertText("#status", "saved hello")
+}
+
+func TestPage_flowInProcess(t *testing.T) {
+	app := newApp()
+	via.Mount[finder](app, "/")
+	finderFlow(vt.OpenPage(t, app))
+}
+
+func TestBrowser_pageFlow(t *testing.T) {
+	app := newApp()
+	via.Mount[finder](app, "/")
+	finderFlow(vtbrowser.Open(t, app))
+}
@@ -18,6 +18,7 @@ const (
 	roleParam
 	roleQuery
 	roleFile
+	roleForm
 	roleChild
 )
 
@@ -77,6 +78,13 @@ func walkStruct(d *cmpDescriptor, typ reflect.Type, indexPath []int, pathPrefix
 				wireKey:   qualify(pathPrefix, parseLocalID(f)),
 				plural:    isFilesType(f.Type),
 			})
+		case roleForm:
+			// Parse T's tags now so a bad one fails Mount, not a render.
+			reflect.New(f.Type).Interface().(formBinder).formSpec()
+			d.formSlots = append(d.formSlots, formSlot{
+				fieldPath: fieldPath,
+				wireKey:   qualify(pathPrefix, parseLocalID(f)),
+			})
 		case roleChild:
 			child := f.Type
 			if child.Kind() != reflect.Pointer {
@@ -91,7 +99,13 @@ func walkStruct(d *cmpDescriptor, typ reflect.Type, indexPath []int, pathPrefix
 						"reassigned; declare the child as a pointer: `%s *%s`",
 					d.typ, typ.Name(), f.Name, f.Name, child.Name()))
 			}
-			walkStruct(d, child.Elem(), fieldPath, qualify(pathPrefix, f.Name))
+			prefix := qualify(pathPrefix, f.Name)
+			d.childSlots = append(d.childSlots, childSlot{
+				fieldPath:  fieldPath,
+				wirePrefix: prefix,
+				idPrefix:   strings.ReplaceAll(prefix, ".", "-"),
+			})
+			walkStruct(d, child.Elem(), fieldPath, prefix)
 		}
 	}
 }
@@ -121,6 +135,9 @@ func classifyField(f reflect.StructField) fieldRole {
 	if isFileType(f.Type) || isFilesType(f.Type) {
 		return roleFile
 	}
+	if implements(f.Type, formBinderType) {
+		return roleForm
+	}
 	if isChildComposition(f.Type) {
 		return roleChild
 	}
@@ -141,6 +158,8 @@ var (
 	stateAppMarkerType  = reflect.TypeOf((*stateAppMarker)(nil)).Elem()
 
 	stateAppEventsMarkerType = reflect.TypeOf((*stateAppEventsMarker)(nil)).Elem()
+
+	formBinderType = reflect.TypeOf((*formBinder)(nil)).Elem()
 )
 
 // implements reports whether *t (pointer-to-t) implements iface. Used
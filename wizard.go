@@ -0,0 +1,229 @@
+package via
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/go-via/via/h"
+)
+
+// Wizard walks a tab through a fixed sequence of steps — an onboarding
+// or checkout flow — keeping the current step, gating each advance on
+// the step's own validation, and rendering a progress list and back/next
+// buttons. Add it as a composition field, give it its steps in OnInit,
+// and forward two actions to it:
+//
+//	type Checkout struct {
+//	    Flow    via.Wizard
+//	    Address via.Form[Address]
+//	    Card    via.Form[Card]
+//	}
+//
+//	func (p *Checkout) OnInit(ctx *via.Ctx) error {
+//	    p.Flow.Steps(
+//	        via.WizardStep{Title: "Address", View: p.addressView,
+//	            Gate: func(ctx *via.Ctx) bool { _, ok := p.Address.Submit(ctx); return ok }},
+//	        via.WizardStep{Title: "Payment", View: p.cardView,
+//	            Gate: func(ctx *via.Ctx) bool { _, ok := p.Card.Submit(ctx); return ok }},
+//	        via.WizardStep{Title: "Confirm", View: p.summaryView},
+//	    )
+//	    p.Flow.OnFinish(p.placeOrder)
+//	    return nil
+//	}
+//
+//	func (p *Checkout) Next(ctx *via.Ctx) error { return p.Flow.Next(ctx) }
+//	func (p *Checkout) Back(ctx *via.Ctx) error { return p.Flow.Back(ctx) }
+//
+//	func (p *Checkout) View(ctx *via.CtxR) h.H {
+//	    return p.Flow.View(ctx, on.Click(p.Back), on.Click(p.Next))
+//	}
+//
+// The step lives in the composition, so it is per tab and a reload
+// starts over; moving it re-renders the view.
+type Wizard struct {
+	mu     sync.Mutex
+	steps  []WizardStep
+	finish func(*Ctx) error
+	step   int
+	done   bool
+}
+
+// WizardStep is one step of a [Wizard].
+type WizardStep struct {
+	// Title names the step in the progress list.
+	Title string
+	// View renders the step's body.
+	View func(ctx *CtxR) h.H
+	// Gate, if set, runs when the user moves on from this step and
+	// returns whether they may. It is the place to validate the step's
+	// inputs — e.g. Form.Submit or Validate — which also puts the
+	// messages on the page when it says no.
+	Gate func(ctx *Ctx) bool
+}
+
+// Steps sets the wizard's steps, in order, and starts it at the first.
+// Call it from OnInit. It panics without a step or on a step without a
+// View.
+func (w *Wizard) Steps(steps ...WizardStep) {
+	if len(steps) == 0 {
+		panic("via.Wizard.Steps: no steps")
+	}
+	for i, s := range steps {
+		if s.View == nil {
+			panic("via.Wizard.Steps: step " + strconv.Itoa(i) + " (" + strconv.Quote(s.Title) + ") has no View")
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.steps = steps
+	w.step, w.done = 0, false
+}
+
+// OnFinish sets fn to run when Next is taken from the last step, after
+// its gate passes. An error keeps the wizard on the last step and is
+// returned from Next.
+func (w *Wizard) OnFinish(fn func(ctx *Ctx) error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finish = fn
+}
+
+// Step is the zero-based current step.
+func (w *Wizard) Step(_ readCtx) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.step
+}
+
+// Done reports whether the wizard has been finished.
+func (w *Wizard) Done(_ readCtx) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.done
+}
+
+// Next runs the current step's gate and, if it passes, moves to the
+// following step — or, from the last, runs the OnFinish func and marks
+// the wizard done. A failing gate leaves the step as it is.
+func (w *Wizard) Next(ctx *Ctx) error {
+	w.mu.Lock()
+	if err := w.checkLocked(); err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	step := w.steps[w.step]
+	last := w.step == len(w.steps)-1
+	finish := w.finish
+	w.mu.Unlock()
+
+	// The gate and finish run unlocked: they read the wizard's own
+	// composition, and may render or move it themselves.
+	if step.Gate != nil && !step.Gate(ctx) {
+		return nil
+	}
+	if last && finish != nil {
+		if err := finish(ctx); err != nil {
+			return err
+		}
+	}
+	w.mu.Lock()
+	if last {
+		w.done = true
+	} else {
+		w.step++
+	}
+	w.mu.Unlock()
+	ctx.markStateDirty()
+	return nil
+}
+
+// Back moves to the previous step without running any gate. It does
+// nothing on the first step.
+func (w *Wizard) Back(ctx *Ctx) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkLocked(); err != nil {
+		return err
+	}
+	if w.step > 0 {
+		w.step--
+		ctx.markStateDirty()
+	}
+	return nil
+}
+
+// Reset returns to the first step, e.g. to start another order once the
+// wizard is done.
+func (w *Wizard) Reset(ctx *Ctx) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.step, w.done = 0, false
+	ctx.markStateDirty()
+}
+
+func (w *Wizard) checkLocked() error {
+	if len(w.steps) == 0 {
+		return errors.New("via.Wizard: Steps was never called")
+	}
+	if w.done {
+		return errors.New("via.Wizard: already finished")
+	}
+	return nil
+}
+
+// View renders the wizard: the progress list, the current step's body,
+// and Back and Next buttons — Next reads "Finish" on the last step and
+// Back is disabled on the first. back and next are the triggers of the
+// actions that forward to Back and Next, e.g. on.Click(p.Next). Once
+// done it renders only the progress list, every step complete; check
+// Done to show what comes after.
+func (w *Wizard) View(ctx *CtxR, back, next h.H) h.H {
+	w.mu.Lock()
+	steps, step, done := w.steps, w.step, w.done
+	w.mu.Unlock()
+	if len(steps) == 0 {
+		return nil
+	}
+	progress := w.Progress(ctx)
+	if done {
+		return h.Div(h.Class("via-wizard"), progress)
+	}
+	backBtn := h.Button(h.Type("button"), h.Text("Back"), h.Disabled())
+	if step > 0 {
+		backBtn = h.Button(h.Type("button"), h.Text("Back"), back)
+	}
+	label := "Next"
+	if step == len(steps)-1 {
+		label = "Finish"
+	}
+	return h.Div(h.Class("via-wizard"),
+		progress,
+		h.Section(h.Aria("label", steps[step].Title), steps[step].View(ctx)),
+		h.Div(h.Class("via-wizard-nav"), backBtn,
+			h.Button(h.Type("button"), h.Text(label), next)),
+	)
+}
+
+// Progress renders the ordered list of step titles on its own, for
+// layouts that place it apart from View. The current step carries
+// aria-current="step"; steps behind it are marked done.
+func (w *Wizard) Progress(_ *CtxR) h.H {
+	w.mu.Lock()
+	steps, step, done := w.steps, w.step, w.done
+	w.mu.Unlock()
+	items := make([]h.H, 0, len(steps)+2)
+	items = append(items, h.Class("via-wizard-steps"),
+		h.Aria("label", "Step "+strconv.Itoa(min(step+1, len(steps)))+" of "+strconv.Itoa(len(steps))))
+	for i, s := range steps {
+		switch {
+		case done || i < step:
+			items = append(items, h.Li(h.Class("done"), h.Text(s.Title)))
+		case i == step:
+			items = append(items, h.Li(h.Aria("current", "step"), h.Text(s.Title)))
+		default:
+			items = append(items, h.Li(h.Text(s.Title)))
+		}
+	}
+	return h.Ol(items...)
+}
@@ -0,0 +1,118 @@
+package via_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-via/via"
+	"github.com/go-via/via/h"
+	"github.com/go-via/via/on"
+	"github.com/go-via/via/vt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type onboardPage struct {
+	Flow   via.Wizard
+	Name   via.SignalStr
+	Placed via.StateTabStr
+}
+
+func (p *onboardPage) OnInit(ctx *via.Ctx) error {
+	p.Flow.Steps(
+		via.WizardStep{Title: "Profile",
+			View: func(ctx *via.CtxR) h.H { return h.Div(h.Input(p.Name.Bind()), p.Name.ErrorText()) },
+			Gate: func(ctx *via.Ctx) bool {
+				return len(via.Validate(ctx, p.Name.Required("Name is required"))) == 0
+			}},
+		via.WizardStep{Title: "Plan", View: func(ctx *via.CtxR) h.H { return h.P(h.Text("pick a plan")) }},
+		via.WizardStep{Title: "Confirm", View: func(ctx *via.CtxR) h.H { return h.P(h.Text("all set?")) }},
+	)
+	p.Flow.OnFinish(func(ctx *via.Ctx) error {
+		if p.Name.Read(ctx) == "fail" {
+			return errors.New("could not save")
+		}
+		p.Placed.Write(ctx, "welcome "+p.Name.Read(ctx))
+		return nil
+	})
+	return nil
+}
+
+func (p *onboardPage) Next(ctx *via.Ctx) error { return p.Flow.Next(ctx) }
+func (p *onboardPage) Back(ctx *via.Ctx) error { return p.Flow.Back(ctx) }
+
+func (p *onboardPage) View(ctx *via.CtxR) h.H {
+	return h.Div(p.Flow.View(ctx, on.Click(p.Back), on.Click(p.Next)), h.P(h.Text(p.Placed.Read(ctx))))
+}
+
+func newOnboardClient(t *testing.T) (*vt.Client, <-chan string) {
+	t.Helper()
+	app := via.New()
+	server := vt.Serve(t, app)
+	via.Mount[onboardPage](app, "/")
+	tc := vt.NewClient(t, server, "/")
+	frames, cancel := tc.SSEReady()
+	t.Cleanup(cancel)
+	return tc, frames
+}
+
+func TestWizard_rendersProgressAndTheFirstStep(t *testing.T) {
+	t.Parallel()
+	tc, _ := newOnboardClient(t)
+
+	html := tc.HTML()
+	assert.Contains(t, html, `<ol class="via-wizard-steps" aria-label="Step 1 of 3"><li aria-current="step">Profile</li><li>Plan</li>`)
+	assert.Contains(t, html, `data-bind="name"`)
+	assert.Contains(t, html, `<button type="button" disabled>Back</button>`)
+	assert.Contains(t, html, `>Next</button>`)
+}
+
+func TestWizard_gateHoldsTheStepUntilItPasses(t *testing.T) {
+	t.Parallel()
+	tc, frames := newOnboardClient(t)
+
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", " ").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `"_err_name":"Name is required"`)
+
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "Ada").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `<li class="done">Profile</li><li aria-current="step">Plan</li>`, "pick a plan")
+}
+
+func TestWizard_backThenFinish(t *testing.T) {
+	t.Parallel()
+	tc, frames := newOnboardClient(t)
+
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "Ada").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "pick a plan")
+	require.Equal(t, 200, tc.Action("Back").WithSignal("name", "Ada").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, `aria-current="step">Profile</li>`)
+
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "Ada").Fire())
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "Ada").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "all set?", ">Finish</button>")
+
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "Ada").Fire())
+	frame := vt.AwaitFrame(t, frames, 2*time.Second, "welcome Ada", `<li class="done">Confirm</li>`)
+	assert.NotContains(t, frame, "Finish", "a finished wizard drops its buttons")
+}
+
+func TestWizard_finishErrorStaysOnTheLastStep(t *testing.T) {
+	t.Parallel()
+	tc, frames := newOnboardClient(t)
+
+	for range 2 {
+		require.Equal(t, 200, tc.Action("Next").WithSignal("name", "fail").Fire())
+	}
+	vt.AwaitFrame(t, frames, 2*time.Second, "all set?")
+	require.Equal(t, 200, tc.Action("Next").WithSignal("name", "fail").Fire())
+	vt.AwaitFrame(t, frames, 2*time.Second, "could not save")
+}
+
+func TestWizard_stepsPanicsOnAStepWithoutAView(t *testing.T) {
+	t.Parallel()
+	var w via.Wizard
+	assert.PanicsWithValue(t, `via.Wizard.Steps: step 1 ("Plan") has no View`, func() {
+		w.Steps(via.WizardStep{Title: "Profile", View: func(*via.CtxR) h.H { return nil }}, via.WizardStep{Title: "Plan"})
+	})
+}